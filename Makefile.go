@@ -1,5 +1,10 @@
 # R2S Backend Makefile for Go Microservices
 
+# SDK version, bumped in sdk/VERSION; OPENAPI_GENERATOR may point at a local
+# openapi-generator-cli instead of the image
+SDK_VERSION ?= $(shell cat sdk/VERSION)
+OPENAPI_GENERATOR ?= docker run --rm -u $$(id -u):$$(id -g) -v $(CURDIR):/local -w /local openapitools/openapi-generator-cli:v7.8.0
+
 .PHONY: help
 help: ## Show this help message
 	@echo "R2S Backend - Go Microservices"
@@ -17,6 +22,7 @@ deps: ## Install all dependencies
 	cd core-server && go mod download
 	cd query-server && go mod download
 	cd batch-server && go mod download
+	cd realtime-server && go mod download
 	cd tx-helper && go mod download
 	cd event-receiver && go mod download
 	cd pkg && go mod download
@@ -29,6 +35,7 @@ build: ## Build all services
 	go build -o bin/core-server ./core-server
 	go build -o bin/query-server ./query-server
 	go build -o bin/batch-server ./batch-server
+	go build -o bin/realtime-server ./realtime-server
 	go build -o bin/tx-helper ./tx-helper
 	go build -o bin/event-receiver ./event-receiver
 
@@ -47,7 +54,7 @@ build-core: ## Build core server
 # Running services
 .PHONY: run-api
 run-api: ## Run API gateway
-	go run api-server/main_new.go api-server/gateway.go
+	go run ./api-server
 
 .PHONY: run-auth
 run-auth: ## Run auth server
@@ -63,7 +70,11 @@ run-query: ## Run query server
 
 .PHONY: run-batch
 run-batch: ## Run batch server
-	go run batch-server/main.go
+	go run ./batch-server
+
+.PHONY: run-realtime
+run-realtime: ## Run realtime WebSocket server
+	go run ./realtime-server
 
 .PHONY: run-tx
 run-tx: ## Run tx-helper
@@ -80,6 +91,7 @@ run-all: ## Run all services
 	@make run-core &
 	@make run-query &
 	@make run-batch &
+	@make run-realtime &
 	@make run-tx &
 	@make run-event &
 	@sleep 2
@@ -90,6 +102,7 @@ run-all: ## Run all services
 db-migrate: ## Run database migrations
 	@echo "Running database migrations..."
 	psql -U postgres -d r2s_dev -f pkg/db/init-postgres.sql
+	@for f in pkg/db/migrations/*.sql; do echo "Applying $$f"; psql -U postgres -d r2s_dev -f $$f; done
 
 .PHONY: db-seed
 db-seed: ## Seed database with test data
@@ -112,6 +125,7 @@ test: ## Run all tests
 	go test ./core-server/... -v
 	go test ./query-server/... -v
 	go test ./batch-server/... -v
+	go test ./realtime-server/... -v
 	go test ./tx-helper/... -v
 	go test ./event-receiver/... -v
 	go test ./pkg/... -v
@@ -170,6 +184,27 @@ proto: ## Generate protobuf files
 	@echo "Generating protobuf files..."
 	protoc --go_out=. --go-grpc_out=. pkg/proto/query/*.proto
 
+.PHONY: openapi
+openapi: ## Write the gateway OpenAPI spec to sdk/openapi.json
+	go run ./api-server -openapi sdk/openapi.json
+
+.PHONY: sdk
+sdk: openapi ## Generate the TypeScript and Go SDKs from the OpenAPI spec
+	@echo "Generating SDKs $(SDK_VERSION)..."
+	rm -rf sdk/typescript/src/generated
+	$(OPENAPI_GENERATOR) generate -i sdk/openapi.json -g typescript-fetch -o sdk/typescript/src/generated \
+		--additional-properties=supportsES6=true,modelPropertyNaming=original
+	find sdk/go/client -mindepth 1 ! -name .openapi-generator-ignore -delete
+	$(OPENAPI_GENERATOR) generate -i sdk/openapi.json -g go -o sdk/go/client \
+		--global-property=apiTests=false,modelTests=false \
+		--additional-properties=packageName=client,packageVersion=$(SDK_VERSION),withGoMod=false
+	cd sdk/typescript && npm version $(SDK_VERSION) --no-git-tag-version --allow-same-version
+
+.PHONY: sdk-tag
+sdk-tag: ## Tag the SDK release (sdk/go/vX.Y.Z for Go modules, sdk/vX.Y.Z for npm)
+	git tag sdk/go/v$(SDK_VERSION)
+	git tag sdk/v$(SDK_VERSION)
+
 .PHONY: clean
 clean: ## Clean build artifacts
 	@echo "Cleaning..."
@@ -181,9 +216,16 @@ clean: ## Clean build artifacts
 install: deps build ## Install dependencies and build
 
 # Development shortcuts
+.PHONY: jwt-key
+jwt-key: ## Create the P-256 key auth-server signs access tokens with (keys/jwt-signing.pem)
+	@mkdir -p keys
+	@test -f keys/jwt-signing.pem || openssl genpkey -algorithm EC -pkeyopt ec_paramgen_curve:P-256 -out keys/jwt-signing.pem
+	@chmod 600 keys/jwt-signing.pem
+
 .PHONY: dev
 dev: ## Start development environment
 	@echo "Starting development environment..."
+	@make jwt-key
 	@make db-migrate
 	@make run-all
 
@@ -196,15 +238,11 @@ dev-reset: ## Reset development environment
 
 # Monitoring
 .PHONY: health
-health: ## Check health of all services
+health: ## Check health of all services through the gateway (fails when a critical one is down)
 	@echo "Checking service health..."
 	@curl -s http://localhost:3001/health | jq '.' || echo "API Gateway: DOWN"
-	@curl -s http://localhost:3002/health | jq '.' || echo "Auth Server: DOWN"
-	@curl -s http://localhost:3003/health | jq '.' || echo "Core Server: DOWN"
-	@curl -s http://localhost:3004/health | jq '.' || echo "Query Server: DOWN"
-	@curl -s http://localhost:3005/health | jq '.' || echo "Batch Server: DOWN"
-	@curl -s http://localhost:3006/health | jq '.' || echo "TX Helper: DOWN"
-	@curl -s http://localhost:3007/health | jq '.' || echo "Event Receiver: DOWN"
+	@curl -s http://localhost:3009/live | jq '.' || echo "Realtime Server: DOWN"
+	@curl -sf -o /dev/null http://localhost:3001/health || { echo "Unhealthy: a critical service is failing"; exit 1; }
 
 .PHONY: logs
 logs: ## Tail logs from all services
@@ -219,6 +257,7 @@ prod-build: ## Build for production
 	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/core-server ./core-server
 	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/query-server ./query-server
 	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/batch-server ./batch-server
+	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/realtime-server ./realtime-server
 	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/tx-helper ./tx-helper
 	CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o bin/event-receiver ./event-receiver
 
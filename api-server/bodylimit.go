@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxRequestBody bounds request bodies; the JSON APIs take far less
+	maxRequestBody = 1 << 20
+	// maxUploadBody bounds a KYC application: four 10 MB documents plus
+	// form overhead, as auth-server allows
+	maxUploadBody = 4*10<<20 + 1<<20
+)
+
+// bodyLimits raises the limit of routes that take uploads
+var bodyLimits = map[string]int64{
+	"POST /api/auth/kyc/applications": maxUploadBody,
+}
+
+// BodyLimit rejects request bodies over the route's limit with 413 before
+// they are read, so oversized payloads never reach the services
+func BodyLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, ok := bodyLimits[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			limit = maxRequestBody
+		}
+		if c.Request.ContentLength > limit {
+			respondError(c, apperrors.PayloadTooLarge(limit))
+			c.Abort()
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
+
+// bodyError classifies a failure to read the request body
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperrors.PayloadTooLarge(tooLarge.Limit)
+	}
+	return apperrors.InvalidArgument("Failed to read request body")
+}
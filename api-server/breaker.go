@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// breakerThreshold consecutive failures open a service's circuit
+	breakerThreshold = 5
+	// breakerCooldown is how long an open circuit rejects requests before
+	// letting a probe through
+	breakerCooldown = 30 * time.Second
+
+	// retryBaseDelay and retryMaxDelay bound the jittered backoff between
+	// attempts of an idempotent request
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops calls to a service that keeps failing. After
+// breakerThreshold consecutive failures it opens and rejects calls for
+// breakerCooldown, then lets a single probe through: success closes it,
+// failure opens it again.
+type circuitBreaker struct {
+	name string
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(name string) *circuitBreaker {
+	return &circuitBreaker{name: name}
+}
+
+// allow reports whether a call may go out, or how long until the circuit
+// lets a probe through
+func (b *circuitBreaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if wait := breakerCooldown - time.Since(b.openedAt); wait > 0 {
+			return false, wait
+		}
+		b.state = breakerHalfOpen
+		slog.Info("Circuit half-open, probing service", "service", b.name)
+		return true, 0
+	case breakerHalfOpen:
+		// a probe is already out
+		return false, time.Second
+	}
+	return true, 0
+}
+
+// record reports the outcome of a call allow let through
+func (b *circuitBreaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ok {
+		if b.state != breakerClosed {
+			slog.Info("Circuit closed, service recovered", "service", b.name)
+		}
+		b.state, b.failures = breakerClosed, 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= breakerThreshold {
+		if b.state != breakerOpen {
+			slog.Warn("Circuit opened, rejecting calls to service", "service", b.name, "failures", b.failures, "cooldown", breakerCooldown)
+		}
+		b.state, b.openedAt = breakerOpen, time.Now()
+	}
+}
+
+// release gives back a call allow let through that ended without an outcome,
+// such as one the client cancelled: a half-open circuit goes back to open,
+// with its cooldown already over, so the next call probes again
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// idempotent reports whether a request may be sent again after a failure
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// serviceFailed reports whether a response means the service itself is
+// failing, as opposed to rejecting the request
+func serviceFailed(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// retryDelay returns the full-jitter backoff before the given retry
+func retryDelay(retry int) time.Duration {
+	delay := retryBaseDelay << (retry - 1)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// sleep waits for d, or returns false when ctx ends first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/cors"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/server"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+	"github.com/Reserve-to-save-backend/pkg/tracing"
+)
+
+// 액세스 토큰 검증 방식
+const (
+	// auth-server의 /auth/validate를 매 요청마다 호출 (세션 폐기 즉시 반영)
+	AuthValidationRemote = "remote"
+	// AUTH_JWKS_URL의 공개키와 Redis 블랙리스트로 게이트웨이에서 직접 검증
+	// (로그아웃은 즉시 반영, 그 밖의 세션 폐기는 토큰 만료 시 반영)
+	AuthValidationLocal = "local"
+)
+
+// Config는 api-server 설정입니다 (환경변수 > CONFIG_FILE > 기본값)
+type Config struct {
+	GatewayPort string `env:"API_SERVER_PORT" default:"3001"`
+
+	// pkg/diag 리스너 주소 (비어 있으면 비활성화, 외부에 노출하지 말 것)
+	GatewayDebugAddr string `env:"API_GATEWAY_DEBUG_ADDR"`
+
+	// 게이트웨이가 프록시할 서비스 (JSON 객체, 서비스별 url/timeout/retries/healthPath/optional)
+	// query는 gRPC로 호출하므로 url이 grpc://host:port이고 timeout이 호출 기한, retries가 UNAVAILABLE 재시도 횟수
+	// 기본값(localhost) 위에 GATEWAY_SERVICES, GATEWAY_SERVICES_FILE 순으로 덮어쓰며 생략한 값은 유지
+	// 파일은 SIGHUP이나 GATEWAY_SERVICES_RELOAD 주기로 변경을 확인해 다시 읽음 (0이면 SIGHUP만)
+	Services       string        `env:"GATEWAY_SERVICES"`
+	ServicesFile   string        `env:"GATEWAY_SERVICES_FILE"`
+	ServicesReload time.Duration `env:"GATEWAY_SERVICES_RELOAD" default:"10s"`
+	// query-server gRPC 연결 수 (연결마다 동시 스트림 수가 제한되므로 라운드 로빈으로 분산)
+	QueryConns int `env:"QUERY_GRPC_CONNS" default:"4"`
+
+	// POST /api/graphql로 query-server 조회 API를 GraphQL(graph/schema.graphqls)로 제공할지 여부
+	// 복잡도(필드마다 1, 목록은 first배)가 GRAPHQL_COMPLEXITY_LIMIT를 넘는 쿼리는 실행하지 않음
+	GraphQLEnabled         bool `env:"GRAPHQL_ENABLED" default:"false"`
+	GraphQLComplexityLimit int  `env:"GRAPHQL_COMPLEXITY_LIMIT" default:"1000"`
+
+	// 게이트웨이 요청을 OpenAPI 스펙(openapi.go)으로 검증할지 여부 (맞지 않으면 필드 목록과 함께 422)
+	OpenAPIValidate bool `env:"OPENAPI_VALIDATE" default:"true"`
+
+	// 액세스 토큰 검증 방식 (remote 또는 local)
+	AuthValidation string `env:"AUTH_VALIDATION" default:"local"`
+	// local 검증에서 공개키나 블랙리스트를 확인할 수 없으면 auth-server로 검증할지 여부
+	AuthValidationFallback bool `env:"AUTH_VALIDATION_FALLBACK" default:"true"`
+	JWKS                   jwks.Config
+
+	// 게이트웨이 요청 제한 (Redis 토큰 버킷, "<요청 수>/<기간>", 비우면 해당 제한 해제)
+	// IP는 모든 /api 요청, Auth는 /api/auth 요청에 IP별로, User와 Admin은 사용자별로 적용
+	RateLimitEnabled bool   `env:"RATE_LIMIT_ENABLED" default:"true"`
+	RateLimitIP      string `env:"RATE_LIMIT_IP" default:"300/1m"`
+	RateLimitAuth    string `env:"RATE_LIMIT_AUTH" default:"30/1m"`
+	RateLimitUser    string `env:"RATE_LIMIT_USER" default:"120/1m"`
+	RateLimitAdmin   string `env:"RATE_LIMIT_ADMIN" default:"60/1m"`
+	// 사용자별 업무 할당량 (Redis 고정 구간, "<요청 수>/<기간>", 비우면 해당 할당량 해제)
+	// 판매자의 캠페인 생성과 참여/취소 트랜잭션 생성에 적용되며, 관리자 API(/api/admin/quotas)로 사용자별 한도 변경 가능
+	QuotasEnabled       bool   `env:"QUOTAS_ENABLED" default:"true"`
+	QuotaCampaignCreate string `env:"QUOTA_CAMPAIGN_CREATE" default:"20/24h"`
+	QuotaTxBuild        string `env:"QUOTA_TX_BUILD" default:"10/1m"`
+	// Idempotency-Key 헤더로 재시도된 생성 요청(/api/tx/*, /api/payment/create, /api/campaigns)에
+	// 첫 응답을 Redis에서 IDEMPOTENCY_TTL 동안 재전송할지 여부
+	IdempotencyEnabled bool          `env:"IDEMPOTENCY_ENABLED" default:"true"`
+	IdempotencyTTL     time.Duration `env:"IDEMPOTENCY_TTL" default:"24h"`
+	// GET /api/campaigns, /api/campaigns/:id 응답을 Redis에 캐시할지 여부 (ETag/If-None-Match 지원)
+	// 게이트웨이의 캠페인 변경 요청과 realtime-server가 전달하는 DB 변경 이벤트로 무효화되며,
+	// TTL은 무효화를 놓쳤을 때 응답이 오래될 수 있는 최대 시간
+	ResponseCacheEnabled bool          `env:"RESPONSE_CACHE_ENABLED" default:"true"`
+	ResponseCacheListTTL time.Duration `env:"RESPONSE_CACHE_LIST_TTL" default:"5s"`
+	ResponseCacheItemTTL time.Duration `env:"RESPONSE_CACHE_ITEM_TTL" default:"30s"`
+	// 점검 모드와 기능 동결 플래그(maintenance, freeze_*)를 Redis에서 읽을지 여부
+	// 관리자 API(/api/admin/features)로 바꾼 값은 FEATURE_FLAGS_REFRESH 안에 모든 게이트웨이에 반영되며,
+	// 끄면 FEATURE_* 환경변수만 사용
+	FeatureFlagsEnabled bool          `env:"FEATURE_FLAGS_ENABLED" default:"true"`
+	FeatureFlagsRefresh time.Duration `env:"FEATURE_FLAGS_REFRESH" default:"5s"`
+	// X-Forwarded-For를 신뢰할 프록시 (IP 또는 CIDR). 비우면 직접 연결한 주소를 클라이언트 IP로 사용
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+	// 브라우저 교차 출처 호출 정책 (CORS_ALLOWED_ORIGINS에 없는 출처에는 CORS 헤더를 보내지 않음)
+	CORS cors.Config
+
+	// 내부 서비스 호출용 클라이언트 자격 증명 (HTTP 프록시와 query-server gRPC 호출)
+	Internal svcauth.Config
+
+	Log     logger.Config
+	Errors  errreport.Config
+	Tracing tracing.Config
+	Server  server.Config
+}
+
+// defaultConfig는 공통 기본값과 다른 api-server 전용 기본값을 채운 Config를 반환합니다
+func defaultConfig() Config {
+	return Config{
+		CORS: cors.Config{
+			// 프론트엔드가 보내는 기기 지문, 멱등성 키, 캐시 재검증과 버전 확인(If-Match) 헤더
+			AllowedHeaders: []string{"Content-Type", "Authorization", "X-Device-Fingerprint", IdempotencyKeyHeader, "If-None-Match", "If-Match"},
+			// 프론트엔드가 읽는 캐시, 재시도, 요청 추적 헤더
+			ExposedHeaders: []string{"ETag", "Retry-After", cacheStatusHeader, idempotentReplayedHeader, logger.RequestIDHeader},
+		},
+	}
+}
+
+// Validate는 AUTH_VALIDATION 값, QUERY_GRPC_CONNS, GRAPHQL_COMPLEXITY_LIMIT, IDEMPOTENCY_TTL, FEATURE_FLAGS_REFRESH와 요청 제한, 할당량 형식을 확인합니다
+func (c *Config) Validate() error {
+	if c.AuthValidation != AuthValidationRemote && c.AuthValidation != AuthValidationLocal {
+		return fmt.Errorf("AUTH_VALIDATION must be %s or %s", AuthValidationRemote, AuthValidationLocal)
+	}
+	if c.QueryConns <= 0 {
+		return fmt.Errorf("QUERY_GRPC_CONNS must be positive")
+	}
+	if c.GraphQLEnabled && c.GraphQLComplexityLimit <= 0 {
+		return fmt.Errorf("GRAPHQL_COMPLEXITY_LIMIT must be positive")
+	}
+	if c.IdempotencyEnabled && c.IdempotencyTTL <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL must be positive")
+	}
+	if c.FeatureFlagsEnabled && c.FeatureFlagsRefresh <= 0 {
+		return fmt.Errorf("FEATURE_FLAGS_REFRESH must be positive")
+	}
+	if _, err := c.RateLimits(); err != nil {
+		return err
+	}
+	_, err := c.Quotas()
+	return err
+}
+
+// RateLimits는 RATE_LIMIT_* 값을 읽습니다
+func (c *Config) RateLimits() (RateLimits, error) {
+	var limits RateLimits
+	var err error
+	for _, l := range []struct {
+		env   string
+		value string
+		dst   **RateLimit
+	}{
+		{"RATE_LIMIT_IP", c.RateLimitIP, &limits.IP},
+		{"RATE_LIMIT_AUTH", c.RateLimitAuth, &limits.Auth},
+		{"RATE_LIMIT_USER", c.RateLimitUser, &limits.User},
+		{"RATE_LIMIT_ADMIN", c.RateLimitAdmin, &limits.Admin},
+	} {
+		if *l.dst, err = parseRateLimit(l.env, l.value); err != nil {
+			return RateLimits{}, err
+		}
+	}
+	return limits, nil
+}
+
+// Quotas는 QUOTA_* 값을 읽습니다 (할당량 이름별, 비운 할당량은 제외)
+func (c *Config) Quotas() (map[string]*RateLimit, error) {
+	quotas := make(map[string]*RateLimit)
+	for _, q := range []struct {
+		env   string
+		value string
+		name  string
+	}{
+		{"QUOTA_CAMPAIGN_CREATE", c.QuotaCampaignCreate, QuotaCampaignCreate},
+		{"QUOTA_TX_BUILD", c.QuotaTxBuild, QuotaTxBuild},
+	} {
+		limit, err := parseRateLimit(q.env, q.value)
+		if err != nil {
+			return nil, err
+		}
+		if limit != nil {
+			quotas[q.name] = limit
+		}
+	}
+	return quotas, nil
+}
+
+// RateLimit은 요청 제한에서는 Period마다 Requests개가 채워지는 토큰 버킷 (최대 Requests개),
+// 할당량에서는 Period 구간마다 Requests개입니다
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimits는 라우트 그룹별 요청 제한입니다 (nil이면 제한 없음)
+type RateLimits struct {
+	// 모든 /api 요청, 클라이언트 IP별
+	IP *RateLimit
+	// /api/auth 요청 (IP 제한에 추가), 클라이언트 IP별
+	Auth *RateLimit
+	// 인증된 요청, 사용자별
+	User  *RateLimit
+	Admin *RateLimit
+}
+
+// parseRateLimit은 "<요청 수>/<기간>" (예: 120/1m)을 읽습니다. 빈 값은 nil입니다
+func parseRateLimit(name, value string) (*RateLimit, error) {
+	if value == "" {
+		return nil, nil
+	}
+	requests, period, ok := strings.Cut(value, "/")
+	n, err1 := strconv.Atoi(requests)
+	d, err2 := time.ParseDuration(period)
+	if !ok || err1 != nil || err2 != nil || n < 1 || d < time.Second {
+		return nil, fmt.Errorf("%s must look like 120/1m", name)
+	}
+	return &RateLimit{Requests: n, Period: d}, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+// maxReadyBody caps how much of an upstream readiness report is read
+const maxReadyBody = 1 << 20
+
+// ServiceHealth is one upstream service in the deep health report
+type ServiceHealth struct {
+	health.Result
+	// Checks are the service's own dependency checks (postgres, redis,
+	// rpc, ...) from its readiness report
+	Checks map[string]health.Result `json:"checks,omitempty"`
+}
+
+// DeepReport is the gateway's aggregated health: every registered service's
+// readiness with its dependencies, plus the gateway's own dependencies
+type DeepReport struct {
+	Status       string                   `json:"status"`
+	Service      string                   `json:"service"`
+	Services     map[string]ServiceHealth `json:"services"`
+	Dependencies map[string]health.Result `json:"dependencies,omitempty"`
+	CheckedAt    time.Time                `json:"checkedAt"`
+}
+
+// healthCache caches the last deep report like health.Checker does, so
+// load balancer probes do not fan out to every service each time
+type healthCache struct {
+	// deps are the gateway's own dependencies; the gateway works without
+	// them, so they only degrade the report
+	deps *health.Checker
+
+	mu   sync.Mutex
+	last *DeepReport
+}
+
+// AddDependency reports one of the gateway's own dependencies in GET /health
+func (g *Gateway) AddDependency(name string, fn health.CheckFunc) {
+	g.health.deps.AddOptional(name, fn)
+}
+
+// HealthHandler answers GET /health with the deep report: 200 while every
+// critical service is ready (status ok or degraded), 503 otherwise
+func (g *Gateway) HealthHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := g.deepHealth(c.Request.Context())
+		status := http.StatusOK
+		if report.Status == health.StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	}
+}
+
+// deepHealth probes every registered service's readiness concurrently and
+// returns the cached report while it is fresh
+func (g *Gateway) deepHealth(ctx context.Context) DeepReport {
+	h := &g.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.last != nil && time.Since(h.last.CheckedAt) < health.DefaultTTL {
+		return *h.last
+	}
+	// the report is shared, so a client hanging up must not fail it
+	ctx = context.WithoutCancel(ctx)
+
+	var keys []string
+	var services []*ServiceConfig
+	for _, key := range g.services.Keys() {
+		if svc, _ := g.services.Get(key); svc.ReadyURL != "" || svc.GRPCTarget != "" {
+			keys, services = append(keys, key), append(services, svc)
+		}
+	}
+	results := make([]ServiceHealth, len(services))
+	var wg sync.WaitGroup
+	for i, svc := range services {
+		wg.Add(1)
+		go func(i int, svc *ServiceConfig) {
+			defer wg.Done()
+			results[i] = g.probe(ctx, keys[i], svc)
+		}(i, svc)
+	}
+	deps := h.deps.Run(ctx)
+	wg.Wait()
+
+	report := DeepReport{
+		Status:       health.StatusOK,
+		Service:      "api-gateway",
+		Services:     make(map[string]ServiceHealth, len(services)),
+		Dependencies: deps.Checks,
+		CheckedAt:    time.Now(),
+	}
+	for i, svc := range services {
+		r := results[i]
+		report.Services[svc.Name] = r
+		switch {
+		case r.Status == health.StatusOK:
+		case r.Status == health.StatusFail && r.Critical:
+			report.Status = health.StatusFail
+		case report.Status == health.StatusOK:
+			report.Status = health.StatusDegraded
+		}
+	}
+	if deps.Status != health.StatusOK && report.Status == health.StatusOK {
+		report.Status = health.StatusDegraded
+	}
+	h.last = &report
+	return report
+}
+
+// probe fetches a service's readiness report. Services that answer without
+// one are judged by the HTTP status alone, and gRPC services by their gRPC
+// health.
+func (g *Gateway) probe(ctx context.Context, key string, svc *ServiceConfig) (res ServiceHealth) {
+	ctx, cancel := context.WithTimeout(ctx, health.DefaultTimeout)
+	defer cancel()
+
+	start := time.Now()
+	res.Status, res.Critical = health.StatusOK, !svc.Optional
+	defer func() { res.LatencyMs = time.Since(start).Milliseconds() }()
+
+	if svc.GRPCTarget != "" {
+		if err := g.grpc[key].Check(ctx); err != nil {
+			res.Status, res.Error = health.StatusFail, err.Error()
+		}
+		return res
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, svc.ReadyURL, nil)
+	if err != nil {
+		res.Status, res.Error = health.StatusFail, err.Error()
+		return res
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		res.Status, res.Error = health.StatusFail, err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+
+	var report health.Report
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxReadyBody)).Decode(&report); err == nil && report.Status != "" {
+		res.Status, res.Checks = report.Status, report.Checks
+	} else if resp.StatusCode >= http.StatusMultipleChoices {
+		res.Status = health.StatusFail
+	}
+	if res.Status == health.StatusFail {
+		res.Error = fmt.Sprintf("readiness returned %d", resp.StatusCode)
+	}
+	return res
+}
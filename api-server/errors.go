@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/i18n"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// respondError는 에러 코드에 맞는 HTTP 상태로 응답합니다 (5xx는 원인을 로그로 남기고 에러 리포팅용으로 컨텍스트에 첨부)
+// 메시지는 협상된 언어(Accept-Language)로 번역되며, 요청 제한과 서킷 차단 에러에는 Retry-After를 붙입니다
+func respondError(c *gin.Context, err error) {
+	status, body := apperrors.Response(err)
+	if status >= http.StatusInternalServerError {
+		ginlog.From(c).Error("request failed", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+		_ = c.Error(err)
+	}
+	if retryAfter, ok := ratelimit.RetryAfter(err); ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	var open *circuitOpenError
+	if errors.As(err, &open) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(open.wait.Seconds()))))
+	}
+	body["error"] = i18n.Localize(i18n.FromContext(c.Request.Context()), apperrors.CodeOf(err), apperrors.MessageOf(err))
+	c.JSON(status, body)
+}
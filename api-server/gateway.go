@@ -2,76 +2,187 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/featureflags"
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/Reserve-to-save-backend/pkg/i18n"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/rbac"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+	"github.com/Reserve-to-save-backend/pkg/tracing"
 	"github.com/gin-gonic/gin"
 )
 
-// ServiceConfig holds the configuration for a microservice
+// ServiceConfig holds the configuration for a microservice, loaded by
+// Registry
 type ServiceConfig struct {
 	Name    string
 	BaseURL string
-	Timeout time.Duration
+	// GRPCTarget is the host:port of a service called over gRPC (a grpc://
+	// URL) through GRPCPool rather than proxied
+	GRPCTarget string
+	Timeout    time.Duration
+	// HealthURL is probed by /ready; empty skips the service
+	HealthURL string
+	// ReadyURL is the service's readiness report, aggregated by /health;
+	// empty skips the service
+	ReadyURL string
+	// Optional services only degrade /ready when unreachable
+	Optional bool
+	// Retries is how many times a failed GET is sent again
+	Retries int
+
+	breaker *circuitBreaker
 }
 
 // Gateway handles routing requests to microservices
 type Gateway struct {
-	services map[string]*ServiceConfig
+	services *Registry
 	client   *http.Client
+	// transport traces proxied requests and propagates the trace upstream
+	transport http.RoundTripper
+	// local validates access tokens in-process; nil calls auth-server
+	local *LocalAuth
+	// internal authenticates the gateway to the services it proxies to;
+	// nil sends no internal token
+	internal *svcauth.Client
+	// limiter rate limits the route groups; nil turns rate limiting off
+	limiter *RateLimiter
+	// idempotency replays responses to retried Idempotency-Key requests;
+	// nil processes every request
+	idempotency *Idempotency
+	// responses caches read-heavy routes; nil proxies every request
+	responses *ResponseCache
+	// flags holds the maintenance and freeze switches; nil never switches
+	// routes off
+	flags *featureflags.Flags
+	// quotas caps business calls per user; nil enforces none
+	quotas *Quotas
+	// grpc holds the pools of the services called over gRPC, by key
+	grpc map[string]*GRPCPool
+	// query serves the query-server routes over grpc["query"]
+	query *QueryAPI
+	// graphql serves the query API as GraphQL; nil turns /api/graphql off
+	graphql http.Handler
+	health    healthCache
+	// streams is cancelled by CloseStreams to end WebSocket and SSE streams
+	streams      context.Context
+	closeStreams context.CancelFunc
+}
+
+// LocalAuth validates access tokens in the gateway instead of calling
+// auth-server for every request
+type LocalAuth struct {
+	Verifier *jwks.Verifier
+	// Blacklist holds the tokens auth-server revoked on logout
+	Blacklist *database.RedisClient
+	// Fallback asks auth-server when the signing keys or the blacklist
+	// cannot be reached, instead of failing the request
+	Fallback bool
 }
 
-// NewGateway creates a new API gateway
-func NewGateway() *Gateway {
+// errLocalAuthUnavailable means a token could not be checked locally
+var errLocalAuthUnavailable = apperrors.New(apperrors.CodeUnavailable, "Token validation is temporarily unavailable")
+
+// validate returns the claims of a valid, unrevoked access token
+func (l *LocalAuth) validate(ctx context.Context, token string) (map[string]interface{}, error) {
+	claims, err := l.Verifier.Verify(ctx, token)
+	if errors.Is(err, jwks.ErrKeysUnavailable) {
+		return nil, fmt.Errorf("%w: %v", errLocalAuthUnavailable, err)
+	}
+	if err != nil {
+		return nil, apperrors.Catalog(apperrors.ReasonInvalidToken)
+	}
+
+	revoked, err := l.Blacklist.Exists(ctx, jwks.BlacklistKey(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to check token blacklist: %v", errLocalAuthUnavailable, err)
+	}
+	if revoked {
+		return nil, apperrors.Catalog(apperrors.ReasonTokenRevoked)
+	}
+	return claims, nil
+}
+
+// NewGateway creates a new API gateway proxying to services and calling
+// query-server over query. With local auth, access tokens are validated
+// in-process instead of by auth-server. internal authenticates proxied
+// calls to the services.
+func NewGateway(services *Registry, local *LocalAuth, internal *svcauth.Client, limiter *RateLimiter, idempotency *Idempotency, responses *ResponseCache, flags *featureflags.Flags, quotas *Quotas, query *GRPCPool, graphql http.Handler) *Gateway {
+	transport := tracing.NewTransport(http.DefaultTransport)
+	streams, closeStreams := context.WithCancel(context.Background())
 	return &Gateway{
-		services: map[string]*ServiceConfig{
-			"auth": {
-				Name:    "auth-server",
-				BaseURL: "http://localhost:3002",
-				Timeout: 10 * time.Second,
-			},
-			"core": {
-				Name:    "core-server",
-				BaseURL: "http://localhost:3003",
-				Timeout: 30 * time.Second,
-			},
-			"query": {
-				Name:    "query-server",
-				BaseURL: "http://localhost:3004",
-				Timeout: 10 * time.Second,
-			},
-			"batch": {
-				Name:    "batch-server",
-				BaseURL: "http://localhost:3005",
-				Timeout: 60 * time.Second,
-			},
-			"tx-helper": {
-				Name:    "tx-helper",
-				BaseURL: "http://localhost:3006",
-				Timeout: 20 * time.Second,
-			},
-		},
+		local:    local,
+		internal: internal,
+		limiter:  limiter,
+		idempotency: idempotency,
+		responses:   responses,
+		flags:       flags,
+		quotas:      quotas,
+		grpc:        map[string]*GRPCPool{"query": query},
+		query:       NewQueryAPI(query),
+		graphql:     graphql,
+		services:  services,
+		transport: transport,
+		health:    healthCache{deps: health.NewChecker("api-gateway")},
+		streams:      streams,
+		closeStreams: closeStreams,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
 	}
 }
 
-// ProxyRequest forwards a request to the appropriate microservice
+// proxy returns a handler that forwards to path on service
+func (g *Gateway) proxy(service, path string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		g.ProxyRequest(c, service, path)
+	}
+}
+
+// ProxyRequest forwards a request to the appropriate microservice.
+// WebSocket upgrades and SSE streams are passed through by StreamRequest.
 func (g *Gateway) ProxyRequest(c *gin.Context, service string, path string) {
-	config, exists := g.services[service]
+	if streaming(c.Request) {
+		g.StreamRequest(c, service, path)
+		return
+	}
+
+	config, exists := g.services.Get(service)
 	if !exists {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Service '%s' not found", service),
-		})
+		respondError(c, apperrors.Internal(fmt.Errorf("service %q is not configured", service)))
 		return
 	}
 
+	// Reject malformed limit/offset/cursor here so every upstream list
+	// endpoint sees the same bounds
+	if c.Request.Method == http.MethodGet {
+		if _, err := pagination.Parse(c.Query); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
 	// Build target URL
 	targetURL := config.BaseURL + path
 	if c.Request.URL.RawQuery != "" {
@@ -81,16 +192,17 @@ func (g *Gateway) ProxyRequest(c *gin.Context, service string, path string) {
 	// Read request body
 	var bodyBytes []byte
 	if c.Request.Body != nil {
-		bodyBytes, _ = io.ReadAll(c.Request.Body)
+		var err error
+		if bodyBytes, err = io.ReadAll(c.Request.Body); err != nil {
+			respondError(c, bodyError(err))
+			return
+		}
 	}
 
 	// Create new request
-	req, err := http.NewRequest(c.Request.Method, targetURL, bytes.NewReader(bodyBytes))
+	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, targetURL, bytes.NewReader(bodyBytes))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to create request",
-		})
+		respondError(c, apperrors.Internal(fmt.Errorf("failed to create request: %w", err)))
 		return
 	}
 
@@ -100,19 +212,67 @@ func (g *Gateway) ProxyRequest(c *gin.Context, service string, path string) {
 			req.Header.Add(key, value)
 		}
 	}
+	setRequestID(c, req)
+	setActor(c, req)
+
+	// Only the gateway's own internal token may reach the services
+	req.Header.Del(svcauth.Header)
+	if g.internal != nil && service != "auth" {
+		if err := g.internal.SetHeader(c.Request.Context(), req); err != nil {
+			respondError(c, apperrors.Unavailable(err, "Failed to authenticate to "+service+" service"))
+			return
+		}
+	}
 
 	// Set timeout for this specific request
 	client := &http.Client{
-		Timeout: config.Timeout,
+		Timeout:   config.Timeout,
+		Transport: g.transport,
+	}
+
+	// Make request, retrying idempotent ones on connection errors and
+	// 502/503/504, and failing fast while the service's circuit is open
+	attempts := 1
+	if idempotent(req.Method) {
+		attempts += config.Retries
 	}
+	var resp *http.Response
+	for attempt := 1; ; attempt++ {
+		if ok, wait := config.breaker.allow(); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			respondError(c, apperrors.Catalog(apperrors.ReasonServiceUnavailable, service))
+			return
+		}
 
-	// Make request
-	resp, err := client.Do(req)
+		resp, err = client.Do(req)
+		// a client that went away says nothing about the service's health
+		if errors.Is(err, context.Canceled) {
+			config.breaker.release()
+			respondError(c, upstreamError(err, service))
+			return
+		}
+		failed := err != nil || serviceFailed(resp.StatusCode)
+		config.breaker.record(!failed)
+		// timeouts are not retried; the attempts would add up past the client's patience
+		var netErr net.Error
+		if !failed || attempt >= attempts || (errors.As(err, &netErr) && netErr.Timeout()) {
+			break
+		}
+
+		reason := err
+		if err == nil {
+			resp.Body.Close()
+			reason = fmt.Errorf("%s returned %d", config.Name, resp.StatusCode)
+		}
+		ginlog.From(c).Warn("Retrying upstream request", "service", service, "attempt", attempt, "error", reason)
+		if !sleep(c.Request.Context(), retryDelay(attempt)) {
+			respondError(c, upstreamError(c.Request.Context().Err(), service))
+			return
+		}
+		req.Body, _ = req.GetBody()
+	}
 	if err != nil {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"success": false,
-			"error":   fmt.Sprintf("Failed to reach %s service", service),
-		})
+		respondError(c, upstreamError(err, service))
 		return
 	}
 	defer resp.Body.Close()
@@ -120,10 +280,7 @@ func (g *Gateway) ProxyRequest(c *gin.Context, service string, path string) {
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to read response",
-		})
+		respondError(c, upstreamError(err, service))
 		return
 	}
 
@@ -134,16 +291,84 @@ func (g *Gateway) ProxyRequest(c *gin.Context, service string, path string) {
 		}
 	}
 
+	// Translate upstream error messages; the length changes with the body
+	if resp.StatusCode >= http.StatusBadRequest {
+		if localized, ok := localizeError(i18n.FromContext(c.Request.Context()), respBody); ok {
+			respBody = localized
+			c.Writer.Header().Del("Content-Length")
+		}
+	}
+
 	// Return response
 	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), respBody)
 }
 
-// AuthMiddleware validates JWT tokens by calling auth-server
+// upstreamError classifies a failed call to a microservice as a timeout or
+// an unavailable service instead of a generic server error
+func upstreamError(err error, service string) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return apperrors.Wrap(err, apperrors.CodeTimeout, fmt.Sprintf("%s service timed out", service))
+	}
+	return apperrors.Unavailable(err, fmt.Sprintf("Failed to reach %s service", service))
+}
+
+// localizeError rewrites the "error" message of a JSON error body from an
+// upstream service into lang. It reports false when the body was left as is.
+func localizeError(lang i18n.Lang, body []byte) ([]byte, bool) {
+	if lang == i18n.English {
+		return nil, false
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, false
+	}
+	message, ok := payload["error"].(string)
+	if !ok {
+		return nil, false
+	}
+	code, _ := payload["code"].(string)
+
+	payload["error"] = i18n.Localize(lang, apperrors.Code(code), message)
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// LocaleMiddleware negotiates the response language from Accept-Language
+// and stores it in the request context for respondError and ProxyRequest
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(i18n.WithLang(c.Request.Context(), lang))
+		c.Header("Content-Language", string(lang))
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// AuthMiddleware validates JWT tokens locally when configured, and by
+// calling auth-server otherwise
 func (g *Gateway) AuthMiddleware() gin.HandlerFunc {
+	return g.authMiddleware(g.local)
+}
+
+// StrictAuthMiddleware always validates JWT tokens with auth-server, so
+// revoked sessions and suspended accounts are refused immediately
+func (g *Gateway) StrictAuthMiddleware() gin.HandlerFunc {
+	return g.authMiddleware(nil)
+}
+
+func (g *Gateway) authMiddleware(local *LocalAuth) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip auth for certain paths
 		if strings.HasPrefix(c.Request.URL.Path, "/api/auth/") || 
 		   c.Request.URL.Path == "/health" ||
+		   c.Request.URL.Path == "/live" ||
+		   c.Request.URL.Path == "/ready" ||
 		   c.Request.URL.Path == "/api-docs" {
 			c.Next()
 			return
@@ -151,29 +376,55 @@ func (g *Gateway) AuthMiddleware() gin.HandlerFunc {
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Authorization header required",
-			})
+			respondError(c, apperrors.Catalog(apperrors.ReasonTokenRequired))
 			c.Abort()
 			return
 		}
 
+		// Local validation sees logouts through the blacklist, but a session
+		// revoked otherwise stays usable until its access token expires
+		if local != nil {
+			claims, err := local.validate(c.Request.Context(), strings.TrimPrefix(authHeader, "Bearer "))
+			switch {
+			case err == nil:
+				setUser(c, claims)
+				c.Next()
+				return
+			case !errors.Is(err, errLocalAuthUnavailable) || !local.Fallback:
+				respondError(c, err)
+				c.Abort()
+				return
+			}
+			ginlog.From(c).Warn("Local token validation unavailable, falling back to auth-server", "error", err)
+		}
+
 		// Validate token with auth-server
-		req, _ := http.NewRequest("GET", g.services["auth"].BaseURL+"/auth/validate", nil)
+		authService, _ := g.services.Get("auth")
+		req, _ := http.NewRequestWithContext(c.Request.Context(), "GET", authService.BaseURL+"/auth/validate", nil)
 		req.Header.Set("Authorization", authHeader)
+		setRequestID(c, req)
 
 		resp, err := g.client.Do(req)
-		if err != nil || resp.StatusCode != http.StatusOK {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Invalid token",
-			})
+		if err != nil {
+			// auth-server being down is not the client's fault
+			respondError(c, upstreamError(err, "auth"))
 			c.Abort()
 			return
 		}
 		defer resp.Body.Close()
 
+		if resp.StatusCode != http.StatusOK {
+			// keep auth-server's code and reason, such as an expired session
+			body, _ := io.ReadAll(resp.Body)
+			if err := apperrors.FromResponse(body); err != nil && resp.StatusCode < http.StatusInternalServerError {
+				respondError(c, err)
+			} else {
+				respondError(c, apperrors.Catalog(apperrors.ReasonInvalidToken))
+			}
+			c.Abort()
+			return
+		}
+
 		// Parse claims from response
 		var result struct {
 			Success bool                   `json:"success"`
@@ -181,37 +432,156 @@ func (g *Gateway) AuthMiddleware() gin.HandlerFunc {
 		}
 		
 		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.Success {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Token validation failed",
-			})
+			respondError(c, apperrors.Unauthorized("Token validation failed"))
 			c.Abort()
 			return
 		}
 
-		// Store user info in context
-		c.Set("user", result.Claims)
+		setUser(c, result.Claims)
+		c.Next()
+	}
+}
+
+// setUser stores the validated token claims in the context
+func setUser(c *gin.Context, claims map[string]interface{}) {
+	c.Set("user", claims)
+	if userID, ok := claims["user_id"]; ok {
+		ginlog.With(c, logger.KeyUserID, userID)
+	}
+}
+
+// RequireAdmin lets through only admins whose session passed an MFA
+// challenge (POST /api/auth/mfa/verify). It must run after AuthMiddleware.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := c.Get("user")
+		claims, _ := user.(map[string]interface{})
+
+		if claimsRole(c) != models.RoleAdmin {
+			respondError(c, apperrors.Catalog(apperrors.ReasonRoleRequired, "Admin"))
+			c.Abort()
+			return
+		}
+		if mfa, _ := claims["mfa"].(bool); !mfa {
+			respondError(c, apperrors.Catalog(apperrors.ReasonMFARequired))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
 
+// RequireRole lets through callers holding any of roles; admins always
+// pass. Upstream services check the role again, along with ownership of the
+// resource. It must run after AuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rbac.Allows(claimsRole(c), roles...) {
+			respondError(c, apperrors.Catalog(apperrors.ReasonRoleRequired, strings.Join(roles, " or ")))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// claimsRole is the authenticated caller's role; tokens issued before roles
+// were introduced belong to plain users
+func claimsRole(c *gin.Context) string {
+	user, _ := c.Get("user")
+	claims, _ := user.(map[string]interface{})
+	if role, _ := claims["role"].(string); rbac.Valid(role) {
+		return role
+	}
+	return models.RoleUser
+}
+
+// healthChecker probes each upstream's HealthURL. Upstreams are probed on
+// /live rather than /ready so one service's database outage does not take
+// the whole gateway out of rotation; gRPC services (query-server) answer
+// the gRPC health protocol with their readiness instead. Each probe uses
+// the service's current HealthURL; whether a service is optional is fixed
+// at startup.
+func (g *Gateway) healthChecker() *health.Checker {
+	checker := health.NewChecker("api-gateway")
+	for _, key := range g.services.Keys() {
+		svc, _ := g.services.Get(key)
+		if svc.HealthURL == "" && svc.GRPCTarget == "" {
+			continue
+		}
+		if svc.Optional {
+			checker.AddOptional(svc.Name, g.healthCheck(key))
+		} else {
+			checker.Add(svc.Name, g.healthCheck(key))
+		}
+	}
+	return checker
+}
+
+// healthCheck probes a service's HealthURL as currently registered, or its
+// gRPC health
+func (g *Gateway) healthCheck(key string) health.CheckFunc {
+	return func(ctx context.Context) error {
+		svc, _ := g.services.Get(key)
+		if svc.GRPCTarget != "" {
+			return g.grpc[key].Check(ctx)
+		}
+		if svc.HealthURL == "" {
+			return nil
+		}
+		return health.HTTP(g.client, svc.HealthURL)(ctx)
+	}
+}
+
+// setRequestID forwards the request id assigned by ginlog.Middleware so the
+// upstream service logs under the same id
+func setRequestID(c *gin.Context, req *http.Request) {
+	if id := logger.RequestID(c.Request.Context()); id != "" {
+		req.Header.Set(logger.RequestIDHeader, id)
+	}
+}
+
+// setActor tells the upstream service who is calling and with which role,
+// for its audit log and access checks. Client-supplied actor headers are
+// dropped so callers cannot impersonate another user.
+func setActor(c *gin.Context, req *http.Request) {
+	req.Header.Del(audit.ActorIDHeader)
+	req.Header.Del(audit.ActorTypeHeader)
+	req.Header.Del(rbac.Header)
+
+	user, ok := c.Get("user")
+	if !ok {
+		return
+	}
+	claims, _ := user.(map[string]interface{})
+	if userID, ok := claims["user_id"].(string); ok && userID != "" {
+		req.Header.Set(audit.ActorIDHeader, userID)
+		req.Header.Set(audit.ActorTypeHeader, audit.ActorUser)
+		req.Header.Set(rbac.Header, claimsRole(c))
+	}
+}
+
 // SetupRoutes configures all API routes
 func (g *Gateway) SetupRoutes(router *gin.Engine) {
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "ok",
-			"service": "api-gateway",
-			"timestamp": time.Now().Format(time.RFC3339),
-		})
-	})
+	// Deep health: every service's readiness and dependencies
+	router.GET("/health", g.HealthHandler())
+
+	// Liveness (process up) and readiness (upstream services reachable)
+	checker := g.healthChecker()
+	router.GET("/live", gin.WrapH(checker.LiveHandler()))
+	router.GET("/ready", gin.WrapH(checker.ReadyHandler()))
 
 	// API routes
 	api := router.Group("/api")
+	api.Use(g.ipLimit())
 	{
 		// Auth routes (no auth middleware)
 		auth := api.Group("/auth")
+		auth.Use(g.authLimit())
 		{
+			auth.GET("/nonce/challenge", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/nonce/challenge")
+			})
 			auth.GET("/nonce", func(c *gin.Context) {
 				g.ProxyRequest(c, "auth", "/auth/nonce")
 			})
@@ -227,33 +597,123 @@ func (g *Gateway) SetupRoutes(router *gin.Engine) {
 			auth.POST("/logout", func(c *gin.Context) {
 				g.ProxyRequest(c, "auth", "/auth/logout")
 			})
+			auth.GET("/.well-known/jwks.json", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/.well-known/jwks.json")
+			})
+			// Signed-in devices; auth-server checks the bearer token
+			auth.GET("/sessions", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/sessions")
+			})
+			auth.DELETE("/sessions/:id", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/sessions/"+c.Param("id"))
+			})
+			// KYC tier upgrades; auth-server checks the bearer token
+			auth.POST("/kyc/applications", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/kyc/applications")
+			})
+			auth.GET("/kyc/status", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/kyc/status")
+			})
+			// MFA enrollment and step-up; auth-server checks the bearer token
+			auth.POST("/mfa/setup", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/mfa/setup")
+			})
+			auth.POST("/mfa/enable", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/mfa/enable")
+			})
+			auth.POST("/mfa/verify", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/mfa/verify")
+			})
+			// Email verification and wallet recovery
+			auth.POST("/email", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/email")
+			})
+			auth.POST("/email/verify", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/email/verify")
+			})
+			auth.POST("/recovery/start", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/recovery/start")
+			})
+			auth.POST("/recovery/complete", func(c *gin.Context) {
+				g.ProxyRequest(c, "auth", "/auth/recovery/complete")
+			})
 		}
 
-		// Protected routes (require auth)
+		// Real-time updates over WebSocket; realtime-server checks the token,
+		// which browsers cannot send as a header and pass as access_token
+		api.GET("/realtime/ws", g.killSwitch(featureflags.Maintenance), g.proxy("realtime", "/ws"))
+
+		// Protected routes (require auth); maintenance takes them offline,
+		// while sign-in and the admin API stay up to switch it off
 		protected := api.Group("/")
-		protected.Use(g.AuthMiddleware())
+		protected.Use(g.killSwitch(featureflags.Maintenance), g.AuthMiddleware(), g.userLimit())
 		{
+			// Campaign categories for the app's tabs, with their campaign counts
+			protected.GET("/categories", g.cacheCampaigns(), g.query.GetCategories)
+
 			// Campaign routes
 			campaigns := protected.Group("/campaigns")
 			{
-				campaigns.GET("", func(c *gin.Context) {
-					g.ProxyRequest(c, "query", "/campaigns")
-				})
-				campaigns.GET("/:id", func(c *gin.Context) {
-					g.ProxyRequest(c, "query", "/campaigns/"+c.Param("id"))
+				campaigns.GET("", g.cacheCampaigns(), g.query.GetCampaigns)
+				campaigns.GET("/search", g.cacheCampaigns(), g.query.SearchCampaigns)
+				// Home feed of the mini-app
+				campaigns.GET("/trending", g.cacheCampaigns(), g.query.GetTrendingCampaigns)
+				campaigns.GET("/recommended", func(c *gin.Context) {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					userID := userClaims["user_id"].(string)
+					g.query.GetRecommendedCampaigns(c, userID)
 				})
-				campaigns.POST("", func(c *gin.Context) {
+				campaigns.GET("/:id", g.cacheCampaign(), g.query.GetCampaign)
+				campaigns.GET("/:id/stats", g.cacheCampaign(), g.query.GetCampaignStats)
+				// Merchants manage their own campaigns
+				campaigns.POST("", RequireRole(models.RoleMerchant), g.killSwitch(featureflags.FreezeCampaigns), g.quota(QuotaCampaignCreate), g.idempotencyKey(), g.bustsCampaigns(), func(c *gin.Context) {
 					g.ProxyRequest(c, "core", "/campaigns")
 				})
-				campaigns.PUT("/:id", func(c *gin.Context) {
+				campaigns.POST("/:id/clone", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), g.quota(QuotaCampaignCreate), g.idempotencyKey(), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/clone")
+				})
+				campaigns.PUT("/:id", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), g.bustsCampaigns(), func(c *gin.Context) {
 					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id"))
 				})
+				campaigns.GET("/:id/history", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/history")
+				})
+				campaigns.POST("/:id/metadata/publish", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/metadata/publish")
+				})
+				// Review before a campaign goes on chain
+				campaigns.POST("/:id/submit", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/submit")
+				})
+				campaigns.POST("/:id/review", RequireRole(models.RoleOps), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/review")
+				})
+				campaigns.GET("/:id/reviews", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/reviews")
+				})
+				campaigns.POST("/:id/deployment", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/deployment")
+				})
+				campaigns.POST("/:id/settle", RequireRole(models.RoleOps), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/settle")
+				})
+				// Merchants mark orders fulfilled with proof before settlement
+				campaigns.GET("/:id/fulfillment", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/fulfillment")
+				})
+				campaigns.POST("/:id/fulfillment", RequireRole(models.RoleMerchant, models.RoleOps), g.killSwitch(featureflags.FreezeCampaigns), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/fulfillment")
+				})
+				campaigns.GET("/:id/fulfillments", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaigns/"+c.Param("id")+"/fulfillments")
+				})
 			}
 
 			// Payment routes
 			payments := protected.Group("/payment")
 			{
-				payments.POST("/create", func(c *gin.Context) {
+				payments.POST("/create", g.killSwitch(featureflags.FreezePayments), g.idempotencyKey(), func(c *gin.Context) {
 					g.ProxyRequest(c, "core", "/payments/process")
 				})
 				payments.GET("/:id/status", func(c *gin.Context) {
@@ -261,6 +721,21 @@ func (g *Gateway) SetupRoutes(router *gin.Engine) {
 				})
 			}
 
+			// Nested reads of the query API for the frontends
+			if g.graphql != nil {
+				protected.POST("/graphql", g.GraphQL)
+			}
+
+			// Feature flags for the current user
+			protected.GET("/features", func(c *gin.Context) {
+				user, _ := c.Get("user")
+				userClaims := user.(map[string]interface{})
+				q := c.Request.URL.Query()
+				q.Set("userId", userClaims["user_id"].(string))
+				c.Request.URL.RawQuery = q.Encode()
+				g.ProxyRequest(c, "core", "/features")
+			})
+
 			// Participation routes
 			participations := protected.Group("/participations")
 			{
@@ -269,22 +744,35 @@ func (g *Gateway) SetupRoutes(router *gin.Engine) {
 					user, _ := c.Get("user")
 					userClaims := user.(map[string]interface{})
 					userID := userClaims["user_id"].(string)
-					g.ProxyRequest(c, "query", "/participations/user/"+userID)
+					g.query.GetUserParticipations(c, userID)
 				})
-				participations.POST("/cancel", func(c *gin.Context) {
+				participations.POST("/cancel", g.killSwitch(featureflags.FreezeParticipations), g.quota(QuotaTxBuild), func(c *gin.Context) {
 					g.ProxyRequest(c, "tx-helper", "/tx/cancel-participation")
 				})
+				// Quote a cancel and record it as pending before the requestCancel transaction
+				participations.POST("/:id/cancel-request", g.killSwitch(featureflags.FreezeParticipations), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/participations/"+c.Param("id")+"/cancel-request")
+				})
 			}
 
 			// Transaction helper routes
 			tx := protected.Group("/tx")
 			{
-				tx.POST("/join", func(c *gin.Context) {
+				tx.POST("/join", g.killSwitch(featureflags.FreezeParticipations), g.quota(QuotaTxBuild), g.idempotencyKey(), func(c *gin.Context) {
 					g.ProxyRequest(c, "tx-helper", "/tx/join-campaign")
 				})
-				tx.POST("/cancel", func(c *gin.Context) {
+				tx.POST("/cancel", g.killSwitch(featureflags.FreezeParticipations), g.quota(QuotaTxBuild), g.idempotencyKey(), func(c *gin.Context) {
 					g.ProxyRequest(c, "tx-helper", "/tx/cancel-participation")
 				})
+				tx.POST("/deploy-campaign", RequireRole(models.RoleMerchant), g.killSwitch(featureflags.FreezeCampaigns), g.quota(QuotaTxBuild), func(c *gin.Context) {
+					g.ProxyRequest(c, "tx-helper", "/tx/deploy-campaign")
+				})
+				tx.POST("/settle-campaign", RequireRole(models.RoleOps), g.quota(QuotaTxBuild), func(c *gin.Context) {
+					g.ProxyRequest(c, "tx-helper", "/tx/settle-campaign")
+				})
+				tx.POST("/refund", RequireRole(models.RoleOps), g.quota(QuotaTxBuild), func(c *gin.Context) {
+					g.ProxyRequest(c, "tx-helper", "/tx/refund")
+				})
 				tx.GET("/estimate-gas", func(c *gin.Context) {
 					g.ProxyRequest(c, "tx-helper", "/tx/estimate-gas")
 				})
@@ -294,15 +782,291 @@ func (g *Gateway) SetupRoutes(router *gin.Engine) {
 			users := protected.Group("/users")
 			{
 				users.GET("/profile", func(c *gin.Context) {
-					g.ProxyRequest(c, "query", "/users/profile")
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					userID := userClaims["user_id"].(string)
+					g.query.GetUserProfile(c, userID)
+				})
+				users.GET("/portfolio", func(c *gin.Context) {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					userID := userClaims["user_id"].(string)
+					g.query.GetUserPortfolio(c, userID)
+				})
+				users.GET("/metadata", func(c *gin.Context) {
+					g.ProxyRequest(c, "auth", "/auth/me/metadata")
+				})
+				users.PATCH("/metadata", func(c *gin.Context) {
+					g.ProxyRequest(c, "auth", "/auth/me/metadata")
 				})
 				users.PUT("/profile", func(c *gin.Context) {
 					g.ProxyRequest(c, "core", "/users/profile")
 				})
+
+				// Watchlist: read from query-server, changed through core-server
+				favoritePath := func(c *gin.Context) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/favorites/user/" + userClaims["user_id"].(string) + "/" + url.PathEscape(c.Param("campaignId"))
+				}
+				users.GET("/favorites", func(c *gin.Context) {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					g.query.GetUserFavorites(c, userClaims["user_id"].(string))
+				})
+				users.PUT("/favorites/:campaignId", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", favoritePath(c))
+				})
+				users.DELETE("/favorites/:campaignId", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", favoritePath(c))
+				})
+
+				// Referral code, stats and rewards
+				referralPath := func(c *gin.Context, suffix string) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/referrals/user/" + userClaims["user_id"].(string) + suffix
+				}
+				users.GET("/referral", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", referralPath(c, ""))
+				})
+				users.POST("/referral", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", referralPath(c, ""))
+				})
+				users.GET("/referral/rewards", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", referralPath(c, "/rewards"))
+				})
+			}
+
+			// Merchant registration of the current user, merchant profiles and
+			// merchant dashboards
+			merchants := protected.Group("/merchants")
+			{
+				merchantPath := func(c *gin.Context) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/merchants/user/" + userClaims["user_id"].(string)
+				}
+				merchants.POST("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", merchantPath(c))
+				})
+				merchants.GET("/me", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", merchantPath(c))
+				})
+				merchants.GET("", g.query.GetMerchants)
+				merchants.GET("/:id", g.query.GetMerchant)
+				merchants.GET("/me/dashboard", RequireRole(models.RoleMerchant), func(c *gin.Context) {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					g.ProxyRequest(c, "core", "/merchants/"+userClaims["user_id"].(string)+"/dashboard")
+				})
+				merchants.GET("/:id/dashboard", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/merchants/"+c.Param("id")+"/dashboard")
+				})
+			}
+
+			// Campaign image, merchant logo and fulfillment proof uploads
+			media := protected.Group("/media/uploads")
+			{
+				media.POST("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/media/uploads")
+				})
+				media.GET("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/media/uploads/"+c.Param("id"))
+				})
+				media.POST("/:id/complete", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/media/uploads/"+c.Param("id")+"/complete")
+				})
+			}
+
+			// Push devices, notification preferences and the LINE and email
+			// delivery log of the current user
+			notifications := protected.Group("/notifications")
+			{
+				userPath := func(c *gin.Context, suffix string) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/notifications/user/" + userClaims["user_id"].(string) + suffix
+				}
+				notifications.GET("/devices", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/devices"))
+				})
+				notifications.POST("/devices", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/devices"))
+				})
+				notifications.DELETE("/devices/:token", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/devices/"+url.PathEscape(c.Param("token"))))
+				})
+				notifications.GET("/preferences", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/preferences"))
+				})
+				notifications.PUT("/preferences", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/preferences"))
+				})
+				notifications.GET("/deliveries", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/deliveries"))
+				})
+			}
+
+			// Fulfillments of the current user's orders, to confirm or dispute
+			fulfillments := protected.Group("/fulfillments")
+			{
+				userPath := func(c *gin.Context, suffix string) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/fulfillments/user/" + userClaims["user_id"].(string) + suffix
+				}
+				fulfillments.GET("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, ""))
+				})
+				fulfillments.POST("/:participationId/confirm", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/"+url.PathEscape(c.Param("participationId"))+"/confirm"))
+				})
+				fulfillments.POST("/:participationId/dispute", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/"+url.PathEscape(c.Param("participationId"))+"/dispute"))
+				})
+			}
+
+			// Disputes of the current user's fulfillments and settlements
+			disputes := protected.Group("/disputes")
+			{
+				userPath := func(c *gin.Context, suffix string) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/disputes/user/" + userClaims["user_id"].(string) + suffix
+				}
+				disputes.GET("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, ""))
+				})
+				disputes.POST("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, ""))
+				})
+				disputes.GET("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/"+url.PathEscape(c.Param("id"))))
+				})
+			}
+
+			// Redemption vouchers: the current user's own, and merchants
+			// validating and redeeming codes at the point of sale
+			vouchers := protected.Group("/vouchers")
+			{
+				userPath := func(c *gin.Context, suffix string) string {
+					user, _ := c.Get("user")
+					userClaims := user.(map[string]interface{})
+					return "/vouchers/user/" + userClaims["user_id"].(string) + suffix
+				}
+				vouchers.GET("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, ""))
+				})
+				vouchers.GET("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", userPath(c, "/"+url.PathEscape(c.Param("id"))))
+				})
+				vouchers.POST("/validate", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/vouchers/validate")
+				})
+				vouchers.POST("/redeem", RequireRole(models.RoleMerchant, models.RoleOps), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/vouchers/redeem")
+				})
+			}
+
+			// Campaign templates for relaunching recurring offers
+			templates := protected.Group("/campaign-templates", RequireRole(models.RoleMerchant, models.RoleOps))
+			{
+				templatePath := func(c *gin.Context, suffix string) string {
+					return "/campaign-templates/" + url.PathEscape(c.Param("id")) + suffix
+				}
+				templates.GET("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaign-templates")
+				})
+				templates.POST("", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", "/campaign-templates")
+				})
+				templates.GET("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", templatePath(c, ""))
+				})
+				templates.PUT("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", templatePath(c, ""))
+				})
+				templates.DELETE("/:id", func(c *gin.Context) {
+					g.ProxyRequest(c, "core", templatePath(c, ""))
+				})
+				templates.POST("/:id/launch", g.killSwitch(featureflags.FreezeCampaigns), g.quota(QuotaCampaignCreate), g.idempotencyKey(), g.bustsCampaigns(), func(c *gin.Context) {
+					g.ProxyRequest(c, "core", templatePath(c, "/launch"))
+				})
 			}
 		}
 	}
 
+	// Admin dashboard (admin role with a verified MFA challenge)
+	admin := router.Group("/api/admin")
+	admin.Use(g.ipLimit(), g.StrictAuthMiddleware(), RequireAdmin(), g.adminLimit())
+	{
+		admin.GET("/overview", g.proxy("core", "/admin/overview"))
+		admin.GET("/users", g.proxy("core", "/admin/users"))
+		admin.POST("/users/suspend", g.proxy("core", "/admin/users/suspend"))
+		admin.POST("/users/reinstate", g.proxy("core", "/admin/users/reinstate"))
+		admin.POST("/users/role", g.proxy("core", "/admin/users/role"))
+		admin.GET("/merchants", g.proxy("core", "/admin/merchants"))
+		admin.GET("/merchants/registrations", g.proxy("core", "/admin/merchants/registrations"))
+		admin.GET("/merchants/:id", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/merchants/"+c.Param("id"))
+		})
+		admin.POST("/merchants/:id/status", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/merchants/"+c.Param("id")+"/status")
+		})
+		admin.GET("/campaigns", g.proxy("core", "/admin/campaigns"))
+		admin.POST("/campaigns/pause", g.proxy("core", "/admin/campaigns/pause"))
+		admin.POST("/campaigns/resume", g.proxy("core", "/admin/campaigns/resume"))
+		admin.GET("/payments", g.proxy("core", "/admin/payments"))
+		// Disputes and their refunds
+		admin.GET("/disputes", g.proxy("core", "/admin/disputes"))
+		admin.GET("/disputes/:id", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/disputes/"+c.Param("id"))
+		})
+		admin.POST("/disputes/:id/resolve", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/disputes/"+c.Param("id")+"/resolve")
+		})
+		admin.POST("/disputes/:id/refund", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/disputes/"+c.Param("id")+"/refund")
+		})
+		// Campaign category taxonomy; campaign lists carry category names
+		admin.POST("/categories", g.bustsCampaigns(), g.proxy("core", "/admin/categories"))
+		admin.PUT("/categories/:slug", g.bustsCampaigns(), func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/categories/"+c.Param("slug"))
+		})
+		admin.DELETE("/categories/:slug", g.bustsCampaigns(), func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/categories/"+c.Param("slug"))
+		})
+		// Campaign policies of the merchant tiers
+		admin.GET("/campaign-policies", g.proxy("core", "/admin/campaign-policies"))
+		admin.GET("/campaign-policies/:tier", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/campaign-policies/"+c.Param("tier"))
+		})
+		admin.PUT("/campaign-policies/:tier", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/campaign-policies/"+c.Param("tier"))
+		})
+		admin.DELETE("/campaign-policies/:tier", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/campaign-policies/"+c.Param("tier"))
+		})
+		admin.GET("/audit-log", g.proxy("core", "/admin/audit-log"))
+		// Feature flags, including the maintenance and freeze switches
+		admin.GET("/features", g.proxy("core", "/admin/features"))
+		admin.GET("/features/:name", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/features/"+c.Param("name"))
+		})
+		admin.PUT("/features/:name", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/features/"+c.Param("name"))
+		})
+		admin.DELETE("/features/:name", func(c *gin.Context) {
+			g.ProxyRequest(c, "core", "/admin/features/"+c.Param("name"))
+		})
+		// Per-user quotas, kept by the gateway
+		admin.GET("/quotas/:name/users/:id", g.QuotaUsage)
+		admin.PUT("/quotas/:name/users/:id", g.SetQuotaLimit)
+		admin.DELETE("/quotas/:name/users/:id/limit", g.ClearQuotaLimit)
+		admin.DELETE("/quotas/:name/users/:id/usage", g.ResetQuota)
+	}
+
 	// Webhook routes (no auth, but verify signature)
 	webhooks := router.Group("/webhooks")
 	{
@@ -312,5 +1076,8 @@ func (g *Gateway) SetupRoutes(router *gin.Engine) {
 		webhooks.POST("/blockchain", func(c *gin.Context) {
 			g.ProxyRequest(c, "event-receiver", "/events/webhook")
 		})
+		webhooks.POST("/kyc", func(c *gin.Context) {
+			g.ProxyRequest(c, "auth", "/auth/kyc/webhook")
+		})
 	}
 }
\ No newline at end of file
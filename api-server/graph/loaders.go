@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vikstrous/dataloadgen"
+
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// Loaders batch the campaigns and merchants that a request resolves by id,
+// so a page of participations costs one GetCampaigns call instead of one
+// per row. They live for a single request.
+type Loaders struct {
+	campaigns *dataloadgen.Loader[string, *query.Campaign]
+	merchants *dataloadgen.Loader[string, *query.Merchant]
+}
+
+// loaderWait is how long a loader collects ids before calling query-server
+const loaderWait = 2 * time.Millisecond
+
+// maxBatch is the page limit query-server allows for GetCampaigns and GetMerchants
+const maxBatch = 100
+
+type loadersKey struct{}
+
+func newLoaders(r *Resolver) *Loaders {
+	opts := []dataloadgen.Option{dataloadgen.WithWait(loaderWait), dataloadgen.WithBatchCapacity(maxBatch)}
+	return &Loaders{
+		campaigns: dataloadgen.NewMappedLoader(r.fetchCampaigns, opts...),
+		merchants: dataloadgen.NewMappedLoader(r.fetchMerchants, opts...),
+	}
+}
+
+// withLoaders returns ctx with new loaders for one request
+func withLoaders(ctx context.Context, r *Resolver) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(r))
+}
+
+// loadersFor returns the loaders of the request in ctx, or new ones outside a request
+func (r *Resolver) loadersFor(ctx context.Context) *Loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*Loaders); ok {
+		return l
+	}
+	return newLoaders(r)
+}
+
+func (r *Resolver) fetchCampaigns(ctx context.Context, ids []string) (map[string]*query.Campaign, error) {
+	resp, err := r.campaigns.GetCampaigns(ctx, &query.GetCampaignsRequest{Ids: ids, Limit: int32(len(ids)), SkipCount: true})
+	if err != nil {
+		return nil, err
+	}
+	campaigns := make(map[string]*query.Campaign, len(resp.Campaigns))
+	for _, c := range resp.Campaigns {
+		campaigns[c.Id] = c
+	}
+	return campaigns, nil
+}
+
+func (r *Resolver) fetchMerchants(ctx context.Context, ids []string) (map[string]*query.Merchant, error) {
+	resp, err := r.merchants.GetMerchants(ctx, &query.GetMerchantsRequest{Ids: ids, Limit: int32(len(ids))})
+	if err != nil {
+		return nil, err
+	}
+	merchants := make(map[string]*query.Merchant, len(resp.Merchants))
+	for _, m := range resp.Merchants {
+		merchants[m.Id] = m
+	}
+	return merchants, nil
+}
+
+// loadCampaign returns the campaign with id, or nil if there is none
+func (r *Resolver) loadCampaign(ctx context.Context, id string) (*query.Campaign, error) {
+	return orNil(r.loadersFor(ctx).campaigns.Load(ctx, id))
+}
+
+// loadMerchant returns the merchant with id, or nil if there is none
+func (r *Resolver) loadMerchant(ctx context.Context, id string) (*query.Merchant, error) {
+	return orNil(r.loadersFor(ctx).merchants.Load(ctx, id))
+}
+
+// primeCampaigns caches campaigns a list already returned for later loads
+func (r *Resolver) primeCampaigns(ctx context.Context, campaigns []*query.Campaign) {
+	loader := r.loadersFor(ctx).campaigns
+	for _, c := range campaigns {
+		loader.Prime(c.Id, c)
+	}
+}
+
+// orNil turns a missing id into a null result
+func orNil[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, dataloadgen.ErrNotFound) {
+		return nil, nil
+	}
+	return v, err
+}
@@ -0,0 +1,95 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+type CampaignConnection struct {
+	Campaigns  []*query.Campaign `json:"campaigns"`
+	TotalCount int               `json:"totalCount"`
+	// Pass as after for the next page; empty on the last page
+	NextCursor string `json:"nextCursor"`
+}
+
+type MerchantConnection struct {
+	Merchants  []*query.Merchant `json:"merchants"`
+	TotalCount int               `json:"totalCount"`
+	NextCursor string            `json:"nextCursor"`
+}
+
+type ParticipationConnection struct {
+	Participations []*query.Participation `json:"participations"`
+	TotalCount     int                    `json:"totalCount"`
+	NextCursor     string                 `json:"nextCursor"`
+}
+
+type Query struct {
+}
+
+type CampaignSort string
+
+const (
+	CampaignSortNewest CampaignSort = "NEWEST"
+	// Soonest end first; ended campaigns last
+	CampaignSortEndingSoon CampaignSort = "ENDING_SOON"
+	CampaignSortMostFunded CampaignSort = "MOST_FUNDED"
+	// Grouped by category in tab order, newest first within one; uncategorized last
+	CampaignSortCategory CampaignSort = "CATEGORY"
+)
+
+var AllCampaignSort = []CampaignSort{
+	CampaignSortNewest,
+	CampaignSortEndingSoon,
+	CampaignSortMostFunded,
+	CampaignSortCategory,
+}
+
+func (e CampaignSort) IsValid() bool {
+	switch e {
+	case CampaignSortNewest, CampaignSortEndingSoon, CampaignSortMostFunded, CampaignSortCategory:
+		return true
+	}
+	return false
+}
+
+func (e CampaignSort) String() string {
+	return string(e)
+}
+
+func (e *CampaignSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CampaignSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CampaignSort", str)
+	}
+	return nil
+}
+
+func (e CampaignSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CampaignSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CampaignSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
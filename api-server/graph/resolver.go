@@ -0,0 +1,164 @@
+// Package graph is the GraphQL facade of the query service. The schema is
+// in schema.graphqls; generated.go and model/models_gen.go are generated
+// from it by gqlgen (see gqlgen.yml), and the resolvers in *.resolvers.go
+// call query-server over gRPC.
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc"
+
+	"github.com/Reserve-to-save-backend/api-server/graph/model"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/i18n"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// Resolver resolves the schema against query-server
+type Resolver struct {
+	campaigns      query.QueryServiceClient
+	merchants      query.MerchantServiceClient
+	participations query.ParticipationServiceClient
+	users          query.UserServiceClient
+}
+
+// NewResolver creates a Resolver calling query-server over conn
+func NewResolver(conn grpc.ClientConnInterface) *Resolver {
+	return &Resolver{
+		campaigns:      query.NewQueryServiceClient(conn),
+		merchants:      query.NewMerchantServiceClient(conn),
+		participations: query.NewParticipationServiceClient(conn),
+		users:          query.NewUserServiceClient(conn),
+	}
+}
+
+// NewHandler serves GraphQL POST requests over conn, rejecting operations
+// whose complexity (one per field, times first on lists) exceeds
+// complexityLimit. Requests must carry the signed-in user (WithUserID).
+func NewHandler(conn grpc.ClientConnInterface, complexityLimit int) http.Handler {
+	r := NewResolver(conn)
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: r, Complexity: complexity()}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.FixedComplexityLimit(complexityLimit))
+	srv.SetErrorPresenter(presentError)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		srv.ServeHTTP(w, req.WithContext(withLoaders(req.Context(), r)))
+	})
+}
+
+// defaultFirst is the page size of query-server when first is not given
+const defaultFirst = 20
+
+// campaignSorts maps CampaignSort to the query-server sort
+var campaignSorts = map[model.CampaignSort]query.CampaignSort{
+	model.CampaignSortNewest:     query.CampaignSort_CAMPAIGN_SORT_NEWEST,
+	model.CampaignSortEndingSoon: query.CampaignSort_CAMPAIGN_SORT_ENDING_SOON,
+	model.CampaignSortMostFunded: query.CampaignSort_CAMPAIGN_SORT_MOST_FUNDED,
+	model.CampaignSortCategory:   query.CampaignSort_CAMPAIGN_SORT_CATEGORY,
+}
+
+// int32Of returns *v, or 0 when the argument was not given
+func int32Of(v *int) int32 {
+	if v == nil {
+		return 0
+	}
+	return int32(*v)
+}
+
+// stringOf returns *v, or "" when the argument was not given
+func stringOf(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// optional returns nil for the empty strings query-server sends for unset values
+func optional(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// campaignConnection returns a page of campaigns, caching them for later loads
+func (r *Resolver) campaignConnection(ctx context.Context, resp *query.GetCampaignsResponse) *model.CampaignConnection {
+	r.primeCampaigns(ctx, resp.Campaigns)
+	return &model.CampaignConnection{Campaigns: resp.Campaigns, TotalCount: int(resp.TotalCount), NextCursor: resp.NextCursor}
+}
+
+// complexity counts list fields as first items each
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	page := func(childComplexity int, first *int) int {
+		n := defaultFirst
+		if first != nil && *first > 0 {
+			n = *first
+		}
+		return 1 + n*childComplexity
+	}
+	c.Query.Campaigns = func(childComplexity int, first *int, _ *string, _ []string, _ *string, _ *string, _ []string, _ *string, _ *model.CampaignSort) int {
+		return page(childComplexity, first)
+	}
+	c.Query.TrendingCampaigns = func(childComplexity int, first *int, _ *int) int {
+		return page(childComplexity, first)
+	}
+	c.Query.Merchants = func(childComplexity int, first *int, _ *string) int {
+		return page(childComplexity, first)
+	}
+	c.Merchant.Campaigns = func(childComplexity int, first *int, _ *string, _ []string) int {
+		return page(childComplexity, first)
+	}
+	c.User.Participations = func(childComplexity int, first *int, _ *string, _ *string) int {
+		return page(childComplexity, first)
+	}
+	return c
+}
+
+type userIDKey struct{}
+
+// WithUserID returns ctx carrying the id of the signed-in user
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// userID returns the id of the signed-in user
+func userID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(userIDKey{}).(string)
+	return id, ok
+}
+
+// presentError reports query-server and argument errors with the code and
+// reason the REST API sends, in the language of the request
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	cause := gqlErr.Err
+	if cause == nil {
+		// parse and validation errors of the operation itself
+		return gqlErr
+	}
+	code := apperrors.CodeOf(cause)
+	if apperrors.HTTPStatus(cause) >= http.StatusInternalServerError {
+		logger.FromContext(ctx).Error("graphql field failed", "path", gqlErr.Path.String(), "error", cause)
+	}
+	gqlErr.Message = i18n.Localize(i18n.FromContext(ctx), code, apperrors.MessageOf(cause))
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	gqlErr.Extensions["code"] = code
+	if reason := apperrors.ReasonOf(cause); reason != "" {
+		gqlErr.Extensions["reason"] = reason
+	}
+	return gqlErr
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MarshalTimestamp writes the Time scalar of a protobuf timestamp as RFC 3339
+func MarshalTimestamp(t *timestamppb.Timestamp) graphql.Marshaler {
+	if t == nil {
+		return graphql.Null
+	}
+	return graphql.MarshalTime(t.AsTime())
+}
+
+// UnmarshalTimestamp reads the Time scalar
+func UnmarshalTimestamp(v interface{}) (*timestamppb.Timestamp, error) {
+	t, err := graphql.UnmarshalTime(v)
+	if err != nil {
+		return nil, err
+	}
+	return timestamppb.New(t), nil
+}
+
+// MarshalUUID writes the ID of a query-server row, a UUID string
+func MarshalUUID(id string) graphql.Marshaler {
+	return graphql.MarshalString(id)
+}
+
+// UnmarshalUUID reads an ID, rejecting anything but a UUID
+func UnmarshalUUID(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%T is not a valid ID", v)
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a valid ID", s)
+	}
+	return id.String(), nil
+}
@@ -0,0 +1,167 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+
+	"github.com/Reserve-to-save-backend/api-server/graph/model"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// Merchant is the resolver for the merchant field.
+func (r *campaignResolver) Merchant(ctx context.Context, obj *query.Campaign) (*query.Merchant, error) {
+	return r.loadMerchant(ctx, obj.MerchantId)
+}
+
+// Stats is the resolver for the stats field.
+func (r *campaignResolver) Stats(ctx context.Context, obj *query.Campaign, days *int) (*query.CampaignStats, error) {
+	resp, err := r.campaigns.GetCampaignStats(ctx, &query.GetCampaignStatsRequest{CampaignId: obj.Id, Days: int32Of(days)})
+	if err != nil || !resp.Found {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+// Campaigns is the resolver for the campaigns field.
+func (r *merchantResolver) Campaigns(ctx context.Context, obj *query.Merchant, first *int, after *string, statuses []string) (*model.CampaignConnection, error) {
+	resp, err := r.campaigns.GetCampaigns(ctx, &query.GetCampaignsRequest{
+		MerchantId: obj.Id,
+		Statuses:   statuses,
+		Limit:      int32Of(first),
+		Cursor:     stringOf(after),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.campaignConnection(ctx, resp), nil
+}
+
+// Campaign is the resolver for the campaign field.
+func (r *participationResolver) Campaign(ctx context.Context, obj *query.Participation) (*query.Campaign, error) {
+	return r.loadCampaign(ctx, obj.CampaignId)
+}
+
+// ActualRebate is the resolver for the actualRebate field.
+func (r *participationResolver) ActualRebate(ctx context.Context, obj *query.Participation) (*string, error) {
+	return optional(obj.ActualRebate), nil
+}
+
+// Campaigns is the resolver for the campaigns field.
+func (r *queryResolver) Campaigns(ctx context.Context, first *int, after *string, statuses []string, merchantID *string, category *string, tags []string, q *string, sort *model.CampaignSort) (*model.CampaignConnection, error) {
+	req := &query.GetCampaignsRequest{
+		Statuses:   statuses,
+		MerchantId: stringOf(merchantID),
+		Category:   stringOf(category),
+		Tags:       tags,
+		Q:          stringOf(q),
+		Limit:      int32Of(first),
+		Cursor:     stringOf(after),
+	}
+	if sort != nil {
+		req.Sort = campaignSorts[*sort]
+	}
+	resp, err := r.campaigns.GetCampaigns(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return r.campaignConnection(ctx, resp), nil
+}
+
+// Campaign is the resolver for the campaign field.
+func (r *queryResolver) Campaign(ctx context.Context, id string) (*query.Campaign, error) {
+	return r.loadCampaign(ctx, id)
+}
+
+// TrendingCampaigns is the resolver for the trendingCampaigns field.
+func (r *queryResolver) TrendingCampaigns(ctx context.Context, first *int, hours *int) ([]*query.Campaign, error) {
+	resp, err := r.campaigns.GetTrendingCampaigns(ctx, &query.GetTrendingCampaignsRequest{Limit: int32Of(first), WindowHours: int32Of(hours)})
+	if err != nil {
+		return nil, err
+	}
+	campaigns := make([]*query.Campaign, len(resp.Campaigns))
+	for i, t := range resp.Campaigns {
+		campaigns[i] = t.Campaign
+	}
+	r.primeCampaigns(ctx, campaigns)
+	return campaigns, nil
+}
+
+// Merchants is the resolver for the merchants field.
+func (r *queryResolver) Merchants(ctx context.Context, first *int, after *string) (*model.MerchantConnection, error) {
+	resp, err := r.merchants.GetMerchants(ctx, &query.GetMerchantsRequest{Limit: int32Of(first), Cursor: stringOf(after)})
+	if err != nil {
+		return nil, err
+	}
+	return &model.MerchantConnection{Merchants: resp.Merchants, TotalCount: int(resp.TotalCount), NextCursor: resp.NextCursor}, nil
+}
+
+// Merchant is the resolver for the merchant field.
+func (r *queryResolver) Merchant(ctx context.Context, id string) (*query.Merchant, error) {
+	return r.loadMerchant(ctx, id)
+}
+
+// Categories is the resolver for the categories field.
+func (r *queryResolver) Categories(ctx context.Context) ([]*query.Category, error) {
+	resp, err := r.campaigns.GetCategories(ctx, &query.GetCategoriesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Categories, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*query.User, error) {
+	id, ok := userID(ctx)
+	if !ok {
+		return nil, apperrors.Catalog(apperrors.ReasonTokenRequired)
+	}
+	resp, err := r.users.GetUser(ctx, &query.GetUserRequest{UserId: id})
+	if err != nil || !resp.Found {
+		return nil, err
+	}
+	return resp.User, nil
+}
+
+// LineUserID is the resolver for the lineUserId field.
+func (r *userResolver) LineUserID(ctx context.Context, obj *query.User) (*string, error) {
+	return optional(obj.LineUserId), nil
+}
+
+// Participations is the resolver for the participations field.
+func (r *userResolver) Participations(ctx context.Context, obj *query.User, first *int, after *string, status *string) (*model.ParticipationConnection, error) {
+	resp, err := r.participations.GetUserParticipations(ctx, &query.GetUserParticipationsRequest{
+		UserId: obj.Id,
+		Status: stringOf(status),
+		Limit:  int32Of(first),
+		Cursor: stringOf(after),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.ParticipationConnection{Participations: resp.Participations, TotalCount: int(resp.TotalCount), NextCursor: resp.NextCursor}, nil
+}
+
+// Campaign returns CampaignResolver implementation.
+func (r *Resolver) Campaign() CampaignResolver { return &campaignResolver{r} }
+
+// Merchant returns MerchantResolver implementation.
+func (r *Resolver) Merchant() MerchantResolver { return &merchantResolver{r} }
+
+// Participation returns ParticipationResolver implementation.
+func (r *Resolver) Participation() ParticipationResolver { return &participationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type campaignResolver struct{ *Resolver }
+type merchantResolver struct{ *Resolver }
+type participationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
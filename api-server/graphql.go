@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/api-server/graph"
+)
+
+// GraphQL handles POST /api/graphql for the signed-in user. Field errors
+// come back in the errors list of a 200 response, with the code and reason
+// of the REST error in their extensions.
+func (g *Gateway) GraphQL(c *gin.Context) {
+	user, _ := c.Get("user")
+	userClaims := user.(map[string]interface{})
+	userID, err := parseID(userClaims["user_id"].(string), "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Request = c.Request.WithContext(graph.WithUserID(c.Request.Context(), userID))
+	g.graphql.ServeHTTP(c.Writer, c.Request)
+}
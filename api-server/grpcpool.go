@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCPool calls a registered service over gRPC on a pool of connections,
+// used round robin so one HTTP/2 connection's stream limit does not queue
+// the gateway's calls. Every call gets the policy ProxyRequest applies to
+// HTTP services: the service's timeout as its deadline (sooner if the
+// caller's is), retries of unavailable calls with backoff, and the service's
+// circuit breaker. The pool redials when the registry moves the service.
+type GRPCPool struct {
+	services *Registry
+	service  string
+	size     int
+	opts     []grpc.DialOption
+
+	mu     sync.Mutex
+	target string
+	conns  []*grpc.ClientConn
+	next   atomic.Uint64
+}
+
+// NewGRPCPool returns a pool of size connections to service, which must be
+// registered with a grpc:// URL. Connections are established on first use.
+func NewGRPCPool(services *Registry, service string, size int, opts ...grpc.DialOption) (*GRPCPool, error) {
+	p := &GRPCPool{services: services, service: service, size: size}
+	p.opts = append([]grpc.DialOption{grpc.WithChainUnaryInterceptor(p.policy)}, opts...)
+	if _, err := p.conn(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Invoke implements grpc.ClientConnInterface
+func (p *GRPCPool) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	conn, err := p.conn()
+	if err != nil {
+		return err
+	}
+	return conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface. Streams get neither the
+// deadline nor the retries of unary calls.
+func (p *GRPCPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	conn, err := p.conn()
+	if err != nil {
+		return nil, err
+	}
+	return conn.NewStream(ctx, desc, method, opts...)
+}
+
+// Check probes the service with the gRPC health protocol
+func (p *GRPCPool) Check(ctx context.Context) error {
+	return health.GRPC(p)(ctx)
+}
+
+// Close closes every connection
+func (p *GRPCPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+// conn picks the next connection, dialing the service's current target
+// first if it changed
+func (p *GRPCPool) conn() (*grpc.ClientConn, error) {
+	svc, ok := p.services.Get(p.service)
+	if !ok || svc.GRPCTarget == "" {
+		return nil, apperrors.Internal(fmt.Errorf("service %q is not registered as a gRPC service", p.service))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if svc.GRPCTarget != p.target || len(p.conns) == 0 {
+		conns := make([]*grpc.ClientConn, 0, p.size)
+		for i := 0; i < p.size; i++ {
+			conn, err := grpc.NewClient(svc.GRPCTarget, p.opts...)
+			if err != nil {
+				for _, c := range conns {
+					c.Close()
+				}
+				return nil, apperrors.Internal(fmt.Errorf("failed to dial %s: %w", svc.Name, err))
+			}
+			conns = append(conns, conn)
+		}
+		if p.target != "" {
+			slog.Info("Service moved, redialed gRPC connections", "service", p.service, "target", svc.GRPCTarget)
+		}
+		// calls still running on the old connections get the timeout to end
+		if old := p.conns; len(old) > 0 {
+			time.AfterFunc(svc.Timeout, func() {
+				for _, c := range old {
+					c.Close()
+				}
+			})
+		}
+		p.target, p.conns = svc.GRPCTarget, conns
+	}
+	return p.conns[p.next.Add(1)%uint64(len(p.conns))], nil
+}
+
+// policy is the unary interceptor applying the service's deadline, retries
+// and circuit breaker; the calls are reads, so all of them may be retried.
+// Health checks bypass it, so they neither trip nor close the circuit.
+func (p *GRPCPool) policy(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if method == healthpb.Health_Check_FullMethodName {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	svc, ok := p.services.Get(p.service)
+	if !ok {
+		return apperrors.Internal(fmt.Errorf("service %q is not configured", p.service))
+	}
+
+	for attempt := 1; ; attempt++ {
+		if ok, wait := svc.breaker.allow(); !ok {
+			return &circuitOpenError{service: p.service, wait: wait}
+		}
+
+		// the deadline reaches the service as grpc-timeout
+		callCtx, cancel := context.WithTimeout(ctx, svc.Timeout)
+		err := invoker(callCtx, method, req, reply, cc, opts...)
+		cancel()
+		code := status.Code(err)
+		failed := code == codes.Unavailable || code == codes.DeadlineExceeded
+		svc.breaker.record(!failed)
+		// timeouts are not retried; the attempts would add up past the client's patience
+		if code != codes.Unavailable || attempt > svc.Retries {
+			return err
+		}
+
+		logger.FromContext(ctx).Warn("Retrying upstream call", "service", p.service, "method", method, "attempt", attempt, "error", err)
+		if !sleep(ctx, retryDelay(attempt)) {
+			return ctx.Err()
+		}
+	}
+}
+
+// circuitOpenError is a call refused while the service's circuit is open
+type circuitOpenError struct {
+	service string
+	wait    time.Duration
+}
+
+func (e *circuitOpenError) Error() string {
+	return fmt.Sprintf("circuit of %s is open", e.service)
+}
+
+// Unwrap gives the error its code and client message
+func (e *circuitOpenError) Unwrap() error {
+	return apperrors.Catalog(apperrors.ReasonServiceUnavailable, e.service)
+}
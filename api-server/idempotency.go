@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// IdempotencyKeyHeader names a mutating request so retries of it are
+	// answered with the first response instead of being processed again
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks a replayed response
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKey bounds the key clients may send
+	maxIdempotencyKey = 255
+	// idempotencyLockTTL releases the key of a request whose gateway died
+	// before it was answered; it outlasts the slowest service timeout
+	idempotencyLockTTL = 2 * time.Minute
+)
+
+// unreplayedHeaders are set anew for every response; the CORS headers
+// depend on the retry's origin
+var unreplayedHeaders = []string{
+	"Content-Length", "Date", logger.RequestIDHeader, "Vary",
+	"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "Access-Control-Expose-Headers",
+}
+
+// Idempotency stores the first response to each Idempotency-Key in Redis,
+// shared by every gateway instance
+type Idempotency struct {
+	redis *database.RedisClient
+	ttl   time.Duration
+}
+
+// NewIdempotency keeps responses for ttl
+func NewIdempotency(redis *database.RedisClient, ttl time.Duration) *Idempotency {
+	return &Idempotency{redis: redis, ttl: ttl}
+}
+
+// storedResponse is a response kept under an Idempotency-Key. Status is 0
+// while the first request is still being processed.
+type storedResponse struct {
+	// Fingerprint is the request the key was first used for
+	Fingerprint string      `json:"fingerprint"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// responseRecorder copies the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyKey is the middleware of routes that create something. It
+// must run after AuthMiddleware, as keys are scoped to the user. Requests
+// without the header, and all requests while Redis is unavailable, are
+// processed as usual.
+func (g *Gateway) idempotencyKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if g.idempotency == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKey {
+			respondError(c, apperrors.Catalog(apperrors.ReasonIdempotencyKeyLength, maxIdempotencyKey))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, bodyError(err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.Request.URL.Path+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+		redisKey := "idempotency:" + userKey(c) + ":" + key
+
+		claimed, err := g.idempotency.claim(c.Request.Context(), redisKey, fingerprint)
+		if err != nil {
+			ginlog.From(c).Warn("Idempotency keys unavailable, processing request", "error", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			g.idempotency.replay(c, redisKey, fingerprint)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// the response is kept even when the client has gone, as its retry
+		// is what the key is for
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := g.idempotency.store(ctx, redisKey, fingerprint, recorder); err != nil {
+			ginlog.From(c).Warn("Failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+// claim reserves key for the request; false means it was used before
+func (i *Idempotency) claim(ctx context.Context, key, fingerprint string) (bool, error) {
+	pending, err := json.Marshal(storedResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return false, err
+	}
+	return i.redis.SetNX(ctx, key, pending, idempotencyLockTTL)
+}
+
+// store keeps the response for the key's TTL. Server errors release the key
+// instead, so the client can retry the request.
+func (i *Idempotency) store(ctx context.Context, key, fingerprint string, w *responseRecorder) error {
+	if w.Status() >= http.StatusInternalServerError {
+		return i.redis.Delete(ctx, key)
+	}
+
+	header := w.Header().Clone()
+	for _, name := range unreplayedHeaders {
+		header.Del(name)
+	}
+	stored, err := json.Marshal(storedResponse{
+		Fingerprint: fingerprint,
+		Status:      w.Status(),
+		Header:      header,
+		Body:        w.body.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+	return i.redis.SetWithExpiry(ctx, key, stored, i.ttl)
+}
+
+// replay answers a retry with the stored response. A key still being
+// processed gets 409, and a key reused for another request 400.
+func (i *Idempotency) replay(c *gin.Context, key, fingerprint string) {
+	raw, err := i.redis.GetString(c.Request.Context(), key)
+	if errors.Is(err, redis.Nil) {
+		// released by a failed first request in the meantime
+		respondError(c, apperrors.Catalog(apperrors.ReasonIdempotencyKeyInUse))
+		return
+	}
+	var stored storedResponse
+	if err == nil {
+		err = json.Unmarshal([]byte(raw), &stored)
+	}
+	if err != nil {
+		respondError(c, apperrors.Unavailable(err, "Failed to read the response for this Idempotency-Key"))
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		respondError(c, apperrors.Catalog(apperrors.ReasonIdempotencyKeyReused))
+	case stored.Status == 0:
+		c.Header("Retry-After", "1")
+		respondError(c, apperrors.Catalog(apperrors.ReasonIdempotencyKeyInUse))
+	default:
+		for name, values := range stored.Header {
+			c.Writer.Header()[name] = values
+		}
+		c.Header(idempotentReplayedHeader, "true")
+		c.Data(stored.Status, stored.Header.Get("Content-Type"), stored.Body)
+	}
+}
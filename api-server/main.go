@@ -2,208 +2,232 @@ package main
 
 import (
 	"context"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"strconv"
-	"time"
-
-	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"os"
+
+	"github.com/Reserve-to-save-backend/api-server/graph"
+	"github.com/Reserve-to-save-backend/pkg/config"
+	"github.com/Reserve-to-save-backend/pkg/cors"
+	"github.com/Reserve-to-save-backend/pkg/cors/gincors"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/diag"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/errreport/ginreport"
+	"github.com/Reserve-to-save-backend/pkg/featureflags"
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/pkg/metrics/ginmetrics"
+	"github.com/Reserve-to-save-backend/pkg/openapi/ginopenapi"
+	"github.com/Reserve-to-save-backend/pkg/server"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+	"github.com/Reserve-to-save-backend/pkg/tracing"
+	"github.com/Reserve-to-save-backend/pkg/tracing/gintrace"
 	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// APIServer는 REST API 서버입니다
-type APIServer struct {
-	queryClient query.QueryServiceClient
-}
-
-// NewAPIServer는 새로운 APIServer 인스턴스를 생성합니다
-func NewAPIServer(queryClient query.QueryServiceClient) *APIServer {
-	return &APIServer{
-		queryClient: queryClient,
+func main() {
+	// -openapi writes the spec and exits (make openapi, make sdk)
+	specOut := flag.String("openapi", "", "write the OpenAPI spec to this file and exit")
+	flag.Parse()
+	if *specOut != "" {
+		if err := writeSpec(*specOut); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
-}
 
-// GetCampaigns는 GET /query/campaigns 엔드포인트를 처리합니다
-func (s *APIServer) GetCampaigns(c *gin.Context) {
-	// 쿼리 파라미터 파싱
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	state, _ := strconv.Atoi(c.DefaultQuery("state", "0"))
+	// Load environment variables
+	envErr := godotenv.Load()
 
-	log.Printf("REST API called: limit=%d, offset=%d, state=%d", limit, offset, state)
+	// Load and validate configuration (env, CONFIG_FILE, defaults)
+	cfg := defaultConfig()
+	config.MustLoad(&cfg)
 
-	// gRPC 요청 생성
-	req := &query.GetCampaignsRequest{
-		Limit:  int32(limit),
-		Offset: int32(offset),
-		State:  int32(state),
+	// Structured logging (LOG_LEVEL, LOG_FORMAT)
+	logger.Init("api-gateway", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
 	}
 
-	// gRPC 호출 (5초 타임아웃)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	// Error reporting (Sentry when SENTRY_DSN is set)
+	if err := errreport.Init("api-gateway", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
+	}
+	defer errreport.Flush()
 
-	resp, err := s.queryClient.GetCampaigns(ctx, req)
+	// Tracing (spans are exported when OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.Init(context.Background(), "api-gateway", cfg.Tracing)
 	if err != nil {
-		log.Printf("gRPC call failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get campaigns",
-		})
-		return
+		logger.Fatal("Failed to initialize tracing", "error", err)
 	}
-
-	log.Printf("gRPC response: %d campaigns, total=%d", len(resp.Campaigns), resp.TotalCount)
-
-	// 응답 변환 (protobuf → JSON)
-	campaigns := make([]map[string]interface{}, len(resp.Campaigns))
-	for i, campaign := range resp.Campaigns {
-		campaigns[i] = map[string]interface{}{
-			"id":               campaign.Id,
-			"address":          campaign.Address,
-			"merchant_id":      campaign.MerchantId,
-			"merchant_name":    campaign.MerchantName,
-			"base_price":       campaign.BasePrice,
-			"min_qty":          campaign.MinQty,
-			"lock_start":       campaign.LockStart.AsTime().Format(time.RFC3339),
-			"lock_end":         campaign.LockEnd.AsTime().Format(time.RFC3339),
-			"rmax_bps":         campaign.RmaxBps,
-			"savefloor_bps":    campaign.SavefloorBps,
-			"merchant_fee_bps": campaign.MerchantFeeBps,
-			"ops_fee_bps":      campaign.OpsFeeBps,
-			"state":            campaign.State,
-			"metadata_uri":     campaign.MetadataUri,
-			"created_at":       campaign.CreatedAt.AsTime().Format(time.RFC3339),
+	defer shutdownTracing(context.Background())
+
+	// Redis backs local token validation, rate limiting, quotas, idempotency
+	// keys, the response cache and feature flags
+	var redis *database.RedisClient
+	if cfg.AuthValidation == AuthValidationLocal || cfg.RateLimitEnabled || cfg.QuotasEnabled || cfg.IdempotencyEnabled || cfg.ResponseCacheEnabled || cfg.FeatureFlagsEnabled {
+		redis, err = database.NewRedisClient(database.RedisConfigFromEnv())
+		if err != nil {
+			logger.Fatal("Failed to connect to Redis", "error", err)
 		}
+		defer redis.Close()
 	}
 
-	// JSON 응답
-	c.JSON(http.StatusOK, gin.H{
-		"campaigns":   campaigns,
-		"total_count": resp.TotalCount,
-		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-		},
-	})
-}
-
-// GetCampaign은 GET /query/campaigns/:id 엔드포인트를 처리합니다
-func (s *APIServer) GetCampaign(c *gin.Context) {
-	// 경로 파라미터 파싱
-	campaignIDStr := c.Param("id")
-	campaignID, err := strconv.ParseInt(campaignIDStr, 10, 64)
+	// Upstream services (GATEWAY_SERVICES, GATEWAY_SERVICES_FILE)
+	services, err := NewRegistry(cfg.Services, cfg.ServicesFile)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid campaign ID",
-		})
-		return
+		logger.Fatal("Failed to load gateway services", "error", err)
+	}
+	go services.Watch(cfg.ServicesReload)
+
+	// Create gateway (AUTH_VALIDATION=local validates tokens with auth-server's
+	// public keys and the logout blacklist in Redis)
+	var localAuth *LocalAuth
+	if cfg.AuthValidation == AuthValidationLocal {
+		localAuth = &LocalAuth{
+			Verifier:  jwks.NewVerifier(jwks.NewRemote(cfg.JWKS.URL, nil), nil),
+			Blacklist: redis,
+			Fallback:  cfg.AuthValidationFallback,
+		}
 	}
 
-	log.Printf("REST API called: campaign_id=%d", campaignID)
+	// Rate limits per route group (RATE_LIMIT_*), shared across instances
+	var limiter *RateLimiter
+	if cfg.RateLimitEnabled {
+		limits, err := cfg.RateLimits()
+		if err != nil {
+			logger.Fatal("Invalid rate limits", "error", err)
+		}
+		limiter = NewRateLimiter(redis, limits)
+	}
 
-	// gRPC 요청 생성
-	req := &query.GetCampaignRequest{
-		CampaignId: campaignID,
+	// Business quotas per user (QUOTA_*), overridable through the admin API
+	var quotas *Quotas
+	if cfg.QuotasEnabled {
+		limits, err := cfg.Quotas()
+		if err != nil {
+			logger.Fatal("Invalid quotas", "error", err)
+		}
+		quotas = NewQuotas(redis, limits)
 	}
 
-	// gRPC 호출
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	// Retried Idempotency-Key requests get the first response (IDEMPOTENCY_*)
+	var idempotency *Idempotency
+	if cfg.IdempotencyEnabled {
+		idempotency = NewIdempotency(redis, cfg.IdempotencyTTL)
+	}
+
+	// Campaign responses cached in Redis (RESPONSE_CACHE_*), busted by the
+	// campaign changes realtime-server relays
+	var responses *ResponseCache
+	if cfg.ResponseCacheEnabled {
+		responses = NewResponseCache(redis, cfg.ResponseCacheListTTL, cfg.ResponseCacheItemTTL)
+		go responses.Watch(context.Background())
+	}
 
-	resp, err := s.queryClient.GetCampaign(ctx, req)
+	// Maintenance and freeze flags toggled through the admin API
+	// (FEATURE_FLAGS_*)
+	var flags *featureflags.Flags
+	if cfg.FeatureFlagsEnabled {
+		flags = featureflags.New(redis.UniversalClient, featureflags.WithRefresh(cfg.FeatureFlagsRefresh))
+	}
+
+	// query-server is called over gRPC on QUERY_GRPC_CONNS connections, with
+	// the deadline, retries and circuit breaker of its registry entry
+	internal := svcauth.NewClient(cfg.Internal, nil)
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor(), metrics.UnaryClientInterceptor(), logger.UnaryClientInterceptor()),
+	}
+	if internal != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(internal.PerRPCCredentials()))
+	}
+	query, err := NewGRPCPool(services, "query", cfg.QueryConns, dialOpts...)
 	if err != nil {
-		log.Printf("gRPC call failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get campaign",
-		})
-		return
+		logger.Fatal("Failed to connect to query-server", "error", err)
 	}
+	defer query.Close()
 
-	if !resp.Found {
-		log.Printf("Campaign not found: %d", campaignID)
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Campaign not found",
-		})
-		return
+	var graphql http.Handler
+	if cfg.GraphQLEnabled {
+		graphql = graph.NewHandler(query, cfg.GraphQLComplexityLimit)
 	}
 
-	campaign := resp.Campaign
-	log.Printf("gRPC response: found campaign %s", campaign.Address)
-
-	// 응답 변환 (protobuf → JSON)
-	result := map[string]interface{}{
-		"id":               campaign.Id,
-		"address":          campaign.Address,
-		"merchant_id":      campaign.MerchantId,
-		"merchant_name":    campaign.MerchantName,
-		"base_price":       campaign.BasePrice,
-		"min_qty":          campaign.MinQty,
-		"lock_start":       campaign.LockStart.AsTime().Format(time.RFC3339),
-		"lock_end":         campaign.LockEnd.AsTime().Format(time.RFC3339),
-		"rmax_bps":         campaign.RmaxBps,
-		"savefloor_bps":    campaign.SavefloorBps,
-		"merchant_fee_bps": campaign.MerchantFeeBps,
-		"ops_fee_bps":      campaign.OpsFeeBps,
-		"state":            campaign.State,
-		"metadata_uri":     campaign.MetadataUri,
-		"created_at":       campaign.CreatedAt.AsTime().Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusOK, result)
-}
-
-// HealthCheck는 GET /health 엔드포인트를 처리합니다
-func (s *APIServer) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "ok",
-		"timestamp": time.Now().Format(time.RFC3339),
-	})
-}
+	gateway := NewGateway(services, localAuth, internal, limiter, idempotency, responses, flags, quotas, query, graphql)
+	if redis != nil {
+		gateway.AddDependency("redis", health.Redis(redis.UniversalClient))
+	}
 
-func main() {
-	// gRPC 클라이언트 연결
-	queryConn, err := grpc.NewClient(
-		"localhost:50051",
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	// Setup Gin router
+	router := gin.New()
+	// Client IPs for rate limiting come from X-Forwarded-For only behind
+	// TRUSTED_PROXIES
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
+	router.Use(gintrace.Middleware(), ginmetrics.Middleware(), ginlog.Middleware(), ginreport.Middleware(), LocaleMiddleware())
+
+	// Browser origins outside CORS_ALLOWED_ORIGINS get no CORS headers, and
+	// each path allows only the methods it is served with
+	policy, err := cors.New(cfg.CORS)
 	if err != nil {
-		log.Fatalf("Failed to connect to query-server: %v", err)
+		logger.Fatal("Invalid CORS configuration", "error", err)
+	}
+	router.Use(gincors.Middleware(policy, router))
+
+	// Oversized bodies are rejected before anything reads them
+	router.Use(BodyLimit())
+
+	// OpenAPI spec generated from the route annotations in openapi.go;
+	// OPENAPI_VALIDATE rejects requests that do not match it with 422
+	spec := apiSpec()
+	if cfg.OpenAPIValidate {
+		router.Use(ginopenapi.Validator(spec))
 	}
-	defer queryConn.Close()
 
-	queryClient := query.NewQueryServiceClient(queryConn)
-	log.Println("Connected to query-server via gRPC")
+	// Setup routes
+	gateway.SetupRoutes(router)
+	for _, route := range ginopenapi.Undocumented(spec, router.Routes(), "/api/") {
+		slog.Warn("Route missing from OpenAPI spec", "route", route)
+	}
 
-	// API 서버 생성
-	apiServer := NewAPIServer(queryClient)
+	// Runtime log level (GET/PUT {"level":"debug"})
+	ginlog.RegisterLevelEndpoint(router, "/admin/log-level")
 
-	// Gin 라우터 설정
-	router := gin.Default()
+	// Prometheus metrics (HTTP, Go runtime)
+	ginmetrics.Register(router)
 
-	// CORS 미들웨어 (필요시)
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
-			return
-		}
-		
-		c.Next()
-	})
+	// Serve Swagger documentation
+	router.Static("/api-docs", "./docs/swagger-ui")
+	router.GET("/swagger.json", ginopenapi.Handler(spec))
 
-	// 라우트 등록
-	router.GET("/health", apiServer.HealthCheck)
-	router.GET("/query/campaigns", apiServer.GetCampaigns)
-	router.GET("/query/campaigns/:id", apiServer.GetCampaign)
+	// Diagnostics (pprof, goroutine dumps, GC stats) on an internal listener
+	if err := diag.Start(cfg.GatewayDebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
+	}
 
-	// 서버 시작
-	log.Println("API server starting on :8081")
-	if err := router.Run(":8081"); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// Start server; SIGTERM drains in-flight requests before Redis and the
+	// query-server connections are closed.
+	// WebSocket and SSE streams would never drain, so they are ended at once.
+	ctx, stop := server.Context()
+	defer stop()
+	context.AfterFunc(ctx, gateway.CloseStreams)
+	port := cfg.GatewayPort
+	slog.Info("API Gateway starting", "port", port)
+	slog.Info("Swagger UI available", "url", "http://localhost:"+port+"/api-docs")
+	
+	if err := server.ServeHTTP(ctx, ":"+port, router, cfg.Server); err != nil {
+		logger.Fatal("Server failed", "error", err)
 	}
-} 
\ No newline at end of file
+	slog.Info("API Gateway stopped")
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package main
+
+import (
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/featureflags"
+	"github.com/Reserve-to-save-backend/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// killSwitch takes the routes it guards offline while flag is on, answering
+// 503 without reaching the services. Flags are toggled through the admin
+// API and reach every gateway within FEATURE_FLAGS_REFRESH. A message set on
+// the flag is shown in place of the catalogued one, untranslated.
+func (g *Gateway) killSwitch(flag string) gin.HandlerFunc {
+	reason := apperrors.ReasonFeatureFrozen
+	if flag == featureflags.Maintenance {
+		reason = apperrors.ReasonMaintenance
+	}
+	return func(c *gin.Context) {
+		if g.flags == nil {
+			c.Next()
+			return
+		}
+		f := g.flags.Get(c.Request.Context(), flag)
+		if !f.EnabledFor(flag, "") {
+			c.Next()
+			return
+		}
+
+		// not respondError: switched-off routes are not failures to report
+		err := apperrors.Catalog(reason)
+		status, body := apperrors.Response(err)
+		body["error"] = i18n.Localize(i18n.FromContext(c.Request.Context()), err.Code, err.Message)
+		if f.Message != "" {
+			body["error"] = f.Message
+		}
+		c.AbortWithStatusJSON(status, body)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMerchants는 GET /api/merchants 엔드포인트를 처리합니다
+func (s *QueryAPI) GetMerchants(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "limit", page.Limit, "offset", page.Offset)
+
+	resp, err := s.merchantClient.GetMerchants(c.Request.Context(), &query.GetMerchantsRequest{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	merchants := make([]map[string]interface{}, len(resp.Merchants))
+	for i, m := range resp.Merchants {
+		merchants[i] = merchantToMap(m)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"merchants":   merchants,
+		"total_count": resp.TotalCount,
+		"pagination":  page.Result(resp.TotalCount),
+	})
+}
+
+// GetMerchant는 GET /api/merchants/:id 엔드포인트를 처리합니다 (최근 정산 내역 포함)
+func (s *QueryAPI) GetMerchant(c *gin.Context) {
+	merchantID, err := parseID(c.Param("id"), "merchant")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "merchant_id", merchantID)
+
+	resp, err := s.merchantClient.GetMerchant(c.Request.Context(), &query.GetMerchantRequest{MerchantId: merchantID})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !resp.Found {
+		ginlog.From(c).Debug("merchant not found", "merchant_id", merchantID)
+		respondError(c, apperrors.Catalog(apperrors.ReasonMerchantNotFound))
+		return
+	}
+
+	settlements := make([]map[string]interface{}, len(resp.Settlements))
+	for i, st := range resp.Settlements {
+		settlements[i] = settlementToMap(st)
+	}
+	merchant := merchantToMap(resp.Merchant)
+	merchant["settlements"] = settlements
+	c.JSON(http.StatusOK, merchant)
+}
+
+// merchantToMap은 protobuf Merchant를 JSON 응답용 map으로 변환합니다
+func merchantToMap(m *query.Merchant) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                     m.Id,
+		"payout_wallet":          m.PayoutWallet,
+		"business_name":          m.BusinessName,
+		"status":                 m.Status,
+		"created_at":             m.CreatedAt.AsTime().Format(time.RFC3339),
+		"campaign_count":         m.CampaignCount,
+		"active_campaign_count":  m.ActiveCampaignCount,
+		"settled_campaign_count": m.SettledCampaignCount,
+		"total_volume":           m.TotalVolume,
+		"total_volume_label":     formatPrice(m.TotalVolume),
+		"logo_url":               m.LogoUrl,
+		"logo_thumbnail_url":     m.LogoThumbnailUrl,
+	}
+}
+
+// settlementToMap은 protobuf Settlement를 JSON 응답용 map으로 변환합니다
+func settlementToMap(st *query.Settlement) map[string]interface{} {
+	return map[string]interface{}{
+		"campaign_id":         st.CampaignId,
+		"campaign_address":    st.CampaignAddress,
+		"settled_at":          st.SettledAt.AsTime().Format(time.RFC3339),
+		"total_deposit":       st.TotalDeposit,
+		"total_deposit_label": formatPrice(st.TotalDeposit),
+		"total_rebate":        st.TotalRebate,
+		"total_rebate_label":  formatPrice(st.TotalRebate),
+		"participant_count":   st.ParticipantCount,
+	}
+}
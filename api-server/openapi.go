@@ -0,0 +1,896 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	"github.com/Reserve-to-save-backend/pkg/featureflags"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/openapi"
+)
+
+// apiVersion is reported in the generated spec
+const apiVersion = "1.0.0"
+
+func init() {
+	openapi.RegisterType(models.BigInt{}, openapi.Schema{
+		Type:        openapi.TypeString,
+		Pattern:     "^[0-9]+$",
+		Description: "Integer amount in the currency's smallest unit",
+	})
+	openapi.RegisterType(models.JSONB{}, openapi.Schema{Type: openapi.TypeObject})
+	openapi.RegisterType(audit.Snapshot{}, openapi.Schema{Type: openapi.TypeObject, Nullable: true})
+}
+
+// Request shapes of the upstream handlers. Keep them in step with the
+// binding structs there; the router-side drift check only covers routes.
+
+type pageQuery struct {
+	Limit  int    `form:"limit" binding:"min=1,max=100" doc:"Page size, 20 by default"`
+	Offset int    `form:"offset" binding:"min=0"`
+	Cursor string `form:"cursor" doc:"next_cursor of the previous page; takes precedence over offset"`
+}
+
+type realtimeQuery struct {
+	AccessToken string `form:"access_token" doc:"Access token, for browsers that cannot send the Authorization header"`
+}
+
+type nonceQuery struct {
+	Address      string `form:"address" binding:"required"`
+	ChainID      string `form:"chainId" doc:"Defaults to 1001 (Kairos)"`
+	Challenge    string `form:"challenge" doc:"Proof-of-work challenge from /api/auth/nonce/challenge"`
+	Solution     string `form:"solution" doc:"String whose sha256(challenge + \":\" + solution) starts with difficulty zero bits"`
+	CaptchaToken string `form:"captchaToken" doc:"Turnstile response token"`
+}
+
+type verifyRequest struct {
+	Address      string `json:"address" binding:"required"`
+	Signature    string `json:"signature" binding:"required" doc:"personal_sign of message; contract wallets are checked with EIP-1271"`
+	Message      string `json:"message" binding:"required"`
+	RequestID    string `json:"requestId" binding:"required"`
+	ReferralCode string `json:"referralCode" doc:"Referral code a new user signs up with; a code that cannot be applied does not fail the sign-in"`
+}
+
+type lineAuthRequest struct {
+	IDToken     string `json:"idToken" binding:"required"`
+	AccessToken string `json:"accessToken" binding:"required"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" binding:"required,min=6,max=6"`
+}
+
+type emailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type emailVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type recoveryCompleteRequest struct {
+	Token       string `json:"token" binding:"required"`
+	LineIDToken string `json:"lineIdToken" binding:"required"`
+	Address     string `json:"address" binding:"required"`
+	Signature   string `json:"signature" binding:"required"`
+	Message     string `json:"message" binding:"required"`
+}
+
+// auth-server answers beside "success" rather than under "data", so the
+// auth routes below are Flat
+
+type nonceResponse struct {
+	Nonce     string `json:"nonce"`
+	Message   string `json:"message" doc:"Text to sign with the wallet (personal_sign)"`
+	RequestID string `json:"requestId" doc:"Passed back to /api/auth/verify"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+type nonceChallenge struct {
+	Mode       string     `json:"mode" doc:"off, pow or turnstile"`
+	Challenge  string     `json:"challenge,omitempty"`
+	Difficulty int        `json:"difficulty,omitempty" doc:"Leading zero bits the solution's hash needs"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+type nonceChallengeResponse struct {
+	Challenge nonceChallenge `json:"challenge"`
+}
+
+type walletUser struct {
+	ID            string `json:"id" binding:"uuid"`
+	Address       string `json:"address"`
+	KYCTier       int    `json:"kycTier"`
+	LineConnected bool   `json:"lineConnected"`
+}
+
+type signInResponse struct {
+	AccessToken  string     `json:"accessToken"`
+	RefreshToken string     `json:"refreshToken"`
+	User         walletUser `json:"user"`
+}
+
+type lineUser struct {
+	ID              string  `json:"id" binding:"uuid"`
+	LineUserID      *string `json:"lineUserId"`
+	DisplayName     *string `json:"displayName"`
+	PictureURL      *string `json:"pictureUrl"`
+	WalletConnected bool    `json:"walletConnected"`
+	KYCTier         int     `json:"kycTier"`
+}
+
+type lineAuthResponse struct {
+	Token string   `json:"token"`
+	User  lineUser `json:"user"`
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"accessToken"`
+}
+
+type sessionInfo struct {
+	ID                string    `json:"id" binding:"uuid"`
+	IPAddress         *string   `json:"ipAddress,omitempty"`
+	UserAgent         *string   `json:"userAgent,omitempty"`
+	DeviceFingerprint *string   `json:"deviceFingerprint,omitempty"`
+	Country           *string   `json:"country,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	LastUsedAt        time.Time `json:"lastUsedAt"`
+	Current           bool      `json:"current" doc:"The session of the token making the request"`
+}
+
+type sessionsResponse struct {
+	Sessions []sessionInfo `json:"sessions"`
+}
+
+type kycApplication struct {
+	ID            string     `json:"id" binding:"uuid"`
+	UserID        string     `json:"user_id" binding:"uuid"`
+	RequestedTier int        `json:"requested_tier"`
+	DocumentType  string     `json:"document_type" binding:"oneof=passport id_card driving_license residence_permit"`
+	Status        string     `json:"status" binding:"oneof=pending approved rejected"`
+	RejectReason  *string    `json:"reject_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+}
+
+type kycApplicationResponse struct {
+	Application kycApplication `json:"application"`
+}
+
+type kycStatusResponse struct {
+	KYCTier     int             `json:"kycTier"`
+	Application *kycApplication `json:"application" doc:"Latest application; null if none"`
+}
+
+type campaignListQuery struct {
+	pageQuery
+	Status     string     `form:"status" binding:"oneof=draft recruiting reached fulfillment settled failed cancelled paused" doc:"Campaign status; repeat to list several, omit to list every status"`
+	MerchantID string     `form:"merchantId" binding:"uuid"`
+	LockFrom   *time.Time `form:"lockFrom" doc:"Only campaigns ending at or after this time"`
+	LockTo     *time.Time `form:"lockTo" doc:"Only campaigns starting at or before this time"`
+	MinPrice   string     `form:"minPrice" doc:"Lowest base price, as a decimal amount"`
+	MaxPrice   string     `form:"maxPrice" doc:"Highest base price, as a decimal amount"`
+	Q          string     `form:"q" doc:"Part of the title or metadata, matched case-insensitively"`
+	Category   string     `form:"category" doc:"Category slug, as listed by GET /api/categories"`
+	Tag        string     `form:"tag" doc:"Tag; repeat to list campaigns carrying every one"`
+	Sort       string     `form:"sort" binding:"oneof=newest ending_soon most_funded category" doc:"newest by default; ending_soon lists ended campaigns last; category groups campaigns by category in tab order, newest first within one"`
+	Count      *bool      `form:"count" doc:"false skips counting the matches, making the page faster; total_count is then -1"`
+}
+
+type campaignSearchQuery struct {
+	pageQuery
+	Q      string `form:"q" binding:"required,min=1,max=200" doc:"Words to find in titles, descriptions and merchant names; each matches as a prefix"`
+	Status string `form:"status" binding:"oneof=draft recruiting reached fulfillment settled failed cancelled paused" doc:"Campaign status; repeat to search several, omit to search every status"`
+}
+
+type trendingQuery struct {
+	Limit int `form:"limit" binding:"min=1" doc:"10 by default and at most 50"`
+	Hours int `form:"hours" binding:"min=1" doc:"Hours of joins counted, 24 by default and at most 168"`
+}
+
+type recommendedQuery struct {
+	Limit int `form:"limit" binding:"min=1" doc:"10 by default and at most 50"`
+}
+
+type favoritesQuery struct {
+	pageQuery
+	Status string `form:"status" binding:"oneof=draft recruiting reached fulfillment settled failed cancelled paused" doc:"Campaign status; repeat to list several, omit to list every status"`
+}
+
+type campaignStatsQuery struct {
+	Days int `form:"days" binding:"min=1" doc:"Days of funding history, 30 by default and at most 180"`
+}
+
+type createCampaignRequest struct {
+	Title          string        `json:"title" binding:"required"`
+	Description    *string       `json:"description"`
+	ImageID        *string       `json:"imageId" binding:"uuid" doc:"A completed campaign_image upload"`
+	Category       *string       `json:"category" doc:"Category slug"`
+	Tags           []string      `json:"tags" doc:"At most 10 tags of up to 32 characters, stored lowercase"`
+	MerchantID     *string       `json:"merchantId" binding:"uuid"`
+	MerchantWallet string        `json:"merchantWallet" binding:"required"`
+	BasePrice      models.BigInt `json:"basePrice" binding:"required"`
+	MinQty         int           `json:"minQty" binding:"required,min=1"`
+	DiscountRate   int           `json:"discountRate" doc:"Basis points"`
+	SaveFloorBps   int           `json:"saveFloorBps"`
+	RMaxBps        int           `json:"rMaxBps"`
+	StartTime      time.Time     `json:"startTime" binding:"required"`
+	EndTime        time.Time     `json:"endTime" binding:"required"`
+	Metadata       models.JSONB  `json:"metadata"`
+
+	CancelDeadline       *time.Time `json:"cancelDeadline" doc:"End of free cancellation, at most endTime; endTime when omitted"`
+	LateCancelPenaltyBps *int       `json:"lateCancelPenaltyBps" doc:"Penalty in basis points on cancels after cancelDeadline; without it they are refused"`
+}
+
+type relaunchRequest struct {
+	Title          *string    `json:"title" doc:"Replaces the copied title"`
+	StartTime      time.Time  `json:"startTime" binding:"required"`
+	EndTime        time.Time  `json:"endTime" binding:"required" doc:"Must be in the future"`
+	CancelDeadline *time.Time `json:"cancelDeadline" doc:"End of free cancellation; by default as long before endTime as in the source"`
+}
+
+type templateRequest struct {
+	Name                 string        `json:"name" binding:"required,max=100" doc:"Unique among the merchant's templates"`
+	CampaignID           *string       `json:"campaignId" binding:"uuid" doc:"Save this campaign's terms and imagery; the fields below are then ignored. Create only"`
+	MerchantID           *string       `json:"merchantId" binding:"uuid" doc:"Whose template ops create; merchants always create their own"`
+	Title                string        `json:"title"`
+	Description          *string       `json:"description"`
+	ImageID              *string       `json:"imageId" binding:"uuid" doc:"A completed campaign_image upload; the image is kept when omitted"`
+	Category             *string       `json:"category" doc:"Category slug; kept when omitted, removed when empty"`
+	Tags                 []string      `json:"tags" doc:"Kept when omitted"`
+	MerchantWallet       string        `json:"merchantWallet"`
+	BasePrice            models.BigInt `json:"basePrice"`
+	MinQty               int           `json:"minQty" binding:"min=1"`
+	DiscountRate         int           `json:"discountRate" doc:"Basis points"`
+	SaveFloorBps         int           `json:"saveFloorBps"`
+	RMaxBps              int           `json:"rMaxBps"`
+	Metadata             models.JSONB  `json:"metadata"`
+	CancelLeadSeconds    *int64        `json:"cancelLeadSeconds" binding:"min=0" doc:"Free cancellation of launched campaigns closes this long before their endTime"`
+	LateCancelPenaltyBps *int          `json:"lateCancelPenaltyBps"`
+}
+
+type policyRequest struct {
+	MaxRMaxBps   *int `json:"maxRMaxBps" binding:"min=0,max=10000" doc:"Highest rMaxBps a campaign may have"`
+	MinLockHours *int `json:"minLockHours" binding:"min=0" doc:"Shortest time from startTime to endTime, in hours"`
+	MinFeeBps    *int `json:"minFeeBps" binding:"min=0,max=10000" doc:"Lowest merchant fee the merchant may have agreed to"`
+	MaxFeeBps    *int `json:"maxFeeBps" binding:"min=0,max=10000" doc:"Highest merchant fee the merchant may have agreed to"`
+}
+
+type templateListQuery struct {
+	pageQuery
+	MerchantID string `form:"merchantId" binding:"uuid" doc:"Ops only; merchants always list their own"`
+}
+
+type updateCampaignRequest struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	ImageID      *string    `json:"imageId" binding:"uuid" doc:"A completed campaign_image upload"`
+	Category     *string    `json:"category" doc:"Category slug; empty removes the campaign from its category"`
+	Tags         []string   `json:"tags" doc:"Replaces the campaign's tags"`
+	StartTime    *time.Time `json:"startTime"`
+	EndTime      *time.Time `json:"endTime"`
+	Status       *string    `json:"status" binding:"oneof=pending_review draft fulfillment failed cancelled" doc:"Requested status change; merchants may submit a draft for review, take a campaign that is not deployed back to draft or cancel it, and start fulfillment of a reached campaign, ops may also fail or cancel running ones"`
+	StatusReason string     `json:"statusReason" doc:"Recorded in the campaign's status history"`
+	Version      *int64     `json:"version" doc:"The campaign version the edit is based on; an If-Match header takes precedence"`
+
+	CancelDeadline       *time.Time `json:"cancelDeadline" doc:"Only while the campaign is a draft"`
+	LateCancelPenaltyBps *int       `json:"lateCancelPenaltyBps" doc:"Only while the campaign is a draft"`
+}
+
+type versionBody struct {
+	Version *int64 `json:"version" doc:"The campaign version the request is based on; an If-Match header takes precedence"`
+}
+
+type reviewCampaignRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Comment  string `json:"comment" doc:"Shown to the merchant; required when rejecting"`
+	Version  *int64 `json:"version" doc:"The campaign version the review is based on; an If-Match header takes precedence"`
+}
+
+type reviewCampaignResponse struct {
+	Campaign models.Campaign       `json:"campaign"`
+	Review   models.CampaignReview `json:"review"`
+}
+
+type deploymentRequest struct {
+	ChainAddress string `json:"chainAddress" binding:"required" doc:"Address of the deployed campaign contract"`
+	TxHash       string `json:"txHash" binding:"required"`
+	BlockNumber  int64  `json:"blockNumber" binding:"required,min=1"`
+	Version      *int64 `json:"version" doc:"The campaign version the request is based on; an If-Match header takes precedence"`
+}
+
+type markFulfilledRequest struct {
+	ParticipationIDs []string `json:"participationIds" doc:"Participations to mark; omit to mark every active one"`
+	ProofHash        string   `json:"proofHash" binding:"required" doc:"0x-prefixed SHA-256 of the receipt or photo"`
+	ProofImageID     *string  `json:"proofImageId" binding:"uuid" doc:"A completed fulfillment_proof upload of the photo"`
+	Note             *string  `json:"note" binding:"max=1000"`
+}
+
+type fulfillmentListQuery struct {
+	pageQuery
+	Status string `form:"status" binding:"oneof=fulfilled confirmed disputed"`
+}
+
+type disputeRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" doc:"Shown to the merchant and ops"`
+}
+
+type openDisputeRequest struct {
+	ParticipationID string `json:"participationId" binding:"required,uuid"`
+	Kind            string `json:"kind" binding:"required,oneof=fulfillment settlement" doc:"fulfillment disputes the fulfillment of the order, settlement the settlement of the deposit"`
+	Reason          string `json:"reason" binding:"required,max=1000" doc:"Shown to the merchant and ops"`
+}
+
+type resolveDisputeRequest struct {
+	Resolution string         `json:"resolution" binding:"required,oneof=refund reject"`
+	Amount     *models.BigInt `json:"amount" doc:"Refund in base units, at most the deposit; required to refund"`
+	Method     string         `json:"method" binding:"oneof=payment onchain" doc:"payment refunds the participant's payment through the provider, onchain by a transaction from the campaign contract; required to refund"`
+	Note       *string        `json:"note" binding:"max=1000" doc:"Shown to the participant"`
+}
+
+type voucherCodeRequest struct {
+	Code string `json:"code" binding:"required,max=64" doc:"The code as typed, in any case and with or without dashes, or the scanned QR payload"`
+}
+
+type recordRefundRequest struct {
+	TxHash string `json:"txHash" binding:"required" doc:"0x-prefixed hash of the mined refund transaction"`
+}
+
+type createUploadRequest struct {
+	Purpose     string `json:"purpose" binding:"required,oneof=campaign_image merchant_logo fulfillment_proof"`
+	ContentType string `json:"contentType" binding:"required,oneof=image/jpeg image/png"`
+	Size        int64  `json:"size" binding:"required,min=1" doc:"Bytes, 5 MB at most by default"`
+}
+
+type uploadTicket struct {
+	Upload  models.MediaUpload `json:"upload"`
+	Method  string             `json:"method" doc:"Always PUT"`
+	URL     string             `json:"url" doc:"Pre-signed URL to upload the file to, until upload.expires_at"`
+	Headers map[string]string  `json:"headers" doc:"Headers to send with the file"`
+}
+
+type createCategoryRequest struct {
+	Slug     string `json:"slug" binding:"required" doc:"Permanent id, lowercase letters and digits joined by hyphens"`
+	Name     string `json:"name" binding:"required"`
+	Position int    `json:"position" doc:"Tabs are ordered by position"`
+}
+
+type updateCategoryRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Position int    `json:"position"`
+}
+
+type createPaymentRequest struct {
+	PaymentID       string        `json:"paymentId"`
+	CampaignID      *string       `json:"campaignId" binding:"uuid"`
+	UserID          *string       `json:"userId" binding:"uuid"`
+	ParticipationID *string       `json:"participationId" binding:"uuid"`
+	Amount          models.BigInt `json:"amount" binding:"required"`
+	Currency        string        `json:"currency" binding:"required,oneof=USDT KAIA KRW USD"`
+	Mode            string        `json:"mode" binding:"required,oneof=crypto stripe"`
+	TransactionHash *string       `json:"transactionHash"`
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]interface{}   `json:"data"`
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
+
+type joinTxRequest struct {
+	UserAddress     string `json:"userAddress" binding:"required"`
+	CampaignAddress string `json:"campaignAddress" binding:"required"`
+	Amount          string `json:"amount" binding:"required"`
+}
+
+type deployCampaignTxRequest struct {
+	MerchantAddress  string `json:"merchantAddress" binding:"required"`
+	Title            string `json:"title" binding:"required"`
+	Description      string `json:"description"`
+	ImageURL         string `json:"imageUrl"`
+	TargetAmount     string `json:"targetAmount" binding:"required" doc:"USDT base units"`
+	MinDeposit       string `json:"minDeposit" binding:"required" doc:"USDT base units"`
+	MaxDeposit       string `json:"maxDeposit" binding:"required" doc:"USDT base units"`
+	DiscountRate     int    `json:"discountRate" doc:"Basis points"`
+	Duration         int64  `json:"duration" binding:"required,min=1" doc:"Seconds of recruiting"`
+	SettlementPeriod int64  `json:"settlementPeriod" binding:"required,min=1" doc:"Seconds from the end of recruiting to settlement"`
+}
+
+type cancelTxRequest struct {
+	UserAddress     string `json:"userAddress" binding:"required"`
+	CampaignAddress string `json:"campaignAddress" binding:"required"`
+}
+
+type settleCampaignTxRequest struct {
+	OperatorAddress string `json:"operatorAddress" binding:"required"`
+	CampaignID      string `json:"campaignId" binding:"required,uuid"`
+}
+
+type refundTxRequest struct {
+	OperatorAddress string `json:"operatorAddress" binding:"required"`
+	DisputeID       string `json:"disputeId" binding:"required,uuid"`
+}
+
+type cancelRequestBody struct {
+	Amount  *models.BigInt `json:"amount" doc:"Part of the deposit to cancel, a multiple of the base price; all of it when omitted"`
+	Version *int64         `json:"version" doc:"The participation version the request is based on; an If-Match header takes precedence"`
+}
+
+type cancelQuote struct {
+	Amount     models.BigInt `json:"amount"`
+	PenaltyBps int           `json:"penaltyBps"`
+	Penalty    models.BigInt `json:"penalty" doc:"Kept from the cancelled amount"`
+	Refund     models.BigInt `json:"refund" doc:"Returned to the participant"`
+	FreeUntil  time.Time     `json:"freeUntil" doc:"When free cancellation of the campaign ends"`
+}
+
+type cancelRequestResponse struct {
+	Participation models.Participation `json:"participation"`
+	Quote         cancelQuote          `json:"quote"`
+}
+
+type registerDeviceRequest struct {
+	Token    string `json:"token" binding:"required,max=4096"`
+	Platform string `json:"platform" binding:"required,oneof=web ios android"`
+}
+
+type preferencesRequest struct {
+	CampaignMilestones *bool `json:"campaign_milestones"`
+	RebatePayouts      *bool `json:"rebate_payouts"`
+	Watchlist          *bool `json:"watchlist" doc:"Pushes about favorited campaigns nearing their goal or closing"`
+	Cancellations      *bool `json:"cancellations" doc:"LINE messages and emails confirming my cancellations"`
+	Line               *bool `json:"line" doc:"LINE messages to my connected LINE account"`
+	Email              *bool `json:"email" doc:"Emails to my verified address"`
+}
+
+type applyReferralRequest struct {
+	Code string `json:"code" binding:"required" doc:"Another user's referral code, case-insensitive"`
+}
+
+type registerMerchantRequest struct {
+	BusinessName       string  `json:"businessName" binding:"required"`
+	RegistrationNumber *string `json:"registrationNumber" doc:"Company registration number"`
+	ContactEmail       string  `json:"contactEmail" binding:"required,email"`
+	ContactPhone       *string `json:"contactPhone"`
+	Website            *string `json:"website"`
+	Country            string  `json:"country" binding:"required,len=2" doc:"ISO 3166-1 alpha-2 code"`
+	PayoutWallet       string  `json:"payoutWallet" binding:"required" doc:"Address settlements are paid to"`
+	AcceptedFeeBps     int     `json:"acceptedFeeBps" binding:"required" doc:"The merchant fee the applicant agreed to; must be the current fee (250)"`
+}
+
+type adminUserQuery struct {
+	pageQuery
+	Q      string `form:"q" doc:"Wallet address, or part of an email or LINE name"`
+	Status string `form:"status" binding:"oneof=active suspended"`
+	Role   string `form:"role" binding:"oneof=user merchant ops admin"`
+}
+
+type merchantDashboardQuery struct {
+	From time.Time `form:"from" doc:"Inclusive; upcoming settlements by end time, settled campaigns by settlement date"`
+	To   time.Time `form:"to" doc:"Exclusive"`
+}
+
+type adminMerchantQuery struct {
+	pageQuery
+	Q string `form:"q" doc:"Merchant wallet address"`
+}
+
+type adminMerchantRegistrationQuery struct {
+	pageQuery
+	Q      string `form:"q" doc:"Payout wallet address, or part of the business name"`
+	Status string `form:"status" binding:"oneof=pending approved rejected suspended"`
+}
+
+type merchantStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected suspended"`
+	Reason string `json:"reason" doc:"Required to reject or suspend"`
+	FeeBps *int   `json:"feeBps" binding:"min=0,max=10000" doc:"Overrides the agreed fee; only when approving"`
+}
+
+type adminCampaignQuery struct {
+	pageQuery
+	Q          string `form:"q" doc:"Part of the title, or a campaign or merchant address"`
+	Status     string `form:"status" doc:"pending_review lists the campaigns awaiting review"`
+	MerchantID string `form:"merchantId" binding:"uuid"`
+}
+
+type adminDisputeQuery struct {
+	pageQuery
+	Status     string `form:"status" binding:"oneof=open refunding refunded rejected redelivered"`
+	Kind       string `form:"kind" binding:"oneof=fulfillment settlement"`
+	CampaignID string `form:"campaignId" binding:"uuid"`
+	UserID     string `form:"userId" binding:"uuid"`
+	Overdue    bool   `form:"overdue" doc:"Only pending disputes past their SLA, the most overdue first"`
+}
+
+type adminPaymentQuery struct {
+	pageQuery
+	Status     string `form:"status"`
+	Mode       string `form:"mode" binding:"oneof=crypto stripe"`
+	CampaignID string `form:"campaignId" binding:"uuid"`
+	UserID     string `form:"userId" binding:"uuid"`
+}
+
+type auditQuery struct {
+	pageQuery
+	ActorID      string    `form:"actorId"`
+	ActorType    string    `form:"actorType" binding:"oneof=user system service"`
+	Action       string    `form:"action"`
+	ResourceType string    `form:"resourceType"`
+	ResourceID   string    `form:"resourceId"`
+	From         time.Time `form:"from" doc:"Inclusive"`
+	To           time.Time `form:"to" doc:"Exclusive"`
+}
+
+type bulkRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1,max=100"`
+	Reason string   `json:"reason" doc:"Required to suspend or pause"`
+}
+
+type roleRequest struct {
+	IDs    []string `json:"ids" binding:"required,min=1,max=100"`
+	Role   string   `json:"role" binding:"required,oneof=user merchant ops admin"`
+	Reason string   `json:"reason" binding:"required"`
+}
+
+// apiSpec documents the public /api routes registered in SetupRoutes.
+// main warns at startup about routes missing from it.
+func apiSpec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Reserve to Save API",
+		Version:     apiVersion,
+		Description: "Public API of the R2S gateway. Errors share one shape; see components.schemas.Error. Branch on its reason (such as R2S-2004), which names the failure across services, rather than on the localized message. Requests that do not match this spec get 422 VALIDATION_FAILED with the invalid fields in details, and bodies over 1 MiB (KYC uploads: 41 MiB) get 413. Requests are rate limited per client IP, and authenticated requests also per user; limited requests get 429 with Retry-After in seconds. Requests that create something accept an Idempotency-Key header (at most 255 characters); a retry with the same key and body gets the first response again, marked Idempotent-Replayed: true, a retry while the first is still processed gets 409, and reusing the key for another request gets 400.",
+	})
+
+	// Auth
+	auth := []string{"Auth"}
+	doc.Add("GET", "/api/auth/nonce/challenge", openapi.Route{
+		Summary:     "Get the challenge to pass before requesting a nonce",
+		Description: "With mode pow, find a solution for the challenge before it expires and send both to /api/auth/nonce; each challenge buys one nonce. With mode turnstile, send the Turnstile widget's token as captchaToken. With mode off nothing is required.",
+		Tags:        auth, Response: nonceChallengeResponse{}, Flat: true,
+	})
+	doc.Add("GET", "/api/auth/nonce", openapi.Route{
+		Summary:     "Get a sign-in nonce for a wallet",
+		Description: "When a nonce challenge is configured, requests without a passing challenge, solution or captchaToken get 403.",
+		Tags:        auth, Query: nonceQuery{}, Response: nonceResponse{}, Flat: true,
+	})
+	doc.Add("POST", "/api/auth/verify", openapi.Route{
+		Summary:     "Sign in with a wallet signature",
+		Description: "Sign-in requests are rate limited per IP and wallet, and repeated signature failures lock both out for a growing period. Limited requests get 429 with Retry-After. Send the device fingerprint in X-Device-Fingerprint; the session is bound to it and to the client's country.",
+		Tags:        auth, Body: verifyRequest{}, Response: signInResponse{}, Flat: true,
+	})
+	doc.Add("POST", "/api/auth/line", openapi.Route{Summary: "Sign in with LINE", Tags: auth, Body: lineAuthRequest{}, Response: lineAuthResponse{}, Flat: true})
+	doc.Add("POST", "/api/auth/refresh", openapi.Route{
+		Summary:     "Exchange a refresh token",
+		Description: "Send the same X-Device-Fingerprint as at sign-in. A refresh from another device or country ends the session with 401, notifies the user's other connections, and requires signing in again.",
+		Tags:        auth, Body: refreshRequest{}, Response: accessTokenResponse{}, Flat: true,
+	})
+	doc.Add("POST", "/api/auth/logout", openapi.Route{Summary: "End the session", Tags: auth, Auth: true})
+	doc.Add("GET", "/api/auth/.well-known/jwks.json", openapi.Route{Summary: "Public keys access tokens are signed with", Tags: auth, Response: jwks.Set{}, Flat: true})
+	doc.Add("GET", "/api/auth/sessions", openapi.Route{Summary: "List the signed-in devices", Tags: auth, Auth: true, Response: sessionsResponse{}, Flat: true})
+	doc.Add("DELETE", "/api/auth/sessions/:id", openapi.Route{
+		Summary:     "Sign out a device",
+		Description: "Its refresh token stops working at once and its access token is revoked.",
+		Tags:        auth, Auth: true,
+	})
+	doc.Add("POST", "/api/auth/kyc/applications", openapi.Route{
+		Summary:     "Apply for a higher KYC tier",
+		Description: "A multipart/form-data upload with tier (1-3), documentType (passport, id_card, driving_license or residence_permit) and one to four documents files, each a JPEG, PNG or PDF of at most 10 MB. The KYC provider's review raises kyc_tier in access tokens issued afterwards.",
+		Tags:        auth, Auth: true, Response: kycApplicationResponse{}, Flat: true, Status: 201,
+	})
+	doc.Add("GET", "/api/auth/kyc/status", openapi.Route{Summary: "Get the KYC tier and latest application", Tags: auth, Auth: true, Response: kycStatusResponse{}, Flat: true})
+	doc.Add("POST", "/api/auth/mfa/setup", openapi.Route{Summary: "Start TOTP enrollment", Tags: auth, Auth: true})
+	doc.Add("POST", "/api/auth/mfa/enable", openapi.Route{Summary: "Confirm TOTP enrollment", Tags: auth, Auth: true, Body: mfaCodeRequest{}})
+	doc.Add("POST", "/api/auth/mfa/verify", openapi.Route{
+		Summary:     "Pass the MFA challenge",
+		Description: "Returns an access token carrying the MFA claim, which the admin API requires.",
+		Tags:        auth, Auth: true, Body: mfaCodeRequest{}, Response: accessTokenResponse{}, Flat: true,
+	})
+	doc.Add("POST", "/api/auth/email", openapi.Route{
+		Summary:     "Set the account email",
+		Description: "The email is stored unverified and a verification link valid for 24 hours is mailed to it.",
+		Tags:        auth, Auth: true, Body: emailRequest{}, Status: 202,
+	})
+	doc.Add("POST", "/api/auth/email/verify", openapi.Route{Summary: "Verify the email with the token from the link", Tags: auth, Body: emailVerifyRequest{}})
+	doc.Add("POST", "/api/auth/recovery/start", openapi.Route{
+		Summary:     "Request an account recovery code",
+		Description: "Mails a recovery code valid for 30 minutes if the email is verified on an account with LINE linked. The answer is the same either way. Limited to 3 requests per email and hour.",
+		Tags:        auth, Body: emailRequest{}, Status: 202,
+	})
+	doc.Add("POST", "/api/auth/recovery/complete", openapi.Route{
+		Summary:     "Re-link the account to a new wallet",
+		Description: "Takes the emailed recovery code, a LINE ID token of the account's linked LINE user, and the new wallet's signature over a nonce from /api/auth/nonce. All sessions end; sign in with the new wallet afterwards.",
+		Tags:        auth, Body: recoveryCompleteRequest{},
+	})
+
+	doc.Add("GET", "/api/realtime/ws", openapi.Route{
+		Summary:     "Subscribe to real-time updates",
+		Description: "A WebSocket upgrade; campaign progress and notifications arrive as JSON messages. Authenticate with the Authorization header or access_token. Any route also streams Server-Sent Events when the service offers them and the request accepts text/event-stream.",
+		Tags:        []string{"Realtime"}, Query: realtimeQuery{}, Status: 101,
+	})
+
+	// Campaigns
+	campaigns := []string{"Campaigns"}
+	const cachedNote = "Cached briefly by the gateway. Responses carry an ETag; send it in If-None-Match to get 304 while the response is unchanged."
+	const relaunchNote = "Like any draft it goes through review before deployment. Accepts an Idempotency-Key header."
+	doc.Add("GET", "/api/campaigns", openapi.Route{Summary: "List campaigns", Description: cachedNote, Tags: campaigns, Auth: true, Query: campaignListQuery{}, Paged: true})
+	doc.Add("GET", "/api/campaigns/search", openapi.Route{Summary: "Search campaigns", Description: "Most relevant first; campaigns carry rank and title and description snippets with the matched words in <mark>. " + cachedNote, Tags: campaigns, Auth: true, Query: campaignSearchQuery{}, Paged: true})
+	doc.Add("GET", "/api/campaigns/trending", openapi.Route{Summary: "List trending campaigns", Description: "Recruiting campaigns by recent join velocity: each join in the window counts from 0 at its start to 1 now, cancelled and refunded ones not at all. Campaigns carry recent_joins and trending_score. " + cachedNote, Tags: campaigns, Auth: true, Query: trendingQuery{}})
+	doc.Add("GET", "/api/campaigns/recommended", openapi.Route{Summary: "List campaigns recommended to me", Description: "Recruiting campaigns I have not joined, scored by my past joins of their merchant and of their category, then filled with trending and newest ones. Campaigns carry a reason: merchant, category, trending or new.", Tags: campaigns, Auth: true, Query: recommendedQuery{}})
+	doc.Add("GET", "/api/categories", openapi.Route{Summary: "List campaign categories", Description: "In tab order, with the number of active campaigns in each. " + cachedNote, Tags: campaigns, Auth: true})
+	doc.Add("GET", "/api/campaigns/:id", openapi.Route{Summary: "Get a campaign", Description: cachedNote, Tags: campaigns, Auth: true})
+	doc.Add("GET", "/api/campaigns/:id/stats", openapi.Route{Summary: "Get a campaign's participation stats", Description: "Participant count, average deposit, cancellation rate, projected rebate per participant between the save floor and maximum rebate rates, and the daily funding history, as aggregated by the batch server every few minutes. " + cachedNote, Tags: campaigns, Auth: true, Query: campaignStatsQuery{}})
+	doc.Add("POST", "/api/campaigns", openapi.Route{Summary: "Create a campaign", Description: "Requires the merchant role; the campaign belongs to the caller. Its terms must satisfy the campaign policy of the merchant's tier (R2S-2020 to R2S-2022). It starts as a draft: submit it for review, and once ops approve it deploy it and record the deployment to open it. Accepts an Idempotency-Key header.", Tags: campaigns, Auth: true, Body: createCampaignRequest{}, Response: models.Campaign{}, Status: 201})
+	doc.Add("POST", "/api/campaigns/:id/clone", openapi.Route{Summary: "Clone a campaign", Description: "Requires the ops role, or the merchant role and ownership of the campaign. Creates a draft with the campaign's pricing, rebate parameters, imagery, category and cancellation terms and a new lock window. The copied terms are checked like a new campaign's, against the current bounds and the merchant's current fee. " + relaunchNote, Tags: campaigns, Auth: true, Body: relaunchRequest{}, Response: models.Campaign{}, Status: 201})
+	doc.Add("PUT", "/api/campaigns/:id", openapi.Route{Summary: "Update a campaign", Description: "Requires the ops role, or the merchant role and ownership of the campaign. A status change the campaign's lifecycle or the caller's role does not allow fails with 409 R2S-2010. Send the version of the campaign you read in If-Match (If-Match: \"7\") or the version field; without it the update fails with 428 R2S-2011, and when the campaign changed since with 412 R2S-2012.", Tags: campaigns, Auth: true, Body: updateCampaignRequest{}, Response: models.Campaign{}})
+	doc.Add("GET", "/api/campaigns/:id/history", openapi.Route{Summary: "Get a campaign's status history", Description: "Every status change, oldest first, with the actor that made it and the reason. Requires the ops role, or the merchant role and ownership of the campaign.", Tags: campaigns, Auth: true, Response: []models.CampaignTransition{}})
+	doc.Add("POST", "/api/campaigns/:id/metadata/publish", openapi.Route{Summary: "Publish campaign metadata now", Description: "Campaign changes publish their metadata in the background; this retries a failed publish and returns the campaign with its metadata_uri.", Tags: campaigns, Auth: true, Response: models.Campaign{}})
+	doc.Add("POST", "/api/campaigns/:id/submit", openapi.Route{Summary: "Submit a draft campaign for review", Description: "Requires the ops role, or the merchant role and ownership of the campaign. Moves the draft to pending_review; it cannot be edited until it is back in draft. Takes the campaign version like PUT /api/campaigns/:id.", Tags: campaigns, Auth: true, Body: versionBody{}, Response: models.Campaign{}})
+	doc.Add("POST", "/api/campaigns/:id/review", openapi.Route{Summary: "Approve or reject a campaign", Description: "Requires the ops role. The campaign must be pending_review, or the review fails with 409 R2S-2015. Approved campaigns can be deployed; rejected ones go back to draft to be edited and resubmitted. The merchant is notified.", Tags: campaigns, Auth: true, Body: reviewCampaignRequest{}, Response: reviewCampaignResponse{}})
+	doc.Add("GET", "/api/campaigns/:id/reviews", openapi.Route{Summary: "Get a campaign's reviews", Description: "Every review decision, oldest first, with the reviewer and comment. Requires the ops role, or the merchant role and ownership of the campaign.", Tags: campaigns, Auth: true, Response: []models.CampaignReview{}})
+	doc.Add("POST", "/api/campaigns/:id/deployment", openapi.Route{Summary: "Record a campaign's deployment", Description: "Requires the ops role, or the merchant role and ownership of the campaign. Call it once the transaction from POST /api/tx/deploy-campaign is mined: it stores the contract address and opens the campaign for participation. Only approved campaigns can be deployed (409 R2S-2016), and the address must not belong to another campaign (409 R2S-2017).", Tags: campaigns, Auth: true, Body: deploymentRequest{}, Response: models.Campaign{}})
+	doc.Add("POST", "/api/campaigns/:id/settle", openapi.Route{Summary: "Settle an ended campaign", Description: "Requires the ops role. Every active participation must be fulfilled and accepted, and none disputed (409 R2S-2506). Issues each participant a redemption voucher when vouchers are configured.", Tags: campaigns, Auth: true})
+	doc.Add("POST", "/api/campaigns/:id/fulfillment", openapi.Route{
+		Summary:     "Mark orders fulfilled",
+		Description: "Requires the ops role, or the merchant role and ownership of the campaign, which must be in fulfillment (409 R2S-2501). Marks the listed participations, or every active one, fulfilled with the proof's hash (400 R2S-2502) and optionally the uploaded photo; participations already marked are skipped unless disputed, which are marked again. Participants are asked to confirm or dispute within the confirmation window, 72 hours by default; unanswered fulfillments count as confirmed once it closes. Returns the fulfillments marked.",
+		Tags:        campaigns, Auth: true, Body: markFulfilledRequest{}, Response: []models.Fulfillment{},
+	})
+	doc.Add("GET", "/api/campaigns/:id/fulfillment", openapi.Route{
+		Summary:     "Get a campaign's fulfillment summary",
+		Description: "Requires the ops role, or the merchant role and ownership of the campaign. Active participations by fulfillment state; settleable is true once the campaign may be settled.",
+		Tags:        campaigns, Auth: true, Response: models.FulfillmentSummary{},
+	})
+	doc.Add("GET", "/api/campaigns/:id/fulfillments", openapi.Route{Summary: "List a campaign's fulfillments", Description: "Newest first. Requires the ops role, or the merchant role and ownership of the campaign.", Tags: campaigns, Auth: true, Query: fulfillmentListQuery{}, Response: []models.Fulfillment{}, Paged: true})
+
+	// Payments
+	payments := []string{"Payments"}
+	doc.Add("POST", "/api/payment/create", openapi.Route{Summary: "Record a payment", Description: "Accepts an Idempotency-Key header.", Tags: payments, Auth: true, Body: createPaymentRequest{}, Response: models.Payment{}})
+	doc.Add("GET", "/api/payment/:id/status", openapi.Route{Summary: "Get a payment's status", Tags: payments, Auth: true})
+
+	doc.Add("POST", "/api/graphql", openapi.Route{
+		Summary:     "Query campaigns, merchants and my participations with GraphQL",
+		Description: "Served when GRAPHQL_ENABLED is set; the schema is api-server/graph/schema.graphqls and can be introspected. Answers the GraphQL {\"data\", \"errors\"} shape; errors carry code and reason extensions like REST errors. Operations over the complexity limit are rejected.",
+		Tags:        []string{"GraphQL"}, Auth: true, Body: graphQLRequest{}, Response: graphQLResponse{}, Flat: true,
+	})
+	doc.Add("GET", "/api/features", openapi.Route{Summary: "Feature flags for the current user", Tags: []string{"Features"}, Auth: true, Response: map[string]bool{}})
+
+	// Participations and transactions
+	participations := []string{"Participations"}
+	doc.Add("GET", "/api/participations/my", openapi.Route{Summary: "List my participations", Description: "Each participation carries its deposit, the rebate range the campaign promises and, once settled, the rebate paid", Tags: participations, Auth: true, Query: pageQuery{}, Paged: true})
+	doc.Add("POST", "/api/participations/cancel", openapi.Route{Summary: "Build a cancel transaction", Tags: participations, Auth: true, Body: cancelTxRequest{}})
+	doc.Add("POST", "/api/participations/:id/cancel-request", openapi.Route{
+		Summary:     "Request a cancel",
+		Description: "Records the amount about to be cancelled with requestCancel as cancel_pending and quotes its penalty. Cancels are free until the campaign's free_cancel_until; later ones cost late_cancel_penalty_bps, or fail with 409 R2S-2106 when late_cancel_allowed is false. An amount that is not a multiple of the base price or exceeds the deposit fails with 400 R2S-2107. The deposit leaves the campaign once the transaction is seen on chain. Send the participation version in If-Match or the version field (428 R2S-2011, 412 R2S-2012).",
+		Tags:        participations, Auth: true, Body: cancelRequestBody{}, Response: cancelRequestResponse{},
+	})
+	tx := []string{"Transactions"}
+	doc.Add("POST", "/api/tx/join", openapi.Route{Summary: "Build a join transaction", Description: "Accepts an Idempotency-Key header.", Tags: tx, Auth: true, Body: joinTxRequest{}})
+	doc.Add("POST", "/api/tx/cancel", openapi.Route{Summary: "Build a cancel transaction", Description: "Accepts an Idempotency-Key header.", Tags: tx, Auth: true, Body: cancelTxRequest{}})
+	doc.Add("POST", "/api/tx/deploy-campaign", openapi.Route{Summary: "Build a campaign deployment transaction", Description: "Requires the merchant role. Deploys an approved campaign through the campaign factory; record the deployment with POST /api/campaigns/:id/deployment.", Tags: tx, Auth: true, Body: deployCampaignTxRequest{}})
+	doc.Add("POST", "/api/tx/settle-campaign", openapi.Route{Summary: "Build a campaign settlement transaction", Description: "Requires the ops role. Only built once every active participation is fulfilled and accepted and none is disputed (409 R2S-2506); settle the campaign with POST /api/campaigns/:id/settle once it is mined.", Tags: tx, Auth: true, Body: settleCampaignTxRequest{}})
+	doc.Add("POST", "/api/tx/refund", openapi.Route{Summary: "Build a dispute refund transaction", Description: "Requires the ops role. Only built for disputes awaiting an on-chain refund (409 R2S-2606); record it with POST /api/admin/disputes/:id/refund once it is mined.", Tags: tx, Auth: true, Body: refundTxRequest{}})
+	doc.Add("GET", "/api/tx/estimate-gas", openapi.Route{Summary: "Current gas price", Tags: tx, Auth: true})
+
+	// Users
+	users := []string{"Users"}
+	doc.Add("GET", "/api/users/profile", openapi.Route{Summary: "Get my profile", Description: "With my active campaigns, total deposit and rebates, and the 20 latest rebates paid.", Tags: users, Auth: true})
+	doc.Add("GET", "/api/users/portfolio", openapi.Route{Summary: "Get my savings summary", Description: "Deposits locked in unsettled campaigns, the rebates accrued on them so far and expected at the end of their lock, the settlements coming up by campaign end date, and the rebates paid by month.", Tags: users, Auth: true})
+	doc.Add("PUT", "/api/users/profile", openapi.Route{Summary: "Update my profile", Tags: users, Auth: true, Body: models.JSONB{}})
+	doc.Add("GET", "/api/users/metadata", openapi.Route{Summary: "Get my metadata", Tags: users, Auth: true, Response: models.JSONB{}})
+	doc.Add("PATCH", "/api/users/metadata", openapi.Route{
+		Summary:     "Merge into my metadata",
+		Description: "Keys set to null are removed.",
+		Tags:        users, Auth: true, Body: models.JSONB{}, Response: models.JSONB{},
+	})
+	doc.Add("GET", "/api/users/favorites", openapi.Route{Summary: "List my watchlist", Description: "Campaigns I favorited, most recently favorited first; each carries favorited_at.", Tags: users, Auth: true, Query: favoritesQuery{}, Paged: true})
+	doc.Add("PUT", "/api/users/favorites/:campaignId", openapi.Route{
+		Summary:     "Favorite a campaign",
+		Description: "Adds the campaign to my watchlist; favoriting it again changes nothing. A watchlist holds at most 200 campaigns (409 R2S-2401). I get a push, under the watchlist preference, when a recruiting campaign on it nears its minimum quantity and when its recruitment is about to close.",
+		Tags:        users, Auth: true, Response: models.CampaignFavorite{},
+	})
+	doc.Add("DELETE", "/api/users/favorites/:campaignId", openapi.Route{Summary: "Unfavorite a campaign", Tags: users, Auth: true})
+	doc.Add("GET", "/api/users/referral", openapi.Route{
+		Summary:     "Get my referral stats",
+		Description: "My referral code (created on first request), how many users joined with it and how many of them qualified by joining a campaign, my pending and paid reward totals in base units, and the code I joined with.",
+		Tags:        users, Auth: true, Response: models.ReferralStats{},
+	})
+	doc.Add("POST", "/api/users/referral", openapi.Route{
+		Summary:     "Apply a referral code",
+		Description: "For users who did not send one at sign-up; only before my first participation (409 R2S-7004) and only once (409 R2S-7003). My own code, or one of an account on my device and network, is refused (403 R2S-7002). My first participation credits the code's owner and me a bonus of its deposit.",
+		Tags:        users, Auth: true, Body: applyReferralRequest{}, Response: models.Referral{}, Status: 201,
+	})
+	doc.Add("GET", "/api/users/referral/rewards", openapi.Route{Summary: "List my referral rewards", Description: "Newest first; amounts are in base units.", Tags: users, Auth: true, Query: pageQuery{}, Paged: true})
+
+	// Fulfillments
+	fulfillments := []string{"Fulfillments"}
+	doc.Add("GET", "/api/fulfillments", openapi.Route{Summary: "List the fulfillments of my orders", Description: "Newest first.", Tags: fulfillments, Auth: true, Query: pageQuery{}, Response: []models.Fulfillment{}, Paged: true})
+	doc.Add("POST", "/api/fulfillments/:participationId/confirm", openapi.Route{
+		Summary:     "Confirm I received my order",
+		Description: "Only while the fulfillment awaits an answer (409 R2S-2504) and before its confirm_deadline (409 R2S-2505).",
+		Tags:        fulfillments, Auth: true, Response: models.Fulfillment{},
+	})
+	doc.Add("POST", "/api/fulfillments/:participationId/dispute", openapi.Route{
+		Summary:     "Dispute the fulfillment of my order",
+		Description: "Like confirming, only before the confirm_deadline. The campaign is not settled until the merchant marks the order fulfilled again, which reopens the confirmation window and closes the dispute as redelivered, or an admin resolves the dispute opened, see GET /api/disputes.",
+		Tags:        fulfillments, Auth: true, Body: disputeRequest{}, Response: models.Fulfillment{},
+	})
+
+	// Disputes
+	disputes := []string{"Disputes"}
+	doc.Add("GET", "/api/disputes", openapi.Route{Summary: "List my disputes", Description: "Newest first.", Tags: disputes, Auth: true, Query: pageQuery{}, Response: []models.Dispute{}, Paged: true})
+	doc.Add("POST", "/api/disputes", openapi.Route{
+		Summary:     "Open a dispute",
+		Description: "A fulfillment dispute works like POST /api/fulfillments/:participationId/dispute. Settled participations may be disputed within the settlement window, 14 days by default (409 R2S-2603, R2S-2604). A participation has at most one pending dispute (409 R2S-2602). Admins resolve disputes within the resolution SLA, 72 hours by default.",
+		Tags:        disputes, Auth: true, Body: openDisputeRequest{}, Response: models.Dispute{}, Status: 201,
+	})
+	doc.Add("GET", "/api/disputes/:id", openapi.Route{Summary: "Get one of my disputes", Tags: disputes, Auth: true, Response: models.Dispute{}})
+
+	// Vouchers
+	vouchers := []string{"Vouchers"}
+	const vouchersDisabled = "Fails with 409 R2S-2701 when vouchers are not configured."
+	doc.Add("GET", "/api/vouchers", openapi.Route{Summary: "List my vouchers", Description: "Settling a campaign issues each participant a voucher to redeem their order at the merchant. Issued vouchers carry their code and qr_payload, the text to encode in a QR code. Newest first. " + vouchersDisabled, Tags: vouchers, Auth: true, Query: pageQuery{}, Response: []models.Voucher{}, Paged: true})
+	doc.Add("GET", "/api/vouchers/:id", openapi.Route{Summary: "Get one of my vouchers", Description: vouchersDisabled, Tags: vouchers, Auth: true, Response: models.Voucher{}})
+	doc.Add("POST", "/api/vouchers/validate", openapi.Route{
+		Summary:     "Look up a voucher code",
+		Description: "Requires the merchant role and ownership of the voucher's campaign, or the ops role; other codes are not found (404 R2S-2702). Does not redeem the voucher; only issued vouchers can be redeemed. " + vouchersDisabled,
+		Tags:        vouchers, Auth: true, Body: voucherCodeRequest{}, Response: models.Voucher{},
+	})
+	doc.Add("POST", "/api/vouchers/redeem", openapi.Route{
+		Summary:     "Redeem a voucher code",
+		Description: "Requires the merchant role and ownership of the voucher's campaign, or the ops role (404 R2S-2702). A code is redeemed once; later attempts fail with 409 R2S-2703, and vouchers voided by a refund with 409 R2S-2704. " + vouchersDisabled,
+		Tags:        vouchers, Auth: true, Body: voucherCodeRequest{}, Response: models.Voucher{},
+	})
+
+	// Campaign templates
+	templates := []string{"Campaign templates"}
+	const templateAccess = "Requires the merchant role, or the ops role; merchants only see their own templates, others are not found (404 R2S-2018)."
+	doc.Add("GET", "/api/campaign-templates", openapi.Route{Summary: "List campaign templates", Description: "Most recently updated first. " + templateAccess, Tags: templates, Auth: true, Query: templateListQuery{}, Response: []models.CampaignTemplate{}, Paged: true})
+	doc.Add("POST", "/api/campaign-templates", openapi.Route{
+		Summary:     "Create a campaign template",
+		Description: "Saves the terms of a recurring offer from campaignId or from the fields given. The terms are checked like a new campaign's. Names are unique per merchant (409 R2S-2019). " + templateAccess,
+		Tags:        templates, Auth: true, Body: templateRequest{}, Response: models.CampaignTemplate{}, Status: 201,
+	})
+	doc.Add("GET", "/api/campaign-templates/:id", openapi.Route{Summary: "Get a campaign template", Description: templateAccess, Tags: templates, Auth: true, Response: models.CampaignTemplate{}})
+	doc.Add("PUT", "/api/campaign-templates/:id", openapi.Route{Summary: "Update a campaign template", Description: "Replaces the template's terms; campaignId and merchantId are ignored. " + templateAccess, Tags: templates, Auth: true, Body: templateRequest{}, Response: models.CampaignTemplate{}})
+	doc.Add("DELETE", "/api/campaign-templates/:id", openapi.Route{Summary: "Delete a campaign template", Description: "Campaigns launched from it are kept. " + templateAccess, Tags: templates, Auth: true})
+	doc.Add("POST", "/api/campaign-templates/:id/launch", openapi.Route{
+		Summary:     "Launch a campaign from a template",
+		Description: "Creates a draft campaign with the template's terms and a new lock window. The terms are checked again like a new campaign's, so a template saved under older bounds, of a merchant no longer approved or in a deleted category cannot launch. " + relaunchNote + " " + templateAccess,
+		Tags:        templates, Auth: true, Body: relaunchRequest{}, Response: models.Campaign{}, Status: 201,
+	})
+
+	// Merchants
+	merchants := []string{"Merchants"}
+	doc.Add("POST", "/api/merchants", openapi.Route{
+		Summary:     "Register as a merchant",
+		Description: "The registration is reviewed by an admin; approval grants the merchant role, which applies at the next sign-in. A rejected registration may be resubmitted.",
+		Tags:        merchants, Auth: true, Body: registerMerchantRequest{}, Response: models.Merchant{}, Status: 201,
+	})
+	doc.Add("GET", "/api/merchants/me", openapi.Route{Summary: "Get my merchant registration", Tags: merchants, Auth: true, Response: models.Merchant{}})
+	doc.Add("GET", "/api/merchants/me/dashboard", openapi.Route{
+		Summary:     "Get my merchant dashboard",
+		Description: "Requires the merchant role. Same as /api/merchants/:id/dashboard with my user id.",
+		Tags:        merchants, Auth: true, Query: merchantDashboardQuery{},
+	})
+	doc.Add("GET", "/api/merchants/:id/dashboard", openapi.Route{
+		Summary:     "Get a merchant dashboard",
+		Description: "Campaign counts by status, active campaigns, pending fulfillments and the deposits they hold in escrow, and the gross, merchant fee, ops fee and net payout of upcoming settlements and of settled campaigns. Merchants may only see their own; ops and admins any.",
+		Tags:        merchants, Auth: true, Query: merchantDashboardQuery{},
+	})
+	doc.Add("GET", "/api/merchants", openapi.Route{Summary: "List merchants", Description: "Newest first, with campaign counts and total volume.", Tags: merchants, Auth: true, Query: pageQuery{}, Paged: true})
+	doc.Add("GET", "/api/merchants/:id", openapi.Route{Summary: "Get a merchant", Description: "With campaign counts, total volume and the 20 latest settlements.", Tags: merchants, Auth: true})
+
+	// Media
+	media := []string{"Media"}
+	doc.Add("POST", "/api/media/uploads", openapi.Route{
+		Summary:     "Start an image upload",
+		Description: "Returns a pre-signed URL to PUT a JPEG or PNG to, then complete the upload. Campaign images and fulfillment proofs require the merchant or ops role; logos are for the caller's merchant registration. Fails with 409 R2S-2201 when uploads are not configured.",
+		Tags:        media, Auth: true, Body: createUploadRequest{}, Response: uploadTicket{}, Status: 201,
+	})
+	doc.Add("POST", "/api/media/uploads/:id/complete", openapi.Route{
+		Summary:     "Complete an image upload",
+		Description: "Checks the uploaded file and publishes it with a thumbnail. A logo becomes the merchant's logo; a campaign image is set by sending the upload id as imageId when creating or updating a campaign. Fails with 409 R2S-2205 before the file is uploaded and R2S-2206 once the upload URL expired.",
+		Tags:        media, Auth: true, Response: models.MediaUpload{},
+	})
+	doc.Add("GET", "/api/media/uploads/:id", openapi.Route{Summary: "Get one of my uploads", Tags: media, Auth: true, Response: models.MediaUpload{}})
+
+	// Notifications
+	notifications := []string{"Notifications"}
+	doc.Add("GET", "/api/notifications/devices", openapi.Route{Summary: "List my push devices", Tags: notifications, Auth: true, Response: []models.Device{}})
+	doc.Add("POST", "/api/notifications/devices", openapi.Route{Summary: "Register an FCM device token", Tags: notifications, Auth: true, Body: registerDeviceRequest{}, Response: models.Device{}, Status: 201})
+	doc.Add("DELETE", "/api/notifications/devices/:token", openapi.Route{Summary: "Unregister a device token", Tags: notifications, Auth: true})
+	doc.Add("GET", "/api/notifications/preferences", openapi.Route{Summary: "Get my notification preferences", Tags: notifications, Auth: true, Response: models.NotificationPreferences{}})
+	doc.Add("PUT", "/api/notifications/preferences", openapi.Route{
+		Summary:     "Change my notification preferences",
+		Description: "Omitted topics and channels keep their setting. Topics apply to pushes, LINE messages and emails alike; line and email turn a channel off for every topic.",
+		Tags:        notifications, Auth: true, Body: preferencesRequest{}, Response: models.NotificationPreferences{},
+	})
+	doc.Add("GET", "/api/notifications/deliveries", openapi.Route{
+		Summary:     "List my LINE messages and emails",
+		Description: "Newest first. Campaign reached, rebate settled and cancellation confirmed events are sent to my connected LINE account and verified email; status is pending until sent, and failed once retries ran out.",
+		Tags:        notifications, Auth: true, Query: pageQuery{}, Paged: true,
+	})
+
+	// Admin (admin role and a passed MFA challenge)
+	admin := []string{"Admin"}
+	doc.Add("GET", "/api/admin/overview", openapi.Route{Summary: "Counts by status", Tags: admin, Auth: true})
+	doc.Add("GET", "/api/admin/users", openapi.Route{Summary: "Search users", Tags: admin, Auth: true, Query: adminUserQuery{}, Response: []models.User{}, Paged: true})
+	doc.Add("POST", "/api/admin/users/suspend", openapi.Route{Summary: "Suspend users and end their sessions", Tags: admin, Auth: true, Body: bulkRequest{}})
+	doc.Add("POST", "/api/admin/users/reinstate", openapi.Route{Summary: "Lift user suspensions", Tags: admin, Auth: true, Body: bulkRequest{}})
+	doc.Add("POST", "/api/admin/users/role", openapi.Route{Summary: "Change users' role and end their sessions", Tags: admin, Auth: true, Body: roleRequest{}})
+	doc.Add("GET", "/api/admin/merchants", openapi.Route{Summary: "List merchants", Tags: admin, Auth: true, Query: adminMerchantQuery{}, Paged: true})
+	doc.Add("GET", "/api/admin/merchants/registrations", openapi.Route{Summary: "List merchant registrations", Description: "Oldest first, so pending registrations are reviewed in order.", Tags: admin, Auth: true, Query: adminMerchantRegistrationQuery{}, Response: []models.Merchant{}, Paged: true})
+	doc.Add("GET", "/api/admin/merchants/:id", openapi.Route{Summary: "Get a merchant registration", Tags: admin, Auth: true, Response: models.Merchant{}})
+	doc.Add("POST", "/api/admin/merchants/:id/status", openapi.Route{
+		Summary:     "Approve, reject, suspend or reinstate a merchant",
+		Description: "Approval grants the merchant role and suspension revokes it; either ends the user's sessions.",
+		Tags:        admin, Auth: true, Body: merchantStatusRequest{}, Response: models.Merchant{},
+	})
+	doc.Add("GET", "/api/admin/campaigns", openapi.Route{Summary: "Search campaigns", Tags: admin, Auth: true, Query: adminCampaignQuery{}, Response: []models.Campaign{}, Paged: true})
+	doc.Add("POST", "/api/admin/campaigns/pause", openapi.Route{Summary: "Pause campaigns", Tags: admin, Auth: true, Body: bulkRequest{}})
+	doc.Add("POST", "/api/admin/campaigns/resume", openapi.Route{Summary: "Resume paused campaigns", Tags: admin, Auth: true, Body: bulkRequest{}})
+	doc.Add("POST", "/api/admin/categories", openapi.Route{Summary: "Add a campaign category", Description: "Fails with 409 R2S-2302 when the slug is taken.", Tags: admin, Auth: true, Body: createCategoryRequest{}, Response: models.CampaignCategory{}, Status: 201})
+	doc.Add("PUT", "/api/admin/categories/:slug", openapi.Route{Summary: "Rename or move a campaign category", Tags: admin, Auth: true, Body: updateCategoryRequest{}, Response: models.CampaignCategory{}})
+	doc.Add("DELETE", "/api/admin/categories/:slug", openapi.Route{Summary: "Delete a campaign category", Description: "Only categories without campaigns can be deleted (409 R2S-2303).", Tags: admin, Auth: true})
+	doc.Add("GET", "/api/admin/campaign-policies", openapi.Route{Summary: "List campaign policies", Description: "The policy of each merchant tier that has one, by tier. A merchant's tier is the KYC tier of their user, 0 to 3.", Tags: admin, Auth: true, Response: []models.CampaignPolicy{}})
+	doc.Add("GET", "/api/admin/campaign-policies/:tier", openapi.Route{Summary: "Get a tier's campaign policy", Description: "Fails with 404 R2S-2023 when the tier has none.", Tags: admin, Auth: true, Response: models.CampaignPolicy{}})
+	doc.Add("PUT", "/api/admin/campaign-policies/:tier", openapi.Route{
+		Summary:     "Set a tier's campaign policy",
+		Description: "Replaces the tier's bounds; omitted ones are not enforced. New campaigns of the tier's merchants, including clones and template launches, are rejected when rMaxBps is above the cap (400 R2S-2020), the lock window is shorter than the minimum (400 R2S-2021) or the merchant's fee is outside the range (409 R2S-2022). Changing a campaign's window checks it again; existing campaigns are otherwise unaffected.",
+		Tags:        admin, Auth: true, Body: policyRequest{}, Response: models.CampaignPolicy{},
+	})
+	doc.Add("DELETE", "/api/admin/campaign-policies/:tier", openapi.Route{Summary: "Delete a tier's campaign policy", Description: "The tier's merchants are then only held to the built-in checks. Fails with 404 R2S-2023 when the tier has none.", Tags: admin, Auth: true})
+	doc.Add("GET", "/api/admin/payments", openapi.Route{Summary: "Search payments", Tags: admin, Auth: true, Query: adminPaymentQuery{}, Response: []models.Payment{}, Paged: true})
+	doc.Add("GET", "/api/admin/disputes", openapi.Route{Summary: "Search disputes", Description: "Pending ones by SLA, the most urgent first, otherwise newest first. sla_breached_at is set once a pending dispute passed its SLA.", Tags: admin, Auth: true, Query: adminDisputeQuery{}, Response: []models.Dispute{}, Paged: true})
+	doc.Add("GET", "/api/admin/disputes/:id", openapi.Route{Summary: "Get a dispute", Tags: admin, Auth: true, Response: models.Dispute{}})
+	doc.Add("POST", "/api/admin/disputes/:id/resolve", openapi.Route{
+		Summary:     "Resolve a dispute",
+		Description: "Only open disputes (409 R2S-2605). Rejecting closes the dispute and accepts a disputed fulfillment. Refunding up to the deposit (400 R2S-2607) moves it to refunding: payment refunds request a refund of the participant's completed payment (409 R2S-2608) and complete with the provider's refunded webhook; on-chain refunds are built with POST /api/tx/refund and recorded with POST /api/admin/disputes/:id/refund. A full refund refunds the participation, a partial one accepts the fulfillment.",
+		Tags:        admin, Auth: true, Body: resolveDisputeRequest{}, Response: models.Dispute{},
+	})
+	doc.Add("POST", "/api/admin/disputes/:id/refund", openapi.Route{Summary: "Record a mined on-chain refund", Description: "Only for disputes awaiting an on-chain refund (409 R2S-2606). Completes the dispute.", Tags: admin, Auth: true, Body: recordRefundRequest{}, Response: models.Dispute{}})
+	doc.Add("GET", "/api/admin/audit-log", openapi.Route{Summary: "Search the audit log", Tags: admin, Auth: true, Query: auditQuery{}, Response: []audit.Entry{}, Paged: true})
+	doc.Add("GET", "/api/admin/features", openapi.Route{Summary: "List feature flags", Description: "Every known flag and every override.", Tags: admin, Auth: true, Response: map[string]featureflags.Flag{}})
+	doc.Add("GET", "/api/admin/features/:name", openapi.Route{Summary: "Get a feature flag", Tags: admin, Auth: true, Response: featureflags.Flag{}})
+	doc.Add("PUT", "/api/admin/features/:name", openapi.Route{
+		Summary:     "Override a feature flag",
+		Description: "Reaches every gateway within seconds. maintenance takes the user API offline and freeze_participations, freeze_payments and freeze_campaigns the routes that change them, with 503 R2S-9006 and R2S-9007; a message replaces the error text.",
+		Tags:        admin, Auth: true, Body: featureflags.Flag{}, Response: featureflags.Flag{},
+	})
+	doc.Add("DELETE", "/api/admin/features/:name", openapi.Route{Summary: "Remove a feature flag override", Description: "The environment default applies again.", Tags: admin, Auth: true})
+	quotaDescription := "Quotas are campaign_create (campaigns a merchant creates) and tx_build (join and cancel transactions a user builds); calls over one get 429 R2S-9008 with Retry-After."
+	doc.Add("GET", "/api/admin/quotas/:name/users/:id", openapi.Route{Summary: "Get a user's quota usage", Description: quotaDescription, Tags: admin, Auth: true, Response: quotaUsage{}})
+	doc.Add("PUT", "/api/admin/quotas/:name/users/:id", openapi.Route{Summary: "Override a user's quota limit", Description: quotaDescription, Tags: admin, Auth: true, Body: quotaLimitRequest{}, Response: quotaUsage{}})
+	doc.Add("DELETE", "/api/admin/quotas/:name/users/:id/limit", openapi.Route{Summary: "Restore a user's configured quota limit", Tags: admin, Auth: true, Response: quotaUsage{}})
+	doc.Add("DELETE", "/api/admin/quotas/:name/users/:id/usage", openapi.Route{Summary: "Reset a user's quota usage", Description: "Forgets the calls of the current window.", Tags: admin, Auth: true, Response: quotaUsage{}})
+
+	return doc
+}
+
+// writeSpec writes the spec as indented JSON; map keys are sorted, so the
+// output only changes when the API does
+func writeSpec(path string) error {
+	body, err := json.MarshalIndent(apiSpec(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	if err := os.WriteFile(path, append(body, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI spec: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserParticipations는 GET /api/participations/my 엔드포인트를 처리합니다 (id는 로그인한 사용자)
+func (s *QueryAPI) GetUserParticipations(c *gin.Context, id string) {
+	userID, err := parseID(id, "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	status := c.Query("status")
+	if status != "" && !participationStatuses[status] {
+		respondError(c, apperrors.InvalidArgument("Invalid status"))
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "user_id", userID, "limit", page.Limit, "offset", page.Offset, "status", status)
+
+	resp, err := s.participationClient.GetUserParticipations(c.Request.Context(), &query.GetUserParticipationsRequest{
+		UserId: userID,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+		Status: status,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, participationsResponse(resp, page))
+}
+
+// participationStatuses는 status 쿼리 파라미터로 지정할 수 있는 참여 상태입니다
+var participationStatuses = map[string]bool{
+	models.ParticipationActive:        true,
+	models.ParticipationPendingCancel: true,
+	models.ParticipationCancelled:     true,
+	models.ParticipationSettled:       true,
+	models.ParticipationRefunded:      true,
+}
+
+// participationsResponse는 참여 목록 응답을 JSON으로 변환합니다
+func participationsResponse(resp *query.GetParticipationsResponse, page pagination.Page) gin.H {
+	participations := make([]map[string]interface{}, len(resp.Participations))
+	for i, p := range resp.Participations {
+		participations[i] = participationToMap(p)
+	}
+
+	return gin.H{
+		"participations": participations,
+		"total_count":    resp.TotalCount,
+		"pagination":     page.Result(resp.TotalCount),
+	}
+}
+
+// participationToMap은 protobuf Participation을 JSON 응답용 map으로 변환합니다
+func participationToMap(p *query.Participation) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                        p.Id,
+		"campaign_id":               p.CampaignId,
+		"campaign_address":          p.CampaignAddress,
+		"user_id":                   p.UserId,
+		"wallet_address":            p.WalletAddress,
+		"deposit_amount":            p.DepositAmount,
+		"deposit_amount_label":      formatPrice(p.DepositAmount),
+		"joined_at":                 p.JoinedAt.AsTime().Format(time.RFC3339),
+		"status":                    p.Status,
+		"expected_rebate_min":       p.ExpectedRebateMin,
+		"expected_rebate_min_label": formatPrice(p.ExpectedRebateMin),
+		"expected_rebate_max":       p.ExpectedRebateMax,
+		"expected_rebate_max_label": formatPrice(p.ExpectedRebateMax),
+		// 정산 전이면 빈 값입니다
+		"actual_rebate":       p.ActualRebate,
+		"actual_rebate_label": formatPrice(p.ActualRebate),
+		// 취소 대기 중인 금액입니다
+		"cancel_pending":       p.CancelPending,
+		"cancel_pending_label": formatPrice(p.CancelPending),
+		// 취소로 차감된 페널티 합계입니다
+		"cancel_penalty":       p.CancelPenalty,
+		"cancel_penalty_label": formatPrice(p.CancelPenalty),
+		// 취소 요청의 If-Match로 보내는 버전입니다
+		"version": p.Version,
+	}
+}
@@ -0,0 +1,548 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/money"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// QueryAPI는 query-server의 gRPC 조회 API를 REST로 제공합니다
+// 호출 기한, 재시도, 서킷 브레이커는 GRPCPool이 서비스 설정에 따라 적용합니다
+type QueryAPI struct {
+	queryClient         query.QueryServiceClient
+	participationClient query.ParticipationServiceClient
+	userClient          query.UserServiceClient
+	merchantClient      query.MerchantServiceClient
+}
+
+// NewQueryAPI는 query-server 연결 풀로 새로운 QueryAPI를 생성합니다
+func NewQueryAPI(conn *GRPCPool) *QueryAPI {
+	return &QueryAPI{
+		queryClient:         query.NewQueryServiceClient(conn),
+		participationClient: query.NewParticipationServiceClient(conn),
+		userClient:          query.NewUserServiceClient(conn),
+		merchantClient:      query.NewMerchantServiceClient(conn),
+	}
+}
+
+// GetCampaigns는 GET /api/campaigns 엔드포인트를 처리합니다
+func (s *QueryAPI) GetCampaigns(c *gin.Context) {
+	// 쿼리 파라미터 파싱
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req, err := campaignsRequest(c, page)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "limit", page.Limit, "offset", page.Offset, "statuses", req.Statuses, "sort", req.Sort)
+
+	// gRPC 호출
+	resp, err := s.queryClient.GetCampaigns(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("gRPC response", "count", len(resp.Campaigns), "total_count", resp.TotalCount)
+
+	// 응답 변환 (protobuf → JSON)
+	campaigns := make([]map[string]interface{}, len(resp.Campaigns))
+	for i, campaign := range resp.Campaigns {
+		campaigns[i] = campaignToMap(campaign)
+	}
+
+	// count=false면 총 개수 없이 (-1) query-server가 정한 다음 페이지 커서를 전달
+	result := page.Result(resp.TotalCount)
+	if req.SkipCount {
+		result.NextCursor = resp.NextCursor
+	}
+
+	// JSON 응답
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns":   campaigns,
+		"total_count": resp.TotalCount,
+		"pagination":  result,
+	})
+}
+
+// SearchCampaigns는 GET /api/campaigns/search 엔드포인트를 처리합니다 (관련도 순)
+func (s *QueryAPI) SearchCampaigns(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req := &query.SearchCampaignsRequest{
+		Q:      c.Query("q"),
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+	if req.Statuses, err = queryStatuses(c); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "q", req.Q, "limit", page.Limit, "offset", page.Offset)
+
+	resp, err := s.queryClient.SearchCampaigns(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	results := make([]map[string]interface{}, len(resp.Results))
+	for i, r := range resp.Results {
+		result := campaignToMap(r.Campaign)
+		result["rank"] = r.Rank
+		result["title_snippet"] = r.TitleSnippet
+		result["description_snippet"] = r.DescriptionSnippet
+		results[i] = result
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns":   results,
+		"total_count": resp.TotalCount,
+		"pagination":  page.Result(resp.TotalCount),
+	})
+}
+
+// campaignSorts는 sort 쿼리 파라미터 값별 정렬 기준입니다
+var campaignSorts = map[string]query.CampaignSort{
+	"newest":      query.CampaignSort_CAMPAIGN_SORT_NEWEST,
+	"ending_soon": query.CampaignSort_CAMPAIGN_SORT_ENDING_SOON,
+	"most_funded": query.CampaignSort_CAMPAIGN_SORT_MOST_FUNDED,
+	"category":    query.CampaignSort_CAMPAIGN_SORT_CATEGORY,
+}
+
+// campaignsRequest는 캠페인 목록의 필터와 정렬 쿼리 파라미터로 gRPC 요청을 생성합니다
+func campaignsRequest(c *gin.Context, page pagination.Page) (*query.GetCampaignsRequest, error) {
+	req := &query.GetCampaignsRequest{
+		Limit:        int32(page.Limit),
+		Offset:       int32(page.Offset),
+		MinBasePrice: c.Query("minPrice"),
+		MaxBasePrice: c.Query("maxPrice"),
+		Q:            c.Query("q"),
+		Category:     c.Query("category"),
+		Tags:         c.QueryArray("tag"),
+	}
+	var err error
+	if req.Statuses, err = queryStatuses(c); err != nil {
+		return nil, err
+	}
+	if v := c.Query("merchantId"); v != "" {
+		if req.MerchantId, err = parseID(v, "merchant"); err != nil {
+			return nil, err
+		}
+	}
+	if v := c.Query("count"); v != "" {
+		count, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, apperrors.InvalidArgument("count must be true or false")
+		}
+		req.SkipCount = !count
+	}
+	if req.LockFrom, err = queryTime(c, "lockFrom"); err != nil {
+		return nil, err
+	}
+	if req.LockTo, err = queryTime(c, "lockTo"); err != nil {
+		return nil, err
+	}
+	if v := c.Query("sort"); v != "" {
+		sort, ok := campaignSorts[v]
+		if !ok {
+			return nil, apperrors.InvalidArgument("sort must be newest, ending_soon, most_funded or category")
+		}
+		req.Sort = sort
+	}
+	return req, nil
+}
+
+// campaignStatuses는 status 쿼리 파라미터로 지정할 수 있는 캠페인 상태입니다
+var campaignStatuses = map[string]bool{
+	string(models.StatusDraft):         true,
+	string(models.StatusPendingReview): true,
+	string(models.StatusApproved):      true,
+	string(models.StatusRejected):      true,
+	string(models.StatusRecruiting):    true,
+	string(models.StatusReached):       true,
+	string(models.StatusFulfillment):   true,
+	string(models.StatusSettled):       true,
+	string(models.StatusFailed):        true,
+	string(models.StatusCancelled):     true,
+	string(models.StatusPaused):        true,
+}
+
+// queryStatuses는 여러 번 지정할 수 있는 status 쿼리 파라미터를 검증합니다 (status=recruiting&status=reached)
+func queryStatuses(c *gin.Context) ([]string, error) {
+	statuses := c.QueryArray("status")
+	for _, v := range statuses {
+		if !campaignStatuses[v] {
+			return nil, apperrors.InvalidArgument("Invalid status")
+		}
+	}
+	return statuses, nil
+}
+
+// parseID는 UUID 경로/쿼리 파라미터를 검증해 표준 형식으로 반환합니다 (name은 오류 메시지용)
+func parseID(v, name string) (string, error) {
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return "", apperrors.InvalidArgument("Invalid " + name + " ID")
+	}
+	return id.String(), nil
+}
+
+// queryTime은 RFC 3339 시각 쿼리 파라미터를 변환합니다 (없으면 nil)
+func queryTime(c *gin.Context, name string) (*timestamppb.Timestamp, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, apperrors.InvalidArgument(name + " must be an RFC 3339 time")
+	}
+	return timestamppb.New(t), nil
+}
+
+// GetCampaign은 GET /api/campaigns/:id 엔드포인트를 처리합니다
+func (s *QueryAPI) GetCampaign(c *gin.Context) {
+	// 경로 파라미터 파싱
+	campaignID, err := parseID(c.Param("id"), "campaign")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "campaign_id", campaignID)
+
+	// gRPC 요청 생성
+	req := &query.GetCampaignRequest{
+		CampaignId: campaignID,
+	}
+
+	// gRPC 호출
+	resp, err := s.queryClient.GetCampaign(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !resp.Found {
+		ginlog.From(c).Debug("campaign not found", "campaign_id", campaignID)
+		respondError(c, apperrors.Catalog(apperrors.ReasonCampaignNotFound))
+		return
+	}
+
+	campaign := resp.Campaign
+	ginlog.From(c).Debug("gRPC response", "chain_address", campaign.ChainAddress)
+
+	// 응답 변환 (protobuf → JSON)
+	c.JSON(http.StatusOK, campaignToMap(campaign))
+}
+
+// GetCampaignStats는 GET /api/campaigns/:id/stats 엔드포인트를 처리합니다
+func (s *QueryAPI) GetCampaignStats(c *gin.Context) {
+	campaignID, err := parseID(c.Param("id"), "campaign")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 모집 추이 기간 (옵션)
+	req := &query.GetCampaignStatsRequest{CampaignId: campaignID}
+	if v := c.Query("days"); v != "" {
+		days, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || days < 1 {
+			respondError(c, apperrors.InvalidArgument("days must be a positive integer"))
+			return
+		}
+		req.Days = int32(days)
+	}
+
+	ginlog.From(c).Debug("REST API called", "campaign_id", campaignID, "days", req.Days)
+
+	resp, err := s.queryClient.GetCampaignStats(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !resp.Found {
+		respondError(c, apperrors.Catalog(apperrors.ReasonCampaignNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, campaignStatsToMap(resp.Stats))
+}
+
+// campaignStatsToMap은 protobuf CampaignStats를 JSON 응답용 map으로 변환합니다
+func campaignStatsToMap(stats *query.CampaignStats) map[string]interface{} {
+	funding := make([]map[string]interface{}, 0, len(stats.Funding))
+	for _, point := range stats.Funding {
+		funding = append(funding, map[string]interface{}{
+			"day":                 point.Day.AsTime().Format(time.DateOnly),
+			"total_deposit":       point.TotalDeposit,
+			"total_deposit_label": formatPrice(point.TotalDeposit),
+			"participant_count":   point.ParticipantCount,
+			"funded_count":        point.FundedCount,
+		})
+	}
+
+	// 집계 전이면 updated_at은 null
+	var updatedAt interface{}
+	if stats.UpdatedAt != nil {
+		updatedAt = stats.UpdatedAt.AsTime().Format(time.RFC3339)
+	}
+
+	return map[string]interface{}{
+		"campaign_id":                stats.CampaignId,
+		"participant_count":          stats.ParticipantCount,
+		"funded_count":               stats.FundedCount,
+		"cancelled_count":            stats.CancelledCount,
+		"cancellation_rate":          stats.CancellationRate,
+		"total_deposit":              stats.TotalDeposit,
+		"total_deposit_label":        formatPrice(stats.TotalDeposit),
+		"average_deposit":            stats.AverageDeposit,
+		"average_deposit_label":      formatPrice(stats.AverageDeposit),
+		"projected_rebate_min":       stats.ProjectedRebateMin,
+		"projected_rebate_min_label": formatPrice(stats.ProjectedRebateMin),
+		"projected_rebate_max":       stats.ProjectedRebateMax,
+		"projected_rebate_max_label": formatPrice(stats.ProjectedRebateMax),
+		"updated_at":                 updatedAt,
+		"funding":                    funding,
+	}
+}
+
+// GetTrendingCampaigns는 GET /api/campaigns/trending 엔드포인트를 처리합니다 (미니앱 홈 피드)
+func (s *QueryAPI) GetTrendingCampaigns(c *gin.Context) {
+	limit, err := queryInt32(c, "limit")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	hours, err := queryInt32(c, "hours")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req := &query.GetTrendingCampaignsRequest{Limit: limit, WindowHours: hours}
+
+	ginlog.From(c).Debug("REST API called", "limit", req.Limit, "window_hours", req.WindowHours)
+
+	resp, err := s.queryClient.GetTrendingCampaigns(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	campaigns := make([]map[string]interface{}, len(resp.Campaigns))
+	for i, t := range resp.Campaigns {
+		campaign := campaignToMap(t.Campaign)
+		campaign["recent_joins"] = t.RecentJoins
+		campaign["trending_score"] = t.Score
+		campaigns[i] = campaign
+	}
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns})
+}
+
+// GetRecommendedCampaigns는 GET /api/campaigns/recommended 엔드포인트를 처리합니다 (로그인 사용자 기준)
+func (s *QueryAPI) GetRecommendedCampaigns(c *gin.Context, id string) {
+	userID, err := parseID(id, "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	limit, err := queryInt32(c, "limit")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req := &query.GetRecommendedCampaignsRequest{UserId: userID, Limit: limit}
+
+	ginlog.From(c).Debug("REST API called", "user_id", userID, "limit", req.Limit)
+
+	resp, err := s.queryClient.GetRecommendedCampaigns(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	campaigns := make([]map[string]interface{}, len(resp.Campaigns))
+	for i, r := range resp.Campaigns {
+		campaign := campaignToMap(r.Campaign)
+		campaign["reason"] = r.Reason
+		campaign["score"] = r.Score
+		campaigns[i] = campaign
+	}
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns})
+}
+
+// GetCategories는 GET /api/categories 엔드포인트를 처리합니다 (앱의 카테고리 탭)
+func (s *QueryAPI) GetCategories(c *gin.Context) {
+	resp, err := s.queryClient.GetCategories(c.Request.Context(), &query.GetCategoriesRequest{})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	categories := make([]map[string]interface{}, len(resp.Categories))
+	for i, category := range resp.Categories {
+		categories[i] = map[string]interface{}{
+			"slug":                  category.Slug,
+			"name":                  category.Name,
+			"position":              category.Position,
+			"active_campaign_count": category.ActiveCampaignCount,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// GetUserFavorites는 GET /api/users/favorites 엔드포인트를 처리합니다 (로그인 사용자의 관심 목록)
+func (s *QueryAPI) GetUserFavorites(c *gin.Context, id string) {
+	userID, err := parseID(id, "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	req := &query.GetUserFavoritesRequest{
+		UserId: userID,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+	if req.Statuses, err = queryStatuses(c); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	ginlog.From(c).Debug("REST API called", "user_id", userID, "limit", page.Limit, "offset", page.Offset, "statuses", req.Statuses)
+
+	resp, err := s.queryClient.GetUserFavorites(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	campaigns := make([]map[string]interface{}, len(resp.Favorites))
+	for i, f := range resp.Favorites {
+		campaign := campaignToMap(f.Campaign)
+		campaign["favorited_at"] = f.FavoritedAt.AsTime().Format(time.RFC3339)
+		campaigns[i] = campaign
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"campaigns":   campaigns,
+		"total_count": resp.TotalCount,
+		"pagination":  page.Result(resp.TotalCount),
+	})
+}
+
+// queryInt32는 양의 정수 쿼리 파라미터를 변환합니다 (없으면 0, 서버 기본값 적용)
+func queryInt32(c *gin.Context, name string) (int32, error) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || n < 1 {
+		return 0, apperrors.InvalidArgument(name + " must be a positive integer")
+	}
+	return int32(n), nil
+}
+
+// campaignToMap은 protobuf Campaign을 JSON 응답용 map으로 변환합니다
+func campaignToMap(campaign *query.Campaign) map[string]interface{} {
+	// 정산 전이면 settlement_date는 null
+	var settlementDate interface{}
+	if campaign.SettlementDate != nil {
+		settlementDate = campaign.SettlementDate.AsTime().Format(time.RFC3339)
+	}
+	var freeCancelUntil interface{}
+	if campaign.FreeCancelUntil != nil {
+		freeCancelUntil = campaign.FreeCancelUntil.AsTime().Format(time.RFC3339)
+	}
+	// 태그가 없으면 null 대신 빈 배열
+	tags := campaign.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return map[string]interface{}{
+		"id":                   campaign.Id,
+		"chain_address":        campaign.ChainAddress,
+		"merchant_id":          campaign.MerchantId,
+		"merchant_name":        campaign.MerchantName,
+		"merchant_wallet":      campaign.MerchantWallet,
+		"title":                campaign.Title,
+		"description":          campaign.Description,
+		"image_url":            campaign.ImageUrl,
+		"image_thumbnail_url":  campaign.ImageThumbnailUrl,
+		"base_price":           campaign.BasePrice,
+		"base_price_units":     basePriceUnits(campaign.BasePrice),
+		"base_price_label":     formatPrice(campaign.BasePrice),
+		"min_qty":              campaign.MinQty,
+		"current_qty":          campaign.CurrentQty,
+		"target_amount":        campaign.TargetAmount,
+		"target_amount_label":  formatPrice(campaign.TargetAmount),
+		"current_amount":       campaign.CurrentAmount,
+		"current_amount_label": formatPrice(campaign.CurrentAmount),
+		"discount_rate":        campaign.DiscountRate,
+		"start_time":           campaign.StartTime.AsTime().Format(time.RFC3339),
+		"end_time":             campaign.EndTime.AsTime().Format(time.RFC3339),
+		"settlement_date":      settlementDate,
+		"r_max_bps":            campaign.RMaxBps,
+		"save_floor_bps":       campaign.SaveFloorBps,
+		"merchant_fee_bps":     campaign.MerchantFeeBps,
+		"ops_fee_bps":          campaign.OpsFeeBps,
+		"status":               campaign.Status,
+		"metadata_uri":         campaign.MetadataUri,
+		"created_at":           campaign.CreatedAt.AsTime().Format(time.RFC3339),
+		// 수정 요청의 If-Match로 보내는 버전입니다
+		"version": campaign.Version,
+		// 취소 조건: 마감 전 무료, 마감 후에는 페널티가 설정된 경우에만 취소 가능
+		"free_cancel_until":       freeCancelUntil,
+		"late_cancel_allowed":     campaign.LateCancelAllowed,
+		"late_cancel_penalty_bps": campaign.LateCancelPenaltyBps,
+		// 카테고리 탭과 태그 (카테고리가 없으면 빈 값)
+		"category":      campaign.Category,
+		"category_name": campaign.CategoryName,
+		"tags":          tags,
+	}
+}
+
+// basePriceUnits는 NUMERIC 가격("10.500000")을 USDT 최소 단위 문자열("10500000")로 변환합니다
+func basePriceUnits(price string) string {
+	amount, err := money.Parse(price, money.USDT)
+	if err != nil {
+		return ""
+	}
+	return amount.Units().String()
+}
+
+// formatPrice는 NUMERIC 가격을 화면 표시용 문자열("10.50 USDT")로 변환합니다
+func formatPrice(price string) string {
+	amount, err := money.Parse(price, money.USDT)
+	if err != nil {
+		return price
+	}
+	return amount.String()
+}
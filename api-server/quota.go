@@ -0,0 +1,171 @@
+package main
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// Quota names, as configured with QUOTA_* and addressed by the admin API
+const (
+	// QuotaCampaignCreate counts the campaigns a merchant creates
+	QuotaCampaignCreate = "campaign_create"
+	// QuotaTxBuild counts the join and cancel transactions a user builds,
+	// each of which calls the blockchain RPC
+	QuotaTxBuild = "tx_build"
+)
+
+// Quotas are business limits per user on top of the rate limits, kept in
+// Redis and shared by every gateway instance. Admins may raise or cut the
+// limit of a single user.
+type Quotas struct {
+	quotas map[string]*ratelimit.Quota
+}
+
+// NewQuotas applies limits, keyed by quota name
+func NewQuotas(redis *database.RedisClient, limits map[string]*RateLimit) *Quotas {
+	q := &Quotas{quotas: make(map[string]*ratelimit.Quota, len(limits))}
+	for name, l := range limits {
+		q.quotas[name] = ratelimit.NewQuota(redis.UniversalClient, "gateway:"+name, l.Requests, l.Period)
+	}
+	return q
+}
+
+// get returns the named quota, or a not found error when it is not enforced
+func (q *Quotas) get(name string) (*ratelimit.Quota, error) {
+	if q != nil {
+		if quota, ok := q.quotas[name]; ok {
+			return quota, nil
+		}
+	}
+	return nil, apperrors.Catalog(apperrors.ReasonQuotaNotFound)
+}
+
+// quota counts the request against the user's quota and answers 429 with
+// Retry-After once it is used up. It must run after AuthMiddleware, and
+// before idempotencyKey so the 429 is not replayed to retries. Requests are
+// let through while Redis is unavailable.
+func (g *Gateway) quota(name string) gin.HandlerFunc {
+	quota, err := g.quotas.get(name)
+	if err != nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		_, err := quota.Allow(c.Request.Context(), userKey(c))
+		if _, limited := ratelimit.RetryAfter(err); limited {
+			exceeded := apperrors.Catalog(apperrors.ReasonQuotaExceeded, name)
+			exceeded.Err = err
+			respondError(c, exceeded)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			ginlog.From(c).Warn("Quotas unavailable, allowing request", "quota", name, "error", err)
+		}
+		c.Next()
+	}
+}
+
+// quotaLimitRequest is the body of PUT /api/admin/quotas/:name/users/:id
+type quotaLimitRequest struct {
+	Limit *int `json:"limit" binding:"required,min=0" doc:"Events allowed per window; 0 blocks the user"`
+}
+
+// QuotaUsage handles GET /api/admin/quotas/:name/users/:id
+func (g *Gateway) QuotaUsage(c *gin.Context) {
+	quota, err := g.quotas.get(c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	g.respondQuotaUsage(c, quota)
+}
+
+// SetQuotaLimit handles PUT /api/admin/quotas/:name/users/:id, overriding
+// the user's limit until it is cleared
+func (g *Gateway) SetQuotaLimit(c *gin.Context) {
+	quota, err := g.quotas.get(c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	var req quotaLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, apperrors.InvalidArgument("limit must be a non-negative integer"))
+		return
+	}
+	if err := quota.SetLimit(c.Request.Context(), "user:"+c.Param("id"), *req.Limit); err != nil {
+		respondError(c, apperrors.Unavailable(err, "Failed to update the quota"))
+		return
+	}
+	ginlog.From(c).Info("Quota limit overridden", "quota", c.Param("name"), "user_id", c.Param("id"), "limit", *req.Limit)
+	g.respondQuotaUsage(c, quota)
+}
+
+// ClearQuotaLimit handles DELETE /api/admin/quotas/:name/users/:id/limit,
+// giving the user the configured limit again
+func (g *Gateway) ClearQuotaLimit(c *gin.Context) {
+	quota, err := g.quotas.get(c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := quota.ClearLimit(c.Request.Context(), "user:"+c.Param("id")); err != nil {
+		respondError(c, apperrors.Unavailable(err, "Failed to update the quota"))
+		return
+	}
+	ginlog.From(c).Info("Quota limit override cleared", "quota", c.Param("name"), "user_id", c.Param("id"))
+	g.respondQuotaUsage(c, quota)
+}
+
+// ResetQuota handles DELETE /api/admin/quotas/:name/users/:id/usage,
+// forgetting the user's calls in the current window
+func (g *Gateway) ResetQuota(c *gin.Context) {
+	quota, err := g.quotas.get(c.Param("name"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if err := quota.Reset(c.Request.Context(), "user:"+c.Param("id")); err != nil {
+		respondError(c, apperrors.Unavailable(err, "Failed to reset the quota"))
+		return
+	}
+	ginlog.From(c).Info("Quota usage reset", "quota", c.Param("name"), "user_id", c.Param("id"))
+	g.respondQuotaUsage(c, quota)
+}
+
+// quotaUsage is the user's standing in a quota
+type quotaUsage struct {
+	Quota      string `json:"quota"`
+	UserID     string `json:"userId"`
+	Limit      int64  `json:"limit"`
+	Used       int64  `json:"used"`
+	Remaining  int64  `json:"remaining"`
+	ResetIn    int    `json:"resetIn" doc:"Seconds until the window ends; 0 before the first call"`
+	Overridden bool   `json:"overridden" doc:"Whether limit is the user's own"`
+}
+
+func (g *Gateway) respondQuotaUsage(c *gin.Context, quota *ratelimit.Quota) {
+	usage, err := quota.Usage(c.Request.Context(), "user:"+c.Param("id"))
+	if err != nil {
+		respondError(c, apperrors.Unavailable(err, "Failed to read the quota"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": quotaUsage{
+			Quota:      c.Param("name"),
+			UserID:     c.Param("id"),
+			Limit:      usage.Limit,
+			Used:       usage.Used,
+			Remaining:  usage.Remaining(),
+			ResetIn:    int(math.Ceil(usage.ResetIn.Seconds())),
+			Overridden: usage.Overridden,
+		},
+	})
+}
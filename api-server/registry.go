@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxRetries caps a service's retries so a misconfigured registry cannot
+// multiply the load on a failing service
+const maxRetries = 5
+
+// serviceEntry is one service in GATEWAY_SERVICES or GATEWAY_SERVICES_FILE
+type serviceEntry struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	Timeout string `json:"timeout"`
+	Retries int    `json:"retries"`
+	// HealthPath is probed by /ready on the URL's host; empty skips it
+	HealthPath string `json:"healthPath"`
+	// ReadyPath is the readiness report GET /health aggregates, on the
+	// URL's host; empty skips it
+	ReadyPath string `json:"readyPath"`
+	Optional  bool   `json:"optional"`
+}
+
+// defaultServices run every service on localhost, as make dev does
+var defaultServices = map[string]serviceEntry{
+	"auth": {Name: "auth-server", URL: "http://localhost:3002", Timeout: "10s", Retries: 2, HealthPath: "/live", ReadyPath: "/ready"},
+	"core": {Name: "core-server", URL: "http://localhost:3003", Timeout: "30s", Retries: 2, HealthPath: "/live", ReadyPath: "/ready"},
+	// query-server speaks gRPC only; the gateway calls it through GRPCPool
+	"query":     {Name: "query-server", URL: "grpc://localhost:50051", Timeout: "10s", Retries: 2},
+	"batch":     {Name: "batch-server", URL: "http://localhost:3005", Timeout: "60s", Retries: 2, HealthPath: "/live", ReadyPath: "/ready", Optional: true},
+	"tx-helper": {Name: "tx-helper", URL: "http://localhost:3006", Timeout: "20s", Retries: 2, HealthPath: "/live", ReadyPath: "/ready"},
+	// realtime-server streams campaign progress over WebSocket (/api/realtime/ws)
+	"realtime": {Name: "realtime-server", URL: "http://localhost:3009", Timeout: "10s", HealthPath: "/live", ReadyPath: "/ready", Optional: true},
+}
+
+// grpcServices are called over gRPC, so their url is grpc://host:port and
+// their health is checked with the gRPC health protocol
+var grpcServices = map[string]bool{"query": true}
+
+// config validates the entry and resolves it into a ServiceConfig
+func (e serviceEntry) config(key string) (*ServiceConfig, error) {
+	base, err := url.Parse(e.URL)
+	switch {
+	case grpcServices[key]:
+		if err != nil || base.Scheme != "grpc" || base.Host == "" {
+			return nil, fmt.Errorf("service %s: url must be a grpc://host:port URL", key)
+		}
+	case err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "":
+		return nil, fmt.Errorf("service %s: url must be an http(s) URL", key)
+	}
+	timeout, err := time.ParseDuration(e.Timeout)
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("service %s: timeout must be a positive duration such as 10s", key)
+	}
+	if e.Retries < 0 || e.Retries > maxRetries {
+		return nil, fmt.Errorf("service %s: retries must be between 0 and %d", key, maxRetries)
+	}
+
+	svc := &ServiceConfig{
+		Name:     e.Name,
+		BaseURL:  e.URL,
+		Timeout:  timeout,
+		Optional: e.Optional,
+		Retries:  e.Retries,
+	}
+	if svc.Name == "" {
+		svc.Name = key
+	}
+	if grpcServices[key] {
+		svc.GRPCTarget = base.Host
+		return svc, nil
+	}
+	if e.HealthPath != "" {
+		svc.HealthURL = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: e.HealthPath}).String()
+	}
+	if e.ReadyPath != "" {
+		svc.ReadyURL = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: e.ReadyPath}).String()
+	}
+	return svc, nil
+}
+
+// parseServices overlays each layer, a JSON object keyed by service, on the
+// default services. Fields a layer leaves out keep their previous value.
+func parseServices(layers ...[]byte) (map[string]*ServiceConfig, error) {
+	entries := make(map[string]serviceEntry, len(defaultServices))
+	for key, e := range defaultServices {
+		entries[key] = e
+	}
+
+	for _, layer := range layers {
+		if len(layer) == 0 {
+			continue
+		}
+		var overrides map[string]json.RawMessage
+		if err := json.Unmarshal(layer, &overrides); err != nil {
+			return nil, fmt.Errorf("failed to parse services: %w", err)
+		}
+		for key, raw := range overrides {
+			e, ok := entries[key]
+			if !ok {
+				return nil, fmt.Errorf("unknown service %q", key)
+			}
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return nil, fmt.Errorf("failed to parse service %s: %w", key, err)
+			}
+			entries[key] = e
+		}
+	}
+
+	services := make(map[string]*ServiceConfig, len(entries))
+	for key, e := range entries {
+		svc, err := e.config(key)
+		if err != nil {
+			return nil, err
+		}
+		services[key] = svc
+	}
+	return services, nil
+}
+
+// Registry holds the services the gateway proxies to. Services are the
+// defaults overlaid with GATEWAY_SERVICES and then GATEWAY_SERVICES_FILE;
+// the file is re-read by Watch, so URLs, timeouts and retries change
+// without a restart.
+type Registry struct {
+	inline []byte
+	file   string
+
+	mu       sync.RWMutex
+	services map[string]*ServiceConfig
+	modTime  time.Time
+}
+
+// NewRegistry loads the services; inline and file may be empty
+func NewRegistry(inline, file string) (*Registry, error) {
+	r := &Registry{inline: []byte(inline), file: file}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get returns the current config of a service
+func (r *Registry) Get(key string) (*ServiceConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	svc, ok := r.services[key]
+	return svc, ok
+}
+
+// Keys returns the registered services in a stable order
+func (r *Registry) Keys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]string, 0, len(r.services))
+	for key := range r.services {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reload re-reads the services file. On error the current services stay in
+// place. A service keeps its circuit breaker unless its URL changed.
+func (r *Registry) Reload() error {
+	var fileLayer []byte
+	var modTime time.Time
+	if r.file != "" {
+		info, err := os.Stat(r.file)
+		if err != nil {
+			return fmt.Errorf("failed to read services file: %w", err)
+		}
+		if fileLayer, err = os.ReadFile(r.file); err != nil {
+			return fmt.Errorf("failed to read services file: %w", err)
+		}
+		modTime = info.ModTime()
+	}
+
+	services, err := parseServices(r.inline, fileLayer)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, svc := range services {
+		if old, ok := r.services[key]; ok && old.BaseURL == svc.BaseURL {
+			svc.breaker = old.breaker
+		} else {
+			svc.breaker = newCircuitBreaker(svc.Name)
+		}
+	}
+	r.services, r.modTime = services, modTime
+	return nil
+}
+
+// Watch reloads the services file on SIGHUP and, when interval is positive,
+// whenever its modification time changes. It runs until the process exits.
+func (r *Registry) Watch(interval time.Duration) {
+	if r.file == "" {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-hup:
+		case <-tick:
+			info, err := os.Stat(r.file)
+			r.mu.RLock()
+			unchanged := err == nil && info.ModTime().Equal(r.modTime)
+			r.mu.RUnlock()
+			if unchanged {
+				continue
+			}
+		}
+		if err := r.Reload(); err != nil {
+			slog.Error("Failed to reload gateway services, keeping the current ones", "file", r.file, "error", err)
+			continue
+		}
+		slog.Info("Reloaded gateway services", "file", r.file)
+	}
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/cache"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// cacheStatusHeader tells whether a response came from the cache
+	cacheStatusHeader = "X-Cache"
+	// changesRetryDelay spaces attempts to subscribe to change events
+	changesRetryDelay = 5 * time.Second
+)
+
+// ResponseCache caches successful responses of read-heavy routes in Redis,
+// shared by every gateway instance. Entries are tagged, and a tag is busted
+// when what it covers changes: by the gateway's own mutations and by the
+// change events realtime-server relays from the database.
+type ResponseCache struct {
+	redis *database.RedisClient
+	// listTTL and itemTTL bound how stale a missed bust leaves campaign
+	// lists and single campaigns
+	listTTL, itemTTL time.Duration
+}
+
+func NewResponseCache(redis *database.RedisClient, listTTL, itemTTL time.Duration) *ResponseCache {
+	return &ResponseCache{redis: redis, listTTL: listTTL, itemTTL: itemTTL}
+}
+
+// cachedResponse is a 200 response kept by ResponseCache. Other headers
+// are not kept, as some (CORS, Content-Language) are the first caller's.
+type cachedResponse struct {
+	ETag        string `json:"etag"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+}
+
+// bufferedWriter holds the response back, so its ETag can be set before
+// the body is written
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// WriteHeaderNow keeps the status unwritten until the body is released
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Written() bool {
+	return false
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+// key returns the Redis key of an entry or tag
+func (r *ResponseCache) key(name string) string {
+	return cache.KeyPrefix + ":gateway:" + name
+}
+
+func (r *ResponseCache) get(ctx context.Context, key string) (*cachedResponse, error) {
+	raw, err := r.redis.GetString(ctx, r.key(key))
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal([]byte(raw), &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// set stores an entry under tag; the tag lists its entries for bust
+func (r *ResponseCache) set(ctx context.Context, key, tag string, cached *cachedResponse, ttl time.Duration) error {
+	raw, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, database.DefaultRedisOpTimeout)
+	defer cancel()
+	_, err = r.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, r.key(key), raw, ttl)
+		pipe.SAdd(ctx, r.key("tag:"+tag), key)
+		pipe.Expire(ctx, r.key("tag:"+tag), ttl)
+		return nil
+	})
+	return err
+}
+
+// bust drops every entry under the tags. Keys are deleted one by one, as
+// they may live on different cluster nodes.
+func (r *ResponseCache) bust(ctx context.Context, tags ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, database.DefaultRedisOpTimeout)
+	defer cancel()
+	for _, tag := range tags {
+		keys, err := r.redis.SMembers(ctx, r.key("tag:"+tag)).Result()
+		if err != nil {
+			return err
+		}
+		_, err = r.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Del(ctx, r.key(key))
+			}
+			pipe.Del(ctx, r.key("tag:"+tag))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Watch busts the campaign responses on every campaign change relayed by
+// realtime-server. Without it, entries live until their TTL. It runs until
+// ctx is cancelled.
+func (r *ResponseCache) Watch(ctx context.Context) {
+	for {
+		err := database.SubscribeChanges(ctx, r.redis.UniversalClient, func(ev database.ChangeEvent) {
+			if ev.Table != "campaigns" {
+				return
+			}
+			if err := r.bust(ctx, campaignTags(ev.ID)...); err != nil {
+				slog.Warn("Failed to bust cached campaign responses", "campaign_id", ev.ID, "error", err)
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Lost campaign change events, retrying", "error", err)
+		if !sleep(ctx, changesRetryDelay) {
+			return
+		}
+	}
+}
+
+// campaignTags are the tags a change of the campaign busts: the campaign
+// and every list, which may show it
+func campaignTags(id string) []string {
+	tags := []string{"campaigns"}
+	if id != "" {
+		tags = append(tags, "campaign:"+id)
+	}
+	return tags
+}
+
+// etag is a strong validator of body
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified reports whether the client's If-None-Match has the ETag
+func notModified(c *gin.Context, etag string) bool {
+	for _, tag := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		if tag = strings.TrimSpace(tag); tag == etag || tag == "*" || tag == "W/"+etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheResponse caches the 200 responses of a GET route for ttl. Responses
+// are keyed by path and query, so they must not depend on the caller. tag
+// names what the response shows, for bust. Requests are proxied as usual
+// while Redis is unavailable.
+func cacheResponse(r *ResponseCache, ttl time.Duration, tag func(*gin.Context) string) gin.HandlerFunc {
+	if r == nil || ttl <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		if streaming(c.Request) {
+			c.Next()
+			return
+		}
+		key := "response:" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+
+		cached, err := r.get(c.Request.Context(), key)
+		if err != nil {
+			ginlog.From(c).Warn("Response cache unavailable, proxying request", "error", err)
+		}
+		if cached != nil {
+			writeCached(c, cached, "HIT")
+			c.Abort()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.Status() != http.StatusOK {
+			c.Writer.WriteHeader(w.Status())
+			c.Writer.Write(w.body.Bytes())
+			return
+		}
+		cached = &cachedResponse{ETag: etag(w.body.Bytes()), ContentType: c.Writer.Header().Get("Content-Type"), Body: w.body.Bytes()}
+		if err == nil {
+			if err := r.set(context.WithoutCancel(c.Request.Context()), key, tag(c), cached, ttl); err != nil {
+				ginlog.From(c).Warn("Failed to cache response", "error", err)
+			}
+		}
+		writeCached(c, cached, "MISS")
+	}
+}
+
+// writeCached answers with the response, or 304 when the client has it.
+// Clients revalidate every time, as the response may change at any moment.
+func writeCached(c *gin.Context, cached *cachedResponse, status string) {
+	c.Header("ETag", cached.ETag)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header(cacheStatusHeader, status)
+	c.Header("Content-Type", cached.ContentType)
+	if notModified(c, cached.ETag) {
+		c.Writer.Header().Del("Content-Length")
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Writer.Write(cached.Body)
+}
+
+// cacheCampaigns and cacheCampaign are the middleware of the campaign list
+// and of a single campaign; without a response cache they do nothing
+func (g *Gateway) cacheCampaigns() gin.HandlerFunc {
+	if g.responses == nil {
+		return cacheResponse(nil, 0, nil)
+	}
+	return cacheResponse(g.responses, g.responses.listTTL, func(*gin.Context) string { return "campaigns" })
+}
+
+func (g *Gateway) cacheCampaign() gin.HandlerFunc {
+	if g.responses == nil {
+		return cacheResponse(nil, 0, nil)
+	}
+	return cacheResponse(g.responses, g.responses.itemTTL, func(c *gin.Context) string { return "campaign:" + c.Param("id") })
+}
+
+// bustsCampaigns is the middleware of routes that change campaigns; once
+// one succeeds, the cached campaign responses are dropped
+func (g *Gateway) bustsCampaigns() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if g.responses == nil || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := g.responses.bust(ctx, campaignTags(c.Param("id"))...); err != nil {
+			ginlog.From(c).Warn("Failed to bust cached campaign responses", "error", err)
+		}
+	}
+}
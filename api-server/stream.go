@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+	"github.com/gin-gonic/gin"
+)
+
+// streaming reports whether a request asks for a long-lived response the
+// gateway must pass through as it arrives: a WebSocket upgrade or a
+// Server-Sent Events stream
+func streaming(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// CloseStreams ends every open WebSocket and SSE stream. Graceful shutdown
+// waits for in-flight requests, which streams never stop being; clients
+// reconnect to another instance.
+func (g *Gateway) CloseStreams() {
+	g.closeStreams()
+}
+
+// StreamRequest forwards a request to service without buffering it.
+// WebSocket upgrades are tunnelled and Server-Sent Events are flushed to the
+// client event by event. Streams are neither retried nor bounded by the
+// service's timeout.
+func (g *Gateway) StreamRequest(c *gin.Context, service string, path string) {
+	config, exists := g.services.Get(service)
+	if !exists {
+		respondError(c, apperrors.Internal(fmt.Errorf("service %q is not configured", service)))
+		return
+	}
+	target, err := url.Parse(config.BaseURL + path)
+	if err != nil {
+		respondError(c, apperrors.Internal(fmt.Errorf("failed to build %s URL: %w", service, err)))
+		return
+	}
+	if ok, wait := config.breaker.allow(); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		respondError(c, apperrors.Catalog(apperrors.ReasonServiceUnavailable, service))
+		return
+	}
+
+	// The proxy copies the headers of c.Request; only the gateway's own
+	// internal token may reach the services
+	c.Request.Header.Del(svcauth.Header)
+	if g.internal != nil && service != "auth" {
+		if err := g.internal.SetHeader(c.Request.Context(), c.Request); err != nil {
+			respondError(c, apperrors.Unavailable(err, "Failed to authenticate to "+service+" service"))
+			return
+		}
+	}
+	setRequestID(c, c.Request)
+	setActor(c, c.Request)
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	stop := context.AfterFunc(g.streams, cancel)
+	defer stop()
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Scheme, pr.Out.URL.Host = target.Scheme, target.Host
+			pr.Out.URL.Path, pr.Out.URL.RawPath = target.Path, target.RawPath
+			// realtime-server accepts same-origin browsers by comparing the
+			// Origin with the public host
+			pr.Out.Host = pr.In.Host
+			pr.SetXForwarded()
+		},
+		Transport: g.transport,
+		// flush every write, so events are not held back
+		FlushInterval: -1,
+		ModifyResponse: func(resp *http.Response) error {
+			config.breaker.record(!serviceFailed(resp.StatusCode))
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if ctx.Err() != nil {
+				// the client or the shutdown ended the stream
+				return
+			}
+			config.breaker.record(false)
+			respondError(c, upstreamError(err, service))
+		},
+	}
+	proxy.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
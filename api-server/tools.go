@@ -0,0 +1,8 @@
+//go:build tools
+
+// Tools run by go generate, pinned in go.mod
+package main
+
+import (
+	_ "github.com/99designs/gqlgen"
+)
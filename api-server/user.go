@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUserProfile은 GET /api/users/profile 엔드포인트를 처리합니다 (id는 로그인한 사용자)
+func (s *QueryAPI) GetUserProfile(c *gin.Context, id string) {
+	userID, err := parseID(id, "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := s.userClient.GetUserProfile(c.Request.Context(), &query.GetUserRequest{UserId: userID})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !resp.Found {
+		respondError(c, apperrors.Catalog(apperrors.ReasonUserNotFound))
+		return
+	}
+
+	profile := resp.Profile
+	user := profile.User
+	rebates := make([]map[string]interface{}, len(profile.Rebates))
+	for i, r := range profile.Rebates {
+		rebates[i] = rebateToMap(r)
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"id":                    user.Id,
+		"wallet_address":        user.WalletAddress,
+		"line_user_id":          user.LineUserId,
+		"status":                user.Status,
+		"created_at":            user.CreatedAt.AsTime().Format(time.RFC3339),
+		"participation_count":   user.ParticipationCount,
+		"active_campaign_count": profile.ActiveCampaignCount,
+		"total_deposit":         user.TotalDeposit,
+		"total_deposit_label":   formatPrice(user.TotalDeposit),
+		"total_rebate":          profile.TotalRebate,
+		"total_rebate_label":    formatPrice(profile.TotalRebate),
+		"rebates":               rebates,
+	})
+}
+
+// GetUserPortfolio는 GET /api/users/portfolio 엔드포인트를 처리합니다 ("내 절약" 탭)
+func (s *QueryAPI) GetUserPortfolio(c *gin.Context, id string) {
+	userID, err := parseID(id, "user")
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	resp, err := s.userClient.GetUserPortfolio(c.Request.Context(), &query.GetUserRequest{UserId: userID})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if !resp.Found {
+		respondError(c, apperrors.Catalog(apperrors.ReasonUserNotFound))
+		return
+	}
+
+	p := resp.Portfolio
+	upcoming := make([]map[string]interface{}, len(p.Upcoming))
+	for i, f := range p.Upcoming {
+		upcoming[i] = map[string]interface{}{
+			"day":                       f.Day.AsTime().Format(time.DateOnly),
+			"campaign_count":            f.CampaignCount,
+			"deposit":                   f.Deposit,
+			"deposit_label":             formatPrice(f.Deposit),
+			"expected_rebate_min":       f.ExpectedRebateMin,
+			"expected_rebate_min_label": formatPrice(f.ExpectedRebateMin),
+			"expected_rebate_max":       f.ExpectedRebateMax,
+			"expected_rebate_max_label": formatPrice(f.ExpectedRebateMax),
+		}
+	}
+	realized := make([]map[string]interface{}, len(p.Realized))
+	for i, m := range p.Realized {
+		realized[i] = map[string]interface{}{
+			"month":          m.Month.AsTime().Format("2006-01"),
+			"campaign_count": m.CampaignCount,
+			"amount":         m.Amount,
+			"amount_label":   formatPrice(m.Amount),
+		}
+	}
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"user_id":                   p.UserId,
+		"total_locked":              p.TotalLocked,
+		"total_locked_label":        formatPrice(p.TotalLocked),
+		"locked_count":              p.LockedCount,
+		"accrued_rebate_min":        p.AccruedRebateMin,
+		"accrued_rebate_min_label":  formatPrice(p.AccruedRebateMin),
+		"accrued_rebate_max":        p.AccruedRebateMax,
+		"accrued_rebate_max_label":  formatPrice(p.AccruedRebateMax),
+		"expected_rebate_min":       p.ExpectedRebateMin,
+		"expected_rebate_min_label": formatPrice(p.ExpectedRebateMin),
+		"expected_rebate_max":       p.ExpectedRebateMax,
+		"expected_rebate_max_label": formatPrice(p.ExpectedRebateMax),
+		"upcoming":                  upcoming,
+		"realized_total":            p.RealizedTotal,
+		"realized_total_label":      formatPrice(p.RealizedTotal),
+		"realized_campaign_count":   p.RealizedCampaignCount,
+		"realized":                  realized,
+	})
+}
+
+// rebateToMap은 protobuf Rebate를 JSON 응답용 map으로 변환합니다
+func rebateToMap(r *query.Rebate) map[string]interface{} {
+	return map[string]interface{}{
+		"participation_id":   r.ParticipationId,
+		"campaign_id":        r.CampaignId,
+		"campaign_address":   r.CampaignAddress,
+		"amount":             r.Amount,
+		"amount_label":       formatPrice(r.Amount),
+		"settlement_tx_hash": r.SettlementTxHash,
+		"settled_at":         r.SettledAt.AsTime().Format(time.RFC3339),
+	}
+}
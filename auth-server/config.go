@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"r2s/pkg/database"
+	"r2s/pkg/errreport"
+	"r2s/pkg/logger"
+	"r2s/pkg/mail"
+	"r2s/pkg/objectstore"
+	"r2s/pkg/server"
+	"r2s/pkg/tracing"
+	"r2s/pkg/utils"
+)
+
+// Config is the auth-server configuration, loaded by config.MustLoad
+type Config struct {
+	Port string `env:"AUTH_SERVER_PORT" default:"3002"`
+
+	// Access tokens are signed with an RSA (RS256) or P-256 (ES256) private
+	// key in PEM form, given inline or as a file (make jwt-key creates one)
+	JWTSigningKey     string        `env:"JWT_SIGNING_KEY" secret:"true"`
+	JWTSigningKeyFile string        `env:"JWT_SIGNING_KEY_FILE"`
+	JWTRefreshSecret  string        `env:"JWT_REFRESH_SECRET" required:"true" secret:"true"`
+	AccessTokenTTL    time.Duration `env:"JWT_ACCESS_EXPIRY" default:"15m"`
+	RefreshTokenTTL   time.Duration `env:"JWT_REFRESH_EXPIRY" default:"168h"`
+	NonceBytes        int           `env:"AUTH_NONCE_BYTES" default:"16"`
+
+	// Retired keys stay accepted so rotating doesn't end every session:
+	// move the old signing key's file here (it stays in the JWKS) until
+	// JWT_ACCESS_EXPIRY has passed, and the old refresh secret until
+	// JWT_REFRESH_EXPIRY has
+	JWTPreviousSigningKeyFiles []string `env:"JWT_PREVIOUS_SIGNING_KEY_FILES"`
+	JWTPreviousRefreshSecrets  []string `env:"JWT_PREVIOUS_REFRESH_SECRETS" secret:"true"`
+
+	// InternalClients are the services that may fetch internal tokens for
+	// calling core-server, query-server and tx-helper, as
+	// id:secret:service+service entries
+	InternalClients []string `env:"INTERNAL_CLIENTS" secret:"true"`
+
+	// SignInDomain is the site named in the message wallets sign
+	SignInDomain string `env:"AUTH_SIGN_IN_DOMAIN" default:"https://r2s.io"`
+
+	// Sign-in requests per minute, and signature failures before an IP or
+	// address is locked out (for a minute, doubling up to AUTH_LOCKOUT_MAX)
+	LoginRatePerIP      int           `env:"AUTH_RATE_LIMIT_IP" default:"30"`
+	LoginRatePerAddress int           `env:"AUTH_RATE_LIMIT_ADDRESS" default:"10"`
+	LockoutThreshold    int           `env:"AUTH_LOCKOUT_THRESHOLD" default:"5"`
+	LockoutMax          time.Duration `env:"AUTH_LOCKOUT_MAX" default:"1h"`
+
+	// NonceChallenge is off, pow or turnstile: what GET /auth/nonce
+	// requires before storing a nonce. pow needs AUTH_POW_SECRET (shared by
+	// all instances); turnstile needs TURNSTILE_SECRET_KEY.
+	NonceChallenge  string `env:"AUTH_NONCE_CHALLENGE" default:"off"`
+	PoWDifficulty   int    `env:"AUTH_POW_DIFFICULTY" default:"18"`
+	PoWSecret       string `env:"AUTH_POW_SECRET" secret:"true"`
+	TurnstileSecret string `env:"TURNSTILE_SECRET_KEY" secret:"true"`
+
+	// CountryHeader is the header the CDN puts the client IP's country in;
+	// sessions refreshed from another country must sign in again. Empty
+	// turns the country check off.
+	CountryHeader string `env:"AUTH_COUNTRY_HEADER" default:"CF-IPCountry"`
+
+	// LineChannelID is the LINE Login channel recovery ID tokens must be
+	// issued to; empty turns account recovery off
+	LineChannelID string `env:"LINE_CHANNEL_ID"`
+
+	// EmailVerifyURL is the app page verification links open, with the
+	// token in ?token=
+	EmailVerifyURL string `env:"AUTH_EMAIL_VERIFY_URL" default:"http://localhost:3000/verify-email"`
+
+	// RPCURL is used to verify contract wallet (EIP-1271) signatures;
+	// empty accepts only EOA signatures
+	RPCURL string `env:"BLOCKCHAIN_RPC_URL"`
+
+	// KYCWebhookSecret signs the KYC provider's review webhooks
+	// (X-Payload-Digest); empty skips the check, for development only
+	KYCWebhookSecret string `env:"KYC_WEBHOOK_SECRET" secret:"true"`
+
+	// DebugAddr serves pkg/diag (empty disables); keep it off the public network
+	DebugAddr string `env:"AUTH_DEBUG_ADDR"`
+
+	Database database.Config
+	Log      logger.Config
+	Tracing  tracing.Config
+	Errors   errreport.Config
+	Server   server.Config
+
+	// Mail sends verification links and recovery codes; without SMTP_HOST
+	// they are only logged
+	Mail mail.Config
+
+	// ObjectStore holds KYC documents; point it at a private bucket
+	ObjectStore objectstore.Config
+}
+
+// Validate requires a signing key, sane token lifetimes and positive
+// sign-in limits, and rejects nonces too short to resist guessing
+func (c *Config) Validate() error {
+	if c.JWTSigningKey == "" && c.JWTSigningKeyFile == "" {
+		return errors.New("JWT_SIGNING_KEY or JWT_SIGNING_KEY_FILE is required")
+	}
+	if c.AccessTokenTTL < time.Minute || c.RefreshTokenTTL < c.AccessTokenTTL {
+		return errors.New("JWT_ACCESS_EXPIRY must be at least 1m and JWT_REFRESH_EXPIRY at least as long")
+	}
+	if c.NonceBytes < utils.MinNonceBytes {
+		return fmt.Errorf("AUTH_NONCE_BYTES must be at least %d", utils.MinNonceBytes)
+	}
+	if c.LoginRatePerIP < 1 || c.LoginRatePerAddress < 1 || c.LockoutThreshold < 1 {
+		return errors.New("AUTH_RATE_LIMIT_IP, AUTH_RATE_LIMIT_ADDRESS and AUTH_LOCKOUT_THRESHOLD must be positive")
+	}
+	if c.LockoutMax < time.Minute {
+		return errors.New("AUTH_LOCKOUT_MAX must be at least 1m")
+	}
+	return nil
+}
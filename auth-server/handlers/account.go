@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"r2s/auth-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/validate"
+)
+
+// AccountHandler serves email verification and wallet recovery
+type AccountHandler struct {
+	accountService *services.AccountService
+	authHandler    *AuthHandler
+}
+
+func NewAccountHandler(accountService *services.AccountService, authHandler *AuthHandler) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		authHandler:    authHandler,
+	}
+}
+
+// SetEmail handles POST /auth/email with {"email"} and mails a
+// verification link to the address
+func (h *AccountHandler) SetEmail(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authHandler.authService)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.accountService.SetEmail(c.Request.Context(), claims.UserID, req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "Verification email sent",
+	})
+}
+
+// VerifyEmail handles POST /auth/email/verify with the {"token"} from the
+// verification link
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.accountService.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Email verified",
+	})
+}
+
+// StartRecovery handles POST /auth/recovery/start with {"email"}. It
+// answers the same whether or not the address belongs to an account.
+func (h *AccountHandler) StartRecovery(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.accountService.StartRecovery(c.Request.Context(), req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": "If the email belongs to a recoverable account, a recovery code was sent",
+	})
+}
+
+// CompleteRecovery handles POST /auth/recovery/complete. The new wallet
+// signs a nonce from GET /auth/nonce as for sign-in; on success all
+// sessions end and the user signs in with the new wallet.
+func (h *AccountHandler) CompleteRecovery(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token" binding:"required"`
+		LineIDToken string `json:"lineIdToken" binding:"required"`
+		Address     string `json:"address" binding:"required"`
+		Signature   string `json:"signature" binding:"required"`
+		Message     string `json:"message" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.Address("address", req.Address); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	err := h.accountService.CompleteRecovery(c.Request.Context(), services.RecoveryInput{
+		Token:       req.Token,
+		LineIDToken: req.LineIDToken,
+		Address:     req.Address,
+		Signature:   req.Signature,
+		Message:     req.Message,
+	}, h.authHandler.clientInfo(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Wallet re-linked; sign in with the new wallet",
+	})
+}
@@ -5,38 +5,84 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"r2s/auth-server/services"
+	"r2s/pkg/address"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/jwks"
+	"r2s/pkg/models"
+	"r2s/pkg/utils"
+	"r2s/pkg/validate"
 )
 
+// DeviceFingerprintHeader carries the client's device fingerprint on
+// sign-in and refresh
+const DeviceFingerprintHeader = "X-Device-Fingerprint"
+
 type AuthHandler struct {
 	authService *services.AuthService
+	keys        jwks.Set
+	// countryHeader is the CDN header with the client IP's country
+	countryHeader string
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService *services.AuthService, keys jwks.Set, countryHeader string) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
+		authService:   authService,
+		keys:          keys,
+		countryHeader: countryHeader,
+	}
+}
+
+// clientInfo describes the device making the request
+func (h *AuthHandler) clientInfo(c *gin.Context) services.ClientInfo {
+	info := services.ClientInfo{
+		IPAddress:   c.ClientIP(),
+		UserAgent:   c.GetHeader("User-Agent"),
+		Fingerprint: c.GetHeader(DeviceFingerprintHeader),
+	}
+	if h.countryHeader != "" {
+		info.Country = c.GetHeader(h.countryHeader)
 	}
+	return info
 }
 
-// GetNonce generates a nonce for wallet authentication
+// GetNonceChallenge handles GET /auth/nonce/challenge: the challenge to
+// solve before GET /auth/nonce, if one is configured
+func (h *AuthHandler) GetNonceChallenge(c *gin.Context) {
+	challenge, err := h.authService.NonceChallenge()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"challenge": challenge,
+	})
+}
+
+// GetNonce generates a nonce for wallet authentication. With a challenge
+// configured it also takes challenge and solution (proof of work) or
+// captchaToken (Turnstile).
 func (h *AuthHandler) GetNonce(c *gin.Context) {
 	address := c.Query("address")
 	chainID := c.DefaultQuery("chainId", "1001")
 
 	if address == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Address is required",
-		})
+		ginerrors.BadRequest(c, "Address is required")
 		return
 	}
 
-	nonce, message, requestID, expiresAt, err := h.authService.GenerateNonce(address, chainID)
+	nonce, message, requestID, expiresAt, err := h.authService.GenerateNonce(c.Request.Context(), address, chainID, services.ChallengeProof{
+		Challenge:    c.Query("challenge"),
+		Solution:     c.Query("solution"),
+		CaptchaToken: c.Query("captchaToken"),
+		IPAddress:    c.ClientIP(),
+	})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -55,29 +101,26 @@ func (h *AuthHandler) VerifySignature(c *gin.Context) {
 		Signature string `json:"signature" binding:"required"`
 		Message   string `json:"message" binding:"required"`
 		RequestID string `json:"requestId" binding:"required"`
+		// ReferralCode refers a new user; ignored for existing ones
+		ReferralCode string `json:"referralCode"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
 		return
 	}
 
 	tokens, user, err := h.authService.VerifySignature(
+		c.Request.Context(),
 		req.Address,
 		req.Signature,
 		req.Message,
 		req.RequestID,
-		c.ClientIP(),
-		c.GetHeader("User-Agent"),
+		req.ReferralCode,
+		h.clientInfo(c),
 	)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -87,7 +130,7 @@ func (h *AuthHandler) VerifySignature(c *gin.Context) {
 		"refreshToken": tokens.RefreshToken,
 		"user": gin.H{
 			"id":            user.ID,
-			"address":       user.WalletAddress,
+			"address":       address.Display(user.WalletAddress),
 			"kycTier":       user.KYCTier,
 			"lineConnected": user.LineUserID != nil,
 		},
@@ -102,10 +145,7 @@ func (h *AuthHandler) LineAuth(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
 		return
 	}
 
@@ -116,10 +156,7 @@ func (h *AuthHandler) LineAuth(c *gin.Context) {
 		c.GetHeader("User-Agent"),
 	)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -144,19 +181,13 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
 		return
 	}
 
-	accessToken, err := h.authService.RefreshToken(req.RefreshToken)
+	accessToken, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken, h.clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -170,19 +201,13 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Token required",
-		})
+		ginerrors.BadRequest(c, "Token required")
 		return
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
-	if err := h.authService.Logout(token); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to logout",
-		})
+	if err := h.authService.Logout(c.Request.Context(), token); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -196,20 +221,14 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) ValidateToken(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "Token required",
-		})
+		respondError(c, apperrors.Catalog(apperrors.ReasonTokenRequired))
 		return
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
-	claims, err := h.authService.ValidateToken(token)
+	claims, err := h.authService.ValidateToken(c.Request.Context(), token)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondError(c, err)
 		return
 	}
 
@@ -217,4 +236,202 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 		"success": true,
 		"claims":  claims,
 	})
-}
\ No newline at end of file
+}
+
+// JWKS handles GET /auth/.well-known/jwks.json. The key set is served
+// bare, as JWKS clients expect, and may be cached briefly.
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys)
+}
+
+// GetMetadata returns the metadata of the user owning the bearer token
+func (h *AuthHandler) GetMetadata(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	metadata, err := h.authService.GetUserMetadata(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metadata,
+	})
+}
+
+// UpdateMetadata merges the body into the metadata of the user owning the
+// bearer token; keys set to null are removed
+func (h *AuthHandler) UpdateMetadata(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	var patch models.JSONB
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.Metadata("metadata", patch); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	metadata, err := h.authService.UpdateUserMetadata(c.Request.Context(), claims.UserID, patch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metadata,
+	})
+}
+
+// ListSessions returns the active sessions of the bearer token's user
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"sessions": sessions,
+	})
+}
+
+// RevokeSession signs out one of the bearer token's user's sessions
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid session ID")
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), claims.UserID, sessionID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session revoked",
+	})
+}
+
+// claims validates the bearer token and writes the error response if it is
+// missing or invalid
+func (h *AuthHandler) claims(c *gin.Context) (*utils.JWTClaims, bool) {
+	return bearerClaims(c, h.authService)
+}
+
+// bearerClaims is claims for handlers outside AuthHandler
+func bearerClaims(c *gin.Context, authService *services.AuthService) (*utils.JWTClaims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		respondError(c, apperrors.Catalog(apperrors.ReasonTokenRequired))
+		return nil, false
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	claims, err := authService.ValidateToken(c.Request.Context(), token)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return claims, true
+}
+
+// SetupMFA starts TOTP enrollment for the bearer token's user and returns
+// the secret and otpauth URI for an authenticator app
+func (h *AuthHandler) SetupMFA(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	setup, err := h.authService.SetupMFA(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    setup,
+	})
+}
+
+// EnableMFA confirms enrollment with a code from the authenticator
+func (h *AuthHandler) EnableMFA(c *gin.Context) {
+	claims, ok := h.claims(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.authService.EnableMFA(c.Request.Context(), claims.UserID, req.Code); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "MFA enabled",
+	})
+}
+
+// VerifyMFA answers the MFA challenge for the current session and returns
+// an access token that carries it
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		respondError(c, apperrors.Catalog(apperrors.ReasonTokenRequired))
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	accessToken, err := h.authService.VerifyMFA(c.Request.Context(), token, req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"accessToken": accessToken,
+	})
+}
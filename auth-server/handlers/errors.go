@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/ratelimit"
+)
+
+// respondError writes err like ginerrors.Respond, adding Retry-After to
+// rate-limited responses
+func respondError(c *gin.Context, err error) {
+	ginerrors.Respond(c, err, retryAfter)
+}
+
+func retryAfter(c *gin.Context, err error) {
+	if wait, ok := ratelimit.RetryAfter(err); ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	}
+}
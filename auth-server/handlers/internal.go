@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"r2s/auth-server/services"
+)
+
+// InternalHandler issues tokens services call each other with. It is not
+// routed through the gateway.
+type InternalHandler struct {
+	internalService *services.InternalAuthService
+}
+
+func NewInternalHandler(internalService *services.InternalAuthService) *InternalHandler {
+	return &InternalHandler{
+		internalService: internalService,
+	}
+}
+
+// IssueToken handles POST /auth/internal/token with the client's
+// credentials in HTTP Basic auth
+func (h *InternalHandler) IssueToken(c *gin.Context) {
+	id, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		c.Header("WWW-Authenticate", `Basic realm="internal"`)
+		respondError(c, services.ErrInvalidClient)
+		return
+	}
+
+	token, expiresAt, err := h.internalService.IssueToken(id, secret)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"token":     token,
+		"expiresAt": expiresAt,
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"r2s/auth-server/services"
+	"r2s/pkg/errors/ginerrors"
+)
+
+// maxKYCUpload bounds a whole submission: the documents plus form overhead
+const maxKYCUpload = services.MaxKYCDocuments*services.MaxKYCDocumentSize + 1<<20
+
+type KYCHandler struct {
+	kycService  *services.KYCService
+	authService *services.AuthService
+}
+
+func NewKYCHandler(kycService *services.KYCService, authService *services.AuthService) *KYCHandler {
+	return &KYCHandler{
+		kycService:  kycService,
+		authService: authService,
+	}
+}
+
+// Submit handles POST /auth/kyc/applications, a multipart form with the
+// requested tier, the documentType and up to four documents files
+func (h *KYCHandler) Submit(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxKYCUpload)
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ginerrors.BadRequest(c, "Documents are too large")
+			return
+		}
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	tier, err := strconv.Atoi(c.PostForm("tier"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid tier")
+		return
+	}
+
+	files := form.File["documents"]
+	docs := make([]services.KYCDocument, 0, len(files))
+	for _, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			ginerrors.BadRequest(c, "Invalid document")
+			return
+		}
+		defer f.Close()
+		docs = append(docs, services.KYCDocument{Content: f, Size: fh.Size})
+	}
+
+	app, err := h.kycService.Submit(c.Request.Context(), claims.UserID, tier, c.PostForm("documentType"), docs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":     true,
+		"application": app,
+	})
+}
+
+// Status handles GET /auth/kyc/status
+func (h *KYCHandler) Status(c *gin.Context) {
+	claims, ok := bearerClaims(c, h.authService)
+	if !ok {
+		return
+	}
+
+	status, err := h.kycService.Status(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"kycTier":     status.Tier,
+		"application": status.Application,
+	})
+}
+
+// HandleWebhook handles POST /auth/kyc/webhook from the KYC provider
+func (h *KYCHandler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.kycService.HandleWebhook(c.Request.Context(), body, c.GetHeader("X-Payload-Digest")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
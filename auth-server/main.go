@@ -1,81 +1,177 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
-	"os"
-	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"r2s/auth-server/handlers"
 	"r2s/auth-server/repository"
 	"r2s/auth-server/services"
+	"r2s/pkg/clock"
+	"r2s/pkg/config"
+	"r2s/pkg/diag"
 	"r2s/pkg/database"
+	"r2s/pkg/errreport"
+	"r2s/pkg/errreport/ginreport"
+	"r2s/pkg/health"
+	"r2s/pkg/jwks"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/mail"
+	"r2s/pkg/metrics/ginmetrics"
+	"r2s/pkg/objectstore"
+	"r2s/pkg/referral"
+	"r2s/pkg/server"
+	"r2s/pkg/tracing"
+	"r2s/pkg/tracing/ethtrace"
+	"r2s/pkg/tracing/gintrace"
 	"r2s/pkg/utils"
 )
 
 func main() {
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	envErr := godotenv.Load()
+
+	// Load and validate configuration (env, CONFIG_FILE, defaults)
+	var cfg Config
+	config.MustLoad(&cfg)
+
+	// Structured logging (LOG_LEVEL, LOG_FORMAT)
+	logger.Init("auth-server", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
 	}
 
-	// Database configuration
-	dbConfig := database.Config{
-		Host:         os.Getenv("DB_HOST"),
-		Port:         5432,
-		User:         os.Getenv("DB_USER"),
-		Password:     os.Getenv("DB_PASSWORD"),
-		Database:     os.Getenv("DB_NAME"),
-		MaxOpenConns: 25,
-		MaxIdleConns: 10,
-		MaxLifetime:  5 * time.Minute,
+	// Error reporting (Sentry when SENTRY_DSN is set)
+	if err := errreport.Init("auth-server", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
 	}
+	defer errreport.Flush()
+
+	// Tracing (spans are exported when OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.Init(context.Background(), "auth-server", cfg.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", "error", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database
-	db, err := database.NewDB(dbConfig)
+	db, err := database.NewDB(cfg.Database)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logger.Fatal("Failed to connect to database", "error", err)
 	}
 	defer db.Close()
 
-	// Redis configuration
-	redisConfig := database.RedisConfig{
-		Host:     os.Getenv("REDIS_HOST"),
-		Port:     6379,
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
-		PoolSize: 10,
-	}
+	// Redis configuration (single node, Sentinel or Cluster via REDIS_MODE)
+	redisConfig := database.RedisConfigFromEnv()
 
 	// Initialize Redis
 	redis, err := database.NewRedisClient(redisConfig)
 	if err != nil {
-		log.Fatal("Failed to connect to Redis:", err)
+		logger.Fatal("Failed to connect to Redis", "error", err)
 	}
 	defer redis.Close()
 
-	// Initialize JWT Manager
-	jwtManager := utils.NewJWTManager(
-		os.Getenv("JWT_SECRET"),
-		os.Getenv("JWT_REFRESH_SECRET"),
-		15*time.Minute,
-		7*24*time.Hour,
+	// Initialize JWT Manager; retired keys verify tokens they signed until
+	// those expire
+	signingKey, err := jwks.LoadSigningKey(cfg.JWTSigningKey, cfg.JWTSigningKeyFile)
+	if err != nil {
+		logger.Fatal("Failed to load JWT signing key", "error", err)
+	}
+	signingKeys := []*jwks.SigningKey{signingKey}
+	for _, path := range cfg.JWTPreviousSigningKeyFiles {
+		key, err := jwks.LoadSigningKey("", path)
+		if err != nil {
+			logger.Fatal("Failed to load previous JWT signing key", "path", path, "error", err)
+		}
+		signingKeys = append(signingKeys, key)
+	}
+	clk := clock.New()
+	jwtManager, err := utils.NewJWTManager(utils.JWTConfig{
+		SigningKeys:     signingKeys,
+		RefreshSecrets:  append([]string{cfg.JWTRefreshSecret}, cfg.JWTPreviousRefreshSecrets...),
+		AccessDuration:  cfg.AccessTokenTTL,
+		RefreshDuration: cfg.RefreshTokenTTL,
+	}, clk)
+	if err != nil {
+		logger.Fatal("Failed to initialize JWT manager", "error", err)
+	}
+	slog.Info("Signing access tokens",
+		"kid", signingKey.ID,
+		"alg", signingKey.Method.Alg(),
+		"retired_keys", len(cfg.JWTPreviousSigningKeyFiles),
+		"retired_refresh_secrets", len(cfg.JWTPreviousRefreshSecrets),
 	)
 
+	// Contract wallet (EIP-1271) sign-ins are verified on-chain; without an
+	// RPC URL only EOA signatures are accepted
+	var chain bind.ContractCaller
+	if cfg.RPCURL != "" {
+		client, err := ethtrace.Dial(context.Background(), cfg.RPCURL)
+		if err != nil {
+			logger.Fatal("Failed to connect to blockchain", "error", err)
+		}
+		defer client.Close()
+		chain = client
+	}
+
+	// KYC documents (OBJECT_STORE_DRIVER s3 or local)
+	kycStore, err := objectstore.New(cfg.ObjectStore)
+	if err != nil {
+		logger.Fatal("Failed to initialize object storage", "error", err)
+	}
+
+	// Services allowed to fetch internal tokens
+	internalClients, err := services.ParseInternalClients(cfg.InternalClients)
+	if err != nil {
+		logger.Fatal("Invalid INTERNAL_CLIENTS", "error", err)
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
+	kycRepo := repository.NewKYCRepository(db)
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, sessionRepo, redis, jwtManager)
+	loginLimits := services.LoginLimits{
+		PerIP:            cfg.LoginRatePerIP,
+		PerAddress:       cfg.LoginRatePerAddress,
+		LockoutThreshold: cfg.LockoutThreshold,
+		LockoutMax:       cfg.LockoutMax,
+	}
+	nonceChallenge, err := services.NewNonceChallenge(services.ChallengeConfig{
+		Mode:            cfg.NonceChallenge,
+		PoWDifficulty:   cfg.PoWDifficulty,
+		PoWSecret:       cfg.PoWSecret,
+		TurnstileSecret: cfg.TurnstileSecret,
+	}, redis, clk)
+	if err != nil {
+		logger.Fatal("Failed to initialize nonce challenge", "error", err)
+	}
+	// Sign-up only applies referral codes; core-server credits the bonuses
+	referralStore := referral.NewStore(db, referral.Config{}, clk)
+	authService := services.NewAuthService(userRepo, sessionRepo, redis, jwtManager, cfg.NonceBytes, cfg.SignInDomain, loginLimits, clk, chain, nonceChallenge, referralStore)
+	kycService := services.NewKYCService(db, kycRepo, userRepo, kycStore, cfg.KYCWebhookSecret, clk)
+	lineVerifier := services.NewLineVerifier(cfg.LineChannelID)
+	if lineVerifier == nil {
+		slog.Warn("LINE_CHANNEL_ID is not set, account recovery is disabled")
+	}
+	internalService := services.NewInternalAuthService(internalClients, jwtManager)
+	accountService := services.NewAccountService(authService, userRepo, sessionRepo, redis, mail.New(cfg.Mail), lineVerifier, cfg.EmailVerifyURL, clk)
 
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
+	authHandler := handlers.NewAuthHandler(authService, jwtManager.JWKS(), cfg.CountryHeader)
+	kycHandler := handlers.NewKYCHandler(kycService, authService)
+	accountHandler := handlers.NewAccountHandler(accountService, authHandler)
+	internalHandler := handlers.NewInternalHandler(internalService)
 
 	// Setup router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gintrace.Middleware(), ginmetrics.Middleware(), ginlog.Middleware(), ginreport.Middleware())
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -85,25 +181,71 @@ func main() {
 		})
 	})
 
+	// Liveness (process up) and readiness (Postgres and Redis reachable)
+	checker := health.NewChecker("auth-server")
+	checker.Add("postgres", health.Database(db))
+	checker.Add("redis", health.Redis(redis.UniversalClient))
+	router.GET("/live", gin.WrapH(checker.LiveHandler()))
+	router.GET("/ready", gin.WrapH(checker.ReadyHandler()))
+
+	// Runtime log level (GET/PUT {"level":"debug"})
+	ginlog.RegisterLevelEndpoint(router, "/admin/log-level")
+
+	// Prometheus metrics (HTTP, DB, Redis, domain counters, Go runtime)
+	ginmetrics.Register(router)
+
 	// Auth routes
 	authGroup := router.Group("/auth")
 	{
+		authGroup.GET("/nonce/challenge", authHandler.GetNonceChallenge)
 		authGroup.GET("/nonce", authHandler.GetNonce)
 		authGroup.POST("/verify", authHandler.VerifySignature)
 		authGroup.POST("/line", authHandler.LineAuth)
 		authGroup.POST("/refresh", authHandler.RefreshToken)
 		authGroup.POST("/logout", authHandler.Logout)
 		authGroup.GET("/validate", authHandler.ValidateToken)
+		// Public keys of access tokens, for local validation elsewhere
+		authGroup.GET("/.well-known/jwks.json", authHandler.JWKS)
+		authGroup.GET("/me/metadata", authHandler.GetMetadata)
+		authGroup.PATCH("/me/metadata", authHandler.UpdateMetadata)
+
+		// Signed-in devices of the bearer token's user
+		authGroup.GET("/sessions", authHandler.ListSessions)
+		authGroup.DELETE("/sessions/:id", authHandler.RevokeSession)
+
+		// KYC tier upgrades; the provider reports reviews to the webhook
+		authGroup.POST("/kyc/applications", kycHandler.Submit)
+		authGroup.GET("/kyc/status", kycHandler.Status)
+		authGroup.POST("/kyc/webhook", kycHandler.HandleWebhook)
+
+		// Email verification, and re-linking a new wallet with the verified
+		// email and the linked LINE account
+		authGroup.POST("/email", accountHandler.SetEmail)
+		authGroup.POST("/email/verify", accountHandler.VerifyEmail)
+		authGroup.POST("/recovery/start", accountHandler.StartRecovery)
+		authGroup.POST("/recovery/complete", accountHandler.CompleteRecovery)
+
+		// Internal tokens for service-to-service calls (pkg/svcauth)
+		authGroup.POST("/internal/token", internalHandler.IssueToken)
+
+		// TOTP MFA: enroll, then step up a session (required for /api/admin)
+		authGroup.POST("/mfa/setup", authHandler.SetupMFA)
+		authGroup.POST("/mfa/enable", authHandler.EnableMFA)
+		authGroup.POST("/mfa/verify", authHandler.VerifyMFA)
 	}
 
-	// Start server
-	port := os.Getenv("AUTH_SERVER_PORT")
-	if port == "" {
-		port = "3002"
+	// Diagnostics (pprof, goroutine dumps, GC stats) on an internal listener
+	if err := diag.Start(cfg.DebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
 	}
 
-	log.Printf("Auth server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Start server; SIGTERM drains in-flight requests before the deferred
+	// connections are closed
+	ctx, stop := server.Context()
+	defer stop()
+	slog.Info("Auth server starting", "port", cfg.Port)
+	if err := server.ServeHTTP(ctx, ":"+cfg.Port, router, cfg.Server); err != nil {
+		logger.Fatal("Server failed", "error", err)
 	}
+	slog.Info("Auth server stopped")
 }
\ No newline at end of file
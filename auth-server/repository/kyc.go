@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const kycColumns = `
+	id, user_id, requested_tier, document_type, document_keys, status,
+	reject_reason, created_at, updated_at, reviewed_at`
+
+// ErrPendingKYC is returned by Create when the user already has an
+// application under review
+var ErrPendingKYC = errors.New("user already has a pending KYC application")
+
+type KYCRepository struct {
+	db *database.DB
+}
+
+func NewKYCRepository(db *database.DB) *KYCRepository {
+	return &KYCRepository{db: db}
+}
+
+func (r *KYCRepository) Create(ctx context.Context, app *models.KYCApplication) error {
+	query := `
+		INSERT INTO kyc_applications (
+			id, user_id, requested_tier, document_type, document_keys,
+			status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		app.ID,
+		app.UserID,
+		app.RequestedTier,
+		app.DocumentType,
+		app.DocumentKeys,
+		app.Status,
+		app.CreatedAt,
+		app.UpdatedAt,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrPendingKYC
+	}
+	return err
+}
+
+// Latest returns the user's most recent application, or nil
+func (r *KYCRepository) Latest(ctx context.Context, userID uuid.UUID) (*models.KYCApplication, error) {
+	var app models.KYCApplication
+	query := `SELECT ` + kycColumns + `
+		FROM kyc_applications
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &app, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &app, err
+}
+
+// FindForUpdate locks an application for review, or returns nil
+func (r *KYCRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.KYCApplication, error) {
+	var app models.KYCApplication
+	query := `SELECT ` + kycColumns + `
+		FROM kyc_applications
+		WHERE id = $1
+		FOR UPDATE`
+
+	err := tx.GetContext(ctx, &app, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &app, err
+}
+
+// Review records the provider's decision on an application
+func (r *KYCRepository) Review(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status string, rejectReason *string, at time.Time) error {
+	query := `
+		UPDATE kyc_applications
+		SET status = $2, reject_reason = $3, reviewed_at = $4, updated_at = $4
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, status, rejectReason, at)
+	return err
+}
+
+// RaiseTier sets the user's KYC tier to at least tier and returns the tier
+// it had before
+func (r *KYCRepository) RaiseTier(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, tier int) (int, error) {
+	var before int
+	query := `
+		UPDATE users u
+		SET kyc_tier = GREATEST(u.kyc_tier, $2), updated_at = NOW()
+		FROM (SELECT kyc_tier FROM users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = $1
+		RETURNING old.kyc_tier`
+
+	err := tx.GetContext(ctx, &before, query, userID, tier)
+	return before, err
+}
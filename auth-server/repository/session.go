@@ -21,10 +21,10 @@ func (r *SessionRepository) Create(session *models.Session) error {
 	query := `
 		INSERT INTO sessions (
 			id, user_id, token_hash, refresh_token_hash,
-			ip_address, user_agent, device_fingerprint,
+			ip_address, user_agent, device_fingerprint, country,
 			expires_at, refresh_expires_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 		)`
 	
 	_, err := r.db.Exec(
@@ -36,6 +36,7 @@ func (r *SessionRepository) Create(session *models.Session) error {
 		session.IPAddress,
 		session.UserAgent,
 		session.DeviceFingerprint,
+		session.Country,
 		session.ExpiresAt,
 		session.RefreshExpiresAt,
 	)
@@ -46,8 +47,9 @@ func (r *SessionRepository) FindByToken(tokenHash string) (*models.Session, erro
 	var session models.Session
 	query := `
 		SELECT id, user_id, token_hash, refresh_token_hash,
-		       ip_address, user_agent, device_fingerprint,
-		       expires_at, refresh_expires_at, created_at, last_used_at
+		       ip_address, user_agent, device_fingerprint, country,
+		       expires_at, refresh_expires_at, created_at, last_used_at,
+		       mfa_verified_at
 		FROM sessions 
 		WHERE token_hash = $1`
 	
@@ -62,8 +64,9 @@ func (r *SessionRepository) FindByRefreshToken(refreshTokenHash string) (*models
 	var session models.Session
 	query := `
 		SELECT id, user_id, token_hash, refresh_token_hash,
-		       ip_address, user_agent, device_fingerprint,
-		       expires_at, refresh_expires_at, created_at, last_used_at
+		       ip_address, user_agent, device_fingerprint, country,
+		       expires_at, refresh_expires_at, created_at, last_used_at,
+		       mfa_verified_at
 		FROM sessions 
 		WHERE refresh_token_hash = $1`
 	
@@ -77,7 +80,7 @@ func (r *SessionRepository) FindByRefreshToken(refreshTokenHash string) (*models
 func (r *SessionRepository) Update(session *models.Session) error {
 	query := `
 		UPDATE sessions 
-		SET token_hash = $2, expires_at = $3, last_used_at = $4
+		SET token_hash = $2, expires_at = $3, last_used_at = $4, mfa_verified_at = $5
 		WHERE id = $1`
 	
 	_, err := r.db.Exec(
@@ -86,10 +89,47 @@ func (r *SessionRepository) Update(session *models.Session) error {
 		session.TokenHash,
 		session.ExpiresAt,
 		session.LastUsedAt,
+		session.MFAVerifiedAt,
 	)
 	return err
 }
 
+// ListActiveByUserID returns the user's sessions that can still be
+// refreshed, most recently used first
+func (r *SessionRepository) ListActiveByUserID(userID uuid.UUID) ([]models.Session, error) {
+	sessions := []models.Session{}
+	query := `
+		SELECT id, user_id, token_hash, refresh_token_hash,
+		       ip_address, user_agent, device_fingerprint, country,
+		       expires_at, refresh_expires_at, created_at, last_used_at,
+		       mfa_verified_at
+		FROM sessions
+		WHERE user_id = $1 AND COALESCE(refresh_expires_at, expires_at) > NOW()
+		ORDER BY last_used_at DESC`
+
+	err := r.db.Select(&sessions, query, userID)
+	return sessions, err
+}
+
+// DeleteForUser deletes one of the user's sessions and returns it, or nil
+// if the user has no such session
+func (r *SessionRepository) DeleteForUser(id, userID uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	query := `
+		DELETE FROM sessions
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, token_hash, refresh_token_hash,
+		          ip_address, user_agent, device_fingerprint, country,
+		          expires_at, refresh_expires_at, created_at, last_used_at,
+		          mfa_verified_at`
+
+	err := r.db.Get(&session, query, id, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &session, err
+}
+
 func (r *SessionRepository) UpdateLastUsed(id uuid.UUID) error {
 	query := `UPDATE sessions SET last_used_at = NOW() WHERE id = $1`
 	_, err := r.db.Exec(query, id)
@@ -2,14 +2,29 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"r2s/pkg/database"
 	"r2s/pkg/models"
 )
 
+var (
+	// ErrEmailTaken is returned when another account verified the email
+	ErrEmailTaken = errors.New("email is verified by another account")
+	// ErrWalletTaken is returned when another account uses the wallet
+	ErrWalletTaken = errors.New("wallet belongs to another account")
+)
+
+// isUniqueViolation reports whether err is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
 type UserRepository struct {
 	db *database.DB
 }
@@ -22,8 +37,8 @@ func (r *UserRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
 	query := `
 		SELECT id, wallet_address, line_user_id, line_display_name, 
-		       line_picture_url, email, kyc_tier, status, 
-		       created_at, updated_at, last_login_at
+		       line_picture_url, email, email_verified_at, kyc_tier, status, role, mfa_enabled,
+		       created_at, updated_at, last_login_at, metadata
 		FROM users 
 		WHERE id = $1`
 	
@@ -38,10 +53,10 @@ func (r *UserRepository) FindByWalletAddress(address string) (*models.User, erro
 	var user models.User
 	query := `
 		SELECT id, wallet_address, line_user_id, line_display_name, 
-		       line_picture_url, email, kyc_tier, status, 
-		       created_at, updated_at, last_login_at
+		       line_picture_url, email, email_verified_at, kyc_tier, status, role, mfa_enabled,
+		       created_at, updated_at, last_login_at, metadata
 		FROM users 
-		WHERE LOWER(wallet_address) = LOWER($1)`
+		WHERE wallet_address = LOWER($1)`
 	
 	err := r.db.Get(&user, query, address)
 	if err == sql.ErrNoRows {
@@ -54,8 +69,8 @@ func (r *UserRepository) FindByLineUserID(lineUserID string) (*models.User, erro
 	var user models.User
 	query := `
 		SELECT id, wallet_address, line_user_id, line_display_name, 
-		       line_picture_url, email, kyc_tier, status, 
-		       created_at, updated_at, last_login_at
+		       line_picture_url, email, email_verified_at, kyc_tier, status, role, mfa_enabled,
+		       created_at, updated_at, last_login_at, metadata
 		FROM users 
 		WHERE line_user_id = $1`
 	
@@ -70,9 +85,9 @@ func (r *UserRepository) Create(user *models.User) error {
 	query := `
 		INSERT INTO users (
 			id, wallet_address, line_user_id, line_display_name, 
-			line_picture_url, email, kyc_tier, status
+			line_picture_url, email, kyc_tier, status, metadata
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
 		)`
 	
 	_, err := r.db.Exec(
@@ -85,6 +100,7 @@ func (r *UserRepository) Create(user *models.User) error {
 		user.Email,
 		user.KYCTier,
 		user.Status,
+		user.Metadata,
 	)
 	return err
 }
@@ -123,4 +139,104 @@ func (r *UserRepository) UpdateLineProfile(id uuid.UUID, displayName, pictureURL
 	
 	_, err := r.db.Exec(query, id, displayName, pictureURL)
 	return err
-}
\ No newline at end of file
+}
+
+// UpdateMetadata shallow-merges patch into the user's metadata and returns
+// the result, or nil if the user does not exist. Keys set to null are removed.
+func (r *UserRepository) UpdateMetadata(id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	query := `
+		UPDATE users
+		SET metadata = jsonb_strip_nulls(COALESCE(metadata, '{}'::jsonb) || $2::jsonb),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING metadata`
+
+	var metadata models.JSONB
+	err := r.db.Get(&metadata, query, id, patch)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return metadata, err
+}
+
+// MFASecret returns the user's TOTP secret, if one was set up, and whether
+// MFA is enabled
+func (r *UserRepository) MFASecret(id uuid.UUID) (*string, bool, error) {
+	var row struct {
+		Secret  *string `db:"mfa_secret"`
+		Enabled bool    `db:"mfa_enabled"`
+	}
+	err := r.db.Get(&row, `SELECT mfa_secret, mfa_enabled FROM users WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	return row.Secret, row.Enabled, err
+}
+
+// SetMFASecret stores a new TOTP secret and leaves MFA disabled until the
+// user confirms a code
+func (r *UserRepository) SetMFASecret(id uuid.UUID, secret string) error {
+	query := `UPDATE users SET mfa_secret = $2, mfa_enabled = FALSE, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(query, id, secret)
+	return err
+}
+
+// EnableMFA turns on MFA for the stored secret
+func (r *UserRepository) EnableMFA(id uuid.UUID) error {
+	query := `UPDATE users SET mfa_enabled = TRUE, updated_at = NOW() WHERE id = $1 AND mfa_secret IS NOT NULL`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// FindByVerifiedEmail returns the account that verified email, or nil
+func (r *UserRepository) FindByVerifiedEmail(email string) (*models.User, error) {
+	var user models.User
+	query := `
+		SELECT id, wallet_address, line_user_id, line_display_name,
+		       line_picture_url, email, email_verified_at, kyc_tier, status, role, mfa_enabled,
+		       created_at, updated_at, last_login_at, metadata
+		FROM users
+		WHERE LOWER(email) = LOWER($1) AND email_verified_at IS NOT NULL`
+
+	err := r.db.Get(&user, query, email)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &user, err
+}
+
+// SetEmail replaces the user's email; it stays unverified until
+// VerifyEmail
+func (r *UserRepository) SetEmail(id uuid.UUID, email string) error {
+	query := `UPDATE users SET email = $2, email_verified_at = NULL, updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(query, id, email)
+	return err
+}
+
+// VerifyEmail marks email verified if it is still the user's unverified
+// email, and reports whether it did
+func (r *UserRepository) VerifyEmail(id uuid.UUID, email string) (bool, error) {
+	query := `
+		UPDATE users SET email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND email = $2 AND email_verified_at IS NULL`
+
+	res, err := r.db.Exec(query, id, email)
+	if isUniqueViolation(err) {
+		return false, ErrEmailTaken
+	}
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// UpdateWalletAddress re-links the account to another wallet
+func (r *UserRepository) UpdateWalletAddress(id uuid.UUID, address string) error {
+	query := `UPDATE users SET wallet_address = LOWER($2), updated_at = NOW() WHERE id = $1`
+	_, err := r.db.Exec(query, id, address)
+	if isUniqueViolation(err) {
+		return ErrWalletTaken
+	}
+	return err
+}
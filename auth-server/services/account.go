@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"r2s/auth-server/repository"
+	"r2s/pkg/address"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	mailer "r2s/pkg/mail"
+	"r2s/pkg/onetime"
+	"r2s/pkg/ratelimit"
+)
+
+const (
+	// emailVerifyTTL is how long a verification link works
+	emailVerifyTTL = 24 * time.Hour
+	// recoveryTTL is how long a recovery code works
+	recoveryTTL = 30 * time.Minute
+	// recoveryPerEmail bounds recovery mails per address and hour
+	recoveryPerEmail = 3
+	// mailTimeout bounds sending one message
+	mailTimeout = 10 * time.Second
+)
+
+var (
+	ErrInvalidEmail        = apperrors.Catalog(apperrors.ReasonInvalidEmail)
+	ErrEmailChanged        = apperrors.Catalog(apperrors.ReasonEmailChanged)
+	ErrEmailTaken          = apperrors.Catalog(apperrors.ReasonEmailTaken)
+	ErrWalletTaken         = apperrors.Catalog(apperrors.ReasonWalletTaken)
+	ErrRecoveryUnavailable = apperrors.Catalog(apperrors.ReasonRecoveryUnavailable)
+	ErrLineMismatch        = apperrors.Catalog(apperrors.ReasonLineMismatch)
+)
+
+// RecoveryInput re-links an account to a new wallet. Token is the emailed
+// recovery code; LineIDToken proves the linked LINE account; the wallet
+// fields are a signed nonce from GET /auth/nonce for the new address.
+type RecoveryInput struct {
+	Token       string
+	LineIDToken string
+	Address     string
+	Signature   string
+	Message     string
+}
+
+// AccountService verifies email addresses and lets users who lost their
+// wallet recover their account with their verified email and linked LINE
+// account
+type AccountService struct {
+	auth           *AuthService
+	userRepo       *repository.UserRepository
+	sessionRepo    *repository.SessionRepository
+	mail           mailer.Sender
+	line           *LineVerifier
+	emailTokens    *onetime.Tokens
+	recoveryTokens *onetime.Tokens
+	recoveryLimit  *ratelimit.Limiter
+	// verifyURL is the app page verification links point at
+	verifyURL string
+}
+
+func NewAccountService(
+	auth *AuthService,
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	redis *database.RedisClient,
+	sender mailer.Sender,
+	line *LineVerifier,
+	verifyURL string,
+	clk clock.Clock,
+) *AccountService {
+	client := redis.UniversalClient
+	return &AccountService{
+		auth:           auth,
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		mail:           sender,
+		line:           line,
+		emailTokens:    onetime.New(client, "email_verify", emailVerifyTTL, onetime.WithClock(clk)),
+		recoveryTokens: onetime.New(client, "account_recovery", recoveryTTL, onetime.WithClock(clk)),
+		recoveryLimit:  ratelimit.NewLimiter(client, "recovery:email", recoveryPerEmail, time.Hour),
+		verifyURL:      verifyURL,
+	}
+}
+
+// SetEmail stores the user's email unverified and mails a verification link
+func (s *AccountService) SetEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.SetEmail(userID, email); err != nil {
+		return fmt.Errorf("failed to set email: %w", err)
+	}
+
+	// The subject pins the address, so a link stops working once the email
+	// is changed again
+	token, _, err := s.emailTokens.Issue(ctx, userID.String()+" "+email)
+	if err != nil {
+		return err
+	}
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	return s.send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: "Open this link to verify your email address for Reserve to Save:\n\n" + link +
+			"\n\nThe link expires in 24 hours. If you did not add this address, ignore this message.",
+	})
+}
+
+// VerifyEmail redeems a verification link
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) error {
+	subject, err := s.emailTokens.Consume(ctx, token)
+	if err != nil {
+		return err
+	}
+	id, email, ok := strings.Cut(subject, " ")
+	userID, err := uuid.Parse(id)
+	if !ok || err != nil {
+		return onetime.ErrInvalidToken
+	}
+
+	verified, err := s.userRepo.VerifyEmail(userID, email)
+	if errors.Is(err, repository.ErrEmailTaken) {
+		return ErrEmailTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	if !verified {
+		return ErrEmailChanged
+	}
+	return nil
+}
+
+// StartRecovery mails a recovery code to email if an account verified it
+// and has LINE linked. It returns nil either way, so callers cannot probe
+// which addresses have accounts.
+func (s *AccountService) StartRecovery(ctx context.Context, email string) error {
+	if s.line == nil {
+		return ErrRecoveryUnavailable
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	if err := s.recoveryLimit.Allow(ctx, email); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByVerifiedEmail(email)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil || user.LineUserID == nil {
+		slog.Info("Account recovery requested for an unrecoverable email")
+		return nil
+	}
+
+	token, _, err := s.recoveryTokens.Issue(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Recover your account",
+		Body: "Use this code to link a new wallet to your Reserve to Save account:\n\n" + token +
+			"\n\nYou will also be asked to sign in with LINE. The code expires in 30 minutes. " +
+			"If you did not ask to recover your account, ignore this message.",
+	})
+}
+
+// CompleteRecovery checks the recovery code, the LINE account and the new
+// wallet's signature, re-links the account to the new wallet and ends all
+// of its sessions. The user then signs in with the new wallet.
+func (s *AccountService) CompleteRecovery(ctx context.Context, in RecoveryInput, client ClientInfo) error {
+	if s.line == nil {
+		return ErrRecoveryUnavailable
+	}
+	subject, err := s.recoveryTokens.Consume(ctx, in.Token)
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return onetime.ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil || user.LineUserID == nil {
+		return onetime.ErrInvalidToken
+	}
+
+	lineUserID, err := s.line.VerifyIDToken(ctx, in.LineIDToken)
+	if err != nil {
+		return err
+	}
+	if lineUserID != *user.LineUserID {
+		return ErrLineMismatch
+	}
+	if err := s.auth.checkWalletProof(ctx, in.Address, in.Signature, in.Message, client); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdateWalletAddress(userID, in.Address); err != nil {
+		if errors.Is(err, repository.ErrWalletTaken) {
+			return ErrWalletTaken
+		}
+		return fmt.Errorf("failed to re-link wallet: %w", err)
+	}
+	if err := s.sessionRepo.DeleteByUserID(userID); err != nil {
+		return fmt.Errorf("failed to end sessions: %w", err)
+	}
+	slog.Warn("Account recovered with a new wallet",
+		"user_id", userID,
+		"old_wallet", address.Display(user.WalletAddress),
+		"new_wallet", address.Display(in.Address),
+		"ip", client.IPAddress,
+	)
+	return nil
+}
+
+func (s *AccountService) send(ctx context.Context, msg mailer.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+	if err := s.mail.Send(ctx, msg); err != nil {
+		return apperrors.Unavailable(err, "failed to send email")
+	}
+	return nil
+}
+
+// normalizeEmail accepts a bare address and returns it in lower case
+func normalizeEmail(email string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || parsed.Name != "" || len(parsed.Address) > 254 {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(parsed.Address), nil
+}
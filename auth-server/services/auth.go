@@ -1,25 +1,47 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"log/slog"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/google/uuid"
 	"r2s/auth-server/repository"
+	"r2s/pkg/clock"
 	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/jwks"
 	"r2s/pkg/models"
+	"r2s/pkg/referral"
 	"r2s/pkg/utils"
 )
 
+var ErrAccountSuspended = apperrors.Catalog(apperrors.ReasonAccountSuspended)
+
+// contractSignatureTimeout bounds the on-chain EIP-1271 check of a sign-in
+const contractSignatureTimeout = 5 * time.Second
+
 type AuthService struct {
 	userRepo    *repository.UserRepository
 	sessionRepo *repository.SessionRepository
 	redis       *database.RedisClient
 	jwtManager  *utils.JWTManager
+	nonceBytes  int
+	login       *loginGuard
+	clock       clock.Clock
+	// chain verifies contract wallet signatures; nil accepts EOAs only
+	chain bind.ContractCaller
+	// domain is the site named in sign-in messages
+	domain string
+	// challenge must be passed before a nonce is issued; nil when off
+	challenge NonceChallenge
+	// referrals applies the referral code a new user signs up with
+	referrals *referral.Store
 }
 
 type Tokens struct {
@@ -32,31 +54,68 @@ func NewAuthService(
 	sessionRepo *repository.SessionRepository,
 	redis *database.RedisClient,
 	jwtManager *utils.JWTManager,
+	nonceBytes int,
+	domain string,
+	limits LoginLimits,
+	clk clock.Clock,
+	chain bind.ContractCaller,
+	challenge NonceChallenge,
+	referrals *referral.Store,
 ) *AuthService {
+	if nonceBytes == 0 {
+		nonceBytes = utils.DefaultNonceBytes
+	}
 	return &AuthService{
 		userRepo:    userRepo,
 		sessionRepo: sessionRepo,
 		redis:       redis,
 		jwtManager:  jwtManager,
+		nonceBytes:  nonceBytes,
+		login:       newLoginGuard(redis, limits),
+		clock:       clock.OrSystem(clk),
+		chain:       chain,
+		domain:      domain,
+		challenge:   challenge,
+		referrals:   referrals,
 	}
 }
 
-// GenerateNonce generates a nonce for wallet authentication
-func (s *AuthService) GenerateNonce(address, chainID string) (string, string, string, string, error) {
+// NonceChallenge hands out the challenge GenerateNonce requires
+func (s *AuthService) NonceChallenge() (*Challenge, error) {
+	if s.challenge == nil {
+		return &Challenge{Mode: ChallengeOff}, nil
+	}
+	return s.challenge.issue()
+}
+
+// GenerateNonce generates a nonce for wallet authentication. With a nonce
+// challenge configured, proof must pass it before the nonce is stored.
+func (s *AuthService) GenerateNonce(ctx context.Context, address, chainID string, proof ChallengeProof) (string, string, string, string, error) {
 	// Validate address
 	if !utils.IsValidAddress(address) {
-		return "", "", "", "", errors.New("invalid wallet address")
+		return "", "", "", "", apperrors.Catalog(apperrors.ReasonInvalidWallet)
+	}
+	if err := s.login.allow(ctx, proof.IPAddress, address); err != nil {
+		return "", "", "", "", err
+	}
+	if s.challenge != nil {
+		if err := s.challenge.verify(ctx, proof); err != nil {
+			return "", "", "", "", err
+		}
 	}
 
 	// Generate nonce
-	nonce := utils.GenerateNonce()
+	nonce, err := utils.GenerateNonceSize(s.nonceBytes)
+	if err != nil {
+		return "", "", "", "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
 	requestID := uuid.New().String()
-	issuedAt := time.Now().Format(time.RFC3339)
-	expiresAt := time.Now().Add(6 * time.Minute).Format(time.RFC3339)
+	now := s.clock.Now()
+	issuedAt := now.Format(time.RFC3339)
+	expiresAt := now.Add(6 * time.Minute).Format(time.RFC3339)
 
 	// Create message
-	domain := "https://r2s.io"
-	message := utils.CreateSignMessage(domain, address, chainID, nonce, issuedAt, expiresAt, requestID)
+	message := utils.CreateSignMessage(s.domain, address, chainID, nonce, issuedAt, expiresAt, requestID)
 
 	// Store nonce in Redis
 	nonceHash := utils.HashString(nonce)
@@ -68,84 +127,53 @@ func (s *AuthService) GenerateNonce(address, chainID string) (string, string, st
 	}
 	
 	nonceJSON, _ := json.Marshal(nonceData)
-	if err := s.redis.SetWithExpiry("nonce:"+nonceHash, string(nonceJSON), 6*time.Minute); err != nil {
+	if err := s.redis.SetWithExpiry(ctx, "nonce:"+nonceHash, string(nonceJSON), 6*time.Minute); err != nil {
 		return "", "", "", "", fmt.Errorf("failed to store nonce: %w", err)
 	}
 
 	return nonce, message, requestID, expiresAt, nil
 }
 
-// VerifySignature verifies wallet signature and issues JWT
-func (s *AuthService) VerifySignature(address, signature, message, requestID, ipAddress, userAgent string) (*Tokens, *models.User, error) {
-	// Extract nonce from message
-	nonceRegex := regexp.MustCompile(`Nonce: ([a-f0-9]{32})`)
-	matches := nonceRegex.FindStringSubmatch(message)
-	if len(matches) != 2 {
-		return nil, nil, errors.New("invalid message format")
-	}
-	nonce := matches[1]
-
-	// Get nonce data from Redis
-	nonceHash := utils.HashString(nonce)
-	nonceDataStr, err := s.redis.GetString("nonce:" + nonceHash)
-	if err != nil {
-		return nil, nil, errors.New("invalid or expired nonce")
-	}
-
-	var nonceData map[string]string
-	if err := json.Unmarshal([]byte(nonceDataStr), &nonceData); err != nil {
-		return nil, nil, errors.New("invalid nonce data")
+// VerifySignature verifies wallet signature and issues JWT. The session is
+// bound to the client's device fingerprint and country. A new user is
+// referred by referralCode when it is set; a code that cannot be applied
+// does not fail the sign-in.
+func (s *AuthService) VerifySignature(ctx context.Context, address, signature, message, requestID, referralCode string, client ClientInfo) (*Tokens, *models.User, error) {
+	if err := s.checkWalletProof(ctx, address, signature, message, client); err != nil {
+		return nil, nil, err
 	}
 
-	// Validate nonce data
-	if strings.ToLower(nonceData["address"]) != strings.ToLower(address) {
-		return nil, nil, errors.New("address mismatch")
-	}
-
-	expiresAt, _ := time.Parse(time.RFC3339, nonceData["expiresAt"])
-	if time.Now().After(expiresAt) {
-		return nil, nil, errors.New("nonce expired")
-	}
-
-	// Verify signature
-	valid, err := utils.VerifySignature(message, signature, address)
-	if err != nil || !valid {
-		return nil, nil, errors.New("invalid signature")
-	}
-
-	// Delete nonce (one-time use)
-	s.redis.Del("nonce:" + nonceHash)
-
 	// Get or create user
 	user, err := s.userRepo.FindByWalletAddress(strings.ToLower(address))
 	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	created := user == nil
+	if created {
 		// Create new user
 		user = &models.User{
 			ID:            uuid.New(),
 			WalletAddress: strings.ToLower(address),
 			KYCTier:       0,
-			Status:        "active",
-			CreatedAt:     time.Now(),
-			UpdatedAt:     time.Now(),
+			Status:        models.UserActive,
+			Role:          models.RoleUser,
+			CreatedAt:     s.clock.Now(),
+			UpdatedAt:     s.clock.Now(),
 		}
 		if err := s.userRepo.Create(user); err != nil {
 			return nil, nil, fmt.Errorf("failed to create user: %w", err)
 		}
 	} else {
+		if user.Status == models.UserSuspended {
+			return nil, nil, ErrAccountSuspended
+		}
 		// Update last login
 		s.userRepo.UpdateLastLogin(user.ID)
 	}
 
 	// Generate tokens
 	sessionID := uuid.New()
-	claims := &utils.JWTClaims{
-		UserID:    user.ID,
-		Address:   user.WalletAddress,
-		KYCTier:   user.KYCTier,
-		SessionID: sessionID,
-	}
-
-	accessToken, err := s.jwtManager.GenerateAccessToken(claims)
+	accessToken, err := s.jwtManager.GenerateAccessToken(accessClaims(user, sessionID, false))
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -161,18 +189,31 @@ func (s *AuthService) VerifySignature(address, signature, message, requestID, ip
 		UserID:           user.ID,
 		TokenHash:        utils.HashString(accessToken),
 		RefreshTokenHash: stringPtr(utils.HashString(refreshToken)),
-		IPAddress:        &ipAddress,
-		UserAgent:        &userAgent,
-		ExpiresAt:        time.Now().Add(15 * time.Minute),
-		RefreshExpiresAt: timePtr(time.Now().Add(7 * 24 * time.Hour)),
-		CreatedAt:        time.Now(),
-		LastUsedAt:       time.Now(),
+		IPAddress:        &client.IPAddress,
+		UserAgent:        &client.UserAgent,
+		ExpiresAt:        s.clock.Now().Add(s.jwtManager.AccessDuration()),
+		RefreshExpiresAt: timePtr(s.clock.Now().Add(s.jwtManager.RefreshDuration())),
+		CreatedAt:        s.clock.Now(),
+		LastUsedAt:       s.clock.Now(),
+	}
+	if fp := client.fingerprint(); fp != "" {
+		session.DeviceFingerprint = &fp
+	}
+	if country := client.country(); country != "" {
+		session.Country = &country
 	}
 	
 	if err := s.sessionRepo.Create(session); err != nil {
 		return nil, nil, fmt.Errorf("failed to create session: %w", err)
 	}
 
+	// Applied after the session is stored so the device check sees it
+	if created && referralCode != "" {
+		if _, err := s.referrals.Apply(ctx, user.ID, referralCode); err != nil {
+			slog.Warn("Referral code not applied at sign-up", "user_id", user.ID, "error", err)
+		}
+	}
+
 	return &Tokens{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -184,47 +225,50 @@ func (s *AuthService) LineAuth(idToken, accessToken, ipAddress, userAgent string
 	// TODO: Implement LINE token verification
 	// This would involve calling LINE API to verify the tokens
 	// For now, returning an error
-	return "", nil, errors.New("LINE authentication not implemented")
+	return "", nil, apperrors.New(apperrors.CodeUnimplemented, "LINE authentication not implemented")
 }
 
-// RefreshToken generates a new access token from refresh token
-func (s *AuthService) RefreshToken(refreshToken string) (string, error) {
+// RefreshToken generates a new access token from refresh token. A refresh
+// from another device or country than the session's ends the session.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, client ClientInfo) (string, error) {
 	// Verify refresh token
 	claims, err := s.jwtManager.VerifyRefreshToken(refreshToken)
 	if err != nil {
-		return "", errors.New("invalid refresh token")
+		return "", apperrors.Catalog(apperrors.ReasonInvalidRefreshToken)
 	}
 
 	// Get session
 	refreshTokenHash := utils.HashString(refreshToken)
 	session, err := s.sessionRepo.FindByRefreshToken(refreshTokenHash)
-	if err != nil || session.UserID != claims.UserID {
-		return "", errors.New("invalid session")
+	if err != nil || session == nil || session.UserID != claims.UserID {
+		return "", apperrors.Catalog(apperrors.ReasonInvalidSession)
+	}
+	if reason := sessionAnomaly(session, client); reason != "" {
+		if err := s.endAnomalousSession(ctx, session, client, reason); err != nil {
+			return "", err
+		}
+		return "", ErrReauthenticationRequired
 	}
 
 	// Get user
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return "", errors.New("user not found")
+		return "", apperrors.Unauthorized("user not found")
 	}
-
-	// Generate new access token
-	newClaims := &utils.JWTClaims{
-		UserID:    user.ID,
-		Address:   user.WalletAddress,
-		KYCTier:   user.KYCTier,
-		SessionID: session.ID,
+	if user.Status == models.UserSuspended {
+		return "", ErrAccountSuspended
 	}
 
-	accessToken, err := s.jwtManager.GenerateAccessToken(newClaims)
+	// Generate new access token; MFA carries over for the session
+	accessToken, err := s.jwtManager.GenerateAccessToken(accessClaims(user, session.ID, session.MFAVerifiedAt != nil))
 	if err != nil {
 		return "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Update session
 	session.TokenHash = utils.HashString(accessToken)
-	session.ExpiresAt = time.Now().Add(15 * time.Minute)
-	session.LastUsedAt = time.Now()
+	session.ExpiresAt = s.clock.Now().Add(s.jwtManager.AccessDuration())
+	session.LastUsedAt = s.clock.Now()
 	
 	if err := s.sessionRepo.Update(session); err != nil {
 		return "", fmt.Errorf("failed to update session: %w", err)
@@ -233,8 +277,80 @@ func (s *AuthService) RefreshToken(refreshToken string) (string, error) {
 	return accessToken, nil
 }
 
+// checkWalletProof checks that message carries an unexpired nonce issued
+// for address and that address signed it. The nonce is spent before the
+// signature is checked, so concurrent requests cannot both use it. Attempts
+// count against the sign-in limits of the client IP and address.
+func (s *AuthService) checkWalletProof(ctx context.Context, address, signature, message string, client ClientInfo) error {
+	if err := s.login.allow(ctx, client.IPAddress, address); err != nil {
+		return err
+	}
+
+	// Extract nonce from message
+	nonceRegex := regexp.MustCompile(fmt.Sprintf(`Nonce: ([a-f0-9]{%d,})`, 2*utils.MinNonceBytes))
+	matches := nonceRegex.FindStringSubmatch(message)
+	if len(matches) != 2 {
+		return apperrors.Catalog(apperrors.ReasonInvalidMessage)
+	}
+	nonce := matches[1]
+
+	// Take the nonce from Redis; a missing one was never issued, expired or
+	// is already spent
+	nonceHash := utils.HashString(nonce)
+	nonceDataStr, err := s.redis.GetAndDelete(ctx, "nonce:"+nonceHash)
+	if err != nil {
+		return apperrors.Catalog(apperrors.ReasonInvalidNonce)
+	}
+
+	var nonceData map[string]string
+	if err := json.Unmarshal([]byte(nonceDataStr), &nonceData); err != nil {
+		return apperrors.Catalog(apperrors.ReasonInvalidNonce)
+	}
+
+	// Validate nonce data
+	if strings.ToLower(nonceData["address"]) != strings.ToLower(address) {
+		return apperrors.Catalog(apperrors.ReasonAddressMismatch)
+	}
+
+	expiresAt, _ := time.Parse(time.RFC3339, nonceData["expiresAt"])
+	if s.clock.Now().After(expiresAt) {
+		return apperrors.Catalog(apperrors.ReasonNonceExpired)
+	}
+
+	// Verify signature
+	valid, err := s.verifyWalletSignature(ctx, message, signature, address)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		s.login.failed(ctx, client.IPAddress, address)
+		return apperrors.Catalog(apperrors.ReasonInvalidSignature)
+	}
+	s.login.succeeded(ctx, address)
+	return nil
+}
+
+// verifyWalletSignature accepts a signature from the address itself (EOA)
+// or, when it is a contract wallet, one its isValidSignature approves
+func (s *AuthService) verifyWalletSignature(ctx context.Context, message, signature, address string) (bool, error) {
+	if valid, err := utils.VerifySignature(message, signature, address); err == nil && valid {
+		return true, nil
+	}
+	if s.chain == nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, contractSignatureTimeout)
+	defer cancel()
+	valid, err := utils.VerifyContractSignature(ctx, s.chain, message, signature, address)
+	if err != nil {
+		return false, apperrors.ChainUnavailable(err)
+	}
+	return valid, nil
+}
+
 // Logout invalidates the current session
-func (s *AuthService) Logout(token string) error {
+func (s *AuthService) Logout(ctx context.Context, token string) error {
 	tokenHash := utils.HashString(token)
 	
 	// Delete session
@@ -245,9 +361,9 @@ func (s *AuthService) Logout(token string) error {
 	// Add token to blacklist
 	claims, _ := s.jwtManager.VerifyAccessToken(token)
 	if claims != nil {
-		remaining := time.Until(claims.ExpiresAt.Time)
+		remaining := s.clock.Until(claims.ExpiresAt.Time)
 		if remaining > 0 {
-			s.redis.SetWithExpiry("blacklist:"+tokenHash, "1", remaining)
+			s.redis.SetWithExpiry(ctx, jwks.BlacklistKey(token), "1", remaining)
 		}
 	}
 
@@ -255,29 +371,29 @@ func (s *AuthService) Logout(token string) error {
 }
 
 // ValidateToken validates and returns token claims
-func (s *AuthService) ValidateToken(token string) (*utils.JWTClaims, error) {
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (*utils.JWTClaims, error) {
 	// Check blacklist
 	tokenHash := utils.HashString(token)
-	blacklisted, _ := s.redis.Exists("blacklist:" + tokenHash)
+	blacklisted, _ := s.redis.Exists(ctx, jwks.BlacklistKey(token))
 	if blacklisted {
-		return nil, errors.New("token has been revoked")
+		return nil, apperrors.Catalog(apperrors.ReasonTokenRevoked)
 	}
 
 	// Verify token
 	claims, err := s.jwtManager.VerifyAccessToken(token)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Catalog(apperrors.ReasonInvalidToken)
 	}
 
 	// Check session
 	session, err := s.sessionRepo.FindByToken(tokenHash)
 	if err != nil || session.UserID != claims.UserID {
-		return nil, errors.New("invalid session")
+		return nil, apperrors.Catalog(apperrors.ReasonInvalidSession)
 	}
 
 	// Check expiry
-	if time.Now().After(session.ExpiresAt) {
-		return nil, errors.New("session expired")
+	if s.clock.Now().After(session.ExpiresAt) {
+		return nil, apperrors.Catalog(apperrors.ReasonSessionExpired)
 	}
 
 	// Update last used
@@ -286,6 +402,43 @@ func (s *AuthService) ValidateToken(token string) (*utils.JWTClaims, error) {
 	return claims, nil
 }
 
+// GetUserMetadata returns the metadata of a user
+func (s *AuthService) GetUserMetadata(ctx context.Context, userID uuid.UUID) (models.JSONB, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonUserNotFound)
+	}
+	return user.Metadata, nil
+}
+
+// UpdateUserMetadata merges patch into a user's metadata; keys set to null
+// are removed
+func (s *AuthService) UpdateUserMetadata(ctx context.Context, userID uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	metadata, err := s.userRepo.UpdateMetadata(userID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user metadata: %w", err)
+	}
+	if metadata == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonUserNotFound)
+	}
+	return metadata, nil
+}
+
+// accessClaims builds the access token claims for a session
+func accessClaims(user *models.User, sessionID uuid.UUID, mfa bool) *utils.JWTClaims {
+	return &utils.JWTClaims{
+		UserID:    user.ID,
+		Address:   user.WalletAddress,
+		KYCTier:   user.KYCTier,
+		SessionID: sessionID,
+		Role:      user.Role,
+		MFA:       mfa,
+	}
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/utils"
+)
+
+// Nonce challenge modes
+const (
+	ChallengeOff       = "off"
+	ChallengePoW       = "pow"
+	ChallengeTurnstile = "turnstile"
+)
+
+const (
+	// powChallengeTTL is how long a proof-of-work challenge can be solved
+	powChallengeTTL = 2 * time.Minute
+	// maxPoWDifficulty keeps a misconfiguration from locking everyone out
+	maxPoWDifficulty   = 32
+	turnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+	turnstileTimeout   = 5 * time.Second
+)
+
+var (
+	ErrChallengeRequired = apperrors.Catalog(apperrors.ReasonChallengeRequired)
+	ErrChallengeFailed   = apperrors.Catalog(apperrors.ReasonChallengeFailed)
+)
+
+// ChallengeConfig selects the challenge GET /auth/nonce requires before it
+// stores a nonce, so bots cannot fill Redis with nonces cheaply
+type ChallengeConfig struct {
+	// Mode is off, pow (hashcash-style proof of work) or turnstile
+	// (Cloudflare Turnstile token)
+	Mode string
+	// PoWDifficulty is the leading zero bits a solution's hash needs
+	PoWDifficulty int
+	// PoWSecret signs challenges, so any auth-server instance can check them
+	PoWSecret string
+	// TurnstileSecret is the Turnstile widget's secret key
+	TurnstileSecret string
+}
+
+// ChallengeProof is what the client sends with GET /auth/nonce
+type ChallengeProof struct {
+	// Challenge and Solution answer a proof-of-work challenge
+	Challenge string
+	Solution  string
+	// CaptchaToken is a Turnstile response token
+	CaptchaToken string
+	IPAddress    string
+}
+
+// Challenge is what GET /auth/nonce/challenge hands out
+type Challenge struct {
+	Mode       string     `json:"mode"`
+	Challenge  string     `json:"challenge,omitempty"`
+	Difficulty int        `json:"difficulty,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// NonceChallenge guards nonce issuance
+type NonceChallenge interface {
+	issue() (*Challenge, error)
+	verify(ctx context.Context, proof ChallengeProof) error
+}
+
+// NewNonceChallenge returns the challenge cfg selects, or nil when it is off
+func NewNonceChallenge(cfg ChallengeConfig, redis *database.RedisClient, clk clock.Clock) (NonceChallenge, error) {
+	switch cfg.Mode {
+	case "", ChallengeOff:
+		return nil, nil
+	case ChallengePoW:
+		if cfg.PoWSecret == "" {
+			return nil, fmt.Errorf("proof-of-work challenges need a secret")
+		}
+		if cfg.PoWDifficulty < 1 || cfg.PoWDifficulty > maxPoWDifficulty {
+			return nil, fmt.Errorf("proof-of-work difficulty must be 1 to %d bits", maxPoWDifficulty)
+		}
+		return &powChallenge{
+			secret:     []byte(cfg.PoWSecret),
+			difficulty: cfg.PoWDifficulty,
+			redis:      redis,
+			clock:      clock.OrSystem(clk),
+		}, nil
+	case ChallengeTurnstile:
+		if cfg.TurnstileSecret == "" {
+			return nil, fmt.Errorf("turnstile challenges need a secret key")
+		}
+		return &turnstileChallenge{
+			secret: cfg.TurnstileSecret,
+			url:    turnstileVerifyURL,
+			client: &http.Client{Timeout: turnstileTimeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown nonce challenge mode %q", cfg.Mode)
+	}
+}
+
+// powChallenge is a hashcash-style proof of work. Challenges are
+// "<expiry>.<difficulty>.<random>.<mac>" and need no storage until solved;
+// a solution is a string whose SHA-256 with the challenge,
+// sha256(challenge + ":" + solution), starts with difficulty zero bits.
+type powChallenge struct {
+	secret     []byte
+	difficulty int
+	redis      *database.RedisClient
+	clock      clock.Clock
+}
+
+func (p *powChallenge) issue() (*Challenge, error) {
+	random, err := utils.GenerateNonceSize(utils.MinNonceBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	expiresAt := p.clock.Now().Add(powChallengeTTL).UTC().Truncate(time.Second)
+	body := strconv.FormatInt(expiresAt.Unix(), 10) + "." + strconv.Itoa(p.difficulty) + "." + random
+	return &Challenge{
+		Mode:       ChallengePoW,
+		Challenge:  body + "." + p.mac(body),
+		Difficulty: p.difficulty,
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+func (p *powChallenge) mac(body string) string {
+	h := hmac.New(sha256.New, p.secret)
+	h.Write([]byte(body))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (p *powChallenge) verify(ctx context.Context, proof ChallengeProof) error {
+	if proof.Challenge == "" || proof.Solution == "" {
+		return ErrChallengeRequired
+	}
+	if len(proof.Solution) > 64 {
+		return ErrChallengeFailed
+	}
+
+	dot := strings.LastIndexByte(proof.Challenge, '.')
+	if dot < 0 || !hmac.Equal([]byte(proof.Challenge[dot+1:]), []byte(p.mac(proof.Challenge[:dot]))) {
+		return ErrChallengeFailed
+	}
+	parts := strings.SplitN(proof.Challenge[:dot], ".", 3)
+	if len(parts) != 3 {
+		return ErrChallengeFailed
+	}
+	expiry, err1 := strconv.ParseInt(parts[0], 10, 64)
+	difficulty, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil {
+		return ErrChallengeFailed
+	}
+	expiresAt := time.Unix(expiry, 0)
+	remaining := expiresAt.Sub(p.clock.Now())
+	// Challenges issued before the difficulty was raised no longer count
+	if remaining <= 0 || difficulty < p.difficulty {
+		return ErrChallengeRequired
+	}
+	if leadingZeroBits(sha256.Sum256([]byte(proof.Challenge+":"+proof.Solution))) < difficulty {
+		return ErrChallengeFailed
+	}
+
+	// Each challenge buys one nonce
+	fresh, err := p.redis.SetNX(ctx, "pow:spent:"+utils.HashString(proof.Challenge), "1", remaining)
+	if err != nil {
+		return fmt.Errorf("failed to redeem challenge: %w", err)
+	}
+	if !fresh {
+		return ErrChallengeRequired
+	}
+	return nil
+}
+
+func leadingZeroBits(sum [sha256.Size]byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+// turnstileChallenge checks Cloudflare Turnstile tokens; Turnstile itself
+// rejects tokens used twice
+type turnstileChallenge struct {
+	secret string
+	url    string
+	client *http.Client
+}
+
+func (t *turnstileChallenge) issue() (*Challenge, error) {
+	return &Challenge{Mode: ChallengeTurnstile}, nil
+}
+
+func (t *turnstileChallenge) verify(ctx context.Context, proof ChallengeProof) error {
+	if proof.CaptchaToken == "" {
+		return ErrChallengeRequired
+	}
+	form := url.Values{"secret": {t.secret}, "response": {proof.CaptchaToken}}
+	if proof.IPAddress != "" {
+		form.Set("remoteip", proof.IPAddress)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return apperrors.Unavailable(err, "challenge verification is unavailable")
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return apperrors.Unavailable(fmt.Errorf("turnstile siteverify returned %d", res.StatusCode), "challenge verification is unavailable")
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return apperrors.Unavailable(err, "challenge verification is unavailable")
+	}
+	if !result.Success {
+		return ErrChallengeFailed
+	}
+	return nil
+}
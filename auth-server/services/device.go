@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/jwks"
+	"r2s/pkg/models"
+	"r2s/pkg/utils"
+)
+
+// ErrReauthenticationRequired is returned when a refresh token is used from
+// a device or country other than the one its session was created on
+var ErrReauthenticationRequired = apperrors.Catalog(apperrors.ReasonReauthenticate)
+
+const (
+	// realtimeUserChannel is realtime-server's per-user channel prefix;
+	// events published there reach the user's open connections
+	realtimeUserChannel = "r2s:realtime:user:"
+	// eventSessionAnomaly is the realtime event sent when a session is ended
+	// for a device or country change
+	eventSessionAnomaly = "security.session_anomaly"
+)
+
+// ClientInfo describes the device a sign-in or refresh comes from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	// Fingerprint is the device fingerprint the client sent, if any
+	Fingerprint string
+	// Country is the ISO 3166-1 alpha-2 country of the client IP as
+	// reported by the CDN, if any
+	Country string
+}
+
+// fingerprint is the value stored in sessions.device_fingerprint: a hash of
+// the client's fingerprint, or of its user agent when it sent none
+func (c ClientInfo) fingerprint() string {
+	switch {
+	case c.Fingerprint != "":
+		return utils.HashString("fp:" + c.Fingerprint)
+	case c.UserAgent != "":
+		return utils.HashString("ua:" + c.UserAgent)
+	}
+	return ""
+}
+
+// country returns the upper-case country code, or "" when the CDN did not
+// know it (XX) or sent something else
+func (c ClientInfo) country() string {
+	code := strings.ToUpper(strings.TrimSpace(c.Country))
+	if len(code) != 2 || code == "XX" {
+		return ""
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return ""
+		}
+	}
+	return code
+}
+
+// sessionAnomaly names what changed since the session was created, or ""
+// when the client matches it. Values missing on either side never count as
+// a change, so sessions created before binding keep working.
+func sessionAnomaly(session *models.Session, client ClientInfo) string {
+	if fp := client.fingerprint(); fp != "" && session.DeviceFingerprint != nil && *session.DeviceFingerprint != fp {
+		return "device"
+	}
+	if country := client.country(); country != "" && session.Country != nil && *session.Country != country {
+		return "country"
+	}
+	return ""
+}
+
+// endAnomalousSession deletes a session whose refresh token was used from a
+// new device or country, blacklists its access token and tells the user.
+// Failures after the delete are logged only; the session is gone either way.
+func (s *AuthService) endAnomalousSession(ctx context.Context, session *models.Session, client ClientInfo, reason string) error {
+	if _, err := s.sessionRepo.DeleteForUser(session.ID, session.UserID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if remaining := s.clock.Until(session.ExpiresAt); remaining > 0 {
+		if err := s.redis.SetWithExpiry(ctx, jwks.BlacklistHashKey(session.TokenHash), "1", remaining); err != nil {
+			slog.Warn("Failed to blacklist anomalous session token", "session_id", session.ID, "error", err)
+		}
+	}
+
+	slog.Warn("Session ended after a device or country change",
+		"user_id", session.UserID,
+		"session_id", session.ID,
+		"reason", reason,
+		"ip", client.IPAddress,
+		"country", client.country(),
+	)
+
+	event, err := json.Marshal(map[string]interface{}{
+		"type": eventSessionAnomaly,
+		"data": map[string]interface{}{
+			"sessionId": session.ID,
+			"reason":    reason,
+			"ipAddress": client.IPAddress,
+			"userAgent": client.UserAgent,
+			"country":   client.country(),
+		},
+		"at": s.clock.Now().UTC(),
+	})
+	if err == nil {
+		err = s.redis.Publish(ctx, realtimeUserChannel+session.UserID.String(), event).Err()
+	}
+	if err != nil {
+		slog.Warn("Failed to publish session anomaly event", "session_id", session.ID, "error", err)
+	}
+	return nil
+}
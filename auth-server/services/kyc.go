@@ -0,0 +1,303 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/auth-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/objectstore"
+)
+
+const (
+	// MaxKYCDocuments is how many files one application may carry
+	MaxKYCDocuments = 4
+	// MaxKYCDocumentSize bounds each file
+	MaxKYCDocumentSize = 10 << 20
+)
+
+var (
+	ErrKYCPending          = apperrors.Catalog(apperrors.ReasonKYCPending)
+	ErrKYCTierNotHigher    = apperrors.Catalog(apperrors.ReasonKYCTierNotHigher)
+	ErrKYCNotFound         = apperrors.Catalog(apperrors.ReasonKYCNotFound)
+	ErrInvalidKYCWebhook   = apperrors.Catalog(apperrors.ReasonInvalidKYCWebhook)
+	ErrInvalidKYCSignature = apperrors.Catalog(apperrors.ReasonInvalidKYCSignature)
+)
+
+// kycDocumentTypes are the accepted identity documents
+var kycDocumentTypes = map[string]bool{
+	models.KYCDocPassport:        true,
+	models.KYCDocIDCard:          true,
+	models.KYCDocDrivingLicense:  true,
+	models.KYCDocResidencePermit: true,
+}
+
+// kycContentTypes maps the accepted file types to the extension they are
+// stored with
+var kycContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// KYCDocument is an uploaded document file
+type KYCDocument struct {
+	Content io.Reader
+	Size    int64
+}
+
+// KYCStatus is what a user polls while an application is reviewed
+type KYCStatus struct {
+	Tier int
+	// Application is the latest application, nil if there is none
+	Application *models.KYCApplication
+}
+
+// kycWebhook is the review result the KYC provider posts (Sumsub-style).
+// externalUserId carries the application ID given to the provider.
+type kycWebhook struct {
+	Type           string `json:"type"`
+	ExternalUserID string `json:"externalUserId"`
+	ReviewResult   struct {
+		ReviewAnswer      string   `json:"reviewAnswer"`
+		RejectLabels      []string `json:"rejectLabels"`
+		ModerationComment string   `json:"moderationComment"`
+	} `json:"reviewResult"`
+}
+
+// kycReviewed is the only webhook type acted on; others are acknowledged
+const kycReviewed = "applicantReviewed"
+
+// KYCService takes tier upgrade applications and applies the provider's
+// reviews. Approved tiers reach access tokens issued afterwards, on sign-in
+// or refresh.
+type KYCService struct {
+	db            *database.DB
+	kycRepo       *repository.KYCRepository
+	userRepo      *repository.UserRepository
+	store         objectstore.Store
+	audit         *audit.Store
+	webhookSecret string
+	clock         clock.Clock
+}
+
+func NewKYCService(
+	db *database.DB,
+	kycRepo *repository.KYCRepository,
+	userRepo *repository.UserRepository,
+	store objectstore.Store,
+	webhookSecret string,
+	clk clock.Clock,
+) *KYCService {
+	clk = clock.OrSystem(clk)
+	return &KYCService{
+		db:            db,
+		kycRepo:       kycRepo,
+		userRepo:      userRepo,
+		store:         store,
+		audit:         audit.NewStore(db, clk),
+		webhookSecret: webhookSecret,
+		clock:         clk,
+	}
+}
+
+// Submit stores the documents and opens an application for tier
+func (s *KYCService) Submit(ctx context.Context, userID uuid.UUID, tier int, documentType string, docs []KYCDocument) (*models.KYCApplication, error) {
+	if tier < 1 || tier > models.MaxKYCTier {
+		return nil, apperrors.Catalog(apperrors.ReasonKYCTierOutOfRange, models.MaxKYCTier)
+	}
+	if !kycDocumentTypes[documentType] {
+		return nil, apperrors.Catalog(apperrors.ReasonKYCDocumentType)
+	}
+	if len(docs) == 0 || len(docs) > MaxKYCDocuments {
+		return nil, apperrors.Catalog(apperrors.ReasonKYCDocumentCount, MaxKYCDocuments)
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonUserNotFound)
+	}
+	if tier <= user.KYCTier {
+		return nil, ErrKYCTierNotHigher
+	}
+
+	// Checked before uploading; the unique index catches concurrent submits
+	latest, err := s.kycRepo.Latest(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KYC application: %w", err)
+	}
+	if latest != nil && latest.Status == models.KYCPending {
+		return nil, ErrKYCPending
+	}
+
+	now := s.clock.Now()
+	app := &models.KYCApplication{
+		ID:            uuid.New(),
+		UserID:        userID,
+		RequestedTier: tier,
+		DocumentType:  documentType,
+		Status:        models.KYCPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	for i, doc := range docs {
+		key, err := s.putDocument(ctx, app, i, doc)
+		if err != nil {
+			return nil, err
+		}
+		app.DocumentKeys = append(app.DocumentKeys, key)
+	}
+
+	if err := s.kycRepo.Create(ctx, app); err != nil {
+		if errors.Is(err, repository.ErrPendingKYC) {
+			return nil, ErrKYCPending
+		}
+		return nil, fmt.Errorf("failed to create KYC application: %w", err)
+	}
+	return app, nil
+}
+
+// putDocument checks a document's size and type from its content and
+// stores it under kyc/<user>/<application>/
+func (s *KYCService) putDocument(ctx context.Context, app *models.KYCApplication, i int, doc KYCDocument) (string, error) {
+	if doc.Size <= 0 || doc.Size > MaxKYCDocumentSize {
+		return "", apperrors.Catalog(apperrors.ReasonKYCDocumentTooLarge, MaxKYCDocumentSize>>20)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(doc.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", apperrors.Catalog(apperrors.ReasonKYCDocumentUnread)
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := kycContentTypes[contentType]
+	if !ok {
+		return "", apperrors.Catalog(apperrors.ReasonKYCDocumentFormat)
+	}
+
+	key := fmt.Sprintf("kyc/%s/%s/%d%s", app.UserID, app.ID, i+1, ext)
+	content := io.MultiReader(bytes.NewReader(head[:n]), doc.Content)
+	if err := s.store.Put(ctx, key, content, doc.Size, contentType); err != nil {
+		return "", fmt.Errorf("failed to store KYC document: %w", err)
+	}
+	return key, nil
+}
+
+// Status returns the user's tier and latest application
+func (s *KYCService) Status(ctx context.Context, userID uuid.UUID) (*KYCStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonUserNotFound)
+	}
+
+	latest, err := s.kycRepo.Latest(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KYC application: %w", err)
+	}
+	return &KYCStatus{Tier: user.KYCTier, Application: latest}, nil
+}
+
+// HandleWebhook verifies and applies a provider review. Redelivered reviews
+// of an application that was already decided are acknowledged as no-ops.
+func (s *KYCService) HandleWebhook(ctx context.Context, body []byte, signature string) error {
+	if s.webhookSecret != "" && !s.validSignature(body, signature) {
+		return ErrInvalidKYCSignature
+	}
+
+	var event kycWebhook
+	if err := json.Unmarshal(body, &event); err != nil || event.Type == "" {
+		return ErrInvalidKYCWebhook
+	}
+	if event.Type != kycReviewed {
+		return nil
+	}
+
+	id, err := uuid.Parse(event.ExternalUserID)
+	if err != nil {
+		return ErrInvalidKYCWebhook
+	}
+	var status string
+	var reason *string
+	switch event.ReviewResult.ReviewAnswer {
+	case "GREEN":
+		status = models.KYCApproved
+	case "RED":
+		status = models.KYCRejected
+		reason = rejectReason(event)
+	default:
+		return ErrInvalidKYCWebhook
+	}
+
+	return s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		app, err := s.kycRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load KYC application: %w", err)
+		}
+		if app == nil {
+			return ErrKYCNotFound
+		}
+		if app.Status != models.KYCPending {
+			return nil
+		}
+
+		if err := s.kycRepo.Review(ctx, tx, app.ID, status, reason, s.clock.Now()); err != nil {
+			return fmt.Errorf("failed to review KYC application: %w", err)
+		}
+		if status != models.KYCApproved {
+			return nil
+		}
+
+		before, err := s.kycRepo.RaiseTier(ctx, tx, app.UserID, app.RequestedTier)
+		if err != nil {
+			return fmt.Errorf("failed to raise KYC tier: %w", err)
+		}
+		return s.audit.Record(audit.AsSystem(ctx, "kyc-provider"), tx, audit.Change{
+			Action:       audit.ActionUserKYCTier,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   app.UserID.String(),
+			Before:       map[string]interface{}{"kycTier": before},
+			After:        map[string]interface{}{"kycTier": max(before, app.RequestedTier), "applicationId": app.ID},
+		})
+	})
+}
+
+// rejectReason prefers the provider's comment for the user over its labels
+func rejectReason(event kycWebhook) *string {
+	reason := event.ReviewResult.ModerationComment
+	if reason == "" {
+		reason = strings.Join(event.ReviewResult.RejectLabels, ", ")
+	}
+	if reason == "" {
+		return nil
+	}
+	return &reason
+}
+
+// validSignature checks the hex HMAC-SHA256 of the body
+func (s *KYCService) validSignature(body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	apperrors "r2s/pkg/errors"
+)
+
+const (
+	lineVerifyURL     = "https://api.line.me/oauth2/v2.1/verify"
+	lineVerifyTimeout = 5 * time.Second
+)
+
+var ErrInvalidLineToken = apperrors.Catalog(apperrors.ReasonInvalidLineToken)
+
+// LineVerifier checks LINE Login ID tokens with LINE's verify endpoint
+type LineVerifier struct {
+	channelID string
+	url       string
+	client    *http.Client
+}
+
+// NewLineVerifier returns a verifier for tokens issued to channelID, or nil
+// when no channel is configured
+func NewLineVerifier(channelID string) *LineVerifier {
+	if channelID == "" {
+		return nil
+	}
+	return &LineVerifier{
+		channelID: channelID,
+		url:       lineVerifyURL,
+		client:    &http.Client{Timeout: lineVerifyTimeout},
+	}
+}
+
+// VerifyIDToken returns the LINE user ID (sub) of a valid ID token
+func (v *LineVerifier) VerifyIDToken(ctx context.Context, idToken string) (string, error) {
+	form := url.Values{"id_token": {idToken}, "client_id": {v.channelID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return "", apperrors.Unavailable(err, "LINE is unavailable")
+	}
+	defer res.Body.Close()
+	// LINE answers 400 for expired, malformed or foreign tokens
+	if res.StatusCode == http.StatusBadRequest {
+		return "", ErrInvalidLineToken
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", apperrors.Unavailable(fmt.Errorf("LINE verify returned %d", res.StatusCode), "LINE is unavailable")
+	}
+
+	var claims struct {
+		Sub string `json:"sub"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&claims); err != nil || claims.Sub == "" {
+		return "", ErrInvalidLineToken
+	}
+	return claims.Sub, nil
+}
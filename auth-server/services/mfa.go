@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/totp"
+	"r2s/pkg/utils"
+)
+
+// mfaIssuer is the account issuer shown in authenticator apps
+const mfaIssuer = "Reserve to Save"
+
+var (
+	ErrMFAAlreadyEnabled = apperrors.Catalog(apperrors.ReasonMFAAlreadyEnabled)
+	ErrMFANotSetUp       = apperrors.Catalog(apperrors.ReasonMFANotSetUp)
+	ErrInvalidMFACode    = apperrors.Catalog(apperrors.ReasonInvalidMFACode)
+)
+
+// MFASetup is returned when a user starts MFA enrollment
+type MFASetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// SetupMFA generates a new TOTP secret for the user. MFA stays disabled
+// until EnableMFA confirms a code from it.
+func (s *AuthService) SetupMFA(ctx context.Context, userID uuid.UUID) (*MFASetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonUserNotFound)
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.SetMFASecret(userID, secret); err != nil {
+		return nil, fmt.Errorf("failed to store MFA secret: %w", err)
+	}
+	return &MFASetup{
+		Secret: secret,
+		URI:    totp.URI(mfaIssuer, user.WalletAddress, secret),
+	}, nil
+}
+
+// EnableMFA turns MFA on once the user proves the authenticator works
+func (s *AuthService) EnableMFA(ctx context.Context, userID uuid.UUID, code string) error {
+	secret, enabled, err := s.userRepo.MFASecret(userID)
+	if err != nil {
+		return fmt.Errorf("failed to load MFA secret: %w", err)
+	}
+	if enabled {
+		return ErrMFAAlreadyEnabled
+	}
+	if secret == nil {
+		return ErrMFANotSetUp
+	}
+	if err := s.checkCode(ctx, userID, *secret, code); err != nil {
+		return err
+	}
+	if err := s.userRepo.EnableMFA(userID); err != nil {
+		return fmt.Errorf("failed to enable MFA: %w", err)
+	}
+	return nil
+}
+
+// VerifyMFA completes the MFA challenge for the session of token and returns
+// a replacement access token carrying "mfa": true. The old token stops
+// validating because the session now points at the new one.
+func (s *AuthService) VerifyMFA(ctx context.Context, token, code string) (string, error) {
+	claims, err := s.ValidateToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	secret, enabled, err := s.userRepo.MFASecret(claims.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load MFA secret: %w", err)
+	}
+	if !enabled || secret == nil {
+		return "", ErrMFANotSetUp
+	}
+	if err := s.checkCode(ctx, claims.UserID, *secret, code); err != nil {
+		return "", err
+	}
+
+	session, err := s.sessionRepo.FindByToken(utils.HashString(token))
+	if err != nil || session == nil {
+		return "", apperrors.Catalog(apperrors.ReasonInvalidSession)
+	}
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil || user == nil {
+		return "", apperrors.Unauthorized("user not found")
+	}
+
+	accessToken, err := s.jwtManager.GenerateAccessToken(accessClaims(user, session.ID, true))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	now := s.clock.Now()
+	session.TokenHash = utils.HashString(accessToken)
+	session.ExpiresAt = now.Add(s.jwtManager.AccessDuration())
+	session.LastUsedAt = now
+	session.MFAVerifiedAt = &now
+	if err := s.sessionRepo.Update(session); err != nil {
+		return "", fmt.Errorf("failed to update session: %w", err)
+	}
+	return accessToken, nil
+}
+
+// checkCode validates code and burns its time step so an observed code
+// cannot be replayed within its validity window
+func (s *AuthService) checkCode(ctx context.Context, userID uuid.UUID, secret, code string) error {
+	step, ok := totp.Validate(secret, code, s.clock.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	fresh, err := s.redis.SetNX(ctx, fmt.Sprintf("mfa:step:%s:%d", userID, step), "1", (2*totp.Skew+1)*totp.Period)
+	if err != nil {
+		return fmt.Errorf("failed to record MFA code use: %w", err)
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
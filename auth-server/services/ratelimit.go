@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"r2s/pkg/database"
+	"r2s/pkg/ratelimit"
+)
+
+// LoginLimits bounds wallet sign-in attempts (/auth/nonce and /auth/verify)
+type LoginLimits struct {
+	// PerIP and PerAddress are requests allowed per minute
+	PerIP      int
+	PerAddress int
+	// LockoutThreshold signature failures within an hour lock the IP and
+	// the address out for a minute, doubling with each further failure up
+	// to LockoutMax
+	LockoutThreshold int
+	LockoutMax       time.Duration
+}
+
+// loginGuard applies LoginLimits
+type loginGuard struct {
+	byIP      *ratelimit.Limiter
+	byAddress *ratelimit.Limiter
+	lockout   *ratelimit.Lockout
+}
+
+func newLoginGuard(redis *database.RedisClient, limits LoginLimits) *loginGuard {
+	client := redis.UniversalClient
+	return &loginGuard{
+		byIP:      ratelimit.NewLimiter(client, "login:ip", limits.PerIP, time.Minute),
+		byAddress: ratelimit.NewLimiter(client, "login:address", limits.PerAddress, time.Minute),
+		lockout:   ratelimit.NewLockout(client, "login", limits.LockoutThreshold, time.Hour, time.Minute, limits.LockoutMax),
+	}
+}
+
+func ipKey(ip string) string           { return "ip:" + ip }
+func addressKey(address string) string { return "address:" + strings.ToLower(address) }
+
+// allow counts a sign-in request and refuses it while the IP or the address
+// is over its rate or locked out
+func (g *loginGuard) allow(ctx context.Context, ip, address string) error {
+	if err := g.byIP.Allow(ctx, ip); err != nil {
+		return err
+	}
+	if err := g.byAddress.Allow(ctx, strings.ToLower(address)); err != nil {
+		return err
+	}
+	if err := g.lockout.Check(ctx, ipKey(ip)); err != nil {
+		return err
+	}
+	return g.lockout.Check(ctx, addressKey(address))
+}
+
+// failed records a rejected signature against the IP and the address
+func (g *loginGuard) failed(ctx context.Context, ip, address string) {
+	for _, key := range []string{ipKey(ip), addressKey(address)} {
+		locked, err := g.lockout.Fail(ctx, key)
+		if err != nil {
+			slog.Warn("Failed to record sign-in failure", "key", key, "error", err)
+			continue
+		}
+		if locked > 0 {
+			slog.Warn("Sign-in locked out after repeated signature failures", "key", key, "duration", locked)
+		}
+	}
+}
+
+// succeeded clears the address's failures. The IP's are kept, so signing
+// in to one's own wallet does not reset attempts on others.
+func (g *loginGuard) succeeded(ctx context.Context, address string) {
+	if err := g.lockout.Reset(ctx, addressKey(address)); err != nil {
+		slog.Warn("Failed to reset sign-in failures", "error", err)
+	}
+}
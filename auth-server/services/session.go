@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/jwks"
+)
+
+var ErrSessionNotFound = apperrors.Catalog(apperrors.ReasonSessionNotFound)
+
+// SessionInfo is a session as shown to its user; token hashes are left out
+type SessionInfo struct {
+	ID                uuid.UUID `json:"id"`
+	IPAddress         *string   `json:"ipAddress,omitempty"`
+	UserAgent         *string   `json:"userAgent,omitempty"`
+	DeviceFingerprint *string   `json:"deviceFingerprint,omitempty"`
+	Country           *string   `json:"country,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	LastUsedAt        time.Time `json:"lastUsedAt"`
+	// Current marks the session of the token making the request
+	Current bool `json:"current"`
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentID uuid.UUID) ([]SessionInfo, error) {
+	sessions, err := s.sessionRepo.ListActiveByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, SessionInfo{
+			ID:                session.ID,
+			IPAddress:         session.IPAddress,
+			UserAgent:         session.UserAgent,
+			DeviceFingerprint: session.DeviceFingerprint,
+			Country:           session.Country,
+			CreatedAt:         session.CreatedAt,
+			LastUsedAt:        session.LastUsedAt,
+			Current:           session.ID == currentID,
+		})
+	}
+	return infos, nil
+}
+
+// RevokeSession signs one of the user's devices out. Its refresh token stops
+// working at once, and its access token is blacklisted so services that
+// validate tokens locally refuse it too.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.DeleteForUser(sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if session == nil {
+		return ErrSessionNotFound
+	}
+
+	if remaining := s.clock.Until(session.ExpiresAt); remaining > 0 {
+		if err := s.redis.SetWithExpiry(ctx, jwks.BlacklistHashKey(session.TokenHash), "1", remaining); err != nil {
+			slog.Warn("Failed to blacklist revoked session token", "session_id", sessionID, "error", err)
+		}
+	}
+	return nil
+}
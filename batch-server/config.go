@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/Reserve-to-save-backend/batch-server/disputes"
+	"github.com/Reserve-to-save-backend/batch-server/export"
+	"github.com/Reserve-to-save-backend/batch-server/lifecycle"
+	"github.com/Reserve-to-save-backend/batch-server/notify"
+	"github.com/Reserve-to-save-backend/batch-server/outbox"
+	"github.com/Reserve-to-save-backend/batch-server/stats"
+	"github.com/Reserve-to-save-backend/batch-server/watchlist"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/line"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/mail"
+	"github.com/Reserve-to-save-backend/pkg/objectstore"
+	"github.com/Reserve-to-save-backend/pkg/push"
+)
+
+// Config is the batch-server configuration, loaded by config.MustLoad
+type Config struct {
+	// MetricsPort serves /metrics, /live and /ready
+	MetricsPort string `env:"BATCH_METRICS_PORT" default:"9465"`
+	// DebugAddr serves pkg/diag (empty disables); keep it off the public network
+	DebugAddr string `env:"BATCH_DEBUG_ADDR"`
+
+	Database    database.Config
+	Log         logger.Config
+	Errors      errreport.Config
+	ObjectStore objectstore.Config
+	Export      export.Config
+	Stats       stats.Config
+	Lifecycle   lifecycle.Config
+	Watchlist   watchlist.Config
+	Notify      notify.Config
+	Disputes    disputes.Config
+	Outbox      outbox.Config
+	Push        push.Config
+	Line        line.Config
+	Mail        mail.Config
+}
@@ -0,0 +1,144 @@
+// Package disputes surfaces the SLA timers of disputes (see
+// 028_disputes.sql). A pending dispute is due at sla_due_at: open ones are
+// to be resolved by an admin, refunding ones to have their refund completed.
+//
+// Every tick stamps sla_breached_at on the pending disputes past their due
+// time, logging each once, and refreshes the pending and overdue gauges
+// alerting is built on. core-server clears sla_breached_at when a dispute
+// moves to its next stage.
+package disputes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often the SLAs are checked; 0 disables the job
+	Interval time.Duration `env:"DISPUTE_SLA_INTERVAL" default:"5m"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("DISPUTE_SLA_INTERVAL must not be negative")
+	}
+	return nil
+}
+
+// pendingStatuses are the statuses a dispute has an SLA in
+var pendingStatuses = []string{models.DisputeOpen, models.DisputeRefunding}
+
+// Monitor checks the dispute SLAs
+type Monitor struct {
+	db  *database.DB
+	cfg Config
+	clk clock.Clock
+}
+
+func NewMonitor(db *database.DB, cfg Config, clk clock.Clock) *Monitor {
+	return &Monitor{db: db, cfg: cfg, clk: clock.OrSystem(clk)}
+}
+
+// breach is a dispute newly found past its SLA
+type breach struct {
+	ID         uuid.UUID `db:"id"`
+	CampaignID uuid.UUID `db:"campaign_id"`
+	Kind       string    `db:"kind"`
+	Status     string    `db:"status"`
+	SLADueAt   time.Time `db:"sla_due_at"`
+}
+
+// Tick marks the disputes newly past their SLA, refreshes the gauges and
+// returns how many disputes were marked
+func (m *Monitor) Tick(ctx context.Context) (int, error) {
+	now := m.clk.Now()
+
+	breached := []breach{}
+	query := `
+		UPDATE disputes SET sla_breached_at = $1
+		WHERE status IN ($2, $3) AND sla_due_at <= $1 AND sla_breached_at IS NULL
+		RETURNING id, campaign_id, kind, status, sla_due_at`
+	if err := m.db.SelectContext(ctx, &breached, query, now, models.DisputeOpen, models.DisputeRefunding); err != nil {
+		return 0, fmt.Errorf("failed to mark breached disputes: %w", err)
+	}
+	for _, b := range breached {
+		breaches.WithLabelValues(b.Status).Inc()
+		slog.Warn("Dispute SLA breached",
+			"dispute_id", b.ID, logger.KeyCampaignID, b.CampaignID,
+			"kind", b.Kind, "status", b.Status, "due", b.SLADueAt)
+	}
+
+	if err := m.refreshGauges(ctx, now); err != nil {
+		return len(breached), err
+	}
+	return len(breached), nil
+}
+
+// refreshGauges sets the pending and overdue gauges of each pending status
+func (m *Monitor) refreshGauges(ctx context.Context, now time.Time) error {
+	var counts []struct {
+		Status  string `db:"status"`
+		Pending int64  `db:"pending"`
+		Overdue int64  `db:"overdue"`
+	}
+	query := `
+		SELECT status, COUNT(*) AS pending,
+		    COUNT(*) FILTER (WHERE sla_due_at <= $1) AS overdue
+		FROM disputes
+		WHERE status IN ($2, $3)
+		GROUP BY status`
+	if err := m.db.SelectContext(ctx, &counts, query, now, models.DisputeOpen, models.DisputeRefunding); err != nil {
+		return fmt.Errorf("failed to count pending disputes: %w", err)
+	}
+
+	// Statuses without disputes are absent from counts
+	for _, status := range pendingStatuses {
+		pending.WithLabelValues(status).Set(0)
+		overdue.WithLabelValues(status).Set(0)
+	}
+	for _, c := range counts {
+		pending.WithLabelValues(c.Status).Set(float64(c.Pending))
+		overdue.WithLabelValues(c.Status).Set(float64(c.Overdue))
+	}
+	return nil
+}
+
+// Run checks the SLAs every cfg.Interval until ctx is done
+func (m *Monitor) Run(ctx context.Context) {
+	if m.cfg.Interval == 0 {
+		slog.Info("Dispute SLA checks disabled")
+		return
+	}
+	for {
+		started := m.clk.Now()
+		marked, err := m.Tick(ctx)
+		switch {
+		case err == nil:
+			slog.Debug("Dispute SLAs checked", "breached", marked, "duration", m.clk.Since(started))
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Dispute SLA check failed", "breached", marked, "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.clk.After(m.cfg.Interval):
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package disputes
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var (
+	pending = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "disputes",
+			Name:      "pending",
+			Help:      "Disputes awaiting a resolution or refund by status.",
+		},
+		[]string{"status"},
+	)
+	overdue = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "disputes",
+			Name:      "overdue",
+			Help:      "Pending disputes past their SLA by status.",
+		},
+		[]string{"status"},
+	)
+	breaches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "disputes",
+			Name:      "sla_breaches_total",
+			Help:      "Disputes found past their SLA by status.",
+		},
+		[]string{"status"},
+	)
+)
+
+func init() {
+	metrics.MustRegister(pending, overdue, breaches)
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+const (
+	bigQueryScope    = "https://www.googleapis.com/auth/bigquery"
+	bigQueryEndpoint = "https://bigquery.googleapis.com/bigquery/v2/projects/%s/jobs"
+	bigQueryPoll     = 5 * time.Second
+)
+
+// BigQueryConfig is loadable with pkg/config. Without a project nothing is
+// loaded. Loading reads the files from GCS, so the object store must be
+// GCS (OBJECT_STORE_ENDPOINT=storage.googleapis.com).
+type BigQueryConfig struct {
+	ProjectID string `env:"EXPORT_BIGQUERY_PROJECT"`
+	Dataset   string `env:"EXPORT_BIGQUERY_DATASET" default:"r2s_exports"`
+	// CredentialsFile is a service account key; empty uses the
+	// application default credentials
+	CredentialsFile string `env:"EXPORT_BIGQUERY_CREDENTIALS_FILE"`
+}
+
+// BigQuery runs load jobs through the BigQuery REST API
+type BigQuery struct {
+	client   *http.Client
+	endpoint string
+	project  string
+	dataset  string
+	clk      clock.Clock
+}
+
+func NewBigQuery(ctx context.Context, cfg BigQueryConfig, clk clock.Clock) (*BigQuery, error) {
+	var creds *google.Credentials
+	var err error
+	if cfg.CredentialsFile != "" {
+		key, readErr := os.ReadFile(cfg.CredentialsFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read BigQuery credentials: %w", readErr)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, key, bigQueryScope)
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, bigQueryScope)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load BigQuery credentials: %w", err)
+	}
+
+	return &BigQuery{
+		client:   oauth2.NewClient(ctx, creds.TokenSource),
+		endpoint: fmt.Sprintf(bigQueryEndpoint, url.PathEscape(cfg.ProjectID)),
+		project:  cfg.ProjectID,
+		dataset:  cfg.Dataset,
+		clk:      clock.OrSystem(clk),
+	}, nil
+}
+
+type bqJob struct {
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Status struct {
+		State       string `json:"state"`
+		ErrorResult *struct {
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"errorResult"`
+	} `json:"status"`
+}
+
+// Load replaces day's partition of the table <dataset>_v<version> with the
+// CSV files at uris and waits for the job to finish. Each schema version
+// gets its own table, created on first load.
+func (b *BigQuery) Load(ctx context.Context, d Dataset, day time.Time, uris []string) error {
+	fields := make([]map[string]string, len(d.Columns))
+	for i, c := range d.Columns {
+		fields[i] = map[string]string{"name": c.Name, "type": c.Type}
+	}
+	table := fmt.Sprintf("%s_v%d$%s", d.Name, d.Version, day.Format("20060102"))
+
+	body, err := json.Marshal(map[string]interface{}{
+		"configuration": map[string]interface{}{
+			"load": map[string]interface{}{
+				"sourceUris":          uris,
+				"sourceFormat":        "CSV",
+				"skipLeadingRows":     1,
+				"allowQuotedNewlines": true,
+				"schema":              map[string]interface{}{"fields": fields},
+				"destinationTable": map[string]string{
+					"projectId": b.project,
+					"datasetId": b.dataset,
+					"tableId":   table,
+				},
+				"timePartitioning":  map[string]string{"type": "DAY"},
+				"createDisposition": "CREATE_IF_NEEDED",
+				"writeDisposition":  "WRITE_TRUNCATE",
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	job, err := b.do(ctx, http.MethodPost, b.endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to start BigQuery load: %w", err)
+	}
+	for job.Status.State != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.clk.After(bigQueryPoll):
+		}
+		query := url.Values{"location": {job.JobReference.Location}}
+		job, err = b.do(ctx, http.MethodGet, b.endpoint+"/"+url.PathEscape(job.JobReference.JobID)+"?"+query.Encode(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to poll BigQuery load: %w", err)
+		}
+	}
+	if e := job.Status.ErrorResult; e != nil {
+		return fmt.Errorf("BigQuery load of %s failed: %s: %s", table, e.Reason, e.Message)
+	}
+	return nil
+}
+
+func (b *BigQuery) do(ctx context.Context, method, endpoint string, body []byte) (*bqJob, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode >= 300 {
+		return nil, fmt.Errorf("BigQuery returned %d: %s", res.StatusCode, raw)
+	}
+
+	var job bqJob
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode BigQuery job: %w", err)
+	}
+	return &job, nil
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"encoding/csv"
+	"io"
+)
+
+// writeCSV writes a header row and rows, whose columns are all text, as
+// gzipped CSV. NULL becomes an empty field, which BigQuery loads as NULL.
+func writeCSV(w io.Writer, columns []Column, rows *sql.Rows) (int64, error) {
+	zw := gzip.NewWriter(w)
+	cw := csv.NewWriter(zw)
+
+	record := make([]string, len(columns))
+	for i, c := range columns {
+		record[i] = c.Name
+	}
+	if err := cw.Write(record); err != nil {
+		return 0, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var n int64
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return n, err
+		}
+		for i, v := range values {
+			record[i] = v.String
+		}
+		if err := cw.Write(record); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return n, err
+	}
+	return n, zw.Close()
+}
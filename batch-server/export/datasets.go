@@ -0,0 +1,162 @@
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+)
+
+// Column types, named after the BigQuery types they load as
+const (
+	TypeString     = "STRING"
+	TypeInt64      = "INT64"
+	TypeBigNumeric = "BIGNUMERIC"
+	TypeBool       = "BOOL"
+	TypeTimestamp  = "TIMESTAMP"
+	TypeJSON       = "JSON"
+)
+
+// Column is one exported column
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Dataset is one exported table. Files are written under
+// <dataset>/v<Version>/dt=<day>/, so bump Version whenever Columns change;
+// analysts then read the new version side by side with the old one instead
+// of finding mixed files under one path.
+type Dataset struct {
+	Name    string
+	Version int
+	Table   string
+	Columns []Column
+	// DayColumn exports only the rows whose timestamp falls on the export
+	// day, for append-only tables. Without it the whole table is
+	// snapshotted every day.
+	DayColumn string
+}
+
+// Datasets are the tables exported every day. Payment provider responses
+// and user profiles stay out of the export.
+var Datasets = []Dataset{
+	{
+		Name:    "campaigns",
+		Version: 1,
+		Table:   "campaigns",
+		Columns: []Column{
+			{"id", TypeString},
+			{"chain_address", TypeString},
+			{"title", TypeString},
+			{"merchant_id", TypeString},
+			{"merchant_wallet", TypeString},
+			{"base_price", TypeBigNumeric},
+			{"min_qty", TypeInt64},
+			{"current_qty", TypeInt64},
+			{"target_amount", TypeBigNumeric},
+			{"current_amount", TypeBigNumeric},
+			{"discount_rate", TypeInt64},
+			{"save_floor_bps", TypeInt64},
+			{"r_max_bps", TypeInt64},
+			{"merchant_fee_bps", TypeInt64},
+			{"ops_fee_bps", TypeInt64},
+			{"start_time", TypeTimestamp},
+			{"end_time", TypeTimestamp},
+			{"settlement_date", TypeTimestamp},
+			{"status", TypeString},
+			{"tx_hash", TypeString},
+			{"block_number", TypeInt64},
+			{"created_at", TypeTimestamp},
+			{"updated_at", TypeTimestamp},
+		},
+	},
+	{
+		Name:    "participations",
+		Version: 1,
+		Table:   "participations",
+		Columns: []Column{
+			{"id", TypeString},
+			{"campaign_id", TypeString},
+			{"user_id", TypeString},
+			{"wallet_address", TypeString},
+			{"deposit_amount", TypeBigNumeric},
+			{"joined_at", TypeTimestamp},
+			{"cancel_pending", TypeBigNumeric},
+			{"expected_rebate", TypeBigNumeric},
+			{"actual_rebate", TypeBigNumeric},
+			{"status", TypeString},
+			{"tx_hash", TypeString},
+			{"cancel_tx_hash", TypeString},
+			{"settlement_tx_hash", TypeString},
+			{"refund_tx_hash", TypeString},
+			{"created_at", TypeTimestamp},
+			{"updated_at", TypeTimestamp},
+		},
+	},
+	{
+		Name:    "payments",
+		Version: 1,
+		Table:   "payments",
+		Columns: []Column{
+			{"id", TypeString},
+			{"payment_id", TypeString},
+			{"campaign_id", TypeString},
+			{"user_id", TypeString},
+			{"participation_id", TypeString},
+			{"amount", TypeBigNumeric},
+			{"currency", TypeString},
+			{"mode", TypeString},
+			{"status", TypeString},
+			{"transaction_hash", TypeString},
+			{"created_at", TypeTimestamp},
+			{"completed_at", TypeTimestamp},
+			{"failed_at", TypeTimestamp},
+			{"refunded_at", TypeTimestamp},
+		},
+	},
+	{
+		Name:      "chain_events",
+		Version:   1,
+		Table:     "chain_events",
+		DayColumn: "ingested_at",
+		Columns: []Column{
+			{"id", TypeInt64},
+			{"block_number", TypeInt64},
+			{"tx_hash", TypeString},
+			{"log_index", TypeInt64},
+			{"contract_address", TypeString},
+			{"event_name", TypeString},
+			{"event_data", TypeJSON},
+			{"decoded_data", TypeJSON},
+			{"chain_timestamp", TypeTimestamp},
+			{"processed", TypeBool},
+			{"processed_at", TypeTimestamp},
+			{"ingested_at", TypeTimestamp},
+		},
+	},
+}
+
+// Prefix is the directory of one day's files: <name>/v<version>/dt=<day>
+func (d Dataset) Prefix(day time.Time) string {
+	return fmt.Sprintf("%s/v%d/dt=%s", d.Name, d.Version, day.Format(time.DateOnly))
+}
+
+// query selects every column as text in the form BigQuery loads it;
+// timestamps are written in UTC
+func (d Dataset) query(day time.Time) (string, []interface{}) {
+	exprs := make([]string, len(d.Columns))
+	for i, c := range d.Columns {
+		if c.Type == TypeTimestamp {
+			exprs[i] = fmt.Sprintf(`to_char(%s AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`, c.Name)
+		} else {
+			exprs[i] = c.Name + "::text"
+		}
+	}
+
+	q := database.NewSelect(exprs...).From(d.Table)
+	if d.DayColumn != "" {
+		q.Where(d.DayColumn+" >= ? AND "+d.DayColumn+" < ?", day, day.AddDate(0, 0, 1))
+	}
+	return q.OrderBy(d.Columns[0].Name).ToSQL()
+}
@@ -0,0 +1,241 @@
+// Package export writes daily snapshots of the core tables to object
+// storage, and optionally loads them into BigQuery, so analytics does not
+// query the production database.
+//
+// Each dataset is written as gzipped CSV with a header row under
+// <prefix>/<dataset>/v<version>/dt=<YYYY-MM-DD>/, followed by a
+// _manifest.json recording the columns and row count. The manifest is
+// written last and marks the day as exported.
+package export
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/objectstore"
+)
+
+const (
+	manifestName = "_manifest.json"
+	dataName     = "part-000.csv.gz"
+)
+
+// ErrLocked means another instance holds the export lock
+var ErrLocked = errors.New("export is running elsewhere")
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Prefix is prepended to every object key
+	Prefix string `env:"EXPORT_PREFIX" default:"exports"`
+	// At is the UTC time of day (HH:MM) the previous day is exported
+	At string `env:"EXPORT_AT" default:"01:00"`
+	// BackfillDays is how many past days each run checks for missing
+	// exports of append-only datasets
+	BackfillDays int `env:"EXPORT_BACKFILL_DAYS" default:"3"`
+	// QueryTimeout replaces DB_STATEMENT_TIMEOUT for the export queries
+	QueryTimeout time.Duration `env:"EXPORT_QUERY_TIMEOUT" default:"30m"`
+
+	BigQuery BigQueryConfig
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if _, err := time.Parse("15:04", c.At); err != nil {
+		return fmt.Errorf("EXPORT_AT must be HH:MM: %w", err)
+	}
+	if c.BackfillDays < 0 {
+		return errors.New("EXPORT_BACKFILL_DAYS must not be negative")
+	}
+	return nil
+}
+
+// Manifest describes one dataset's export for one day
+type Manifest struct {
+	Dataset string   `json:"dataset"`
+	Version int      `json:"version"`
+	Day     string   `json:"day"`
+	Columns []Column `json:"columns"`
+	// Snapshot is true when the files hold the whole table as of
+	// ExportedAt rather than the rows of Day
+	Snapshot   bool      `json:"snapshot"`
+	Files      []string  `json:"files"`
+	Rows       int64     `json:"rows"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// Exporter writes the Datasets for a day
+type Exporter struct {
+	db    *database.DB
+	store objectstore.Store
+	// bq loads the files into BigQuery; nil disables it
+	bq  *BigQuery
+	cfg Config
+	clk clock.Clock
+}
+
+func NewExporter(db *database.DB, store objectstore.Store, bq *BigQuery, cfg Config, clk clock.Clock) *Exporter {
+	return &Exporter{db: db, store: store, bq: bq, cfg: cfg, clk: clock.OrSystem(clk)}
+}
+
+// dump is one dataset written to a local temporary file
+type dump struct {
+	dataset Dataset
+	file    string
+	rows    int64
+}
+
+// Export writes every dataset that has not been exported for day yet. day
+// is truncated to its UTC date. Snapshot datasets can only be exported for
+// the previous day, since they hold the current state of the table.
+func (e *Exporter) Export(ctx context.Context, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	log := slog.With("day", day.Format(time.DateOnly))
+
+	var pending []Dataset
+	for _, d := range Datasets {
+		if d.DayColumn == "" && !day.Equal(e.yesterday()) {
+			continue
+		}
+		done, err := e.store.Exists(ctx, e.key(d, day, manifestName))
+		if err != nil {
+			return err
+		}
+		if !done {
+			pending = append(pending, d)
+		}
+	}
+	if len(pending) == 0 {
+		log.Debug("Export up to date")
+		return nil
+	}
+
+	started := e.clk.Now()
+	dumps, err := e.dump(ctx, day, pending)
+	defer func() {
+		for _, d := range dumps {
+			os.Remove(d.file)
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range dumps {
+		if err := e.publish(ctx, day, d, started); err != nil {
+			exportRuns.WithLabelValues(d.dataset.Name, "error").Inc()
+			errs = append(errs, fmt.Errorf("%s: %w", d.dataset.Name, err))
+			continue
+		}
+		exportRuns.WithLabelValues(d.dataset.Name, "ok").Inc()
+		exportedRows.WithLabelValues(d.dataset.Name).Add(float64(d.rows))
+		log.Info("Exported dataset", "dataset", d.dataset.Name, "version", d.dataset.Version, "rows", d.rows)
+	}
+	return errors.Join(errs...)
+}
+
+// dump queries the datasets in one read-only repeatable-read transaction,
+// so snapshots taken together are consistent with each other, and writes
+// them to temporary files. Uploads happen after the transaction ends.
+func (e *Exporter) dump(ctx context.Context, day time.Time, datasets []Dataset) ([]dump, error) {
+	tx, err := e.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ok, err := database.TryAdvisoryXactLock(ctx, tx, database.AdvisoryKey{Namespace: database.LockExport})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocked
+	}
+	if err := database.SetLocalStatementTimeout(ctx, tx, e.cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+
+	var dumps []dump
+	for _, d := range datasets {
+		f, err := os.CreateTemp("", "export-"+d.Name+"-*.csv.gz")
+		if err != nil {
+			return dumps, fmt.Errorf("failed to create export file: %w", err)
+		}
+		dumps = append(dumps, dump{dataset: d, file: f.Name()})
+
+		query, args := d.query(day)
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			f.Close()
+			return dumps, fmt.Errorf("failed to query %s: %w", d.Name, err)
+		}
+		n, err := writeCSV(f, d.Columns, rows)
+		rows.Close()
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return dumps, fmt.Errorf("failed to write %s: %w", d.Name, err)
+		}
+		dumps[len(dumps)-1].rows = n
+	}
+	return dumps, nil
+}
+
+// publish uploads a dump, loads it into BigQuery when enabled and writes
+// the manifest
+func (e *Exporter) publish(ctx context.Context, day time.Time, d dump, started time.Time) error {
+	f, err := os.Open(d.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	dataKey := e.key(d.dataset, day, dataName)
+	if err := e.store.Put(ctx, dataKey, f, info.Size(), "application/gzip"); err != nil {
+		return err
+	}
+	if e.bq != nil {
+		if err := e.bq.Load(ctx, d.dataset, day, []string{e.store.URI(dataKey)}); err != nil {
+			return err
+		}
+	}
+
+	manifest, err := json.MarshalIndent(Manifest{
+		Dataset:    d.dataset.Name,
+		Version:    d.dataset.Version,
+		Day:        day.Format(time.DateOnly),
+		Columns:    d.dataset.Columns,
+		Snapshot:   d.dataset.DayColumn == "",
+		Files:      []string{dataName},
+		Rows:       d.rows,
+		ExportedAt: started.UTC(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return e.store.Put(ctx, e.key(d.dataset, day, manifestName), bytes.NewReader(manifest), int64(len(manifest)), "application/json")
+}
+
+func (e *Exporter) key(d Dataset, day time.Time, name string) string {
+	return path.Join(strings.Trim(e.cfg.Prefix, "/"), d.Prefix(day), name)
+}
+
+func (e *Exporter) yesterday() time.Time {
+	return e.clk.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+}
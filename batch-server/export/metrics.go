@@ -0,0 +1,33 @@
+package export
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var (
+	exportRuns = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "export",
+			Name:      "runs_total",
+			Help:      "Daily dataset exports by outcome.",
+		},
+		[]string{"dataset", "result"},
+	)
+
+	exportedRows = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "export",
+			Name:      "rows_total",
+			Help:      "Rows written by the daily export.",
+		},
+		[]string{"dataset"},
+	)
+)
+
+func init() {
+	metrics.MustRegister(exportRuns, exportedRows)
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+)
+
+// Run exports on schedule until ctx is done: once at start, to catch up,
+// then every day at cfg.At (UTC)
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		e.runOnce(ctx)
+
+		next := e.nextRun()
+		slog.Info("Next export scheduled", "at", next)
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.clk.After(e.clk.Until(next)):
+		}
+	}
+}
+
+// runOnce exports the previous day and the missing days of the backfill
+// window, oldest first
+func (e *Exporter) runOnce(ctx context.Context) {
+	yesterday := e.yesterday()
+	for i := e.cfg.BackfillDays; i >= 0; i-- {
+		day := yesterday.AddDate(0, 0, -i)
+		err := e.Export(ctx, day)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrLocked):
+			slog.Info("Export skipped, another instance is running it", "day", day.Format(time.DateOnly))
+			return
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Export failed", "day", day.Format(time.DateOnly), "error", err)
+			errreport.Report(ctx, err)
+		}
+	}
+}
+
+// nextRun is the next occurrence of cfg.At, validated by Config.Validate
+func (e *Exporter) nextRun() time.Time {
+	at, _ := time.Parse("15:04", e.cfg.At)
+	now := e.clk.Now().UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,286 @@
+// Package lifecycle moves campaigns along their lifecycle when their totals
+// or the clock call for it, instead of waiting for a PUT /campaigns/:id:
+//
+//   - recruiting campaigns at min_qty become reached (core-server does this
+//     on joins it handles itself; this catches joins indexed from the chain)
+//   - recruiting campaigns whose end_time passed short of min_qty fail
+//   - reached campaigns whose end_time passed go to fulfillment; recruitment
+//     closing is when deposits lock, there is no separate lock start
+//
+// Each move updates campaigns.status, which publishes on r2s_changes
+// (pkg/db/migrations/002_change_notify.sql), appends to
+// campaign_status_history as the batch-server system actor, and pushes a
+// notification to the campaign's active participants.
+package lifecycle
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/push"
+	"github.com/Reserve-to-save-backend/pkg/statemachine"
+)
+
+// actorID attributes the moves in campaign_status_history
+const actorID = "batch-server"
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often campaigns are checked; 0 disables the job
+	Interval time.Duration `env:"LIFECYCLE_INTERVAL" default:"1m"`
+	// BatchSize caps the campaigns moved per rule and tick
+	BatchSize int `env:"LIFECYCLE_BATCH_SIZE" default:"100"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("LIFECYCLE_INTERVAL must not be negative")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("LIFECYCLE_BATCH_SIZE must be positive")
+	}
+	return nil
+}
+
+// rule is one automatic move. cond is a SQL condition on the campaign row
+// where $2 is the current time.
+type rule struct {
+	from, to models.CampaignStatus
+	cond     string
+	reason   string
+	kind     string
+	body     string
+}
+
+// rules run in order, so a recruiting campaign that ended at min_qty is
+// reached and then goes to fulfillment in the same tick
+var rules = []rule{
+	{
+		from:   models.StatusRecruiting,
+		to:     models.StatusReached,
+		cond:   "COALESCE(current_qty, 0) >= min_qty",
+		reason: "minimum quantity reached",
+		kind:   "campaign_reached",
+		body:   "The campaign reached its goal and will go ahead.",
+	},
+	{
+		from:   models.StatusRecruiting,
+		to:     models.StatusFailed,
+		cond:   "end_time <= $2 AND COALESCE(current_qty, 0) < min_qty",
+		reason: "recruitment ended under the minimum quantity",
+		kind:   "campaign_failed",
+		body:   "The campaign ended short of its goal. Your deposit will be refunded.",
+	},
+	{
+		from:   models.StatusReached,
+		to:     models.StatusFulfillment,
+		cond:   "end_time <= $2",
+		reason: "recruitment ended",
+		kind:   "campaign_fulfillment",
+		body:   "Recruitment has closed and the campaign is moving to fulfillment.",
+	},
+}
+
+// Runner applies the rules
+type Runner struct {
+	db      *database.DB
+	cfg     Config
+	clk     clock.Clock
+	sender  push.Sender
+	machine *statemachine.Machine[models.CampaignStatus]
+}
+
+func NewRunner(db *database.DB, cfg Config, clk clock.Clock, sender push.Sender) *Runner {
+	return &Runner{
+		db:      db,
+		cfg:     cfg,
+		clk:     clock.OrSystem(clk),
+		sender:  sender,
+		machine: statemachine.NewCampaign().OnTransition(statemachine.LogHistory[models.CampaignStatus]()),
+	}
+}
+
+// Tick applies every rule once and returns how many campaigns moved
+func (r *Runner) Tick(ctx context.Context) (int, error) {
+	ctx = audit.AsSystem(ctx, actorID)
+	moved := 0
+	for _, rl := range rules {
+		ids, err := r.candidates(ctx, rl)
+		if err != nil {
+			return moved, err
+		}
+		for _, id := range ids {
+			title, ok, err := r.apply(ctx, rl, id)
+			if err != nil {
+				transitions.WithLabelValues(string(rl.to), "error").Inc()
+				return moved, err
+			}
+			if !ok {
+				continue
+			}
+			transitions.WithLabelValues(string(rl.to), "ok").Inc()
+			moved++
+			r.notify(ctx, rl, id, title)
+		}
+	}
+	return moved, nil
+}
+
+// candidates returns the campaigns rl applies to, earliest end first
+func (r *Runner) candidates(ctx context.Context, rl rule) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM campaigns
+		WHERE status = $1 AND start_time <= $2 AND (` + rl.cond + `)
+		ORDER BY end_time, id
+		LIMIT $3`
+
+	ids := []uuid.UUID{}
+	if err := r.db.SelectContext(ctx, &ids, query, rl.from, r.clk.Now(), r.cfg.BatchSize); err != nil {
+		return nil, fmt.Errorf("failed to find %s campaigns to move to %s: %w", rl.from, rl.to, err)
+	}
+	return ids, nil
+}
+
+// apply moves one campaign under its advisory lock, re-checking the rule
+// since core-server may have changed it after candidates ran. It returns
+// the campaign title and whether it moved.
+func (r *Runner) apply(ctx context.Context, rl rule, id uuid.UUID) (string, bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to begin lifecycle transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, id.String())); err != nil {
+		return "", false, err
+	}
+
+	now := r.clk.Now()
+	var title string
+	query := `
+		SELECT title FROM campaigns
+		WHERE id = $3 AND status = $1 AND start_time <= $2 AND (` + rl.cond + `)
+		FOR UPDATE`
+	err = tx.GetContext(ctx, &title, query, rl.from, now, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to lock campaign %s: %w", id, err)
+	}
+
+	if err := r.machine.Transition(ctx, id.String(), rl.from, rl.to); err != nil {
+		return "", false, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE campaigns SET status = $2, updated_at = NOW() WHERE id = $1`, id, rl.to); err != nil {
+		return "", false, fmt.Errorf("failed to update campaign %s status: %w", id, err)
+	}
+
+	actor, reason := actorID, rl.reason
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO campaign_status_history (
+			id, campaign_id, from_status, to_status, actor_id, actor_type, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		uuid.New(), id, rl.from, rl.to, &actor, audit.ActorSystem, &reason, now)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to record campaign %s transition: %w", id, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", false, fmt.Errorf("failed to commit campaign %s transition: %w", id, err)
+	}
+	return title, true, nil
+}
+
+// target is a device of an active participant
+type target struct {
+	UserID uuid.UUID `db:"user_id"`
+	Token  string    `db:"token"`
+}
+
+// notify pushes rl's message to the campaign's active participants who
+// have campaign milestones enabled. The move is already committed, so
+// failures are only logged.
+func (r *Runner) notify(ctx context.Context, rl rule, id uuid.UUID, title string) {
+	log := logger.FromContext(ctx)
+	topic := models.TopicCampaignMilestones
+
+	targets := []target{}
+	query := `
+		SELECT DISTINCT d.user_id, d.token
+		FROM participations p
+		JOIN device_tokens d ON d.user_id = p.user_id
+		LEFT JOIN notification_preferences np ON np.user_id = p.user_id
+		WHERE p.campaign_id = $1 AND p.status = $2
+			AND COALESCE(np.campaign_milestones, TRUE)`
+	if err := r.db.SelectContext(ctx, &targets, query, id, models.ParticipationActive); err != nil {
+		log.Error("failed to load push targets", "topic", topic, "error", err)
+		return
+	}
+
+	msg := push.Message{
+		Title: title,
+		Body:  rl.body,
+		Data: map[string]string{
+			"type":        rl.kind,
+			"campaign_id": id.String(),
+		},
+	}
+	for _, t := range targets {
+		err := r.sender.Send(ctx, t.Token, msg)
+		switch {
+		case err == nil:
+			metrics.PushNotifications.WithLabelValues(topic, "sent").Inc()
+		case errors.Is(err, push.ErrUnregistered):
+			metrics.PushNotifications.WithLabelValues(topic, "unregistered").Inc()
+			if _, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE token = $1`, t.Token); err != nil {
+				log.Warn("failed to delete unregistered device token", "error", err)
+			}
+		default:
+			metrics.PushNotifications.WithLabelValues(topic, "failed").Inc()
+			log.Warn("push failed", "topic", topic, logger.KeyUserID, t.UserID, "error", err)
+		}
+	}
+}
+
+// Run applies the rules every cfg.Interval until ctx is done
+func (r *Runner) Run(ctx context.Context) {
+	if r.cfg.Interval == 0 {
+		slog.Info("Campaign lifecycle job disabled")
+		return
+	}
+	for {
+		started := r.clk.Now()
+		n, err := r.Tick(ctx)
+		switch {
+		case err == nil:
+			slog.Debug("Campaign lifecycle checked", "moved", n, "duration", r.clk.Since(started))
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Campaign lifecycle check failed", "moved", n, "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clk.After(r.cfg.Interval):
+		}
+	}
+}
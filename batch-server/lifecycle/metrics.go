@@ -0,0 +1,21 @@
+package lifecycle
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var transitions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "campaign_lifecycle",
+		Name:      "transitions_total",
+		Help:      "Automatic campaign status changes by target status and outcome.",
+	},
+	[]string{"status", "result"},
+)
+
+func init() {
+	metrics.MustRegister(transitions)
+}
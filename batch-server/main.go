@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Reserve-to-save-backend/batch-server/disputes"
+	"github.com/Reserve-to-save-backend/batch-server/export"
+	"github.com/Reserve-to-save-backend/batch-server/lifecycle"
+	"github.com/Reserve-to-save-backend/batch-server/notify"
+	"github.com/Reserve-to-save-backend/batch-server/outbox"
+	"github.com/Reserve-to-save-backend/batch-server/stats"
+	"github.com/Reserve-to-save-backend/batch-server/watchlist"
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/config"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/diag"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/Reserve-to-save-backend/pkg/line"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/mail"
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/pkg/objectstore"
+	"github.com/Reserve-to-save-backend/pkg/push"
+)
+
+func main() {
+	// -export-day exports one day and exits, for backfills by hand
+	exportDay := flag.String("export-day", "", "export this day (YYYY-MM-DD) and exit")
+	flag.Parse()
+
+	envErr := godotenv.Load()
+
+	var cfg Config
+	config.MustLoad(&cfg)
+
+	logger.Init("batch-server", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
+	}
+
+	if err := errreport.Init("batch-server", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
+	}
+	defer errreport.Flush()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.NewDB(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	store, err := objectstore.New(cfg.ObjectStore)
+	if err != nil {
+		logger.Fatal("Failed to initialize object store", "error", err)
+	}
+
+	clk := clock.New()
+	var bq *export.BigQuery
+	if cfg.Export.BigQuery.ProjectID != "" {
+		if !strings.HasPrefix(store.URI(""), "gs://") {
+			logger.Fatal("BigQuery loads need the GCS object store (OBJECT_STORE_ENDPOINT=storage.googleapis.com)")
+		}
+		if bq, err = export.NewBigQuery(ctx, cfg.Export.BigQuery, clk); err != nil {
+			logger.Fatal("Failed to initialize BigQuery", "error", err)
+		}
+	}
+	exporter := export.NewExporter(db, store, bq, cfg.Export, clk)
+	aggregator := stats.NewAggregator(db, cfg.Stats, clk)
+
+	sender, err := push.New(ctx, cfg.Push)
+	if err != nil {
+		logger.Fatal("Failed to initialize push notifications", "error", err)
+	}
+	runner := lifecycle.NewRunner(db, cfg.Lifecycle, clk, sender)
+	notifier := watchlist.NewNotifier(db, cfg.Watchlist, clk, sender)
+	dispatcher := notify.NewDispatcher(db, cfg.Notify, clk, line.New(cfg.Line), mail.New(cfg.Mail))
+	monitor := disputes.NewMonitor(db, cfg.Disputes, clk)
+
+	// Redis carries the outbox relay's event stream; only the relay needs it
+	var redis *database.RedisClient
+	if cfg.Outbox.Interval != 0 {
+		if redis, err = database.NewRedisClient(database.RedisConfigFromEnv()); err != nil {
+			logger.Fatal("Failed to connect to Redis", "error", err)
+		}
+		defer redis.Close()
+	}
+	relay := outbox.NewRelay(db, redis, cfg.Outbox, clk)
+
+	if *exportDay != "" {
+		day, err := time.Parse(time.DateOnly, *exportDay)
+		if err != nil {
+			logger.Fatal("Invalid -export-day", "error", err)
+		}
+		if err := exporter.Export(ctx, day); err != nil {
+			logger.Fatal("Export failed", "error", err)
+		}
+		return
+	}
+
+	checker := health.NewChecker("batch-server")
+	checker.Add("postgres", health.Database(db))
+	if redis != nil {
+		checker.Add("redis", health.Redis(redis.UniversalClient))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metrics.Path, metrics.Handler())
+	mux.Handle("/live", checker.LiveHandler())
+	mux.Handle("/ready", checker.ReadyHandler())
+	go func() {
+		slog.Info("Metrics server starting", "port", cfg.MetricsPort)
+		if err := http.ListenAndServe(":"+cfg.MetricsPort, mux); err != nil {
+			logger.Fatal("Failed to serve metrics", "error", err)
+		}
+	}()
+
+	if err := diag.Start(cfg.DebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
+	}
+
+	slog.Info("Batch server starting")
+	var wg sync.WaitGroup
+	wg.Add(6)
+	go func() {
+		defer wg.Done()
+		aggregator.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		runner.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		notifier.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		monitor.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		relay.Run(ctx)
+	}()
+	exporter.Run(ctx)
+	wg.Wait()
+	slog.Info("Batch server stopped")
+}
@@ -0,0 +1,21 @@
+package notify
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var deliveries = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "notify",
+		Name:      "deliveries_total",
+		Help:      "LINE and email delivery attempts by channel and result (sent, retry, failed).",
+	},
+	[]string{"channel", "result"},
+)
+
+func init() {
+	metrics.MustRegister(deliveries)
+}
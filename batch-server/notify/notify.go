@@ -0,0 +1,195 @@
+// Package notify delivers the LINE messages and emails core-server queues
+// in notification_deliveries (campaign reached, rebate settled,
+// cancellation confirmed).
+//
+// Each tick claims due deliveries by pushing their next_attempt_at a lease
+// ahead, so a sender that dies mid-batch leaves them to be retried once the
+// lease runs out rather than lost. Delivery is therefore at least once; LINE
+// drops repeats itself because the delivery id is sent as the retry key.
+// Failures are retried with doubling backoff until MaxAttempts; messages
+// LINE rejects for good fail at once.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/line"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/mail"
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often due deliveries are sent; 0 disables the job
+	Interval time.Duration `env:"NOTIFY_INTERVAL" default:"30s"`
+	// BatchSize caps the deliveries sent per tick
+	BatchSize int `env:"NOTIFY_BATCH_SIZE" default:"100"`
+	// MaxAttempts is how often a delivery is tried before it fails
+	MaxAttempts int `env:"NOTIFY_MAX_ATTEMPTS" default:"5"`
+	// RetryBackoff is the wait before the first retry, doubling after that
+	RetryBackoff time.Duration `env:"NOTIFY_RETRY_BACKOFF" default:"1m"`
+	// Lease is how long a claimed delivery is left to its sender before
+	// another tick may send it again; keep it above a batch's send time
+	Lease time.Duration `env:"NOTIFY_LEASE" default:"5m"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("NOTIFY_INTERVAL must not be negative")
+	}
+	if c.BatchSize <= 0 || c.MaxAttempts <= 0 {
+		return errors.New("NOTIFY_BATCH_SIZE and NOTIFY_MAX_ATTEMPTS must be positive")
+	}
+	if c.RetryBackoff <= 0 || c.Lease <= 0 {
+		return errors.New("NOTIFY_RETRY_BACKOFF and NOTIFY_LEASE must be positive")
+	}
+	return nil
+}
+
+// Dispatcher sends queued deliveries
+type Dispatcher struct {
+	db   *database.DB
+	cfg  Config
+	clk  clock.Clock
+	line line.Sender
+	mail mail.Sender
+}
+
+func NewDispatcher(db *database.DB, cfg Config, clk clock.Clock, lineSender line.Sender, mailSender mail.Sender) *Dispatcher {
+	return &Dispatcher{db: db, cfg: cfg, clk: clock.OrSystem(clk), line: lineSender, mail: mailSender}
+}
+
+// delivery is a claimed notification_deliveries row
+type delivery struct {
+	ID        uuid.UUID `db:"id"`
+	Kind      string    `db:"kind"`
+	Channel   string    `db:"channel"`
+	Recipient string    `db:"recipient"`
+	Subject   string    `db:"subject"`
+	Body      string    `db:"body"`
+	Attempts  int       `db:"attempts"`
+}
+
+// Tick sends one batch of due deliveries and returns how many were sent
+func (d *Dispatcher) Tick(ctx context.Context) (int, error) {
+	due, err := d.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, dl := range due {
+		err := d.send(ctx, dl)
+		if err == nil {
+			sent++
+		}
+		if err := d.record(ctx, dl, err); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// claim leases up to BatchSize due deliveries and counts the attempt.
+// Deliveries another tick is claiming are skipped.
+func (d *Dispatcher) claim(ctx context.Context) ([]delivery, error) {
+	now := d.clk.Now()
+	query := `
+		WITH due AS (
+			SELECT id FROM notification_deliveries
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notification_deliveries nd
+		SET attempts = nd.attempts + 1, next_attempt_at = $3
+		FROM due
+		WHERE nd.id = due.id
+		RETURNING nd.id, nd.kind, nd.channel, nd.recipient, nd.subject, nd.body, nd.attempts`
+
+	due := []delivery{}
+	if err := d.db.SelectContext(ctx, &due, query, models.DeliveryPending, now, now.Add(d.cfg.Lease), d.cfg.BatchSize); err != nil {
+		return nil, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+	return due, nil
+}
+
+// send delivers dl on its channel
+func (d *Dispatcher) send(ctx context.Context, dl delivery) error {
+	switch dl.Channel {
+	case models.ChannelLine:
+		return d.line.Push(ctx, dl.ID.String(), dl.Recipient, dl.Subject+"\n\n"+dl.Body)
+	case models.ChannelEmail:
+		return d.mail.Send(ctx, mail.Message{To: dl.Recipient, Subject: dl.Subject, Body: dl.Body})
+	}
+	return fmt.Errorf("unknown notification channel %q", dl.Channel)
+}
+
+// record stores the outcome of sending dl: sent, due again after the
+// backoff, or failed when it was rejected or out of attempts
+func (d *Dispatcher) record(ctx context.Context, dl delivery, sendErr error) error {
+	now := d.clk.Now()
+	var err error
+	switch {
+	case sendErr == nil:
+		deliveries.WithLabelValues(dl.Channel, "sent").Inc()
+		_, err = d.db.ExecContext(ctx, `
+			UPDATE notification_deliveries SET status = $2, sent_at = $3, last_error = NULL
+			WHERE id = $1`, dl.ID, models.DeliverySent, now)
+	case errors.Is(sendErr, line.ErrRejected) || dl.Attempts >= d.cfg.MaxAttempts:
+		deliveries.WithLabelValues(dl.Channel, "failed").Inc()
+		logger.FromContext(ctx).Warn("notification delivery failed", "id", dl.ID, "kind", dl.Kind, "channel", dl.Channel, "attempts", dl.Attempts, "error", sendErr)
+		_, err = d.db.ExecContext(ctx, `
+			UPDATE notification_deliveries SET status = $2, last_error = $3
+			WHERE id = $1`, dl.ID, models.DeliveryFailed, sendErr.Error())
+	default:
+		deliveries.WithLabelValues(dl.Channel, "retry").Inc()
+		backoff := d.cfg.RetryBackoff << min(dl.Attempts-1, 16)
+		_, err = d.db.ExecContext(ctx, `
+			UPDATE notification_deliveries SET next_attempt_at = $2, last_error = $3
+			WHERE id = $1`, dl.ID, now.Add(backoff), sendErr.Error())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record notification delivery %s: %w", dl.ID, err)
+	}
+	return nil
+}
+
+// Run sends due deliveries every cfg.Interval until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d.cfg.Interval == 0 {
+		slog.Info("LINE and email notifications disabled")
+		return
+	}
+	for {
+		started := d.clk.Now()
+		sent, err := d.Tick(ctx)
+		switch {
+		case err == nil:
+			slog.Debug("Notifications delivered", "sent", sent, "duration", d.clk.Since(started))
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Notification delivery failed", "sent", sent, "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.clk.After(d.cfg.Interval):
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var (
+	published = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "outbox",
+			Name:      "published_total",
+			Help:      "Domain events published to the event stream by type.",
+		},
+		[]string{"type"},
+	)
+	backlog = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "outbox",
+			Name:      "backlog",
+			Help:      "Domain events not yet published.",
+		},
+	)
+	lag = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "outbox",
+			Name:      "lag_seconds",
+			Help:      "Age of the oldest unpublished domain event, 0 when there is none.",
+		},
+	)
+)
+
+func init() {
+	metrics.MustRegister(published, backlog, lag)
+}
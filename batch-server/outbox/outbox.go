@@ -0,0 +1,147 @@
+// Package outbox relays the domain events core-server appends to
+// outbox_events (see pkg/outbox) to the r2s:events Redis stream.
+//
+// Every tick publishes unpublished events in batches, lowest seq first,
+// until none are left, then refreshes the backlog gauges. A batch is marked
+// published in the transaction that claimed it, so a relay that fails to
+// publish or to commit leaves it to the next tick: delivery is at least
+// once and consumers drop repeats by event id. Published events are kept
+// for Retention, then pruned.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/outbox"
+)
+
+// pruneEvery is how often published events past their retention are
+// deleted
+const pruneEvery = time.Hour
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often unpublished events are relayed; 0 disables the
+	// job and with it batch-server's Redis connection
+	Interval time.Duration `env:"OUTBOX_INTERVAL" default:"1s"`
+	// BatchSize caps the events published in one transaction
+	BatchSize int `env:"OUTBOX_BATCH_SIZE" default:"500"`
+	// StreamMaxLen trims the stream to about this many entries; 0 never trims
+	StreamMaxLen int64 `env:"OUTBOX_STREAM_MAXLEN" default:"1000000"`
+	// Retention is how long published events stay in outbox_events
+	Retention time.Duration `env:"OUTBOX_RETENTION" default:"168h"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 || c.StreamMaxLen < 0 {
+		return errors.New("OUTBOX_INTERVAL and OUTBOX_STREAM_MAXLEN must not be negative")
+	}
+	if c.BatchSize <= 0 || c.Retention <= 0 {
+		return errors.New("OUTBOX_BATCH_SIZE and OUTBOX_RETENTION must be positive")
+	}
+	return nil
+}
+
+// Relay publishes stored events
+type Relay struct {
+	store     *outbox.Store
+	publisher *outbox.Publisher
+	cfg       Config
+	clk       clock.Clock
+
+	prunedAt time.Time
+}
+
+// NewRelay returns a relay publishing on redis, which may be nil when the
+// relay is disabled
+func NewRelay(db *database.DB, redis *database.RedisClient, cfg Config, clk clock.Clock) *Relay {
+	r := &Relay{store: outbox.NewStore(db, clk), cfg: cfg, clk: clock.OrSystem(clk)}
+	if redis != nil {
+		r.publisher = outbox.NewPublisher(redis.UniversalClient, cfg.StreamMaxLen)
+	}
+	return r
+}
+
+// Tick publishes every unpublished event, prunes when due and returns how
+// many events were published
+func (r *Relay) Tick(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		var sent []outbox.Record
+		n, err := r.store.Relay(ctx, r.cfg.BatchSize, func(ctx context.Context, records []outbox.Record) error {
+			if err := r.publisher.Publish(ctx, records); err != nil {
+				return err
+			}
+			sent = records
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, rec := range sent {
+			published.WithLabelValues(rec.Type).Inc()
+		}
+		total += n
+		if n < r.cfg.BatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	now := r.clk.Now()
+	if now.Sub(r.prunedAt) >= pruneEvery {
+		pruned, err := r.store.Prune(ctx, now.Add(-r.cfg.Retention))
+		if err != nil {
+			return total, err
+		}
+		r.prunedAt = now
+		slog.Debug("Outbox pruned", "events", pruned)
+	}
+
+	count, oldest, err := r.store.Backlog(ctx)
+	if err != nil {
+		return total, err
+	}
+	backlog.Set(float64(count))
+	if oldest.IsZero() {
+		lag.Set(0)
+	} else {
+		lag.Set(now.Sub(oldest).Seconds())
+	}
+	return total, nil
+}
+
+// Run relays events every cfg.Interval until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	if r.cfg.Interval == 0 {
+		slog.Info("Outbox relay disabled")
+		return
+	}
+	for {
+		started := r.clk.Now()
+		sent, err := r.Tick(ctx)
+		switch {
+		case err == nil:
+			if sent > 0 {
+				slog.Debug("Outbox relayed", "published", sent, "duration", r.clk.Since(started))
+			}
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Outbox relay failed", "published", sent, "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clk.After(r.cfg.Interval):
+		}
+	}
+}
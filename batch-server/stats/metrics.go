@@ -0,0 +1,21 @@
+package stats
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var refreshRuns = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "campaign_stats",
+		Name:      "refreshes_total",
+		Help:      "Campaign stats refreshes by outcome.",
+	},
+	[]string{"result"},
+)
+
+func init() {
+	metrics.MustRegister(refreshRuns)
+}
@@ -0,0 +1,126 @@
+// Package stats maintains campaign_stats_daily, the per-day aggregates of
+// campaign participation behind the query server's GetCampaignStats.
+//
+// Every refresh upserts today's row of each open campaign from
+// participations, so a day's row ends up holding the campaign's standing at
+// the last refresh of that day. Rows of past days are never rewritten.
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often the aggregates are refreshed; 0 disables them
+	Interval time.Duration `env:"STATS_INTERVAL" default:"15m"`
+	// QueryTimeout replaces DB_STATEMENT_TIMEOUT for the refresh
+	QueryTimeout time.Duration `env:"STATS_QUERY_TIMEOUT" default:"5m"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("STATS_INTERVAL must not be negative")
+	}
+	return nil
+}
+
+// Aggregator refreshes campaign_stats_daily
+type Aggregator struct {
+	db  *database.DB
+	cfg Config
+	clk clock.Clock
+}
+
+func NewAggregator(db *database.DB, cfg Config, clk clock.Clock) *Aggregator {
+	return &Aggregator{db: db, cfg: cfg, clk: clock.OrSystem(clk)}
+}
+
+// refreshQuery upserts the day's row of every campaign still open to joins
+// or which ended less than a day ago, so closed campaigns get a final row
+const refreshQuery = `
+INSERT INTO campaign_stats_daily
+    (campaign_id, day, participant_count, funded_count, cancelled_count, total_deposit, updated_at)
+SELECT p.campaign_id, $1::DATE,
+    COUNT(*),
+    COUNT(*) FILTER (WHERE p.status NOT IN ($2, $3)),
+    COUNT(*) FILTER (WHERE p.status = $2),
+    COALESCE(SUM(p.deposit_amount) FILTER (WHERE p.status NOT IN ($2, $3)), 0),
+    now()
+FROM participations p
+JOIN campaigns c ON c.id = p.campaign_id
+WHERE c.status IN ($4, $5) OR c.end_time >= $6
+GROUP BY p.campaign_id
+ON CONFLICT (campaign_id, day) DO UPDATE SET
+    participant_count = EXCLUDED.participant_count,
+    funded_count = EXCLUDED.funded_count,
+    cancelled_count = EXCLUDED.cancelled_count,
+    total_deposit = EXCLUDED.total_deposit,
+    updated_at = EXCLUDED.updated_at`
+
+// Refresh upserts today's (UTC) aggregates and returns how many campaigns
+// were written
+func (a *Aggregator) Refresh(ctx context.Context) (int64, error) {
+	now := a.clk.Now().UTC()
+	day := now.Truncate(24 * time.Hour)
+
+	tx, err := a.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin stats transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := database.SetLocalStatementTimeout(ctx, tx, a.cfg.QueryTimeout); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, refreshQuery,
+		day.Format(time.DateOnly), models.ParticipationCancelled, models.ParticipationRefunded,
+		string(models.StatusRecruiting), string(models.StatusReached), now.Add(-24*time.Hour))
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh campaign stats: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit campaign stats: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Run refreshes the aggregates every cfg.Interval until ctx is done
+func (a *Aggregator) Run(ctx context.Context) {
+	if a.cfg.Interval == 0 {
+		slog.Info("Campaign stats disabled")
+		return
+	}
+	for {
+		started := a.clk.Now()
+		n, err := a.Refresh(ctx)
+		switch {
+		case err == nil:
+			refreshRuns.WithLabelValues("ok").Inc()
+			slog.Debug("Campaign stats refreshed", "campaigns", n, "duration", a.clk.Since(started))
+		case ctx.Err() != nil:
+			return
+		default:
+			refreshRuns.WithLabelValues("error").Inc()
+			slog.Error("Campaign stats refresh failed", "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.clk.After(a.cfg.Interval):
+		}
+	}
+}
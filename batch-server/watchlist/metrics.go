@@ -0,0 +1,21 @@
+package watchlist
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var alerts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "watchlist",
+		Name:      "alerts_total",
+		Help:      "Watchlist pushes delivered by alert kind.",
+	},
+	[]string{"alert"},
+)
+
+func init() {
+	metrics.MustRegister(alerts)
+}
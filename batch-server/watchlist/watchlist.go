@@ -0,0 +1,227 @@
+// Package watchlist pushes to users who favorited a campaign
+// (campaign_favorites) when it is worth another look:
+//
+//   - near target: a recruiting campaign's current_qty reached
+//     NearTargetPercent of its min_qty
+//   - closing: a recruiting or reached campaign's recruitment ends within
+//     ClosingWithin; deposits lock at end_time, so this is the last chance
+//     to join
+//
+// Each favorite gets each alert at most once. The alert is marked on the
+// favorite in the statement that picks its devices, before the push is
+// sent, so a crash in between loses the push rather than repeating it.
+// Users who already joined the campaign get its milestone pushes instead
+// and are skipped; users with the watchlist preference off are marked but
+// not pushed.
+package watchlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/push"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// Interval is how often favorites are checked; 0 disables the job
+	Interval time.Duration `env:"WATCHLIST_INTERVAL" default:"5m"`
+	// NearTargetPercent of min_qty joined counts as near the target
+	NearTargetPercent int `env:"WATCHLIST_NEAR_TARGET_PERCENT" default:"80"`
+	// ClosingWithin is how long before end_time the closing alert goes out
+	ClosingWithin time.Duration `env:"WATCHLIST_CLOSING_WITHIN" default:"24h"`
+	// BatchSize caps the favorites alerted per alert and tick
+	BatchSize int `env:"WATCHLIST_BATCH_SIZE" default:"500"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("WATCHLIST_INTERVAL must not be negative")
+	}
+	if c.NearTargetPercent <= 0 || c.NearTargetPercent > 100 {
+		return errors.New("WATCHLIST_NEAR_TARGET_PERCENT must be between 1 and 100")
+	}
+	if c.ClosingWithin <= 0 {
+		return errors.New("WATCHLIST_CLOSING_WITHIN must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return errors.New("WATCHLIST_BATCH_SIZE must be positive")
+	}
+	return nil
+}
+
+// alert is one watchlist push. cond is a SQL condition on the campaign c
+// where $1 is the current time and $2 the alert's threshold.
+type alert struct {
+	column string
+	cond   string
+	kind   string
+	body   string
+}
+
+var (
+	nearTarget = alert{
+		column: "near_target_notified_at",
+		cond: `c.status = 'recruiting' AND c.end_time > $1 AND c.min_qty > 0
+			AND COALESCE(c.current_qty, 0) * 100 >= c.min_qty * $2`,
+		kind: "watchlist_near_target",
+		body: "A campaign on your watchlist is close to its goal.",
+	}
+	closing = alert{
+		column: "closing_notified_at",
+		cond:   `c.status IN ('recruiting', 'reached') AND c.end_time > $1 AND c.end_time <= $2`,
+		kind:   "watchlist_closing",
+		body:   "A campaign on your watchlist closes soon. Join before deposits lock.",
+	}
+)
+
+// Notifier sends the watchlist alerts
+type Notifier struct {
+	db     *database.DB
+	cfg    Config
+	clk    clock.Clock
+	sender push.Sender
+}
+
+func NewNotifier(db *database.DB, cfg Config, clk clock.Clock, sender push.Sender) *Notifier {
+	return &Notifier{db: db, cfg: cfg, clk: clock.OrSystem(clk), sender: sender}
+}
+
+// Tick sends each alert to one batch of favorites that are due and returns
+// how many pushes were attempted
+func (n *Notifier) Tick(ctx context.Context) (int, error) {
+	now := n.clk.Now()
+	sent := 0
+	steps := []struct {
+		alert     alert
+		threshold interface{}
+	}{
+		{nearTarget, n.cfg.NearTargetPercent},
+		{closing, now.Add(n.cfg.ClosingWithin)},
+	}
+	for _, step := range steps {
+		targets, err := n.claim(ctx, step.alert, now, step.threshold)
+		if err != nil {
+			return sent, err
+		}
+		n.send(ctx, step.alert, targets)
+		sent += len(targets)
+	}
+	return sent, nil
+}
+
+// target is a device of a user to alert about a campaign
+type target struct {
+	CampaignID uuid.UUID `db:"campaign_id"`
+	Title      string    `db:"title"`
+	UserID     uuid.UUID `db:"user_id"`
+	Token      string    `db:"token"`
+}
+
+// claim marks up to BatchSize due favorites as alerted and returns the
+// devices of their users who have the watchlist topic enabled. Favorites
+// another tick is claiming are skipped.
+func (n *Notifier) claim(ctx context.Context, a alert, now time.Time, threshold interface{}) ([]target, error) {
+	query := `
+		WITH due AS (
+			SELECT f.user_id, f.campaign_id
+			FROM campaign_favorites f
+			JOIN campaigns c ON c.id = f.campaign_id
+			WHERE f.` + a.column + ` IS NULL AND (` + a.cond + `)
+				AND NOT EXISTS (
+					SELECT 1 FROM participations p
+					WHERE p.campaign_id = f.campaign_id AND p.user_id = f.user_id AND p.status = $4
+				)
+			ORDER BY f.campaign_id, f.user_id
+			LIMIT $3
+			FOR UPDATE OF f SKIP LOCKED
+		), claimed AS (
+			UPDATE campaign_favorites f SET ` + a.column + ` = $1
+			FROM due
+			WHERE f.user_id = due.user_id AND f.campaign_id = due.campaign_id
+			RETURNING f.user_id, f.campaign_id
+		)
+		SELECT cl.campaign_id, c.title, d.user_id, d.token
+		FROM claimed cl
+		JOIN campaigns c ON c.id = cl.campaign_id
+		JOIN device_tokens d ON d.user_id = cl.user_id
+		LEFT JOIN notification_preferences np ON np.user_id = cl.user_id
+		WHERE COALESCE(np.watchlist, TRUE)`
+
+	targets := []target{}
+	if err := n.db.SelectContext(ctx, &targets, query, now, threshold, n.cfg.BatchSize, models.ParticipationActive); err != nil {
+		return nil, fmt.Errorf("failed to claim %s alerts: %w", a.kind, err)
+	}
+	return targets, nil
+}
+
+// send pushes a's message to each target. The favorites are already
+// marked, so failures are only logged.
+func (n *Notifier) send(ctx context.Context, a alert, targets []target) {
+	log := logger.FromContext(ctx)
+	topic := models.TopicWatchlist
+
+	for _, t := range targets {
+		msg := push.Message{
+			Title: t.Title,
+			Body:  a.body,
+			Data: map[string]string{
+				"type":        a.kind,
+				"campaign_id": t.CampaignID.String(),
+			},
+		}
+		err := n.sender.Send(ctx, t.Token, msg)
+		switch {
+		case err == nil:
+			metrics.PushNotifications.WithLabelValues(topic, "sent").Inc()
+			alerts.WithLabelValues(a.kind).Inc()
+		case errors.Is(err, push.ErrUnregistered):
+			metrics.PushNotifications.WithLabelValues(topic, "unregistered").Inc()
+			if _, err := n.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE token = $1`, t.Token); err != nil {
+				log.Warn("failed to delete unregistered device token", "error", err)
+			}
+		default:
+			metrics.PushNotifications.WithLabelValues(topic, "failed").Inc()
+			log.Warn("push failed", "topic", topic, logger.KeyUserID, t.UserID, "error", err)
+		}
+	}
+}
+
+// Run sends the alerts every cfg.Interval until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	if n.cfg.Interval == 0 {
+		slog.Info("Watchlist alerts disabled")
+		return
+	}
+	for {
+		started := n.clk.Now()
+		sent, err := n.Tick(ctx)
+		switch {
+		case err == nil:
+			slog.Debug("Watchlist checked", "pushes", sent, "duration", n.clk.Since(started))
+		case ctx.Err() != nil:
+			return
+		default:
+			slog.Error("Watchlist check failed", "pushes", sent, "error", err)
+			errreport.Report(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.clk.After(n.cfg.Interval):
+		}
+	}
+}
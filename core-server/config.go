@@ -0,0 +1,53 @@
+package main
+
+import (
+	"r2s/core-server/services"
+	"r2s/pkg/database"
+	"r2s/pkg/errreport"
+	"r2s/pkg/jwks"
+	"r2s/pkg/logger"
+	"r2s/pkg/media"
+	"r2s/pkg/metadata"
+	"r2s/pkg/objectstore"
+	"r2s/pkg/push"
+	"r2s/pkg/referral"
+	"r2s/pkg/server"
+	"r2s/pkg/svcauth"
+	"r2s/pkg/tracing"
+)
+
+// Config is the core-server configuration, loaded by config.MustLoad
+type Config struct {
+	Port                 string `env:"CORE_SERVER_PORT" default:"3003"`
+	PaymentWebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET" secret:"true"`
+
+	// DebugAddr serves pkg/diag (empty disables); keep it off the public network
+	DebugAddr string `env:"CORE_DEBUG_ADDR"`
+
+	Database database.Config
+	Log      logger.Config
+	Tracing  tracing.Config
+	Errors   errreport.Config
+	Server   server.Config
+	Push     push.Config
+	Metadata metadata.Config
+	// JWKS verifies bearer tokens forwarded by the gateway, and internal
+	// tokens of calling services
+	JWKS jwks.Config
+	// Internal requires callers to be services allowed to call core-server
+	Internal svcauth.Config
+	// ObjectStore holds metadata for the store metadata driver, and media
+	// uploads
+	ObjectStore objectstore.Config
+	// Media turns on image uploads; they need an S3 object store
+	Media media.Config
+	// Referral sets the bonuses credited when a referral qualifies
+	Referral referral.Config
+	// Fulfillment sets how long participants have to confirm fulfillments
+	Fulfillment services.FulfillmentConfig
+	// Disputes sets the resolution and refund SLAs and how long settlements
+	// stay disputable
+	Disputes services.DisputeConfig
+	// Vouchers sets the secret redemption codes are derived from
+	Vouchers services.VoucherConfig
+}
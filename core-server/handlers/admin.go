@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"r2s/core-server/repository"
+	"r2s/core-server/services"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/pagination"
+	"r2s/pkg/rbac"
+)
+
+// AdminHandler serves the admin dashboard API. It is only reachable through
+// the gateway's /api/admin group, which requires the admin role and MFA.
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// Overview handles GET /admin/overview
+func (h *AdminHandler) Overview(c *gin.Context) {
+	overview, err := h.adminService.Overview(c.Request.Context())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    overview,
+	})
+}
+
+// ListUsers handles GET /admin/users?q=&status=&role=
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	users, total, err := h.adminService.ListUsers(c.Request.Context(), repository.UserFilter{
+		Query:  c.Query("q"),
+		Status: c.Query("status"),
+		Role:   c.Query("role"),
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       users,
+		"pagination": page.Result(total),
+	})
+}
+
+// ListMerchants handles GET /admin/merchants?q=<wallet>
+func (h *AdminHandler) ListMerchants(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	merchants, total, err := h.adminService.ListMerchants(c.Request.Context(), c.Query("q"), page.Limit, page.Offset)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       merchants,
+		"pagination": page.Result(total),
+	})
+}
+
+// ListCampaigns handles GET /admin/campaigns?q=&status=&merchantId=
+func (h *AdminHandler) ListCampaigns(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	merchantID, err := optionalUUID(c, "merchantId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	campaigns, total, err := h.adminService.ListCampaigns(c.Request.Context(), repository.AdminCampaignFilter{
+		Query:      c.Query("q"),
+		Status:     c.Query("status"),
+		MerchantID: merchantID,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       campaigns,
+		"pagination": page.Result(total),
+	})
+}
+
+// ListPayments handles GET /admin/payments?status=&mode=&campaignId=&userId=
+func (h *AdminHandler) ListPayments(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	campaignID, err := optionalUUID(c, "campaignId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	userID, err := optionalUUID(c, "userId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	payments, total, err := h.adminService.ListPayments(c.Request.Context(), repository.PaymentFilter{
+		Status:     c.Query("status"),
+		Mode:       c.Query("mode"),
+		CampaignID: campaignID,
+		UserID:     userID,
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       payments,
+		"pagination": page.Result(total),
+	})
+}
+
+// SuspendUsers handles POST /admin/users/suspend
+func (h *AdminHandler) SuspendUsers(c *gin.Context) {
+	h.bulk(c, h.adminService.SuspendUsers, true)
+}
+
+// ReinstateUsers handles POST /admin/users/reinstate
+func (h *AdminHandler) ReinstateUsers(c *gin.Context) {
+	h.bulk(c, h.adminService.ReinstateUsers, false)
+}
+
+// SetUserRole handles POST /admin/users/role with {"ids", "role", "reason"}
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil || !rbac.Valid(req.Role) {
+		ginerrors.BadRequest(c, "role must be one of user, merchant, ops, admin")
+		return
+	}
+	h.bulk(c, func(ctx context.Context, ids []uuid.UUID, reason string) []services.BulkResult {
+		return h.adminService.SetUserRoles(ctx, ids, req.Role, reason)
+	}, true)
+}
+
+// PauseCampaigns handles POST /admin/campaigns/pause
+func (h *AdminHandler) PauseCampaigns(c *gin.Context) {
+	h.bulk(c, h.adminService.PauseCampaigns, true)
+}
+
+// ResumeCampaigns handles POST /admin/campaigns/resume
+func (h *AdminHandler) ResumeCampaigns(c *gin.Context) {
+	h.bulk(c, h.adminService.ResumeCampaigns, false)
+}
+
+// bulk binds {"ids": [...], "reason": "..."} and runs action over the ids.
+// The response is 200 with a per-id result even when some ids fail.
+func (h *AdminHandler) bulk(c *gin.Context, action func(ctx context.Context, ids []uuid.UUID, reason string) []services.BulkResult, requireReason bool) {
+	var req struct {
+		IDs    []uuid.UUID `json:"ids" binding:"required"`
+		Reason string      `json:"reason"`
+	}
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if requireReason && req.Reason == "" {
+		ginerrors.BadRequest(c, "reason is required")
+		return
+	}
+	if len(req.IDs) == 0 || len(req.IDs) > services.MaxBulkItems {
+		ginerrors.Respond(c, apperrors.Catalog(apperrors.ReasonBulkSize, services.MaxBulkItems))
+		return
+	}
+
+	results := action(c.Request.Context(), req.IDs, req.Reason)
+	succeeded := 0
+	for _, r := range results {
+		if r.Success {
+			succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	})
+}
+
+// optionalUUID parses an optional UUID query parameter
+func optionalUUID(c *gin.Context, key string) (*uuid.UUID, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return nil, apperrors.InvalidArgument(fmt.Sprintf("Invalid %s", key))
+	}
+	return &id, nil
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"r2s/pkg/audit"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/pagination"
+)
+
+type AuditHandler struct {
+	store *audit.Store
+}
+
+func NewAuditHandler(store *audit.Store) *AuditHandler {
+	return &AuditHandler{
+		store: store,
+	}
+}
+
+// ListEntries handles GET /admin/audit-log. Optional filters: actorId,
+// actorType, action, resourceType, resourceId and an RFC 3339 from/to range
+// (from inclusive, to exclusive).
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	filter := audit.Filter{
+		ActorID:      c.Query("actorId"),
+		ActorType:    c.Query("actorType"),
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resourceType"),
+		ResourceID:   c.Query("resourceId"),
+		Limit:        page.Limit,
+		Offset:       page.Offset,
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		ginerrors.BadRequest(c, "Invalid from time")
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		ginerrors.BadRequest(c, "Invalid to time")
+		return
+	}
+
+	entries, total, err := h.store.List(c.Request.Context(), filter)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       entries,
+		"pagination": page.Result(total),
+	})
+}
+
+// parseTimeQuery returns the zero time when the parameter is absent
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,486 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/repository"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/validate"
+)
+
+type CampaignHandler struct {
+	campaignService *services.CampaignService
+	metadataService *services.MetadataService
+}
+
+func NewCampaignHandler(campaignService *services.CampaignService, metadataService *services.MetadataService) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: campaignService,
+		metadataService: metadataService,
+	}
+}
+
+// ListCampaigns handles GET /campaigns
+func (h *CampaignHandler) ListCampaigns(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	var sortByCategory bool
+	switch c.Query("sort") {
+	case "", "newest":
+	case "category":
+		sortByCategory = true
+	default:
+		ginerrors.BadRequest(c, "sort must be newest or category")
+		return
+	}
+
+	campaigns, total, err := h.campaignService.ListCampaigns(c.Request.Context(), repository.CampaignFilter{
+		Status:         c.Query("status"),
+		Category:       c.Query("category"),
+		Tag:            strings.ToLower(c.Query("tag")),
+		SortByCategory: sortByCategory,
+		Limit:          page.Limit,
+		Offset:         page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       campaigns,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetCampaign handles GET /campaigns/:id
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	campaign, err := h.campaignService.GetCampaign(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// CreateCampaign handles POST /campaigns
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	var req struct {
+		Title          string        `json:"title" binding:"required"`
+		Description    *string       `json:"description"`
+		ImageID        *uuid.UUID    `json:"imageId"`
+		Category       *string       `json:"category"`
+		Tags           []string      `json:"tags"`
+		MerchantID     *uuid.UUID    `json:"merchantId"`
+		MerchantWallet string        `json:"merchantWallet" binding:"required"`
+		BasePrice      models.BigInt `json:"basePrice" binding:"required"`
+		MinQty         int           `json:"minQty" binding:"required"`
+		DiscountRate   int           `json:"discountRate"`
+		SaveFloorBps   int           `json:"saveFloorBps"`
+		RMaxBps        int           `json:"rMaxBps"`
+		StartTime      time.Time     `json:"startTime" binding:"required"`
+		EndTime        time.Time     `json:"endTime" binding:"required"`
+		Metadata       models.JSONB  `json:"metadata"`
+		// Cancellation window; see models.Campaign
+		CancelDeadline       *time.Time `json:"cancelDeadline"`
+		LateCancelPenaltyBps *int       `json:"lateCancelPenaltyBps"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	// The service checks the terms against the platform's bounds
+	campaign, err := h.campaignService.CreateCampaign(c.Request.Context(), services.CreateCampaignInput{
+		Title:          req.Title,
+		Description:    req.Description,
+		ImageID:        req.ImageID,
+		Category:       req.Category,
+		Tags:           req.Tags,
+		MerchantID:     req.MerchantID,
+		MerchantWallet: req.MerchantWallet,
+		BasePrice:      req.BasePrice.Int,
+		MinQty:         req.MinQty,
+		DiscountRate:   req.DiscountRate,
+		SaveFloorBps:   req.SaveFloorBps,
+		RMaxBps:        req.RMaxBps,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		Metadata:       req.Metadata,
+
+		CancelDeadline:       req.CancelDeadline,
+		LateCancelPenaltyBps: req.LateCancelPenaltyBps,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// CloneCampaign handles POST /campaigns/:id/clone
+func (h *CampaignHandler) CloneCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var req relaunchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	campaign, err := h.campaignService.CloneCampaign(c.Request.Context(), id, req.input())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// UpdateCampaign handles PUT /campaigns/:id
+func (h *CampaignHandler) UpdateCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var req struct {
+		Title        *string                `json:"title"`
+		Description  *string                `json:"description"`
+		ImageID      *uuid.UUID             `json:"imageId"`
+		Category     *string                `json:"category"`
+		Tags         []string               `json:"tags"`
+		StartTime    *time.Time             `json:"startTime"`
+		EndTime      *time.Time             `json:"endTime"`
+		Status       *models.CampaignStatus `json:"status"`
+		StatusReason string                 `json:"statusReason"`
+		// Version stands in for If-Match
+		Version *int64 `json:"version"`
+		// Cancellation terms can only change in draft
+		CancelDeadline       *time.Time `json:"cancelDeadline"`
+		LateCancelPenaltyBps *int       `json:"lateCancelPenaltyBps"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	if req.StartTime != nil && req.EndTime != nil {
+		if err := validate.TimeWindow("startTime", *req.StartTime, "endTime", *req.EndTime); err != nil {
+			ginerrors.Respond(c, err)
+			return
+		}
+	}
+
+	campaign, err := h.campaignService.UpdateCampaign(c.Request.Context(), id, services.UpdateCampaignInput{
+		Title:        req.Title,
+		Description:  req.Description,
+		ImageID:      req.ImageID,
+		Category:     req.Category,
+		Tags:         req.Tags,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Status:       req.Status,
+		StatusReason: req.StatusReason,
+		Version:      version,
+
+		CancelDeadline:       req.CancelDeadline,
+		LateCancelPenaltyBps: req.LateCancelPenaltyBps,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// GetCampaignHistory handles GET /campaigns/:id/history
+func (h *CampaignHandler) GetCampaignHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	transitions, err := h.campaignService.CampaignHistory(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transitions,
+	})
+}
+
+// PublishCampaignMetadata handles POST /campaigns/:id/metadata/publish. It
+// publishes the metadata document now, e.g. after a background publish
+// failed, and returns the campaign with its metadata URI.
+func (h *CampaignHandler) PublishCampaignMetadata(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	campaign, err := h.metadataService.Publish(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// SettleCampaign handles POST /campaigns/:id/settle
+func (h *CampaignHandler) SettleCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	result, err := h.campaignService.SettleCampaign(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// UpdateCampaignMetadata handles PATCH /campaigns/:id/metadata. The body is
+// merged into the stored object; keys set to null are removed.
+func (h *CampaignHandler) UpdateCampaignMetadata(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var patch models.JSONB
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.Metadata("metadata", patch); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	metadata, err := h.campaignService.UpdateCampaignMetadata(c.Request.Context(), id, patch)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metadata,
+	})
+}
+
+// SubmitCampaign handles POST /campaigns/:id/submit
+func (h *CampaignHandler) SubmitCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	// Optional; Version stands in for If-Match
+	var req struct {
+		Version *int64 `json:"version"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ginerrors.BadRequest(c, "Invalid request")
+			return
+		}
+	}
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	campaign, err := h.campaignService.SubmitCampaign(c.Request.Context(), id, version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
+
+// ReviewCampaign handles POST /campaigns/:id/review
+func (h *CampaignHandler) ReviewCampaign(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var req struct {
+		Decision string `json:"decision" binding:"required,oneof=approve reject"`
+		Comment  string `json:"comment"`
+		Version  *int64 `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	campaign, review, err := h.campaignService.ReviewCampaign(c.Request.Context(), id, version, req.Decision == "approve", strings.TrimSpace(req.Comment))
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"campaign": campaign,
+			"review":   review,
+		},
+	})
+}
+
+// GetCampaignReviews handles GET /campaigns/:id/reviews
+func (h *CampaignHandler) GetCampaignReviews(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	reviews, err := h.campaignService.CampaignReviews(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reviews,
+	})
+}
+
+// RecordDeployment handles POST /campaigns/:id/deployment, called once the
+// transaction built by tx-helper's /tx/deploy-campaign is mined
+func (h *CampaignHandler) RecordDeployment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var req struct {
+		ChainAddress string `json:"chainAddress" binding:"required"`
+		TxHash       string `json:"txHash" binding:"required"`
+		BlockNumber  int64  `json:"blockNumber" binding:"required,gt=0"`
+		Version      *int64 `json:"version"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.Address("chainAddress", req.ChainAddress); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	campaign, err := h.campaignService.RecordDeployment(c.Request.Context(), id, services.DeploymentInput{
+		ChainAddress: req.ChainAddress,
+		TxHash:       req.TxHash,
+		BlockNumber:  req.BlockNumber,
+		Version:      version,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+)
+
+// CategoryHandler serves the campaign category taxonomy; changes sit in
+// the admin group
+type CategoryHandler struct {
+	categoryService *services.CategoryService
+}
+
+func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+type categoryRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Position int    `json:"position"`
+}
+
+// ListCategories handles GET /categories
+func (h *CategoryHandler) ListCategories(c *gin.Context) {
+	categories, err := h.categoryService.ListCategories(c.Request.Context())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+	})
+}
+
+// CreateCategory handles POST /admin/categories
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req struct {
+		Slug     string `json:"slug" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+		Position int    `json:"position"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(c.Request.Context(), req.Slug, services.CategoryInput{
+		Name:     req.Name,
+		Position: req.Position,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    category,
+	})
+}
+
+// UpdateCategory handles PUT /admin/categories/:slug
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	var req categoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(c.Request.Context(), c.Param("slug"), services.CategoryInput{
+		Name:     req.Name,
+		Position: req.Position,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+	})
+}
+
+// DeleteCategory handles DELETE /admin/categories/:slug
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), c.Param("slug")); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/repository"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// DisputeHandler serves participants opening disputes of fulfillments and
+// settlements and admins resolving them. The gateway fills in :userId from
+// the caller's token.
+type DisputeHandler struct {
+	disputeService *services.DisputeService
+}
+
+func NewDisputeHandler(disputeService *services.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+	}
+}
+
+// Open handles POST /disputes/user/:userId with {"participationId", "kind",
+// "reason"}
+func (h *DisputeHandler) Open(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ParticipationID uuid.UUID `json:"participationId" binding:"required"`
+		Kind            string    `json:"kind" binding:"required,oneof=fulfillment settlement"`
+		Reason          string    `json:"reason" binding:"required,max=1000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	dispute, err := h.disputeService.Open(c.Request.Context(), userID, services.OpenDisputeInput{
+		ParticipationID: req.ParticipationID,
+		Kind:            req.Kind,
+		Reason:          strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    dispute,
+	})
+}
+
+// ListUserDisputes handles GET /disputes/user/:userId
+func (h *DisputeHandler) ListUserDisputes(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	disputes, total, err := h.disputeService.ListUserDisputes(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       disputes,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetUserDispute handles GET /disputes/user/:userId/:id
+func (h *DisputeHandler) GetUserDispute(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	id, ok := disputeParam(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.disputeService.GetUserDispute(c.Request.Context(), userID, id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dispute,
+	})
+}
+
+// ListDisputes handles GET /admin/disputes?status=&kind=&campaignId=&userId=&overdue=
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	switch c.Query("status") {
+	case "", models.DisputeOpen, models.DisputeRefunding, models.DisputeRefunded, models.DisputeRejected, models.DisputeRedelivered:
+	default:
+		ginerrors.BadRequest(c, "Invalid status")
+		return
+	}
+	switch c.Query("kind") {
+	case "", models.DisputeOnFulfillment, models.DisputeOnSettlement:
+	default:
+		ginerrors.BadRequest(c, "Invalid kind")
+		return
+	}
+	campaignID, err := optionalUUID(c, "campaignId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	userID, err := optionalUUID(c, "userId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	disputes, total, err := h.disputeService.ListDisputes(c.Request.Context(), repository.DisputeFilter{
+		Status:     c.Query("status"),
+		Kind:       c.Query("kind"),
+		CampaignID: campaignID,
+		UserID:     userID,
+		Overdue:    c.Query("overdue") == "true",
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       disputes,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetDispute handles GET /admin/disputes/:id; tx-helper reads it before
+// building an on-chain refund
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	id, ok := disputeParam(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.disputeService.GetDispute(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dispute,
+	})
+}
+
+// Resolve handles POST /admin/disputes/:id/resolve with {"resolution",
+// "amount", "method", "note"}
+func (h *DisputeHandler) Resolve(c *gin.Context) {
+	id, ok := disputeParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Resolution string        `json:"resolution" binding:"required,oneof=refund reject"`
+		Amount     models.BigInt `json:"amount"`
+		Method     string        `json:"method" binding:"omitempty,oneof=payment onchain"`
+		Note       *string       `json:"note" binding:"omitempty,max=1000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	refund := req.Resolution == "refund"
+	if refund && (req.Amount.Int == nil || req.Method == "") {
+		ginerrors.BadRequest(c, "Refunds need an amount and a method")
+		return
+	}
+
+	dispute, err := h.disputeService.Resolve(c.Request.Context(), id, services.ResolveDisputeInput{
+		Refund: refund,
+		Amount: req.Amount.Int,
+		Method: req.Method,
+		Note:   req.Note,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dispute,
+	})
+}
+
+// RecordRefund handles POST /admin/disputes/:id/refund with {"txHash"},
+// once an on-chain refund is mined
+func (h *DisputeHandler) RecordRefund(c *gin.Context) {
+	id, ok := disputeParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		TxHash string `json:"txHash" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	dispute, err := h.disputeService.RecordRefund(c.Request.Context(), id, req.TxHash)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dispute,
+	})
+}
+
+// disputeParam parses :id, answering 400 when it is not a UUID
+func disputeParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid dispute ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+)
+
+// FavoriteHandler adds and removes watchlist campaigns. The gateway fills
+// in :userId from the caller's token; listing is served by query-server.
+type FavoriteHandler struct {
+	favoriteService *services.FavoriteService
+}
+
+func NewFavoriteHandler(favoriteService *services.FavoriteService) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteService: favoriteService,
+	}
+}
+
+// AddFavorite handles PUT /favorites/user/:userId/:campaignId
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+
+	favorite, err := h.favoriteService.AddFavorite(c.Request.Context(), userID, campaignID)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    favorite,
+	})
+}
+
+// RemoveFavorite handles DELETE /favorites/user/:userId/:campaignId
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+
+	if err := h.favoriteService.RemoveFavorite(c.Request.Context(), userID, campaignID); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Favorite removed",
+	})
+}
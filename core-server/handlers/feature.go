@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"r2s/pkg/audit"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/featureflags"
+	"r2s/pkg/logger/ginlog"
+)
+
+type FeatureHandler struct {
+	flags *featureflags.Flags
+	audit *audit.Store
+}
+
+func NewFeatureHandler(flags *featureflags.Flags, store *audit.Store) *FeatureHandler {
+	return &FeatureHandler{
+		flags: flags,
+		audit: store,
+	}
+}
+
+// ListFeatures handles GET /features?userId=, returning every known flag
+// evaluated for the user
+func (h *FeatureHandler) ListFeatures(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.flags.All(c.Request.Context(), c.Query("userId")),
+	})
+}
+
+// ListFlags handles GET /admin/features, returning every known flag and
+// every override
+func (h *FeatureHandler) ListFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.flags.List(c.Request.Context()),
+	})
+}
+
+// GetFlag handles GET /admin/features/:name
+func (h *FeatureHandler) GetFlag(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.flags.Get(c.Request.Context(), c.Param("name")),
+	})
+}
+
+// SetFlag handles PUT /admin/features/:name with a featureflags.Flag body
+func (h *FeatureHandler) SetFlag(c *gin.Context) {
+	var flag featureflags.Flag
+	if err := c.ShouldBindJSON(&flag); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	name := c.Param("name")
+	before := h.flags.Get(c.Request.Context(), name)
+	if err := h.flags.Set(c.Request.Context(), name, flag); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	h.record(c, audit.ActionFeatureFlagSet, name, before, flag)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    flag,
+	})
+}
+
+// DeleteFlag handles DELETE /admin/features/:name, restoring the
+// environment default
+func (h *FeatureHandler) DeleteFlag(c *gin.Context) {
+	name := c.Param("name")
+	before := h.flags.Get(c.Request.Context(), name)
+	if err := h.flags.Delete(c.Request.Context(), name); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	h.record(c, audit.ActionFeatureFlagReset, name, before, h.flags.Get(c.Request.Context(), name))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// record audits a flag change. The change is already in Redis, so a failure
+// is logged rather than answered.
+func (h *FeatureHandler) record(c *gin.Context, action, name string, before, after featureflags.Flag) {
+	err := h.audit.Record(c.Request.Context(), nil, audit.Change{
+		Action:       action,
+		ResourceType: audit.ResourceFeatureFlag,
+		ResourceID:   name,
+		Before:       before,
+		After:        after,
+	})
+	if err != nil {
+		ginlog.From(c).Error("failed to audit feature flag change", "flag", name, "error", err)
+	}
+}
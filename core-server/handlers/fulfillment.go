@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// FulfillmentHandler serves merchants marking campaign orders fulfilled
+// and participants confirming or disputing them. The gateway fills in
+// :userId from the caller's token.
+type FulfillmentHandler struct {
+	fulfillmentService *services.FulfillmentService
+}
+
+func NewFulfillmentHandler(fulfillmentService *services.FulfillmentService) *FulfillmentHandler {
+	return &FulfillmentHandler{
+		fulfillmentService: fulfillmentService,
+	}
+}
+
+// MarkFulfilled handles POST /campaigns/:id/fulfillment with
+// {"participationIds", "proofHash", "proofImageId", "note"}
+func (h *FulfillmentHandler) MarkFulfilled(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, id)
+
+	var req struct {
+		ParticipationIDs []uuid.UUID `json:"participationIds" binding:"max=1000"`
+		ProofHash        string      `json:"proofHash" binding:"required"`
+		ProofImageID     *uuid.UUID  `json:"proofImageId"`
+		Note             *string     `json:"note" binding:"omitempty,max=1000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	fulfillments, err := h.fulfillmentService.MarkFulfilled(c.Request.Context(), id, services.MarkFulfilledInput{
+		ParticipationIDs: req.ParticipationIDs,
+		ProofHash:        req.ProofHash,
+		ProofImageID:     req.ProofImageID,
+		Note:             req.Note,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fulfillments,
+	})
+}
+
+// GetSummary handles GET /campaigns/:id/fulfillment
+func (h *FulfillmentHandler) GetSummary(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+
+	summary, err := h.fulfillmentService.Summary(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// ListCampaignFulfillments handles GET /campaigns/:id/fulfillments?status=
+func (h *FulfillmentHandler) ListCampaignFulfillments(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", models.FulfillmentFulfilled, models.FulfillmentConfirmed, models.FulfillmentDisputed:
+	default:
+		ginerrors.BadRequest(c, "Invalid status")
+		return
+	}
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	fulfillments, total, err := h.fulfillmentService.ListCampaignFulfillments(c.Request.Context(), id, status, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       fulfillments,
+		"pagination": page.Result(total),
+	})
+}
+
+// ListUserFulfillments handles GET /fulfillments/user/:userId
+func (h *FulfillmentHandler) ListUserFulfillments(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	fulfillments, total, err := h.fulfillmentService.ListUserFulfillments(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       fulfillments,
+		"pagination": page.Result(total),
+	})
+}
+
+// Confirm handles POST /fulfillments/user/:userId/:participationId/confirm
+func (h *FulfillmentHandler) Confirm(c *gin.Context) {
+	userID, participationID, ok := fulfillmentParams(c)
+	if !ok {
+		return
+	}
+
+	fulfillment, err := h.fulfillmentService.Confirm(c.Request.Context(), userID, participationID)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fulfillment,
+	})
+}
+
+// Dispute handles POST /fulfillments/user/:userId/:participationId/dispute
+// with {"reason"}
+func (h *FulfillmentHandler) Dispute(c *gin.Context) {
+	userID, participationID, ok := fulfillmentParams(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required,max=1000"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		ginerrors.BadRequest(c, "A reason is required")
+		return
+	}
+
+	fulfillment, err := h.fulfillmentService.Dispute(c.Request.Context(), userID, participationID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    fulfillment,
+	})
+}
+
+// fulfillmentParams parses :userId and :participationId, answering 400
+// when either is not a UUID
+func fulfillmentParams(c *gin.Context) (userID, participationID uuid.UUID, ok bool) {
+	if userID, ok = userParam(c); !ok {
+		return
+	}
+	participationID, err := uuid.Parse(c.Param("participationId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid participation ID")
+		return userID, participationID, false
+	}
+	return userID, participationID, true
+}
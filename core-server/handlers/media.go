@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+)
+
+// MediaHandler serves image uploads for campaigns and merchant logos
+type MediaHandler struct {
+	mediaService *services.MediaService
+}
+
+func NewMediaHandler(mediaService *services.MediaService) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+	}
+}
+
+// CreateUpload handles POST /media/uploads, returning a pre-signed URL to
+// PUT the file to
+func (h *MediaHandler) CreateUpload(c *gin.Context) {
+	var req struct {
+		Purpose     models.MediaPurpose `json:"purpose" binding:"required,oneof=campaign_image merchant_logo fulfillment_proof"`
+		ContentType string              `json:"contentType" binding:"required"`
+		Size        int64               `json:"size" binding:"required,gt=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	ticket, err := h.mediaService.CreateUpload(c.Request.Context(), req.Purpose, req.ContentType, req.Size)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    ticket,
+	})
+}
+
+// CompleteUpload handles POST /media/uploads/:id/complete, once the file
+// has been uploaded
+func (h *MediaHandler) CompleteUpload(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid upload ID")
+		return
+	}
+
+	upload, err := h.mediaService.CompleteUpload(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    upload,
+	})
+}
+
+// GetUpload handles GET /media/uploads/:id
+func (h *MediaHandler) GetUpload(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid upload ID")
+		return
+	}
+
+	upload, err := h.mediaService.GetUpload(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    upload,
+	})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/repository"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/validate"
+)
+
+// MerchantHandler serves merchant registration and the merchant dashboard.
+// The gateway fills in :userId from the caller's token; review routes sit
+// in the admin group.
+type MerchantHandler struct {
+	merchantService *services.MerchantService
+}
+
+func NewMerchantHandler(merchantService *services.MerchantService) *MerchantHandler {
+	return &MerchantHandler{
+		merchantService: merchantService,
+	}
+}
+
+// Register handles POST /merchants/user/:userId
+func (h *MerchantHandler) Register(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		BusinessName       string  `json:"businessName" binding:"required"`
+		RegistrationNumber *string `json:"registrationNumber"`
+		ContactEmail       string  `json:"contactEmail" binding:"required,email"`
+		ContactPhone       *string `json:"contactPhone"`
+		Website            *string `json:"website"`
+		Country            string  `json:"country" binding:"required,len=2"`
+		PayoutWallet       string  `json:"payoutWallet" binding:"required"`
+		AcceptedFeeBps     int     `json:"acceptedFeeBps" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.First(
+		validate.Required("businessName", req.BusinessName),
+		validate.Address("payoutWallet", req.PayoutWallet),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	merchant, err := h.merchantService.Register(c.Request.Context(), userID, services.RegisterMerchantInput{
+		BusinessName:       strings.TrimSpace(req.BusinessName),
+		RegistrationNumber: req.RegistrationNumber,
+		ContactEmail:       req.ContactEmail,
+		ContactPhone:       req.ContactPhone,
+		Website:            req.Website,
+		Country:            strings.ToUpper(req.Country),
+		PayoutWallet:       req.PayoutWallet,
+		AcceptedFeeBps:     req.AcceptedFeeBps,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    merchant,
+	})
+}
+
+// GetRegistration handles GET /merchants/user/:userId
+func (h *MerchantHandler) GetRegistration(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	h.get(c, userID)
+}
+
+// ListMerchants handles GET /admin/merchants/registrations?q=&status=
+func (h *MerchantHandler) ListMerchants(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	merchants, total, err := h.merchantService.ListMerchants(c.Request.Context(), repository.MerchantFilter{
+		Query:  c.Query("q"),
+		Status: c.Query("status"),
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       merchants,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetMerchant handles GET /admin/merchants/:id
+func (h *MerchantHandler) GetMerchant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid merchant ID")
+		return
+	}
+	h.get(c, id)
+}
+
+// SetStatus handles POST /admin/merchants/:id/status with {"status",
+// "reason", "feeBps"}. Rejecting and suspending require a reason.
+func (h *MerchantHandler) SetStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid merchant ID")
+		return
+	}
+
+	var req struct {
+		Status models.MerchantStatus `json:"status" binding:"required"`
+		Reason string                `json:"reason"`
+		FeeBps *int                  `json:"feeBps"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	switch req.Status {
+	case models.MerchantApproved:
+	case models.MerchantRejected, models.MerchantSuspended:
+		if strings.TrimSpace(req.Reason) == "" {
+			ginerrors.BadRequest(c, "reason is required")
+			return
+		}
+	default:
+		ginerrors.BadRequest(c, "status must be one of approved, rejected, suspended")
+		return
+	}
+	if req.FeeBps != nil {
+		if err := validate.Bps("feeBps", *req.FeeBps); err != nil {
+			ginerrors.Respond(c, err)
+			return
+		}
+	}
+
+	merchant, err := h.merchantService.SetStatus(c.Request.Context(), id, req.Status, req.Reason, req.FeeBps)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    merchant,
+	})
+}
+
+// GetDashboard handles GET /merchants/:id/dashboard with an optional
+// RFC 3339 from/to range (from inclusive, to exclusive) for upcoming and
+// settled payouts
+func (h *MerchantHandler) GetDashboard(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid merchant ID")
+		return
+	}
+
+	var r services.DashboardRange
+	if r.From, err = parseTimeQuery(c, "from"); err != nil {
+		ginerrors.BadRequest(c, "Invalid from time")
+		return
+	}
+	if r.To, err = parseTimeQuery(c, "to"); err != nil {
+		ginerrors.BadRequest(c, "Invalid to time")
+		return
+	}
+	if !r.From.IsZero() && !r.To.IsZero() {
+		if err := validate.TimeWindow("from", r.From, "to", r.To); err != nil {
+			ginerrors.Respond(c, err)
+			return
+		}
+	}
+
+	dashboard, err := h.merchantService.Dashboard(c.Request.Context(), id, r)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    dashboard,
+	})
+}
+
+func (h *MerchantHandler) get(c *gin.Context, id uuid.UUID) {
+	merchant, err := h.merchantService.GetMerchant(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    merchant,
+	})
+}
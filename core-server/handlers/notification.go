@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/pagination"
+)
+
+// NotificationHandler manages push devices and preferences. The gateway
+// fills in :userId from the caller's token.
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// ListDevices handles GET /notifications/user/:userId/devices
+func (h *NotificationHandler) ListDevices(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	devices, err := h.notificationService.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    devices,
+	})
+}
+
+// RegisterDevice handles POST /notifications/user/:userId/devices
+func (h *NotificationHandler) RegisterDevice(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Platform string `json:"platform" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	device, err := h.notificationService.RegisterDevice(c.Request.Context(), userID, req.Token, req.Platform)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    device,
+	})
+}
+
+// UnregisterDevice handles DELETE /notifications/user/:userId/devices/:token
+func (h *NotificationHandler) UnregisterDevice(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.UnregisterDevice(c.Request.Context(), userID, c.Param("token")); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Device unregistered",
+	})
+}
+
+// GetPreferences handles GET /notifications/user/:userId/preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prefs,
+	})
+}
+
+// UpdatePreferences handles PUT /notifications/user/:userId/preferences;
+// omitted topics and channels keep their setting
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		CampaignMilestones *bool `json:"campaign_milestones"`
+		RebatePayouts      *bool `json:"rebate_payouts"`
+		Watchlist          *bool `json:"watchlist"`
+		Cancellations      *bool `json:"cancellations"`
+		Line               *bool `json:"line"`
+		Email              *bool `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	prefs, err := h.notificationService.UpdatePreferences(c.Request.Context(), userID, services.UpdatePreferencesInput{
+		CampaignMilestones: req.CampaignMilestones,
+		RebatePayouts:      req.RebatePayouts,
+		Watchlist:          req.Watchlist,
+		Cancellations:      req.Cancellations,
+		Line:               req.Line,
+		Email:              req.Email,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prefs,
+	})
+}
+
+// ListDeliveries handles GET /notifications/user/:userId/deliveries
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	deliveries, total, err := h.notificationService.ListDeliveries(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       deliveries,
+		"pagination": page.Result(total),
+	})
+}
+
+// userParam parses :userId, answering 400 when it is not a UUID
+func userParam(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid user ID")
+		return uuid.Nil, false
+	}
+	ginlog.With(c, logger.KeyUserID, userID)
+	return userID, true
+}
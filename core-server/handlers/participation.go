@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/validate"
+)
+
+type ParticipationHandler struct {
+	participationService *services.ParticipationService
+}
+
+func NewParticipationHandler(participationService *services.ParticipationService) *ParticipationHandler {
+	return &ParticipationHandler{
+		participationService: participationService,
+	}
+}
+
+// GetUserParticipations handles GET /participations/user/:userId
+func (h *ParticipationHandler) GetUserParticipations(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid user ID")
+		return
+	}
+	ginlog.With(c, logger.KeyUserID, userID)
+
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	participations, total, err := h.participationService.GetUserParticipations(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       participations,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetCampaignParticipations handles GET /participations/campaign/:campaignId
+func (h *ParticipationHandler) GetCampaignParticipations(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("campaignId"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, campaignID)
+
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	participations, total, err := h.participationService.GetCampaignParticipations(c.Request.Context(), campaignID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       participations,
+		"pagination": page.Result(total),
+	})
+}
+
+// CreateParticipation handles POST /participations
+func (h *ParticipationHandler) CreateParticipation(c *gin.Context) {
+	var req struct {
+		CampaignID    uuid.UUID     `json:"campaignId" binding:"required"`
+		UserID        uuid.UUID     `json:"userId" binding:"required"`
+		WalletAddress string        `json:"walletAddress" binding:"required"`
+		DepositAmount models.BigInt `json:"depositAmount" binding:"required"`
+		TxHash        *string       `json:"txHash"`
+		Metadata      models.JSONB  `json:"metadata"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	ginlog.With(c, logger.KeyCampaignID, req.CampaignID, logger.KeyUserID, req.UserID)
+
+	if err := validate.First(
+		validate.Address("walletAddress", req.WalletAddress),
+		validate.PositiveAmount("depositAmount", req.DepositAmount.Int),
+		validate.Metadata("metadata", req.Metadata),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	participation, created, err := h.participationService.CreateParticipation(c.Request.Context(), services.CreateParticipationInput{
+		CampaignID:    req.CampaignID,
+		UserID:        req.UserID,
+		WalletAddress: req.WalletAddress,
+		DepositAmount: req.DepositAmount.Int,
+		TxHash:        req.TxHash,
+		Metadata:      req.Metadata,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	// A retried join gets the participation it created the first time
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, gin.H{
+		"success": true,
+		"data":    participation,
+	})
+}
+
+// cancelRequest is the optional body of the cancel endpoints. Amount is the
+// part of the deposit to cancel, all of it when omitted; Version stands in
+// for If-Match.
+type cancelRequest struct {
+	Amount  *models.BigInt `json:"amount"`
+	Version *int64         `json:"version"`
+}
+
+// bindCancel reads the participation ID, the optional body and the expected
+// version of a cancel endpoint; it responds itself when ok is false
+func bindCancel(c *gin.Context) (id uuid.UUID, amount *big.Int, version int64, ok bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid participation ID")
+		return id, nil, 0, false
+	}
+
+	var req cancelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			ginerrors.BadRequest(c, "Invalid request")
+			return id, nil, 0, false
+		}
+	}
+	if req.Amount != nil {
+		if err := validate.PositiveAmount("amount", req.Amount.Int); err != nil {
+			ginerrors.Respond(c, err)
+			return id, nil, 0, false
+		}
+		amount = req.Amount.Int
+	}
+	version, err = expectedVersion(c, req.Version)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return id, nil, 0, false
+	}
+	return id, amount, version, true
+}
+
+// CancelParticipation handles PUT /participations/:id/cancel
+func (h *ParticipationHandler) CancelParticipation(c *gin.Context) {
+	id, amount, version, ok := bindCancel(c)
+	if !ok {
+		return
+	}
+
+	participation, err := h.participationService.CancelParticipation(c.Request.Context(), id, version, amount)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    participation,
+	})
+}
+
+// RequestCancel handles POST /participations/:id/cancel-request. It records
+// the amount the participant is about to cancel on chain and returns what
+// the cancel will cost.
+func (h *ParticipationHandler) RequestCancel(c *gin.Context) {
+	id, amount, version, ok := bindCancel(c)
+	if !ok {
+		return
+	}
+
+	participation, quote, err := h.participationService.RequestCancel(c.Request.Context(), id, version, amount)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"participation": participation,
+			"quote":         quote,
+		},
+	})
+}
+
+// ReconcileCancel handles POST /participations/cancel-events, where the
+// indexer reports requestCancel calls seen on chain. Redelivered events
+// answer 200 without applying anything again.
+func (h *ParticipationHandler) ReconcileCancel(c *gin.Context) {
+	var req struct {
+		CampaignAddress string        `json:"campaignAddress" binding:"required"`
+		WalletAddress   string        `json:"walletAddress" binding:"required"`
+		Amount          models.BigInt `json:"amount" binding:"required"`
+		TxHash          string        `json:"txHash" binding:"required"`
+		BlockTime       time.Time     `json:"blockTime"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.First(
+		validate.Address("campaignAddress", req.CampaignAddress),
+		validate.Address("walletAddress", req.WalletAddress),
+		validate.PositiveAmount("amount", req.Amount.Int),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	participation, applied, err := h.participationService.ReconcileCancel(c.Request.Context(), services.CancelEvent{
+		CampaignAddress: req.CampaignAddress,
+		WalletAddress:   req.WalletAddress,
+		Amount:          req.Amount.Int,
+		TxHash:          req.TxHash,
+		At:              req.BlockTime,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"participation": participation,
+			"applied":       applied,
+		},
+	})
+}
+
+// UpdateParticipationMetadata handles PATCH /participations/:id/metadata. The
+// body is merged into the stored object; keys set to null are removed.
+func (h *ParticipationHandler) UpdateParticipationMetadata(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid participation ID")
+		return
+	}
+
+	var patch models.JSONB
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+	if err := validate.Metadata("metadata", patch); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	metadata, err := h.participationService.UpdateParticipationMetadata(c.Request.Context(), id, patch)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    metadata,
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+	"r2s/pkg/validate"
+)
+
+type PaymentHandler struct {
+	paymentService *services.PaymentService
+}
+
+func NewPaymentHandler(paymentService *services.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// ProcessPayment handles POST /payments/process
+func (h *PaymentHandler) ProcessPayment(c *gin.Context) {
+	var req struct {
+		PaymentID       string        `json:"paymentId"`
+		CampaignID      *uuid.UUID    `json:"campaignId"`
+		UserID          *uuid.UUID    `json:"userId"`
+		ParticipationID *uuid.UUID    `json:"participationId"`
+		Amount          models.BigInt `json:"amount" binding:"required"`
+		Currency        string        `json:"currency" binding:"required,oneof=USDT KAIA KRW USD"`
+		Mode            string        `json:"mode" binding:"required,oneof=crypto stripe"`
+		TransactionHash *string       `json:"transactionHash"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.PositiveAmount("amount", req.Amount.Int); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	payment, err := h.paymentService.ProcessPayment(c.Request.Context(), services.ProcessPaymentInput{
+		PaymentID:       req.PaymentID,
+		CampaignID:      req.CampaignID,
+		UserID:          req.UserID,
+		ParticipationID: req.ParticipationID,
+		Amount:          req.Amount.Int,
+		Currency:        models.Currency(req.Currency),
+		Mode:            models.PaymentMode(req.Mode),
+		TransactionHash: req.TransactionHash,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    payment,
+	})
+}
+
+// GetPaymentStatus handles GET /payments/:id/status
+func (h *PaymentHandler) GetPaymentStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid payment ID")
+		return
+	}
+
+	payment, err := h.paymentService.GetPaymentStatus(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"id":              payment.ID,
+			"paymentId":       payment.PaymentID,
+			"status":          payment.Status,
+			"transactionHash": payment.TransactionHash,
+			"completedAt":     payment.CompletedAt,
+			"failedAt":        payment.FailedAt,
+			"refundedAt":      payment.RefundedAt,
+		},
+	})
+}
+
+// HandleWebhook handles POST /payments/webhook
+func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(c.Request.Context(), body, c.GetHeader("X-Signature")); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+)
+
+// PolicyHandler serves the campaign policies of the merchant tiers to
+// admins
+type PolicyHandler struct {
+	policyService *services.PolicyService
+}
+
+func NewPolicyHandler(policyService *services.PolicyService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+	}
+}
+
+// policyRequest is a tier's bounds; omitted ones are not enforced
+type policyRequest struct {
+	MaxRMaxBps   *int `json:"maxRMaxBps"`
+	MinLockHours *int `json:"minLockHours"`
+	MinFeeBps    *int `json:"minFeeBps"`
+	MaxFeeBps    *int `json:"maxFeeBps"`
+}
+
+// tierParam parses :tier, responding 400 when it is not a number
+func tierParam(c *gin.Context) (int, bool) {
+	tier, err := strconv.Atoi(c.Param("tier"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid tier")
+		return 0, false
+	}
+	return tier, true
+}
+
+// ListPolicies handles GET /admin/campaign-policies
+func (h *PolicyHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.policyService.ListPolicies(c.Request.Context())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policies,
+	})
+}
+
+// GetPolicy handles GET /admin/campaign-policies/:tier
+func (h *PolicyHandler) GetPolicy(c *gin.Context) {
+	tier, ok := tierParam(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.policyService.GetPolicy(c.Request.Context(), tier)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// SetPolicy handles PUT /admin/campaign-policies/:tier
+func (h *PolicyHandler) SetPolicy(c *gin.Context) {
+	tier, ok := tierParam(c)
+	if !ok {
+		return
+	}
+	var req policyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	policy, err := h.policyService.SetPolicy(c.Request.Context(), &models.CampaignPolicy{
+		MerchantTier: tier,
+		MaxRMaxBps:   req.MaxRMaxBps,
+		MinLockHours: req.MinLockHours,
+		MinFeeBps:    req.MinFeeBps,
+		MaxFeeBps:    req.MaxFeeBps,
+	})
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// DeletePolicy handles DELETE /admin/campaign-policies/:tier
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	tier, ok := tierParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.policyService.DeletePolicy(c.Request.Context(), tier); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/pagination"
+	"r2s/pkg/referral"
+)
+
+// ReferralHandler serves a user's referral code, stats and rewards, and
+// applies a code after sign-up. The gateway fills in :userId from the
+// caller's token.
+type ReferralHandler struct {
+	referrals *referral.Store
+}
+
+func NewReferralHandler(referrals *referral.Store) *ReferralHandler {
+	return &ReferralHandler{
+		referrals: referrals,
+	}
+}
+
+// GetStats handles GET /referrals/user/:userId
+func (h *ReferralHandler) GetStats(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.referrals.Stats(c.Request.Context(), userID)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// ApplyCode handles POST /referrals/user/:userId
+func (h *ReferralHandler) ApplyCode(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	ref, err := h.referrals.Apply(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    ref,
+	})
+}
+
+// ListRewards handles GET /referrals/user/:userId/rewards
+func (h *ReferralHandler) ListRewards(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	rewards, total, err := h.referrals.Rewards(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       rewards,
+		"pagination": page.Result(total),
+	})
+}
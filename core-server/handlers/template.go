@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// relaunchRequest is the new lock window of a clone or template launch
+type relaunchRequest struct {
+	Title          *string    `json:"title"`
+	StartTime      time.Time  `json:"startTime" binding:"required"`
+	EndTime        time.Time  `json:"endTime" binding:"required"`
+	CancelDeadline *time.Time `json:"cancelDeadline"`
+}
+
+func (r relaunchRequest) input() services.RelaunchInput {
+	return services.RelaunchInput{
+		Title:          r.Title,
+		StartTime:      r.StartTime,
+		EndTime:        r.EndTime,
+		CancelDeadline: r.CancelDeadline,
+	}
+}
+
+// templateRequest is a template's terms. Create takes either campaignId,
+// saving that campaign's terms, or the terms themselves.
+type templateRequest struct {
+	Name                 string        `json:"name" binding:"required"`
+	CampaignID           *uuid.UUID    `json:"campaignId"`
+	MerchantID           *uuid.UUID    `json:"merchantId"`
+	Title                string        `json:"title"`
+	Description          *string       `json:"description"`
+	ImageID              *uuid.UUID    `json:"imageId"`
+	Category             *string       `json:"category"`
+	Tags                 []string      `json:"tags"`
+	MerchantWallet       string        `json:"merchantWallet"`
+	BasePrice            models.BigInt `json:"basePrice"`
+	MinQty               int           `json:"minQty"`
+	DiscountRate         int           `json:"discountRate"`
+	SaveFloorBps         int           `json:"saveFloorBps"`
+	RMaxBps              int           `json:"rMaxBps"`
+	Metadata             models.JSONB  `json:"metadata"`
+	CancelLeadSeconds    *int64        `json:"cancelLeadSeconds"`
+	LateCancelPenaltyBps *int          `json:"lateCancelPenaltyBps"`
+}
+
+func (r templateRequest) input() services.TemplateInput {
+	return services.TemplateInput{
+		Name:                 r.Name,
+		MerchantID:           r.MerchantID,
+		Title:                r.Title,
+		Description:          r.Description,
+		MerchantWallet:       r.MerchantWallet,
+		BasePrice:            r.BasePrice.Int,
+		MinQty:               r.MinQty,
+		DiscountRate:         r.DiscountRate,
+		SaveFloorBps:         r.SaveFloorBps,
+		RMaxBps:              r.RMaxBps,
+		Metadata:             r.Metadata,
+		CancelLeadSeconds:    r.CancelLeadSeconds,
+		LateCancelPenaltyBps: r.LateCancelPenaltyBps,
+		ImageID:              r.ImageID,
+		Category:             r.Category,
+		Tags:                 r.Tags,
+	}
+}
+
+// templateParam parses :id, responding 400 when it is not a UUID
+func templateParam(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid template ID")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ListTemplates handles GET /campaign-templates?merchantId=
+func (h *CampaignHandler) ListTemplates(c *gin.Context) {
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	merchantID, err := optionalUUID(c, "merchantId")
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	templates, total, err := h.campaignService.ListTemplates(c.Request.Context(), merchantID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       templates,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetTemplate handles GET /campaign-templates/:id
+func (h *CampaignHandler) GetTemplate(c *gin.Context) {
+	id, ok := templateParam(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.campaignService.GetTemplate(c.Request.Context(), id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// CreateTemplate handles POST /campaign-templates
+func (h *CampaignHandler) CreateTemplate(c *gin.Context) {
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	var template *models.CampaignTemplate
+	var err error
+	if req.CampaignID != nil {
+		template, err = h.campaignService.SaveTemplate(c.Request.Context(), *req.CampaignID, req.Name)
+	} else {
+		template, err = h.campaignService.CreateTemplate(c.Request.Context(), req.input())
+	}
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// UpdateTemplate handles PUT /campaign-templates/:id
+func (h *CampaignHandler) UpdateTemplate(c *gin.Context) {
+	id, ok := templateParam(c)
+	if !ok {
+		return
+	}
+	var req templateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	template, err := h.campaignService.UpdateTemplate(c.Request.Context(), id, req.input())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    template,
+	})
+}
+
+// DeleteTemplate handles DELETE /campaign-templates/:id
+func (h *CampaignHandler) DeleteTemplate(c *gin.Context) {
+	id, ok := templateParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.campaignService.DeleteTemplate(c.Request.Context(), id); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// LaunchTemplate handles POST /campaign-templates/:id/launch
+func (h *CampaignHandler) LaunchTemplate(c *gin.Context) {
+	id, ok := templateParam(c)
+	if !ok {
+		return
+	}
+	var req relaunchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	campaign, err := h.campaignService.LaunchTemplate(c.Request.Context(), id, req.input())
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    campaign,
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	apperrors "r2s/pkg/errors"
+)
+
+// Row versions (models.Campaign.Version, models.Participation.Version) are
+// returned in the version field of reads and come back in If-Match, as a
+// decimal that may be quoted like an ETag: If-Match: "7". The gateway's
+// response cache sets its own ETags, so versions are not sent as ETags.
+
+var errVersionRequired = apperrors.Catalog(apperrors.ReasonVersionRequired)
+
+// expectedVersion returns the version the client read: If-Match, or else
+// the version field of the body when it has one
+func expectedVersion(c *gin.Context, body *int64) (int64, error) {
+	if match := c.GetHeader("If-Match"); match != "" {
+		v, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(match, "W/"), `"`), 10, 64)
+		if err != nil || v <= 0 {
+			return 0, apperrors.InvalidArgument("If-Match must be the version of the resource, e.g. \"7\"")
+		}
+		return v, nil
+	}
+	if body != nil {
+		return *body, nil
+	}
+	return 0, errVersionRequired
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/core-server/services"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/pagination"
+)
+
+// VoucherHandler serves participants their redemption vouchers and
+// merchants validating and redeeming them. The gateway fills in :userId
+// from the caller's token.
+type VoucherHandler struct {
+	voucherService *services.VoucherService
+}
+
+func NewVoucherHandler(voucherService *services.VoucherService) *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: voucherService,
+	}
+}
+
+// ListUserVouchers handles GET /vouchers/user/:userId
+func (h *VoucherHandler) ListUserVouchers(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	page, err := pagination.Parse(c.Query)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	vouchers, total, err := h.voucherService.ListUserVouchers(c.Request.Context(), userID, page)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       vouchers,
+		"pagination": page.Result(total),
+	})
+}
+
+// GetUserVoucher handles GET /vouchers/user/:userId/:id
+func (h *VoucherHandler) GetUserVoucher(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid voucher ID")
+		return
+	}
+
+	voucher, err := h.voucherService.GetUserVoucher(c.Request.Context(), userID, id)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    voucher,
+	})
+}
+
+// voucherCodeRequest is a code as typed at the POS, or a scanned QR payload
+type voucherCodeRequest struct {
+	Code string `json:"code" binding:"required,max=64"`
+}
+
+// Validate handles POST /vouchers/validate with {"code"}
+func (h *VoucherHandler) Validate(c *gin.Context) {
+	var req voucherCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	voucher, err := h.voucherService.Validate(c.Request.Context(), req.Code)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    voucher,
+	})
+}
+
+// Redeem handles POST /vouchers/redeem with {"code"}
+func (h *VoucherHandler) Redeem(c *gin.Context) {
+	var req voucherCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	voucher, err := h.voucherService.Redeem(c.Request.Context(), req.Code)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    voucher,
+	})
+}
@@ -1,71 +1,160 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"r2s/core-server/handlers"
 	"r2s/core-server/services"
+	"r2s/pkg/audit"
+	"r2s/pkg/audit/ginaudit"
+	"r2s/pkg/clock"
+	"r2s/pkg/config"
+	"r2s/pkg/diag"
 	"r2s/pkg/database"
+	"r2s/pkg/errreport"
+	"r2s/pkg/errreport/ginreport"
+	"r2s/pkg/featureflags"
+	"r2s/pkg/health"
+	"r2s/pkg/jwks"
+	"r2s/pkg/jwks/ginjwks"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/metadata"
+	"r2s/pkg/metrics/ginmetrics"
+	"r2s/pkg/models"
+	"r2s/pkg/objectstore"
+	"r2s/pkg/push"
+	"r2s/pkg/rbac/ginrbac"
+	"r2s/pkg/referral"
+	"r2s/pkg/server"
+	"r2s/pkg/svcauth"
+	"r2s/pkg/svcauth/ginsvcauth"
+	"r2s/pkg/tracing"
+	"r2s/pkg/tracing/gintrace"
 )
 
 func main() {
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	envErr := godotenv.Load()
+
+	// Load and validate configuration (env, CONFIG_FILE, defaults)
+	var cfg Config
+	config.MustLoad(&cfg)
+
+	// Structured logging (LOG_LEVEL, LOG_FORMAT)
+	logger.Init("core-server", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
+	}
+
+	// Error reporting (Sentry when SENTRY_DSN is set)
+	if err := errreport.Init("core-server", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
 	}
+	defer errreport.Flush()
 
-	// Database configuration
-	dbConfig := database.Config{
-		Host:         os.Getenv("DB_HOST"),
-		Port:         5432,
-		User:         os.Getenv("DB_USER"),
-		Password:     os.Getenv("DB_PASSWORD"),
-		Database:     os.Getenv("DB_NAME"),
-		MaxOpenConns: 25,
-		MaxIdleConns: 10,
-		MaxLifetime:  5 * time.Minute,
+	// Tracing (spans are exported when OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.Init(context.Background(), "core-server", cfg.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", "error", err)
 	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize database
-	db, err := database.NewDB(dbConfig)
+	db, err := database.NewDB(cfg.Database)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logger.Fatal("Failed to connect to database", "error", err)
 	}
 	defer db.Close()
 
-	// Redis configuration
-	redisConfig := database.RedisConfig{
-		Host:     os.Getenv("REDIS_HOST"),
-		Port:     6379,
-		Password: os.Getenv("REDIS_PASSWORD"),
-		DB:       0,
-		PoolSize: 10,
-	}
+	// Redis configuration (single node, Sentinel or Cluster via REDIS_MODE)
+	redisConfig := database.RedisConfigFromEnv()
 
 	// Initialize Redis
 	redis, err := database.NewRedisClient(redisConfig)
 	if err != nil {
-		log.Fatal("Failed to connect to Redis:", err)
+		logger.Fatal("Failed to connect to Redis", "error", err)
 	}
 	defer redis.Close()
 
+	// Push notifications (FCM when FCM_PROJECT_ID is set, otherwise logged)
+	pushSender, err := push.New(context.Background(), cfg.Push)
+	if err != nil {
+		logger.Fatal("Failed to initialize push notifications", "error", err)
+	}
+
+	// Object storage, for the store metadata driver and media uploads
+	var store, metadataStore objectstore.Store
+	if cfg.Metadata.Driver == metadata.DriverStore || cfg.Media.Enabled() {
+		store, err = objectstore.New(cfg.ObjectStore)
+		if err != nil {
+			logger.Fatal("Failed to initialize object store", "error", err)
+		}
+	}
+
+	// Campaign metadata publishing (METADATA_DRIVER ipfs or store, otherwise off)
+	if cfg.Metadata.Driver == metadata.DriverStore {
+		metadataStore = store
+	}
+	metadataPublisher, err := metadata.New(cfg.Metadata, metadataStore)
+	if err != nil {
+		logger.Fatal("Failed to initialize metadata publishing", "error", err)
+	}
+
 	// Initialize services
-	campaignService := services.NewCampaignService(db, redis)
-	participationService := services.NewParticipationService(db, redis)
-	paymentService := services.NewPaymentService(db, redis)
+	clk := clock.New()
+	flags := featureflags.New(redis.UniversalClient, featureflags.WithClock(clk))
+	notificationService := services.NewNotificationService(db, pushSender, clk)
+	metadataService := services.NewMetadataService(db, metadataPublisher, clk)
+	voucherService := services.NewVoucherService(db, cfg.Vouchers, clk)
+	campaignService := services.NewCampaignService(db, redis, clk, notificationService, metadataService, voucherService)
+	referralStore := referral.NewStore(db, cfg.Referral, clk)
+	participationService := services.NewParticipationService(db, redis, clk, notificationService, referralStore)
+	disputeService := services.NewDisputeService(db, cfg.Disputes, clk, notificationService)
+	paymentService := services.NewPaymentService(db, redis, cfg.PaymentWebhookSecret, flags, disputeService)
+	adminService := services.NewAdminService(db, clk)
+	merchantService := services.NewMerchantService(db, clk)
+	mediaService := services.NewMediaService(db, store, cfg.Media, clk)
+	categoryService := services.NewCategoryService(db, clk)
+	policyService := services.NewPolicyService(db, clk)
+	favoriteService := services.NewFavoriteService(db, clk)
+	fulfillmentService := services.NewFulfillmentService(db, cfg.Fulfillment, clk, notificationService, disputeService)
 
 	// Initialize handlers
-	campaignHandler := handlers.NewCampaignHandler(campaignService)
+	campaignHandler := handlers.NewCampaignHandler(campaignService, metadataService)
 	participationHandler := handlers.NewParticipationHandler(participationService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
+	auditStore := audit.NewStore(db, clk)
+	featureHandler := handlers.NewFeatureHandler(flags, auditStore)
+	auditHandler := handlers.NewAuditHandler(auditStore)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	merchantHandler := handlers.NewMerchantHandler(merchantService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	favoriteHandler := handlers.NewFavoriteHandler(favoriteService)
+	referralHandler := handlers.NewReferralHandler(referralStore)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	fulfillmentHandler := handlers.NewFulfillmentHandler(fulfillmentService)
+	disputeHandler := handlers.NewDisputeHandler(disputeService)
+	voucherHandler := handlers.NewVoucherHandler(voucherService)
+
+	// Access tokens and the internal tokens of calling services are verified
+	// locally against auth-server's published keys
+	keys := jwks.NewRemote(cfg.JWKS.URL, clk)
+	tokenVerifier := jwks.NewVerifier(keys, clk)
+	callerVerifier := svcauth.NewVerifier(keys, "core-server", clk)
+	if !cfg.Internal.Required {
+		slog.Warn("INTERNAL_AUTH_REQUIRED is off, serving callers without an internal token")
+	}
 
 	// Setup router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gintrace.Middleware(), ginmetrics.Middleware(), ginlog.Middleware(), ginreport.Middleware(), ginsvcauth.Middleware(callerVerifier, cfg.Internal.Required), ginjwks.Middleware(tokenVerifier), ginaudit.Middleware(), ginrbac.Middleware())
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -75,14 +164,125 @@ func main() {
 		})
 	})
 
+	// Liveness (process up) and readiness (Postgres and Redis reachable)
+	checker := health.NewChecker("core-server")
+	checker.Add("postgres", health.Database(db))
+	checker.Add("redis", health.Redis(redis.UniversalClient))
+	router.GET("/live", gin.WrapH(checker.LiveHandler()))
+	router.GET("/ready", gin.WrapH(checker.ReadyHandler()))
+
+	// Runtime log level (GET/PUT {"level":"debug"})
+	ginlog.RegisterLevelEndpoint(router, "/admin/log-level")
+
+	// Admin API; the gateway additionally requires MFA
+	adminGroup := router.Group("/admin", ginrbac.Require(models.RoleAdmin))
+	{
+		// Feature flag overrides (stored in Redis, shared by every instance),
+		// including the maintenance and freeze flags the gateway enforces
+		adminGroup.GET("/features", featureHandler.ListFlags)
+		adminGroup.GET("/features/:name", featureHandler.GetFlag)
+		adminGroup.PUT("/features/:name", featureHandler.SetFlag)
+		adminGroup.DELETE("/features/:name", featureHandler.DeleteFlag)
+
+		// Audit log of admin and merchant changes, for compliance review
+		adminGroup.GET("/audit-log", auditHandler.ListEntries)
+
+		// Dashboard
+		adminGroup.GET("/overview", adminHandler.Overview)
+		adminGroup.GET("/users", adminHandler.ListUsers)
+		adminGroup.POST("/users/suspend", adminHandler.SuspendUsers)
+		adminGroup.POST("/users/reinstate", adminHandler.ReinstateUsers)
+		adminGroup.POST("/users/role", adminHandler.SetUserRole)
+		adminGroup.GET("/merchants", adminHandler.ListMerchants)
+		adminGroup.GET("/merchants/registrations", merchantHandler.ListMerchants)
+		adminGroup.GET("/merchants/:id", merchantHandler.GetMerchant)
+		adminGroup.POST("/merchants/:id/status", merchantHandler.SetStatus)
+		adminGroup.GET("/campaigns", adminHandler.ListCampaigns)
+		adminGroup.POST("/campaigns/pause", adminHandler.PauseCampaigns)
+		adminGroup.POST("/campaigns/resume", adminHandler.ResumeCampaigns)
+		adminGroup.GET("/payments", adminHandler.ListPayments)
+
+		// Campaign category taxonomy
+		adminGroup.POST("/categories", categoryHandler.CreateCategory)
+		adminGroup.PUT("/categories/:slug", categoryHandler.UpdateCategory)
+		adminGroup.DELETE("/categories/:slug", categoryHandler.DeleteCategory)
+
+		// Campaign policies of the merchant tiers
+		adminGroup.GET("/campaign-policies", policyHandler.ListPolicies)
+		adminGroup.GET("/campaign-policies/:tier", policyHandler.GetPolicy)
+		adminGroup.PUT("/campaign-policies/:tier", policyHandler.SetPolicy)
+		adminGroup.DELETE("/campaign-policies/:tier", policyHandler.DeletePolicy)
+
+		// Dispute resolution; tx-helper reads a dispute before building its
+		// on-chain refund
+		adminGroup.GET("/disputes", disputeHandler.ListDisputes)
+		adminGroup.GET("/disputes/:id", disputeHandler.GetDispute)
+		adminGroup.POST("/disputes/:id/resolve", disputeHandler.Resolve)
+		adminGroup.POST("/disputes/:id/refund", disputeHandler.RecordRefund)
+	}
+
+	// Prometheus metrics (HTTP, DB, Redis, domain counters, Go runtime)
+	ginmetrics.Register(router)
+
+	// Feature flags evaluated for a user
+	router.GET("/features", featureHandler.ListFeatures)
+
+	// Campaign categories, in display order
+	router.GET("/categories", categoryHandler.ListCategories)
+
 	// Campaign routes
 	campaignGroup := router.Group("/campaigns")
 	{
 		campaignGroup.GET("", campaignHandler.ListCampaigns)
 		campaignGroup.GET("/:id", campaignHandler.GetCampaign)
-		campaignGroup.POST("", campaignHandler.CreateCampaign)
-		campaignGroup.PUT("/:id", campaignHandler.UpdateCampaign)
-		campaignGroup.POST("/:id/settle", campaignHandler.SettleCampaign)
+		// Merchants may only change their own campaigns
+		campaignGroup.POST("", ginrbac.Require(models.RoleMerchant), campaignHandler.CreateCampaign)
+		campaignGroup.POST("/:id/clone", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.CloneCampaign)
+		campaignGroup.PUT("/:id", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.UpdateCampaign)
+		campaignGroup.GET("/:id/history", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.GetCampaignHistory)
+		campaignGroup.PATCH("/:id/metadata", ginrbac.Require(models.RoleMerchant), campaignHandler.UpdateCampaignMetadata)
+		campaignGroup.POST("/:id/metadata/publish", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.PublishCampaignMetadata)
+		campaignGroup.POST("/:id/settle", ginrbac.Require(models.RoleOps), campaignHandler.SettleCampaign)
+		campaignGroup.POST("/:id/submit", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.SubmitCampaign)
+		campaignGroup.POST("/:id/review", ginrbac.Require(models.RoleOps), campaignHandler.ReviewCampaign)
+		campaignGroup.GET("/:id/reviews", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.GetCampaignReviews)
+		campaignGroup.POST("/:id/deployment", ginrbac.Require(models.RoleMerchant, models.RoleOps), campaignHandler.RecordDeployment)
+		// Fulfillment of the orders; tx-helper reads the summary before
+		// building the settlement transaction
+		campaignGroup.GET("/:id/fulfillment", ginrbac.Require(models.RoleMerchant, models.RoleOps), fulfillmentHandler.GetSummary)
+		campaignGroup.POST("/:id/fulfillment", ginrbac.Require(models.RoleMerchant, models.RoleOps), fulfillmentHandler.MarkFulfilled)
+		campaignGroup.GET("/:id/fulfillments", ginrbac.Require(models.RoleMerchant, models.RoleOps), fulfillmentHandler.ListCampaignFulfillments)
+	}
+
+	// Campaign templates for recurring offers; merchants see only their
+	// own, ops and admins any
+	templateGroup := router.Group("/campaign-templates", ginrbac.Require(models.RoleMerchant, models.RoleOps))
+	{
+		templateGroup.GET("", campaignHandler.ListTemplates)
+		templateGroup.POST("", campaignHandler.CreateTemplate)
+		templateGroup.GET("/:id", campaignHandler.GetTemplate)
+		templateGroup.PUT("/:id", campaignHandler.UpdateTemplate)
+		templateGroup.DELETE("/:id", campaignHandler.DeleteTemplate)
+		templateGroup.POST("/:id/launch", campaignHandler.LaunchTemplate)
+	}
+
+	merchantGroup := router.Group("/merchants/user/:userId")
+	{
+		merchantGroup.GET("", merchantHandler.GetRegistration)
+		merchantGroup.POST("", merchantHandler.Register)
+	}
+
+	// Merchant dashboard; merchants see only their own, ops and admins any
+	router.GET("/merchants/:id/dashboard", ginrbac.Require(models.RoleMerchant, models.RoleOps), merchantHandler.GetDashboard)
+
+	// Image uploads; campaign images and fulfillment proofs need the
+	// merchant or ops role, checked by MediaService since logos are uploaded
+	// while registering
+	mediaGroup := router.Group("/media/uploads")
+	{
+		mediaGroup.POST("", mediaHandler.CreateUpload)
+		mediaGroup.GET("/:id", mediaHandler.GetUpload)
+		mediaGroup.POST("/:id/complete", mediaHandler.CompleteUpload)
 	}
 
 	// Participation routes
@@ -92,6 +292,62 @@ func main() {
 		participationGroup.GET("/campaign/:campaignId", participationHandler.GetCampaignParticipations)
 		participationGroup.POST("", participationHandler.CreateParticipation)
 		participationGroup.PUT("/:id/cancel", participationHandler.CancelParticipation)
+		participationGroup.POST("/:id/cancel-request", participationHandler.RequestCancel)
+		// Fed by the indexer with requestCancel calls seen on chain
+		participationGroup.POST("/cancel-events", ginrbac.Require(models.RoleAdmin), participationHandler.ReconcileCancel)
+		participationGroup.PATCH("/:id/metadata", participationHandler.UpdateParticipationMetadata)
+	}
+
+	// Push devices, notification preferences and the LINE and email delivery log
+	notificationGroup := router.Group("/notifications/user/:userId")
+	{
+		notificationGroup.GET("/devices", notificationHandler.ListDevices)
+		notificationGroup.POST("/devices", notificationHandler.RegisterDevice)
+		notificationGroup.DELETE("/devices/:token", notificationHandler.UnregisterDevice)
+		notificationGroup.GET("/preferences", notificationHandler.GetPreferences)
+		notificationGroup.PUT("/preferences", notificationHandler.UpdatePreferences)
+		notificationGroup.GET("/deliveries", notificationHandler.ListDeliveries)
+	}
+
+	// Participants confirming or disputing the fulfillment of their orders
+	fulfillmentGroup := router.Group("/fulfillments/user/:userId")
+	{
+		fulfillmentGroup.GET("", fulfillmentHandler.ListUserFulfillments)
+		fulfillmentGroup.POST("/:participationId/confirm", fulfillmentHandler.Confirm)
+		fulfillmentGroup.POST("/:participationId/dispute", fulfillmentHandler.Dispute)
+	}
+
+	// Participants' disputes of fulfillments and settlements
+	disputeGroup := router.Group("/disputes/user/:userId")
+	{
+		disputeGroup.GET("", disputeHandler.ListUserDisputes)
+		disputeGroup.POST("", disputeHandler.Open)
+		disputeGroup.GET("/:id", disputeHandler.GetUserDispute)
+	}
+
+	// Redemption vouchers of settled participations; merchants validate and
+	// redeem the codes of their own campaigns at the point of sale
+	voucherGroup := router.Group("/vouchers")
+	{
+		voucherGroup.GET("/user/:userId", voucherHandler.ListUserVouchers)
+		voucherGroup.GET("/user/:userId/:id", voucherHandler.GetUserVoucher)
+		voucherGroup.POST("/validate", ginrbac.Require(models.RoleMerchant, models.RoleOps), voucherHandler.Validate)
+		voucherGroup.POST("/redeem", ginrbac.Require(models.RoleMerchant, models.RoleOps), voucherHandler.Redeem)
+	}
+
+	// Watchlist changes; the watchlist itself is read from query-server
+	favoriteGroup := router.Group("/favorites/user/:userId")
+	{
+		favoriteGroup.PUT("/:campaignId", favoriteHandler.AddFavorite)
+		favoriteGroup.DELETE("/:campaignId", favoriteHandler.RemoveFavorite)
+	}
+
+	// Referral code, stats and rewards; codes can also be applied at sign-up
+	referralGroup := router.Group("/referrals/user/:userId")
+	{
+		referralGroup.GET("", referralHandler.GetStats)
+		referralGroup.POST("", referralHandler.ApplyCode)
+		referralGroup.GET("/rewards", referralHandler.ListRewards)
 	}
 
 	// Payment routes
@@ -102,14 +358,18 @@ func main() {
 		paymentGroup.POST("/webhook", paymentHandler.HandleWebhook)
 	}
 
-	// Start server
-	port := os.Getenv("CORE_SERVER_PORT")
-	if port == "" {
-		port = "3003"
+	// Diagnostics (pprof, goroutine dumps, GC stats) on an internal listener
+	if err := diag.Start(cfg.DebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
 	}
 
-	log.Printf("Core server starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Start server; SIGTERM drains in-flight requests before the deferred
+	// connections are closed
+	ctx, stop := server.Context()
+	defer stop()
+	slog.Info("Core server starting", "port", cfg.Port)
+	if err := server.ServeHTTP(ctx, ":"+cfg.Port, router, cfg.Server); err != nil {
+		logger.Fatal("Server failed", "error", err)
 	}
+	slog.Info("Core server stopped")
 }
\ No newline at end of file
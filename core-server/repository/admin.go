@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const userColumns = `
+	id, wallet_address, line_user_id, line_display_name, line_picture_url,
+	email, kyc_tier, status, role, mfa_enabled, created_at, updated_at,
+	last_login_at, metadata`
+
+// UserFilter selects users for the admin API; Query matches the wallet
+// address, email or LINE display name
+type UserFilter struct {
+	Query  string
+	Status string
+	Role   string
+	Limit  int
+	Offset int
+}
+
+// AdminCampaignFilter extends CampaignFilter with search and merchant filters
+type AdminCampaignFilter struct {
+	Query      string
+	Status     string
+	MerchantID *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// PaymentFilter selects payments for the admin API
+type PaymentFilter struct {
+	Status     string
+	Mode       string
+	CampaignID *uuid.UUID
+	UserID     *uuid.UUID
+	Limit      int
+	Offset     int
+}
+
+// MerchantSummary aggregates a merchant's campaigns
+type MerchantSummary struct {
+	MerchantID      *uuid.UUID    `json:"merchantId" db:"merchant_id"`
+	MerchantWallet  string        `json:"merchantWallet" db:"merchant_wallet"`
+	Campaigns       int64         `json:"campaigns" db:"campaigns"`
+	ActiveCampaigns int64         `json:"activeCampaigns" db:"active_campaigns"`
+	TotalRaised     models.BigInt `json:"totalRaised" db:"total_raised"`
+	LastCampaignAt  time.Time     `json:"lastCampaignAt" db:"last_campaign_at"`
+}
+
+// StatusCount is one row of a GROUP BY status count
+type StatusCount struct {
+	Status string `db:"status"`
+	Count  int64  `db:"count"`
+}
+
+// AdminRepository backs the admin API: cross-entity search and the user
+// status changes core-server otherwise never makes
+type AdminRepository struct {
+	db *database.DB
+}
+
+func NewAdminRepository(db *database.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// count returns the total rows matching q, ignoring paging
+func (r *AdminRepository) count(ctx context.Context, q *database.SelectBuilder) (int64, error) {
+	query, args := q.CountSQL()
+	var total int64
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// ListUsers returns a page of users, newest first, and the matching total
+func (r *AdminRepository) ListUsers(ctx context.Context, f UserFilter) ([]*models.User, int64, error) {
+	q := database.NewSelect(userColumns).
+		From("users").
+		WhereIf(f.Query != "", "(wallet_address = ? OR email ILIKE ? OR line_display_name ILIKE ?)",
+			strings.ToLower(f.Query), database.LikePattern(f.Query), database.LikePattern(f.Query)).
+		WhereIf(f.Status != "", "status = ?", f.Status).
+		WhereIf(f.Role != "", "role = ?", f.Role)
+
+	total, err := r.count(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := q.OrderBy("created_at DESC", "id").Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	users := []*models.User{}
+	if err := r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		return nil, 0, err
+	}
+	for _, u := range users {
+		u.WalletAddress = address.Display(u.WalletAddress)
+	}
+	return users, total, nil
+}
+
+// ListCampaigns returns a page of campaigns, newest first, and the matching
+// total
+func (r *AdminRepository) ListCampaigns(ctx context.Context, f AdminCampaignFilter) ([]*models.Campaign, int64, error) {
+	q := database.NewSelect(campaignColumns).
+		From("campaigns").
+		WhereIf(f.Query != "", "(title ILIKE ? OR chain_address = ? OR merchant_wallet = ?)",
+			database.LikePattern(f.Query), strings.ToLower(f.Query), strings.ToLower(f.Query)).
+		WhereIf(f.Status != "", "status = ?", f.Status).
+		WhereIf(f.MerchantID != nil, "merchant_id = ?", f.MerchantID)
+
+	total, err := r.count(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := q.OrderBy("created_at DESC", "id").Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	var rows []campaignRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, err
+	}
+	campaigns := make([]*models.Campaign, len(rows))
+	for i, row := range rows {
+		campaigns[i] = row.toModel()
+	}
+	return campaigns, total, nil
+}
+
+// ListMerchants returns a page of merchants, derived from the campaigns they
+// run, most recently active first
+func (r *AdminRepository) ListMerchants(ctx context.Context, query string, limit, offset int) ([]MerchantSummary, int64, error) {
+	q := database.NewSelect(
+		"merchant_id", "merchant_wallet",
+		"COUNT(*) AS campaigns",
+		"COUNT(*) FILTER (WHERE status IN ('recruiting', 'reached', 'fulfillment', 'paused')) AS active_campaigns",
+		"COALESCE(SUM(current_amount), 0) AS total_raised",
+		"MAX(created_at) AS last_campaign_at",
+	).
+		From("campaigns").
+		WhereIf(query != "", "merchant_wallet = ?", strings.ToLower(query)).
+		GroupBy("merchant_id", "merchant_wallet")
+
+	// Grouped queries need their own count (see CountSQL)
+	countSQL, countArgs := database.NewSelect("COUNT(DISTINCT (merchant_id, merchant_wallet))").
+		From("campaigns").
+		WhereIf(query != "", "merchant_wallet = ?", strings.ToLower(query)).
+		ToSQL()
+	var total int64
+	if err := r.db.GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+		return nil, 0, err
+	}
+
+	listSQL, args := q.OrderBy("last_campaign_at DESC").Limit(int64(limit)).Offset(int64(offset)).ToSQL()
+	merchants := []MerchantSummary{}
+	if err := r.db.SelectContext(ctx, &merchants, listSQL, args...); err != nil {
+		return nil, 0, err
+	}
+	for i := range merchants {
+		merchants[i].MerchantWallet = address.Display(merchants[i].MerchantWallet)
+	}
+	return merchants, total, nil
+}
+
+// ListPayments returns a page of payments, newest first, and the matching
+// total
+func (r *AdminRepository) ListPayments(ctx context.Context, f PaymentFilter) ([]*models.Payment, int64, error) {
+	q := database.NewSelect(paymentColumns).
+		From("payments").
+		WhereIf(f.Status != "", "status = ?", f.Status).
+		WhereIf(f.Mode != "", "mode = ?", f.Mode).
+		WhereIf(f.CampaignID != nil, "campaign_id = ?", f.CampaignID).
+		WhereIf(f.UserID != nil, "user_id = ?", f.UserID)
+
+	total, err := r.count(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query, args := q.OrderBy("created_at DESC", "id").Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	var rows []paymentRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, err
+	}
+	payments := make([]*models.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = row.toModel()
+	}
+	return payments, total, nil
+}
+
+// CountByStatus returns row counts per status of table (users, campaigns
+// or payments)
+func (r *AdminRepository) CountByStatus(ctx context.Context, table string) (map[string]int64, error) {
+	var rows []StatusCount
+	query := `SELECT status, COUNT(*) AS count FROM ` + table + ` GROUP BY status`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// FindUserForUpdate loads a user's status and role inside tx and locks the
+// row until the transaction ends
+func (r *AdminRepository) FindUserForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	query := `SELECT id, status, role FROM users WHERE id = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForNoKeyUpdate, &user, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetUserStatus changes a user's status inside tx
+func (r *AdminRepository) SetUserStatus(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status string) error {
+	query := `UPDATE users SET status = $2, updated_at = NOW() WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, id, status)
+	return err
+}
+
+// SetUserRole changes a user's role inside tx
+func (r *AdminRepository) SetUserRole(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, role string) error {
+	query := `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, id, role)
+	return err
+}
+
+// DeleteUserSessions signs the user out everywhere; their access tokens stop
+// validating because auth-server checks the session on every request
+func (r *AdminRepository) DeleteUserSessions(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM sessions WHERE user_id = $1`, id)
+	return err
+}
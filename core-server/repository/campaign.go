@@ -0,0 +1,440 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const campaignColumns = `
+	id, chain_address, title, description, image_url, merchant_id,
+	merchant_wallet, base_price, min_qty, current_qty, target_amount,
+	current_amount, discount_rate, save_floor_bps, r_max_bps,
+	merchant_fee_bps, ops_fee_bps, start_time, end_time, settlement_date,
+	status, tx_hash, block_number, created_at, updated_at, metadata,
+	metadata_uri, metadata_hash, metadata_published_at, cancel_deadline,
+	late_cancel_penalty_bps, image_thumbnail_url, category, tags, version`
+
+// campaignRow mirrors the campaigns table; NUMERIC and JSONB columns are
+// scanned into BigInt and JSONB
+type campaignRow struct {
+	ID             uuid.UUID             `db:"id"`
+	ChainAddress   *string               `db:"chain_address"`
+	Title          string                `db:"title"`
+	Description    *string               `db:"description"`
+	ImageURL       *string               `db:"image_url"`
+	MerchantID     *uuid.UUID            `db:"merchant_id"`
+	MerchantWallet string                `db:"merchant_wallet"`
+	BasePrice      models.BigInt         `db:"base_price"`
+	MinQty         int                   `db:"min_qty"`
+	CurrentQty     int                   `db:"current_qty"`
+	TargetAmount   models.BigInt         `db:"target_amount"`
+	CurrentAmount  models.BigInt         `db:"current_amount"`
+	DiscountRate   int                   `db:"discount_rate"`
+	SaveFloorBps   int                   `db:"save_floor_bps"`
+	RMaxBps        int                   `db:"r_max_bps"`
+	MerchantFeeBps int                   `db:"merchant_fee_bps"`
+	OpsFeeBps      int                   `db:"ops_fee_bps"`
+	StartTime      time.Time             `db:"start_time"`
+	EndTime        time.Time             `db:"end_time"`
+	SettlementDate *time.Time            `db:"settlement_date"`
+	Status         models.CampaignStatus `db:"status"`
+	TxHash         *string               `db:"tx_hash"`
+	BlockNumber    *int64                `db:"block_number"`
+	CreatedAt      time.Time             `db:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at"`
+	Metadata       models.JSONB          `db:"metadata"`
+
+	MetadataURI         *string    `db:"metadata_uri"`
+	MetadataHash        *string    `db:"metadata_hash"`
+	MetadataPublishedAt *time.Time `db:"metadata_published_at"`
+
+	CancelDeadline       *time.Time `db:"cancel_deadline"`
+	LateCancelPenaltyBps *int       `db:"late_cancel_penalty_bps"`
+	ImageThumbnailURL    *string    `db:"image_thumbnail_url"`
+	Version              int64      `db:"version"`
+
+	Category *string        `db:"category"`
+	Tags     pq.StringArray `db:"tags"`
+}
+
+func (r campaignRow) toModel() *models.Campaign {
+	c := &models.Campaign{
+		ID:             r.ID,
+		Title:          r.Title,
+		Description:    r.Description,
+		ImageURL:       r.ImageURL,
+		MerchantID:     r.MerchantID,
+		MerchantWallet: address.Display(r.MerchantWallet),
+		BasePrice:      r.BasePrice,
+		MinQty:         r.MinQty,
+		CurrentQty:     r.CurrentQty,
+		TargetAmount:   r.TargetAmount,
+		CurrentAmount:  r.CurrentAmount,
+		DiscountRate:   r.DiscountRate,
+		SaveFloorBps:   r.SaveFloorBps,
+		RMaxBps:        r.RMaxBps,
+		MerchantFeeBps: r.MerchantFeeBps,
+		OpsFeeBps:      r.OpsFeeBps,
+		StartTime:      r.StartTime,
+		EndTime:        r.EndTime,
+		SettlementDate: r.SettlementDate,
+		Status:         r.Status,
+		TxHash:         r.TxHash,
+		BlockNumber:    r.BlockNumber,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		Metadata:       r.Metadata,
+
+		MetadataURI:         r.MetadataURI,
+		MetadataHash:        r.MetadataHash,
+		MetadataPublishedAt: r.MetadataPublishedAt,
+
+		CancelDeadline:       r.CancelDeadline,
+		LateCancelPenaltyBps: r.LateCancelPenaltyBps,
+		ImageThumbnailURL:    r.ImageThumbnailURL,
+		Version:              r.Version,
+
+		Category: r.Category,
+		Tags:     r.Tags,
+	}
+	// NULL until the campaign is deployed
+	if r.ChainAddress != nil {
+		c.ChainAddress = address.Display(*r.ChainAddress)
+	}
+	return c
+}
+
+// tags keeps a campaign without tags at the column's empty array rather
+// than NULL
+func tags(t pq.StringArray) pq.StringArray {
+	if t == nil {
+		return pq.StringArray{}
+	}
+	return t
+}
+
+// ErrDuplicateChainAddress is returned by SetDeployment when another
+// campaign is deployed at the address
+var ErrDuplicateChainAddress = errors.New("another campaign is deployed at this address")
+
+type CampaignFilter struct {
+	Status string
+	// Category is a category slug; Tag matches campaigns carrying it
+	Category string
+	Tag      string
+	// SortByCategory lists campaigns grouped by category in display order,
+	// uncategorized ones last, instead of newest first
+	SortByCategory bool
+	Limit          int
+	Offset         int
+}
+
+type CampaignRepository struct {
+	db *database.DB
+}
+
+func NewCampaignRepository(db *database.DB) *CampaignRepository {
+	return &CampaignRepository{db: db}
+}
+
+func (r *CampaignRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	var row campaignRow
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindByChainAddress returns the campaign deployed at chainAddress, or nil
+func (r *CampaignRepository) FindByChainAddress(ctx context.Context, chainAddress string) (*models.Campaign, error) {
+	var row campaignRow
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE chain_address = $1`
+
+	err := r.db.GetContext(ctx, &row, query, strings.ToLower(chainAddress))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindByIDForUpdate loads the campaign inside tx and holds the given row lock
+// until the transaction ends
+func (r *CampaignRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, lock database.RowLock) (*models.Campaign, error) {
+	var row campaignRow
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	err := database.GetForUpdate(ctx, tx, lock, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// where returns the filter's WHERE clause (empty when unfiltered) and args
+func (f CampaignFilter) where() (string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if f.Status != "" {
+		args = append(args, f.Status)
+		where = append(where, "status = $"+strconv.Itoa(len(args)))
+	}
+	if f.Category != "" {
+		args = append(args, f.Category)
+		where = append(where, "category = $"+strconv.Itoa(len(args)))
+	}
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		where = append(where, "$"+strconv.Itoa(len(args))+" = ANY(tags)")
+	}
+
+	if len(where) == 0 {
+		return "", args
+	}
+	return ` WHERE ` + strings.Join(where, " AND "), args
+}
+
+// Count returns how many campaigns match the filter, ignoring Limit/Offset
+func (r *CampaignRepository) Count(ctx context.Context, filter CampaignFilter) (int64, error) {
+	where, args := filter.where()
+
+	var total int64
+	err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM campaigns`+where, args...)
+	return total, err
+}
+
+func (r *CampaignRepository) List(ctx context.Context, filter CampaignFilter) ([]*models.Campaign, error) {
+	where, args := filter.where()
+
+	query := `SELECT ` + campaignColumns + ` FROM campaigns` + where
+	if filter.SortByCategory {
+		query += ` ORDER BY (SELECT position FROM campaign_categories cc WHERE cc.slug = category) NULLS LAST, category, created_at DESC`
+	} else {
+		query += ` ORDER BY created_at DESC`
+	}
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit, filter.Offset)
+		query += ` LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+	}
+
+	var rows []campaignRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]*models.Campaign, len(rows))
+	for i, row := range rows {
+		campaigns[i] = row.toModel()
+	}
+	return campaigns, nil
+}
+
+// Create inserts the campaign inside tx and sets its version
+func (r *CampaignRepository) Create(ctx context.Context, tx *sqlx.Tx, c *models.Campaign) error {
+	query := `
+		INSERT INTO campaigns (
+			id, chain_address, title, description, image_url, merchant_id,
+			merchant_wallet, base_price, min_qty, target_amount, discount_rate,
+			save_floor_bps, r_max_bps, merchant_fee_bps, ops_fee_bps,
+			start_time, end_time, settlement_date, status, metadata,
+			cancel_deadline, late_cancel_penalty_bps, image_thumbnail_url,
+			category, tags
+		) VALUES (
+			$1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25
+		)
+		RETURNING version`
+
+	return tx.QueryRowxContext(
+		ctx,
+		query,
+		c.ID,
+		strings.ToLower(c.ChainAddress),
+		c.Title,
+		c.Description,
+		c.ImageURL,
+		c.MerchantID,
+		strings.ToLower(c.MerchantWallet),
+		c.BasePrice,
+		c.MinQty,
+		c.TargetAmount,
+		c.DiscountRate,
+		c.SaveFloorBps,
+		c.RMaxBps,
+		c.MerchantFeeBps,
+		c.OpsFeeBps,
+		c.StartTime,
+		c.EndTime,
+		c.SettlementDate,
+		c.Status,
+		c.Metadata,
+		c.CancelDeadline,
+		c.LateCancelPenaltyBps,
+		c.ImageThumbnailURL,
+		c.Category,
+		tags(c.Tags),
+	).Scan(&c.Version)
+}
+
+// Update writes the editable fields inside tx and sets c.Version to the
+// version the row ends up with
+func (r *CampaignRepository) Update(ctx context.Context, tx *sqlx.Tx, c *models.Campaign) error {
+	query := `
+		UPDATE campaigns
+		SET title = $2, description = $3, image_url = $4, start_time = $5,
+		    end_time = $6, settlement_date = $7, cancel_deadline = $8,
+		    late_cancel_penalty_bps = $9, image_thumbnail_url = $10, category = $11,
+		    tags = $12, updated_at = NOW()
+		WHERE id = $1
+		RETURNING version`
+
+	return tx.QueryRowxContext(
+		ctx, query, c.ID, c.Title, c.Description, c.ImageURL, c.StartTime, c.EndTime,
+		c.SettlementDate, c.CancelDeadline, c.LateCancelPenaltyBps, c.ImageThumbnailURL,
+		c.Category, tags(c.Tags),
+	).Scan(&c.Version)
+}
+
+// UpdateMetadata shallow-merges patch into the campaign metadata and returns
+// the result inside tx. Keys set to null in patch are removed.
+func (r *CampaignRepository) UpdateMetadata(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	return updateMetadata(ctx, tx, "campaigns", id, patch)
+}
+
+// SetMetadataURI records the published metadata document inside tx. It
+// leaves updated_at alone: publishing does not change the campaign.
+func (r *CampaignRepository) SetMetadataURI(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, uri, hash string, publishedAt time.Time) error {
+	query := `
+		UPDATE campaigns
+		SET metadata_uri = $2, metadata_hash = $3, metadata_published_at = $4
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, uri, hash, publishedAt)
+	return err
+}
+
+// UpdateTotals writes current_amount, current_qty and status inside tx
+func (r *CampaignRepository) UpdateTotals(ctx context.Context, tx *sqlx.Tx, c *models.Campaign) error {
+	query := `
+		UPDATE campaigns
+		SET current_amount = $2, current_qty = $3, status = $4, updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, c.ID, c.CurrentAmount, c.CurrentQty, c.Status)
+	return err
+}
+
+// UpdateStatus sets the campaign status inside tx
+func (r *CampaignRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status models.CampaignStatus) error {
+	query := `UPDATE campaigns SET status = $2, updated_at = NOW() WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, id, status)
+	return err
+}
+
+// SetDeployment records where the campaign was deployed, with its status,
+// inside tx and sets c.Version to the version the row ends up with. The
+// two are written together since only undeployed campaigns may lack an
+// address. It returns ErrDuplicateChainAddress when another campaign has
+// the address.
+func (r *CampaignRepository) SetDeployment(ctx context.Context, tx *sqlx.Tx, c *models.Campaign) error {
+	query := `
+		UPDATE campaigns
+		SET chain_address = $2, tx_hash = $3, block_number = $4, status = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING version`
+
+	err := tx.QueryRowxContext(ctx, query, c.ID, strings.ToLower(c.ChainAddress), c.TxHash, c.BlockNumber, c.Status).Scan(&c.Version)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateChainAddress
+	}
+	return err
+}
+
+// MarkSettled moves the campaign to settled inside tx
+func (r *CampaignRepository) MarkSettled(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, settledAt time.Time) error {
+	query := `
+		UPDATE campaigns
+		SET status = $2, settlement_date = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, models.StatusSettled, settledAt)
+	return err
+}
+
+// RecordTransition appends a status change to the campaign history inside tx
+func (r *CampaignRepository) RecordTransition(ctx context.Context, tx *sqlx.Tx, t *models.CampaignTransition) error {
+	query := `
+		INSERT INTO campaign_status_history (
+			id, campaign_id, from_status, to_status, actor_id, actor_type, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := tx.ExecContext(ctx, query,
+		t.ID, t.CampaignID, t.FromStatus, t.ToStatus, t.ActorID, t.ActorType, t.Reason, t.CreatedAt)
+	return err
+}
+
+// FindTransitions returns the status history of a campaign, oldest first
+func (r *CampaignRepository) FindTransitions(ctx context.Context, campaignID uuid.UUID) ([]*models.CampaignTransition, error) {
+	query := `
+		SELECT id, campaign_id, from_status, to_status, actor_id, actor_type, reason, created_at
+		FROM campaign_status_history
+		WHERE campaign_id = $1
+		ORDER BY created_at, id`
+
+	transitions := []*models.CampaignTransition{}
+	if err := r.db.SelectContext(ctx, &transitions, query, campaignID); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// CreateReview stores a review decision inside tx
+func (r *CampaignRepository) CreateReview(ctx context.Context, tx *sqlx.Tx, rv *models.CampaignReview) error {
+	query := `
+		INSERT INTO campaign_reviews (id, campaign_id, reviewer_id, decision, comment, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.ExecContext(ctx, query, rv.ID, rv.CampaignID, rv.ReviewerID, rv.Decision, rv.Comment, rv.CreatedAt)
+	return err
+}
+
+// FindReviews returns the review decisions on a campaign, oldest first
+func (r *CampaignRepository) FindReviews(ctx context.Context, campaignID uuid.UUID) ([]*models.CampaignReview, error) {
+	query := `
+		SELECT id, campaign_id, reviewer_id, decision, comment, created_at
+		FROM campaign_reviews
+		WHERE campaign_id = $1
+		ORDER BY created_at, id`
+
+	reviews := []*models.CampaignReview{}
+	if err := r.db.SelectContext(ctx, &reviews, query, campaignID); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const categoryColumns = `slug, name, position, created_at, updated_at`
+
+var (
+	// ErrDuplicateCategory is returned by Create when the slug is taken
+	ErrDuplicateCategory = errors.New("a category with this slug already exists")
+	// ErrCategoryInUse is returned by Delete while campaigns are in the
+	// category
+	ErrCategoryInUse = errors.New("the category still has campaigns")
+)
+
+// CategoryRepository stores the campaign category taxonomy
+type CategoryRepository struct {
+	db *database.DB
+}
+
+func NewCategoryRepository(db *database.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// List returns every category by position, then slug
+func (r *CategoryRepository) List(ctx context.Context) ([]*models.CampaignCategory, error) {
+	categories := []*models.CampaignCategory{}
+	query := `SELECT ` + categoryColumns + ` FROM campaign_categories ORDER BY position, slug`
+
+	if err := r.db.SelectContext(ctx, &categories, query); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FindBySlug returns a category, or nil
+func (r *CategoryRepository) FindBySlug(ctx context.Context, slug string) (*models.CampaignCategory, error) {
+	var c models.CampaignCategory
+	query := `SELECT ` + categoryColumns + ` FROM campaign_categories WHERE slug = $1`
+
+	err := r.db.GetContext(ctx, &c, query, slug)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindForUpdate loads a category inside tx and locks the row until the
+// transaction ends, or returns nil
+func (r *CategoryRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, slug string) (*models.CampaignCategory, error) {
+	var c models.CampaignCategory
+	query := `SELECT ` + categoryColumns + ` FROM campaign_categories WHERE slug = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &c, query, slug)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create inserts a category inside tx
+func (r *CategoryRepository) Create(ctx context.Context, tx *sqlx.Tx, c *models.CampaignCategory) error {
+	query := `
+		INSERT INTO campaign_categories (slug, name, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.ExecContext(ctx, query, c.Slug, c.Name, c.Position, c.CreatedAt, c.UpdatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateCategory
+	}
+	return err
+}
+
+// Update writes a category's name and position inside tx
+func (r *CategoryRepository) Update(ctx context.Context, tx *sqlx.Tx, c *models.CampaignCategory) error {
+	query := `
+		UPDATE campaign_categories
+		SET name = $2, position = $3, updated_at = $4
+		WHERE slug = $1`
+
+	_, err := tx.ExecContext(ctx, query, c.Slug, c.Name, c.Position, c.UpdatedAt)
+	return err
+}
+
+// Delete removes a category inside tx. It returns ErrCategoryInUse while
+// campaigns reference it.
+func (r *CategoryRepository) Delete(ctx context.Context, tx *sqlx.Tx, slug string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM campaign_categories WHERE slug = $1`, slug)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrCategoryInUse
+	}
+	return err
+}
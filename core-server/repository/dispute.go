@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// Disputes are read with the participant's wallet and the campaign contract
+const (
+	disputeColumns = `
+	d.id, d.participation_id, d.campaign_id, d.user_id, d.kind, d.status,
+	d.reason, d.resolution_note, d.refund_amount, d.refund_method,
+	d.payment_id, d.refund_tx_hash, d.resolved_by, d.resolved_at,
+	d.refunded_at, d.sla_due_at, d.sla_breached_at, d.created_at,
+	d.updated_at, p.wallet_address, COALESCE(c.chain_address, '') AS chain_address`
+	disputeFrom = `
+	disputes d
+	JOIN participations p ON p.id = d.participation_id
+	JOIN campaigns c ON c.id = d.campaign_id`
+)
+
+// lockDispute locks only the dispute row of a joined select
+const lockDispute = database.ForNoKeyUpdate + " OF d"
+
+// ErrDuplicateDispute is returned by Create when the participation already
+// has an open or refunding dispute
+var ErrDuplicateDispute = errors.New("participation already has a pending dispute")
+
+// DisputeFilter selects disputes for the admin API. Overdue keeps pending
+// disputes whose SLA has passed.
+type DisputeFilter struct {
+	Status     string
+	Kind       string
+	CampaignID *uuid.UUID
+	UserID     *uuid.UUID
+	Overdue    bool
+	Now        time.Time
+	Limit      int
+	Offset     int
+}
+
+// DisputeRepository stores disputes and their resolutions
+type DisputeRepository struct {
+	db *database.DB
+}
+
+func NewDisputeRepository(db *database.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// Create inserts d inside tx
+func (r *DisputeRepository) Create(ctx context.Context, tx *sqlx.Tx, d *models.Dispute) error {
+	query := `
+		INSERT INTO disputes (
+			id, participation_id, campaign_id, user_id, kind, status, reason,
+			sla_due_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $9
+		)`
+
+	_, err := tx.ExecContext(ctx, query,
+		d.ID, d.ParticipationID, d.CampaignID, d.UserID, d.Kind, d.Status, d.Reason,
+		d.SLADueAt, d.CreatedAt,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateDispute
+	}
+	return err
+}
+
+// FindByID returns a dispute, or nil
+func (r *DisputeRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Dispute, error) {
+	var d models.Dispute
+	err := r.db.GetContext(ctx, &d, `SELECT `+disputeColumns+` FROM `+disputeFrom+` WHERE d.id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return displayDispute(&d), nil
+}
+
+// FindForUpdate returns a dispute locked inside tx, or nil
+func (r *DisputeRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.Dispute, error) {
+	var d models.Dispute
+	query := `SELECT ` + disputeColumns + ` FROM ` + disputeFrom + ` WHERE d.id = $1`
+
+	err := database.GetForUpdate(ctx, tx, lockDispute, &d, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return displayDispute(&d), nil
+}
+
+// FindRefundingByPayment returns the disputes waiting for a refund of the
+// payment, locked inside tx
+func (r *DisputeRepository) FindRefundingByPayment(ctx context.Context, tx *sqlx.Tx, paymentID uuid.UUID) ([]*models.Dispute, error) {
+	disputes := []*models.Dispute{}
+	query := `
+		SELECT ` + disputeColumns + `
+		FROM ` + disputeFrom + `
+		WHERE d.payment_id = $1 AND d.status = $2
+		ORDER BY d.created_at`
+
+	if err := database.SelectForUpdate(ctx, tx, lockDispute, &disputes, query, paymentID, models.DisputeRefunding); err != nil {
+		return nil, err
+	}
+	for _, d := range disputes {
+		displayDispute(d)
+	}
+	return disputes, nil
+}
+
+// RefundTotals returns what the disputes in one of statuses refund to each
+// of the participations, read inside tx; participations without such
+// refunds are absent
+func (r *DisputeRepository) RefundTotals(ctx context.Context, tx *sqlx.Tx, participationIDs []uuid.UUID, statuses ...string) (map[uuid.UUID]*big.Int, error) {
+	var rows []struct {
+		ParticipationID uuid.UUID     `db:"participation_id"`
+		Total           models.BigInt `db:"total"`
+	}
+	query := `
+		SELECT participation_id, SUM(refund_amount) AS total
+		FROM disputes
+		WHERE participation_id = ANY($1) AND status = ANY($2) AND refund_amount IS NOT NULL
+		GROUP BY participation_id`
+
+	if err := tx.SelectContext(ctx, &rows, query, pq.Array(participationIDs), pq.Array(statuses)); err != nil {
+		return nil, err
+	}
+	totals := make(map[uuid.UUID]*big.Int, len(rows))
+	for _, row := range rows {
+		totals[row.ParticipationID] = row.Total.Int
+	}
+	return totals, nil
+}
+
+// Update writes the status, resolution, refund and SLA of d inside tx
+func (r *DisputeRepository) Update(ctx context.Context, tx *sqlx.Tx, d *models.Dispute) error {
+	query := `
+		UPDATE disputes
+		SET status = $2, resolution_note = $3, refund_amount = $4,
+		    refund_method = $5, payment_id = $6, refund_tx_hash = $7,
+		    resolved_by = $8, resolved_at = $9, refunded_at = $10,
+		    sla_due_at = $11, sla_breached_at = $12, updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query,
+		d.ID, d.Status, d.ResolutionNote, d.RefundAmount,
+		d.RefundMethod, d.PaymentID, d.RefundTxHash,
+		d.ResolvedBy, d.ResolvedAt, d.RefundedAt,
+		d.SLADueAt, d.SLABreachedAt,
+	)
+	return err
+}
+
+// CloseRedelivered closes the open fulfillment disputes of the
+// participations as redelivered inside tx
+func (r *DisputeRepository) CloseRedelivered(ctx context.Context, tx *sqlx.Tx, participationIDs []uuid.UUID, at time.Time) error {
+	if len(participationIDs) == 0 {
+		return nil
+	}
+	query := `
+		UPDATE disputes
+		SET status = $3, resolved_at = $4, updated_at = NOW()
+		WHERE participation_id = ANY($1) AND kind = $2 AND status = $5`
+
+	_, err := tx.ExecContext(ctx, query,
+		pq.Array(participationIDs), models.DisputeOnFulfillment, models.DisputeRedelivered, at,
+		models.DisputeOpen,
+	)
+	return err
+}
+
+// FindByUser returns one page of the user's disputes, newest first, and
+// their total
+func (r *DisputeRepository) FindByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Dispute, int64, error) {
+	return r.List(ctx, DisputeFilter{UserID: &userID, Limit: page.Limit, Offset: page.Offset})
+}
+
+// List returns the disputes matching f and their total: pending ones by
+// SLA, the most urgent first, otherwise the newest first
+func (r *DisputeRepository) List(ctx context.Context, f DisputeFilter) ([]*models.Dispute, int64, error) {
+	q := database.NewSelect(disputeColumns).
+		From(disputeFrom).
+		WhereIf(f.Status != "", "d.status = ?", f.Status).
+		WhereIf(f.Kind != "", "d.kind = ?", f.Kind).
+		WhereIf(f.CampaignID != nil, "d.campaign_id = ?", f.CampaignID).
+		WhereIf(f.UserID != nil, "d.user_id = ?", f.UserID).
+		WhereIf(f.Overdue, "d.status IN (?, ?) AND d.sla_due_at <= ?", models.DisputeOpen, models.DisputeRefunding, f.Now)
+
+	query, args := q.CountSQL()
+	var total int64
+	if err := r.db.GetContext(ctx, &total, query, args...); err != nil {
+		return nil, 0, err
+	}
+
+	order := []string{"d.created_at DESC", "d.id"}
+	if f.Overdue || f.Status == models.DisputeOpen || f.Status == models.DisputeRefunding {
+		order = []string{"d.sla_due_at", "d.id"}
+	}
+	query, args = q.OrderBy(order...).Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	disputes := []*models.Dispute{}
+	if err := r.db.SelectContext(ctx, &disputes, query, args...); err != nil {
+		return nil, 0, err
+	}
+	for _, d := range disputes {
+		displayDispute(d)
+	}
+	return disputes, total, nil
+}
+
+// displayDispute formats the participant's wallet like participations do
+func displayDispute(d *models.Dispute) *models.Dispute {
+	d.WalletAddress = address.Display(d.WalletAddress)
+	return d
+}
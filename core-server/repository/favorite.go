@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+// FavoriteRepository stores users' watchlists. Listing them is served by
+// query-server.
+type FavoriteRepository struct {
+	db *database.DB
+}
+
+func NewFavoriteRepository(db *database.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Find returns the user's favorite of the campaign inside tx, or nil
+func (r *FavoriteRepository) Find(ctx context.Context, tx *sqlx.Tx, userID, campaignID uuid.UUID) (*models.CampaignFavorite, error) {
+	var f models.CampaignFavorite
+	query := `
+		SELECT user_id, campaign_id, created_at
+		FROM campaign_favorites WHERE user_id = $1 AND campaign_id = $2`
+
+	err := tx.GetContext(ctx, &f, query, userID, campaignID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Count returns how many campaigns the user favorited, inside tx
+func (r *FavoriteRepository) Count(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (int, error) {
+	var n int
+	err := tx.GetContext(ctx, &n, `SELECT COUNT(*) FROM campaign_favorites WHERE user_id = $1`, userID)
+	return n, err
+}
+
+// Create inserts a favorite inside tx
+func (r *FavoriteRepository) Create(ctx context.Context, tx *sqlx.Tx, f *models.CampaignFavorite) error {
+	query := `
+		INSERT INTO campaign_favorites (user_id, campaign_id, created_at)
+		VALUES ($1, $2, $3)`
+
+	_, err := tx.ExecContext(ctx, query, f.UserID, f.CampaignID, f.CreatedAt)
+	return err
+}
+
+// Delete removes the user's favorite of the campaign, if any
+func (r *FavoriteRepository) Delete(ctx context.Context, userID, campaignID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM campaign_favorites WHERE user_id = $1 AND campaign_id = $2`, userID, campaignID)
+	return err
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+const fulfillmentColumns = `
+	participation_id, campaign_id, user_id, status, proof_hash, proof_url,
+	proof_thumbnail_url, note, fulfilled_by, fulfilled_at, confirm_deadline,
+	responded_at, dispute_reason`
+
+// FulfillmentRepository stores merchants' fulfillment marks and the
+// participants' answers
+type FulfillmentRepository struct {
+	db *database.DB
+}
+
+func NewFulfillmentRepository(db *database.DB) *FulfillmentRepository {
+	return &FulfillmentRepository{db: db}
+}
+
+// MarkFulfilled marks the campaign's active participations fulfilled with
+// f's proof inside tx, only those in participationIDs unless it is empty.
+// Participations already fulfilled or confirmed are left alone; disputed
+// ones are marked again unless their dispute is being refunded. It returns
+// the fulfillments it wrote.
+func (r *FulfillmentRepository) MarkFulfilled(ctx context.Context, tx *sqlx.Tx, campaignID uuid.UUID, participationIDs []uuid.UUID, f *models.Fulfillment) ([]*models.Fulfillment, error) {
+	var ids interface{}
+	if len(participationIDs) > 0 {
+		ids = pq.Array(participationIDs)
+	}
+	query := `
+		INSERT INTO fulfillments (
+			participation_id, campaign_id, user_id, status, proof_hash, proof_url,
+			proof_thumbnail_url, note, fulfilled_by, fulfilled_at, confirm_deadline
+		)
+		SELECT p.id, p.campaign_id, p.user_id, $3, $4, $5, $6, $7, $8, $9, $10
+		FROM participations p
+		WHERE p.campaign_id = $1 AND p.status = $11
+			AND ($2::uuid[] IS NULL OR p.id = ANY($2::uuid[]))
+		ON CONFLICT (participation_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			proof_hash = EXCLUDED.proof_hash,
+			proof_url = EXCLUDED.proof_url,
+			proof_thumbnail_url = EXCLUDED.proof_thumbnail_url,
+			note = EXCLUDED.note,
+			fulfilled_by = EXCLUDED.fulfilled_by,
+			fulfilled_at = EXCLUDED.fulfilled_at,
+			confirm_deadline = EXCLUDED.confirm_deadline,
+			responded_at = NULL,
+			dispute_reason = NULL
+		WHERE fulfillments.status = $12 AND NOT EXISTS (
+			SELECT 1 FROM disputes d
+			WHERE d.participation_id = fulfillments.participation_id AND d.status = $13
+		)
+		RETURNING ` + fulfillmentColumns
+
+	marked := []*models.Fulfillment{}
+	err := tx.SelectContext(ctx, &marked, query,
+		campaignID, ids, models.FulfillmentFulfilled, f.ProofHash, f.ProofURL,
+		f.ProofThumbnailURL, f.Note, f.FulfilledBy, f.FulfilledAt, f.ConfirmDeadline,
+		ParticipationActive, models.FulfillmentDisputed, models.DisputeRefunding,
+	)
+	return marked, err
+}
+
+// FindForUpdate returns the fulfillment of a participation locked inside
+// tx, or nil
+func (r *FulfillmentRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, participationID uuid.UUID) (*models.Fulfillment, error) {
+	var f models.Fulfillment
+	query := `SELECT ` + fulfillmentColumns + ` FROM fulfillments WHERE participation_id = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForNoKeyUpdate, &f, query, participationID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Respond stores the participant's confirmation or dispute inside tx
+func (r *FulfillmentRepository) Respond(ctx context.Context, tx *sqlx.Tx, f *models.Fulfillment) error {
+	query := `
+		UPDATE fulfillments SET status = $2, responded_at = $3, dispute_reason = $4
+		WHERE participation_id = $1`
+
+	_, err := tx.ExecContext(ctx, query, f.ParticipationID, f.Status, f.RespondedAt, f.DisputeReason)
+	return err
+}
+
+// FindByUser returns one page of the user's fulfillments, newest first,
+// and their total
+func (r *FulfillmentRepository) FindByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Fulfillment, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM fulfillments WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, err
+	}
+
+	fulfillments := []*models.Fulfillment{}
+	query := `
+		SELECT ` + fulfillmentColumns + `
+		FROM fulfillments WHERE user_id = $1
+		ORDER BY fulfilled_at DESC, participation_id
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.SelectContext(ctx, &fulfillments, query, userID, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	return fulfillments, total, nil
+}
+
+// FindByCampaign returns one page of the campaign's fulfillments, of the
+// given status unless it is empty, and their total
+func (r *FulfillmentRepository) FindByCampaign(ctx context.Context, campaignID uuid.UUID, status string, page pagination.Page) ([]*models.Fulfillment, int64, error) {
+	where := `WHERE campaign_id = $1 AND ($2 = '' OR status = $2)`
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM fulfillments `+where, campaignID, status); err != nil {
+		return nil, 0, err
+	}
+
+	fulfillments := []*models.Fulfillment{}
+	query := `
+		SELECT ` + fulfillmentColumns + `
+		FROM fulfillments ` + where + `
+		ORDER BY fulfilled_at DESC, participation_id
+		LIMIT $3 OFFSET $4`
+
+	if err := r.db.SelectContext(ctx, &fulfillments, query, campaignID, status, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	return fulfillments, total, nil
+}
+
+// Summary counts the campaign's active participations by fulfillment state
+// at now. q is the database or a transaction. Settleable and the campaign
+// fields are left to the caller.
+func (r *FulfillmentRepository) Summary(ctx context.Context, q sqlx.QueryerContext, campaignID uuid.UUID, now time.Time) (*models.FulfillmentSummary, error) {
+	s := models.FulfillmentSummary{CampaignID: campaignID}
+	query := `
+		SELECT
+			COUNT(*) AS participations,
+			COUNT(*) FILTER (WHERE f.participation_id IS NULL) AS unfulfilled,
+			COUNT(*) FILTER (WHERE f.status = $3 AND f.confirm_deadline > $4) AS awaiting_response,
+			COUNT(*) FILTER (WHERE f.status = $5 OR (f.status = $3 AND f.confirm_deadline <= $4)) AS accepted,
+			COUNT(*) FILTER (WHERE f.status = $6) AS disputed,
+			MAX(f.confirm_deadline) FILTER (WHERE f.status = $3 AND f.confirm_deadline > $4) AS confirm_deadline
+		FROM participations p
+		LEFT JOIN fulfillments f ON f.participation_id = p.id
+		WHERE p.campaign_id = $1 AND p.status = $2`
+
+	err := sqlx.GetContext(ctx, q, &s, query,
+		campaignID, ParticipationActive, models.FulfillmentFulfilled, now,
+		models.FulfillmentConfirmed, models.FulfillmentDisputed,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const mediaColumns = `
+	id, owner_id, purpose, status, content_type, size, upload_key, url,
+	thumbnail_url, expires_at, created_at, completed_at`
+
+// MediaRepository stores media uploads
+type MediaRepository struct {
+	db *database.DB
+}
+
+func NewMediaRepository(db *database.DB) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// Create inserts a pending upload
+func (r *MediaRepository) Create(ctx context.Context, u *models.MediaUpload) error {
+	query := `
+		INSERT INTO media_uploads (
+			id, owner_id, purpose, status, content_type, size, upload_key,
+			expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		u.ID, u.OwnerID, u.Purpose, u.Status, u.ContentType, u.Size, u.UploadKey,
+		u.ExpiresAt, u.CreatedAt,
+	)
+	return err
+}
+
+// FindByID returns an upload, or nil
+func (r *MediaRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.MediaUpload, error) {
+	var u models.MediaUpload
+	query := `SELECT ` + mediaColumns + ` FROM media_uploads WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &u, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// Complete records a checked upload inside tx
+func (r *MediaRepository) Complete(ctx context.Context, tx *sqlx.Tx, u *models.MediaUpload) error {
+	query := `
+		UPDATE media_uploads
+		SET status = $2, content_type = $3, size = $4, url = $5,
+		    thumbnail_url = $6, completed_at = $7
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, u.ID, u.Status, u.ContentType, u.Size, u.URL, u.ThumbnailURL, u.CompletedAt)
+	return err
+}
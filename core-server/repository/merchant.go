@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const merchantColumns = `
+	id, business_name, registration_number, contact_email, contact_phone,
+	website, country, payout_wallet, fee_bps, fee_agreed_at, status,
+	status_reason, reviewed_by, reviewed_at, created_at, updated_at,
+	logo_url, logo_thumbnail_url`
+
+// MerchantFilter selects merchant registrations for the admin API; Query
+// matches the business name or payout wallet
+type MerchantFilter struct {
+	Query  string
+	Status string
+	Limit  int
+	Offset int
+}
+
+// MerchantRepository stores merchant registrations. Payout wallets are
+// stored lower case and returned in EIP-55 form.
+type MerchantRepository struct {
+	db *database.DB
+}
+
+func NewMerchantRepository(db *database.DB) *MerchantRepository {
+	return &MerchantRepository{db: db}
+}
+
+// FindByID returns a merchant, or nil
+func (r *MerchantRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Merchant, error) {
+	var m models.Merchant
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &m, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.PayoutWallet = address.Display(m.PayoutWallet)
+	return &m, nil
+}
+
+// FindForUpdate loads a merchant inside tx and locks the row until the
+// transaction ends, or returns nil
+func (r *MerchantRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.Merchant, error) {
+	var m models.Merchant
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE id = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForNoKeyUpdate, &m, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m.PayoutWallet = address.Display(m.PayoutWallet)
+	return &m, nil
+}
+
+// List returns a page of merchants, oldest first so the review queue is
+// worked in order, and the matching total
+func (r *MerchantRepository) List(ctx context.Context, f MerchantFilter) ([]*models.Merchant, int64, error) {
+	q := database.NewSelect(merchantColumns).
+		From("merchants").
+		WhereIf(f.Query != "", "(business_name ILIKE ? OR payout_wallet = ?)", database.LikePattern(f.Query), strings.ToLower(f.Query)).
+		WhereIf(f.Status != "", "status = ?", f.Status)
+
+	countSQL, countArgs := q.CountSQL()
+	var total int64
+	if err := r.db.GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args := q.OrderBy("created_at", "id").Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	merchants := []*models.Merchant{}
+	if err := r.db.SelectContext(ctx, &merchants, query, args...); err != nil {
+		return nil, 0, err
+	}
+	for _, m := range merchants {
+		m.PayoutWallet = address.Display(m.PayoutWallet)
+	}
+	return merchants, total, nil
+}
+
+// Create inserts a registration inside tx
+func (r *MerchantRepository) Create(ctx context.Context, tx *sqlx.Tx, m *models.Merchant) error {
+	query := `
+		INSERT INTO merchants (
+			id, business_name, registration_number, contact_email, contact_phone,
+			website, country, payout_wallet, fee_bps, fee_agreed_at, status,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	_, err := tx.ExecContext(ctx, query,
+		m.ID,
+		m.BusinessName,
+		m.RegistrationNumber,
+		m.ContactEmail,
+		m.ContactPhone,
+		m.Website,
+		m.Country,
+		strings.ToLower(m.PayoutWallet),
+		m.FeeBps,
+		m.FeeAgreedAt,
+		m.Status,
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+	return err
+}
+
+// Resubmit replaces a rejected registration's details inside tx and clears
+// the previous review
+func (r *MerchantRepository) Resubmit(ctx context.Context, tx *sqlx.Tx, m *models.Merchant) error {
+	query := `
+		UPDATE merchants SET
+			business_name = $2, registration_number = $3, contact_email = $4,
+			contact_phone = $5, website = $6, country = $7, payout_wallet = $8,
+			fee_bps = $9, fee_agreed_at = $10, status = $11,
+			status_reason = NULL, reviewed_by = NULL, reviewed_at = NULL,
+			updated_at = $12
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query,
+		m.ID,
+		m.BusinessName,
+		m.RegistrationNumber,
+		m.ContactEmail,
+		m.ContactPhone,
+		m.Website,
+		m.Country,
+		strings.ToLower(m.PayoutWallet),
+		m.FeeBps,
+		m.FeeAgreedAt,
+		m.Status,
+		m.UpdatedAt,
+	)
+	return err
+}
+
+// SetLogo records the merchant's uploaded logo inside tx
+func (r *MerchantRepository) SetLogo(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, url, thumbnailURL string) error {
+	query := `UPDATE merchants SET logo_url = $2, logo_thumbnail_url = $3, updated_at = NOW() WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, url, thumbnailURL)
+	return err
+}
+
+// Review records an admin's status change inside tx
+func (r *MerchantRepository) Review(ctx context.Context, tx *sqlx.Tx, m *models.Merchant) error {
+	query := `
+		UPDATE merchants SET
+			status = $2, status_reason = $3, fee_bps = $4,
+			reviewed_by = $5, reviewed_at = $6, updated_at = $6
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, m.ID, m.Status, m.StatusReason, m.FeeBps, m.ReviewedBy, m.ReviewedAt)
+	return err
+}
+
+// DashboardCampaign is a campaign on the merchant dashboard. Amount is the
+// deposits it holds, or for a settled campaign the deposits it settled.
+type DashboardCampaign struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	Title          string                `json:"title" db:"title"`
+	Status         models.CampaignStatus `json:"status" db:"status"`
+	MinQty         int                   `json:"minQty" db:"min_qty"`
+	CurrentQty     int                   `json:"currentQty" db:"current_qty"`
+	EndTime        time.Time             `json:"endTime" db:"end_time"`
+	SettlementDate *time.Time            `json:"settlementDate,omitempty" db:"settlement_date"`
+	MerchantFeeBps int                   `json:"merchantFeeBps" db:"merchant_fee_bps"`
+	OpsFeeBps      int                   `json:"opsFeeBps" db:"ops_fee_bps"`
+	Participants   int64                 `json:"participants" db:"participants"`
+	Amount         models.BigInt         `json:"amount" db:"amount"`
+}
+
+// dashboardColumns select a DashboardCampaign from campaigns c joined to the
+// participations p being counted
+var dashboardColumns = []string{
+	"c.id", "c.title", "c.status", "c.min_qty", "c.current_qty", "c.end_time",
+	"c.settlement_date", "c.merchant_fee_bps", "c.ops_fee_bps",
+	"COUNT(p.id) AS participants",
+	"COALESCE(SUM(p.deposit_amount), 0) AS amount",
+}
+
+// CountCampaignsByStatus counts the merchant's campaigns by status
+func (r *MerchantRepository) CountCampaignsByStatus(ctx context.Context, merchantID uuid.UUID) (map[string]int64, error) {
+	var rows []StatusCount
+	query := `SELECT status, COUNT(*) AS count FROM campaigns WHERE merchant_id = $1 GROUP BY status`
+	if err := r.db.SelectContext(ctx, &rows, query, merchantID); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// OpenCampaigns returns the merchant's campaigns in statuses, soonest
+// ending first, with the deposits of their active and pending cancel
+// participations
+func (r *MerchantRepository) OpenCampaigns(ctx context.Context, merchantID uuid.UUID, statuses []models.CampaignStatus) ([]*DashboardCampaign, error) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+
+	query, args := database.NewSelect(dashboardColumns...).
+		From("campaigns c").
+		Join("LEFT JOIN participations p ON p.campaign_id = c.id AND p.status IN (?, ?)",
+			models.ParticipationActive, models.ParticipationPendingCancel).
+		Where("c.merchant_id = ?", merchantID).
+		WhereAny("c.status", values).
+		GroupBy("c.id").
+		OrderBy("c.end_time", "c.id").
+		ToSQL()
+
+	campaigns := []*DashboardCampaign{}
+	if err := r.db.SelectContext(ctx, &campaigns, query, args...); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// SettledCampaigns returns the merchant's campaigns settled in [from, to),
+// most recent first, with their settled deposits. A zero time leaves that
+// end of the range open.
+func (r *MerchantRepository) SettledCampaigns(ctx context.Context, merchantID uuid.UUID, from, to time.Time) ([]*DashboardCampaign, error) {
+	query, args := database.NewSelect(dashboardColumns...).
+		From("campaigns c").
+		Join("LEFT JOIN participations p ON p.campaign_id = c.id AND p.status = ?", models.ParticipationSettled).
+		Where("c.merchant_id = ?", merchantID).
+		Where("c.status = ?", models.StatusSettled).
+		WhereIf(!from.IsZero(), "c.settlement_date >= ?", from).
+		WhereIf(!to.IsZero(), "c.settlement_date < ?", to).
+		GroupBy("c.id").
+		OrderBy("c.settlement_date DESC", "c.id").
+		ToSQL()
+
+	campaigns := []*DashboardCampaign{}
+	if err := r.db.SelectContext(ctx, &campaigns, query, args...); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/models"
+)
+
+// updateMetadata shallow-merges patch into the metadata column of the row id
+// in table and returns the stored result, or nil if the row does not exist.
+// Keys set to null are removed, so clients can delete keys without a
+// read-modify-write round trip.
+func updateMetadata(ctx context.Context, q sqlx.QueryerContext, table string, id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	query := `
+		UPDATE ` + table + `
+		SET metadata = jsonb_strip_nulls(COALESCE(metadata, '{}'::jsonb) || $2::jsonb),
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING metadata`
+
+	var metadata models.JSONB
+	err := sqlx.GetContext(ctx, q, &metadata, query, id, patch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return metadata, err
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// topicColumns whitelists the notification_preferences column for a topic,
+// since the column name is interpolated into queries
+var topicColumns = map[string]string{
+	models.TopicCampaignMilestones: "campaign_milestones",
+	models.TopicRebatePayouts:      "rebate_payouts",
+	models.TopicWatchlist:          "watchlist",
+	models.TopicCancellations:      "cancellations",
+}
+
+// Target is a device to push to
+type Target struct {
+	UserID uuid.UUID `db:"user_id"`
+	Token  string    `db:"token"`
+}
+
+type NotificationRepository struct {
+	db *database.DB
+}
+
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// UpsertDevice registers token for the user, taking it over if another user
+// had registered it
+func (r *NotificationRepository) UpsertDevice(ctx context.Context, d *models.Device) error {
+	query := `
+		INSERT INTO device_tokens (id, user_id, token, platform, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (token) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id, created_at`
+
+	return r.db.QueryRowxContext(ctx, query, d.ID, d.UserID, d.Token, d.Platform, d.LastSeenAt).
+		Scan(&d.ID, &d.CreatedAt)
+}
+
+// DeleteDevice removes the user's token; it reports whether it existed
+func (r *NotificationRepository) DeleteDevice(ctx context.Context, userID uuid.UUID, token string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE user_id = $1 AND token = $2`, userID, token)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteToken forgets a token FCM no longer accepts
+func (r *NotificationRepository) DeleteToken(ctx context.Context, token string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE token = $1`, token)
+	return err
+}
+
+// FindDevices returns the user's registered devices, most recently seen first
+func (r *NotificationRepository) FindDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error) {
+	devices := []*models.Device{}
+	query := `
+		SELECT id, user_id, token, platform, created_at, last_seen_at
+		FROM device_tokens WHERE user_id = $1 ORDER BY last_seen_at DESC`
+
+	if err := r.db.SelectContext(ctx, &devices, query, userID); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// FindPreferences returns the user's preferences, or nil if they never
+// changed them
+func (r *NotificationRepository) FindPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	var prefs models.NotificationPreferences
+	query := `
+		SELECT user_id, campaign_milestones, rebate_payouts, watchlist, cancellations, line, email, updated_at
+		FROM notification_preferences WHERE user_id = $1`
+
+	err := r.db.GetContext(ctx, &prefs, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SavePreferences inserts or replaces the user's preferences
+func (r *NotificationRepository) SavePreferences(ctx context.Context, p *models.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, campaign_milestones, rebate_payouts, watchlist, cancellations, line, email, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+			campaign_milestones = EXCLUDED.campaign_milestones,
+			rebate_payouts = EXCLUDED.rebate_payouts,
+			watchlist = EXCLUDED.watchlist,
+			cancellations = EXCLUDED.cancellations,
+			line = EXCLUDED.line,
+			email = EXCLUDED.email,
+			updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.ExecContext(ctx, query, p.UserID, p.CampaignMilestones, p.RebatePayouts, p.Watchlist,
+		p.Cancellations, p.Line, p.Email, p.UpdatedAt)
+	return err
+}
+
+// TargetsForCampaign returns the devices of the campaign's active
+// participants who have topic enabled
+func (r *NotificationRepository) TargetsForCampaign(ctx context.Context, campaignID uuid.UUID, topic string) ([]Target, error) {
+	column, ok := topicColumns[topic]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification topic %q", topic)
+	}
+
+	targets := []Target{}
+	query := `
+		SELECT DISTINCT d.user_id, d.token
+		FROM participations p
+		JOIN device_tokens d ON d.user_id = p.user_id
+		LEFT JOIN notification_preferences np ON np.user_id = p.user_id
+		WHERE p.campaign_id = $1 AND p.status = $2
+			AND COALESCE(np.` + column + `, TRUE)`
+
+	if err := r.db.SelectContext(ctx, &targets, query, campaignID, ParticipationActive); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// TargetsForUsers returns the devices of the users who have topic enabled
+func (r *NotificationRepository) TargetsForUsers(ctx context.Context, userIDs []uuid.UUID, topic string) ([]Target, error) {
+	column, ok := topicColumns[topic]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification topic %q", topic)
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	targets := []Target{}
+	query := `
+		SELECT d.user_id, d.token
+		FROM device_tokens d
+		LEFT JOIN notification_preferences np ON np.user_id = d.user_id
+		WHERE d.user_id = ANY($1::uuid[])
+			AND COALESCE(np.` + column + `, TRUE)`
+
+	if err := r.db.SelectContext(ctx, &targets, query, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// Deliveries is one event's LINE messages and emails: Bodies[i] is the
+// text for UserIDs[i]
+type Deliveries struct {
+	EventKey string
+	Kind     string
+	Topic    string
+	Subject  string
+	UserIDs  []uuid.UUID
+	Bodies   []string
+	At       time.Time
+}
+
+// QueueDeliveries queues d for each channel of each user that has an
+// address on it (a connected LINE account, a verified email) and has the
+// channel and d.Topic enabled. Users who already got the event on a channel
+// are skipped. It returns the number queued.
+func (r *NotificationRepository) QueueDeliveries(ctx context.Context, d Deliveries) (int64, error) {
+	column, ok := topicColumns[d.Topic]
+	if !ok {
+		return 0, fmt.Errorf("unknown notification topic %q", d.Topic)
+	}
+
+	ids := make([]string, len(d.UserIDs))
+	for i, id := range d.UserIDs {
+		ids[i] = id.String()
+	}
+
+	query := `
+		INSERT INTO notification_deliveries (
+			user_id, event_key, kind, channel, recipient, subject, body, next_attempt_at, created_at
+		)
+		SELECT m.user_id, $3, $4, ch.channel, ch.recipient, $5, m.body, $6, $6
+		FROM unnest($1::uuid[], $2::text[]) AS m(user_id, body)
+		JOIN users u ON u.id = m.user_id
+		LEFT JOIN notification_preferences np ON np.user_id = u.id
+		CROSS JOIN LATERAL (VALUES
+			('` + models.ChannelLine + `', u.line_user_id, COALESCE(np.line, TRUE)),
+			('` + models.ChannelEmail + `', u.email, u.email_verified_at IS NOT NULL AND COALESCE(np.email, TRUE))
+		) AS ch(channel, recipient, enabled)
+		WHERE ch.recipient IS NOT NULL AND ch.enabled
+			AND COALESCE(np.` + column + `, TRUE)
+		ON CONFLICT (event_key, user_id, channel) DO NOTHING`
+
+	res, err := r.db.ExecContext(ctx, query, pq.Array(ids), pq.Array(d.Bodies), d.EventKey, d.Kind, d.Subject, d.At)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ParticipantIDs returns the users with an active participation in the
+// campaign
+func (r *NotificationRepository) ParticipantIDs(ctx context.Context, campaignID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := `SELECT DISTINCT user_id FROM participations WHERE campaign_id = $1 AND status = $2`
+	if err := r.db.SelectContext(ctx, &ids, query, campaignID, ParticipationActive); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindDeliveries returns one page of the user's deliveries, newest first,
+// and the user's total count
+func (r *NotificationRepository) FindDeliveries(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.NotificationDelivery, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM notification_deliveries WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := []*models.NotificationDelivery{}
+	query := `
+		SELECT id, user_id, event_key, kind, channel, recipient, subject, body, status,
+			attempts, last_error, next_attempt_at, created_at, sent_at
+		FROM notification_deliveries WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.SelectContext(ctx, &deliveries, query, userID, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
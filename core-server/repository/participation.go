@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+const participationColumns = `
+	id, campaign_id, user_id, wallet_address, deposit_amount, joined_at,
+	cancel_pending, expected_rebate, actual_rebate, status, tx_hash,
+	cancel_tx_hash, settlement_tx_hash, refund_tx_hash, created_at, updated_at,
+	metadata, cancel_penalty, version`
+
+// Participation statuses
+const (
+	ParticipationActive        = models.ParticipationActive
+	ParticipationPendingCancel = models.ParticipationPendingCancel
+	ParticipationCancelled     = models.ParticipationCancelled
+	ParticipationSettled       = models.ParticipationSettled
+	ParticipationRefunded      = models.ParticipationRefunded
+)
+
+// ErrDuplicateParticipation is returned by Create when the user already
+// joined the campaign
+var ErrDuplicateParticipation = errors.New("user already participates in this campaign")
+
+type participationRow struct {
+	ID               uuid.UUID     `db:"id"`
+	CampaignID       uuid.UUID     `db:"campaign_id"`
+	UserID           uuid.UUID     `db:"user_id"`
+	WalletAddress    string        `db:"wallet_address"`
+	DepositAmount    models.BigInt `db:"deposit_amount"`
+	JoinedAt         time.Time     `db:"joined_at"`
+	CancelPending    models.BigInt `db:"cancel_pending"`
+	ExpectedRebate   models.BigInt `db:"expected_rebate"`
+	ActualRebate     models.BigInt `db:"actual_rebate"`
+	Status           string        `db:"status"`
+	TxHash           *string       `db:"tx_hash"`
+	CancelTxHash     *string       `db:"cancel_tx_hash"`
+	SettlementTxHash *string       `db:"settlement_tx_hash"`
+	RefundTxHash     *string       `db:"refund_tx_hash"`
+	CreatedAt        time.Time     `db:"created_at"`
+	UpdatedAt        time.Time     `db:"updated_at"`
+	Metadata         models.JSONB  `db:"metadata"`
+	CancelPenalty    models.BigInt `db:"cancel_penalty"`
+	Version          int64         `db:"version"`
+}
+
+func (r participationRow) toModel() *models.Participation {
+	return &models.Participation{
+		ID:               r.ID,
+		CampaignID:       r.CampaignID,
+		UserID:           r.UserID,
+		WalletAddress:    address.Display(r.WalletAddress),
+		DepositAmount:    r.DepositAmount,
+		JoinedAt:         r.JoinedAt,
+		CancelPending:    r.CancelPending,
+		CancelPenalty:    r.CancelPenalty,
+		ExpectedRebate:   r.ExpectedRebate,
+		ActualRebate:     r.ActualRebate,
+		Status:           r.Status,
+		TxHash:           r.TxHash,
+		CancelTxHash:     r.CancelTxHash,
+		SettlementTxHash: r.SettlementTxHash,
+		RefundTxHash:     r.RefundTxHash,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		Metadata:         r.Metadata,
+		Version:          r.Version,
+	}
+}
+
+func toParticipations(rows []participationRow) []*models.Participation {
+	out := make([]*models.Participation, len(rows))
+	for i, row := range rows {
+		out[i] = row.toModel()
+	}
+	return out
+}
+
+type ParticipationRepository struct {
+	db *database.DB
+}
+
+func NewParticipationRepository(db *database.DB) *ParticipationRepository {
+	return &ParticipationRepository{db: db}
+}
+
+func (r *ParticipationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Participation, error) {
+	var row participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindByIDForUpdate loads the participation inside tx with a row lock
+func (r *ParticipationRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.Participation, error) {
+	var row participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE id = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindForUser returns the user's participation in the campaign inside tx,
+// or nil when the user has not joined it
+func (r *ParticipationRepository) FindForUser(ctx context.Context, tx *sqlx.Tx, campaignID, userID uuid.UUID) (*models.Participation, error) {
+	var row participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE campaign_id = $1 AND user_id = $2`
+
+	err := tx.GetContext(ctx, &row, query, campaignID, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindByWalletForUpdate locks the participation of wallet in the campaign
+// inside tx, or returns nil when the wallet has not joined it
+func (r *ParticipationRepository) FindByWalletForUpdate(ctx context.Context, tx *sqlx.Tx, campaignID uuid.UUID, wallet string) (*models.Participation, error) {
+	var row participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE campaign_id = $1 AND wallet_address = $2`
+
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &row, query, campaignID, strings.ToLower(wallet))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// FindByUser returns one page of the user's participations, newest first,
+// and the user's total participation count
+func (r *ParticipationRepository) FindByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Participation, int64, error) {
+	return r.findPage(ctx, "user_id = $1", "joined_at DESC", userID, page)
+}
+
+// FindByCampaign returns one page of the campaign's participations in join
+// order and the campaign's total participation count
+func (r *ParticipationRepository) FindByCampaign(ctx context.Context, campaignID uuid.UUID, page pagination.Page) ([]*models.Participation, int64, error) {
+	return r.findPage(ctx, "campaign_id = $1", "joined_at ASC", campaignID, page)
+}
+
+func (r *ParticipationRepository) findPage(ctx context.Context, where, orderBy string, id uuid.UUID, page pagination.Page) ([]*models.Participation, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM participations WHERE `+where, id); err != nil {
+		return nil, 0, err
+	}
+
+	var rows []participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE ` + where +
+		` ORDER BY ` + orderBy + ` LIMIT $2 OFFSET $3`
+	if err := r.db.SelectContext(ctx, &rows, query, id, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	return toParticipations(rows), total, nil
+}
+
+// FindActiveByCampaignForUpdate locks every active participation of the
+// campaign inside tx
+func (r *ParticipationRepository) FindActiveByCampaignForUpdate(ctx context.Context, tx *sqlx.Tx, campaignID uuid.UUID) ([]*models.Participation, error) {
+	var rows []participationRow
+	query := `SELECT ` + participationColumns + ` FROM participations WHERE campaign_id = $1 AND status = $2 ORDER BY id`
+
+	if err := database.SelectForUpdate(ctx, tx, database.ForUpdate, &rows, query, campaignID, ParticipationActive); err != nil {
+		return nil, err
+	}
+	return toParticipations(rows), nil
+}
+
+// Create inserts p inside tx and sets its version. It returns
+// ErrDuplicateParticipation when the user already joined the campaign, e.g.
+// through a join indexed from the chain that did not take the campaign lock.
+func (r *ParticipationRepository) Create(ctx context.Context, tx *sqlx.Tx, p *models.Participation) error {
+	query := `
+		INSERT INTO participations (
+			id, campaign_id, user_id, wallet_address, deposit_amount,
+			expected_rebate, status, tx_hash, metadata
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		RETURNING version`
+
+	err := tx.QueryRowxContext(
+		ctx,
+		query,
+		p.ID,
+		p.CampaignID,
+		p.UserID,
+		strings.ToLower(p.WalletAddress),
+		p.DepositAmount,
+		p.ExpectedRebate,
+		p.Status,
+		p.TxHash,
+		p.Metadata,
+	).Scan(&p.Version)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateParticipation
+	}
+	return err
+}
+
+// UpdateMetadata shallow-merges patch into the participation metadata and
+// returns the result inside tx. Keys set to null are removed.
+func (r *ParticipationRepository) UpdateMetadata(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	return updateMetadata(ctx, tx, "participations", id, patch)
+}
+
+// SetCancelPending records the amount of a requested cancel inside tx and
+// sets p.Version to the version the row ends up with
+func (r *ParticipationRepository) SetCancelPending(ctx context.Context, tx *sqlx.Tx, p *models.Participation) error {
+	query := `UPDATE participations SET cancel_pending = $2, updated_at = NOW() WHERE id = $1 RETURNING version`
+	return tx.QueryRowxContext(ctx, query, p.ID, p.CancelPending).Scan(&p.Version)
+}
+
+// UpdateCancellation writes the result of a cancel inside tx: the remaining
+// deposit and rebate, the pending and penalty amounts, the status and the
+// cancel transaction. It sets p.Version to the version the row ends up with.
+func (r *ParticipationRepository) UpdateCancellation(ctx context.Context, tx *sqlx.Tx, p *models.Participation) error {
+	query := `
+		UPDATE participations
+		SET deposit_amount = $2, expected_rebate = $3, cancel_pending = $4,
+		    cancel_penalty = $5, status = $6, cancel_tx_hash = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING version`
+
+	return tx.QueryRowxContext(
+		ctx, query, p.ID, p.DepositAmount, p.ExpectedRebate, p.CancelPending,
+		p.CancelPenalty, p.Status, p.CancelTxHash,
+	).Scan(&p.Version)
+}
+
+// MarkSettled records the final rebate of a participation inside tx
+func (r *ParticipationRepository) MarkSettled(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, rebate *big.Int) error {
+	query := `
+		UPDATE participations
+		SET status = $2, actual_rebate = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, ParticipationSettled, models.NewBigInt(rebate))
+	return err
+}
+
+// RecordRefund stores a dispute refund of a participation inside tx: its
+// status and, for on-chain refunds, the refund transaction
+func (r *ParticipationRepository) RecordRefund(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status string, txHash *string) error {
+	query := `
+		UPDATE participations
+		SET status = $2, refund_tx_hash = COALESCE($3, refund_tx_hash), updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, status, txHash)
+	return err
+}
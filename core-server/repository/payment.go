@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const paymentColumns = `
+	id, payment_id, campaign_id, user_id, participation_id, amount, currency,
+	mode, status, transaction_hash, provider_response, created_at,
+	completed_at, failed_at, refunded_at, metadata`
+
+type paymentRow struct {
+	ID               uuid.UUID            `db:"id"`
+	PaymentID        string               `db:"payment_id"`
+	CampaignID       *uuid.UUID           `db:"campaign_id"`
+	UserID           *uuid.UUID           `db:"user_id"`
+	ParticipationID  *uuid.UUID           `db:"participation_id"`
+	Amount           models.BigInt        `db:"amount"`
+	Currency         models.Currency      `db:"currency"`
+	Mode             models.PaymentMode   `db:"mode"`
+	Status           models.PaymentStatus `db:"status"`
+	TransactionHash  *string              `db:"transaction_hash"`
+	ProviderResponse []byte               `db:"provider_response"`
+	CreatedAt        time.Time            `db:"created_at"`
+	CompletedAt      *time.Time           `db:"completed_at"`
+	FailedAt         *time.Time           `db:"failed_at"`
+	RefundedAt       *time.Time           `db:"refunded_at"`
+	Metadata         models.JSONB         `db:"metadata"`
+}
+
+func (r paymentRow) toModel() *models.Payment {
+	p := &models.Payment{
+		ID:              r.ID,
+		PaymentID:       r.PaymentID,
+		CampaignID:      r.CampaignID,
+		UserID:          r.UserID,
+		ParticipationID: r.ParticipationID,
+		Amount:          r.Amount,
+		Currency:        r.Currency,
+		Mode:            r.Mode,
+		Status:          r.Status,
+		TransactionHash: r.TransactionHash,
+		CreatedAt:       r.CreatedAt,
+		CompletedAt:     r.CompletedAt,
+		FailedAt:        r.FailedAt,
+		RefundedAt:      r.RefundedAt,
+		Metadata:        r.Metadata,
+	}
+	if len(r.ProviderResponse) > 0 {
+		json.Unmarshal(r.ProviderResponse, &p.ProviderResponse)
+	}
+	return p
+}
+
+type PaymentRepository struct {
+	db *database.DB
+}
+
+func NewPaymentRepository(db *database.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (r *PaymentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	return r.findOne(ctx, `SELECT `+paymentColumns+` FROM payments WHERE id = $1`, id)
+}
+
+func (r *PaymentRepository) FindByPaymentID(ctx context.Context, paymentID string) (*models.Payment, error) {
+	return r.findOne(ctx, `SELECT `+paymentColumns+` FROM payments WHERE payment_id = $1`, paymentID)
+}
+
+func (r *PaymentRepository) findOne(ctx context.Context, query string, arg interface{}) (*models.Payment, error) {
+	var row paymentRow
+	err := r.db.GetContext(ctx, &row, query, arg)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// Create inserts a payment inside tx
+func (r *PaymentRepository) Create(ctx context.Context, tx *sqlx.Tx, p *models.Payment) error {
+	query := `
+		INSERT INTO payments (
+			id, payment_id, campaign_id, user_id, participation_id,
+			amount, currency, mode, status, transaction_hash, metadata
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		p.ID,
+		p.PaymentID,
+		p.CampaignID,
+		p.UserID,
+		p.ParticipationID,
+		p.Amount,
+		p.Currency,
+		p.Mode,
+		p.Status,
+		p.TransactionHash,
+		p.Metadata,
+	)
+	return err
+}
+
+// UpdateStatus sets the payment status and the matching completed/failed/
+// refunded timestamp inside tx
+func (r *PaymentRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status models.PaymentStatus, providerResponse map[string]interface{}) error {
+	var resp []byte
+	if providerResponse != nil {
+		var err error
+		if resp, err = json.Marshal(providerResponse); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE payments
+		SET status = $2,
+		    provider_response = COALESCE($3::jsonb, provider_response),
+		    completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE completed_at END,
+		    failed_at = CASE WHEN $2 = 'failed' THEN NOW() ELSE failed_at END,
+		    refunded_at = CASE WHEN $2 = 'refunded' THEN NOW() ELSE refunded_at END
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, status, resp)
+	return err
+}
+
+// FindRefundableForUpdate returns the latest completed payment of a
+// participation locked inside tx, or nil
+func (r *PaymentRepository) FindRefundableForUpdate(ctx context.Context, tx *sqlx.Tx, participationID uuid.UUID) (*models.Payment, error) {
+	var row paymentRow
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE participation_id = $1 AND status = $2
+		ORDER BY completed_at DESC NULLS LAST, created_at DESC
+		LIMIT 1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForNoKeyUpdate, &row, query, participationID, models.PaymentCompleted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+// RequestRefund notes in the payment's metadata, inside tx, that amount is
+// to be refunded for a dispute; the provider's refunded webhook completes it
+func (r *PaymentRepository) RequestRefund(ctx context.Context, tx *sqlx.Tx, id, disputeID uuid.UUID, amount models.BigInt, at time.Time) error {
+	query := `
+		UPDATE payments
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(
+			'refund_dispute_id', $2::text,
+			'refund_requested_amount', $3::text,
+			'refund_requested_at', $4::timestamptz
+		)
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, id, disputeID, amount.String(), at)
+	return err
+}
+
+// LogWebhook stores a received webhook; it returns false when the event was
+// already recorded
+func (r *PaymentRepository) LogWebhook(ctx context.Context, eventID, eventType string, payload []byte, signature *string) (bool, error) {
+	query := `
+		INSERT INTO webhook_logs (event_id, event_type, payload, signature)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id) DO NOTHING`
+
+	res, err := r.db.ExecContext(ctx, query, eventID, eventType, payload, signature)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkWebhookProcessed records the outcome of processing a webhook
+func (r *PaymentRepository) MarkWebhookProcessed(ctx context.Context, eventID string, procErr error) error {
+	var errMsg *string
+	if procErr != nil {
+		msg := procErr.Error()
+		errMsg = &msg
+	}
+
+	query := `
+		UPDATE webhook_logs
+		SET processed = $2, error_message = $3, processed_at = NOW(),
+		    retry_count = retry_count + CASE WHEN $2 THEN 0 ELSE 1 END
+		WHERE event_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, eventID, procErr == nil, errMsg)
+	return err
+}
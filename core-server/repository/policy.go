@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+)
+
+const policyColumns = `merchant_tier, max_r_max_bps, min_lock_hours, min_fee_bps, max_fee_bps, updated_at`
+
+// PolicyRepository stores the campaign policy of each merchant tier
+type PolicyRepository struct {
+	db *database.DB
+}
+
+func NewPolicyRepository(db *database.DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+// List returns every policy by tier
+func (r *PolicyRepository) List(ctx context.Context) ([]*models.CampaignPolicy, error) {
+	policies := []*models.CampaignPolicy{}
+	query := `SELECT ` + policyColumns + ` FROM campaign_policies ORDER BY merchant_tier`
+
+	if err := r.db.SelectContext(ctx, &policies, query); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// FindByTier returns a tier's policy, or nil
+func (r *PolicyRepository) FindByTier(ctx context.Context, tier int) (*models.CampaignPolicy, error) {
+	var p models.CampaignPolicy
+	err := r.db.GetContext(ctx, &p, `SELECT `+policyColumns+` FROM campaign_policies WHERE merchant_tier = $1`, tier)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindForMerchant returns the policy of the merchant's tier, or nil
+func (r *PolicyRepository) FindForMerchant(ctx context.Context, merchantID uuid.UUID) (*models.CampaignPolicy, error) {
+	var p models.CampaignPolicy
+	query := `
+		SELECT ` + policyColumns + `
+		FROM campaign_policies
+		WHERE merchant_tier = (SELECT kyc_tier FROM users WHERE id = $1)`
+
+	err := r.db.GetContext(ctx, &p, query, merchantID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindForUpdate loads a tier's policy inside tx and locks the row until the
+// transaction ends, or returns nil
+func (r *PolicyRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, tier int) (*models.CampaignPolicy, error) {
+	var p models.CampaignPolicy
+	query := `SELECT ` + policyColumns + ` FROM campaign_policies WHERE merchant_tier = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &p, query, tier)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Upsert writes a tier's policy inside tx, replacing any it had
+func (r *PolicyRepository) Upsert(ctx context.Context, tx *sqlx.Tx, p *models.CampaignPolicy) error {
+	query := `
+		INSERT INTO campaign_policies (` + policyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (merchant_tier) DO UPDATE
+		SET max_r_max_bps = EXCLUDED.max_r_max_bps, min_lock_hours = EXCLUDED.min_lock_hours,
+		    min_fee_bps = EXCLUDED.min_fee_bps, max_fee_bps = EXCLUDED.max_fee_bps,
+		    updated_at = EXCLUDED.updated_at`
+
+	_, err := tx.ExecContext(ctx, query,
+		p.MerchantTier, p.MaxRMaxBps, p.MinLockHours, p.MinFeeBps, p.MaxFeeBps, p.UpdatedAt,
+	)
+	return err
+}
+
+// Delete removes a tier's policy inside tx
+func (r *PolicyRepository) Delete(ctx context.Context, tx *sqlx.Tx, tier int) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM campaign_policies WHERE merchant_tier = $1`, tier)
+	return err
+}
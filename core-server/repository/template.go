@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"r2s/pkg/address"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+const templateColumns = `
+	id, merchant_id, name, title, description, image_url, image_thumbnail_url,
+	merchant_wallet, base_price, min_qty, discount_rate, save_floor_bps,
+	r_max_bps, cancel_lead_seconds, late_cancel_penalty_bps, category, tags,
+	metadata, source_campaign_id, created_at, updated_at`
+
+// ErrDuplicateTemplate is returned by Create and Update when the merchant
+// already has a template of that name
+var ErrDuplicateTemplate = errors.New("a campaign template with this name already exists")
+
+// TemplateRepository stores merchants' campaign templates
+type TemplateRepository struct {
+	db *database.DB
+}
+
+func NewTemplateRepository(db *database.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// FindByID returns a template, or nil
+func (r *TemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.CampaignTemplate, error) {
+	var t models.CampaignTemplate
+	err := r.db.GetContext(ctx, &t, `SELECT `+templateColumns+` FROM campaign_templates WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.MerchantWallet = address.Display(t.MerchantWallet)
+	return &t, nil
+}
+
+// FindForUpdate loads a template inside tx and locks the row until the
+// transaction ends, or returns nil
+func (r *TemplateRepository) FindForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.CampaignTemplate, error) {
+	var t models.CampaignTemplate
+	query := `SELECT ` + templateColumns + ` FROM campaign_templates WHERE id = $1`
+
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &t, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.MerchantWallet = address.Display(t.MerchantWallet)
+	return &t, nil
+}
+
+// List returns one page of templates, most recently updated first, and
+// their total. A nil merchantID lists every merchant's.
+func (r *TemplateRepository) List(ctx context.Context, merchantID *uuid.UUID, page pagination.Page) ([]*models.CampaignTemplate, int64, error) {
+	where := ` WHERE $1::uuid IS NULL OR merchant_id = $1`
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM campaign_templates`+where, merchantID); err != nil {
+		return nil, 0, err
+	}
+
+	templates := []*models.CampaignTemplate{}
+	query := `
+		SELECT ` + templateColumns + `
+		FROM campaign_templates` + where + `
+		ORDER BY updated_at DESC, id
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.SelectContext(ctx, &templates, query, merchantID, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	for _, t := range templates {
+		t.MerchantWallet = address.Display(t.MerchantWallet)
+	}
+	return templates, total, nil
+}
+
+// Create inserts a template inside tx
+func (r *TemplateRepository) Create(ctx context.Context, tx *sqlx.Tx, t *models.CampaignTemplate) error {
+	query := `
+		INSERT INTO campaign_templates (` + templateColumns + `)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21
+		)`
+
+	_, err := tx.ExecContext(ctx, query,
+		t.ID, t.MerchantID, t.Name, t.Title, t.Description, t.ImageURL, t.ImageThumbnailURL,
+		strings.ToLower(t.MerchantWallet), t.BasePrice, t.MinQty, t.DiscountRate, t.SaveFloorBps,
+		t.RMaxBps, t.CancelLeadSeconds, t.LateCancelPenaltyBps, t.Category, tags(t.Tags),
+		t.Metadata, t.SourceCampaignID, t.CreatedAt, t.UpdatedAt,
+	)
+	return duplicateTemplate(err)
+}
+
+// Update writes every editable field of a template inside tx
+func (r *TemplateRepository) Update(ctx context.Context, tx *sqlx.Tx, t *models.CampaignTemplate) error {
+	query := `
+		UPDATE campaign_templates
+		SET name = $2, title = $3, description = $4, image_url = $5,
+		    image_thumbnail_url = $6, merchant_wallet = $7, base_price = $8,
+		    min_qty = $9, discount_rate = $10, save_floor_bps = $11,
+		    r_max_bps = $12, cancel_lead_seconds = $13,
+		    late_cancel_penalty_bps = $14, category = $15, tags = $16,
+		    metadata = $17, updated_at = $18
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query,
+		t.ID, t.Name, t.Title, t.Description, t.ImageURL,
+		t.ImageThumbnailURL, strings.ToLower(t.MerchantWallet), t.BasePrice,
+		t.MinQty, t.DiscountRate, t.SaveFloorBps,
+		t.RMaxBps, t.CancelLeadSeconds,
+		t.LateCancelPenaltyBps, t.Category, tags(t.Tags),
+		t.Metadata, t.UpdatedAt,
+	)
+	return duplicateTemplate(err)
+}
+
+// Delete removes a template inside tx
+func (r *TemplateRepository) Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM campaign_templates WHERE id = $1`, id)
+	return err
+}
+
+// duplicateTemplate maps the unique (merchant_id, name) violation to
+// ErrDuplicateTemplate
+func duplicateTemplate(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateTemplate
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/pkg/database"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+)
+
+// Vouchers are read with their campaign's title and merchant and the
+// participation's deposit
+const (
+	voucherColumns = `
+	v.id, v.participation_id, v.campaign_id, v.user_id, v.status, v.issued_at,
+	v.redeemed_at, v.redeemed_by, v.voided_at, c.title AS campaign_title,
+	c.merchant_id, p.deposit_amount`
+	voucherFrom = `
+	vouchers v
+	JOIN campaigns c ON c.id = v.campaign_id
+	JOIN participations p ON p.id = v.participation_id`
+)
+
+// lockVoucher locks only the voucher row of a joined select
+const lockVoucher = database.ForNoKeyUpdate + " OF v"
+
+// VoucherRepository stores redemption vouchers by the hash of their code
+type VoucherRepository struct {
+	db *database.DB
+}
+
+func NewVoucherRepository(db *database.DB) *VoucherRepository {
+	return &VoucherRepository{db: db}
+}
+
+// Create issues v with the hash of its code inside tx. A participation
+// already holding a voucher keeps it.
+func (r *VoucherRepository) Create(ctx context.Context, tx *sqlx.Tx, v *models.Voucher, codeHash string) error {
+	query := `
+		INSERT INTO vouchers (id, participation_id, campaign_id, user_id, code_hash, status, issued_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (participation_id) DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query,
+		v.ID, v.ParticipationID, v.CampaignID, v.UserID, codeHash, v.Status, v.IssuedAt,
+	)
+	return err
+}
+
+// FindByID returns a voucher, or nil
+func (r *VoucherRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Voucher, error) {
+	var v models.Voucher
+	err := r.db.GetContext(ctx, &v, `SELECT `+voucherColumns+` FROM `+voucherFrom+` WHERE v.id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindByCodeHash returns the voucher of a code, or nil
+func (r *VoucherRepository) FindByCodeHash(ctx context.Context, codeHash string) (*models.Voucher, error) {
+	var v models.Voucher
+	err := r.db.GetContext(ctx, &v, `SELECT `+voucherColumns+` FROM `+voucherFrom+` WHERE v.code_hash = $1`, codeHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindByCodeHashForUpdate returns the voucher of a code locked inside tx,
+// or nil
+func (r *VoucherRepository) FindByCodeHashForUpdate(ctx context.Context, tx *sqlx.Tx, codeHash string) (*models.Voucher, error) {
+	var v models.Voucher
+	query := `SELECT ` + voucherColumns + ` FROM ` + voucherFrom + ` WHERE v.code_hash = $1`
+
+	err := database.GetForUpdate(ctx, tx, lockVoucher, &v, query, codeHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// FindByUser returns one page of the user's vouchers, newest first, and
+// their total
+func (r *VoucherRepository) FindByUser(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Voucher, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM vouchers WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, err
+	}
+
+	vouchers := []*models.Voucher{}
+	query := `
+		SELECT ` + voucherColumns + `
+		FROM ` + voucherFrom + `
+		WHERE v.user_id = $1
+		ORDER BY v.issued_at DESC, v.id
+		LIMIT $2 OFFSET $3`
+
+	if err := r.db.SelectContext(ctx, &vouchers, query, userID, page.Limit, page.Offset); err != nil {
+		return nil, 0, err
+	}
+	return vouchers, total, nil
+}
+
+// Redeem marks an issued voucher redeemed inside tx and reports whether it
+// was still issued
+func (r *VoucherRepository) Redeem(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, by *uuid.UUID, at time.Time) (bool, error) {
+	query := `
+		UPDATE vouchers
+		SET status = $2, redeemed_at = $3, redeemed_by = $4, updated_at = NOW()
+		WHERE id = $1 AND status = $5`
+
+	res, err := tx.ExecContext(ctx, query, id, models.VoucherRedeemed, at, by, models.VoucherIssued)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Void voids the participation's voucher inside tx unless it was already
+// redeemed
+func (r *VoucherRepository) Void(ctx context.Context, tx *sqlx.Tx, participationID uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE vouchers
+		SET status = $2, voided_at = $3, updated_at = NOW()
+		WHERE participation_id = $1 AND status = $4`
+
+	_, err := tx.ExecContext(ctx, query, participationID, models.VoucherVoid, at, models.VoucherIssued)
+	return err
+}
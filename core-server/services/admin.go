@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/models"
+)
+
+// MaxBulkItems caps the ids accepted by one bulk action
+const MaxBulkItems = 100
+
+var (
+	ErrUserNotFound        = apperrors.Catalog(apperrors.ReasonUserNotFound)
+	ErrCannotSuspendAdmin  = apperrors.Catalog(apperrors.ReasonCannotSuspendAdmin)
+	ErrCannotChangeOwnRole = apperrors.Catalog(apperrors.ReasonCannotChangeOwnRole)
+	ErrUserNotSuspended    = apperrors.Catalog(apperrors.ReasonUserNotSuspended)
+	ErrCampaignNotPaused   = apperrors.Catalog(apperrors.ReasonCampaignNotPaused)
+	ErrCampaignNotPausable = apperrors.Catalog(apperrors.ReasonCampaignNotPausable)
+)
+
+// Overview is the admin dashboard summary
+type Overview struct {
+	Users     map[string]int64 `json:"users"`
+	Campaigns map[string]int64 `json:"campaigns"`
+	Payments  map[string]int64 `json:"payments"`
+}
+
+// BulkResult is the outcome of a bulk action for one id; one failing id
+// does not stop the others
+type BulkResult struct {
+	ID      uuid.UUID `json:"id"`
+	Success bool      `json:"success"`
+	Status  string    `json:"status,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type AdminService struct {
+	db           *database.DB
+	adminRepo    *repository.AdminRepository
+	campaignRepo *repository.CampaignRepository
+	audit        *audit.Store
+	campaigns    *CampaignStateMachine
+}
+
+func NewAdminService(db *database.DB, clk clock.Clock) *AdminService {
+	return &AdminService{
+		db:           db,
+		adminRepo:    repository.NewAdminRepository(db),
+		campaignRepo: repository.NewCampaignRepository(db),
+		audit:        audit.NewStore(db, clk),
+		campaigns:    NewCampaignStateMachine(db, clk),
+	}
+}
+
+// Overview counts users, campaigns and payments by status
+func (s *AdminService) Overview(ctx context.Context) (*Overview, error) {
+	var o Overview
+	var err error
+	if o.Users, err = s.adminRepo.CountByStatus(ctx, "users"); err != nil {
+		return nil, fmt.Errorf("failed to count users: %w", err)
+	}
+	if o.Campaigns, err = s.adminRepo.CountByStatus(ctx, "campaigns"); err != nil {
+		return nil, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+	if o.Payments, err = s.adminRepo.CountByStatus(ctx, "payments"); err != nil {
+		return nil, fmt.Errorf("failed to count payments: %w", err)
+	}
+	return &o, nil
+}
+
+// ListUsers searches users
+func (s *AdminService) ListUsers(ctx context.Context, f repository.UserFilter) ([]*models.User, int64, error) {
+	users, total, err := s.adminRepo.ListUsers(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
+
+// ListMerchants lists merchants by their campaigns
+func (s *AdminService) ListMerchants(ctx context.Context, query string, limit, offset int) ([]repository.MerchantSummary, int64, error) {
+	merchants, total, err := s.adminRepo.ListMerchants(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list merchants: %w", err)
+	}
+	return merchants, total, nil
+}
+
+// ListCampaigns searches campaigns
+func (s *AdminService) ListCampaigns(ctx context.Context, f repository.AdminCampaignFilter) ([]*models.Campaign, int64, error) {
+	campaigns, total, err := s.adminRepo.ListCampaigns(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	return campaigns, total, nil
+}
+
+// ListPayments searches payments
+func (s *AdminService) ListPayments(ctx context.Context, f repository.PaymentFilter) ([]*models.Payment, int64, error) {
+	payments, total, err := s.adminRepo.ListPayments(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list payments: %w", err)
+	}
+	return payments, total, nil
+}
+
+// SuspendUsers blocks the users from signing in and ends their sessions
+func (s *AdminService) SuspendUsers(ctx context.Context, ids []uuid.UUID, reason string) []BulkResult {
+	return s.bulk(ctx, ids, func(tx *sqlx.Tx, id uuid.UUID) (string, error) {
+		user, err := s.adminRepo.FindUserForUpdate(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if user == nil {
+			return "", ErrUserNotFound
+		}
+		if user.Role == models.RoleAdmin {
+			return "", ErrCannotSuspendAdmin
+		}
+		if user.Status == models.UserSuspended {
+			return user.Status, nil
+		}
+
+		if err := s.adminRepo.SetUserStatus(ctx, tx, id, models.UserSuspended); err != nil {
+			return "", err
+		}
+		if err := s.adminRepo.DeleteUserSessions(ctx, tx, id); err != nil {
+			return "", err
+		}
+		return models.UserSuspended, s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionUserSuspend,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   id.String(),
+			Before:       map[string]interface{}{"status": user.Status},
+			After:        map[string]interface{}{"status": models.UserSuspended, "reason": reason},
+		})
+	})
+}
+
+// ReinstateUsers lifts a suspension
+func (s *AdminService) ReinstateUsers(ctx context.Context, ids []uuid.UUID, reason string) []BulkResult {
+	return s.bulk(ctx, ids, func(tx *sqlx.Tx, id uuid.UUID) (string, error) {
+		user, err := s.adminRepo.FindUserForUpdate(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if user == nil {
+			return "", ErrUserNotFound
+		}
+		if user.Status != models.UserSuspended {
+			return "", ErrUserNotSuspended
+		}
+
+		if err := s.adminRepo.SetUserStatus(ctx, tx, id, models.UserActive); err != nil {
+			return "", err
+		}
+		return models.UserActive, s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionUserReinstate,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   id.String(),
+			Before:       map[string]interface{}{"status": user.Status},
+			After:        map[string]interface{}{"status": models.UserActive, "reason": reason},
+		})
+	})
+}
+
+// SetUserRoles changes the users' role and ends their sessions, so a
+// revoked role stops working at once and a granted one applies at the next
+// sign-in
+func (s *AdminService) SetUserRoles(ctx context.Context, ids []uuid.UUID, role, reason string) []BulkResult {
+	actorID := audit.ActorFrom(ctx).ID
+
+	return s.bulk(ctx, ids, func(tx *sqlx.Tx, id uuid.UUID) (string, error) {
+		if id.String() == actorID {
+			return "", ErrCannotChangeOwnRole
+		}
+		user, err := s.adminRepo.FindUserForUpdate(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if user == nil {
+			return "", ErrUserNotFound
+		}
+		if user.Role == role {
+			return role, nil
+		}
+
+		if err := s.adminRepo.SetUserRole(ctx, tx, id, role); err != nil {
+			return "", err
+		}
+		if err := s.adminRepo.DeleteUserSessions(ctx, tx, id); err != nil {
+			return "", err
+		}
+		action := audit.ActionRoleGrant
+		if role == models.RoleUser {
+			action = audit.ActionRoleRevoke
+		}
+		return role, s.audit.Record(ctx, tx, audit.Change{
+			Action:       action,
+			ResourceType: audit.ResourceUser,
+			ResourceID:   id.String(),
+			Before:       map[string]interface{}{"role": user.Role},
+			After:        map[string]interface{}{"role": role, "reason": reason},
+		})
+	})
+}
+
+// PauseCampaigns stops new participations in recruiting or reached campaigns
+func (s *AdminService) PauseCampaigns(ctx context.Context, ids []uuid.UUID, reason string) []BulkResult {
+	return s.bulk(ctx, ids, func(tx *sqlx.Tx, id uuid.UUID) (string, error) {
+		campaign, err := s.lockCampaign(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if campaign.Status == models.StatusPaused {
+			return string(campaign.Status), nil
+		}
+		if !s.campaigns.Can(campaign.Status, models.StatusPaused) {
+			return "", ErrCampaignNotPausable
+		}
+		return s.setCampaignStatus(ctx, tx, campaign, models.StatusPaused, audit.ActionCampaignPause, reason)
+	})
+}
+
+// ResumeCampaigns reopens paused campaigns as reached or recruiting,
+// depending on whether they hold min_qty
+func (s *AdminService) ResumeCampaigns(ctx context.Context, ids []uuid.UUID, reason string) []BulkResult {
+	return s.bulk(ctx, ids, func(tx *sqlx.Tx, id uuid.UUID) (string, error) {
+		campaign, err := s.lockCampaign(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if campaign.Status != models.StatusPaused {
+			return "", ErrCampaignNotPaused
+		}
+		to := models.StatusRecruiting
+		if campaign.CurrentQty >= campaign.MinQty {
+			to = models.StatusReached
+		}
+		return s.setCampaignStatus(ctx, tx, campaign, to, audit.ActionCampaignResume, reason)
+	})
+}
+
+// lockCampaign takes the campaign advisory lock, so the status change is
+// serialised against joins and settlement, and loads the campaign
+func (s *AdminService) lockCampaign(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*models.Campaign, error) {
+	if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, id.String())); err != nil {
+		return nil, err
+	}
+	campaign, err := s.campaignRepo.FindByIDForUpdate(ctx, tx, id, database.ForNoKeyUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+func (s *AdminService) setCampaignStatus(ctx context.Context, tx *sqlx.Tx, campaign *models.Campaign, to models.CampaignStatus, action, reason string) (string, error) {
+	from := campaign.Status
+	if err := s.campaigns.Transition(ctx, tx, campaign, to, reason); err != nil {
+		return "", err
+	}
+	if err := s.campaignRepo.UpdateStatus(ctx, tx, campaign.ID, to); err != nil {
+		return "", err
+	}
+	return string(to), s.audit.Record(ctx, tx, audit.Change{
+		Action:       action,
+		ResourceType: audit.ResourceCampaign,
+		ResourceID:   campaign.ID.String(),
+		Before:       map[string]interface{}{"status": from},
+		After:        map[string]interface{}{"status": to, "reason": reason},
+	})
+}
+
+// bulk runs fn for each id in its own transaction and collects the results
+func (s *AdminService) bulk(ctx context.Context, ids []uuid.UUID, fn func(tx *sqlx.Tx, id uuid.UUID) (string, error)) []BulkResult {
+	results := make([]BulkResult, len(ids))
+	for i, id := range ids {
+		var status string
+		err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+			var err error
+			status, err = fn(tx, id)
+			return err
+		})
+
+		results[i] = BulkResult{ID: id, Success: err == nil, Status: status}
+		if err != nil {
+			results[i].Status = ""
+			results[i].Error = apperrors.MessageOf(err)
+			if apperrors.CodeOf(err) == apperrors.CodeInternal {
+				logger.FromContext(ctx).Error("bulk action failed", "id", id, "error", err)
+			}
+		}
+	}
+	return results
+}
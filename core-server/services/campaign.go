@@ -0,0 +1,651 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/address"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/metrics"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/outbox"
+	"r2s/pkg/policy"
+	"r2s/pkg/rbac"
+	"r2s/pkg/statemachine"
+	"r2s/pkg/validate"
+)
+
+var (
+	ErrCampaignNotFound   = apperrors.Catalog(apperrors.ReasonCampaignNotFound)
+	ErrCampaignNotSettled = apperrors.Catalog(apperrors.ReasonCampaignNotSettled)
+	ErrSettlementTooEarly = apperrors.Catalog(apperrors.ReasonSettlementTooEarly)
+	ErrNotCampaignOwner   = apperrors.Catalog(apperrors.ReasonNotCampaignOwner)
+	// ErrStaleVersion means the campaign or participation changed since the
+	// caller read the version it sent
+	ErrStaleVersion = apperrors.Catalog(apperrors.ReasonStaleVersion)
+)
+
+type CampaignService struct {
+	db                *database.DB
+	redis             *database.RedisClient
+	campaignRepo      *repository.CampaignRepository
+	merchantRepo      *repository.MerchantRepository
+	categoryRepo      *repository.CategoryRepository
+	mediaRepo         *repository.MediaRepository
+	participationRepo *repository.ParticipationRepository
+	fulfillmentRepo   *repository.FulfillmentRepository
+	disputeRepo       *repository.DisputeRepository
+	templateRepo      *repository.TemplateRepository
+	policyRepo        *repository.PolicyRepository
+	clock             clock.Clock
+	audit             *audit.Store
+	events            *outbox.Store
+	notifications     *NotificationService
+	metadataPublisher *MetadataService
+	vouchers          *VoucherService
+	campaigns         *CampaignStateMachine
+	participations    *statemachine.Machine[string]
+}
+
+type CreateCampaignInput struct {
+	Title          string
+	Description    *string
+	MerchantID     *uuid.UUID
+	MerchantWallet string
+	BasePrice      *big.Int
+	MinQty         int
+	DiscountRate   int
+	SaveFloorBps   int
+	RMaxBps        int
+	StartTime      time.Time
+	EndTime        time.Time
+	Metadata       models.JSONB
+	// CancelDeadline and LateCancelPenaltyBps set the cancellation window;
+	// see models.Campaign
+	CancelDeadline       *time.Time
+	LateCancelPenaltyBps *int
+	// ImageID is a completed campaign_image upload; see MediaService
+	ImageID *uuid.UUID
+	// Category is a category slug; Tags are normalized by normalizeTags
+	Category *string
+	Tags     []string
+
+	// imageURL and imageThumbnailURL carry the imagery of a clone over;
+	// ImageID replaces them
+	imageURL          *string
+	imageThumbnailURL *string
+	// source is the campaign or template a clone is made from
+	source *cloneSource
+}
+
+type UpdateCampaignInput struct {
+	Title       *string
+	Description *string
+	ImageID     *uuid.UUID
+	StartTime   *time.Time
+	EndTime     *time.Time
+	// Category moves the campaign to a category, or out of its category
+	// when empty. Tags replace the campaign's tags unless nil.
+	Category *string
+	Tags     []string
+	// CancelDeadline and LateCancelPenaltyBps may only change in draft
+	CancelDeadline       *time.Time
+	LateCancelPenaltyBps *int
+	// Status requests a status change, checked by CampaignStateMachine.Request
+	Status       *models.CampaignStatus
+	StatusReason string
+	// Version is the version the caller read; the update fails with
+	// ErrStaleVersion when the campaign changed since
+	Version int64
+}
+
+// SettlementResult summarises a completed settlement
+type SettlementResult struct {
+	CampaignID     uuid.UUID     `json:"campaignId"`
+	RebateBps      int           `json:"rebateBps"`
+	Participations int           `json:"participations"`
+	TotalDeposit   models.BigInt `json:"totalDeposit"`
+	TotalRebate    models.BigInt `json:"totalRebate"`
+	SettledAt      time.Time     `json:"settledAt"`
+}
+
+func NewCampaignService(db *database.DB, redis *database.RedisClient, clk clock.Clock, notifications *NotificationService, metadataPublisher *MetadataService, vouchers *VoucherService) *CampaignService {
+	return &CampaignService{
+		db:                db,
+		redis:             redis,
+		campaignRepo:      repository.NewCampaignRepository(db),
+		merchantRepo:      repository.NewMerchantRepository(db),
+		categoryRepo:      repository.NewCategoryRepository(db),
+		mediaRepo:         repository.NewMediaRepository(db),
+		participationRepo: repository.NewParticipationRepository(db),
+		fulfillmentRepo:   repository.NewFulfillmentRepository(db),
+		disputeRepo:       repository.NewDisputeRepository(db),
+		templateRepo:      repository.NewTemplateRepository(db),
+		policyRepo:        repository.NewPolicyRepository(db),
+		clock:             clock.OrSystem(clk),
+		audit:             audit.NewStore(db, clk),
+		events:            outbox.NewStore(db, clk),
+		notifications:     notifications,
+		metadataPublisher: metadataPublisher,
+		vouchers:          vouchers,
+		campaigns:         NewCampaignStateMachine(db, clk),
+		participations:    statemachine.NewParticipation().OnTransition(statemachine.LogHistory[string]()),
+	}
+}
+
+// ListCampaigns returns a page of campaigns, newest first, and the total
+// number matching the filter
+func (s *CampaignService) ListCampaigns(ctx context.Context, filter repository.CampaignFilter) ([]*models.Campaign, int64, error) {
+	total, err := s.campaignRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+	campaigns, err := s.campaignRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	return campaigns, total, nil
+}
+
+// GetCampaign returns a single campaign
+func (s *CampaignService) GetCampaign(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// CreateCampaign stores a new draft campaign. It goes on chain once it has
+// been reviewed and approved; see ReviewCampaign and RecordDeployment.
+func (s *CampaignService) CreateCampaign(ctx context.Context, in CreateCampaignInput) (*models.Campaign, error) {
+	return s.createCampaign(ctx, in)
+}
+
+// createCampaign checks in against the platform's bounds and the policy of
+// the merchant's tier and stores the draft. Clones and template launches
+// come through here too, so their copied terms are held to the bounds and
+// policies in force when they launch.
+func (s *CampaignService) createCampaign(ctx context.Context, in CreateCampaignInput) (*models.Campaign, error) {
+	if err := checkTerms(in); err != nil {
+		return nil, err
+	}
+	if err := validate.TimeWindow("startTime", in.StartTime, "endTime", in.EndTime); err != nil {
+		return nil, err
+	}
+	// Merchants always create campaigns of their own
+	if rbac.RoleFrom(ctx) == models.RoleMerchant {
+		merchantID, err := uuid.Parse(audit.ActorFrom(ctx).ID)
+		if err != nil {
+			return nil, ErrNotCampaignOwner
+		}
+		in.MerchantID = &merchantID
+	}
+	feeBps, err := s.merchantFeeBps(ctx, in.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPolicy(ctx, in.MerchantID, policy.Terms{
+		RMaxBps:        in.RMaxBps,
+		MerchantFeeBps: feeBps,
+		StartTime:      in.StartTime,
+		EndTime:        in.EndTime,
+	}); err != nil {
+		return nil, err
+	}
+
+	campaign := &models.Campaign{
+		ID:                uuid.New(),
+		Title:             in.Title,
+		Description:       in.Description,
+		ImageURL:          in.imageURL,
+		ImageThumbnailURL: in.imageThumbnailURL,
+		MerchantID:        in.MerchantID,
+		MerchantWallet:    address.Display(in.MerchantWallet),
+		BasePrice:         models.NewBigInt(in.BasePrice),
+		MinQty:            in.MinQty,
+		TargetAmount:      models.NewBigInt(money.New(in.BasePrice, money.USDT).Mul(int64(in.MinQty)).Units()),
+		CurrentAmount:     models.NewBigInt(new(big.Int)),
+		DiscountRate:      in.DiscountRate,
+		SaveFloorBps:      in.SaveFloorBps,
+		RMaxBps:           in.RMaxBps,
+		MerchantFeeBps:    feeBps,
+		OpsFeeBps:         100,
+		StartTime:         in.StartTime,
+		EndTime:           in.EndTime,
+		Status:            models.StatusDraft,
+		Metadata:          in.Metadata,
+
+		CancelDeadline:       in.CancelDeadline,
+		LateCancelPenaltyBps: in.LateCancelPenaltyBps,
+	}
+	if err := validateCancelTerms(campaign); err != nil {
+		return nil, err
+	}
+	if in.ImageID != nil {
+		campaign.ImageURL, campaign.ImageThumbnailURL, err = readyImage(ctx, s.mediaRepo, *in.ImageID, models.MediaCampaignImage)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.setCategory(ctx, campaign, in.Category, in.Tags); err != nil {
+		return nil, err
+	}
+
+	change := audit.Change{
+		Action:       audit.ActionCampaignCreate,
+		ResourceType: audit.ResourceCampaign,
+		ResourceID:   campaign.ID.String(),
+		After:        campaign,
+	}
+	if in.source != nil {
+		change.Action = audit.ActionCampaignClone
+		change.After = map[string]interface{}{"campaign": campaign, "source": in.source}
+	}
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := s.campaignRepo.Create(ctx, tx, campaign); err != nil {
+			return fmt.Errorf("failed to create campaign: %w", err)
+		}
+		if err := s.campaignEvent(ctx, tx, outbox.CampaignCreated, campaign.ID, campaign); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, campaign.ID)
+	return campaign, nil
+}
+
+// checkTerms checks the pricing, rebate and cancellation terms of a new
+// campaign against the platform's bounds
+func checkTerms(in CreateCampaignInput) error {
+	if err := validate.First(
+		validate.Required("title", in.Title),
+		validate.Address("merchantWallet", in.MerchantWallet),
+		validate.PositiveAmount("basePrice", in.BasePrice),
+		validate.Bps("discountRate", in.DiscountRate),
+		validate.BpsRange("saveFloorBps", in.SaveFloorBps, "rMaxBps", in.RMaxBps),
+		validate.Metadata("metadata", in.Metadata),
+	); err != nil {
+		return err
+	}
+	if in.MinQty <= 0 {
+		return apperrors.Catalog(apperrors.ReasonInvalidMinQuantity)
+	}
+	if in.LateCancelPenaltyBps != nil {
+		return validate.Bps("lateCancelPenaltyBps", *in.LateCancelPenaltyBps)
+	}
+	return nil
+}
+
+// setCategory applies a category and tags given by the caller, checking the
+// category exists. An empty category clears it; nil leaves either as is.
+func (s *CampaignService) setCategory(ctx context.Context, campaign *models.Campaign, category *string, tags []string) error {
+	if category != nil && *category == "" {
+		campaign.Category = nil
+	} else if category != nil {
+		if err := checkCategory(ctx, s.categoryRepo, *category); err != nil {
+			return err
+		}
+		campaign.Category = category
+	}
+	if tags != nil {
+		normalized, err := normalizeTags(tags)
+		if err != nil {
+			return err
+		}
+		campaign.Tags = normalized
+	}
+	return nil
+}
+
+// merchantFeeBps returns the fee agreed in the merchant's registration.
+// Registered merchants must be approved; merchants that predate
+// registration pay the default fee.
+func (s *CampaignService) merchantFeeBps(ctx context.Context, merchantID *uuid.UUID) (int, error) {
+	if merchantID == nil {
+		return models.DefaultMerchantFeeBps, nil
+	}
+	merchant, err := s.merchantRepo.FindByID(ctx, *merchantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load merchant: %w", err)
+	}
+	if merchant == nil {
+		return models.DefaultMerchantFeeBps, nil
+	}
+	if merchant.Status != models.MerchantApproved {
+		return 0, ErrMerchantNotApproved
+	}
+	return merchant.FeeBps, nil
+}
+
+// UpdateCampaign applies editable fields and any requested status change to
+// a campaign and records the change in the audit log
+func (s *CampaignService) UpdateCampaign(ctx context.Context, id uuid.UUID, in UpdateCampaignInput) (*models.Campaign, error) {
+	var campaign *models.Campaign
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		campaign, err = s.campaignRepo.FindByIDForUpdate(ctx, tx, id, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		if err := authorizeCampaign(ctx, campaign); err != nil {
+			return err
+		}
+		if campaign.Version != in.Version {
+			return ErrStaleVersion
+		}
+		before := *campaign
+
+		edits := in.Title != nil || in.Description != nil || in.ImageID != nil ||
+			in.StartTime != nil || in.EndTime != nil || in.Category != nil || in.Tags != nil ||
+			in.CancelDeadline != nil || in.LateCancelPenaltyBps != nil
+		// Ops approve exactly what they reviewed
+		if edits && inReview(campaign.Status) {
+			return ErrCampaignInReview
+		}
+
+		if in.Title != nil {
+			campaign.Title = *in.Title
+		}
+		if in.Description != nil {
+			campaign.Description = in.Description
+		}
+		if in.ImageID != nil {
+			campaign.ImageURL, campaign.ImageThumbnailURL, err = readyImage(ctx, s.mediaRepo, *in.ImageID, models.MediaCampaignImage)
+			if err != nil {
+				return err
+			}
+		}
+		if err := s.setCategory(ctx, campaign, in.Category, in.Tags); err != nil {
+			return err
+		}
+		if in.StartTime != nil {
+			campaign.StartTime = *in.StartTime
+		}
+		if in.EndTime != nil {
+			campaign.EndTime = *in.EndTime
+		}
+		if in.CancelDeadline != nil || in.LateCancelPenaltyBps != nil {
+			// Participants joined under the terms they saw
+			if campaign.Status != models.StatusDraft {
+				return ErrCancelTermsLocked
+			}
+			if in.CancelDeadline != nil {
+				campaign.CancelDeadline = in.CancelDeadline
+			}
+			if in.LateCancelPenaltyBps != nil {
+				campaign.LateCancelPenaltyBps = in.LateCancelPenaltyBps
+			}
+		}
+		if err := validate.First(
+			validate.TimeWindow("start time", campaign.StartTime, "end time", campaign.EndTime),
+			validateCancelTerms(campaign),
+		); err != nil {
+			return err
+		}
+		// A new window must still satisfy the merchant's policy
+		if in.StartTime != nil || in.EndTime != nil {
+			if err := s.checkPolicy(ctx, campaign.MerchantID, policy.Terms{
+				RMaxBps:        campaign.RMaxBps,
+				MerchantFeeBps: campaign.MerchantFeeBps,
+				StartTime:      campaign.StartTime,
+				EndTime:        campaign.EndTime,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if in.Status != nil && *in.Status != campaign.Status {
+			if err := s.campaigns.Request(ctx, tx, campaign, *in.Status, in.StatusReason); err != nil {
+				return err
+			}
+			if err := s.campaignRepo.UpdateStatus(ctx, tx, id, campaign.Status); err != nil {
+				return fmt.Errorf("failed to update campaign status: %w", err)
+			}
+		}
+		// Last, so campaign.Version is the version both writes leave
+		if err := s.campaignRepo.Update(ctx, tx, campaign); err != nil {
+			return fmt.Errorf("failed to update campaign: %w", err)
+		}
+		if err := s.campaignEvent(ctx, tx, outbox.CampaignUpdated, campaign.ID, campaign); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignUpdate,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       &before,
+			After:        campaign,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, id)
+	return campaign, nil
+}
+
+// CampaignHistory returns the status changes of a campaign, oldest first
+func (s *CampaignService) CampaignHistory(ctx context.Context, id uuid.UUID) ([]*models.CampaignTransition, error) {
+	campaign, err := s.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return s.campaigns.History(ctx, id)
+}
+
+// UpdateCampaignMetadata merges patch into a campaign's metadata; keys set to
+// null are removed
+func (s *CampaignService) UpdateCampaignMetadata(ctx context.Context, id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	var metadata models.JSONB
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		campaign, err := s.campaignRepo.FindByIDForUpdate(ctx, tx, id, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		if err := authorizeCampaign(ctx, campaign); err != nil {
+			return err
+		}
+
+		metadata, err = s.campaignRepo.UpdateMetadata(ctx, tx, id, patch)
+		if err != nil {
+			return fmt.Errorf("failed to update campaign metadata: %w", err)
+		}
+		before := campaign.Metadata
+		campaign.Metadata = metadata
+		if err := s.campaignEvent(ctx, tx, outbox.CampaignUpdated, campaign.ID, campaign); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignMetadata,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       before,
+			After:        metadata,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, id)
+	return metadata, nil
+}
+
+// campaignEvent appends an event of campaign id inside tx
+func (s *CampaignService) campaignEvent(ctx context.Context, tx *sqlx.Tx, eventType string, id uuid.UUID, payload interface{}) error {
+	return s.events.Append(ctx, tx, outbox.Event{
+		Type:          eventType,
+		AggregateType: outbox.AggregateCampaign,
+		AggregateID:   id.String(),
+		Payload:       payload,
+	})
+}
+
+// authorizeCampaign lets merchants change only their own campaigns; ops,
+// admins and internal services may change any
+func authorizeCampaign(ctx context.Context, c *models.Campaign) error {
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return nil
+	}
+	if c.MerchantID == nil || c.MerchantID.String() != audit.ActorFrom(ctx).ID {
+		return ErrNotCampaignOwner
+	}
+	return nil
+}
+
+// SettleCampaign finalises rebates for every active participation once
+// each is fulfilled and accepted and none is disputed, and issues each a
+// redemption voucher when vouchers are enabled. Rebates are paid on the
+// deposit less any dispute refunds. The campaign advisory lock serialises
+// it against participation changes, fulfillment marks and concurrent
+// settle calls; SERIALIZABLE guarantees the totals are computed from one
+// consistent snapshot.
+func (s *CampaignService) SettleCampaign(ctx context.Context, id uuid.UUID) (*SettlementResult, error) {
+	var result *SettlementResult
+	var settled *models.Campaign
+	var rebates []Rebate
+	var voucherUsers []uuid.UUID
+
+	err := s.db.TransactionWithRetryContext(ctx, database.DefaultRetryConfig, database.Serializable, func(tx *sqlx.Tx) error {
+		rebates = rebates[:0]
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, id.String())); err != nil {
+			return err
+		}
+
+		campaign, err := s.campaignRepo.FindByIDForUpdate(ctx, tx, id, database.ForUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		if !s.campaigns.Can(campaign.Status, models.StatusSettled) {
+			return ErrCampaignNotSettled
+		}
+
+		now := s.clock.Now()
+		if !now.After(campaign.EndTime) {
+			return ErrSettlementTooEarly
+		}
+		fulfillment, err := fulfillmentSummary(ctx, s.fulfillmentRepo, tx, campaign, now)
+		if err != nil {
+			return err
+		}
+		if !fulfillment.Settleable {
+			return ErrFulfillmentPending
+		}
+
+		participations, err := s.participationRepo.FindActiveByCampaignForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(participations))
+		for i, p := range participations {
+			ids[i] = p.ID
+		}
+		refunds, err := s.disputeRepo.RefundTotals(ctx, tx, ids, models.DisputeRefunding, models.DisputeRefunded)
+		if err != nil {
+			return fmt.Errorf("failed to load refunds: %w", err)
+		}
+
+		bps := rebateBps(campaign)
+		result = &SettlementResult{
+			CampaignID:     id,
+			RebateBps:      bps,
+			Participations: len(participations),
+			TotalDeposit:   models.NewBigInt(new(big.Int)),
+			TotalRebate:    models.NewBigInt(new(big.Int)),
+			SettledAt:      now,
+		}
+
+		for _, p := range participations {
+			if err := s.participations.Transition(ctx, p.ID.String(), p.Status, repository.ParticipationSettled); err != nil {
+				return err
+			}
+			deposit := money.New(p.DepositAmount.Int, money.USDT)
+			// Money refunded through a dispute earns no rebate
+			kept, err := deposit.Sub(money.New(refunds[p.ID], money.USDT))
+			if err != nil {
+				return err
+			}
+			rebate := kept.MulBps(bps).Units()
+			if err := s.participationRepo.MarkSettled(ctx, tx, p.ID, rebate); err != nil {
+				return fmt.Errorf("failed to settle participation %s: %w", p.ID, err)
+			}
+			result.TotalDeposit.Add(result.TotalDeposit.Int, deposit.Units())
+			result.TotalRebate.Add(result.TotalRebate.Int, rebate)
+			rebates = append(rebates, Rebate{UserID: p.UserID, Amount: rebate})
+		}
+		voucherUsers, err = s.vouchers.issue(ctx, tx, participations, now)
+		if err != nil {
+			return err
+		}
+
+		from := campaign.Status
+		if err := s.campaigns.Transition(ctx, tx, campaign, models.StatusSettled, ""); err != nil {
+			return err
+		}
+		if err := s.campaignRepo.MarkSettled(ctx, tx, id, now); err != nil {
+			return err
+		}
+		settled = campaign
+		if err := s.campaignEvent(ctx, tx, outbox.CampaignSettled, id, result); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignSettle,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       map[string]interface{}{"status": from},
+			After:        result,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.SettlementsCompleted.Inc()
+	metrics.AddRebates(money.New(result.TotalRebate.Int, money.USDT))
+	s.notifications.RebatesSettled(ctx, settled, rebates)
+	s.notifications.VouchersIssued(ctx, settled, voucherUsers)
+	return result, nil
+}
+
+// rebateBps is the achieved discount clamped to the campaign's guaranteed
+// floor and rebate cap
+func rebateBps(c *models.Campaign) int {
+	bps := c.DiscountRate
+	if bps < c.SaveFloorBps {
+		bps = c.SaveFloorBps
+	}
+	if bps > c.RMaxBps {
+		bps = c.RMaxBps
+	}
+	return bps
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/address"
+	"r2s/pkg/audit"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/outbox"
+	"r2s/pkg/rbac"
+)
+
+var (
+	ErrCampaignInReview    = apperrors.Catalog(apperrors.ReasonCampaignInReview)
+	ErrCampaignNotInReview = apperrors.Catalog(apperrors.ReasonCampaignNotInReview)
+	ErrCampaignNotApproved = apperrors.Catalog(apperrors.ReasonCampaignNotApproved)
+	ErrChainAddressTaken   = apperrors.Catalog(apperrors.ReasonChainAddressTaken)
+)
+
+// inReview reports whether a campaign's content is under or past review,
+// so that it has to go back to draft before it is edited
+func inReview(status models.CampaignStatus) bool {
+	switch status {
+	case models.StatusPendingReview, models.StatusApproved, models.StatusRejected:
+		return true
+	}
+	return false
+}
+
+// SubmitCampaign sends a draft to ops for review
+func (s *CampaignService) SubmitCampaign(ctx context.Context, id uuid.UUID, version int64) (*models.Campaign, error) {
+	var campaign *models.Campaign
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		campaign, err = s.lockCampaign(ctx, tx, id, version)
+		if err != nil {
+			return err
+		}
+		before := *campaign
+
+		if err := s.campaigns.Request(ctx, tx, campaign, models.StatusPendingReview, "submitted for review"); err != nil {
+			return err
+		}
+		if err := s.campaignRepo.UpdateStatus(ctx, tx, id, campaign.Status); err != nil {
+			return fmt.Errorf("failed to update campaign status: %w", err)
+		}
+		if err := s.campaignRepo.Update(ctx, tx, campaign); err != nil {
+			return fmt.Errorf("failed to update campaign: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignSubmit,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       &before,
+			After:        campaign,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, id)
+	return campaign, nil
+}
+
+// ReviewCampaign records an ops decision on a campaign awaiting review.
+// The comment is kept with the decision and becomes the reason of the
+// status change; rejections need one so the merchant knows what to fix.
+func (s *CampaignService) ReviewCampaign(ctx context.Context, id uuid.UUID, version int64, approve bool, comment string) (*models.Campaign, *models.CampaignReview, error) {
+	if !rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return nil, nil, apperrors.Forbidden("only ops can review campaigns")
+	}
+	if !approve && comment == "" {
+		return nil, nil, apperrors.InvalidArgument("comment is required when rejecting a campaign")
+	}
+
+	review := &models.CampaignReview{
+		ID:         uuid.New(),
+		CampaignID: id,
+		ReviewerID: audit.ActorFrom(ctx).ID,
+		Decision:   models.ReviewApproved,
+		CreatedAt:  s.clock.Now(),
+	}
+	to := models.StatusApproved
+	if !approve {
+		review.Decision = models.ReviewRejected
+		to = models.StatusRejected
+	}
+	if comment != "" {
+		review.Comment = &comment
+	}
+
+	var campaign *models.Campaign
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		campaign, err = s.lockCampaign(ctx, tx, id, version)
+		if err != nil {
+			return err
+		}
+		if campaign.Status != models.StatusPendingReview {
+			return ErrCampaignNotInReview
+		}
+		before := *campaign
+
+		if err := s.campaigns.Transition(ctx, tx, campaign, to, comment); err != nil {
+			return err
+		}
+		if err := s.campaignRepo.UpdateStatus(ctx, tx, id, campaign.Status); err != nil {
+			return fmt.Errorf("failed to update campaign status: %w", err)
+		}
+		if err := s.campaignRepo.Update(ctx, tx, campaign); err != nil {
+			return fmt.Errorf("failed to update campaign: %w", err)
+		}
+		if err := s.campaignRepo.CreateReview(ctx, tx, review); err != nil {
+			return fmt.Errorf("failed to record campaign review: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignReview,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       &before,
+			After:        campaign,
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, id)
+	s.notifications.CampaignReviewed(ctx, campaign, review)
+	return campaign, review, nil
+}
+
+// CampaignReviews returns the review decisions on a campaign, oldest first
+func (s *CampaignService) CampaignReviews(ctx context.Context, id uuid.UUID) ([]*models.CampaignReview, error) {
+	campaign, err := s.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	reviews, err := s.campaignRepo.FindReviews(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// DeploymentInput is the on-chain deployment of an approved campaign, built
+// with tx-helper's deploy-campaign transaction
+type DeploymentInput struct {
+	ChainAddress string
+	TxHash       string
+	BlockNumber  int64
+	Version      int64
+}
+
+// RecordDeployment stores where an approved campaign was deployed and opens
+// it for participation
+func (s *CampaignService) RecordDeployment(ctx context.Context, id uuid.UUID, in DeploymentInput) (*models.Campaign, error) {
+	var campaign *models.Campaign
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		campaign, err = s.lockCampaign(ctx, tx, id, in.Version)
+		if err != nil {
+			return err
+		}
+		if campaign.Status != models.StatusApproved {
+			return ErrCampaignNotApproved
+		}
+		before := *campaign
+
+		campaign.ChainAddress = address.Display(in.ChainAddress)
+		campaign.TxHash = &in.TxHash
+		campaign.BlockNumber = &in.BlockNumber
+		if err := s.campaigns.Transition(ctx, tx, campaign, models.StatusRecruiting, "deployed on chain"); err != nil {
+			return err
+		}
+		if err := s.campaignRepo.SetDeployment(ctx, tx, campaign); err != nil {
+			if errors.Is(err, repository.ErrDuplicateChainAddress) {
+				return ErrChainAddressTaken
+			}
+			return fmt.Errorf("failed to record campaign deployment: %w", err)
+		}
+		if err := s.campaignEvent(ctx, tx, outbox.CampaignUpdated, id, campaign); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignDeploy,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   id.String(),
+			Before:       &before,
+			After:        campaign,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.metadataPublisher.PublishAsync(ctx, id)
+	return campaign, nil
+}
+
+// lockCampaign loads a campaign for a status change inside tx, checking the
+// caller may change it and read the current version
+func (s *CampaignService) lockCampaign(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, version int64) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByIDForUpdate(ctx, tx, id, database.ForNoKeyUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	if err := authorizeCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	if campaign.Version != version {
+		return nil, ErrStaleVersion
+	}
+	return campaign, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/outbox"
+	"r2s/pkg/rbac"
+	"r2s/pkg/statemachine"
+)
+
+// merchantCampaignMoves are the status changes a merchant may request with
+// UpdateCampaign: submitting a draft for review, taking a campaign that is
+// not on chain yet back to draft to edit it or cancelling it, and starting
+// fulfillment once the campaign reached its minimum. Approved and rejected
+// come from ReviewCampaign, recruiting from RecordDeployment, reached and
+// recruiting otherwise follow the participation totals, settled comes from
+// SettleCampaign and paused from the admin API.
+var merchantCampaignMoves = statemachine.Table[models.CampaignStatus]{
+	models.StatusDraft:         {models.StatusPendingReview, models.StatusCancelled},
+	models.StatusPendingReview: {models.StatusDraft, models.StatusCancelled},
+	models.StatusRejected:      {models.StatusDraft, models.StatusCancelled},
+	models.StatusApproved:      {models.StatusDraft, models.StatusCancelled},
+	models.StatusReached:       {models.StatusFulfillment},
+}
+
+// opsCampaignMoves additionally let ops end campaigns that hold deposits
+var opsCampaignMoves = statemachine.Table[models.CampaignStatus]{
+	models.StatusDraft:         {models.StatusPendingReview, models.StatusCancelled},
+	models.StatusPendingReview: {models.StatusDraft, models.StatusCancelled},
+	models.StatusRejected:      {models.StatusDraft, models.StatusCancelled},
+	models.StatusApproved:      {models.StatusDraft, models.StatusCancelled},
+	models.StatusRecruiting:    {models.StatusFailed, models.StatusCancelled},
+	models.StatusReached:       {models.StatusFulfillment, models.StatusCancelled},
+	models.StatusFulfillment:   {models.StatusFailed},
+	models.StatusPaused:        {models.StatusCancelled},
+}
+
+// CampaignStateMachine moves campaigns along statemachine.CampaignTable and
+// records every move in campaign_status_history, with the actor in ctx and
+// a reason, and as a campaign.status_changed event, in the transaction that
+// writes the new status
+type CampaignStateMachine struct {
+	machine      *statemachine.Machine[models.CampaignStatus]
+	merchant     *statemachine.Machine[models.CampaignStatus]
+	ops          *statemachine.Machine[models.CampaignStatus]
+	campaignRepo *repository.CampaignRepository
+	events       *outbox.Store
+	clock        clock.Clock
+}
+
+func NewCampaignStateMachine(db *database.DB, clk clock.Clock) *CampaignStateMachine {
+	return &CampaignStateMachine{
+		machine:      statemachine.NewCampaign().OnTransition(statemachine.LogHistory[models.CampaignStatus]()),
+		merchant:     statemachine.New("campaign", merchantCampaignMoves),
+		ops:          statemachine.New("campaign", opsCampaignMoves),
+		campaignRepo: repository.NewCampaignRepository(db),
+		events:       outbox.NewStore(db, clk),
+		clock:        clock.OrSystem(clk),
+	}
+}
+
+// Can reports whether the lifecycle allows from -> to
+func (m *CampaignStateMachine) Can(from, to models.CampaignStatus) bool {
+	return m.machine.Can(from, to)
+}
+
+// Transition moves c to status to inside tx: it checks the lifecycle,
+// records the change and sets c.Status. The caller writes the status
+// (UpdateStatus, UpdateTotals or MarkSettled) in the same tx.
+func (m *CampaignStateMachine) Transition(ctx context.Context, tx *sqlx.Tx, c *models.Campaign, to models.CampaignStatus, reason string) error {
+	if !m.machine.Can(c.Status, to) {
+		return apperrors.Catalog(apperrors.ReasonCampaignTransition, c.Status, to)
+	}
+	if err := m.machine.Transition(ctx, c.ID.String(), c.Status, to); err != nil {
+		return err
+	}
+
+	actor := audit.ActorFrom(ctx)
+	t := &models.CampaignTransition{
+		ID:         uuid.New(),
+		CampaignID: c.ID,
+		FromStatus: c.Status,
+		ToStatus:   to,
+		ActorType:  actor.Type,
+		CreatedAt:  m.clock.Now(),
+	}
+	if actor.ID != "" {
+		t.ActorID = &actor.ID
+	}
+	if reason != "" {
+		t.Reason = &reason
+	}
+	if err := m.campaignRepo.RecordTransition(ctx, tx, t); err != nil {
+		return fmt.Errorf("failed to record campaign transition: %w", err)
+	}
+	if err := m.events.Append(ctx, tx, outbox.Event{
+		Type:          outbox.CampaignStatusChanged,
+		AggregateType: outbox.AggregateCampaign,
+		AggregateID:   c.ID.String(),
+		Payload:       t,
+	}); err != nil {
+		return err
+	}
+	c.Status = to
+	return nil
+}
+
+// Request applies a status change asked for through UpdateCampaign, which
+// may only make the moves the caller's role is allowed to request
+func (m *CampaignStateMachine) Request(ctx context.Context, tx *sqlx.Tx, c *models.Campaign, to models.CampaignStatus, reason string) error {
+	allowed := m.merchant
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		allowed = m.ops
+	}
+	if !allowed.Can(c.Status, to) {
+		return apperrors.Catalog(apperrors.ReasonCampaignTransition, c.Status, to)
+	}
+	return m.Transition(ctx, tx, c, to, reason)
+}
+
+// History returns the status changes of a campaign, oldest first
+func (m *CampaignStateMachine) History(ctx context.Context, id uuid.UUID) ([]*models.CampaignTransition, error) {
+	transitions, err := m.campaignRepo.FindTransitions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign history: %w", err)
+	}
+	return transitions, nil
+}
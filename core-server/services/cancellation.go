@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/metrics"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/outbox"
+	"r2s/pkg/rbac"
+	"r2s/pkg/validate"
+)
+
+var (
+	ErrCancelWindowClosed  = apperrors.Catalog(apperrors.ReasonCancelWindowClosed)
+	ErrInvalidCancelAmount = apperrors.Catalog(apperrors.ReasonInvalidCancelAmount)
+	ErrCancelTermsLocked   = apperrors.Catalog(apperrors.ReasonCancelTermsLocked)
+)
+
+// CancelQuote is what cancelling Amount of a deposit costs: Penalty is kept
+// and Refund goes back to the participant
+type CancelQuote struct {
+	Amount     models.BigInt `json:"amount"`
+	PenaltyBps int           `json:"penaltyBps"`
+	Penalty    models.BigInt `json:"penalty"`
+	Refund     models.BigInt `json:"refund"`
+	FreeUntil  time.Time     `json:"freeUntil"`
+}
+
+// CancelEvent is a requestCancel seen on chain, as reported by the indexer
+type CancelEvent struct {
+	CampaignAddress string
+	WalletAddress   string
+	Amount          *big.Int
+	TxHash          string
+	// At is the block time; zero means now
+	At time.Time
+}
+
+// cancelPenaltyBps applies the cancellation window of c at t. Cancels are
+// free until c.FreeCancelUntil while the campaign still recruits; later,
+// and once it is in fulfillment, they cost c.LateCancelPenaltyBps or are
+// refused when the campaign sets none.
+func cancelPenaltyBps(c *models.Campaign, t time.Time) (int, error) {
+	switch c.Status {
+	case models.StatusRecruiting, models.StatusReached, models.StatusPaused:
+		if t.Before(c.FreeCancelUntil()) {
+			return 0, nil
+		}
+	case models.StatusFulfillment:
+	default:
+		return 0, ErrNotCancellable
+	}
+	if c.LateCancelPenaltyBps == nil {
+		return 0, ErrCancelWindowClosed
+	}
+	return *c.LateCancelPenaltyBps, nil
+}
+
+// validateCancelTerms checks the cancellation terms of c against its
+// schedule. A deadline before StartTime is allowed and leaves no free window.
+func validateCancelTerms(c *models.Campaign) error {
+	if c.LateCancelPenaltyBps != nil {
+		if err := validate.Bps("late cancel penalty bps", *c.LateCancelPenaltyBps); err != nil {
+			return err
+		}
+	}
+	if c.CancelDeadline != nil && c.CancelDeadline.After(c.EndTime) {
+		return apperrors.InvalidArgument("cancel deadline must not be after end time")
+	}
+	return nil
+}
+
+// quoteCancel prices cancelling amount of p at bps; a nil amount cancels the
+// whole deposit. The amount must be a whole number of units.
+func quoteCancel(c *models.Campaign, p *models.Participation, amount *big.Int, bps int) (CancelQuote, error) {
+	if amount == nil {
+		amount = p.DepositAmount.Int
+	}
+	cancelled := money.New(amount, money.USDT)
+	deposit := money.New(p.DepositAmount.Int, money.USDT)
+	if !cancelled.IsPositive() {
+		return CancelQuote{}, ErrInvalidCancelAmount
+	}
+	if cmp, err := cancelled.Cmp(deposit); err != nil || cmp > 0 {
+		return CancelQuote{}, ErrInvalidCancelAmount
+	}
+	if _, rem, err := cancelled.QuoRem(money.New(c.BasePrice.Int, money.USDT)); err != nil || !rem.IsZero() {
+		return CancelQuote{}, ErrInvalidCancelAmount
+	}
+
+	penalty := cancelled.MulBps(bps)
+	refund, err := cancelled.Sub(penalty)
+	if err != nil {
+		return CancelQuote{}, err
+	}
+	return CancelQuote{
+		Amount:     models.NewBigInt(cancelled.Units()),
+		PenaltyBps: bps,
+		Penalty:    models.NewBigInt(penalty.Units()),
+		Refund:     models.NewBigInt(refund.Units()),
+		FreeUntil:  c.FreeCancelUntil(),
+	}, nil
+}
+
+// authorizeParticipation lets users act on their own participations only;
+// ops and services may act on any. Others get ErrParticipationNotFound so
+// participation IDs cannot be probed.
+func authorizeParticipation(ctx context.Context, p *models.Participation) error {
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return nil
+	}
+	if p.UserID.String() != audit.ActorFrom(ctx).ID {
+		return ErrParticipationNotFound
+	}
+	return nil
+}
+
+// RequestCancel records that the participant is about to cancel amount of
+// the deposit on chain (nil for all of it) and returns what it will cost
+// under the campaign's cancellation window. Only cancel_pending changes: the
+// deposit stays in the campaign until ReconcileCancel sees the cancel on
+// chain. A new request replaces the previous one.
+func (s *ParticipationService) RequestCancel(ctx context.Context, id uuid.UUID, version int64, amount *big.Int) (*models.Participation, *CancelQuote, error) {
+	var participation *models.Participation
+	var quote CancelQuote
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		participation, err = s.participationRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if participation == nil {
+			return ErrParticipationNotFound
+		}
+		if err := authorizeParticipation(ctx, participation); err != nil {
+			return err
+		}
+		if participation.Version != version {
+			return ErrStaleVersion
+		}
+		if !s.participations.Can(participation.Status, repository.ParticipationCancelled) {
+			return ErrNotCancellable
+		}
+
+		campaign, err := s.campaignRepo.FindByID(ctx, participation.CampaignID)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		bps, err := cancelPenaltyBps(campaign, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		if quote, err = quoteCancel(campaign, participation, amount, bps); err != nil {
+			return err
+		}
+
+		participation.CancelPending = quote.Amount
+		if err := s.participationRepo.SetCancelPending(ctx, tx, participation); err != nil {
+			return fmt.Errorf("failed to record cancel request: %w", err)
+		}
+		return s.participationEvent(ctx, tx, outbox.ParticipationCancelRequested, id, map[string]interface{}{
+			"participation": participation,
+			"quote":         quote,
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return participation, &quote, nil
+}
+
+// CancelParticipation cancels amount of a participation's deposit (nil for
+// all of it) off chain, under the campaign's cancellation window, and
+// releases it from the campaign totals. version is the participation
+// version the caller read; the cancel fails with ErrStaleVersion when it
+// changed since.
+func (s *ParticipationService) CancelParticipation(ctx context.Context, id uuid.UUID, version int64, amount *big.Int) (*models.Participation, error) {
+	existing, err := s.participationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrParticipationNotFound
+	}
+	if err := authorizeParticipation(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	var participation *models.Participation
+	var campaign *models.Campaign
+	var quote CancelQuote
+
+	err = s.db.TransactionWithRetryContext(ctx, database.DefaultRetryConfig, nil, func(tx *sqlx.Tx) error {
+		// Same lock order as CreateParticipation and SettleCampaign
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, existing.CampaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		campaign, err = s.campaignRepo.FindByIDForUpdate(ctx, tx, existing.CampaignID, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		bps, err := cancelPenaltyBps(campaign, s.clock.Now())
+		if err != nil {
+			return err
+		}
+
+		participation, err = s.participationRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if participation == nil {
+			return ErrParticipationNotFound
+		}
+		if participation.Version != version {
+			return ErrStaleVersion
+		}
+		quote, err = quoteCancel(campaign, participation, amount, bps)
+		if err != nil {
+			return err
+		}
+		return s.applyCancel(ctx, tx, campaign, participation, quote, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.ParticipationsCancelled.Inc()
+	s.notifications.CancellationConfirmed(ctx, campaign, participation, quote)
+	return participation, nil
+}
+
+// ReconcileCancel applies a requestCancel seen on chain. The chain is the
+// record: the cancel is applied even when the window has closed, with the
+// penalty the window gives at the block time, or none when it refuses the
+// cancel. Events are applied in chain order; redelivering the last one
+// returns the participation with applied false.
+func (s *ParticipationService) ReconcileCancel(ctx context.Context, ev CancelEvent) (participation *models.Participation, applied bool, err error) {
+	campaign, err := s.campaignRepo.FindByChainAddress(ctx, ev.CampaignAddress)
+	if err != nil {
+		return nil, false, err
+	}
+	if campaign == nil {
+		return nil, false, ErrCampaignNotFound
+	}
+	at := ev.At
+	if at.IsZero() {
+		at = s.clock.Now()
+	}
+
+	var locked *models.Campaign
+	var quote CancelQuote
+
+	err = s.db.TransactionWithRetryContext(ctx, database.DefaultRetryConfig, nil, func(tx *sqlx.Tx) error {
+		applied = false
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, campaign.ID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		locked, err = s.campaignRepo.FindByIDForUpdate(ctx, tx, campaign.ID, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if locked == nil {
+			return ErrCampaignNotFound
+		}
+
+		participation, err = s.participationRepo.FindByWalletForUpdate(ctx, tx, campaign.ID, ev.WalletAddress)
+		if err != nil {
+			return err
+		}
+		if participation == nil {
+			return ErrParticipationNotFound
+		}
+		if participation.CancelTxHash != nil && strings.EqualFold(*participation.CancelTxHash, ev.TxHash) {
+			return nil
+		}
+
+		bps, err := cancelPenaltyBps(locked, at)
+		if errors.Is(err, ErrCancelWindowClosed) {
+			logger.FromContext(ctx).Warn("cancel seen on chain after the cancellation window closed",
+				logger.KeyCampaignID, campaign.ID, "tx_hash", ev.TxHash)
+			bps, err = 0, nil
+		}
+		if err != nil {
+			return err
+		}
+		quote, err = quoteCancel(locked, participation, ev.Amount, bps)
+		if err != nil {
+			return err
+		}
+		applied = true
+		return s.applyCancel(ctx, tx, locked, participation, quote, &ev.TxHash)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if applied {
+		metrics.ParticipationsCancelled.Inc()
+		s.notifications.CancellationConfirmed(ctx, locked, participation, quote)
+	}
+	return participation, applied, nil
+}
+
+// applyCancel takes quote.Amount out of p and the campaign totals inside
+// tx. Cancelling the whole deposit cancels the participation; deposit_amount
+// then keeps what was cancelled. The caller holds the campaign lock.
+func (s *ParticipationService) applyCancel(ctx context.Context, tx *sqlx.Tx, campaign *models.Campaign, p *models.Participation, quote CancelQuote, txHash *string) error {
+	if !s.participations.Can(p.Status, repository.ParticipationCancelled) {
+		return ErrNotCancellable
+	}
+
+	cancelled := money.New(quote.Amount.Int, money.USDT)
+	deposit := money.New(p.DepositAmount.Int, money.USDT)
+	remaining, err := deposit.Sub(cancelled)
+	if err != nil {
+		return err
+	}
+	if remaining.IsZero() {
+		if err := s.participations.Transition(ctx, p.ID.String(), p.Status, repository.ParticipationCancelled); err != nil {
+			return err
+		}
+		p.Status = repository.ParticipationCancelled
+	} else {
+		if p.Status != repository.ParticipationActive {
+			if err := s.participations.Transition(ctx, p.ID.String(), p.Status, repository.ParticipationActive); err != nil {
+				return err
+			}
+			p.Status = repository.ParticipationActive
+		}
+		p.DepositAmount = models.NewBigInt(remaining.Units())
+		p.ExpectedRebate = models.NewBigInt(remaining.MulBps(campaign.SaveFloorBps).Units())
+	}
+
+	penalty, err := money.New(p.CancelPenalty.Int, money.USDT).Add(money.New(quote.Penalty.Int, money.USDT))
+	if err != nil {
+		return err
+	}
+	p.CancelPenalty = models.NewBigInt(penalty.Units())
+	// What is left of a larger cancel request stays pending
+	pending, err := money.New(p.CancelPending.Int, money.USDT).Sub(cancelled)
+	if err != nil {
+		return err
+	}
+	if pending.Sign() < 0 {
+		pending = money.Zero(money.USDT)
+	}
+	p.CancelPending = models.NewBigInt(pending.Units())
+	if txHash != nil {
+		p.CancelTxHash = txHash
+	}
+	if err := s.participationRepo.UpdateCancellation(ctx, tx, p); err != nil {
+		return fmt.Errorf("failed to cancel participation: %w", err)
+	}
+	if err := s.participationEvent(ctx, tx, outbox.ParticipationCancelled, p.ID, map[string]interface{}{
+		"participation": p,
+		"quote":         quote,
+	}); err != nil {
+		return err
+	}
+
+	qty, _, err := cancelled.QuoRem(money.New(campaign.BasePrice.Int, money.USDT))
+	if err != nil {
+		return err
+	}
+	total, err := money.New(campaign.CurrentAmount.Int, money.USDT).Sub(cancelled)
+	if err != nil {
+		return err
+	}
+	campaign.CurrentAmount = models.NewBigInt(total.Units())
+	campaign.CurrentQty -= int(qty.Int64())
+	if campaign.Status == models.StatusReached && campaign.CurrentQty < campaign.MinQty {
+		if err := s.campaigns.Transition(ctx, tx, campaign, models.StatusRecruiting, "cancellation took it under the minimum quantity"); err != nil {
+			return err
+		}
+	}
+	return s.campaignRepo.UpdateTotals(ctx, tx, campaign)
+}
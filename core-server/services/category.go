@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/validate"
+)
+
+const (
+	// MaxTags bounds the tags of a campaign
+	MaxTags = 10
+	// MaxTagLength bounds a tag, in characters
+	MaxTagLength = 32
+)
+
+var (
+	ErrCategoryNotFound = apperrors.Catalog(apperrors.ReasonCategoryNotFound)
+	ErrCategoryExists   = apperrors.Catalog(apperrors.ReasonCategoryExists)
+	ErrCategoryInUse    = apperrors.Catalog(apperrors.ReasonCategoryInUse)
+	ErrInvalidTags      = apperrors.Catalog(apperrors.ReasonInvalidTags, MaxTags, MaxTagLength)
+)
+
+// CategoryInput is a category's editable fields
+type CategoryInput struct {
+	Name     string
+	Position int
+}
+
+// CategoryService keeps the category taxonomy campaigns are browsed by.
+// Slugs are permanent; categories with campaigns cannot be deleted.
+type CategoryService struct {
+	db           *database.DB
+	categoryRepo *repository.CategoryRepository
+	audit        *audit.Store
+	clock        clock.Clock
+}
+
+func NewCategoryService(db *database.DB, clk clock.Clock) *CategoryService {
+	return &CategoryService{
+		db:           db,
+		categoryRepo: repository.NewCategoryRepository(db),
+		audit:        audit.NewStore(db, clk),
+		clock:        clock.OrSystem(clk),
+	}
+}
+
+// ListCategories returns every category in display order
+func (s *CategoryService) ListCategories(ctx context.Context) ([]*models.CampaignCategory, error) {
+	categories, err := s.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	return categories, nil
+}
+
+// CreateCategory adds a category
+func (s *CategoryService) CreateCategory(ctx context.Context, slug string, in CategoryInput) (*models.CampaignCategory, error) {
+	if err := validate.First(
+		validate.Slug("slug", slug),
+		validate.Required("name", in.Name),
+	); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	category := &models.CampaignCategory{
+		Slug:      slug,
+		Name:      strings.TrimSpace(in.Name),
+		Position:  in.Position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := s.categoryRepo.Create(ctx, tx, category); err != nil {
+			if errors.Is(err, repository.ErrDuplicateCategory) {
+				return ErrCategoryExists
+			}
+			return fmt.Errorf("failed to create category: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCategoryCreate,
+			ResourceType: audit.ResourceCategory,
+			ResourceID:   slug,
+			After:        category,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory renames or moves a category
+func (s *CategoryService) UpdateCategory(ctx context.Context, slug string, in CategoryInput) (*models.CampaignCategory, error) {
+	if err := validate.Required("name", in.Name); err != nil {
+		return nil, err
+	}
+
+	var category *models.CampaignCategory
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		category, err = s.categoryRepo.FindForUpdate(ctx, tx, slug)
+		if err != nil {
+			return fmt.Errorf("failed to load category: %w", err)
+		}
+		if category == nil {
+			return ErrCategoryNotFound
+		}
+		before := *category
+
+		category.Name = strings.TrimSpace(in.Name)
+		category.Position = in.Position
+		category.UpdatedAt = s.clock.Now()
+		if err := s.categoryRepo.Update(ctx, tx, category); err != nil {
+			return fmt.Errorf("failed to update category: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCategoryUpdate,
+			ResourceType: audit.ResourceCategory,
+			ResourceID:   slug,
+			Before:       &before,
+			After:        category,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory removes a category no campaign is in
+func (s *CategoryService) DeleteCategory(ctx context.Context, slug string) error {
+	return s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		category, err := s.categoryRepo.FindForUpdate(ctx, tx, slug)
+		if err != nil {
+			return fmt.Errorf("failed to load category: %w", err)
+		}
+		if category == nil {
+			return ErrCategoryNotFound
+		}
+		if err := s.categoryRepo.Delete(ctx, tx, slug); err != nil {
+			if errors.Is(err, repository.ErrCategoryInUse) {
+				return ErrCategoryInUse
+			}
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCategoryDelete,
+			ResourceType: audit.ResourceCategory,
+			ResourceID:   slug,
+			Before:       category,
+		})
+	})
+}
+
+// checkCategory returns ErrCategoryNotFound unless slug names a category
+func checkCategory(ctx context.Context, repo *repository.CategoryRepository, slug string) error {
+	category, err := repo.FindBySlug(ctx, slug)
+	if err != nil {
+		return fmt.Errorf("failed to load category: %w", err)
+	}
+	if category == nil {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// normalizeTags trims and lowercases tags and drops blanks and repeats,
+// keeping their order
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(out, tag) {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, ErrInvalidTags
+		}
+		out = append(out, tag)
+	}
+	if len(out) > MaxTags {
+		return nil, ErrInvalidTags
+	}
+	return out, nil
+}
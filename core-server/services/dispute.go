@@ -0,0 +1,597 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/outbox"
+	"r2s/pkg/pagination"
+	"r2s/pkg/statemachine"
+)
+
+var (
+	ErrDisputeNotFound     = apperrors.Catalog(apperrors.ReasonDisputeNotFound)
+	ErrDisputeExists       = apperrors.Catalog(apperrors.ReasonDisputeExists)
+	ErrNotDisputable       = apperrors.Catalog(apperrors.ReasonNotDisputable)
+	ErrDisputeWindowClosed = apperrors.Catalog(apperrors.ReasonDisputeWindowClosed)
+	ErrDisputeClosed       = apperrors.Catalog(apperrors.ReasonDisputeClosed)
+	ErrRefundNotPending    = apperrors.Catalog(apperrors.ReasonRefundNotPending)
+	ErrInvalidRefund       = apperrors.Catalog(apperrors.ReasonInvalidRefund)
+	ErrNoPaymentToRefund   = apperrors.Catalog(apperrors.ReasonNoPaymentToRefund)
+)
+
+// DisputeConfig is loadable with pkg/config
+type DisputeConfig struct {
+	// ResolveSLA is how long admins have to resolve an open dispute
+	ResolveSLA time.Duration `env:"DISPUTE_RESOLVE_SLA" default:"72h"`
+	// RefundSLA is how long a refund may take once a dispute is resolved
+	// with one
+	RefundSLA time.Duration `env:"DISPUTE_REFUND_SLA" default:"72h"`
+	// SettlementWindow is how long after settlement participants may
+	// dispute it
+	SettlementWindow time.Duration `env:"DISPUTE_SETTLEMENT_WINDOW" default:"336h"`
+}
+
+// Validate implements config.Validator
+func (c DisputeConfig) Validate() error {
+	if c.ResolveSLA <= 0 {
+		return errors.New("DISPUTE_RESOLVE_SLA must be positive")
+	}
+	if c.RefundSLA <= 0 {
+		return errors.New("DISPUTE_REFUND_SLA must be positive")
+	}
+	if c.SettlementWindow <= 0 {
+		return errors.New("DISPUTE_SETTLEMENT_WINDOW must be positive")
+	}
+	return nil
+}
+
+// OpenDisputeInput is a participant's complaint about a participation
+type OpenDisputeInput struct {
+	ParticipationID uuid.UUID
+	// Kind is models.DisputeOnFulfillment or models.DisputeOnSettlement
+	Kind   string
+	Reason string
+}
+
+// ResolveDisputeInput is an admin's decision on an open dispute
+type ResolveDisputeInput struct {
+	// Refund resolves the dispute with a refund of Amount by Method;
+	// otherwise it is rejected
+	Refund bool
+	Amount *big.Int
+	Method string
+	Note   *string
+}
+
+// DisputeService handles participants' disputes of fulfillments and
+// settlements. Admins resolve open disputes by rejecting them or with a
+// refund, which completes when the payment provider reports the payment
+// refunded or an admin records the on-chain refund. Closing a fulfillment
+// dispute accepts the fulfillment, unless the whole deposit was refunded,
+// so the campaign can settle.
+type DisputeService struct {
+	db                *database.DB
+	disputeRepo       *repository.DisputeRepository
+	fulfillmentRepo   *repository.FulfillmentRepository
+	participationRepo *repository.ParticipationRepository
+	campaignRepo      *repository.CampaignRepository
+	paymentRepo       *repository.PaymentRepository
+	voucherRepo       *repository.VoucherRepository
+	cfg               DisputeConfig
+	clock             clock.Clock
+	audit             *audit.Store
+	events            *outbox.Store
+	notifications     *NotificationService
+	participations    *statemachine.Machine[string]
+}
+
+func NewDisputeService(db *database.DB, cfg DisputeConfig, clk clock.Clock, notifications *NotificationService) *DisputeService {
+	clk = clock.OrSystem(clk)
+	return &DisputeService{
+		db:                db,
+		disputeRepo:       repository.NewDisputeRepository(db),
+		fulfillmentRepo:   repository.NewFulfillmentRepository(db),
+		participationRepo: repository.NewParticipationRepository(db),
+		campaignRepo:      repository.NewCampaignRepository(db),
+		paymentRepo:       repository.NewPaymentRepository(db),
+		voucherRepo:       repository.NewVoucherRepository(db),
+		cfg:               cfg,
+		clock:             clk,
+		audit:             audit.NewStore(db, clk),
+		events:            outbox.NewStore(db, clk),
+		notifications:     notifications,
+		participations:    statemachine.NewParticipation().OnTransition(statemachine.LogHistory[string]()),
+	}
+}
+
+// Open opens a dispute of the user's participation. Fulfillments are
+// disputed while their confirmation window is open, which holds up the
+// campaign's settlement; settlements within SettlementWindow of it.
+func (s *DisputeService) Open(ctx context.Context, userID uuid.UUID, in OpenDisputeInput) (*models.Dispute, error) {
+	var dispute *models.Dispute
+	var err error
+	switch in.Kind {
+	case models.DisputeOnFulfillment:
+		_, dispute, err = s.disputeFulfillment(ctx, userID, in.ParticipationID, in.Reason)
+	case models.DisputeOnSettlement:
+		dispute, err = s.disputeSettlement(ctx, userID, in.ParticipationID, in.Reason)
+	default:
+		return nil, apperrors.InvalidArgument("kind must be fulfillment or settlement")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.disputeRepo.FindByID(ctx, dispute.ID)
+}
+
+// disputeFulfillment marks the fulfillment of the user's participation
+// disputed and opens a dispute of it
+func (s *DisputeService) disputeFulfillment(ctx context.Context, userID, participationID uuid.UUID, reason string) (*models.Fulfillment, *models.Dispute, error) {
+	participation, err := s.participationRepo.FindByID(ctx, participationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if participation == nil {
+		return nil, nil, ErrFulfillmentNotFound
+	}
+
+	var fulfillment *models.Fulfillment
+	var dispute *models.Dispute
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Disputing holds up the campaign's settlement
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, participation.CampaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		fulfillment, err = s.fulfillmentRepo.FindForUpdate(ctx, tx, participationID)
+		if err != nil {
+			return fmt.Errorf("failed to load fulfillment: %w", err)
+		}
+		now := s.clock.Now()
+		if err := checkAnswerable(fulfillment, userID, now); err != nil {
+			return err
+		}
+
+		fulfillment.Status = models.FulfillmentDisputed
+		fulfillment.RespondedAt = &now
+		fulfillment.DisputeReason = &reason
+		if err := s.fulfillmentRepo.Respond(ctx, tx, fulfillment); err != nil {
+			return fmt.Errorf("failed to record fulfillment response: %w", err)
+		}
+		dispute, err = s.create(ctx, tx, fulfillment.ParticipationID, fulfillment.CampaignID, userID, models.DisputeOnFulfillment, reason, now)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	campaign, err := s.campaignRepo.FindByID(ctx, fulfillment.CampaignID)
+	if err == nil && campaign != nil {
+		s.notifications.FulfillmentDisputed(ctx, campaign, fulfillment)
+	}
+	return fulfillment, dispute, nil
+}
+
+// disputeSettlement opens a dispute of the user's settled participation
+func (s *DisputeService) disputeSettlement(ctx context.Context, userID, participationID uuid.UUID, reason string) (*models.Dispute, error) {
+	var dispute *models.Dispute
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		participation, err := s.participationRepo.FindByIDForUpdate(ctx, tx, participationID)
+		if err != nil {
+			return err
+		}
+		if participation == nil || participation.UserID != userID {
+			return ErrParticipationNotFound
+		}
+		if participation.Status != models.ParticipationSettled {
+			return ErrNotDisputable
+		}
+		campaign, err := s.campaignRepo.FindByID(ctx, participation.CampaignID)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		now := s.clock.Now()
+		if campaign.SettlementDate == nil || !now.Before(campaign.SettlementDate.Add(s.cfg.SettlementWindow)) {
+			return ErrDisputeWindowClosed
+		}
+
+		dispute, err = s.create(ctx, tx, participation.ID, campaign.ID, userID, models.DisputeOnSettlement, reason, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// create opens a dispute inside tx, due for resolution within ResolveSLA
+func (s *DisputeService) create(ctx context.Context, tx *sqlx.Tx, participationID, campaignID, userID uuid.UUID, kind, reason string, now time.Time) (*models.Dispute, error) {
+	d := &models.Dispute{
+		ID:              uuid.New(),
+		ParticipationID: participationID,
+		CampaignID:      campaignID,
+		UserID:          userID,
+		Kind:            kind,
+		Status:          models.DisputeOpen,
+		Reason:          reason,
+		SLADueAt:        now.Add(s.cfg.ResolveSLA),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err := s.disputeRepo.Create(ctx, tx, d)
+	if errors.Is(err, repository.ErrDuplicateDispute) {
+		return nil, ErrDisputeExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dispute: %w", err)
+	}
+	return d, nil
+}
+
+// ListUserDisputes returns one page of the user's disputes, newest first,
+// and their total
+func (s *DisputeService) ListUserDisputes(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Dispute, int64, error) {
+	disputes, total, err := s.disputeRepo.FindByUser(ctx, userID, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
+// GetUserDispute returns one of the user's disputes; those of other users
+// are reported as not found
+func (s *DisputeService) GetUserDispute(ctx context.Context, userID, id uuid.UUID) (*models.Dispute, error) {
+	dispute, err := s.GetDispute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.UserID != userID {
+		return nil, ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+// GetDispute returns a dispute for admins and internal services
+func (s *DisputeService) GetDispute(ctx context.Context, id uuid.UUID) (*models.Dispute, error) {
+	dispute, err := s.disputeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dispute: %w", err)
+	}
+	if dispute == nil {
+		return nil, ErrDisputeNotFound
+	}
+	return dispute, nil
+}
+
+// ListDisputes returns the disputes matching f for the admin API
+func (s *DisputeService) ListDisputes(ctx context.Context, f repository.DisputeFilter) ([]*models.Dispute, int64, error) {
+	f.Now = s.clock.Now()
+	disputes, total, err := s.disputeRepo.List(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
+// Resolve rejects an open dispute or resolves it with a refund of at most
+// the deposit. Payment refunds are requested on the participation's
+// completed payment and complete with its refunded webhook; on-chain
+// refunds complete with RecordRefund. The refund is then due within
+// RefundSLA.
+func (s *DisputeService) Resolve(ctx context.Context, id uuid.UUID, in ResolveDisputeInput) (*models.Dispute, error) {
+	existing, err := s.GetDispute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var dispute *models.Dispute
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Resolving changes what the campaign's settlement sees
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, existing.CampaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		dispute, err = s.disputeRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load dispute: %w", err)
+		}
+		if dispute == nil {
+			return ErrDisputeNotFound
+		}
+		if dispute.Status != models.DisputeOpen {
+			return ErrDisputeClosed
+		}
+
+		now := s.clock.Now()
+		dispute.ResolutionNote = in.Note
+		dispute.ResolvedAt = &now
+		if actor, err := uuid.Parse(audit.ActorFrom(ctx).ID); err == nil {
+			dispute.ResolvedBy = &actor
+		}
+		if in.Refund {
+			err = s.startRefund(ctx, tx, dispute, in, now)
+		} else {
+			dispute.Status = models.DisputeRejected
+			err = s.acceptFulfillment(ctx, tx, dispute)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.disputeRepo.Update(ctx, tx, dispute); err != nil {
+			return fmt.Errorf("failed to resolve dispute: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionDisputeResolve,
+			ResourceType: audit.ResourceDispute,
+			ResourceID:   dispute.ID.String(),
+			Before:       map[string]interface{}{"status": models.DisputeOpen},
+			After: map[string]interface{}{
+				"status":       dispute.Status,
+				"refundAmount": dispute.RefundAmount,
+				"refundMethod": dispute.RefundMethod,
+				"paymentId":    dispute.PaymentID,
+				"note":         dispute.ResolutionNote,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyResolved(ctx, dispute)
+	return dispute, nil
+}
+
+// startRefund moves dispute to refunding with the refund of in inside tx
+func (s *DisputeService) startRefund(ctx context.Context, tx *sqlx.Tx, dispute *models.Dispute, in ResolveDisputeInput, now time.Time) error {
+	participation, err := s.participationRepo.FindByIDForUpdate(ctx, tx, dispute.ParticipationID)
+	if err != nil {
+		return err
+	}
+	if participation == nil {
+		return ErrParticipationNotFound
+	}
+	if in.Amount == nil {
+		return ErrInvalidRefund
+	}
+	// Earlier refunds of the participation count against its deposit
+	refunded, err := s.refunded(ctx, tx, participation.ID, models.DisputeRefunding, models.DisputeRefunded)
+	if err != nil {
+		return err
+	}
+	left, err := money.New(participation.DepositAmount.Int, money.USDT).Sub(refunded)
+	if err != nil {
+		return err
+	}
+	refund := money.New(in.Amount, money.USDT)
+	if cmp, err := refund.Cmp(left); err != nil || !refund.IsPositive() || cmp > 0 {
+		return ErrInvalidRefund
+	}
+
+	switch in.Method {
+	case models.RefundViaPayment:
+		payment, err := s.paymentRepo.FindRefundableForUpdate(ctx, tx, dispute.ParticipationID)
+		if err != nil {
+			return fmt.Errorf("failed to load payment: %w", err)
+		}
+		if payment == nil {
+			return ErrNoPaymentToRefund
+		}
+		if err := s.paymentRepo.RequestRefund(ctx, tx, payment.ID, dispute.ID, models.NewBigInt(in.Amount), now); err != nil {
+			return fmt.Errorf("failed to request refund: %w", err)
+		}
+		if err := s.events.Append(ctx, tx, outbox.Event{
+			Type:          outbox.PaymentRefundRequested,
+			AggregateType: outbox.AggregatePayment,
+			AggregateID:   payment.ID.String(),
+			Payload: map[string]interface{}{
+				"paymentId": payment.ID,
+				"disputeId": dispute.ID,
+				"amount":    models.NewBigInt(in.Amount),
+			},
+		}); err != nil {
+			return err
+		}
+		dispute.PaymentID = &payment.ID
+	case models.RefundViaChain:
+	default:
+		return apperrors.InvalidArgument("method must be payment or onchain")
+	}
+
+	method := in.Method
+	dispute.Status = models.DisputeRefunding
+	dispute.RefundAmount = models.NewBigInt(in.Amount)
+	dispute.RefundMethod = &method
+	dispute.SLADueAt = now.Add(s.cfg.RefundSLA)
+	dispute.SLABreachedAt = nil
+	return nil
+}
+
+// RecordRefund completes the on-chain refund of a dispute with its mined
+// transaction
+func (s *DisputeService) RecordRefund(ctx context.Context, id uuid.UUID, txHash string) (*models.Dispute, error) {
+	if !proofHashPattern.MatchString(txHash) {
+		return nil, apperrors.InvalidArgument("txHash must be a transaction hash")
+	}
+	txHash = strings.ToLower(txHash)
+	existing, err := s.GetDispute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var dispute *models.Dispute
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, existing.CampaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		dispute, err = s.disputeRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load dispute: %w", err)
+		}
+		if dispute == nil {
+			return ErrDisputeNotFound
+		}
+		if dispute.Status != models.DisputeRefunding || dispute.RefundMethod == nil || *dispute.RefundMethod != models.RefundViaChain {
+			return ErrRefundNotPending
+		}
+
+		dispute.RefundTxHash = &txHash
+		if err := s.completeRefund(ctx, tx, dispute); err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionDisputeRefund,
+			ResourceType: audit.ResourceDispute,
+			ResourceID:   dispute.ID.String(),
+			Before:       map[string]interface{}{"status": models.DisputeRefunding},
+			After:        map[string]interface{}{"status": dispute.Status, "txHash": txHash},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyResolved(ctx, dispute)
+	return dispute, nil
+}
+
+// paymentRefunded completes the disputes refunded through payment inside
+// the tx of its refunded webhook and returns them. The caller notifies the
+// participants once tx commits.
+func (s *DisputeService) paymentRefunded(ctx context.Context, tx *sqlx.Tx, payment *models.Payment) ([]*models.Dispute, error) {
+	if payment.CampaignID != nil {
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, payment.CampaignID.String())); err != nil {
+			return nil, err
+		}
+	}
+	disputes, err := s.disputeRepo.FindRefundingByPayment(ctx, tx, payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load refunding disputes: %w", err)
+	}
+	for _, d := range disputes {
+		if err := s.completeRefund(ctx, tx, d); err != nil {
+			return nil, err
+		}
+	}
+	return disputes, nil
+}
+
+// completeRefund closes a refunding dispute inside tx. Refunding the whole
+// deposit of an active participation refunds the participation, taking it
+// out of the campaign's settlement; otherwise a fulfillment dispute accepts
+// the fulfillment. A whole refund also voids the participation's voucher
+// unless it was redeemed.
+func (s *DisputeService) completeRefund(ctx context.Context, tx *sqlx.Tx, d *models.Dispute) error {
+	now := s.clock.Now()
+	d.Status = models.DisputeRefunded
+	d.RefundedAt = &now
+	if err := s.disputeRepo.Update(ctx, tx, d); err != nil {
+		return fmt.Errorf("failed to complete refund: %w", err)
+	}
+
+	participation, err := s.participationRepo.FindByIDForUpdate(ctx, tx, d.ParticipationID)
+	if err != nil {
+		return err
+	}
+	if participation == nil {
+		return ErrParticipationNotFound
+	}
+	status := participation.Status
+	// d is refunded by now, so the sum includes it
+	refunded, err := s.refunded(ctx, tx, participation.ID, models.DisputeRefunded)
+	if err != nil {
+		return err
+	}
+	whole := refunded.Units().Cmp(participation.DepositAmount.Big()) >= 0
+	if whole {
+		if err := s.voucherRepo.Void(ctx, tx, participation.ID, now); err != nil {
+			return fmt.Errorf("failed to void voucher: %w", err)
+		}
+	}
+	if status == models.ParticipationActive && whole {
+		if err := s.participations.Transition(ctx, participation.ID.String(), status, models.ParticipationRefunded); err != nil {
+			return err
+		}
+		status = models.ParticipationRefunded
+	} else if err := s.acceptFulfillment(ctx, tx, d); err != nil {
+		return err
+	}
+	if err := s.participationRepo.RecordRefund(ctx, tx, participation.ID, status, d.RefundTxHash); err != nil {
+		return fmt.Errorf("failed to record refund: %w", err)
+	}
+	return s.events.Append(ctx, tx, outbox.Event{
+		Type:          outbox.ParticipationRefunded,
+		AggregateType: outbox.AggregateParticipation,
+		AggregateID:   participation.ID.String(),
+		Payload: map[string]interface{}{
+			"participationId": participation.ID,
+			"campaignId":      participation.CampaignID,
+			"disputeId":       d.ID,
+			"amount":          d.RefundAmount,
+			"method":          d.RefundMethod,
+			"txHash":          d.RefundTxHash,
+			"status":          status,
+		},
+	})
+}
+
+// refunded returns what the participation's disputes in one of statuses
+// refund, read inside tx
+func (s *DisputeService) refunded(ctx context.Context, tx *sqlx.Tx, participationID uuid.UUID, statuses ...string) (money.Amount, error) {
+	totals, err := s.disputeRepo.RefundTotals(ctx, tx, []uuid.UUID{participationID}, statuses...)
+	if err != nil {
+		return money.Amount{}, fmt.Errorf("failed to load refunds: %w", err)
+	}
+	return money.New(totals[participationID], money.USDT), nil
+}
+
+// acceptFulfillment confirms the disputed fulfillment of a closed
+// fulfillment dispute inside tx, so it no longer holds up settlement
+func (s *DisputeService) acceptFulfillment(ctx context.Context, tx *sqlx.Tx, d *models.Dispute) error {
+	if d.Kind != models.DisputeOnFulfillment {
+		return nil
+	}
+	fulfillment, err := s.fulfillmentRepo.FindForUpdate(ctx, tx, d.ParticipationID)
+	if err != nil {
+		return fmt.Errorf("failed to load fulfillment: %w", err)
+	}
+	if fulfillment == nil || fulfillment.Status != models.FulfillmentDisputed {
+		return nil
+	}
+	fulfillment.Status = models.FulfillmentConfirmed
+	if err := s.fulfillmentRepo.Respond(ctx, tx, fulfillment); err != nil {
+		return fmt.Errorf("failed to accept fulfillment: %w", err)
+	}
+	return nil
+}
+
+// notifyResolved tells participants how their disputes were resolved
+func (s *DisputeService) notifyResolved(ctx context.Context, disputes ...*models.Dispute) {
+	for _, d := range disputes {
+		campaign, err := s.campaignRepo.FindByID(ctx, d.CampaignID)
+		if err != nil || campaign == nil {
+			continue
+		}
+		s.notifications.DisputeResolved(ctx, campaign, d)
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+)
+
+// MaxFavorites bounds a user's watchlist
+const MaxFavorites = 200
+
+var ErrWatchlistFull = apperrors.Catalog(apperrors.ReasonWatchlistFull)
+
+// FavoriteService keeps users' watchlists. batch-server pushes to watchers
+// when a favorited campaign nears its goal or closes.
+type FavoriteService struct {
+	db           *database.DB
+	favoriteRepo *repository.FavoriteRepository
+	campaignRepo *repository.CampaignRepository
+	clock        clock.Clock
+}
+
+func NewFavoriteService(db *database.DB, clk clock.Clock) *FavoriteService {
+	return &FavoriteService{
+		db:           db,
+		favoriteRepo: repository.NewFavoriteRepository(db),
+		campaignRepo: repository.NewCampaignRepository(db),
+		clock:        clock.OrSystem(clk),
+	}
+}
+
+// AddFavorite puts the campaign on the user's watchlist. Adding it again
+// returns the existing favorite.
+func (s *FavoriteService) AddFavorite(ctx context.Context, userID, campaignID uuid.UUID) (*models.CampaignFavorite, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign: %w", err)
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	var favorite *models.CampaignFavorite
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Serializes the user's adds so the count below holds
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockUser, userID.String())); err != nil {
+			return err
+		}
+
+		existing, err := s.favoriteRepo.Find(ctx, tx, userID, campaignID)
+		if err != nil {
+			return fmt.Errorf("failed to load favorite: %w", err)
+		}
+		if existing != nil {
+			favorite = existing
+			return nil
+		}
+
+		n, err := s.favoriteRepo.Count(ctx, tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to count favorites: %w", err)
+		}
+		if n >= MaxFavorites {
+			return ErrWatchlistFull
+		}
+
+		favorite = &models.CampaignFavorite{
+			UserID:     userID,
+			CampaignID: campaignID,
+			CreatedAt:  s.clock.Now(),
+		}
+		if err := s.favoriteRepo.Create(ctx, tx, favorite); err != nil {
+			return fmt.Errorf("failed to create favorite: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return favorite, nil
+}
+
+// RemoveFavorite takes the campaign off the user's watchlist; removing one
+// that is not on it succeeds
+func (s *FavoriteService) RemoveFavorite(ctx context.Context, userID, campaignID uuid.UUID) error {
+	if err := s.favoriteRepo.Delete(ctx, userID, campaignID); err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
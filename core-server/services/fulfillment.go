@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/statemachine"
+)
+
+var (
+	ErrNotInFulfillment    = apperrors.Catalog(apperrors.ReasonNotInFulfillment)
+	ErrInvalidProofHash    = apperrors.Catalog(apperrors.ReasonInvalidProofHash)
+	ErrFulfillmentNotFound = apperrors.Catalog(apperrors.ReasonFulfillmentNotFound)
+	ErrFulfillmentAnswered = apperrors.Catalog(apperrors.ReasonFulfillmentAnswered)
+	ErrConfirmWindowClosed = apperrors.Catalog(apperrors.ReasonConfirmWindowClosed)
+	ErrFulfillmentPending  = apperrors.Catalog(apperrors.ReasonFulfillmentPending)
+)
+
+// proofHashPattern is a 0x-prefixed SHA-256 digest
+var proofHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// FulfillmentConfig is loadable with pkg/config
+type FulfillmentConfig struct {
+	// ConfirmWindow is how long participants have to confirm or dispute a
+	// fulfillment before it counts as confirmed
+	ConfirmWindow time.Duration `env:"FULFILLMENT_CONFIRM_WINDOW" default:"72h"`
+}
+
+// Validate implements config.Validator
+func (c FulfillmentConfig) Validate() error {
+	if c.ConfirmWindow <= 0 {
+		return errors.New("FULFILLMENT_CONFIRM_WINDOW must be positive")
+	}
+	return nil
+}
+
+// MarkFulfilledInput is a merchant's fulfillment of a campaign's orders
+type MarkFulfilledInput struct {
+	// ParticipationIDs limits the fulfillment to these participations;
+	// empty marks every active one
+	ParticipationIDs []uuid.UUID
+	// ProofHash is the 0x-prefixed SHA-256 of the receipt or photo
+	ProofHash string
+	// ProofImageID is a completed fulfillment_proof upload of the photo
+	ProofImageID *uuid.UUID
+	Note         *string
+}
+
+// FulfillmentService records merchants' fulfillment of campaign orders and
+// the participants' confirmations and disputes. A campaign settles only
+// once every active participation is fulfilled and accepted and none is
+// disputed; see Summary. Disputes are handled by DisputeService.
+type FulfillmentService struct {
+	db                *database.DB
+	fulfillmentRepo   *repository.FulfillmentRepository
+	participationRepo *repository.ParticipationRepository
+	disputeRepo       *repository.DisputeRepository
+	campaignRepo      *repository.CampaignRepository
+	mediaRepo         *repository.MediaRepository
+	cfg               FulfillmentConfig
+	clock             clock.Clock
+	audit             *audit.Store
+	notifications     *NotificationService
+	disputes          *DisputeService
+	campaigns         *statemachine.Machine[models.CampaignStatus]
+}
+
+func NewFulfillmentService(db *database.DB, cfg FulfillmentConfig, clk clock.Clock, notifications *NotificationService, disputes *DisputeService) *FulfillmentService {
+	clk = clock.OrSystem(clk)
+	return &FulfillmentService{
+		db:                db,
+		fulfillmentRepo:   repository.NewFulfillmentRepository(db),
+		participationRepo: repository.NewParticipationRepository(db),
+		disputeRepo:       repository.NewDisputeRepository(db),
+		campaignRepo:      repository.NewCampaignRepository(db),
+		mediaRepo:         repository.NewMediaRepository(db),
+		cfg:               cfg,
+		clock:             clk,
+		audit:             audit.NewStore(db, clk),
+		notifications:     notifications,
+		disputes:          disputes,
+		campaigns:         statemachine.NewCampaign(),
+	}
+}
+
+// MarkFulfilled marks the campaign's orders fulfilled with proof and opens
+// each participant's confirmation window. Participations already marked
+// are skipped unless they were disputed, which are marked again with the
+// new proof and their open disputes closed as redelivered; those being
+// refunded are skipped too. It returns the fulfillments it marked.
+func (s *FulfillmentService) MarkFulfilled(ctx context.Context, campaignID uuid.UUID, in MarkFulfilledInput) ([]*models.Fulfillment, error) {
+	if !proofHashPattern.MatchString(in.ProofHash) {
+		return nil, ErrInvalidProofHash
+	}
+
+	f := &models.Fulfillment{
+		ProofHash:   strings.ToLower(in.ProofHash),
+		Note:        in.Note,
+		FulfilledAt: s.clock.Now(),
+	}
+	f.ConfirmDeadline = f.FulfilledAt.Add(s.cfg.ConfirmWindow)
+	if actor, err := uuid.Parse(audit.ActorFrom(ctx).ID); err == nil {
+		f.FulfilledBy = &actor
+	}
+	if in.ProofImageID != nil {
+		var err error
+		f.ProofURL, f.ProofThumbnailURL, err = readyImage(ctx, s.mediaRepo, *in.ProofImageID, models.MediaFulfillmentProof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var campaign *models.Campaign
+	var marked []*models.Fulfillment
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Serializes marking against settlement and participation changes
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, campaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		campaign, err = s.campaignRepo.FindByIDForUpdate(ctx, tx, campaignID, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		if err := authorizeCampaign(ctx, campaign); err != nil {
+			return err
+		}
+		if campaign.Status != models.StatusFulfillment {
+			return ErrNotInFulfillment
+		}
+
+		marked, err = s.fulfillmentRepo.MarkFulfilled(ctx, tx, campaignID, in.ParticipationIDs, f)
+		if err != nil {
+			return fmt.Errorf("failed to mark fulfillments: %w", err)
+		}
+		if len(marked) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(marked))
+		for i, m := range marked {
+			ids[i] = m.ParticipationID
+		}
+		if err := s.disputeRepo.CloseRedelivered(ctx, tx, ids, f.FulfilledAt); err != nil {
+			return fmt.Errorf("failed to close redelivered disputes: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionCampaignFulfill,
+			ResourceType: audit.ResourceCampaign,
+			ResourceID:   campaignID.String(),
+			After: map[string]interface{}{
+				"participations":  len(marked),
+				"proofHash":       f.ProofHash,
+				"proofUrl":        f.ProofURL,
+				"confirmDeadline": f.ConfirmDeadline,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifications.OrdersFulfilled(ctx, campaign, marked)
+	return marked, nil
+}
+
+// Confirm accepts the fulfillment of the user's participation
+func (s *FulfillmentService) Confirm(ctx context.Context, userID, participationID uuid.UUID) (*models.Fulfillment, error) {
+	participation, err := s.participationRepo.FindByID(ctx, participationID)
+	if err != nil {
+		return nil, err
+	}
+	if participation == nil {
+		return nil, ErrFulfillmentNotFound
+	}
+
+	var fulfillment *models.Fulfillment
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Confirming changes what the campaign's settlement sees
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, participation.CampaignID.String())); err != nil {
+			return err
+		}
+
+		var err error
+		fulfillment, err = s.fulfillmentRepo.FindForUpdate(ctx, tx, participationID)
+		if err != nil {
+			return fmt.Errorf("failed to load fulfillment: %w", err)
+		}
+		now := s.clock.Now()
+		if err := checkAnswerable(fulfillment, userID, now); err != nil {
+			return err
+		}
+
+		fulfillment.Status = models.FulfillmentConfirmed
+		fulfillment.RespondedAt = &now
+		if err := s.fulfillmentRepo.Respond(ctx, tx, fulfillment); err != nil {
+			return fmt.Errorf("failed to record fulfillment response: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment, nil
+}
+
+// Dispute contests the fulfillment of the user's participation and opens a
+// dispute of it, holding up the campaign's settlement until an admin
+// resolves it or the merchant marks it again
+func (s *FulfillmentService) Dispute(ctx context.Context, userID, participationID uuid.UUID, reason string) (*models.Fulfillment, error) {
+	fulfillment, _, err := s.disputes.disputeFulfillment(ctx, userID, participationID, reason)
+	return fulfillment, err
+}
+
+// checkAnswerable lets participants answer a fulfillment of their own while
+// its confirmation window is open. Fulfillments of other users are
+// reported as not found.
+func checkAnswerable(f *models.Fulfillment, userID uuid.UUID, now time.Time) error {
+	if f == nil || f.UserID != userID {
+		return ErrFulfillmentNotFound
+	}
+	if f.Status != models.FulfillmentFulfilled {
+		return ErrFulfillmentAnswered
+	}
+	if !now.Before(f.ConfirmDeadline) {
+		return ErrConfirmWindowClosed
+	}
+	return nil
+}
+
+// ListUserFulfillments returns one page of the user's fulfillments, newest
+// first, and their total
+func (s *FulfillmentService) ListUserFulfillments(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Fulfillment, int64, error) {
+	fulfillments, total, err := s.fulfillmentRepo.FindByUser(ctx, userID, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fulfillments: %w", err)
+	}
+	return fulfillments, total, nil
+}
+
+// ListCampaignFulfillments returns one page of the campaign's
+// fulfillments, of status unless it is empty, to its merchant or ops
+func (s *FulfillmentService) ListCampaignFulfillments(ctx context.Context, campaignID uuid.UUID, status string, page pagination.Page) ([]*models.Fulfillment, int64, error) {
+	if _, err := s.ownedCampaign(ctx, campaignID); err != nil {
+		return nil, 0, err
+	}
+	fulfillments, total, err := s.fulfillmentRepo.FindByCampaign(ctx, campaignID, status, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list fulfillments: %w", err)
+	}
+	return fulfillments, total, nil
+}
+
+// Summary counts the campaign's participations by fulfillment state for
+// its merchant, ops and internal services. tx-helper only builds the
+// settlement transaction of a settleable campaign.
+func (s *FulfillmentService) Summary(ctx context.Context, campaignID uuid.UUID) (*models.FulfillmentSummary, error) {
+	campaign, err := s.ownedCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := fulfillmentSummary(ctx, s.fulfillmentRepo, s.db, campaign, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	summary.Settleable = summary.Settleable && s.campaigns.Can(campaign.Status, models.StatusSettled)
+	return summary, nil
+}
+
+func (s *FulfillmentService) ownedCampaign(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	campaign, err := s.campaignRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign: %w", err)
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	if err := authorizeCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// fulfillmentSummary summarizes campaign's fulfillments at now, reading
+// through q so settlement can check them inside its transaction
+func fulfillmentSummary(ctx context.Context, repo *repository.FulfillmentRepository, q sqlx.QueryerContext, campaign *models.Campaign, now time.Time) (*models.FulfillmentSummary, error) {
+	summary, err := repo.Summary(ctx, q, campaign.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize fulfillments: %w", err)
+	}
+	summary.CampaignStatus = campaign.Status
+	summary.ChainAddress = campaign.ChainAddress
+	summary.Settleable = summary.Unfulfilled == 0 && summary.AwaitingResponse == 0 && summary.Disputed == 0
+	return summary, nil
+}
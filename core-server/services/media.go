@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/media"
+	"r2s/pkg/models"
+	"r2s/pkg/objectstore"
+	"r2s/pkg/rbac"
+)
+
+var (
+	ErrMediaDisabled       = apperrors.Catalog(apperrors.ReasonMediaDisabled)
+	ErrMediaType           = apperrors.Catalog(apperrors.ReasonMediaType)
+	ErrMediaNotFound       = apperrors.Catalog(apperrors.ReasonMediaNotFound)
+	ErrMediaNotUploaded    = apperrors.Catalog(apperrors.ReasonMediaNotUploaded)
+	ErrMediaUploadExpired  = apperrors.Catalog(apperrors.ReasonMediaUploadExpired)
+	errMediaOwnerRequired  = apperrors.Forbidden("uploads need a user account")
+	errMerchantMediaAccess = apperrors.Catalog(apperrors.ReasonRoleRequired, models.RoleMerchant+" or "+models.RoleOps)
+)
+
+// mediaPurposes are the accepted upload purposes
+var mediaPurposes = map[models.MediaPurpose]bool{
+	models.MediaCampaignImage:    true,
+	models.MediaMerchantLogo:     true,
+	models.MediaFulfillmentProof: true,
+}
+
+// MediaUploadTicket is what a client needs to upload a file: a PUT of the
+// file to URL with Headers, before the upload's ExpiresAt
+type MediaUploadTicket struct {
+	Upload  *models.MediaUpload `json:"upload"`
+	Method  string              `json:"method"`
+	URL     string              `json:"url"`
+	Headers map[string]string   `json:"headers"`
+}
+
+// MediaService takes campaign images, merchant logos and fulfillment proof
+// photos. Clients upload straight to object storage through a pre-signed
+// URL, then complete the upload: the file is checked from its content,
+// stored under media/ with a thumbnail and served from the public base URL.
+type MediaService struct {
+	db           *database.DB
+	repo         *repository.MediaRepository
+	merchantRepo *repository.MerchantRepository
+	store        objectstore.Store
+	cfg          media.Config
+	audit        *audit.Store
+	clock        clock.Clock
+}
+
+// NewMediaService takes a nil store when uploads are off
+func NewMediaService(db *database.DB, store objectstore.Store, cfg media.Config, clk clock.Clock) *MediaService {
+	clk = clock.OrSystem(clk)
+	return &MediaService{
+		db:           db,
+		repo:         repository.NewMediaRepository(db),
+		merchantRepo: repository.NewMerchantRepository(db),
+		store:        store,
+		cfg:          cfg,
+		audit:        audit.NewStore(db, clk),
+		clock:        clk,
+	}
+}
+
+// CreateUpload opens an upload of size bytes of contentType for the caller
+// and returns where to upload the file
+func (s *MediaService) CreateUpload(ctx context.Context, purpose models.MediaPurpose, contentType string, size int64) (*MediaUploadTicket, error) {
+	presigner, ok := s.store.(objectstore.Presigner)
+	if !ok || !s.cfg.Enabled() {
+		return nil, ErrMediaDisabled
+	}
+	if !mediaPurposes[purpose] {
+		return nil, apperrors.InvalidArgument("purpose must be campaign_image, merchant_logo or fulfillment_proof")
+	}
+	if purpose != models.MediaMerchantLogo && !rbac.Allows(rbac.RoleFrom(ctx), models.RoleMerchant, models.RoleOps) {
+		return nil, errMerchantMediaAccess
+	}
+	if _, ok := media.ContentTypes[contentType]; !ok {
+		return nil, ErrMediaType
+	}
+	if size <= 0 || size > s.cfg.MaxBytes {
+		return nil, apperrors.Catalog(apperrors.ReasonMediaTooLarge, s.cfg.MaxBytes>>20)
+	}
+	ownerID, err := uuid.Parse(audit.ActorFrom(ctx).ID)
+	if err != nil {
+		return nil, errMediaOwnerRequired
+	}
+
+	now := s.clock.Now()
+	id := uuid.New()
+	upload := &models.MediaUpload{
+		ID:          id,
+		OwnerID:     ownerID,
+		Purpose:     purpose,
+		Status:      models.MediaPending,
+		ContentType: contentType,
+		Size:        size,
+		UploadKey:   "uploads/" + id.String(),
+		ExpiresAt:   now.Add(s.cfg.UploadTTL),
+		CreatedAt:   now,
+	}
+	url, err := presigner.PresignPut(ctx, upload.UploadKey, s.cfg.UploadTTL)
+	if err != nil {
+		return nil, apperrors.Unavailable(err, "Failed to prepare the upload")
+	}
+	if err := s.repo.Create(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to create upload: %w", err)
+	}
+	return &MediaUploadTicket{
+		Upload:  upload,
+		Method:  http.MethodPut,
+		URL:     url,
+		Headers: map[string]string{"Content-Type": contentType},
+	}, nil
+}
+
+// GetUpload returns one of the caller's uploads
+func (s *MediaService) GetUpload(ctx context.Context, id uuid.UUID) (*models.MediaUpload, error) {
+	upload, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load upload: %w", err)
+	}
+	if upload == nil || !ownsUpload(ctx, upload) {
+		return nil, ErrMediaNotFound
+	}
+	return upload, nil
+}
+
+// CompleteUpload checks the uploaded file and publishes it with a
+// thumbnail. A merchant logo replaces the merchant's logo right away;
+// campaign images are used by passing the upload's ID as a campaign's
+// imageId. Completing a completed upload returns it unchanged.
+func (s *MediaService) CompleteUpload(ctx context.Context, id uuid.UUID) (*models.MediaUpload, error) {
+	if s.store == nil || !s.cfg.Enabled() {
+		return nil, ErrMediaDisabled
+	}
+	upload, err := s.GetUpload(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == models.MediaReady {
+		return upload, nil
+	}
+	if s.clock.Now().After(upload.ExpiresAt) {
+		return nil, ErrMediaUploadExpired
+	}
+
+	content, err := s.readUpload(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	contentType := http.DetectContentType(content)
+	ext, ok := media.ContentTypes[contentType]
+	if !ok {
+		return nil, ErrMediaType
+	}
+	thumb, thumbType, err := media.Thumbnail(bytes.NewReader(content), s.cfg.ThumbnailSize)
+	if errors.Is(err, media.ErrUnsupportedImage) {
+		return nil, ErrMediaType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir := path.Join("media", string(upload.Purpose), upload.ID.String())
+	key, thumbKey := dir+"/original"+ext, dir+"/thumbnail"+media.ContentTypes[thumbType]
+	if err := s.store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	if err := s.store.Put(ctx, thumbKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+		return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	now := s.clock.Now()
+	url, thumbURL := s.cfg.URL(key), s.cfg.URL(thumbKey)
+	upload.Status = models.MediaReady
+	upload.ContentType = contentType
+	upload.Size = int64(len(content))
+	upload.URL = &url
+	upload.ThumbnailURL = &thumbURL
+	upload.CompletedAt = &now
+
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := s.repo.Complete(ctx, tx, upload); err != nil {
+			return fmt.Errorf("failed to complete upload: %w", err)
+		}
+		if upload.Purpose == models.MediaMerchantLogo {
+			return s.setLogo(ctx, tx, upload)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The client could replace the file at the upload URL until it expires,
+	// so only the checked copy is kept
+	if err := s.store.Delete(ctx, upload.UploadKey); err != nil {
+		logger.FromContext(ctx).Warn("failed to delete uploaded file", "upload_id", upload.ID, "error", err)
+	}
+	return upload, nil
+}
+
+// readUpload reads the file the client uploaded, refusing files over the
+// size limit before reading them
+func (s *MediaService) readUpload(ctx context.Context, upload *models.MediaUpload) ([]byte, error) {
+	size, err := s.store.Stat(ctx, upload.UploadKey)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, ErrMediaNotUploaded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat upload: %w", err)
+	}
+	if size <= 0 || size > s.cfg.MaxBytes {
+		return nil, apperrors.Catalog(apperrors.ReasonMediaTooLarge, s.cfg.MaxBytes>>20)
+	}
+
+	r, err := s.store.Open(ctx, upload.UploadKey)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, ErrMediaNotUploaded
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload: %w", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(content)) > s.cfg.MaxBytes {
+		return nil, apperrors.Catalog(apperrors.ReasonMediaTooLarge, s.cfg.MaxBytes>>20)
+	}
+	return content, nil
+}
+
+// setLogo makes a completed logo upload the owner's merchant logo
+func (s *MediaService) setLogo(ctx context.Context, tx *sqlx.Tx, upload *models.MediaUpload) error {
+	merchant, err := s.merchantRepo.FindForUpdate(ctx, tx, upload.OwnerID)
+	if err != nil {
+		return fmt.Errorf("failed to load merchant: %w", err)
+	}
+	if merchant == nil {
+		return ErrMerchantNotFound
+	}
+	before := *merchant
+	merchant.LogoURL = upload.URL
+	merchant.LogoThumbnailURL = upload.ThumbnailURL
+	if err := s.merchantRepo.SetLogo(ctx, tx, merchant.ID, *upload.URL, *upload.ThumbnailURL); err != nil {
+		return fmt.Errorf("failed to set merchant logo: %w", err)
+	}
+	return s.audit.Record(ctx, tx, audit.Change{
+		Action:       audit.ActionMerchantLogo,
+		ResourceType: audit.ResourceMerchant,
+		ResourceID:   merchant.ID.String(),
+		Before:       &before,
+		After:        merchant,
+	})
+}
+
+// ownsUpload reports whether the caller uploaded u; ops may use any upload
+func ownsUpload(ctx context.Context, u *models.MediaUpload) bool {
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return true
+	}
+	return u.OwnerID.String() == audit.ActorFrom(ctx).ID
+}
+
+// readyImage returns the URLs of a completed upload of purpose the caller
+// may use
+func readyImage(ctx context.Context, repo *repository.MediaRepository, id uuid.UUID, purpose models.MediaPurpose) (url, thumbnailURL *string, err error) {
+	upload, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load upload: %w", err)
+	}
+	if upload == nil || !ownsUpload(ctx, upload) || upload.Purpose != purpose || upload.Status != models.MediaReady {
+		return nil, nil, apperrors.Catalog(apperrors.ReasonMediaNotReady, string(purpose))
+	}
+	return upload.URL, upload.ThumbnailURL, nil
+}
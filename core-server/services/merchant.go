@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/address"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/rbac"
+	"r2s/pkg/statemachine"
+)
+
+var (
+	ErrMerchantNotFound    = apperrors.Catalog(apperrors.ReasonMerchantNotFound)
+	ErrMerchantRegistered  = apperrors.Catalog(apperrors.ReasonMerchantRegistered)
+	ErrMerchantNotApproved = apperrors.Catalog(apperrors.ReasonMerchantNotApproved)
+	ErrFeeNotAgreed        = apperrors.Catalog(apperrors.ReasonFeeNotAgreed, models.DefaultMerchantFeeBps)
+	ErrFeeOnlyOnApproval   = apperrors.Catalog(apperrors.ReasonFeeOnlyOnApproval)
+	ErrNotOwnMerchant      = apperrors.Catalog(apperrors.ReasonNotOwnMerchant)
+)
+
+// RegisterMerchantInput is a business registration. AcceptedFeeBps is the
+// fee the applicant was shown and agreed to; it must match the current fee.
+type RegisterMerchantInput struct {
+	BusinessName       string
+	RegistrationNumber *string
+	ContactEmail       string
+	ContactPhone       *string
+	Website            *string
+	Country            string
+	PayoutWallet       string
+	AcceptedFeeBps     int
+}
+
+// DashboardRange bounds the period figures of the merchant dashboard:
+// upcoming settlements by end time and settled campaigns by settlement
+// date, From inclusive and To exclusive. A zero time leaves that end open.
+type DashboardRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// Payout splits deposits into the merchant and ops fees and what the
+// merchant is paid. Rebates come from the rebate pool, not the merchant.
+type Payout struct {
+	Gross       models.BigInt `json:"gross"`
+	MerchantFee models.BigInt `json:"merchantFee"`
+	OpsFee      models.BigInt `json:"opsFee"`
+	Net         models.BigInt `json:"net"`
+}
+
+// CampaignPayout is a campaign's payout, expected or settled
+type CampaignPayout struct {
+	Campaign *repository.DashboardCampaign `json:"campaign"`
+	Payout   Payout                        `json:"payout"`
+}
+
+// PayoutSummary totals the payouts of several campaigns
+type PayoutSummary struct {
+	Total     Payout           `json:"total"`
+	Campaigns []CampaignPayout `json:"campaigns"`
+}
+
+// MerchantDashboard is a merchant's view of their campaigns and money.
+// Active campaigns are still recruiting (or paused); pending fulfillments
+// reached their goal and wait for the merchant to deliver. Escrowed is the
+// deposits both hold. Upcoming settlements are the reached and fulfillment
+// campaigns, which are paid out once settled.
+type MerchantDashboard struct {
+	MerchantID          uuid.UUID                       `json:"merchantId"`
+	Campaigns           map[string]int64                `json:"campaigns"`
+	ActiveCampaigns     []*repository.DashboardCampaign `json:"activeCampaigns"`
+	PendingFulfillments []*repository.DashboardCampaign `json:"pendingFulfillments"`
+	Escrowed            models.BigInt                   `json:"escrowed"`
+	UpcomingSettlements PayoutSummary                   `json:"upcomingSettlements"`
+	Settled             PayoutSummary                   `json:"settled"`
+}
+
+// MerchantService onboards merchants: users register a business, admins
+// review the registration and may later suspend it. Approval grants the
+// merchant role and suspension revokes it; both end the user's sessions so
+// the change applies at once.
+type MerchantService struct {
+	db           *database.DB
+	merchantRepo *repository.MerchantRepository
+	adminRepo    *repository.AdminRepository
+	clock        clock.Clock
+	audit        *audit.Store
+	merchants    *statemachine.Machine[models.MerchantStatus]
+}
+
+func NewMerchantService(db *database.DB, clk clock.Clock) *MerchantService {
+	return &MerchantService{
+		db:           db,
+		merchantRepo: repository.NewMerchantRepository(db),
+		adminRepo:    repository.NewAdminRepository(db),
+		clock:        clock.OrSystem(clk),
+		audit:        audit.NewStore(db, clk),
+		merchants:    statemachine.NewMerchant().OnTransition(statemachine.LogHistory[models.MerchantStatus]()),
+	}
+}
+
+// Register stores the caller's registration for review. A rejected
+// registration may be resubmitted with corrected details.
+func (s *MerchantService) Register(ctx context.Context, userID uuid.UUID, in RegisterMerchantInput) (*models.Merchant, error) {
+	if in.AcceptedFeeBps != models.DefaultMerchantFeeBps {
+		return nil, ErrFeeNotAgreed
+	}
+
+	now := s.clock.Now()
+	merchant := &models.Merchant{
+		ID:                 userID,
+		BusinessName:       in.BusinessName,
+		RegistrationNumber: in.RegistrationNumber,
+		ContactEmail:       in.ContactEmail,
+		ContactPhone:       in.ContactPhone,
+		Website:            in.Website,
+		Country:            in.Country,
+		PayoutWallet:       address.Display(in.PayoutWallet),
+		FeeBps:             in.AcceptedFeeBps,
+		FeeAgreedAt:        now,
+		Status:             models.MerchantPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		existing, err := s.merchantRepo.FindForUpdate(ctx, tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to load merchant: %w", err)
+		}
+
+		var before interface{}
+		if existing == nil {
+			if err := s.merchantRepo.Create(ctx, tx, merchant); err != nil {
+				return fmt.Errorf("failed to create merchant: %w", err)
+			}
+		} else {
+			if existing.Status != models.MerchantRejected {
+				return ErrMerchantRegistered
+			}
+			if err := s.merchants.Transition(ctx, userID.String(), existing.Status, models.MerchantPending); err != nil {
+				return err
+			}
+			merchant.CreatedAt = existing.CreatedAt
+			if err := s.merchantRepo.Resubmit(ctx, tx, merchant); err != nil {
+				return fmt.Errorf("failed to resubmit merchant: %w", err)
+			}
+			before = existing
+		}
+
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionMerchantRegister,
+			ResourceType: audit.ResourceMerchant,
+			ResourceID:   userID.String(),
+			Before:       before,
+			After:        merchant,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// GetMerchant returns a merchant registration
+func (s *MerchantService) GetMerchant(ctx context.Context, id uuid.UUID) (*models.Merchant, error) {
+	merchant, err := s.merchantRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load merchant: %w", err)
+	}
+	if merchant == nil {
+		return nil, ErrMerchantNotFound
+	}
+	return merchant, nil
+}
+
+// Dashboard summarises the merchant's campaigns, escrow and payouts.
+// Merchants may only see their own; ops and admins see any.
+func (s *MerchantService) Dashboard(ctx context.Context, id uuid.UUID, r DashboardRange) (*MerchantDashboard, error) {
+	if !rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) && id.String() != audit.ActorFrom(ctx).ID {
+		return nil, ErrNotOwnMerchant
+	}
+
+	counts, err := s.merchantRepo.CountCampaignsByStatus(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count campaigns: %w", err)
+	}
+	open, err := s.merchantRepo.OpenCampaigns(ctx, id, models.ActiveStatuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load open campaigns: %w", err)
+	}
+	settled, err := s.merchantRepo.SettledCampaigns(ctx, id, r.From, r.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settled campaigns: %w", err)
+	}
+
+	d := &MerchantDashboard{
+		MerchantID:          id,
+		Campaigns:           counts,
+		ActiveCampaigns:     []*repository.DashboardCampaign{},
+		PendingFulfillments: []*repository.DashboardCampaign{},
+		Escrowed:            models.NewBigInt(new(big.Int)),
+	}
+	var upcoming []*repository.DashboardCampaign
+	for _, c := range open {
+		d.Escrowed.Add(d.Escrowed.Int, c.Amount.Big())
+		if c.Status == models.StatusFulfillment {
+			d.PendingFulfillments = append(d.PendingFulfillments, c)
+		} else {
+			d.ActiveCampaigns = append(d.ActiveCampaigns, c)
+		}
+		settles := c.Status == models.StatusReached || c.Status == models.StatusFulfillment
+		if settles && (r.From.IsZero() || !c.EndTime.Before(r.From)) && (r.To.IsZero() || c.EndTime.Before(r.To)) {
+			upcoming = append(upcoming, c)
+		}
+	}
+	d.UpcomingSettlements = summarizePayouts(upcoming)
+	d.Settled = summarizePayouts(settled)
+	return d, nil
+}
+
+// summarizePayouts computes each campaign's payout from its deposits and
+// fees, and their total
+func summarizePayouts(campaigns []*repository.DashboardCampaign) PayoutSummary {
+	gross, merchantFee, opsFee, net := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+	summary := PayoutSummary{Campaigns: make([]CampaignPayout, 0, len(campaigns))}
+	for _, c := range campaigns {
+		amount := money.New(c.Amount.Big(), money.USDT)
+		merchantFeeUnits := amount.MulBps(c.MerchantFeeBps).Units()
+		opsFeeUnits := amount.MulBps(c.OpsFeeBps).Units()
+		netUnits := amount.Units()
+		netUnits.Sub(netUnits, merchantFeeUnits)
+		netUnits.Sub(netUnits, opsFeeUnits)
+		p := Payout{
+			Gross:       models.NewBigInt(amount.Units()),
+			MerchantFee: models.NewBigInt(merchantFeeUnits),
+			OpsFee:      models.NewBigInt(opsFeeUnits),
+			Net:         models.NewBigInt(netUnits),
+		}
+		summary.Campaigns = append(summary.Campaigns, CampaignPayout{Campaign: c, Payout: p})
+
+		gross.Add(gross, p.Gross.Int)
+		merchantFee.Add(merchantFee, p.MerchantFee.Int)
+		opsFee.Add(opsFee, p.OpsFee.Int)
+		net.Add(net, p.Net.Int)
+	}
+	summary.Total = Payout{
+		Gross:       models.NewBigInt(gross),
+		MerchantFee: models.NewBigInt(merchantFee),
+		OpsFee:      models.NewBigInt(opsFee),
+		Net:         models.NewBigInt(net),
+	}
+	return summary
+}
+
+// ListMerchants searches merchant registrations
+func (s *MerchantService) ListMerchants(ctx context.Context, f repository.MerchantFilter) ([]*models.Merchant, int64, error) {
+	merchants, total, err := s.merchantRepo.List(ctx, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list merchants: %w", err)
+	}
+	return merchants, total, nil
+}
+
+// SetStatus moves a registration along MerchantTable on behalf of the
+// admin in ctx. feeBps may override the agreed fee on approval.
+func (s *MerchantService) SetStatus(ctx context.Context, id uuid.UUID, to models.MerchantStatus, reason string, feeBps *int) (*models.Merchant, error) {
+	if feeBps != nil && to != models.MerchantApproved {
+		return nil, ErrFeeOnlyOnApproval
+	}
+
+	var merchant *models.Merchant
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		merchant, err = s.merchantRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load merchant: %w", err)
+		}
+		if merchant == nil {
+			return ErrMerchantNotFound
+		}
+		if err := s.merchants.Transition(ctx, id.String(), merchant.Status, to); err != nil {
+			return err
+		}
+
+		action := merchantAction(merchant.Status, to)
+		before := map[string]interface{}{"status": merchant.Status, "feeBps": merchant.FeeBps}
+
+		now := s.clock.Now()
+		merchant.Status = to
+		merchant.StatusReason = nil
+		if reason != "" {
+			merchant.StatusReason = &reason
+		}
+		if feeBps != nil {
+			merchant.FeeBps = *feeBps
+		}
+		if actor, err := uuid.Parse(audit.ActorFrom(ctx).ID); err == nil {
+			merchant.ReviewedBy = &actor
+		}
+		merchant.ReviewedAt = &now
+		merchant.UpdatedAt = now
+		if err := s.merchantRepo.Review(ctx, tx, merchant); err != nil {
+			return fmt.Errorf("failed to update merchant: %w", err)
+		}
+
+		role, err := s.syncRole(ctx, tx, id, to)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       action,
+			ResourceType: audit.ResourceMerchant,
+			ResourceID:   id.String(),
+			Before:       before,
+			After:        map[string]interface{}{"status": to, "feeBps": merchant.FeeBps, "role": role, "reason": reason},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// syncRole grants the merchant role to plain users on approval and takes it
+// back on suspension, ending their sessions. Ops and admins keep their
+// role. It returns the user's role afterwards.
+func (s *MerchantService) syncRole(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status models.MerchantStatus) (string, error) {
+	user, err := s.adminRepo.FindUserForUpdate(ctx, tx, id)
+	if err != nil {
+		return "", fmt.Errorf("failed to load user: %w", err)
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+
+	role := user.Role
+	switch {
+	case status == models.MerchantApproved && user.Role == models.RoleUser:
+		role = models.RoleMerchant
+	case status == models.MerchantSuspended && user.Role == models.RoleMerchant:
+		role = models.RoleUser
+	default:
+		return role, nil
+	}
+
+	if err := s.adminRepo.SetUserRole(ctx, tx, id, role); err != nil {
+		return "", fmt.Errorf("failed to set user role: %w", err)
+	}
+	if err := s.adminRepo.DeleteUserSessions(ctx, tx, id); err != nil {
+		return "", fmt.Errorf("failed to end user sessions: %w", err)
+	}
+	return role, nil
+}
+
+// merchantAction names the audit action of a status change
+func merchantAction(from, to models.MerchantStatus) string {
+	switch to {
+	case models.MerchantApproved:
+		if from == models.MerchantSuspended {
+			return audit.ActionMerchantReinstate
+		}
+		return audit.ActionMerchantApprove
+	case models.MerchantRejected:
+		return audit.ActionMerchantReject
+	default:
+		return audit.ActionMerchantSuspend
+	}
+}
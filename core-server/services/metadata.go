@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/metadata"
+	"r2s/pkg/models"
+)
+
+// publishTimeout bounds one background publish, which runs after the
+// request returned
+const publishTimeout = time.Minute
+
+// metadataSchemaVersion is bumped when the document layout changes, which
+// republishes every campaign on its next publish
+const metadataSchemaVersion = 1
+
+var ErrMetadataDisabled = apperrors.Catalog(apperrors.ReasonMetadataDisabled)
+
+// MetadataService renders the canonical metadata document of a campaign and
+// publishes it so the campaign's on-chain metadata URI resolves. Campaign
+// changes publish in the background after they committed; a failed publish
+// is logged and retried with Publish.
+type MetadataService struct {
+	db        *database.DB
+	repo      *repository.CampaignRepository
+	publisher metadata.Publisher
+	clock     clock.Clock
+}
+
+func NewMetadataService(db *database.DB, publisher metadata.Publisher, clk clock.Clock) *MetadataService {
+	return &MetadataService{
+		db:        db,
+		repo:      repository.NewCampaignRepository(db),
+		publisher: publisher,
+		clock:     clock.OrSystem(clk),
+	}
+}
+
+// campaignDocument follows the ERC-721/1155 metadata layout wallets and
+// explorers read. Only fields fixed by the merchant are included, so
+// participation does not change the document.
+type campaignDocument struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Image       string             `json:"image,omitempty"`
+	Properties  campaignProperties `json:"properties"`
+}
+
+type campaignProperties struct {
+	SchemaVersion  int           `json:"schema_version"`
+	CampaignID     uuid.UUID     `json:"campaign_id"`
+	ChainAddress   string        `json:"chain_address,omitempty"`
+	MerchantWallet string        `json:"merchant_wallet"`
+	BasePrice      models.BigInt `json:"base_price"`
+	MinQty         int           `json:"min_qty"`
+	TargetAmount   models.BigInt `json:"target_amount"`
+	DiscountRate   int           `json:"discount_rate"`
+	SaveFloorBps   int           `json:"save_floor_bps"`
+	RMaxBps        int           `json:"r_max_bps"`
+	MerchantFeeBps int           `json:"merchant_fee_bps"`
+	OpsFeeBps      int           `json:"ops_fee_bps"`
+	StartTime      time.Time     `json:"start_time"`
+	EndTime        time.Time     `json:"end_time"`
+	// Terms is the merchant's terms from the campaign metadata ("terms")
+	Terms interface{} `json:"terms,omitempty"`
+}
+
+// RenderMetadata returns the canonical metadata document of c and its
+// SHA-256. The same campaign always renders to the same bytes.
+func RenderMetadata(c *models.Campaign) ([]byte, string, error) {
+	d := campaignDocument{
+		Name: c.Title,
+		Properties: campaignProperties{
+			SchemaVersion:  metadataSchemaVersion,
+			CampaignID:     c.ID,
+			ChainAddress:   c.ChainAddress,
+			MerchantWallet: c.MerchantWallet,
+			BasePrice:      c.BasePrice,
+			MinQty:         c.MinQty,
+			TargetAmount:   c.TargetAmount,
+			DiscountRate:   c.DiscountRate,
+			SaveFloorBps:   c.SaveFloorBps,
+			RMaxBps:        c.RMaxBps,
+			MerchantFeeBps: c.MerchantFeeBps,
+			OpsFeeBps:      c.OpsFeeBps,
+			StartTime:      c.StartTime.UTC(),
+			EndTime:        c.EndTime.UTC(),
+			Terms:          c.Metadata["terms"],
+		},
+	}
+	if c.Description != nil {
+		d.Description = *c.Description
+	}
+	if c.ImageURL != nil {
+		d.Image = *c.ImageURL
+	}
+
+	// encoding/json writes struct fields in order and map keys sorted
+	doc, err := json.Marshal(d)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to render campaign metadata: %w", err)
+	}
+	sum := sha256.Sum256(doc)
+	return doc, hex.EncodeToString(sum[:]), nil
+}
+
+// Publish renders the campaign's metadata and publishes it unless the
+// published document is already current, then records its URI and hash.
+// Publishes of one campaign are serialised, so the last one to finish always
+// reflects the latest campaign.
+func (s *MetadataService) Publish(ctx context.Context, id uuid.UUID) (*models.Campaign, error) {
+	var campaign *models.Campaign
+
+	// The transaction only holds the advisory lock during the upload; the
+	// campaign row itself stays unlocked
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockMetadata, id.String())); err != nil {
+			return err
+		}
+
+		var err error
+		campaign, err = s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+		if err := authorizeCampaign(ctx, campaign); err != nil {
+			return err
+		}
+
+		doc, hash, err := RenderMetadata(campaign)
+		if err != nil {
+			return err
+		}
+		if campaign.MetadataURI != nil && campaign.MetadataHash != nil && *campaign.MetadataHash == hash {
+			return nil
+		}
+
+		uri, err := s.publisher.Publish(ctx, fmt.Sprintf("campaigns/%s/metadata.json", id), doc)
+		if errors.Is(err, metadata.ErrDisabled) {
+			return ErrMetadataDisabled
+		}
+		if err != nil {
+			return apperrors.Unavailable(err, "metadata storage is unavailable")
+		}
+
+		now := s.clock.Now()
+		if err := s.repo.SetMetadataURI(ctx, tx, id, uri, hash, now); err != nil {
+			return fmt.Errorf("failed to record metadata uri: %w", err)
+		}
+		campaign.MetadataURI = &uri
+		campaign.MetadataHash = &hash
+		campaign.MetadataPublishedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// PublishAsync publishes in the background after a campaign change
+// committed. The request context's values are kept but not its
+// cancellation, since the request finishes first.
+func (s *MetadataService) PublishAsync(ctx context.Context, id uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+	go func() {
+		defer cancel()
+		campaign, err := s.Publish(ctx, id)
+		log := logger.FromContext(ctx).With(logger.KeyCampaignID, id)
+		switch {
+		case errors.Is(err, ErrMetadataDisabled):
+		case err != nil:
+			log.Error("Failed to publish campaign metadata", "error", err)
+		default:
+			log.Debug("Campaign metadata published", "uri", *campaign.MetadataURI)
+		}
+	}()
+}
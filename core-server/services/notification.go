@@ -0,0 +1,515 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"r2s/core-server/repository"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/metrics"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/pagination"
+	"r2s/pkg/push"
+)
+
+// pushTimeout bounds one fan-out, which runs after the request returned
+const pushTimeout = time.Minute
+
+// maxDeviceTokenLength is well above FCM's token length; longer input is
+// not a token
+const maxDeviceTokenLength = 4096
+
+var (
+	ErrDeviceNotFound  = apperrors.Catalog(apperrors.ReasonDeviceNotFound)
+	ErrInvalidPlatform = apperrors.Catalog(apperrors.ReasonInvalidPlatform)
+	ErrInvalidToken    = apperrors.Catalog(apperrors.ReasonInvalidDeviceToken)
+)
+
+// UpdatePreferencesInput changes the topics that are set
+type UpdatePreferencesInput struct {
+	CampaignMilestones *bool
+	RebatePayouts      *bool
+	Watchlist          *bool
+	Cancellations      *bool
+	Line               *bool
+	Email              *bool
+}
+
+// Rebate is one participant's settled rebate
+type Rebate struct {
+	UserID uuid.UUID
+	Amount *big.Int
+}
+
+// NotificationService manages push devices and preferences and sends
+// campaign milestone and rebate pushes. Pushes are sent in the background
+// after the change committed; a failed push is logged, never returned.
+// The same events are queued as LINE messages and emails, which
+// batch-server delivers.
+type NotificationService struct {
+	repo   *repository.NotificationRepository
+	sender push.Sender
+	clock  clock.Clock
+}
+
+func NewNotificationService(db *database.DB, sender push.Sender, clk clock.Clock) *NotificationService {
+	return &NotificationService{
+		repo:   repository.NewNotificationRepository(db),
+		sender: sender,
+		clock:  clock.OrSystem(clk),
+	}
+}
+
+// RegisterDevice stores a device token for the user
+func (s *NotificationService) RegisterDevice(ctx context.Context, userID uuid.UUID, token, platform string) (*models.Device, error) {
+	switch platform {
+	case models.PlatformWeb, models.PlatformIOS, models.PlatformAndroid:
+	default:
+		return nil, ErrInvalidPlatform
+	}
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return nil, ErrInvalidToken
+	}
+
+	device := &models.Device{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Token:      token,
+		Platform:   platform,
+		LastSeenAt: s.clock.Now(),
+	}
+	if err := s.repo.UpsertDevice(ctx, device); err != nil {
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+	return device, nil
+}
+
+// UnregisterDevice removes one of the user's device tokens
+func (s *NotificationService) UnregisterDevice(ctx context.Context, userID uuid.UUID, token string) error {
+	found, err := s.repo.DeleteDevice(ctx, userID, token)
+	if err != nil {
+		return fmt.Errorf("failed to unregister device: %w", err)
+	}
+	if !found {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// ListDevices returns the user's registered devices
+func (s *NotificationService) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.Device, error) {
+	devices, err := s.repo.FindDevices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	return devices, nil
+}
+
+// GetPreferences returns the user's preferences, defaulting to every topic
+func (s *NotificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	prefs, err := s.repo.FindPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	if prefs == nil {
+		prefs = &models.NotificationPreferences{
+			UserID:             userID,
+			CampaignMilestones: true,
+			RebatePayouts:      true,
+			Watchlist:          true,
+			Cancellations:      true,
+			Line:               true,
+			Email:              true,
+		}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences changes the topics set in in and returns the result
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, in UpdatePreferencesInput) (*models.NotificationPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if in.CampaignMilestones != nil {
+		prefs.CampaignMilestones = *in.CampaignMilestones
+	}
+	if in.RebatePayouts != nil {
+		prefs.RebatePayouts = *in.RebatePayouts
+	}
+	if in.Watchlist != nil {
+		prefs.Watchlist = *in.Watchlist
+	}
+	if in.Cancellations != nil {
+		prefs.Cancellations = *in.Cancellations
+	}
+	if in.Line != nil {
+		prefs.Line = *in.Line
+	}
+	if in.Email != nil {
+		prefs.Email = *in.Email
+	}
+	prefs.UpdatedAt = s.clock.Now()
+
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// ListDeliveries returns one page of the LINE messages and emails sent or
+// queued for the user, and the user's total count
+func (s *NotificationService) ListDeliveries(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.NotificationDelivery, int64, error) {
+	deliveries, total, err := s.repo.FindDeliveries(ctx, userID, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notification deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// CampaignReached tells the campaign's participants it reached its minimum
+// quantity and will go ahead
+func (s *NotificationService) CampaignReached(ctx context.Context, campaign *models.Campaign) {
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  "The campaign reached its goal and will go ahead.",
+		Data: map[string]string{
+			"type":        "campaign_reached",
+			"campaign_id": campaign.ID.String(),
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForCampaign(ctx, campaign.ID, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+
+	s.queue(ctx, func(ctx context.Context) (repository.Deliveries, error) {
+		userIDs, err := s.repo.ParticipantIDs(ctx, campaign.ID)
+		if err != nil {
+			return repository.Deliveries{}, err
+		}
+		bodies := make([]string, len(userIDs))
+		for i := range bodies {
+			bodies[i] = msg.Body
+		}
+		return repository.Deliveries{
+			EventKey: "campaign_reached:" + campaign.ID.String(),
+			Kind:     "campaign_reached",
+			Topic:    models.TopicCampaignMilestones,
+			Subject:  campaign.Title,
+			UserIDs:  userIDs,
+			Bodies:   bodies,
+		}, nil
+	})
+}
+
+// CancellationConfirmed tells a participant their cancel was applied and
+// what is refunded
+func (s *NotificationService) CancellationConfirmed(ctx context.Context, campaign *models.Campaign, p *models.Participation, quote CancelQuote) {
+	refund := money.New(quote.Refund.Big(), money.USDT)
+	body := fmt.Sprintf("Your cancellation was confirmed. %s will be refunded.", refund)
+	if p.Status == models.ParticipationActive {
+		remaining := money.New(p.DepositAmount.Big(), money.USDT)
+		body = fmt.Sprintf("Your partial cancellation was confirmed. %s will be refunded and %s stays in the campaign.", refund, remaining)
+	}
+
+	s.queue(ctx, func(context.Context) (repository.Deliveries, error) {
+		return repository.Deliveries{
+			EventKey: fmt.Sprintf("cancellation_confirmed:%s:%d", p.ID, p.Version),
+			Kind:     "cancellation_confirmed",
+			Topic:    models.TopicCancellations,
+			Subject:  campaign.Title,
+			UserIDs:  []uuid.UUID{p.UserID},
+			Bodies:   []string{body},
+		}, nil
+	})
+}
+
+// CampaignReviewed tells the merchant whether their campaign was approved
+func (s *NotificationService) CampaignReviewed(ctx context.Context, campaign *models.Campaign, review *models.CampaignReview) {
+	if campaign.MerchantID == nil {
+		return
+	}
+	body := "Your campaign was approved and can be deployed."
+	if review.Decision == models.ReviewRejected {
+		body = "Your campaign was not approved. See the review comment for details."
+	}
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  body,
+		Data: map[string]string{
+			"type":        "campaign_reviewed",
+			"campaign_id": campaign.ID.String(),
+			"decision":    review.Decision,
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, []uuid.UUID{*campaign.MerchantID}, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+}
+
+// RebatesSettled tells each participant the rebate they receive from a
+// settled campaign
+func (s *NotificationService) RebatesSettled(ctx context.Context, campaign *models.Campaign, rebates []Rebate) {
+	amounts := make(map[uuid.UUID]money.Amount, len(rebates))
+	userIDs := make([]uuid.UUID, 0, len(rebates))
+	for _, r := range rebates {
+		if r.Amount.Sign() <= 0 {
+			continue
+		}
+		amounts[r.UserID] = money.New(r.Amount, money.USDT)
+		userIDs = append(userIDs, r.UserID)
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	s.queue(ctx, func(context.Context) (repository.Deliveries, error) {
+		bodies := make([]string, len(userIDs))
+		for i, id := range userIDs {
+			bodies[i] = fmt.Sprintf("The campaign was settled. Your rebate of %s has been paid.", amounts[id])
+		}
+		return repository.Deliveries{
+			EventKey: "rebate_settled:" + campaign.ID.String(),
+			Kind:     "rebate_settled",
+			Topic:    models.TopicRebatePayouts,
+			Subject:  campaign.Title,
+			UserIDs:  userIDs,
+			Bodies:   bodies,
+		}, nil
+	})
+
+	s.fanOut(ctx, models.TopicRebatePayouts,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, userIDs, models.TopicRebatePayouts)
+		},
+		func(t repository.Target) push.Message {
+			amount := amounts[t.UserID]
+			return push.Message{
+				Title: campaign.Title,
+				Body:  fmt.Sprintf("Your rebate of %s has been settled.", amount),
+				Data: map[string]string{
+					"type":        "rebate_settled",
+					"campaign_id": campaign.ID.String(),
+					"amount":      amount.Decimal(),
+					"currency":    amount.Currency().Code,
+				},
+			}
+		})
+}
+
+// OrdersFulfilled asks the participants whose orders the merchant marked
+// fulfilled to confirm or dispute it before the deadline
+func (s *NotificationService) OrdersFulfilled(ctx context.Context, campaign *models.Campaign, fulfillments []*models.Fulfillment) {
+	if len(fulfillments) == 0 {
+		return
+	}
+	deadline := fulfillments[0].ConfirmDeadline
+	userIDs := make([]uuid.UUID, len(fulfillments))
+	bodies := make([]string, len(fulfillments))
+	body := fmt.Sprintf("Your order was marked fulfilled. Confirm it, or dispute it if you did not receive it, by %s.", deadline.UTC().Format("2006-01-02 15:04 MST"))
+	for i, f := range fulfillments {
+		userIDs[i] = f.UserID
+		bodies[i] = body
+	}
+
+	s.queue(ctx, func(context.Context) (repository.Deliveries, error) {
+		return repository.Deliveries{
+			EventKey: fmt.Sprintf("order_fulfilled:%s:%d", campaign.ID, fulfillments[0].FulfilledAt.Unix()),
+			Kind:     "order_fulfilled",
+			Topic:    models.TopicCampaignMilestones,
+			Subject:  campaign.Title,
+			UserIDs:  userIDs,
+			Bodies:   bodies,
+		}, nil
+	})
+
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  body,
+		Data: map[string]string{
+			"type":             "order_fulfilled",
+			"campaign_id":      campaign.ID.String(),
+			"confirm_deadline": deadline.UTC().Format(time.RFC3339),
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, userIDs, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+}
+
+// FulfillmentDisputed tells the merchant a participant disputed the
+// fulfillment of their order
+func (s *NotificationService) FulfillmentDisputed(ctx context.Context, campaign *models.Campaign, f *models.Fulfillment) {
+	if campaign.MerchantID == nil {
+		return
+	}
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  "A participant disputed the fulfillment of their order. Mark it again with new proof to settle the campaign.",
+		Data: map[string]string{
+			"type":             "fulfillment_disputed",
+			"campaign_id":      campaign.ID.String(),
+			"participation_id": f.ParticipationID.String(),
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, []uuid.UUID{*campaign.MerchantID}, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+}
+
+// DisputeResolved tells a participant how their dispute was resolved, and
+// again once its refund is done
+func (s *NotificationService) DisputeResolved(ctx context.Context, campaign *models.Campaign, d *models.Dispute) {
+	refund := money.New(d.RefundAmount.Big(), money.USDT)
+	var body string
+	switch d.Status {
+	case models.DisputeRejected:
+		body = "Your dispute was reviewed and closed without a refund."
+	case models.DisputeRefunding:
+		body = fmt.Sprintf("Your dispute was resolved with a refund of %s, which is on its way.", refund)
+	case models.DisputeRefunded:
+		body = fmt.Sprintf("Your refund of %s has been completed.", refund)
+	default:
+		return
+	}
+	kind := "dispute_" + d.Status
+
+	s.queue(ctx, func(context.Context) (repository.Deliveries, error) {
+		return repository.Deliveries{
+			EventKey: kind + ":" + d.ID.String(),
+			Kind:     kind,
+			Topic:    models.TopicCampaignMilestones,
+			Subject:  campaign.Title,
+			UserIDs:  []uuid.UUID{d.UserID},
+			Bodies:   []string{body},
+		}, nil
+	})
+
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  body,
+		Data: map[string]string{
+			"type":        kind,
+			"campaign_id": campaign.ID.String(),
+			"dispute_id":  d.ID.String(),
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, []uuid.UUID{d.UserID}, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+}
+
+// VouchersIssued tells the participants of a settled campaign their
+// redemption voucher is ready
+func (s *NotificationService) VouchersIssued(ctx context.Context, campaign *models.Campaign, userIDs []uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+	const body = "Your voucher is ready. Show it at the store to redeem your order."
+
+	s.queue(ctx, func(context.Context) (repository.Deliveries, error) {
+		bodies := make([]string, len(userIDs))
+		for i := range bodies {
+			bodies[i] = body
+		}
+		return repository.Deliveries{
+			EventKey: "voucher_issued:" + campaign.ID.String(),
+			Kind:     "voucher_issued",
+			Topic:    models.TopicCampaignMilestones,
+			Subject:  campaign.Title,
+			UserIDs:  userIDs,
+			Bodies:   bodies,
+		}, nil
+	})
+
+	msg := push.Message{
+		Title: campaign.Title,
+		Body:  body,
+		Data: map[string]string{
+			"type":        "voucher_issued",
+			"campaign_id": campaign.ID.String(),
+		},
+	}
+	s.fanOut(ctx, models.TopicCampaignMilestones,
+		func(ctx context.Context) ([]repository.Target, error) {
+			return s.repo.TargetsForUsers(ctx, userIDs, models.TopicCampaignMilestones)
+		},
+		func(repository.Target) push.Message { return msg })
+}
+
+// fanOut loads the targets and sends each its message in the background.
+// The request context's values (request id, trace) are kept but not its
+// cancellation, since the request finishes first.
+func (s *NotificationService) fanOut(ctx context.Context, topic string, targets func(context.Context) ([]repository.Target, error), message func(repository.Target) push.Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+	go func() {
+		defer cancel()
+		log := logger.FromContext(ctx)
+
+		list, err := targets(ctx)
+		if err != nil {
+			log.Error("failed to load push targets", "topic", topic, "error", err)
+			return
+		}
+		for _, t := range list {
+			err := s.sender.Send(ctx, t.Token, message(t))
+			switch {
+			case err == nil:
+				metrics.PushNotifications.WithLabelValues(topic, "sent").Inc()
+			case errors.Is(err, push.ErrUnregistered):
+				metrics.PushNotifications.WithLabelValues(topic, "unregistered").Inc()
+				if err := s.repo.DeleteToken(ctx, t.Token); err != nil {
+					log.Warn("failed to delete unregistered device token", "error", err)
+				}
+			default:
+				metrics.PushNotifications.WithLabelValues(topic, "failed").Inc()
+				log.Warn("push failed", "topic", topic, logger.KeyUserID, t.UserID, "error", err)
+			}
+		}
+	}()
+}
+
+// queue stores the LINE messages and emails built by deliveries for
+// batch-server to send, in the background like fanOut. A failure loses
+// them and is only logged.
+func (s *NotificationService) queue(ctx context.Context, deliveries func(context.Context) (repository.Deliveries, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pushTimeout)
+	go func() {
+		defer cancel()
+		log := logger.FromContext(ctx)
+
+		d, err := deliveries(ctx)
+		if err != nil {
+			log.Error("failed to load notification recipients", "error", err)
+			return
+		}
+		if len(d.UserIDs) == 0 {
+			return
+		}
+		d.At = s.clock.Now()
+		n, err := s.repo.QueueDeliveries(ctx, d)
+		if err != nil {
+			log.Error("failed to queue notification deliveries", "kind", d.Kind, "error", err)
+			return
+		}
+		log.Debug("queued notification deliveries", "kind", d.Kind, "count", n)
+	}()
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/address"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/logger"
+	"r2s/pkg/metrics"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/outbox"
+	"r2s/pkg/pagination"
+	"r2s/pkg/referral"
+	"r2s/pkg/statemachine"
+)
+
+var (
+	ErrParticipationNotFound = apperrors.Catalog(apperrors.ReasonParticipationMissing)
+	ErrCampaignNotOpen       = apperrors.Catalog(apperrors.ReasonCampaignNotOpen)
+	ErrAlreadyParticipating  = apperrors.Catalog(apperrors.ReasonAlreadyParticipating)
+	ErrInvalidDeposit        = apperrors.Catalog(apperrors.ReasonInvalidDeposit)
+	ErrNotCancellable        = apperrors.Catalog(apperrors.ReasonNotCancellable)
+	ErrJoinInProgress        = apperrors.Catalog(apperrors.ReasonJoinInProgress)
+)
+
+// joinLockTTL frees the join lock of an instance that died mid-request; it
+// outlasts the join transaction and its retries
+const joinLockTTL = 30 * time.Second
+
+type ParticipationService struct {
+	db                *database.DB
+	redis             *database.RedisClient
+	campaignRepo      *repository.CampaignRepository
+	participationRepo *repository.ParticipationRepository
+	clock             clock.Clock
+	events            *outbox.Store
+	notifications     *NotificationService
+	referrals         *referral.Store
+	campaigns         *CampaignStateMachine
+	participations    *statemachine.Machine[string]
+}
+
+type CreateParticipationInput struct {
+	CampaignID    uuid.UUID
+	UserID        uuid.UUID
+	WalletAddress string
+	DepositAmount *big.Int
+	TxHash        *string
+	Metadata      models.JSONB
+}
+
+func NewParticipationService(db *database.DB, redis *database.RedisClient, clk clock.Clock, notifications *NotificationService, referrals *referral.Store) *ParticipationService {
+	return &ParticipationService{
+		db:                db,
+		redis:             redis,
+		campaignRepo:      repository.NewCampaignRepository(db),
+		participationRepo: repository.NewParticipationRepository(db),
+		clock:             clock.OrSystem(clk),
+		events:            outbox.NewStore(db, clk),
+		notifications:     notifications,
+		referrals:         referrals,
+		campaigns:         NewCampaignStateMachine(db, clk),
+		participations:    statemachine.NewParticipation().OnTransition(statemachine.LogHistory[string]()),
+	}
+}
+
+// GetUserParticipations returns a page of a user's participations and the
+// user's total count
+func (s *ParticipationService) GetUserParticipations(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Participation, int64, error) {
+	return s.participationRepo.FindByUser(ctx, userID, page)
+}
+
+// GetCampaignParticipations returns a page of a campaign's participations and
+// the campaign's total count
+func (s *ParticipationService) GetCampaignParticipations(ctx context.Context, campaignID uuid.UUID, page pagination.Page) ([]*models.Participation, int64, error) {
+	return s.participationRepo.FindByCampaign(ctx, campaignID, page)
+}
+
+// CreateParticipation joins a user to a campaign and updates the campaign
+// totals. Concurrent joins of the same campaign are serialised by the
+// campaign advisory lock so the totals and the reached transition stay exact.
+//
+// It is idempotent: a retry of a join that already went through returns the
+// existing participation with created false instead of a conflict, and a
+// retry racing the original is turned away by a Redis lock on the (campaign,
+// user) pair before it reaches the database.
+func (s *ParticipationService) CreateParticipation(ctx context.Context, in CreateParticipationInput) (participation *models.Participation, created bool, err error) {
+	if in.DepositAmount == nil || in.DepositAmount.Sign() <= 0 {
+		return nil, false, ErrInvalidDeposit
+	}
+
+	unlock, err := s.lockJoin(ctx, in.CampaignID, in.UserID)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+
+	// reached is set when this participation made the campaign reach min_qty
+	var reached *models.Campaign
+
+	err = s.db.TransactionWithRetryContext(ctx, database.DefaultRetryConfig, nil, func(tx *sqlx.Tx) error {
+		reached, created = nil, false
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockCampaign, in.CampaignID.String())); err != nil {
+			return err
+		}
+
+		// FOR NO KEY UPDATE still lets participations reference the row
+		campaign, err := s.campaignRepo.FindByIDForUpdate(ctx, tx, in.CampaignID, database.ForNoKeyUpdate)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			return ErrCampaignNotFound
+		}
+
+		existing, err := s.participationRepo.FindForUser(ctx, tx, in.CampaignID, in.UserID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			if !sameJoin(existing, in) {
+				return ErrAlreadyParticipating
+			}
+			participation = existing
+			return nil
+		}
+
+		now := s.clock.Now()
+		if campaign.Status != models.StatusRecruiting && campaign.Status != models.StatusReached {
+			return ErrCampaignNotOpen
+		}
+		if now.Before(campaign.StartTime) || now.After(campaign.EndTime) {
+			return ErrCampaignNotOpen
+		}
+
+		deposit := money.New(in.DepositAmount, money.USDT)
+		qty, rem, err := deposit.QuoRem(money.New(campaign.BasePrice.Int, money.USDT))
+		if err != nil || qty.Sign() <= 0 || !rem.IsZero() || !qty.IsInt64() {
+			return ErrInvalidDeposit
+		}
+
+		participation = &models.Participation{
+			ID:             uuid.New(),
+			CampaignID:     in.CampaignID,
+			UserID:         in.UserID,
+			WalletAddress:  address.Display(in.WalletAddress),
+			DepositAmount:  models.NewBigInt(deposit.Units()),
+			JoinedAt:       now,
+			ExpectedRebate: models.NewBigInt(deposit.MulBps(campaign.SaveFloorBps).Units()),
+			Status:         repository.ParticipationActive,
+			TxHash:         in.TxHash,
+			Metadata:       in.Metadata,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := s.participationRepo.Create(ctx, tx, participation); err != nil {
+			if errors.Is(err, repository.ErrDuplicateParticipation) {
+				return ErrAlreadyParticipating
+			}
+			return fmt.Errorf("failed to create participation: %w", err)
+		}
+		created = true
+		if err := s.participationEvent(ctx, tx, outbox.ParticipationCreated, participation.ID, participation); err != nil {
+			return err
+		}
+
+		// A user's first participation qualifies their referral
+		if _, err := s.referrals.Qualify(ctx, tx, participation); err != nil {
+			return err
+		}
+
+		total, err := money.New(campaign.CurrentAmount.Int, money.USDT).Add(deposit)
+		if err != nil {
+			return err
+		}
+		campaign.CurrentAmount = models.NewBigInt(total.Units())
+		campaign.CurrentQty += int(qty.Int64())
+		if campaign.Status == models.StatusRecruiting && campaign.CurrentQty >= campaign.MinQty {
+			if err := s.campaigns.Transition(ctx, tx, campaign, models.StatusReached, "minimum quantity reached"); err != nil {
+				return err
+			}
+			reached = campaign
+		}
+		return s.campaignRepo.UpdateTotals(ctx, tx, campaign)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		return participation, false, nil
+	}
+	metrics.ParticipationsCreated.Inc()
+	if reached != nil {
+		s.notifications.CampaignReached(ctx, reached)
+	}
+	return participation, true, nil
+}
+
+// lockJoin takes the join lock of the (campaign, user) pair and returns its
+// release. The campaign advisory lock and the unique (campaign_id, user_id)
+// constraint keep the data right on their own, so when Redis is unreachable
+// the join goes ahead without the lock.
+func (s *ParticipationService) lockJoin(ctx context.Context, campaignID, userID uuid.UUID) (func(), error) {
+	key := fmt.Sprintf("participation:join:%s:%s", campaignID, userID)
+	token := uuid.NewString()
+
+	ok, err := s.redis.SetNX(ctx, key, token, joinLockTTL)
+	if err != nil {
+		logger.FromContext(ctx).Warn("failed to take join lock, joining without it", "error", err)
+		return func() {}, nil
+	}
+	if !ok {
+		return nil, ErrJoinInProgress
+	}
+	return func() {
+		if _, err := s.redis.DeleteIfEqual(context.WithoutCancel(ctx), key, token); err != nil {
+			logger.FromContext(ctx).Warn("failed to release join lock", "error", err)
+		}
+	}, nil
+}
+
+// sameJoin reports whether p is what in would create, so that a retried
+// join gets it back; any other join of the same user is a conflict
+func sameJoin(p *models.Participation, in CreateParticipationInput) bool {
+	if p.Status != repository.ParticipationActive && p.Status != repository.ParticipationPendingCancel {
+		return false
+	}
+	if !strings.EqualFold(p.WalletAddress, in.WalletAddress) || p.DepositAmount.Int.Cmp(in.DepositAmount) != 0 {
+		return false
+	}
+	return in.TxHash == nil || (p.TxHash != nil && strings.EqualFold(*p.TxHash, *in.TxHash))
+}
+
+// UpdateParticipationMetadata merges patch into a participation's metadata;
+// keys set to null are removed
+func (s *ParticipationService) UpdateParticipationMetadata(ctx context.Context, id uuid.UUID, patch models.JSONB) (models.JSONB, error) {
+	var metadata models.JSONB
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		participation, err := s.participationRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if participation == nil {
+			return ErrParticipationNotFound
+		}
+
+		metadata, err = s.participationRepo.UpdateMetadata(ctx, tx, id, patch)
+		if err != nil {
+			return fmt.Errorf("failed to update participation metadata: %w", err)
+		}
+		participation.Metadata = metadata
+		return s.participationEvent(ctx, tx, outbox.ParticipationUpdated, id, participation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// participationEvent appends an event of participation id inside tx
+func (s *ParticipationService) participationEvent(ctx context.Context, tx *sqlx.Tx, eventType string, id uuid.UUID, payload interface{}) error {
+	return s.events.Append(ctx, tx, outbox.Event{
+		Type:          eventType,
+		AggregateType: outbox.AggregateParticipation,
+		AggregateID:   id.String(),
+		Payload:       payload,
+	})
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/featureflags"
+	"r2s/pkg/models"
+	"r2s/pkg/money"
+	"r2s/pkg/outbox"
+	"r2s/pkg/statemachine"
+)
+
+var (
+	ErrPaymentNotFound  = apperrors.Catalog(apperrors.ReasonPaymentNotFound)
+	ErrInvalidSignature = apperrors.Catalog(apperrors.ReasonWebhookSignature)
+	ErrInvalidWebhook   = apperrors.Catalog(apperrors.ReasonInvalidWebhook)
+	ErrStripeDisabled   = apperrors.Catalog(apperrors.ReasonStripeDisabled)
+)
+
+type PaymentService struct {
+	db            *database.DB
+	redis         *database.RedisClient
+	paymentRepo   *repository.PaymentRepository
+	webhookSecret string
+	flags         *featureflags.Flags
+	payments      *statemachine.Machine[models.PaymentStatus]
+	audit         *audit.Store
+	events        *outbox.Store
+	disputes      *DisputeService
+}
+
+type ProcessPaymentInput struct {
+	PaymentID       string
+	CampaignID      *uuid.UUID
+	UserID          *uuid.UUID
+	ParticipationID *uuid.UUID
+	Amount          *big.Int
+	Currency        models.Currency
+	Mode            models.PaymentMode
+	TransactionHash *string
+}
+
+// WebhookEvent is the payload posted by the payment provider
+type WebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		PaymentID string                 `json:"payment_id"`
+		Status    models.PaymentStatus   `json:"status"`
+		Raw       map[string]interface{} `json:"raw,omitempty"`
+	} `json:"data"`
+}
+
+func NewPaymentService(db *database.DB, redis *database.RedisClient, webhookSecret string, flags *featureflags.Flags, disputes *DisputeService) *PaymentService {
+	return &PaymentService{
+		db:            db,
+		redis:         redis,
+		paymentRepo:   repository.NewPaymentRepository(db),
+		webhookSecret: webhookSecret,
+		flags:         flags,
+		payments:      statemachine.NewPayment().OnTransition(statemachine.LogHistory[models.PaymentStatus]()),
+		audit:         audit.NewStore(db, nil),
+		events:        outbox.NewStore(db, nil),
+		disputes:      disputes,
+	}
+}
+
+// ProcessPayment records a new payment in pending state
+func (s *PaymentService) ProcessPayment(ctx context.Context, in ProcessPaymentInput) (*models.Payment, error) {
+	currency, err := money.LookupCurrency(string(in.Currency))
+	if err != nil {
+		return nil, apperrors.Wrap(err, apperrors.CodeInvalidArgument, "unsupported currency")
+	}
+	if !money.New(in.Amount, currency).IsPositive() {
+		return nil, apperrors.Catalog(apperrors.ReasonInvalidAmount)
+	}
+	if in.Mode == models.ModeStripe {
+		userID := ""
+		if in.UserID != nil {
+			userID = in.UserID.String()
+		}
+		if !s.flags.Enabled(ctx, featureflags.StripePayments, userID) {
+			return nil, ErrStripeDisabled
+		}
+	}
+
+	paymentID := in.PaymentID
+	if paymentID == "" {
+		paymentID = uuid.New().String()
+	}
+
+	payment := &models.Payment{
+		ID:              uuid.New(),
+		PaymentID:       paymentID,
+		CampaignID:      in.CampaignID,
+		UserID:          in.UserID,
+		ParticipationID: in.ParticipationID,
+		Amount:          models.NewBigInt(in.Amount),
+		Currency:        in.Currency,
+		Mode:            in.Mode,
+		Status:          models.PaymentPending,
+		TransactionHash: in.TransactionHash,
+		Metadata:        models.JSONB{},
+	}
+
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := s.paymentRepo.Create(ctx, tx, payment); err != nil {
+			return fmt.Errorf("failed to create payment: %w", err)
+		}
+		return s.paymentEvent(ctx, tx, outbox.PaymentCreated, payment.ID, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// GetPaymentStatus returns a payment by its internal id
+func (s *PaymentService) GetPaymentStatus(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	payment, err := s.paymentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+// HandleWebhook verifies, logs and applies a provider webhook. Replayed
+// events are acknowledged without being applied again.
+func (s *PaymentService) HandleWebhook(ctx context.Context, body []byte, signature string) error {
+	if s.webhookSecret != "" && !s.validSignature(body, signature) {
+		return ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" {
+		return ErrInvalidWebhook
+	}
+
+	var sig *string
+	if signature != "" {
+		sig = &signature
+	}
+
+	fresh, err := s.paymentRepo.LogWebhook(ctx, event.ID, event.Type, body, sig)
+	if err != nil {
+		return fmt.Errorf("failed to log webhook: %w", err)
+	}
+	if !fresh {
+		return nil
+	}
+
+	procErr := s.applyWebhook(ctx, &event)
+	if err := s.paymentRepo.MarkWebhookProcessed(ctx, event.ID, procErr); err != nil {
+		return fmt.Errorf("failed to update webhook log: %w", err)
+	}
+	return procErr
+}
+
+func (s *PaymentService) applyWebhook(ctx context.Context, event *WebhookEvent) error {
+	payment, err := s.paymentRepo.FindByPaymentID(ctx, event.Data.PaymentID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return ErrPaymentNotFound
+	}
+
+	switch event.Data.Status {
+	case models.PaymentProcessing, models.PaymentCompleted, models.PaymentFailed, models.PaymentRefunded:
+	default:
+		return apperrors.Catalog(apperrors.ReasonPaymentStatus, event.Data.Status)
+	}
+
+	// Providers redeliver webhooks; a repeated status is a no-op
+	if payment.Status == event.Data.Status {
+		return nil
+	}
+	if err := s.payments.Transition(ctx, payment.ID.String(), payment.Status, event.Data.Status); err != nil {
+		return err
+	}
+
+	var refunded []*models.Dispute
+	err = s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if event.Data.Status == models.PaymentRefunded {
+			// Completes the dispute refunds requested on the payment; first,
+			// since it takes the campaign lock
+			var err error
+			if refunded, err = s.disputes.paymentRefunded(ctx, tx, payment); err != nil {
+				return err
+			}
+		}
+		if err := s.paymentRepo.UpdateStatus(ctx, tx, payment.ID, event.Data.Status, event.Data.Raw); err != nil {
+			return err
+		}
+		if err := s.paymentEvent(audit.AsSystem(ctx, "payment-provider"), tx, outbox.PaymentStatusChanged, payment.ID, map[string]interface{}{
+			"paymentId":  payment.ID,
+			"campaignId": payment.CampaignID,
+			"from":       payment.Status,
+			"to":         event.Data.Status,
+			"eventId":    event.ID,
+		}); err != nil {
+			return err
+		}
+		if event.Data.Status != models.PaymentRefunded {
+			return nil
+		}
+		// Refunds are issued at the provider; attribute them to it
+		return s.audit.Record(audit.AsSystem(ctx, "payment-provider"), tx, audit.Change{
+			Action:       audit.ActionPaymentRefund,
+			ResourceType: audit.ResourcePayment,
+			ResourceID:   payment.ID.String(),
+			Before:       map[string]interface{}{"status": payment.Status},
+			After:        map[string]interface{}{"status": event.Data.Status, "eventId": event.ID},
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.disputes.notifyResolved(ctx, refunded...)
+	return nil
+}
+
+// paymentEvent appends an event of payment id inside tx
+func (s *PaymentService) paymentEvent(ctx context.Context, tx *sqlx.Tx, eventType string, id uuid.UUID, payload interface{}) error {
+	return s.events.Append(ctx, tx, outbox.Event{
+		Type:          eventType,
+		AggregateType: outbox.AggregatePayment,
+		AggregateID:   id.String(),
+		Payload:       payload,
+	})
+}
+
+func (s *PaymentService) validSignature(body []byte, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/policy"
+)
+
+var ErrPolicyNotFound = apperrors.Catalog(apperrors.ReasonPolicyNotFound)
+
+// PolicyService keeps the campaign policy of each merchant tier; see
+// pkg/policy. CampaignService enforces them.
+type PolicyService struct {
+	db         *database.DB
+	policyRepo *repository.PolicyRepository
+	audit      *audit.Store
+	clock      clock.Clock
+}
+
+func NewPolicyService(db *database.DB, clk clock.Clock) *PolicyService {
+	return &PolicyService{
+		db:         db,
+		policyRepo: repository.NewPolicyRepository(db),
+		audit:      audit.NewStore(db, clk),
+		clock:      clock.OrSystem(clk),
+	}
+}
+
+// ListPolicies returns the policy of every tier that has one
+func (s *PolicyService) ListPolicies(ctx context.Context) ([]*models.CampaignPolicy, error) {
+	policies, err := s.policyRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaign policies: %w", err)
+	}
+	return policies, nil
+}
+
+// GetPolicy returns a tier's policy
+func (s *PolicyService) GetPolicy(ctx context.Context, tier int) (*models.CampaignPolicy, error) {
+	p, err := s.policyRepo.FindByTier(ctx, tier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign policy: %w", err)
+	}
+	if p == nil {
+		return nil, ErrPolicyNotFound
+	}
+	return p, nil
+}
+
+// SetPolicy replaces the policy of p.MerchantTier. Campaigns already
+// created keep their terms; the policy applies from their next check.
+func (s *PolicyService) SetPolicy(ctx context.Context, p *models.CampaignPolicy) (*models.CampaignPolicy, error) {
+	if err := policy.Validate(p); err != nil {
+		return nil, err
+	}
+	p.UpdatedAt = s.clock.Now()
+
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		before, err := s.policyRepo.FindForUpdate(ctx, tx, p.MerchantTier)
+		if err != nil {
+			return fmt.Errorf("failed to load campaign policy: %w", err)
+		}
+		if err := s.policyRepo.Upsert(ctx, tx, p); err != nil {
+			return fmt.Errorf("failed to set campaign policy: %w", err)
+		}
+		change := audit.Change{
+			Action:       audit.ActionPolicySet,
+			ResourceType: audit.ResourcePolicy,
+			ResourceID:   strconv.Itoa(p.MerchantTier),
+			After:        p,
+		}
+		// A nil *CampaignPolicy would be recorded as null rather than absent
+		if before != nil {
+			change.Before = before
+		}
+		return s.audit.Record(ctx, tx, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DeletePolicy removes a tier's policy, leaving its merchants bound only by
+// the built-in checks
+func (s *PolicyService) DeletePolicy(ctx context.Context, tier int) error {
+	return s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		p, err := s.policyRepo.FindForUpdate(ctx, tx, tier)
+		if err != nil {
+			return fmt.Errorf("failed to load campaign policy: %w", err)
+		}
+		if p == nil {
+			return ErrPolicyNotFound
+		}
+		if err := s.policyRepo.Delete(ctx, tx, tier); err != nil {
+			return fmt.Errorf("failed to delete campaign policy: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionPolicyDelete,
+			ResourceType: audit.ResourcePolicy,
+			ResourceID:   strconv.Itoa(tier),
+			Before:       p,
+		})
+	})
+}
+
+// checkPolicy checks t against the policy of the merchant's tier; campaigns
+// without a merchant fall under tier 0
+func (s *CampaignService) checkPolicy(ctx context.Context, merchantID *uuid.UUID, t policy.Terms) error {
+	var p *models.CampaignPolicy
+	var err error
+	if merchantID != nil {
+		p, err = s.policyRepo.FindForMerchant(ctx, *merchantID)
+	} else {
+		p, err = s.policyRepo.FindByTier(ctx, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load campaign policy: %w", err)
+	}
+	return policy.Check(p, t)
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/rbac"
+	"r2s/pkg/validate"
+)
+
+// MaxTemplateName bounds a template's name, in characters
+const MaxTemplateName = 100
+
+var (
+	ErrTemplateNotFound  = apperrors.Catalog(apperrors.ReasonTemplateNotFound)
+	ErrTemplateNameTaken = apperrors.Catalog(apperrors.ReasonTemplateNameTaken)
+)
+
+// TemplateInput is a template's editable fields. When updating, a nil
+// ImageID, Category or Tags leaves them as they are; an empty Category
+// clears it.
+type TemplateInput struct {
+	Name string
+	// MerchantID is whose template ops create; merchants always create
+	// their own
+	MerchantID     *uuid.UUID
+	Title          string
+	Description    *string
+	MerchantWallet string
+	BasePrice      *big.Int
+	MinQty         int
+	DiscountRate   int
+	SaveFloorBps   int
+	RMaxBps        int
+	Metadata       models.JSONB
+	// CancelLeadSeconds closes free cancellation that long before a
+	// launched campaign ends; nil leaves it open until the end
+	CancelLeadSeconds    *int64
+	LateCancelPenaltyBps *int
+	ImageID              *uuid.UUID
+	Category             *string
+	Tags                 []string
+}
+
+// RelaunchInput is the new lock window of a cloned or launched campaign
+type RelaunchInput struct {
+	// Title replaces the copied title unless nil
+	Title     *string
+	StartTime time.Time
+	EndTime   time.Time
+	// CancelDeadline replaces the deadline derived from the copied cancel
+	// lead unless nil
+	CancelDeadline *time.Time
+}
+
+// cloneSource is what a cloned campaign was made from, as recorded in the
+// audit log
+type cloneSource struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// CloneCampaign creates a draft campaign with the terms and imagery of an
+// existing one and a new lock window. A free cancellation deadline keeps
+// its distance from the end of the window.
+func (s *CampaignService) CloneCampaign(ctx context.Context, id uuid.UUID, in RelaunchInput) (*models.Campaign, error) {
+	source, err := s.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeCampaign(ctx, source); err != nil {
+		return nil, err
+	}
+	return s.launch(ctx, templateOf(source), in, &cloneSource{Type: audit.ResourceCampaign, ID: source.ID})
+}
+
+// ListTemplates returns one page of templates, most recently updated
+// first, and their total. Merchants only see their own; ops may filter by
+// merchantID or list every merchant's with nil.
+func (s *CampaignService) ListTemplates(ctx context.Context, merchantID *uuid.UUID, page pagination.Page) ([]*models.CampaignTemplate, int64, error) {
+	if rbac.RoleFrom(ctx) == models.RoleMerchant {
+		id, err := uuid.Parse(audit.ActorFrom(ctx).ID)
+		if err != nil {
+			return nil, 0, ErrNotCampaignOwner
+		}
+		merchantID = &id
+	}
+	templates, total, err := s.templateRepo.List(ctx, merchantID, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list campaign templates: %w", err)
+	}
+	return templates, total, nil
+}
+
+// GetTemplate returns a template; those of other merchants are reported as
+// not found
+func (s *CampaignService) GetTemplate(ctx context.Context, id uuid.UUID) (*models.CampaignTemplate, error) {
+	t, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load campaign template: %w", err)
+	}
+	if err := authorizeTemplate(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// CreateTemplate saves a template from explicit terms
+func (s *CampaignService) CreateTemplate(ctx context.Context, in TemplateInput) (*models.CampaignTemplate, error) {
+	merchantID := in.MerchantID
+	if rbac.RoleFrom(ctx) == models.RoleMerchant {
+		id, err := uuid.Parse(audit.ActorFrom(ctx).ID)
+		if err != nil {
+			return nil, ErrNotCampaignOwner
+		}
+		merchantID = &id
+	}
+	if merchantID == nil {
+		return nil, apperrors.InvalidArgument("merchantId is required")
+	}
+
+	now := s.clock.Now()
+	t := &models.CampaignTemplate{
+		ID:         uuid.New(),
+		MerchantID: *merchantID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.applyTemplate(ctx, t, in); err != nil {
+		return nil, err
+	}
+	if err := s.createTemplate(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SaveTemplate saves the terms and imagery of an existing campaign as a
+// template under name
+func (s *CampaignService) SaveTemplate(ctx context.Context, campaignID uuid.UUID, name string) (*models.CampaignTemplate, error) {
+	source, err := s.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeCampaign(ctx, source); err != nil {
+		return nil, err
+	}
+	if source.MerchantID == nil {
+		return nil, apperrors.InvalidArgument("the campaign has no merchant")
+	}
+
+	now := s.clock.Now()
+	t := templateOf(source)
+	t.ID = uuid.New()
+	t.Name = strings.TrimSpace(name)
+	t.SourceCampaignID = &source.ID
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	if err := checkTemplate(t); err != nil {
+		return nil, err
+	}
+	if err := s.createTemplate(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// createTemplate stores a checked template and records it in the audit log
+func (s *CampaignService) createTemplate(ctx context.Context, t *models.CampaignTemplate) error {
+	return s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		if err := s.templateRepo.Create(ctx, tx, t); err != nil {
+			if errors.Is(err, repository.ErrDuplicateTemplate) {
+				return ErrTemplateNameTaken
+			}
+			return fmt.Errorf("failed to create campaign template: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionTemplateCreate,
+			ResourceType: audit.ResourceTemplate,
+			ResourceID:   t.ID.String(),
+			After:        t,
+		})
+	})
+}
+
+// UpdateTemplate replaces a template's terms
+func (s *CampaignService) UpdateTemplate(ctx context.Context, id uuid.UUID, in TemplateInput) (*models.CampaignTemplate, error) {
+	var t *models.CampaignTemplate
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		var err error
+		t, err = s.templateRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load campaign template: %w", err)
+		}
+		if err := authorizeTemplate(ctx, t); err != nil {
+			return err
+		}
+		before := *t
+
+		if err := s.applyTemplate(ctx, t, in); err != nil {
+			return err
+		}
+		t.UpdatedAt = s.clock.Now()
+		if err := s.templateRepo.Update(ctx, tx, t); err != nil {
+			if errors.Is(err, repository.ErrDuplicateTemplate) {
+				return ErrTemplateNameTaken
+			}
+			return fmt.Errorf("failed to update campaign template: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionTemplateUpdate,
+			ResourceType: audit.ResourceTemplate,
+			ResourceID:   t.ID.String(),
+			Before:       &before,
+			After:        t,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteTemplate removes a template. Campaigns launched from it are kept.
+func (s *CampaignService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	return s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		t, err := s.templateRepo.FindForUpdate(ctx, tx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load campaign template: %w", err)
+		}
+		if err := authorizeTemplate(ctx, t); err != nil {
+			return err
+		}
+		if err := s.templateRepo.Delete(ctx, tx, id); err != nil {
+			return fmt.Errorf("failed to delete campaign template: %w", err)
+		}
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionTemplateDelete,
+			ResourceType: audit.ResourceTemplate,
+			ResourceID:   t.ID.String(),
+			Before:       t,
+		})
+	})
+}
+
+// LaunchTemplate creates a draft campaign from a template with a new lock
+// window
+func (s *CampaignService) LaunchTemplate(ctx context.Context, id uuid.UUID, in RelaunchInput) (*models.Campaign, error) {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.launch(ctx, t, in, &cloneSource{Type: audit.ResourceTemplate, ID: t.ID})
+}
+
+// launch creates a draft campaign with t's terms in the window of in. The
+// terms go through createCampaign as if entered by hand, so a template or
+// campaign from before a bound was tightened, a merchant no longer
+// approved or a deleted category fail here rather than on chain.
+func (s *CampaignService) launch(ctx context.Context, t *models.CampaignTemplate, in RelaunchInput, source *cloneSource) (*models.Campaign, error) {
+	if !in.EndTime.After(s.clock.Now()) {
+		return nil, apperrors.InvalidArgument("endTime must be in the future")
+	}
+
+	create := campaignInput(t)
+	if in.Title != nil {
+		create.Title = *in.Title
+	}
+	create.StartTime = in.StartTime
+	create.EndTime = in.EndTime
+	create.CancelDeadline = in.CancelDeadline
+	if create.CancelDeadline == nil && t.CancelLeadSeconds != nil {
+		deadline := in.EndTime.Add(-time.Duration(*t.CancelLeadSeconds) * time.Second)
+		create.CancelDeadline = &deadline
+	}
+	create.source = source
+	return s.createCampaign(ctx, create)
+}
+
+// applyTemplate checks in and applies it to t
+func (s *CampaignService) applyTemplate(ctx context.Context, t *models.CampaignTemplate, in TemplateInput) error {
+	t.Name = strings.TrimSpace(in.Name)
+	t.Title = in.Title
+	t.Description = in.Description
+	t.MerchantWallet = in.MerchantWallet
+	t.BasePrice = models.NewBigInt(in.BasePrice)
+	t.MinQty = in.MinQty
+	t.DiscountRate = in.DiscountRate
+	t.SaveFloorBps = in.SaveFloorBps
+	t.RMaxBps = in.RMaxBps
+	t.Metadata = in.Metadata
+	t.CancelLeadSeconds = in.CancelLeadSeconds
+	t.LateCancelPenaltyBps = in.LateCancelPenaltyBps
+	if err := checkTemplate(t); err != nil {
+		return err
+	}
+
+	if in.ImageID != nil {
+		var err error
+		t.ImageURL, t.ImageThumbnailURL, err = readyImage(ctx, s.mediaRepo, *in.ImageID, models.MediaCampaignImage)
+		if err != nil {
+			return err
+		}
+	}
+	if in.Category != nil && *in.Category == "" {
+		t.Category = nil
+	} else if in.Category != nil {
+		if err := checkCategory(ctx, s.categoryRepo, *in.Category); err != nil {
+			return err
+		}
+		t.Category = in.Category
+	}
+	if in.Tags != nil {
+		normalized, err := normalizeTags(in.Tags)
+		if err != nil {
+			return err
+		}
+		t.Tags = normalized
+	}
+	return nil
+}
+
+// checkTemplate checks a template's name and that its terms are within the
+// platform's bounds
+func checkTemplate(t *models.CampaignTemplate) error {
+	if err := validate.Required("name", t.Name); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(t.Name) > MaxTemplateName {
+		return apperrors.InvalidArgument(fmt.Sprintf("name must not exceed %d characters", MaxTemplateName))
+	}
+	if t.CancelLeadSeconds != nil && *t.CancelLeadSeconds < 0 {
+		return apperrors.InvalidArgument("cancelLeadSeconds must not be negative")
+	}
+	return checkTerms(campaignInput(t))
+}
+
+// authorizeTemplate lets merchants use only their own templates; ops,
+// admins and internal services may use any. Unknown templates and those of
+// other merchants look the same.
+func authorizeTemplate(ctx context.Context, t *models.CampaignTemplate) error {
+	if t == nil {
+		return ErrTemplateNotFound
+	}
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return nil
+	}
+	if t.MerchantID.String() != audit.ActorFrom(ctx).ID {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// templateOf copies the terms and imagery of a campaign, keeping its free
+// cancellation deadline as a lead on the end of the window
+func templateOf(c *models.Campaign) *models.CampaignTemplate {
+	t := &models.CampaignTemplate{
+		Title:                c.Title,
+		Description:          c.Description,
+		ImageURL:             c.ImageURL,
+		ImageThumbnailURL:    c.ImageThumbnailURL,
+		MerchantWallet:       c.MerchantWallet,
+		BasePrice:            c.BasePrice,
+		MinQty:               c.MinQty,
+		DiscountRate:         c.DiscountRate,
+		SaveFloorBps:         c.SaveFloorBps,
+		RMaxBps:              c.RMaxBps,
+		LateCancelPenaltyBps: c.LateCancelPenaltyBps,
+		Category:             c.Category,
+		Tags:                 c.Tags,
+		Metadata:             c.Metadata,
+	}
+	if c.MerchantID != nil {
+		t.MerchantID = *c.MerchantID
+	}
+	if c.CancelDeadline != nil {
+		lead := int64(c.EndTime.Sub(*c.CancelDeadline) / time.Second)
+		t.CancelLeadSeconds = &lead
+	}
+	return t
+}
+
+// campaignInput returns the creation input of t's terms, without a window.
+// A template copied from a campaign without a merchant has none.
+func campaignInput(t *models.CampaignTemplate) CreateCampaignInput {
+	in := CreateCampaignInput{
+		Title:                t.Title,
+		Description:          t.Description,
+		MerchantWallet:       t.MerchantWallet,
+		BasePrice:            t.BasePrice.Int,
+		MinQty:               t.MinQty,
+		DiscountRate:         t.DiscountRate,
+		SaveFloorBps:         t.SaveFloorBps,
+		RMaxBps:              t.RMaxBps,
+		Metadata:             t.Metadata,
+		LateCancelPenaltyBps: t.LateCancelPenaltyBps,
+		Category:             t.Category,
+		Tags:                 t.Tags,
+		imageURL:             t.ImageURL,
+		imageThumbnailURL:    t.ImageThumbnailURL,
+	}
+	if t.MerchantID != uuid.Nil {
+		merchantID := t.MerchantID
+		in.MerchantID = &merchantID
+	}
+	return in
+}
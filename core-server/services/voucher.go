@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"r2s/core-server/repository"
+	"r2s/pkg/audit"
+	"r2s/pkg/clock"
+	"r2s/pkg/database"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/pagination"
+	"r2s/pkg/rbac"
+)
+
+var (
+	ErrVouchersDisabled = apperrors.Catalog(apperrors.ReasonVouchersDisabled)
+	ErrVoucherNotFound  = apperrors.Catalog(apperrors.ReasonVoucherNotFound)
+	ErrVoucherRedeemed  = apperrors.Catalog(apperrors.ReasonVoucherRedeemed)
+	ErrVoucherVoid      = apperrors.Catalog(apperrors.ReasonVoucherVoid)
+)
+
+// VoucherConfig is loadable with pkg/config
+type VoucherConfig struct {
+	// Secret derives voucher codes; empty disables vouchers. Rotating it
+	// invalidates every code already issued.
+	Secret string `env:"VOUCHER_SECRET" secret:"true"`
+}
+
+// Validate implements config.Validator
+func (c VoucherConfig) Validate() error {
+	if c.Secret != "" && len(c.Secret) < 32 {
+		return errors.New("VOUCHER_SECRET must be at least 32 characters")
+	}
+	return nil
+}
+
+const (
+	// voucherAlphabet leaves out 0/O and 1/I so codes can be read out
+	voucherAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// voucherCodeLength characters carry 60 bits, shown in groups of four
+	voucherCodeLength = 12
+	voucherCodeGroup  = 4
+	// voucherQRPrefix marks QR payloads so POS scanners can tell them apart
+	voucherQRPrefix = "r2s:voucher:"
+)
+
+// VoucherService issues redemption vouchers when campaigns settle and
+// redeems them for merchants. Codes are an HMAC of the voucher id, so they
+// can be shown to the participant again while only their hash is stored.
+type VoucherService struct {
+	db          *database.DB
+	voucherRepo *repository.VoucherRepository
+	secret      []byte
+	clock       clock.Clock
+	audit       *audit.Store
+}
+
+func NewVoucherService(db *database.DB, cfg VoucherConfig, clk clock.Clock) *VoucherService {
+	clk = clock.OrSystem(clk)
+	return &VoucherService{
+		db:          db,
+		voucherRepo: repository.NewVoucherRepository(db),
+		secret:      []byte(cfg.Secret),
+		clock:       clk,
+		audit:       audit.NewStore(db, clk),
+	}
+}
+
+// Enabled reports whether VOUCHER_SECRET is set
+func (s *VoucherService) Enabled() bool {
+	return len(s.secret) > 0
+}
+
+// issue issues a voucher for each settled participation inside the
+// settlement's tx and returns the users who got one. It does nothing when
+// vouchers are disabled.
+func (s *VoucherService) issue(ctx context.Context, tx *sqlx.Tx, participations []*models.Participation, now time.Time) ([]uuid.UUID, error) {
+	if !s.Enabled() {
+		return nil, nil
+	}
+	userIDs := make([]uuid.UUID, 0, len(participations))
+	for _, p := range participations {
+		v := &models.Voucher{
+			ID:              uuid.New(),
+			ParticipationID: p.ID,
+			CampaignID:      p.CampaignID,
+			UserID:          p.UserID,
+			Status:          models.VoucherIssued,
+			IssuedAt:        now,
+		}
+		if err := s.voucherRepo.Create(ctx, tx, v, hashVoucherCode(s.code(v.ID))); err != nil {
+			return nil, fmt.Errorf("failed to issue voucher for participation %s: %w", p.ID, err)
+		}
+		userIDs = append(userIDs, p.UserID)
+	}
+	return userIDs, nil
+}
+
+// ListUserVouchers returns one page of the user's vouchers with their
+// codes, newest first, and their total
+func (s *VoucherService) ListUserVouchers(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.Voucher, int64, error) {
+	if !s.Enabled() {
+		return nil, 0, ErrVouchersDisabled
+	}
+	vouchers, total, err := s.voucherRepo.FindByUser(ctx, userID, page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list vouchers: %w", err)
+	}
+	for _, v := range vouchers {
+		s.reveal(v)
+	}
+	return vouchers, total, nil
+}
+
+// GetUserVoucher returns one of the user's vouchers with its code; those
+// of other users are reported as not found
+func (s *VoucherService) GetUserVoucher(ctx context.Context, userID, id uuid.UUID) (*models.Voucher, error) {
+	if !s.Enabled() {
+		return nil, ErrVouchersDisabled
+	}
+	v, err := s.voucherRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voucher: %w", err)
+	}
+	if v == nil || v.UserID != userID {
+		return nil, ErrVoucherNotFound
+	}
+	return s.reveal(v), nil
+}
+
+// Validate looks up a code, or QR payload, for the merchant of its
+// campaign without redeeming it. Vouchers of other merchants' campaigns
+// are reported as not found.
+func (s *VoucherService) Validate(ctx context.Context, code string) (*models.Voucher, error) {
+	if !s.Enabled() {
+		return nil, ErrVouchersDisabled
+	}
+	v, err := s.voucherRepo.FindByCodeHash(ctx, hashVoucherCode(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load voucher: %w", err)
+	}
+	if err := authorizeVoucher(ctx, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Redeem marks the voucher of a code redeemed by the calling merchant. The
+// voucher is locked and only an issued one is redeemed, so concurrent
+// scans of the same code redeem it once; the others fail with
+// ErrVoucherRedeemed.
+func (s *VoucherService) Redeem(ctx context.Context, code string) (*models.Voucher, error) {
+	if !s.Enabled() {
+		return nil, ErrVouchersDisabled
+	}
+	hash := hashVoucherCode(code)
+
+	var voucher *models.Voucher
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		v, err := s.voucherRepo.FindByCodeHashForUpdate(ctx, tx, hash)
+		if err != nil {
+			return fmt.Errorf("failed to load voucher: %w", err)
+		}
+		if err := authorizeVoucher(ctx, v); err != nil {
+			return err
+		}
+		switch v.Status {
+		case models.VoucherRedeemed:
+			return ErrVoucherRedeemed
+		case models.VoucherVoid:
+			return ErrVoucherVoid
+		}
+
+		now := s.clock.Now()
+		var by *uuid.UUID
+		if actor, err := uuid.Parse(audit.ActorFrom(ctx).ID); err == nil {
+			by = &actor
+		}
+		redeemed, err := s.voucherRepo.Redeem(ctx, tx, v.ID, by, now)
+		if err != nil {
+			return fmt.Errorf("failed to redeem voucher: %w", err)
+		}
+		if !redeemed {
+			return ErrVoucherRedeemed
+		}
+		v.Status = models.VoucherRedeemed
+		v.RedeemedAt = &now
+		v.RedeemedBy = by
+		voucher = v
+
+		return s.audit.Record(ctx, tx, audit.Change{
+			Action:       audit.ActionVoucherRedeem,
+			ResourceType: audit.ResourceVoucher,
+			ResourceID:   v.ID.String(),
+			Before:       map[string]interface{}{"status": models.VoucherIssued},
+			After:        map[string]interface{}{"status": models.VoucherRedeemed, "campaignId": v.CampaignID},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return voucher, nil
+}
+
+// authorizeVoucher lets merchants use only the vouchers of their own
+// campaigns; ops, admins and internal services may use any. Unknown
+// vouchers and those of other merchants look the same.
+func authorizeVoucher(ctx context.Context, v *models.Voucher) error {
+	if v == nil {
+		return ErrVoucherNotFound
+	}
+	if rbac.Allows(rbac.RoleFrom(ctx), models.RoleOps) {
+		return nil
+	}
+	if v.MerchantID == nil || v.MerchantID.String() != audit.ActorFrom(ctx).ID {
+		return ErrVoucherNotFound
+	}
+	return nil
+}
+
+// reveal fills in the code and QR payload of the participant's voucher
+func (s *VoucherService) reveal(v *models.Voucher) *models.Voucher {
+	if v.Status == models.VoucherIssued {
+		v.Code = s.code(v.ID)
+		v.QRPayload = voucherQRPrefix + strings.ReplaceAll(v.Code, "-", "")
+	}
+	return v
+}
+
+// code derives the voucher's code from its id, e.g. "ABCD-EFGH-JKLM"
+func (s *VoucherService) code(id uuid.UUID) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(id[:])
+	sum := mac.Sum(nil)
+
+	var b strings.Builder
+	for i := 0; i < voucherCodeLength; i++ {
+		if i > 0 && i%voucherCodeGroup == 0 {
+			b.WriteByte('-')
+		}
+		// 256 is a multiple of len(voucherAlphabet), so this is unbiased
+		b.WriteByte(voucherAlphabet[int(sum[i])%len(voucherAlphabet)])
+	}
+	return b.String()
+}
+
+// hashVoucherCode hashes a code as typed or scanned: case, spaces and
+// dashes are ignored, and a QR payload's prefix is dropped
+func hashVoucherCode(code string) string {
+	code = strings.TrimPrefix(strings.TrimSpace(code), voucherQRPrefix)
+	code = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+// Package address parses and formats EVM addresses. Addresses are stored in
+// their canonical lower-case form (so equality and indexes work on plain
+// strings) and returned to clients EIP-55 checksummed.
+package address
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrInvalid is returned for anything that is not 0x + 40 hex digits
+	ErrInvalid = errors.New("invalid address")
+	// ErrChecksum is returned for mixed-case input whose EIP-55 checksum
+	// does not match, which usually means a typo
+	ErrChecksum = errors.New("address checksum mismatch")
+)
+
+// Parse accepts a 0x-prefixed address in all lower case, all upper case or
+// correct EIP-55 mixed case
+func Parse(s string) (common.Address, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return common.Address{}, ErrInvalid
+	}
+	if !common.IsHexAddress(s) {
+		return common.Address{}, ErrInvalid
+	}
+
+	addr := common.HexToAddress(s)
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr.Hex()[2:] != digits {
+		return common.Address{}, ErrChecksum
+	}
+	return addr, nil
+}
+
+// Checksum returns the EIP-55 form of s, or an error if s does not Parse
+func Checksum(s string) (string, error) {
+	addr, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return addr.Hex(), nil
+}
+
+// Canonical returns the lower-case form of s used for storage, or an error
+// if s does not Parse
+func Canonical(s string) (string, error) {
+	addr, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(addr.Hex()), nil
+}
+
+// Display returns the EIP-55 form of a stored address. Values that are not
+// addresses (e.g. empty) are returned unchanged so responses never fail on
+// legacy data.
+func Display(s string) string {
+	if !common.IsHexAddress(s) {
+		return s
+	}
+	return common.HexToAddress(s).Hex()
+}
+
+// FromBytes returns the EIP-55 form of a 20-byte address stored as BYTEA,
+// or "" for a missing one
+func FromBytes(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return common.BytesToAddress(b).Hex()
+}
+
+// Equal compares two addresses regardless of case. Invalid input is never
+// equal to anything.
+func Equal(a, b string) bool {
+	x, errA := Parse(a)
+	y, errB := Parse(b)
+	return errA == nil && errB == nil && x == y
+}
@@ -0,0 +1,48 @@
+package audit
+
+import "context"
+
+// Actor types
+const (
+	ActorUser    = "user"
+	ActorSystem  = "system"
+	ActorService = "service"
+)
+
+// Headers the gateway uses to pass the authenticated caller to upstream
+// services. The gateway drops any client-supplied values.
+const (
+	ActorIDHeader   = "X-Actor-ID"
+	ActorTypeHeader = "X-Actor-Type"
+)
+
+// Actor is who performed a change
+type Actor struct {
+	ID   string
+	Type string
+	IP   string
+}
+
+type actorKey struct{}
+
+// WithActor attributes changes made with ctx to a
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFrom returns the actor stored by WithActor. Without one the change
+// is attributed to an anonymous internal service call.
+func ActorFrom(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	if a.Type == "" {
+		a.Type = ActorService
+	}
+	return a
+}
+
+// AsSystem attributes changes made with ctx to the system, e.g. for provider
+// webhooks and scheduled jobs, keeping any client IP already recorded
+func AsSystem(ctx context.Context, id string) context.Context {
+	a := ActorFrom(ctx)
+	return WithActor(ctx, Actor{ID: id, Type: ActorSystem, IP: a.IP})
+}
@@ -0,0 +1,204 @@
+// Package audit records who changed what in the audit_log table: the actor,
+// the action, the resource and JSON snapshots of it before and after the
+// change. Entries are written with the caller's transaction so a change and
+// its audit entry commit or roll back together, and the table rejects
+// updates and deletes (pkg/db/migrations/004_audit_log.sql).
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// Actions recorded across services
+const (
+	ActionCampaignCreate   = "campaign.create"
+	ActionCampaignUpdate   = "campaign.update"
+	ActionCampaignMetadata = "campaign.metadata"
+	ActionCampaignFees     = "campaign.fees"
+	ActionCampaignSettle   = "campaign.settle"
+	ActionCampaignPause    = "campaign.pause"
+	ActionCampaignResume   = "campaign.resume"
+	ActionCampaignSubmit   = "campaign.submit"
+	ActionCampaignReview   = "campaign.review"
+	ActionCampaignDeploy   = "campaign.deploy"
+	ActionCampaignFulfill  = "campaign.fulfill"
+	ActionCampaignClone    = "campaign.clone"
+	ActionPaymentRefund    = "payment.refund"
+	ActionRoleGrant        = "role.grant"
+	ActionRoleRevoke       = "role.revoke"
+	ActionUserSuspend      = "user.suspend"
+	ActionUserReinstate    = "user.reinstate"
+	ActionUserKYCTier      = "user.kyc_tier"
+
+	ActionMerchantRegister  = "merchant.register"
+	ActionMerchantApprove   = "merchant.approve"
+	ActionMerchantReject    = "merchant.reject"
+	ActionMerchantSuspend   = "merchant.suspend"
+	ActionMerchantReinstate = "merchant.reinstate"
+	ActionMerchantLogo      = "merchant.logo"
+
+	ActionCategoryCreate = "category.create"
+	ActionCategoryUpdate = "category.update"
+	ActionCategoryDelete = "category.delete"
+
+	ActionTemplateCreate = "campaign_template.create"
+	ActionTemplateUpdate = "campaign_template.update"
+	ActionTemplateDelete = "campaign_template.delete"
+
+	ActionPolicySet    = "campaign_policy.set"
+	ActionPolicyDelete = "campaign_policy.delete"
+
+	ActionDisputeResolve = "dispute.resolve"
+	ActionDisputeRefund  = "dispute.refund"
+
+	ActionVoucherRedeem = "voucher.redeem"
+
+	ActionFeatureFlagSet   = "feature_flag.set"
+	ActionFeatureFlagReset = "feature_flag.reset"
+)
+
+// Resource types
+const (
+	ResourceCampaign    = "campaign"
+	ResourceMerchant    = "merchant"
+	ResourcePayment     = "payment"
+	ResourceUser        = "user"
+	ResourceFeatureFlag = "feature_flag"
+	ResourceCategory    = "campaign_category"
+	ResourceTemplate    = "campaign_template"
+	ResourcePolicy      = "campaign_policy"
+	ResourceDispute     = "dispute"
+	ResourceVoucher     = "voucher"
+)
+
+// Change describes one mutation. Before and After are marshalled to JSON;
+// leave Before nil for creations and After nil for deletions.
+type Change struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       interface{}
+	After        interface{}
+}
+
+// Entry is a stored audit record
+type Entry struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	ActorID      *string   `json:"actorId" db:"actor_id"`
+	ActorType    string    `json:"actorType" db:"actor_type"`
+	Action       string    `json:"action" db:"action"`
+	ResourceType string    `json:"resourceType" db:"resource_type"`
+	ResourceID   string    `json:"resourceId" db:"resource_id"`
+	Before       Snapshot  `json:"before" db:"before"`
+	After        Snapshot  `json:"after" db:"after"`
+	RequestID    *string   `json:"requestId,omitempty" db:"request_id"`
+	ClientIP     *string   `json:"clientIp,omitempty" db:"client_ip"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Snapshot is a stored JSON snapshot; NULL scans to an empty snapshot and
+// encodes as null
+type Snapshot json.RawMessage
+
+func (s *Snapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+	case []byte:
+		*s = append(Snapshot(nil), v...)
+	case string:
+		*s = Snapshot(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Snapshot", value)
+	}
+	return nil
+}
+
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	if len(s) == 0 {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// Store writes and queries the audit log
+type Store struct {
+	db    *database.DB
+	clock clock.Clock
+}
+
+// NewStore returns a store on db
+func NewStore(db *database.DB, clk clock.Clock) *Store {
+	return &Store{db: db, clock: clock.OrSystem(clk)}
+}
+
+// Record writes change attributed to the actor in ctx. Pass the transaction
+// that performs the change as exec; nil writes on its own.
+func (s *Store) Record(ctx context.Context, exec sqlx.ExecerContext, change Change) error {
+	if exec == nil {
+		exec = s.db
+	}
+
+	before, err := snapshot(change.Before)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	after, err := snapshot(change.After)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+
+	actor := ActorFrom(ctx)
+	query := `
+		INSERT INTO audit_log (
+			id, actor_id, actor_type, action, resource_type, resource_id,
+			before, after, request_id, client_ip, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err = exec.ExecContext(ctx, query,
+		uuid.New(),
+		nullString(actor.ID),
+		actor.Type,
+		change.Action,
+		change.ResourceType,
+		change.ResourceID,
+		before,
+		after,
+		nullString(logger.RequestID(ctx)),
+		nullString(actor.IP),
+		s.clock.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// snapshot returns v as a JSON string, or nil (SQL NULL) when v is nil
+func snapshot(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,27 @@
+// Package ginaudit adapts pkg/audit to gin. It lives in its own package so
+// services that do not use gin (query-server) do not depend on it.
+package ginaudit
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+)
+
+// Middleware attributes changes made while handling the request to the
+// caller the gateway authenticated (audit.ActorIDHeader), or to an internal
+// service call when there is none, together with the client IP
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := audit.Actor{
+			ID:   c.GetHeader(audit.ActorIDHeader),
+			Type: c.GetHeader(audit.ActorTypeHeader),
+			IP:   c.ClientIP(),
+		}
+		if actor.Type == "" && actor.ID != "" {
+			actor.Type = audit.ActorUser
+		}
+		c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
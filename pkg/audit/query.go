@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+)
+
+// Filter selects audit entries; zero fields are ignored
+type Filter struct {
+	ActorID      string
+	ActorType    string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	Offset       int
+}
+
+func (f Filter) query() *database.SelectBuilder {
+	return database.NewSelect(
+		"id", "actor_id", "actor_type", "action", "resource_type", "resource_id",
+		"before", "after", "request_id", "client_ip", "created_at",
+	).
+		From("audit_log").
+		WhereIf(f.ActorID != "", "actor_id = ?", f.ActorID).
+		WhereIf(f.ActorType != "", "actor_type = ?", f.ActorType).
+		WhereIf(f.Action != "", "action = ?", f.Action).
+		WhereIf(f.ResourceType != "", "resource_type = ?", f.ResourceType).
+		WhereIf(f.ResourceID != "", "resource_id = ?", f.ResourceID).
+		WhereIf(!f.From.IsZero(), "created_at >= ?", f.From).
+		WhereIf(!f.To.IsZero(), "created_at < ?", f.To)
+}
+
+// List returns a page of matching entries, newest first, and the total
+// number matching the filter
+func (s *Store) List(ctx context.Context, f Filter) ([]Entry, int64, error) {
+	q := f.query()
+
+	countSQL, countArgs := q.CountSQL()
+	var total int64
+	if err := s.db.GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query, args := q.OrderBy("created_at DESC", "id").Limit(int64(f.Limit)).Offset(int64(f.Offset)).ToSQL()
+	entries := []Entry{}
+	if err := s.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/sync/singleflight"
+)
+
+// KeyPrefix is prepended to every cache key and invalidation channel
+const KeyPrefix = "r2s:cache"
+
+// DefaultLoadTimeout bounds a GetOrLoad load when Config.LoadTimeout is zero
+const DefaultLoadTimeout = 10 * time.Second
+
+type Config struct {
+	// Namespace isolates keys per service and data type, e.g. "query:campaign"
+	Namespace string
+	// TTL is the default Redis expiry for Set and GetOrLoad
+	TTL time.Duration
+	// LocalTTL enables an in-process copy in front of Redis. Entries are
+	// evicted on expiry or when another instance publishes an invalidation.
+	LocalTTL time.Duration
+	// LoadTimeout bounds the load GetOrLoad shares between callers, which
+	// outlives any one of them
+	LoadTimeout time.Duration
+}
+
+// Cache is a typed, namespaced JSON cache backed by Redis
+type Cache[T any] struct {
+	client redis.UniversalClient
+	cfg    Config
+	group  singleflight.Group
+
+	mu    sync.RWMutex
+	local map[string]localEntry[T]
+	// sweptAt is when expired local entries were last removed
+	sweptAt time.Time
+}
+
+type localEntry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+func New[T any](client redis.UniversalClient, cfg Config) *Cache[T] {
+	return &Cache[T]{
+		client: client,
+		cfg:    cfg,
+		local:  make(map[string]localEntry[T]),
+	}
+}
+
+// Key returns the fully qualified Redis key for key
+func (c *Cache[T]) Key(key string) string {
+	return fmt.Sprintf("%s:%s:%s", KeyPrefix, c.cfg.Namespace, key)
+}
+
+// Channel returns the pub/sub channel used for invalidations of this namespace
+func (c *Cache[T]) Channel() string {
+	return fmt.Sprintf("%s:invalidate:%s", KeyPrefix, c.cfg.Namespace)
+}
+
+// Get returns the cached value and whether it was found
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	v, result, err := c.lookup(ctx, key)
+	lookups.WithLabelValues(c.cfg.Namespace, result).Inc()
+	return v, result == resultLocalHit || result == resultHit, err
+}
+
+// lookup is Get without the lookup metric; it reports where the value was
+// found
+func (c *Cache[T]) lookup(ctx context.Context, key string) (T, string, error) {
+	var zero T
+
+	if v, ok := c.getLocal(key); ok {
+		return v, resultLocalHit, nil
+	}
+
+	raw, err := c.client.Get(ctx, c.Key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return zero, resultMiss, nil
+	}
+	if err != nil {
+		return zero, resultError, fmt.Errorf("cache get %s: %w", key, err)
+	}
+
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return zero, resultError, fmt.Errorf("cache decode %s: %w", key, err)
+	}
+
+	c.setLocal(key, v)
+	return v, resultHit, nil
+}
+
+// Set stores value with the default TTL
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	return c.SetWithTTL(ctx, key, value, c.cfg.TTL)
+}
+
+// SetWithTTL stores value with an explicit TTL
+func (c *Cache[T]) SetWithTTL(ctx context.Context, key string, value T, ttl time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache encode %s: %w", key, err)
+	}
+
+	if err := c.client.Set(ctx, c.Key(key), raw, ttl).Err(); err != nil {
+		return fmt.Errorf("cache set %s: %w", key, err)
+	}
+
+	c.setLocal(key, value)
+	return nil
+}
+
+// GetOrLoad returns the cached value or calls load once per key across
+// concurrent callers in this process and caches the result. The load runs
+// detached from the caller that started it, bounded by LoadTimeout, so a
+// caller giving up does not fail the others; each caller still returns
+// when its own ctx is done.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if v, ok, err := c.Get(ctx, key); err == nil && ok {
+		return v, nil
+	}
+
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		timeout := c.cfg.LoadTimeout
+		if timeout <= 0 {
+			timeout = DefaultLoadTimeout
+		}
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		// Another caller may have filled the cache while we waited; the
+		// miss was already counted
+		if v, result, err := c.lookup(loadCtx, key); err == nil && (result == resultLocalHit || result == resultHit) {
+			return v, nil
+		}
+
+		v, err := load(loadCtx)
+		if err != nil {
+			return v, err
+		}
+
+		// A failed write only costs a future cache miss
+		_ = c.Set(loadCtx, key, v)
+		return v, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+// Invalidate deletes keys and notifies other instances to drop local copies
+func (c *Cache[T]) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = c.Key(k)
+		c.dropLocal(k)
+	}
+
+	if err := c.client.Del(ctx, full...).Err(); err != nil {
+		return fmt.Errorf("cache delete: %w", err)
+	}
+
+	if err := c.client.Publish(ctx, c.Channel(), strings.Join(keys, "\n")).Err(); err != nil {
+		return fmt.Errorf("cache publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe listens for invalidations published by any instance and evicts
+// local copies until ctx is cancelled. onInvalidate, if set, is called for
+// every invalidated key.
+func (c *Cache[T]) Subscribe(ctx context.Context, onInvalidate func(key string)) error {
+	sub := c.client.Subscribe(ctx, c.Channel())
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("cache subscribe: %w", err)
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			for _, key := range strings.Split(msg.Payload, "\n") {
+				c.dropLocal(key)
+				if onInvalidate != nil {
+					onInvalidate(key)
+				}
+			}
+		}
+	}
+}
+
+func (c *Cache[T]) getLocal(key string) (T, bool) {
+	var zero T
+	if c.cfg.LocalTTL <= 0 {
+		return zero, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.local[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return zero, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		c.dropLocal(key)
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *Cache[T]) setLocal(key string, value T) {
+	if c.cfg.LocalTTL <= 0 {
+		return
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.local[key] = localEntry[T]{value: value, expiresAt: now.Add(c.cfg.LocalTTL)}
+
+	// Keys that are never read again would otherwise stay until an
+	// invalidation; a sweep per LocalTTL bounds them to one TTL's writes
+	if now.Sub(c.sweptAt) < c.cfg.LocalTTL {
+		return
+	}
+	for k, entry := range c.local {
+		if now.After(entry.expiresAt) {
+			delete(c.local, k)
+		}
+	}
+	c.sweptAt = now
+}
+
+func (c *Cache[T]) dropLocal(key string) {
+	c.mu.Lock()
+	delete(c.local, key)
+	c.mu.Unlock()
+}
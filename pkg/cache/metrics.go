@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+// Lookup results
+const (
+	resultLocalHit = "local_hit"
+	resultHit      = "hit"
+	resultMiss     = "miss"
+	resultError    = "error"
+)
+
+// lookups counts Get calls so hit rates can be charted per namespace
+var lookups = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Cache lookups by namespace and result (local_hit, hit, miss, error).",
+	},
+	[]string{"namespace", "result"},
+)
+
+func init() {
+	metrics.MustRegister(lookups)
+}
@@ -0,0 +1,38 @@
+// Package clock abstracts the current time so time-based rules (campaign
+// windows, token and nonce expiry, settlement cut-offs) can be driven by a
+// Mock in tests instead of the wall clock.
+package clock
+
+import "time"
+
+// Clock is the subset of the time package services depend on
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the wall clock
+var System Clock = systemClock{}
+
+// New returns the wall clock
+func New() Clock {
+	return System
+}
+
+// OrSystem returns c, or System when c is nil, so constructors can accept an
+// optional clock
+func OrSystem(c Clock) Clock {
+	if c == nil {
+		return System
+	}
+	return c
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (systemClock) Until(t time.Time) time.Duration        { return time.Until(t) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
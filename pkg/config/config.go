@@ -0,0 +1,348 @@
+// Package config loads typed service configuration from defaults, an
+// optional KEY=VALUE file and the environment, in increasing precedence.
+//
+// Fields are described with struct tags:
+//
+//	Port     string `env:"CORE_SERVER_PORT" default:"3003"`
+//	Password string `env:"DB_PASSWORD" required:"true" secret:"true"`
+//
+// Nested structs without an env tag are walked recursively, so shared
+// sections such as Database can be embedded in each service's Config.
+// Values already set on the struct before Load take the place of the
+// default tag, which lets a service override a shared default.
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileEnv names an optional config file read when no WithFile option is given
+const FileEnv = "CONFIG_FILE"
+
+const redacted = "<redacted>"
+
+// Validator is implemented by config structs with cross-field rules
+type Validator interface {
+	Validate() error
+}
+
+// Option customizes Load
+type Option func(*loader)
+
+// WithFile reads KEY=VALUE pairs from path; a missing file is an error
+func WithFile(path string) Option {
+	return func(l *loader) {
+		l.file = path
+	}
+}
+
+// WithLookup replaces os.LookupEnv as the environment source
+func WithLookup(lookup func(string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookup = lookup
+	}
+}
+
+type loader struct {
+	file   string
+	lookup func(string) (string, bool)
+	values map[string]string
+}
+
+// field is one env-tagged leaf of a config struct
+type field struct {
+	name     string
+	value    reflect.Value
+	def      string
+	required bool
+	secret   bool
+}
+
+// Load fills cfg, which must be a pointer to a struct. All missing required
+// values and unparsable values are reported together.
+func Load(cfg interface{}, opts ...Option) error {
+	l := &loader{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.file == "" {
+		l.file, _ = l.lookup(FileEnv)
+	}
+	if l.file != "" {
+		values, err := readFile(l.file)
+		if err != nil {
+			return err
+		}
+		l.values = values
+	}
+
+	fields, err := collect(cfg)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	var errs []error
+	for _, f := range fields {
+		raw, ok := l.get(f.name)
+		if !ok {
+			if !f.value.IsZero() {
+				continue
+			}
+			if f.def == "" {
+				if f.required {
+					missing = append(missing, f.name)
+				}
+				continue
+			}
+			raw = f.def
+		}
+		if err := set(f.value, raw); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %w", f.name, err))
+			continue
+		}
+		if f.required && f.value.IsZero() {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		errs = append([]error{fmt.Errorf("missing required config: %s", strings.Join(missing, ", "))}, errs...)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return validate(reflect.ValueOf(cfg).Elem())
+}
+
+// MustLoad loads cfg and exits on error. When the process was started with
+// -print-config it prints the redacted effective config and exits instead.
+func MustLoad(cfg interface{}, opts ...Option) {
+	err := Load(cfg, opts...)
+	if printRequested(os.Args[1:]) {
+		if perr := Print(os.Stdout, cfg); perr != nil {
+			log.Fatalf("Failed to print config: %v", perr)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+}
+
+// Print writes the effective config as KEY=VALUE lines with secrets redacted
+func Print(w io.Writer, cfg interface{}) error {
+	lines, err := Redacted(cfg)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Redacted returns the effective config as KEY=VALUE lines in declaration
+// order. Secrets that are set are replaced with a placeholder.
+func Redacted(cfg interface{}) ([]string, error) {
+	fields, err := collect(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(fields))
+	for _, f := range fields {
+		v := format(f.value)
+		if f.secret && v != "" {
+			v = redacted
+		}
+		lines = append(lines, f.name+"="+v)
+	}
+	return lines, nil
+}
+
+func (l *loader) get(name string) (string, bool) {
+	if v, ok := l.lookup(name); ok {
+		return v, true
+	}
+	v, ok := l.values[name]
+	return v, ok
+}
+
+func printRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "-print-config" || arg == "--print-config" {
+			return true
+		}
+	}
+	return false
+}
+
+func collect(cfg interface{}) ([]field, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a non-nil pointer to a struct, got %T", cfg)
+	}
+	var fields []field
+	walk(v.Elem(), &fields)
+	return fields, nil
+}
+
+func walk(v reflect.Value, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := sf.Tag.Lookup("env")
+		if !ok {
+			if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+				walk(v.Field(i), fields)
+			}
+			continue
+		}
+		*fields = append(*fields, field{
+			name:     name,
+			value:    v.Field(i),
+			def:      sf.Tag.Get("default"),
+			required: sf.Tag.Get("required") == "true",
+			secret:   sf.Tag.Get("secret") == "true",
+		})
+	}
+}
+
+// validate runs Validate on nested sections first, then on v itself
+func validate(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() == reflect.Struct && v.Type().Field(i).IsExported() {
+			if err := validate(f); err != nil {
+				return err
+			}
+		}
+	}
+	if v.CanAddr() {
+		if val, ok := v.Addr().Interface().(Validator); ok {
+			return val.Validate()
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func set(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", v.Type())
+		}
+		var parts []string
+		for _, p := range strings.Split(raw, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		v.Set(reflect.ValueOf(parts))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func format(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// readFile parses a dotenv-style file: KEY=VALUE lines, # comments, an
+// optional "export " prefix and optional surrounding quotes.
+func readFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,283 @@
+// Package cors applies a configurable CORS policy: an allowlist of browser
+// origins, the methods and headers they may use, per-route method
+// restrictions and preflight caching. Origins that are not allowed get no
+// CORS headers, so browsers keep the responses from their pages.
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// AllowedOrigins lists the browser origins allowed to call, such as
+	// https://app.example.com. An entry may take a wildcard subdomain
+	// (https://*.example.com), be "null" for pages opened from files, or be
+	// "*" for any origin, which cannot be combined with credentials. Empty,
+	// no cross-origin calls are allowed.
+	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods []string `env:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,PATCH,DELETE"`
+	// AllowedHeaders are the request headers pages may send besides the
+	// CORS-safelisted ones
+	AllowedHeaders []string `env:"CORS_ALLOWED_HEADERS" default:"Content-Type,Authorization"`
+	// ExposedHeaders are the response headers pages may read besides the
+	// CORS-safelisted ones
+	ExposedHeaders   []string `env:"CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" default:"false"`
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration `env:"CORS_MAX_AGE" default:"10m"`
+	// RouteMethods narrows the methods allowed on matching paths, as
+	// PATH=METHOD METHOD entries. Paths take :name segments and a trailing
+	// *; an entry without methods keeps browsers off the path altogether.
+	RouteMethods []string `env:"CORS_ROUTE_METHODS"`
+}
+
+// Validate rejects malformed origins and route entries, and a wildcard
+// origin with credentials, which browsers refuse
+func (c *Config) Validate() error {
+	_, err := New(*c)
+	return err
+}
+
+// Route is a path pattern and the methods allowed on it
+type Route struct {
+	Path    string
+	Methods []string
+}
+
+// Policy decides the CORS headers of requests
+type Policy struct {
+	anyOrigin bool
+	origins   map[string]bool
+	// wildcards are scheme://*.domain entries, split at the *
+	wildcards   [][2]string
+	methods     []string
+	headers     map[string]bool
+	credentials bool
+	allowHeader string
+	exposed     string
+	maxAge      string
+	// restricted are the configured RouteMethods entries
+	restricted []Route
+	// registered are the served routes; nil until WithRoutes
+	registered []Route
+}
+
+// New returns the policy of cfg
+func New(cfg Config) (*Policy, error) {
+	p := &Policy{
+		origins:     make(map[string]bool),
+		headers:     make(map[string]bool),
+		credentials: cfg.AllowCredentials,
+		exposed:     strings.Join(cfg.ExposedHeaders, ", "),
+		maxAge:      strconv.Itoa(int(cfg.MaxAge / time.Second)),
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		switch {
+		case origin == "*":
+			if cfg.AllowCredentials {
+				return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS cannot be * with CORS_ALLOW_CREDENTIALS")
+			}
+			p.anyOrigin = true
+		case origin == "null":
+			p.origins[origin] = true
+		case strings.Contains(origin, "://*."):
+			scheme, domain, _ := strings.Cut(origin, "://*")
+			if err := checkOrigin(scheme + "://x" + domain); err != nil {
+				return nil, err
+			}
+			p.wildcards = append(p.wildcards, [2]string{scheme + "://", domain})
+		default:
+			if err := checkOrigin(origin); err != nil {
+				return nil, err
+			}
+			p.origins[origin] = true
+		}
+	}
+	for _, method := range cfg.AllowedMethods {
+		p.methods = append(p.methods, strings.ToUpper(method))
+	}
+	var allowed []string
+	for _, header := range cfg.AllowedHeaders {
+		p.headers[strings.ToLower(header)] = true
+		allowed = append(allowed, http.CanonicalHeaderKey(header))
+	}
+	p.allowHeader = strings.Join(allowed, ", ")
+	if cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("CORS_MAX_AGE must not be negative")
+	}
+	for _, entry := range cfg.RouteMethods {
+		path, methods, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("CORS_ROUTE_METHODS entry %q must be PATH=METHOD METHOD", entry)
+		}
+		route := Route{Path: path}
+		for _, method := range strings.Fields(methods) {
+			route.Methods = append(route.Methods, strings.ToUpper(method))
+		}
+		p.restricted = append(p.restricted, route)
+	}
+	return p, nil
+}
+
+// checkOrigin accepts a bare scheme://host[:port] origin
+func checkOrigin(origin string) error {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+		return fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must be scheme://host[:port]", origin)
+	}
+	return nil
+}
+
+// WithRoutes returns a copy of p that allows each path only the methods it
+// is served with, and rejects preflights of paths that are not served
+func (p *Policy) WithRoutes(routes []Route) *Policy {
+	q := *p
+	q.registered = routes
+	return &q
+}
+
+// AllowOrigin reports whether pages of origin may call
+func (p *Policy) AllowOrigin(origin string) bool {
+	if p.anyOrigin || p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) && len(origin) > len(w[0])+len(w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Methods returns the methods pages may use on path: the allowed methods,
+// narrowed by the matching RouteMethods entry and the registered routes
+func (p *Policy) Methods(path string) []string {
+	methods := p.methods
+	for _, route := range p.restricted {
+		if Match(route.Path, path) {
+			methods = intersect(methods, route.Methods)
+			break
+		}
+	}
+	if p.registered == nil {
+		return methods
+	}
+	var served []string
+	for _, route := range p.registered {
+		if Match(route.Path, path) {
+			served = append(served, route.Methods...)
+		}
+	}
+	return intersect(methods, served)
+}
+
+// Handle sets the CORS headers of the response to r. It reports whether r
+// is a preflight request, which the caller answers with status alone: 204
+// when the request it announces is allowed, 403 otherwise. Other requests
+// are served as usual, with CORS headers only when the origin and method
+// are allowed.
+func (p *Policy) Handle(h http.Header, r *http.Request) (preflight bool, status int) {
+	origin := r.Header.Get("Origin")
+	requested := r.Header.Get("Access-Control-Request-Method")
+	preflight = r.Method == http.MethodOptions && requested != ""
+	if origin == "" {
+		return preflight, http.StatusNoContent
+	}
+	if preflight {
+		h.Add("Vary", "Origin, Access-Control-Request-Method, Access-Control-Request-Headers")
+	} else {
+		h.Add("Vary", "Origin")
+	}
+
+	method := r.Method
+	if preflight {
+		method = strings.ToUpper(requested)
+	}
+	methods := p.Methods(r.URL.Path)
+	if !p.AllowOrigin(origin) || !slices.Contains(methods, method) {
+		return preflight, http.StatusForbidden
+	}
+	if preflight {
+		for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+			if header = strings.ToLower(strings.TrimSpace(header)); header != "" && !p.headers[header] {
+				return preflight, http.StatusForbidden
+			}
+		}
+	}
+
+	if p.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		if p.exposed != "" {
+			h.Set("Access-Control-Expose-Headers", p.exposed)
+		}
+		return false, 0
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if p.allowHeader != "" {
+		h.Set("Access-Control-Allow-Headers", p.allowHeader)
+	}
+	h.Set("Access-Control-Max-Age", p.maxAge)
+	return true, http.StatusNoContent
+}
+
+// Handler applies p to the requests of next, answering preflights itself
+func (p *Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if preflight, status := p.Handle(w.Header(), r); preflight {
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Match reports whether path matches pattern, whose :name segments match
+// any one segment and whose trailing *name matches the rest of the path
+func Match(pattern, path string) bool {
+	for {
+		if strings.HasPrefix(pattern, "*") {
+			return true
+		}
+		pseg, prest, pmore := strings.Cut(pattern, "/")
+		seg, rest, more := strings.Cut(path, "/")
+		if pmore != more {
+			return false
+		}
+		if strings.HasPrefix(pseg, ":") {
+			if seg == "" {
+				return false
+			}
+		} else if pseg != seg {
+			return false
+		}
+		if !pmore {
+			return true
+		}
+		pattern, path = prest, rest
+	}
+}
+
+// intersect returns the methods of a also in b, in a's order
+func intersect(a, b []string) []string {
+	out := []string{}
+	for _, method := range a {
+		if slices.Contains(b, method) {
+			out = append(out, method)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+// Package gincors adapts pkg/cors to gin
+package gincors
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/pkg/cors"
+)
+
+// Middleware applies p to every request, answering preflights itself. It
+// must be registered with engine.Use, which also runs it for unmatched
+// paths such as preflights. With an engine, each path allows only the
+// methods it is registered with; routes are read on the first request,
+// once all of them are registered.
+func Middleware(p *cors.Policy, engine *gin.Engine) gin.HandlerFunc {
+	var once sync.Once
+	policy := p
+	return func(c *gin.Context) {
+		once.Do(func() {
+			if engine != nil {
+				policy = p.WithRoutes(Routes(engine.Routes()))
+			}
+		})
+		if preflight, status := policy.Handle(c.Writer.Header(), c.Request); preflight {
+			c.AbortWithStatus(status)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Routes groups gin's routes by path
+func Routes(routes gin.RoutesInfo) []cors.Route {
+	var out []cors.Route
+	index := make(map[string]int)
+	for _, route := range routes {
+		i, ok := index[route.Path]
+		if !ok {
+			i = len(out)
+			index[route.Path] = i
+			out = append(out, cors.Route{Path: route.Path})
+		}
+		out[i].Methods = append(out[i].Methods, route.Method)
+	}
+	return out
+}
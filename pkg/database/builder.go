@@ -0,0 +1,160 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SelectBuilder assembles a SELECT with optional filters and paging. Conditions
+// use ? placeholders which are rebound to $n by ToSQL, so filters can be added
+// in any order without tracking parameter numbers. Literal question marks
+// (e.g. the jsonb ? operator) are not supported in builder fragments.
+type SelectBuilder struct {
+	columns []string
+	from    string
+	joins   []string
+	// joinArgs are bound before args, as joins precede the WHERE clause
+	joinArgs []interface{}
+	where    []string
+	args     []interface{}
+	groupBy  []string
+	orderBy  []string
+	limit    int64
+	offset   int64
+}
+
+// NewSelect starts a SELECT of the given columns
+func NewSelect(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: columns}
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+	return b
+}
+
+// Join adds a join clause verbatim, e.g. "JOIN merchants m ON c.merchant_id = m.id",
+// with the arguments of its placeholders
+func (b *SelectBuilder) Join(clause string, args ...interface{}) *SelectBuilder {
+	b.joins = append(b.joins, clause)
+	b.joinArgs = append(b.joinArgs, args...)
+	return b
+}
+
+// Where adds a condition; multiple conditions are ANDed
+func (b *SelectBuilder) Where(cond string, args ...interface{}) *SelectBuilder {
+	b.where = append(b.where, cond)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// WhereIf adds the condition only when ok is true
+func (b *SelectBuilder) WhereIf(ok bool, cond string, args ...interface{}) *SelectBuilder {
+	if ok {
+		return b.Where(cond, args...)
+	}
+	return b
+}
+
+// WhereAny adds "expr = ANY(?)" with values as one array parameter, matching
+// any of them; an empty slice adds nothing
+func (b *SelectBuilder) WhereAny(expr string, values []string) *SelectBuilder {
+	return b.WhereIf(len(values) > 0, expr+" = ANY(?)", pq.Array(values))
+}
+
+// WhereAnyID is WhereAny for UUID ids
+func (b *SelectBuilder) WhereAnyID(expr string, ids []string) *SelectBuilder {
+	return b.WhereIf(len(ids) > 0, expr+" = ANY(?::UUID[])", pq.Array(ids))
+}
+
+// WhereContainsAll adds "expr @> ?" for an array column holding every one of
+// values; an empty slice adds nothing
+func (b *SelectBuilder) WhereContainsAll(expr string, values []string) *SelectBuilder {
+	return b.WhereIf(len(values) > 0, expr+" @> ?", pq.Array(values))
+}
+
+// LikePattern returns a pattern for LIKE and ILIKE matching q anywhere, with
+// q's own wildcards escaped
+func LikePattern(q string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(q) + "%"
+}
+
+func (b *SelectBuilder) GroupBy(exprs ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, exprs...)
+	return b
+}
+
+func (b *SelectBuilder) OrderBy(exprs ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, exprs...)
+	return b
+}
+
+// Limit sets LIMIT; zero or negative means no limit
+func (b *SelectBuilder) Limit(n int64) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+func (b *SelectBuilder) Offset(n int64) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// ToSQL returns the statement with $n placeholders and its arguments
+func (b *SelectBuilder) ToSQL() (string, []interface{}) {
+	var sb strings.Builder
+	args := b.fromWhereArgs()
+
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(b.columns, ", "))
+	b.writeFromWhere(&sb)
+
+	if len(b.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(b.groupBy, ", "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+	if b.limit > 0 {
+		sb.WriteString(" LIMIT ?")
+		args = append(args, b.limit)
+	}
+	if b.offset > 0 {
+		sb.WriteString(" OFFSET ?")
+		args = append(args, b.offset)
+	}
+
+	return sqlx.Rebind(sqlx.DOLLAR, sb.String()), args
+}
+
+// CountSQL returns a COUNT(*) over the same FROM, joins and filters, ignoring
+// ordering and paging, for total counts alongside a paged list. Grouped
+// queries need their own count.
+func (b *SelectBuilder) CountSQL() (string, []interface{}) {
+	var sb strings.Builder
+	sb.WriteString("SELECT COUNT(*)")
+	b.writeFromWhere(&sb)
+	return sqlx.Rebind(sqlx.DOLLAR, sb.String()), b.fromWhereArgs()
+}
+
+// fromWhereArgs returns the arguments of writeFromWhere, in order
+func (b *SelectBuilder) fromWhereArgs() []interface{} {
+	return append(append([]interface{}{}, b.joinArgs...), b.args...)
+}
+
+func (b *SelectBuilder) writeFromWhere(sb *strings.Builder) {
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.from)
+	for _, j := range b.joins {
+		sb.WriteString(" ")
+		sb.WriteString(j)
+	}
+	if len(b.where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(b.where, " AND "))
+	}
+}
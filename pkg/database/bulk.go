@@ -0,0 +1,191 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DefaultBulkBatchSize is the number of rows sent per COPY when
+// BulkInsert.BatchSize is zero
+const DefaultBulkBatchSize = 5000
+
+// ConflictAction decides what happens to rows that hit ConflictColumns
+type ConflictAction int
+
+const (
+	// ConflictNone copies straight into the table; a duplicate fails the batch
+	ConflictNone ConflictAction = iota
+	// ConflictDoNothing skips rows that already exist
+	ConflictDoNothing
+	// ConflictUpdate overwrites UpdateColumns of existing rows
+	ConflictUpdate
+)
+
+// BulkInsert describes a COPY-based bulk load into Table
+type BulkInsert struct {
+	Table   string
+	Columns []string
+
+	// OnConflict other than ConflictNone stages each batch in a temporary
+	// table and merges it with INSERT ... ON CONFLICT (ConflictColumns)
+	OnConflict      ConflictAction
+	ConflictColumns []string
+	// UpdateColumns defaults to Columns minus ConflictColumns
+	UpdateColumns []string
+
+	// BatchSize rows are copied and committed per transaction
+	BatchSize int
+}
+
+// BulkInsert loads rows in batches, committing each batch in its own
+// transaction so a large backfill makes progress and does not hold one huge
+// transaction open. It returns the number of rows written; on error, batches
+// committed before the failure remain.
+func (db *DB) BulkInsert(ctx context.Context, spec BulkInsert, rows [][]interface{}) (int64, error) {
+	size := spec.BatchSize
+	if size <= 0 {
+		size = DefaultBulkBatchSize
+	}
+
+	var total int64
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		tx, err := db.BeginTxx(ctx, nil)
+		if err != nil {
+			return total, fmt.Errorf("failed to begin bulk insert: %w", err)
+		}
+
+		n, err := CopyIn(ctx, tx, spec, rows[start:end])
+		if err != nil {
+			tx.Rollback()
+			return total, fmt.Errorf("bulk insert into %s (rows %d-%d): %w", spec.Table, start, end-1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return total, fmt.Errorf("failed to commit bulk insert: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// CopyIn copies rows inside an existing transaction
+func CopyIn(ctx context.Context, tx *sqlx.Tx, spec BulkInsert, rows [][]interface{}) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if spec.OnConflict == ConflictNone {
+		return copyRows(ctx, tx, spec.Table, spec.Columns, rows)
+	}
+	if len(spec.ConflictColumns) == 0 {
+		return 0, fmt.Errorf("conflict handling requires ConflictColumns")
+	}
+
+	// Stage into a temp table shaped like the target, then merge
+	staging := "bulk_" + strings.ReplaceAll(spec.Table, ".", "_")
+	create := fmt.Sprintf(`CREATE TEMP TABLE IF NOT EXISTS %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP`,
+		pq.QuoteIdentifier(staging), quoteTable(spec.Table))
+	if _, err := tx.ExecContext(ctx, create); err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `TRUNCATE `+pq.QuoteIdentifier(staging)); err != nil {
+		return 0, fmt.Errorf("failed to truncate staging table: %w", err)
+	}
+
+	if _, err := copyRows(ctx, tx, staging, spec.Columns, rows); err != nil {
+		return 0, err
+	}
+
+	cols := quoteIdents(spec.Columns)
+	merge := fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT (%s) `,
+		quoteTable(spec.Table), cols, cols, pq.QuoteIdentifier(staging), quoteIdents(spec.ConflictColumns))
+
+	if spec.OnConflict == ConflictDoNothing {
+		merge += `DO NOTHING`
+	} else {
+		update := spec.UpdateColumns
+		if len(update) == 0 {
+			update = without(spec.Columns, spec.ConflictColumns)
+		}
+		sets := make([]string, len(update))
+		for i, c := range update {
+			q := pq.QuoteIdentifier(c)
+			sets[i] = q + " = EXCLUDED." + q
+		}
+		merge += `DO UPDATE SET ` + strings.Join(sets, ", ")
+	}
+
+	res, err := tx.ExecContext(ctx, merge)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge staged rows: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func copyRows(ctx context.Context, tx *sqlx.Tx, table string, columns []string, rows [][]interface{}) (int64, error) {
+	var stmt *sqlx.Stmt
+	var err error
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		stmt, err = tx.PreparexContext(ctx, pq.CopyInSchema(schema, name, columns...))
+	} else {
+		stmt, err = tx.PreparexContext(ctx, pq.CopyIn(table, columns...))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare COPY: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("row %d has %d values, want %d", i, len(row), len(columns))
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return 0, fmt.Errorf("failed to copy row %d: %w", i, err)
+		}
+	}
+
+	// An Exec without arguments flushes the buffered rows
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, fmt.Errorf("failed to flush COPY: %w", err)
+	}
+	return int64(len(rows)), nil
+}
+
+func quoteTable(table string) string {
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name)
+	}
+	return pq.QuoteIdentifier(table)
+}
+
+func quoteIdents(idents []string) string {
+	out := make([]string, len(idents))
+	for i, id := range idents {
+		out[i] = pq.QuoteIdentifier(id)
+	}
+	return strings.Join(out, ", ")
+}
+
+func without(all, skip []string) []string {
+	var out []string
+	for _, a := range all {
+		keep := true
+		for _, s := range skip {
+			if a == s {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, a)
+		}
+	}
+	return out
+}
@@ -0,0 +1,297 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DriverName is the instrumented postgres driver used by NewDB. It wraps
+// lib/pq so every statement is reported to the registered QueryHooks.
+const DriverName = "postgres-instrumented"
+
+func init() {
+	sql.Register(DriverName, &instrumentedDriver{parent: &pq.Driver{}})
+	sqlx.BindDriver(DriverName, sqlx.DOLLAR)
+}
+
+// QueryEvent describes a single statement executed through the driver
+type QueryEvent struct {
+	Op           string // query, exec, prepare, begin, commit, rollback
+	Query        string
+	Start        time.Time
+	Duration     time.Duration
+	RowsAffected int64 // -1 when unknown
+	Err          error
+}
+
+// QueryHook observes statements executed through the instrumented driver
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, ev *QueryEvent)
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   []QueryHook
+)
+
+// AddQueryHook registers a hook for all connections opened with DriverName
+func AddQueryHook(h QueryHook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks = append(hooks, h)
+}
+
+func currentHooks() []QueryHook {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+	return hooks
+}
+
+// observe runs fn between the Before/After hooks and returns its error
+func observe(ctx context.Context, op, query string, fn func(ctx context.Context) (int64, error)) error {
+	hs := currentHooks()
+	ev := &QueryEvent{Op: op, Query: query, Start: time.Now(), RowsAffected: -1}
+	for _, h := range hs {
+		ctx = h.BeforeQuery(ctx, ev)
+	}
+
+	rows, err := fn(ctx)
+	ev.Duration = time.Since(ev.Start)
+	ev.RowsAffected = rows
+	ev.Err = err
+
+	for i := len(hs) - 1; i >= 0; i-- {
+		hs[i].AfterQuery(ctx, ev)
+	}
+	return err
+}
+
+func rowsAffected(res driver.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+type instrumentedDriver struct {
+	parent driver.Driver
+}
+
+func (d *instrumentedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.parent.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{parent: c}, nil
+}
+
+type instrumentedConn struct {
+	parent driver.Conn
+}
+
+func (c *instrumentedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	err := observe(ctx, "prepare", query, func(ctx context.Context) (int64, error) {
+		var err error
+		if p, ok := c.parent.(driver.ConnPrepareContext); ok {
+			stmt, err = p.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.parent.Prepare(query)
+		}
+		return -1, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedStmt{parent: stmt, query: query, copy: isCopy(query)}, nil
+}
+
+func (c *instrumentedConn) Close() error {
+	return c.parent.Close()
+}
+
+func (c *instrumentedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	err := observe(ctx, "begin", "BEGIN", func(ctx context.Context) (int64, error) {
+		var err error
+		if b, ok := c.parent.(driver.ConnBeginTx); ok {
+			tx, err = b.BeginTx(ctx, opts)
+		} else {
+			tx, err = c.parent.Begin()
+		}
+		return -1, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{parent: tx, ctx: ctx}, nil
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.parent.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	var res driver.Result
+	err := observe(ctx, "exec", query, func(ctx context.Context) (int64, error) {
+		var err error
+		res, err = execer.ExecContext(ctx, query, args)
+		return rowsAffected(res), err
+	})
+	return res, err
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.parent.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	var rows driver.Rows
+	err := observe(ctx, "query", query, func(ctx context.Context) (int64, error) {
+		var err error
+		rows, err = queryer.QueryContext(ctx, query, args)
+		return -1, err
+	})
+	return rows, err
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if p, ok := c.parent.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.parent.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if v, ok := c.parent.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+type instrumentedTx struct {
+	parent driver.Tx
+	ctx    context.Context
+}
+
+func (t *instrumentedTx) Commit() error {
+	return observe(t.ctx, "commit", "COMMIT", func(context.Context) (int64, error) {
+		return -1, t.parent.Commit()
+	})
+}
+
+func (t *instrumentedTx) Rollback() error {
+	return observe(t.ctx, "rollback", "ROLLBACK", func(context.Context) (int64, error) {
+		return -1, t.parent.Rollback()
+	})
+}
+
+type instrumentedStmt struct {
+	parent driver.Stmt
+	query  string
+	// copy statements buffer one Exec per row; only the final flush is observed
+	copy bool
+}
+
+func isCopy(query string) bool {
+	return len(query) >= 4 && strings.EqualFold(query[:4], "COPY")
+}
+
+func (s *instrumentedStmt) Close() error {
+	return s.parent.Close()
+}
+
+func (s *instrumentedStmt) NumInput() int {
+	return s.parent.NumInput()
+}
+
+func (s *instrumentedStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.copy && len(args) > 0 {
+		return s.parent.Exec(args)
+	}
+
+	var res driver.Result
+	err := observe(context.Background(), "exec", s.query, func(context.Context) (int64, error) {
+		var err error
+		res, err = s.parent.Exec(args)
+		return rowsAffected(res), err
+	})
+	return res, err
+}
+
+func (s *instrumentedStmt) Query(args []driver.Value) (driver.Rows, error) {
+	var rows driver.Rows
+	err := observe(context.Background(), "query", s.query, func(context.Context) (int64, error) {
+		var err error
+		rows, err = s.parent.Query(args)
+		return -1, err
+	})
+	return rows, err
+}
+
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.parent.(driver.StmtExecContext)
+	if !ok {
+		return s.Exec(namedToValues(args))
+	}
+
+	var res driver.Result
+	err := observe(ctx, "exec", s.query, func(ctx context.Context) (int64, error) {
+		var err error
+		res, err = execer.ExecContext(ctx, args)
+		return rowsAffected(res), err
+	})
+	return res, err
+}
+
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.parent.(driver.StmtQueryContext)
+	if !ok {
+		return s.Query(namedToValues(args))
+	}
+
+	var rows driver.Rows
+	err := observe(ctx, "query", s.query, func(ctx context.Context) (int64, error) {
+		var err error
+		rows, err = queryer.QueryContext(ctx, args)
+		return -1, err
+	})
+	return rows, err
+}
+
+func namedToValues(named []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(named))
+	for i, nv := range named {
+		values[i] = nv.Value
+	}
+	return values
+}
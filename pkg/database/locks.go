@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RowLock is the row-level lock clause appended to a SELECT
+type RowLock string
+
+const (
+	ForUpdate      RowLock = "FOR UPDATE"
+	ForNoKeyUpdate RowLock = "FOR NO KEY UPDATE"
+	ForShare       RowLock = "FOR SHARE"
+)
+
+// GetForUpdate runs a single-row SELECT inside tx with the given lock clause
+// appended, e.g. GetForUpdate(ctx, tx, ForNoKeyUpdate, &c, "SELECT ... WHERE id = $1", id)
+func GetForUpdate(ctx context.Context, tx *sqlx.Tx, lock RowLock, dest interface{}, query string, args ...interface{}) error {
+	return tx.GetContext(ctx, dest, query+" "+string(lock), args...)
+}
+
+// SelectForUpdate is the multi-row variant of GetForUpdate
+func SelectForUpdate(ctx context.Context, tx *sqlx.Tx, lock RowLock, dest interface{}, query string, args ...interface{}) error {
+	return tx.SelectContext(ctx, dest, query+" "+string(lock), args...)
+}
+
+// LockNamespace separates advisory lock keys of different resource types so
+// that e.g. a campaign and a user never hash onto the same lock
+type LockNamespace int32
+
+const (
+	LockCampaign LockNamespace = iota + 1
+	LockParticipation
+	LockSettlement
+	LockUser
+	LockExport
+	LockMetadata
+)
+
+// AdvisoryKey identifies a pg_advisory_xact_lock(int, int) lock
+type AdvisoryKey struct {
+	Namespace LockNamespace
+	ID        int32
+}
+
+// NewAdvisoryKey derives a key for the resource id (uuid, address, ...)
+func NewAdvisoryKey(ns LockNamespace, id string) AdvisoryKey {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return AdvisoryKey{Namespace: ns, ID: int32(h.Sum32())}
+}
+
+func (k AdvisoryKey) String() string {
+	return fmt.Sprintf("%d:%d", k.Namespace, k.ID)
+}
+
+// AdvisoryXactLock blocks until the advisory lock for key is acquired. The
+// lock is released automatically when tx commits or rolls back.
+func AdvisoryXactLock(ctx context.Context, tx *sqlx.Tx, key AdvisoryKey) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, int32(key.Namespace), key.ID); err != nil {
+		return fmt.Errorf("failed to acquire advisory lock %s: %w", key, err)
+	}
+	return nil
+}
+
+// TryAdvisoryXactLock acquires the advisory lock for key without waiting and
+// reports whether it was obtained
+func TryAdvisoryXactLock(ctx context.Context, tx *sqlx.Tx, key AdvisoryKey) (bool, error) {
+	var ok bool
+	if err := tx.GetContext(ctx, &ok, `SELECT pg_try_advisory_xact_lock($1, $2)`, int32(key.Namespace), key.ID); err != nil {
+		return false, fmt.Errorf("failed to try advisory lock %s: %w", key, err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+var queryDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "r2s",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of statements executed through pkg/database.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	},
+	[]string{"op", "status"},
+)
+
+var redisCmdDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "r2s",
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Latency of Redis commands and pipelines sent through pkg/database.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	},
+	[]string{"cmd", "status"},
+)
+
+func init() {
+	mustRegister(queryDuration)
+	mustRegister(redisCmdDuration)
+	mustRegister(retryCollector{})
+	AddQueryHook(metricsHook{})
+}
+
+// metricsHook records the latency of every statement by operation and outcome
+type metricsHook struct{}
+
+func (metricsHook) BeforeQuery(ctx context.Context, _ *QueryEvent) context.Context {
+	return ctx
+}
+
+func (metricsHook) AfterQuery(_ context.Context, ev *QueryEvent) {
+	status := "ok"
+	if ev.Err != nil {
+		status = "error"
+	}
+	queryDuration.WithLabelValues(ev.Op, status).Observe(ev.Duration.Seconds())
+}
+
+// registerPoolMetrics exposes sql.DBStats (open, idle, in use, wait count and
+// duration) for this pool, labelled with the database name
+func registerPoolMetrics(db *DB, name string) {
+	mustRegister(collectors.NewDBStatsCollector(db.DB.DB, name))
+}
+
+// registerRedisPoolMetrics exposes the connection pool counters of client
+func registerRedisPoolMetrics(client redis.UniversalClient) {
+	mustRegister(redisPoolCollector{client: client})
+}
+
+// redisMetricsHook records the latency of every command by name and outcome.
+// A missing key (redis.Nil) counts as ok.
+type redisMetricsHook struct{}
+
+type redisStartKey struct{}
+
+func (redisMetricsHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (redisMetricsHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	observeRedis(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (redisMetricsHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (redisMetricsHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	observeRedis(ctx, "pipeline", err)
+	return nil
+}
+
+func observeRedis(ctx context.Context, cmd string, err error) {
+	start, ok := ctx.Value(redisStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if err != nil && !errors.Is(err, redis.Nil) {
+		status = "error"
+	}
+	redisCmdDuration.WithLabelValues(cmd, status).Observe(time.Since(start).Seconds())
+}
+
+// redisPoolCollector exports redis.PoolStats
+type redisPoolCollector struct {
+	client redis.UniversalClient
+}
+
+func (redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- redisPoolConnsDesc
+	ch <- redisPoolEventsDesc
+}
+
+func (c redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(redisPoolConnsDesc, prometheus.GaugeValue, float64(stats.IdleConns), "idle")
+	ch <- prometheus.MustNewConstMetric(redisPoolConnsDesc, prometheus.GaugeValue, float64(stats.TotalConns-stats.IdleConns), "in_use")
+	ch <- prometheus.MustNewConstMetric(redisPoolEventsDesc, prometheus.CounterValue, float64(stats.Hits), "hit")
+	ch <- prometheus.MustNewConstMetric(redisPoolEventsDesc, prometheus.CounterValue, float64(stats.Misses), "miss")
+	ch <- prometheus.MustNewConstMetric(redisPoolEventsDesc, prometheus.CounterValue, float64(stats.Timeouts), "timeout")
+	ch <- prometheus.MustNewConstMetric(redisPoolEventsDesc, prometheus.CounterValue, float64(stats.StaleConns), "stale")
+}
+
+var (
+	redisPoolConnsDesc = prometheus.NewDesc(
+		"r2s_redis_pool_connections",
+		"Redis pool connections by state.",
+		[]string{"state"}, nil,
+	)
+	redisPoolEventsDesc = prometheus.NewDesc(
+		"r2s_redis_pool_events_total",
+		"Redis pool lookups by outcome (hit, miss, timeout) and stale connections removed.",
+		[]string{"event"}, nil,
+	)
+)
+
+// retryCollector exports the counters maintained by TransactionWithRetry
+type retryCollector struct{}
+
+func (retryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- retryAbortsDesc
+	ch <- retriesDesc
+	ch <- retriesExhaustedDesc
+}
+
+func (retryCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := GetRetryStats()
+	ch <- prometheus.MustNewConstMetric(retryAbortsDesc, prometheus.CounterValue, float64(stats.Serialization), "serialization")
+	ch <- prometheus.MustNewConstMetric(retryAbortsDesc, prometheus.CounterValue, float64(stats.Deadlocks), "deadlock")
+	ch <- prometheus.MustNewConstMetric(retriesDesc, prometheus.CounterValue, float64(stats.Retries))
+	ch <- prometheus.MustNewConstMetric(retriesExhaustedDesc, prometheus.CounterValue, float64(stats.Exhausted))
+}
+
+var (
+	retryAbortsDesc = prometheus.NewDesc(
+		"r2s_db_tx_retryable_aborts_total",
+		"Transactions aborted with a retryable Postgres error.",
+		[]string{"reason"}, nil,
+	)
+	retriesDesc = prometheus.NewDesc(
+		"r2s_db_tx_retries_total",
+		"Transaction attempts re-run after a retryable error.",
+		nil, nil,
+	)
+	retriesExhaustedDesc = prometheus.NewDesc(
+		"r2s_db_tx_retries_exhausted_total",
+		"Transactions that still failed after the last retry.",
+		nil, nil,
+	)
+)
+
+// mustRegister registers c on the default registry, tolerating duplicates so
+// that opening several pools in one process does not panic
+func mustRegister(c prometheus.Collector) {
+	if err := prometheus.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// ChangeChannel is the NOTIFY channel used by the r2s_notify_change trigger
+// (pkg/db/migrations/002_change_notify.sql) and by NotifyChange
+const ChangeChannel = "r2s_changes"
+
+// ChangeRelayChannel is the Redis channel ChangeEvents are relayed on, for
+// services without a database connection (the gateway's response cache)
+const ChangeRelayChannel = "r2s:changes"
+
+// ChangeEvent is the payload published on ChangeChannel
+type ChangeEvent struct {
+	Table string `json:"table"`
+	Op    string `json:"op"` // INSERT, UPDATE, DELETE
+	ID    string `json:"id"`
+	// CampaignID is the campaign of a participations row; empty for other tables
+	CampaignID string `json:"campaign_id,omitempty"`
+}
+
+// Notify publishes payload on channel. Non-string payloads are JSON encoded.
+func (db *DB) Notify(ctx context.Context, channel string, payload interface{}) error {
+	return notify(ctx, db.DB, channel, payload)
+}
+
+// NotifyTx publishes payload on channel when tx commits
+func NotifyTx(ctx context.Context, tx *sqlx.Tx, channel string, payload interface{}) error {
+	return notify(ctx, tx, channel, payload)
+}
+
+// NotifyChange publishes a ChangeEvent on ChangeChannel when tx commits, for
+// writes that should propagate but are not covered by the table triggers
+func NotifyChange(ctx context.Context, tx *sqlx.Tx, table, op, id string) error {
+	return NotifyTx(ctx, tx, ChangeChannel, ChangeEvent{Table: table, Op: op, ID: id})
+}
+
+func notify(ctx context.Context, ex sqlx.ExecerContext, channel string, payload interface{}) error {
+	var body string
+	switch p := payload.(type) {
+	case string:
+		body = p
+	case []byte:
+		body = string(p)
+	default:
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return fmt.Errorf("failed to encode notify payload: %w", err)
+		}
+		body = string(raw)
+	}
+
+	if _, err := ex.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, body); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Listener receives NOTIFY messages on a dedicated connection and reconnects
+// with backoff when the connection drops
+type Listener struct {
+	listener *pq.Listener
+	channels []string
+
+	// OnReconnect is called after the connection was re-established. Messages
+	// sent while disconnected are lost, so callers should resync state here.
+	OnReconnect func()
+}
+
+func NewListener(cfg Config, channels ...string) (*Listener, error) {
+	l := &Listener{channels: channels}
+
+	l.listener = pq.NewListener(cfg.DSN(), time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if ev == pq.ListenerEventReconnected && l.OnReconnect != nil {
+			l.OnReconnect()
+		}
+	})
+
+	for _, ch := range channels {
+		if err := l.listener.Listen(ch); err != nil {
+			l.listener.Close()
+			return nil, fmt.Errorf("failed to listen on %s: %w", ch, err)
+		}
+	}
+
+	return l, nil
+}
+
+// Run delivers notifications to fn until ctx is cancelled
+func (l *Listener) Run(ctx context.Context, fn func(channel, payload string)) error {
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case n := <-l.listener.Notify:
+			// pq sends nil after a reconnect; OnReconnect handles that case
+			if n == nil {
+				continue
+			}
+			fn(n.Channel, n.Extra)
+
+		case <-ping.C:
+			// Detect dead connections that never reported an error
+			go l.listener.Ping()
+		}
+	}
+}
+
+// RunChanges decodes ChangeChannel payloads and delivers them to fn
+func (l *Listener) RunChanges(ctx context.Context, fn func(ChangeEvent)) error {
+	return l.Run(ctx, func(channel, payload string) {
+		if channel != ChangeChannel {
+			return
+		}
+		var ev ChangeEvent
+		if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+			return
+		}
+		fn(ev)
+	})
+}
+
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
+
+// RelayChange republishes ev on ChangeRelayChannel. Only one listener
+// should relay, or subscribers hear each change several times.
+func RelayChange(ctx context.Context, client redis.UniversalClient, ev ChangeEvent) error {
+	raw, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %w", err)
+	}
+	if err := client.Publish(ctx, ChangeRelayChannel, raw).Err(); err != nil {
+		return fmt.Errorf("failed to relay change: %w", err)
+	}
+	return nil
+}
+
+// SubscribeChanges delivers the ChangeEvents relayed on ChangeRelayChannel
+// to fn until ctx is cancelled. Changes relayed while the subscription
+// reconnects are lost.
+func SubscribeChanges(ctx context.Context, client redis.UniversalClient, fn func(ChangeEvent)) error {
+	sub := client.Subscribe(ctx, ChangeRelayChannel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to changes: %w", err)
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			var ev ChangeEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &ev); err != nil {
+				continue
+			}
+			fn(ev)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,26 +11,69 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// Config is loadable with pkg/config; the tags give the shared DB_* names
 type Config struct {
-	Host         string
-	Port         int
-	User         string
-	Password     string
-	Database     string
-	MaxOpenConns int
-	MaxIdleConns int
-	MaxLifetime  time.Duration
+	Host         string        `env:"DB_HOST" default:"localhost"`
+	Port         int           `env:"DB_PORT" default:"5432"`
+	User         string        `env:"DB_USER" required:"true"`
+	Password     string        `env:"DB_PASSWORD" required:"true" secret:"true"`
+	Database     string        `env:"DB_NAME" required:"true"`
+	MaxOpenConns int           `env:"DB_MAX_OPEN_CONNS" default:"25"`
+	MaxIdleConns int           `env:"DB_MAX_IDLE_CONNS" default:"10"`
+	MaxLifetime  time.Duration `env:"DB_CONN_MAX_LIFETIME" default:"5m"`
+
+	// Server-side limits applied to every connection in the pool; zero
+	// leaves the server default. Individual statements can lower them with
+	// SetLocalStatementTimeout.
+	StatementTimeout         time.Duration `env:"DB_STATEMENT_TIMEOUT" default:"30s"`
+	LockTimeout              time.Duration `env:"DB_LOCK_TIMEOUT"`
+	IdleInTransactionTimeout time.Duration `env:"DB_IDLE_IN_TRANSACTION_TIMEOUT" default:"1m"`
 }
 
 type DB struct {
 	*sqlx.DB
 }
 
-func NewDB(cfg Config) (*DB, error) {
+// DSN returns the lib/pq connection string for cfg. lib/pq forwards unknown
+// keys as run-time parameters, which is how the pool timeouts are applied.
+func (cfg Config) DSN() string {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database)
+	return dsn + cfg.params()
+}
+
+// params returns the pool timeouts as DSN keys, each with a leading space
+func (cfg Config) params() string {
+	var params string
+	if cfg.StatementTimeout > 0 {
+		params += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
+	if cfg.LockTimeout > 0 {
+		params += fmt.Sprintf(" lock_timeout=%d", cfg.LockTimeout.Milliseconds())
+	}
+	if cfg.IdleInTransactionTimeout > 0 {
+		params += fmt.Sprintf(" idle_in_transaction_session_timeout=%d", cfg.IdleInTransactionTimeout.Milliseconds())
+	}
+	return params
+}
+
+func NewDB(cfg Config) (*DB, error) {
+	db, err := open(cfg.DSN(), cfg, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify connection
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
 
-	db, err := sqlx.Open("postgres", dsn)
+// open opens a pool sized by cfg without connecting; name labels its
+// metrics
+func open(dsn string, cfg Config, name string) (*DB, error) {
+	db, err := sqlx.Open(DriverName, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -38,16 +83,29 @@ func NewDB(cfg Config) (*DB, error) {
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
 	db.SetConnMaxLifetime(cfg.MaxLifetime)
 
-	// Verify connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
-	return &DB{db}, nil
+	wrapped := &DB{db}
+	registerPoolMetrics(wrapped, name)
+	return wrapped, nil
 }
 
+// Common transaction options for TransactionContext
+var (
+	// Serializable is for multi-row math that must see a consistent snapshot,
+	// e.g. settlement. Combine with TransactionWithRetryContext.
+	Serializable = &sql.TxOptions{Isolation: sql.LevelSerializable}
+	// ReadOnly lets Postgres reject accidental writes in reporting code
+	ReadOnly = &sql.TxOptions{ReadOnly: true}
+)
+
 func (db *DB) Transaction(fn func(*sqlx.Tx) error) error {
-	tx, err := db.Beginx()
+	return db.TransactionContext(context.Background(), nil, fn)
+}
+
+// TransactionContext runs fn in a transaction bound to ctx with the given
+// isolation level and read-only mode; nil opts uses the server defaults.
+// Cancelling ctx rolls the transaction back.
+func (db *DB) TransactionContext(ctx context.Context, opts *sql.TxOptions, fn func(*sqlx.Tx) error) error {
+	tx, err := db.BeginTxx(ctx, opts)
 	if err != nil {
 		return err
 	}
@@ -60,7 +118,7 @@ func (db *DB) Transaction(fn func(*sqlx.Tx) error) error {
 	}()
 
 	if err := fn(tx); err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
 			return fmt.Errorf("tx error: %v, rollback error: %v", err, rbErr)
 		}
 		return err
@@ -2,79 +2,256 @@ package database
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// Redis deployment modes
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
+)
+
 type RedisConfig struct {
 	Host     string
 	Port     int
+	Username string
 	Password string
 	DB       int
 	PoolSize int
+
+	// Mode selects the topology; empty means single node
+	Mode string
+
+	// Sentinel settings (Mode == RedisModeSentinel)
+	MasterName       string
+	SentinelAddrs    []string
+	SentinelPassword string
+
+	// Cluster settings (Mode == RedisModeCluster)
+	ClusterAddrs []string
+
+	// TLS settings
+	TLSEnabled            bool
+	TLSServerName         string
+	TLSInsecureSkipVerify bool
+
+	// OpTimeout bounds helper calls whose context has no deadline
+	OpTimeout time.Duration
 }
 
+// DefaultRedisOpTimeout is used when RedisConfig.OpTimeout is zero
+const DefaultRedisOpTimeout = 2 * time.Second
+
 type RedisClient struct {
-	*redis.Client
-	ctx context.Context
+	redis.UniversalClient
+	opTimeout time.Duration
+}
+
+// RedisConfigFromEnv builds a RedisConfig from the REDIS_* environment
+// variables so services can switch between single node, Sentinel and Cluster
+// deployments without code changes.
+func RedisConfigFromEnv() RedisConfig {
+	return RedisConfig{
+		Host:                  os.Getenv("REDIS_HOST"),
+		Port:                  envInt("REDIS_PORT", 6379),
+		Username:              os.Getenv("REDIS_USERNAME"),
+		Password:              os.Getenv("REDIS_PASSWORD"),
+		DB:                    envInt("REDIS_DB", 0),
+		PoolSize:              envInt("REDIS_POOL_SIZE", 10),
+		Mode:                  strings.ToLower(os.Getenv("REDIS_MODE")),
+		MasterName:            os.Getenv("REDIS_MASTER_NAME"),
+		SentinelAddrs:         envList("REDIS_SENTINEL_ADDRS"),
+		SentinelPassword:      os.Getenv("REDIS_SENTINEL_PASSWORD"),
+		ClusterAddrs:          envList("REDIS_CLUSTER_ADDRS"),
+		TLSEnabled:            os.Getenv("REDIS_TLS") == "true",
+		TLSServerName:         os.Getenv("REDIS_TLS_SERVER_NAME"),
+		TLSInsecureSkipVerify: os.Getenv("REDIS_TLS_INSECURE_SKIP_VERIFY") == "true",
+		OpTimeout:             envDuration("REDIS_OP_TIMEOUT", DefaultRedisOpTimeout),
+	}
 }
 
 func NewRedisClient(cfg RedisConfig) (*RedisClient, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-		PoolSize: cfg.PoolSize,
-	})
-
-	ctx := context.Background()
-	
+	client, err := newUniversalClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client.AddHook(redisTracingHook{})
+	client.AddHook(redisMetricsHook{})
+
+	opTimeout := cfg.OpTimeout
+	if opTimeout <= 0 {
+		opTimeout = DefaultRedisOpTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+
 	// Test connection
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
+	registerRedisPoolMetrics(client)
+
 	return &RedisClient{
-		Client: client,
-		ctx:    ctx,
+		UniversalClient: client,
+		opTimeout:       opTimeout,
 	}, nil
 }
 
-func (r *RedisClient) SetWithExpiry(key string, value interface{}, expiration time.Duration) error {
-	return r.Set(r.ctx, key, value, expiration).Err()
-}
+func newUniversalClient(cfg RedisConfig) (redis.UniversalClient, error) {
+	var tlsConfig *tls.Config
+	if cfg.TLSEnabled {
+		tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         cfg.TLSServerName,
+			InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+		}
+	}
 
-func (r *RedisClient) GetString(key string) (string, error) {
-	return r.Get(r.ctx, key).Result()
-}
+	switch cfg.Mode {
+	case "", RedisModeSingle:
+		return redis.NewClient(&redis.Options{
+			Addr:      fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Username:  cfg.Username,
+			Password:  cfg.Password,
+			DB:        cfg.DB,
+			PoolSize:  cfg.PoolSize,
+			TLSConfig: tlsConfig,
+		}), nil
 
-func (r *RedisClient) GetAndDelete(key string) (string, error) {
-	val, err := r.Get(r.ctx, key).Result()
-	if err != nil {
-		return "", err
+	case RedisModeSentinel:
+		if cfg.MasterName == "" || len(cfg.SentinelAddrs) == 0 {
+			return nil, fmt.Errorf("redis sentinel mode requires a master name and sentinel addresses")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.SentinelAddrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			TLSConfig:        tlsConfig,
+		}), nil
+
+	case RedisModeCluster:
+		if len(cfg.ClusterAddrs) == 0 {
+			return nil, fmt.Errorf("redis cluster mode requires at least one cluster address")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     cfg.ClusterAddrs,
+			Username:  cfg.Username,
+			Password:  cfg.Password,
+			PoolSize:  cfg.PoolSize,
+			TLSConfig: tlsConfig,
+		}), nil
 	}
-	
-	if err := r.Del(r.ctx, key).Err(); err != nil {
-		return val, err
+
+	return nil, fmt.Errorf("unknown redis mode %q", cfg.Mode)
+}
+
+// withTimeout applies the default op timeout unless ctx already has a deadline
+func (r *RedisClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
 	}
-	
-	return val, nil
+	return context.WithTimeout(ctx, r.opTimeout)
+}
+
+func (r *RedisClient) SetWithExpiry(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Set(ctx, key, value, expiration).Err()
+}
+
+func (r *RedisClient) GetString(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Get(ctx, key).Result()
+}
+
+func (r *RedisClient) GetAndDelete(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// GETDEL, so concurrent callers cannot both get the value
+	return r.GetDel(ctx, key).Result()
+}
+
+func (r *RedisClient) Delete(ctx context.Context, keys ...string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.Del(ctx, keys...).Err()
 }
 
-func (r *RedisClient) Exists(key string) (bool, error) {
-	val, err := r.Exists(r.ctx, key).Result()
+func (r *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	val, err := r.UniversalClient.Exists(ctx, key).Result()
 	if err != nil {
 		return false, err
 	}
 	return val > 0, nil
 }
 
-func (r *RedisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
-	return r.Client.SetNX(r.ctx, key, value, expiration).Result()
+func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	return r.UniversalClient.SetNX(ctx, key, value, expiration).Result()
+}
+
+// deleteIfEqual deletes KEYS[1] only while it holds ARGV[1]
+var deleteIfEqual = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// DeleteIfEqual deletes key if it still holds value, so a lock taken with
+// SetNX and a unique value is only released by its holder, not by whoever
+// took it after it expired. It reports whether key was deleted.
+func (r *RedisClient) DeleteIfEqual(ctx context.Context, key, value string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+	n, err := deleteIfEqual.Run(ctx, r.UniversalClient, []string{key}, value).Int()
+	return n > 0, err
 }
 
 func (r *RedisClient) Close() error {
-	return r.Client.Close()
-}
\ No newline at end of file
+	return r.UniversalClient.Close()
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func envList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,320 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ReplicaConfig is loadable with pkg/config
+type ReplicaConfig struct {
+	// DSNs are the read replicas, as postgres:// URLs or key=value strings.
+	// The pool sizes and timeouts of Config apply to them too. Empty, every
+	// read goes to the primary.
+	DSNs []string `env:"DB_REPLICA_DSNS" secret:"true"`
+	// MaxLag is how far a replica may fall behind the primary before reads
+	// stop going to it
+	MaxLag time.Duration `env:"DB_REPLICA_MAX_LAG" default:"10s"`
+	// CheckInterval is how often replicas are probed for health and lag
+	CheckInterval time.Duration `env:"DB_REPLICA_CHECK_INTERVAL" default:"5s"`
+}
+
+// Validate implements config.Validator
+func (c ReplicaConfig) Validate() error {
+	if len(c.DSNs) > 0 && c.CheckInterval <= 0 {
+		return errors.New("DB_REPLICA_CHECK_INTERVAL must be positive")
+	}
+	return nil
+}
+
+// Router sends reads round robin to the replicas that are up and caught
+// up, and to the primary when none is. Replicas are probed in the
+// background; one whose connection fails mid-read is taken out of rotation
+// until the next probe passes, and the read is retried on the primary.
+//
+// Router implements sqlx.QueryerContext, so Get, Select and the sqlx
+// helpers read through it. Writes and transactions belong on Primary.
+type Router struct {
+	primary  *DB
+	replicas []*replica
+	cfg      ReplicaConfig
+	next     atomic.Uint64
+
+	stop context.CancelFunc
+	done sync.WaitGroup
+}
+
+// replica is one read replica and its standing at the last probe
+type replica struct {
+	name string
+	db   *DB
+	up   atomic.Bool
+}
+
+// NewRouter opens a pool per replica of cfg, sized like primaryCfg, and
+// probes them once before returning. Replicas that are down do not fail
+// it; they take reads once a probe passes. Close stops the probes.
+func NewRouter(primary *DB, primaryCfg Config, cfg ReplicaConfig) (*Router, error) {
+	r := &Router{primary: primary, cfg: cfg}
+	for i, dsn := range cfg.DSNs {
+		name := fmt.Sprintf("%s_replica%d", primaryCfg.Database, i+1)
+		dsn, err := replicaDSN(dsn, primaryCfg)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("invalid DSN of %s: %w", name, err)
+		}
+		db, err := open(dsn, primaryCfg, name)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		r.replicas = append(r.replicas, &replica{name: name, db: db})
+	}
+	if len(r.replicas) == 0 {
+		return r, nil
+	}
+
+	r.check(context.Background())
+	ctx, stop := context.WithCancel(context.Background())
+	r.stop = stop
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+		r.run(ctx)
+	}()
+	return r, nil
+}
+
+// replicaDSN returns dsn as key=value pairs with the pool timeouts of cfg
+func replicaDSN(dsn string, cfg Config) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		converted, err := pq.ParseURL(dsn)
+		if err != nil {
+			return "", err
+		}
+		dsn = converted
+	}
+	return dsn + cfg.params(), nil
+}
+
+// Primary returns the primary pool, for writes and reads that must see
+// them
+func (r *Router) Primary() *DB {
+	return r.primary
+}
+
+// Reader returns the next replica that is up, or the primary
+func (r *Router) Reader() *DB {
+	if rep := r.pick(); rep != nil {
+		return rep.db
+	}
+	return r.primary
+}
+
+func (r *Router) pick() *replica {
+	n := uint64(len(r.replicas))
+	if n == 0 {
+		return nil
+	}
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if rep := r.replicas[(start+i)%n]; rep.up.Load() {
+			return rep
+		}
+	}
+	replicaFallbacks.Inc()
+	return nil
+}
+
+// read runs fn on a replica, retrying it on the primary when the replica's
+// connection fails
+func (r *Router) read(ctx context.Context, fn func(*DB) error) error {
+	rep := r.pick()
+	if rep == nil {
+		return fn(r.primary)
+	}
+	err := fn(rep.db)
+	if !r.failed(ctx, rep, err) {
+		return err
+	}
+	return fn(r.primary)
+}
+
+// failed reports whether err is a connection failure of rep, and takes rep
+// out of rotation if so
+func (r *Router) failed(ctx context.Context, rep *replica, err error) bool {
+	if err == nil || ctx.Err() != nil || !isConnError(err) {
+		return false
+	}
+	if rep.up.Swap(false) {
+		slog.Warn("Read replica failed, reading from the primary", "replica", rep.name, "error", err)
+		replicaUp.WithLabelValues(rep.name).Set(0)
+	}
+	replicaFallbacks.Inc()
+	return true
+}
+
+// isConnError reports whether err means the connection, rather than the
+// statement, failed
+func isConnError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
+
+// QueryContext implements sqlx.QueryerContext
+func (r *Router) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := r.read(ctx, func(db *DB) (err error) {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryxContext implements sqlx.QueryerContext
+func (r *Router) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := r.read(ctx, func(db *DB) (err error) {
+		rows, err = db.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowxContext implements sqlx.QueryerContext
+func (r *Router) QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row {
+	var row *sqlx.Row
+	r.read(ctx, func(db *DB) error {
+		row = db.QueryRowxContext(ctx, query, args...)
+		return row.Err()
+	})
+	return row
+}
+
+// GetContext reads a single row into dest, like sqlx.GetContext
+func (r *Router) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return sqlx.GetContext(ctx, r, dest, query, args...)
+}
+
+// SelectContext reads rows into dest, like sqlx.SelectContext
+func (r *Router) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return sqlx.SelectContext(ctx, r, dest, query, args...)
+}
+
+// Close stops the probes and closes the replica pools; the primary is the
+// caller's to close
+func (r *Router) Close() error {
+	if r.stop != nil {
+		r.stop()
+		r.done.Wait()
+	}
+	var errs []error
+	for _, rep := range r.replicas {
+		errs = append(errs, rep.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// run probes the replicas every cfg.CheckInterval until ctx is done
+func (r *Router) run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.check(ctx)
+		}
+	}
+}
+
+// lagQuery is how far a replica's replay is behind, in seconds. A replica
+// that has replayed all it received is caught up however old its last
+// transaction is; a server not in recovery is the primary or was promoted.
+const lagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// check probes every replica, putting it in rotation when it answers within
+// the interval and lags at most cfg.MaxLag
+func (r *Router) check(ctx context.Context) {
+	for _, rep := range r.replicas {
+		probeCtx, cancel := context.WithTimeout(ctx, r.cfg.CheckInterval)
+		var lag float64
+		err := rep.db.GetContext(probeCtx, &lag, lagQuery)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		up := err == nil && lag <= r.cfg.MaxLag.Seconds()
+		if err == nil {
+			replicaLag.WithLabelValues(rep.name).Set(lag)
+		}
+		if up {
+			replicaUp.WithLabelValues(rep.name).Set(1)
+		} else {
+			replicaUp.WithLabelValues(rep.name).Set(0)
+		}
+		if was := rep.up.Swap(up); was == up {
+			continue
+		}
+		switch {
+		case up:
+			slog.Info("Read replica in rotation", "replica", rep.name, "lag", lag)
+		case err != nil:
+			slog.Warn("Read replica unreachable, out of rotation", "replica", rep.name, "error", err)
+		default:
+			slog.Warn("Read replica lagging, out of rotation", "replica", rep.name, "lag", lag, "max_lag", r.cfg.MaxLag.Seconds())
+		}
+	}
+}
+
+var (
+	replicaUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "r2s",
+			Subsystem: "db",
+			Name:      "replica_up",
+			Help:      "Whether the read replica is in rotation (reachable and caught up).",
+		},
+		[]string{"replica"},
+	)
+	replicaLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "r2s",
+			Subsystem: "db",
+			Name:      "replica_lag_seconds",
+			Help:      "Replay lag of the read replica at the last probe.",
+		},
+		[]string{"replica"},
+	)
+	replicaFallbacks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "r2s",
+			Subsystem: "db",
+			Name:      "replica_fallbacks_total",
+			Help:      "Reads sent to the primary because no replica was in rotation or the replica's connection failed.",
+		},
+	)
+)
+
+func init() {
+	mustRegister(replicaUp)
+	mustRegister(replicaLag)
+	mustRegister(replicaFallbacks)
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Postgres SQLSTATE codes that are safe to retry as a whole transaction
+const (
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts: 5,
+	BaseDelay:   20 * time.Millisecond,
+	MaxDelay:    time.Second,
+}
+
+// RetryStats is a snapshot of the transaction retry counters
+type RetryStats struct {
+	Retries       uint64 `json:"retries"`
+	Serialization uint64 `json:"serialization_failures"`
+	Deadlocks     uint64 `json:"deadlocks"`
+	Exhausted     uint64 `json:"exhausted"`
+	Recovered     uint64 `json:"recovered"`
+}
+
+var retryCounters struct {
+	retries       atomic.Uint64
+	serialization atomic.Uint64
+	deadlocks     atomic.Uint64
+	exhausted     atomic.Uint64
+	recovered     atomic.Uint64
+}
+
+// GetRetryStats returns the process-wide transaction retry counters
+func GetRetryStats() RetryStats {
+	return RetryStats{
+		Retries:       retryCounters.retries.Load(),
+		Serialization: retryCounters.serialization.Load(),
+		Deadlocks:     retryCounters.deadlocks.Load(),
+		Exhausted:     retryCounters.exhausted.Load(),
+		Recovered:     retryCounters.recovered.Load(),
+	}
+}
+
+// IsRetryableError reports whether err is a serialization failure or deadlock
+func IsRetryableError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == SerializationFailure || pqErr.Code == DeadlockDetected
+}
+
+// TransactionWithRetry runs fn in a transaction using DefaultRetryConfig
+func (db *DB) TransactionWithRetry(fn func(*sqlx.Tx) error) error {
+	return db.TransactionWithRetryConfig(DefaultRetryConfig, fn)
+}
+
+// TransactionWithRetryConfig runs fn in a transaction and re-runs the whole
+// transaction with exponential backoff when Postgres aborts it with a
+// serialization failure or deadlock. fn must be safe to execute more than once.
+func (db *DB) TransactionWithRetryConfig(cfg RetryConfig, fn func(*sqlx.Tx) error) error {
+	return db.TransactionWithRetryContext(context.Background(), cfg, nil, fn)
+}
+
+// TransactionWithRetryContext is TransactionWithRetryConfig with a context and
+// transaction options. Retries stop early when ctx is done.
+func (db *DB) TransactionWithRetryContext(ctx context.Context, cfg RetryConfig, opts *sql.TxOptions, fn func(*sqlx.Tx) error) error {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < cfg.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			retryCounters.retries.Add(1)
+			select {
+			case <-time.After(backoff(cfg, attempt)):
+			case <-ctx.Done():
+				// Callers see why the retries stopped, not a retryable error
+				return fmt.Errorf("%w (last attempt: %v)", ctx.Err(), err)
+			}
+		}
+
+		err = db.TransactionContext(ctx, opts, fn)
+		if !IsRetryableError(err) {
+			if err == nil && attempt > 0 {
+				retryCounters.recovered.Add(1)
+			}
+			return err
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == DeadlockDetected {
+			retryCounters.deadlocks.Add(1)
+		} else {
+			retryCounters.serialization.Add(1)
+		}
+	}
+
+	retryCounters.exhausted.Add(1)
+	return err
+}
+
+// backoff returns the delay before the given attempt with full jitter
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	delay := cfg.BaseDelay << (attempt - 1)
+	if delay <= 0 || (cfg.MaxDelay > 0 && delay > cfg.MaxDelay) {
+		delay = cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
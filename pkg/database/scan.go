@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Get scans a single row into a T using sqlx struct-tag conventions (db:"col").
+// It returns nil, nil when no row matches, like the repositories do.
+func Get[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) (*T, error) {
+	var dest T
+	err := sqlx.GetContext(ctx, q, &dest, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &dest, nil
+}
+
+// Select scans all rows into a slice of T
+func Select[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) ([]T, error) {
+	var dest []T
+	if err := sqlx.SelectContext(ctx, q, &dest, query, args...); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// NamedSelect runs a query with :name parameters bound from arg (a struct
+// with db tags or a map[string]interface{}) and scans all rows into T
+func NamedSelect[T any](ctx context.Context, e sqlx.ExtContext, query string, arg interface{}) ([]T, error) {
+	rows, err := sqlx.NamedQueryContext(ctx, e, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dest []T
+	for rows.Next() {
+		var v T
+		if err := rows.StructScan(&v); err != nil {
+			return nil, err
+		}
+		dest = append(dest, v)
+	}
+	return dest, rows.Err()
+}
+
+// Map converts scanned rows with fn, e.g. from a row struct to a model or
+// protobuf message
+func Map[T, U any](rows []T, fn func(T) U) []U {
+	out := make([]U, len(rows))
+	for i, row := range rows {
+		out[i] = fn(row)
+	}
+	return out
+}
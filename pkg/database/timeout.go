@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// QueryCanceled is raised when statement_timeout fires or a cancel request
+// (sent by lib/pq when the context is done) reaches the server
+const QueryCanceled = "57014"
+
+// WithQueryTimeout bounds ctx by d unless it already has an earlier deadline.
+// lib/pq cancels the running statement on the server once ctx is done, so
+// the connection is returned to the pool instead of waiting for the query.
+func WithQueryTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// SetLocalStatementTimeout overrides statement_timeout for the rest of tx
+func SetLocalStatementTimeout(ctx context.Context, tx *sqlx.Tx, d time.Duration) error {
+	// SET does not accept bind parameters; set_config is the parameterised form
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('statement_timeout', $1, true)`, fmt.Sprintf("%dms", d.Milliseconds())); err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+	return nil
+}
+
+// PropagateDeadline copies the remaining time of ctx into statement_timeout
+// for tx, so the server aborts the work even if the cancel request is lost
+func PropagateDeadline(ctx context.Context, tx *sqlx.Tx) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < time.Millisecond {
+		return context.DeadlineExceeded
+	}
+	return SetLocalStatementTimeout(ctx, tx, remaining)
+}
+
+// IsTimeoutError reports whether err came from a statement timeout or a
+// cancelled/expired context
+func IsTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && string(pqErr.Code) == QueryCanceled
+}
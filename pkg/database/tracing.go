@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName identifies spans created by pkg/database
+const TracerName = "github.com/Reserve-to-save-backend/pkg/database"
+
+// maxStatementLen caps the db.statement attribute
+const maxStatementLen = 2048
+
+func init() {
+	AddQueryHook(tracingHook{})
+}
+
+type tracingSpanKey struct{}
+
+// tracingHook creates a client span for every statement that runs under an
+// existing trace, so queries show up beneath the request that issued them.
+// Statements without a parent span (startup, background pings) are skipped.
+type tracingHook struct{}
+
+func (tracingHook) BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.operation", ev.Op),
+	}
+	if ev.Query != "" {
+		attrs = append(attrs, attribute.String("db.statement", SanitizeQuery(ev.Query)))
+	}
+
+	ctx, span := otel.Tracer(TracerName).Start(ctx, "db."+ev.Op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(ev.Start),
+		trace.WithAttributes(attrs...),
+	)
+	return context.WithValue(ctx, tracingSpanKey{}, span)
+}
+
+func (tracingHook) AfterQuery(ctx context.Context, ev *QueryEvent) {
+	span, ok := ctx.Value(tracingSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+
+	if ev.RowsAffected >= 0 {
+		span.SetAttributes(attribute.Int64("db.rows_affected", ev.RowsAffected))
+	}
+	if ev.Err != nil {
+		span.RecordError(ev.Err)
+		span.SetStatus(codes.Error, ev.Err.Error())
+	}
+	span.End(trace.WithTimestamp(ev.Start.Add(ev.Duration)))
+}
+
+// SanitizeQuery replaces string and numeric literals with ? and collapses
+// whitespace so statements can be recorded without leaking values. Bind
+// placeholders ($1, $2, ...) are kept.
+func SanitizeQuery(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			space = b.Len() > 0
+			continue
+
+		case ch == '\'':
+			// Skip to the closing quote, treating '' as an escaped quote
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			ch = '?'
+
+		case isDigit(ch) && (i == 0 || !isIdentChar(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			ch = '?'
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteByte(ch)
+	}
+
+	out := b.String()
+	if len(out) > maxStatementLen {
+		out = out[:maxStatementLen]
+	}
+	return out
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// isIdentChar also treats $ as part of an identifier so $1 stays intact
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch == '$' || isDigit(ch) || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// redisTracingHook does for Redis what tracingHook does for SQL: a client
+// span per command or pipeline under an existing trace. Only command names
+// are recorded, never keys or values.
+type redisTracingHook struct{}
+
+type redisSpanKey struct{}
+
+func (redisTracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return startRedisSpan(ctx, "redis."+cmd.Name(), cmd.Name()), nil
+}
+
+func (redisTracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+func (redisTracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	names := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		names[i] = cmd.Name()
+	}
+	return startRedisSpan(ctx, "redis.pipeline", strings.Join(names, " ")), nil
+}
+
+func (redisTracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	endRedisSpan(ctx, err)
+	return nil
+}
+
+func startRedisSpan(ctx context.Context, name, operation string) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	ctx, span := otel.Tracer(TracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", operation),
+		),
+	)
+	return context.WithValue(ctx, redisSpanKey{}, span)
+}
+
+func endRedisSpan(ctx context.Context, err error) {
+	span, ok := ctx.Value(redisSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	// A missing key is a normal result, not a failure
+	if err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -1,136 +1,301 @@
--- PostgreSQL schema (핵심만 발췌)
+-- PostgreSQL 기준 스키마 (모든 서비스 공통)
+--
+-- core-server, query-server, batch-server, event-receiver가 모두 이 스키마를
+-- 사용합니다. 이후 변경은 pkg/db/migrations/*.sql로 적용하며, Go 모델은
+-- pkg/models에 있습니다.
+--
+-- 규칙
+--   - id는 UUID (chain_events, audit_logs 제외)
+--   - 주소는 0x + 소문자 hex 문자열 (003_normalize_addresses.sql)
+--   - 금액은 NUMERIC 최소 단위 정수 (USDT는 6자리, 1 USDT = 1000000)
+--   - 상태는 텍스트 (pkg/models의 상태 상수)
+--
+-- 정산 내역과 리베이트는 따로 저장하지 않습니다. 정산은 status = 'settled'인
+-- 캠페인의 settlement_date이고, 리베이트는 정산된 참여의 actual_rebate입니다.
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TABLE users (
-  id BIGSERIAL PRIMARY KEY,
-  wallet_address BYTEA UNIQUE NOT NULL, -- lowercased hex -> bytea
-  line_uid TEXT,
-  created_at TIMESTAMPTZ DEFAULT now(),
-  status SMALLINT DEFAULT 1
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  wallet_address VARCHAR(42) UNIQUE NOT NULL,
+  line_user_id VARCHAR(255) UNIQUE,
+  line_display_name VARCHAR(255),
+  line_picture_url TEXT,
+  email VARCHAR(255),
+  kyc_tier SMALLINT DEFAULT 0 CHECK (kyc_tier >= 0 AND kyc_tier <= 3),
+  status VARCHAR(20) DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'deleted')),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  last_login_at TIMESTAMPTZ,
+  metadata JSONB DEFAULT '{}'::jsonb
 );
 
-CREATE TABLE merchants (
-  id BIGSERIAL PRIMARY KEY,
-  wallet_address BYTEA UNIQUE NOT NULL,
-  name TEXT,
-  created_at TIMESTAMPTZ DEFAULT now()
-);
+CREATE INDEX idx_users_wallet ON users(wallet_address);
+CREATE INDEX idx_users_line_id ON users(line_user_id);
+CREATE INDEX idx_users_status ON users(status);
 
+-- merchant_id는 캠페인을 등록한 사용자이며, 머천트 등록 정보는
+-- merchants(id = users.id, 010_merchants.sql)에 있습니다
 CREATE TABLE campaigns (
-  id BIGSERIAL PRIMARY KEY,
-  address BYTEA UNIQUE NOT NULL,
-  merchant_id BIGINT REFERENCES merchants(id),
-  base_price NUMERIC(20,6) NOT NULL,
-  min_qty BIGINT NOT NULL,
-  lock_start TIMESTAMPTZ NOT NULL,
-  lock_end TIMESTAMPTZ NOT NULL,
-  rmax_bps INTEGER NOT NULL,
-  savefloor_bps INTEGER NOT NULL,
-  merchant_fee_bps INTEGER NOT NULL,
-  ops_fee_bps INTEGER NOT NULL,
-  state SMALLINT NOT NULL,
-  metadata_uri TEXT,
-  created_at TIMESTAMPTZ DEFAULT now()
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  chain_address VARCHAR(42) UNIQUE NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT,
+  image_url TEXT,
+  merchant_id UUID REFERENCES users(id),
+  merchant_wallet VARCHAR(42) NOT NULL,
+  base_price NUMERIC(36, 18) NOT NULL CHECK (base_price > 0),
+  min_qty INTEGER NOT NULL CHECK (min_qty > 0),
+  current_qty INTEGER DEFAULT 0,
+  target_amount NUMERIC(36, 18) NOT NULL,
+  current_amount NUMERIC(36, 18) DEFAULT 0,
+  discount_rate INTEGER NOT NULL CHECK (discount_rate >= 0 AND discount_rate <= 10000),
+  save_floor_bps INTEGER NOT NULL CHECK (save_floor_bps >= 0 AND save_floor_bps <= 10000),
+  r_max_bps INTEGER NOT NULL CHECK (r_max_bps >= 0 AND r_max_bps <= 10000),
+  merchant_fee_bps INTEGER DEFAULT 250,
+  ops_fee_bps INTEGER DEFAULT 100,
+  start_time TIMESTAMPTZ NOT NULL,
+  end_time TIMESTAMPTZ NOT NULL,
+  settlement_date TIMESTAMPTZ,
+  status VARCHAR(20) DEFAULT 'draft' CONSTRAINT campaigns_status_check CHECK (status IN (
+    'draft', 'recruiting', 'reached', 'fulfillment', 'settled', 'failed', 'cancelled', 'paused'
+  )),
+  tx_hash VARCHAR(66),
+  block_number BIGINT,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  metadata JSONB DEFAULT '{}'::jsonb,
+  CONSTRAINT check_time_order CHECK (start_time < end_time),
+  CONSTRAINT check_settlement CHECK (settlement_date IS NULL OR settlement_date > end_time),
+  CONSTRAINT check_rebate_order CHECK (save_floor_bps <= r_max_bps)
 );
 
-CREATE TABLE participants (
-  id BIGSERIAL PRIMARY KEY,
-  campaign_id BIGINT REFERENCES campaigns(id),
-  user_id BIGINT REFERENCES users(id),
-  deposit NUMERIC(20,6) NOT NULL,
-  joined_at TIMESTAMPTZ NOT NULL,
-  status SMALLINT NOT NULL,
-  UNIQUE (campaign_id, user_id)
+CREATE INDEX idx_campaigns_status ON campaigns(status);
+CREATE INDEX idx_campaigns_merchant ON campaigns(merchant_id);
+CREATE INDEX idx_campaigns_chain_address ON campaigns(chain_address);
+CREATE INDEX idx_campaigns_dates ON campaigns(start_time, end_time);
+
+CREATE TABLE participations (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  campaign_id UUID REFERENCES campaigns(id) ON DELETE CASCADE,
+  user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  wallet_address VARCHAR(42) NOT NULL,
+  deposit_amount NUMERIC(36, 18) NOT NULL CHECK (deposit_amount > 0),
+  joined_at TIMESTAMPTZ DEFAULT NOW(),
+  cancel_pending NUMERIC(36, 18) DEFAULT 0,
+  expected_rebate NUMERIC(36, 18) DEFAULT 0,
+  actual_rebate NUMERIC(36, 18),
+  status VARCHAR(20) DEFAULT 'active' CHECK (status IN (
+    'active', 'pending_cancel', 'cancelled', 'settled', 'refunded'
+  )),
+  tx_hash VARCHAR(66),
+  cancel_tx_hash VARCHAR(66),
+  settlement_tx_hash VARCHAR(66),
+  refund_tx_hash VARCHAR(66),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  metadata JSONB DEFAULT '{}'::jsonb,
+  UNIQUE(campaign_id, user_id)
 );
 
-CREATE TABLE sponsor_allocations (
-  id BIGSERIAL PRIMARY KEY,
-  campaign_id BIGINT REFERENCES campaigns(id),
-  amount NUMERIC(20,6) NOT NULL,
-  sponsor_wallet BYTEA NOT NULL,
-  allocated_at TIMESTAMPTZ DEFAULT now()
+CREATE INDEX idx_participations_campaign ON participations(campaign_id);
+CREATE INDEX idx_participations_user ON participations(user_id);
+CREATE INDEX idx_participations_status ON participations(status);
+CREATE INDEX idx_participations_wallet ON participations(wallet_address);
+
+CREATE TABLE payments (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  payment_id VARCHAR(255) UNIQUE NOT NULL,
+  campaign_id UUID REFERENCES campaigns(id),
+  user_id UUID REFERENCES users(id),
+  participation_id UUID REFERENCES participations(id),
+  amount NUMERIC(36, 18) NOT NULL,
+  currency VARCHAR(10) NOT NULL CHECK (currency IN ('USDT', 'KAIA', 'KRW', 'USD')),
+  mode VARCHAR(20) NOT NULL CHECK (mode IN ('crypto', 'stripe')),
+  status VARCHAR(20) DEFAULT 'pending' CHECK (status IN (
+    'pending', 'processing', 'completed', 'failed', 'refunded'
+  )),
+  transaction_hash VARCHAR(66),
+  provider_response JSONB,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  completed_at TIMESTAMPTZ,
+  failed_at TIMESTAMPTZ,
+  refunded_at TIMESTAMPTZ,
+  metadata JSONB DEFAULT '{}'::jsonb
 );
 
-CREATE TABLE settlements (
+CREATE INDEX idx_payments_payment_id ON payments(payment_id);
+CREATE INDEX idx_payments_user ON payments(user_id);
+CREATE INDEX idx_payments_campaign ON payments(campaign_id);
+CREATE INDEX idx_payments_status ON payments(status);
+
+CREATE TABLE chain_events (
   id BIGSERIAL PRIMARY KEY,
-  campaign_id BIGINT REFERENCES campaigns(id) UNIQUE,
-  snapshot_time TIMESTAMPTZ NOT NULL,
-  total_amount NUMERIC(20,6) NOT NULL,
-  rebate_paid NUMERIC(20,6) NOT NULL,
-  merchant_payout NUMERIC(20,6) NOT NULL,
-  ops_fee NUMERIC(20,6) NOT NULL,
-  sponsor_consumed NUMERIC(20,6) NOT NULL,
-  state SMALLINT NOT NULL,
-  created_at TIMESTAMPTZ DEFAULT now()
+  block_number BIGINT NOT NULL,
+  tx_hash VARCHAR(66) NOT NULL,
+  log_index INTEGER NOT NULL,
+  contract_address VARCHAR(42) NOT NULL,
+  event_name VARCHAR(100) NOT NULL,
+  event_data JSONB NOT NULL,
+  decoded_data JSONB,
+  chain_timestamp TIMESTAMPTZ,
+  processed BOOLEAN DEFAULT FALSE,
+  processed_at TIMESTAMPTZ,
+  ingested_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE(tx_hash, log_index)
+);
+
+CREATE INDEX idx_chain_events_block ON chain_events(block_number);
+CREATE INDEX idx_chain_events_contract ON chain_events(contract_address);
+CREATE INDEX idx_chain_events_event ON chain_events(event_name);
+CREATE INDEX idx_chain_events_processed ON chain_events(processed);
+
+CREATE TABLE receipts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  campaign_id UUID REFERENCES campaigns(id),
+  user_id UUID REFERENCES users(id),
+  participation_id UUID REFERENCES participations(id),
+  type VARCHAR(20) NOT NULL CHECK (type IN ('settlement', 'refund', 'cancel')),
+  file_url TEXT NOT NULL,
+  file_hash VARCHAR(64) NOT NULL,
+  metadata JSONB DEFAULT '{}'::jsonb,
+  created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_receipts_campaign ON receipts(campaign_id);
+CREATE INDEX idx_receipts_user ON receipts(user_id);
+CREATE INDEX idx_receipts_type ON receipts(type);
+
+CREATE TABLE sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  token_hash VARCHAR(64) UNIQUE NOT NULL,
+  refresh_token_hash VARCHAR(64) UNIQUE,
+  ip_address INET,
+  user_agent TEXT,
+  device_fingerprint VARCHAR(255),
+  expires_at TIMESTAMPTZ NOT NULL,
+  refresh_expires_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  last_used_at TIMESTAMPTZ DEFAULT NOW()
 );
 
-CREATE TABLE rebates (
+CREATE INDEX idx_sessions_user ON sessions(user_id);
+CREATE INDEX idx_sessions_token ON sessions(token_hash);
+CREATE INDEX idx_sessions_expires ON sessions(expires_at);
+
+CREATE TABLE webhook_logs (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_id VARCHAR(255) UNIQUE NOT NULL,
+  event_type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL,
+  signature VARCHAR(255),
+  processed BOOLEAN DEFAULT FALSE,
+  retry_count INTEGER DEFAULT 0,
+  error_message TEXT,
+  received_at TIMESTAMPTZ DEFAULT NOW(),
+  processed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_logs_event_id ON webhook_logs(event_id);
+CREATE INDEX idx_webhook_logs_processed ON webhook_logs(processed);
+
+CREATE TABLE audit_logs (
   id BIGSERIAL PRIMARY KEY,
-  settlement_id BIGINT REFERENCES settlements(id),
-  user_id BIGINT REFERENCES users(id),
-  amount NUMERIC(20,6) NOT NULL,
-  sponsor_part NUMERIC(20,6) NOT NULL,
-  yield_part NUMERIC(20,6) NOT NULL
+  user_id UUID,
+  action VARCHAR(100) NOT NULL,
+  resource_type VARCHAR(50),
+  resource_id VARCHAR(255),
+  ip_address INET,
+  user_agent TEXT,
+  request_body JSONB,
+  response_status INTEGER,
+  error_message TEXT,
+  created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
-CREATE INDEX idx_campaign_state ON campaigns(state, lock_end);
-CREATE INDEX idx_participants_user ON participants(user_id, campaign_id);
+CREATE INDEX idx_audit_logs_user ON audit_logs(user_id);
+CREATE INDEX idx_audit_logs_action ON audit_logs(action);
+CREATE INDEX idx_audit_logs_created ON audit_logs(created_at);
+
+CREATE OR REPLACE FUNCTION update_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_campaigns_updated_at BEFORE UPDATE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+CREATE TRIGGER update_participations_updated_at BEFORE UPDATE ON participations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
 -- 예시 데이터 INSERT
--- commnet below if you don't want example data
-
--- Users 예시 데이터 (5개)
-INSERT INTO users (wallet_address, line_uid, status) VALUES
-  (decode('a1b2c3d4e5f6789012345678901234567890abcd', 'hex'), 'line_user_001', 1),
-  (decode('b2c3d4e5f6789012345678901234567890abcdef', 'hex'), 'line_user_002', 1),
-  (decode('c3d4e5f6789012345678901234567890abcdef01', 'hex'), 'line_user_003', 1),
-  (decode('d4e5f6789012345678901234567890abcdef0123', 'hex'), 'line_user_004', 1),
-  (decode('e5f6789012345678901234567890abcdef012345', 'hex'), 'line_user_005', 1);
-
--- Merchants 예시 데이터 (2개)
-INSERT INTO merchants (wallet_address, name) VALUES
-  (decode('1234567890abcdef1234567890abcdef12345678', 'hex'), 'merchant1'),
-  (decode('234567890abcdef1234567890abcdef123456789', 'hex'), 'merchant2');
-
--- Campaigns 예시 데이터 (2개) - merchant_id는 위에서 생성된 merchants를 참조
+-- commnet below if you don't want example data (demo/seed.go가 더 많은 데이터를 넣습니다)
+
+-- Users 예시 데이터 (사용자 3명, 머천트 2명)
+INSERT INTO users (id, wallet_address, line_user_id, status) VALUES
+  ('00000000-0000-4000-8000-000000000001', '0xa1b2c3d4e5f6789012345678901234567890abcd', 'line_user_001', 'active'),
+  ('00000000-0000-4000-8000-000000000002', '0xb2c3d4e5f6789012345678901234567890abcdef', 'line_user_002', 'active'),
+  ('00000000-0000-4000-8000-000000000003', '0xc3d4e5f6789012345678901234567890abcdef01', 'line_user_003', 'active'),
+  ('00000000-0000-4000-8000-000000000101', '0x1234567890abcdef1234567890abcdef12345678', NULL, 'active'),
+  ('00000000-0000-4000-8000-000000000102', '0x234567890abcdef1234567890abcdef123456789', NULL, 'active');
+
+-- Campaigns 예시 데이터 (2개) - merchant_id는 위 머천트 사용자, 금액은 USDT 최소 단위
 INSERT INTO campaigns (
-  address, 
-  merchant_id, 
-  base_price, 
-  min_qty, 
-  lock_start, 
-  lock_end, 
-  rmax_bps, 
-  savefloor_bps, 
-  merchant_fee_bps, 
-  ops_fee_bps, 
-  state, 
-  metadata_uri
+  id,
+  chain_address,
+  title,
+  merchant_id,
+  merchant_wallet,
+  base_price,
+  min_qty,
+  target_amount,
+  discount_rate,
+  save_floor_bps,
+  r_max_bps,
+  merchant_fee_bps,
+  ops_fee_bps,
+  start_time,
+  end_time,
+  status
 ) VALUES
   (
-    decode('1111111111111111111111111111111111111111', 'hex'),
-    1, -- merchant1의 ID
-    10.500000,
+    '00000000-0000-4000-8000-000000001001',
+    '0x1111111111111111111111111111111111111111',
+    'campaign1',
+    '00000000-0000-4000-8000-000000000101',
+    '0x1234567890abcdef1234567890abcdef12345678',
+    10500000,    -- 10.5 USDT
     100,
-    '2024-01-15 09:00:00+00',
-    '2024-02-15 18:00:00+00',
+    1050000000,  -- base_price * min_qty
+    300,
+    200,  -- 2% save floor
     500,  -- 5% rmax
-    200,  -- 2% savefloor
     300,  -- 3% merchant fee
     100,  -- 1% ops fee
-    1,    -- active state
-    'https://example.com/campaign1-metadata.json'
+    '2024-01-15 09:00:00+00',
+    '2024-02-15 18:00:00+00',
+    'recruiting'
   ),
   (
-    decode('2222222222222222222222222222222222222222', 'hex'),
-    2, -- merchant2의 ID
-    25.000000,
+    '00000000-0000-4000-8000-000000001002',
+    '0x2222222222222222222222222222222222222222',
+    'campaign2',
+    '00000000-0000-4000-8000-000000000102',
+    '0x234567890abcdef1234567890abcdef123456789',
+    25000000,    -- 25 USDT
     50,
-    '2024-01-20 10:00:00+00',
-    '2024-03-20 20:00:00+00',
+    1250000000,
+    400,
+    250,  -- 2.5% save floor
     600,  -- 6% rmax
-    250,  -- 2.5% savefloor
     400,  -- 4% merchant fee
     150,  -- 1.5% ops fee
-    1,    -- active state
-    'https://example.com/campaign2-metadata.json'
+    '2024-01-20 10:00:00+00',
+    '2024-03-20 20:00:00+00',
+    'recruiting'
   );
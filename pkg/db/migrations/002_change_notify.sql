@@ -0,0 +1,34 @@
+-- Publish row changes on the r2s_changes channel so listeners
+-- (query-server streaming, cache invalidation) can react without a broker.
+-- Payload: {"table": "...", "op": "INSERT|UPDATE|DELETE", "id": "..."}
+
+CREATE OR REPLACE FUNCTION r2s_notify_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_id TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_id := OLD.id::TEXT;
+    ELSE
+        row_id := NEW.id::TEXT;
+    END IF;
+
+    PERFORM pg_notify('r2s_changes', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', TG_OP,
+        'id', row_id
+    )::TEXT);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notify_campaigns_change ON campaigns;
+CREATE TRIGGER notify_campaigns_change
+    AFTER INSERT OR UPDATE OR DELETE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION r2s_notify_change();
+
+DROP TRIGGER IF EXISTS notify_participations_change ON participations;
+CREATE TRIGGER notify_participations_change
+    AFTER INSERT OR UPDATE OR DELETE ON participations
+    FOR EACH ROW EXECUTE FUNCTION r2s_notify_change();
@@ -0,0 +1,65 @@
+-- Store every address in canonical lower-case form. Services compare and
+-- index addresses as plain strings and return them EIP-55 checksummed
+-- (pkg/address), so mixed-case rows written before that would never match.
+-- Safe to re-run.
+
+-- Lower-casing must not merge two users or campaigns; stop with the
+-- offending addresses instead of failing on a unique index halfway through
+DO $$
+DECLARE
+    dupes TEXT;
+BEGIN
+    SELECT string_agg(addr, ', ') INTO dupes
+    FROM (
+        SELECT LOWER(wallet_address) AS addr
+        FROM users
+        GROUP BY LOWER(wallet_address)
+        HAVING COUNT(*) > 1
+    ) d;
+
+    IF dupes IS NOT NULL THEN
+        RAISE EXCEPTION 'users share a wallet address up to case: %', dupes;
+    END IF;
+
+    SELECT string_agg(addr, ', ') INTO dupes
+    FROM (
+        SELECT LOWER(chain_address) AS addr
+        FROM campaigns
+        GROUP BY LOWER(chain_address)
+        HAVING COUNT(*) > 1
+    ) d;
+
+    IF dupes IS NOT NULL THEN
+        RAISE EXCEPTION 'campaigns share a chain address up to case: %', dupes;
+    END IF;
+END;
+$$;
+
+UPDATE users SET wallet_address = LOWER(wallet_address)
+WHERE wallet_address <> LOWER(wallet_address);
+
+UPDATE campaigns SET chain_address = LOWER(chain_address)
+WHERE chain_address <> LOWER(chain_address);
+
+UPDATE campaigns SET merchant_wallet = LOWER(merchant_wallet)
+WHERE merchant_wallet <> LOWER(merchant_wallet);
+
+UPDATE participations SET wallet_address = LOWER(wallet_address)
+WHERE wallet_address <> LOWER(wallet_address);
+
+-- Reject non-canonical writes from now on
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_wallet_address_canonical;
+ALTER TABLE users ADD CONSTRAINT users_wallet_address_canonical
+    CHECK (wallet_address ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_chain_address_canonical;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_chain_address_canonical
+    CHECK (chain_address ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_merchant_wallet_canonical;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_merchant_wallet_canonical
+    CHECK (merchant_wallet ~ '^0x[0-9a-f]{40}$');
+
+ALTER TABLE participations DROP CONSTRAINT IF EXISTS participations_wallet_address_canonical;
+ALTER TABLE participations ADD CONSTRAINT participations_wallet_address_canonical
+    CHECK (wallet_address ~ '^0x[0-9a-f]{40}$');
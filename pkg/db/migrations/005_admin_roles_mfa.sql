@@ -0,0 +1,23 @@
+-- User roles and TOTP multi-factor authentication for the admin API.
+-- Admins are promoted by hand:
+--   UPDATE users SET role = 'admin' WHERE wallet_address = '0x...';
+-- and must enroll MFA (POST /auth/mfa/setup, /auth/mfa/enable) before the
+-- gateway lets them through to /api/admin.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'users_role_check') THEN
+        ALTER TABLE users ADD CONSTRAINT users_role_check
+            CHECK (role IN ('user', 'merchant', 'admin'));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users (role) WHERE role <> 'user';
+
+-- Set when the session passed an MFA challenge; access tokens issued for
+-- the session (including refreshes) then carry "mfa": true
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS mfa_verified_at TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+-- Where each campaign's metadata document is published (ipfs:// or https://)
+-- and the SHA-256 of the published document, so unchanged campaigns are not
+-- published again. Older CIDs stay pinned, so URIs already written on-chain
+-- keep resolving.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS metadata_uri TEXT,
+    ADD COLUMN IF NOT EXISTS metadata_hash TEXT,
+    ADD COLUMN IF NOT EXISTS metadata_published_at TIMESTAMPTZ;
@@ -0,0 +1,7 @@
+-- Operators (ops) settle campaigns and publish metadata on behalf of
+-- merchants without the admin dashboard's user management. Roles are
+-- changed through POST /api/admin/users/role.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check
+    CHECK (role IN ('user', 'merchant', 'ops', 'admin'));
@@ -0,0 +1,23 @@
+-- KYC tier upgrades. A user submits identity documents for a tier; the
+-- KYC provider reviews them and reports back through POST
+-- /webhooks/kyc, which approves or rejects the application and raises
+-- users.kyc_tier. Documents live in object storage under kyc/.
+
+CREATE TABLE IF NOT EXISTS kyc_applications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_tier SMALLINT NOT NULL CHECK (requested_tier BETWEEN 1 AND 3),
+    document_type TEXT NOT NULL,
+    document_keys TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reject_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_applications_user ON kyc_applications (user_id, created_at DESC);
+
+-- One application under review per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_applications_pending
+    ON kyc_applications (user_id) WHERE status = 'pending';
@@ -0,0 +1,30 @@
+-- Merchant registrations. A user registers a business with its payout
+-- wallet and accepts the merchant fee; an admin approves the registration,
+-- which grants the merchant role, and may later suspend and reinstate it.
+-- Merchants are users, so the merchant id is the user id and
+-- campaigns.merchant_id keeps referencing users(id).
+--
+--   pending -> approved | rejected
+--   approved -> suspended -> approved
+--   rejected -> pending (registration resubmitted)
+
+CREATE TABLE IF NOT EXISTS merchants (
+    id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    business_name TEXT NOT NULL,
+    registration_number TEXT,
+    contact_email TEXT NOT NULL,
+    contact_phone TEXT,
+    website TEXT,
+    country CHAR(2) NOT NULL,
+    payout_wallet TEXT NOT NULL,
+    fee_bps INT NOT NULL DEFAULT 250 CHECK (fee_bps BETWEEN 0 AND 10000),
+    fee_agreed_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'suspended')),
+    status_reason TEXT,
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchants_status ON merchants (status, created_at);
@@ -0,0 +1,6 @@
+-- Device binding of sessions. auth-server stores the device fingerprint
+-- (sessions.device_fingerprint) and the country the CDN reports for the
+-- client IP when a session is created; a refresh from another fingerprint
+-- or country ends the session and requires signing in again.
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS country CHAR(2);
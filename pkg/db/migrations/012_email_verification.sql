@@ -0,0 +1,9 @@
+-- Email verification and account recovery. users.email is set unverified;
+-- following the emailed link sets email_verified_at. A verified email
+-- belongs to one account, which can recover access with it and its linked
+-- LINE account by re-linking a new wallet.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_verified_email
+    ON users (LOWER(email)) WHERE email_verified_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Campaign titles and metadata for the text filter of campaign lists. The
+-- columns already exist where campaigns are created through core-server.
+
+ALTER TABLE campaigns
+    ADD COLUMN IF NOT EXISTS title TEXT,
+    ADD COLUMN IF NOT EXISTS metadata JSONB;
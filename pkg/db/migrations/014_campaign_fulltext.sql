@@ -0,0 +1,14 @@
+-- Full-text search of campaigns. search_vector weighs the title over the
+-- description; merchant names are matched separately, as they live in
+-- merchants. The simple configuration neither stems nor drops stop words,
+-- so Korean, Japanese and Thai titles are searchable by their words.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS description TEXT;
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_search ON campaigns USING GIN (search_vector);
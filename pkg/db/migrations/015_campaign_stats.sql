@@ -0,0 +1,16 @@
+-- Daily aggregates of campaign participation, written by batch-server and
+-- read by GetCampaignStats. Each row is the campaign's standing at the last
+-- refresh of the day, so the rows of a campaign trace its funding over time.
+
+CREATE TABLE IF NOT EXISTS campaign_stats_daily (
+    campaign_id BIGINT NOT NULL REFERENCES campaigns(id),
+    day DATE NOT NULL,
+    -- participant_count counts everyone who joined, cancelled or not
+    participant_count BIGINT NOT NULL,
+    funded_count BIGINT NOT NULL,
+    cancelled_count BIGINT NOT NULL,
+    -- total_deposit sums the deposits of funded participants
+    total_deposit NUMERIC(20,6) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (campaign_id, day)
+);
@@ -0,0 +1,32 @@
+-- Publish participant changes on r2s_changes with the campaign they belong
+-- to, so caches holding a campaign's deposit total (query-server) are
+-- dropped when deposits move. Participants are written by event-receiver as
+-- it indexes on-chain joins and cancellations.
+-- Payload: {"table": "participants", "op": "...", "id": "...", "campaign_id": "..."}
+
+CREATE OR REPLACE FUNCTION r2s_notify_participant_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed participants;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('r2s_changes', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', TG_OP,
+        'id', changed.id::TEXT,
+        'campaign_id', changed.campaign_id::TEXT
+    )::TEXT);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notify_participants_change ON participants;
+CREATE TRIGGER notify_participants_change
+    AFTER INSERT OR UPDATE OR DELETE ON participants
+    FOR EACH ROW EXECUTE FUNCTION r2s_notify_participant_change();
@@ -0,0 +1,17 @@
+-- One schema for every service. query-server used to read a separate
+-- schema (BIGINT ids, BYTEA addresses, participants, settlements and
+-- rebates tables) that core-server never wrote; it now reads the tables in
+-- init-postgres.sql. Settlements are campaigns with status 'settled' and a
+-- settlement_date, and rebates are the actual_rebate of settled
+-- participations. Databases created from the old query-server schema keep
+-- their tables, which nothing reads any more. Safe to re-run.
+
+-- core-server pauses campaigns (admin), which the original check rejected
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_status_check;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_status_check CHECK (status IN (
+    'draft', 'recruiting', 'reached', 'fulfillment', 'settled', 'failed', 'cancelled', 'paused'
+));
+
+-- Settled campaigns by merchant, for merchant settlement history
+CREATE INDEX IF NOT EXISTS idx_campaigns_merchant_settled
+    ON campaigns (merchant_id, settlement_date DESC) WHERE status = 'settled';
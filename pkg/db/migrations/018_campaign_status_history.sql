@@ -0,0 +1,18 @@
+-- Every campaign status change with the actor that made it and why,
+-- written by core-server in the transaction that changes the status.
+-- actor_id and actor_type follow audit_log; actor_id is NULL for changes
+-- made by a service on its own behalf.
+
+CREATE TABLE IF NOT EXISTS campaign_status_history (
+    id UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor_id TEXT,
+    actor_type TEXT NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_status_history_campaign
+    ON campaign_status_history (campaign_id, created_at);
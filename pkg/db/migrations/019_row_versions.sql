@@ -0,0 +1,37 @@
+-- Row versions for optimistic concurrency. Campaigns and participations
+-- carry a version that every change bumps; core-server rejects updates and
+-- cancellations sent with a version other than the current one (If-Match),
+-- so concurrent admin edits and writers such as event-receiver's on-chain
+-- projections cannot silently overwrite each other.
+--
+-- The bump is a trigger so writers outside core-server need no changes.
+-- Columns named in the trigger arguments are bookkeeping and do not count
+-- as a change: publishing campaign metadata leaves the version alone.
+-- Safe to re-run.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE participations ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION r2s_bump_version()
+RETURNS TRIGGER AS $$
+DECLARE
+    ignored TEXT[] := ARRAY['version', 'updated_at'] || TG_ARGV;
+BEGIN
+    IF to_jsonb(NEW) - ignored IS DISTINCT FROM to_jsonb(OLD) - ignored THEN
+        NEW.version := OLD.version + 1;
+    ELSE
+        NEW.version := OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS bump_campaigns_version ON campaigns;
+CREATE TRIGGER bump_campaigns_version
+    BEFORE UPDATE ON campaigns
+    FOR EACH ROW EXECUTE FUNCTION r2s_bump_version('metadata_uri', 'metadata_hash', 'metadata_published_at');
+
+DROP TRIGGER IF EXISTS bump_participations_version ON participations;
+CREATE TRIGGER bump_participations_version
+    BEFORE UPDATE ON participations
+    FOR EACH ROW EXECUTE FUNCTION r2s_bump_version();
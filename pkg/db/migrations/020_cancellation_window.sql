@@ -0,0 +1,19 @@
+-- Cancellation terms. A participant may cancel all or part of a deposit
+-- for free until the campaign's cancel_deadline (its end_time, when
+-- deposits lock, when unset). Later cancels cost late_cancel_penalty_bps of
+-- the cancelled amount, or are refused when the campaign sets no penalty.
+-- The terms are fixed once the campaign leaves draft.
+--
+-- cancel_pending holds the amount of a cancel requested but not yet seen
+-- on chain; cancel_penalty is what cancels so far have cost.
+-- Safe to re-run.
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS cancel_deadline TIMESTAMPTZ;
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS late_cancel_penalty_bps INTEGER;
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_late_cancel_penalty_bps_check;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_late_cancel_penalty_bps_check
+    CHECK (late_cancel_penalty_bps BETWEEN 0 AND 10000);
+
+ALTER TABLE participations ADD COLUMN IF NOT EXISTS cancel_penalty NUMERIC(36, 18) NOT NULL DEFAULT 0;
+UPDATE participations SET cancel_pending = 0 WHERE cancel_pending IS NULL;
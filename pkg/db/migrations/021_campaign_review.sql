@@ -0,0 +1,39 @@
+-- Campaign review. Merchants submit drafts for review; ops approve or
+-- reject them with a comment, and only an approved campaign that was
+-- deployed on chain opens for participation:
+--
+--   draft -> pending_review -> approved -> recruiting
+--                           -> rejected -> draft
+--
+-- A campaign has no chain address until it is deployed. Every review
+-- decision is kept in campaign_reviews. Safe to re-run.
+
+ALTER TABLE campaigns ALTER COLUMN chain_address DROP NOT NULL;
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_status_check;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_status_check CHECK (status IN (
+    'draft', 'pending_review', 'approved', 'rejected',
+    'recruiting', 'reached', 'fulfillment', 'settled', 'failed', 'cancelled', 'paused'
+));
+
+ALTER TABLE campaigns DROP CONSTRAINT IF EXISTS campaigns_deployed_check;
+ALTER TABLE campaigns ADD CONSTRAINT campaigns_deployed_check CHECK (
+    chain_address IS NOT NULL
+    OR status IN ('draft', 'pending_review', 'approved', 'rejected', 'cancelled')
+);
+
+CREATE TABLE IF NOT EXISTS campaign_reviews (
+    id UUID PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('approved', 'rejected')),
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_reviews_campaign
+    ON campaign_reviews (campaign_id, created_at);
+
+-- Ops work through the review queue oldest first
+CREATE INDEX IF NOT EXISTS idx_campaigns_pending_review
+    ON campaigns (updated_at) WHERE status = 'pending_review';
@@ -0,0 +1,29 @@
+-- Media uploads. Clients upload campaign images and merchant logos straight
+-- to object storage through a pre-signed URL, then complete the upload:
+-- core-server checks the file is a JPEG or PNG image within the size limit,
+-- stores it under media/ with a thumbnail and records their public URLs.
+-- Campaigns and merchants only take images from completed uploads, rather
+-- than URLs on arbitrary hosts. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS media_uploads (
+    id UUID PRIMARY KEY,
+    owner_id UUID NOT NULL,
+    purpose TEXT NOT NULL CHECK (purpose IN ('campaign_image', 'merchant_logo')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    -- Where the client uploads to; the file is moved away on completion
+    upload_key TEXT NOT NULL,
+    url TEXT,
+    thumbnail_url TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_uploads_owner ON media_uploads (owner_id, created_at);
+
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS image_thumbnail_url TEXT;
+
+ALTER TABLE merchants ADD COLUMN IF NOT EXISTS logo_url TEXT;
+ALTER TABLE merchants ADD COLUMN IF NOT EXISTS logo_thumbnail_url TEXT;
@@ -0,0 +1,31 @@
+-- Campaign categories and tags. Categories are a small taxonomy kept by ops
+-- ("Coffee", "Convenience", "Dining") that the app shows as tabs, ordered by
+-- position; a campaign is in at most one. Tags are free-form lowercase
+-- labels set by the merchant. Categories used to be an informal
+-- metadata.category key; matching values are carried over. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS campaign_categories (
+    slug TEXT PRIMARY KEY CHECK (slug ~ '^[a-z0-9]+(-[a-z0-9]+)*$'),
+    name TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO campaign_categories (slug, name, position) VALUES
+    ('coffee', 'Coffee', 10),
+    ('convenience', 'Convenience', 20),
+    ('dining', 'Dining', 30)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Categories with campaigns cannot be deleted
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS category TEXT REFERENCES campaign_categories (slug);
+ALTER TABLE campaigns ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_category ON campaigns (category, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaigns_tags ON campaigns USING GIN (tags);
+
+UPDATE campaigns c
+SET category = cc.slug
+FROM campaign_categories cc
+WHERE c.category IS NULL AND cc.slug = lower(c.metadata->>'category');
@@ -0,0 +1,19 @@
+-- Campaigns users favorited (their watchlist), and the watchlist push topic.
+-- batch-server pushes to watchers once when a recruiting campaign nears its
+-- min_qty and once when recruitment is about to close, which is when
+-- deposits lock; the *_notified_at columns record that it did. Safe to
+-- re-run.
+
+CREATE TABLE IF NOT EXISTS campaign_favorites (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    near_target_notified_at TIMESTAMPTZ,
+    closing_notified_at TIMESTAMPTZ,
+    PRIMARY KEY (user_id, campaign_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_favorites_user ON campaign_favorites (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_campaign_favorites_campaign ON campaign_favorites (campaign_id);
+
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS watchlist BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,40 @@
+-- Referral program: one code per user, the referral a new user signed up or
+-- applied a code with, and the rewards ledger. A referral qualifies on the
+-- referee's first participation, which writes a reward for the referrer and
+-- one for the referee, each REFERRAL_*_BONUS_BPS of that deposit. Rewards
+-- are pending until paid out. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS referral_codes (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A user is referred at most once
+CREATE TABLE IF NOT EXISTS referrals (
+    referee_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    qualified_at TIMESTAMPTZ,
+    participation_id UUID REFERENCES participations(id) ON DELETE SET NULL,
+    CHECK (referee_id <> referrer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals (referrer_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS referral_rewards (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referee_id UUID NOT NULL REFERENCES referrals(referee_id) ON DELETE CASCADE,
+    participation_id UUID REFERENCES participations(id) ON DELETE SET NULL,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('referrer', 'referee')),
+    amount NUMERIC(36, 18) NOT NULL CHECK (amount > 0),
+    bonus_bps INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    paid_at TIMESTAMPTZ,
+    UNIQUE (referee_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_referral_rewards_user ON referral_rewards (user_id, created_at DESC);
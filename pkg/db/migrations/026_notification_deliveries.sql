@@ -0,0 +1,35 @@
+-- LINE and email notifications. core-server queues one delivery per user
+-- and channel for each event (campaign reached, rebate settled,
+-- cancellation confirmed) and batch-server sends them, retrying failures
+-- with backoff. The rows are the delivery log users can read back; each
+-- event reaches a user at most once per channel (event_key). Channels and
+-- the new cancellations topic are opt-outs in notification_preferences.
+-- Safe to re-run.
+
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS cancellations BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS line BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS email BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_key TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('line', 'email')),
+    -- LINE user id or email address when queued
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ,
+    UNIQUE (event_key, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_due
+    ON notification_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_user
+    ON notification_deliveries (user_id, created_at DESC);
@@ -0,0 +1,36 @@
+-- Fulfillment confirmation. Once a campaign is in fulfillment the merchant
+-- marks participations fulfilled, the whole campaign at once or single
+-- participants, with proof: the SHA-256 hash of a receipt or photo and
+-- optionally the photo itself (a fulfillment_proof upload). Each
+-- participant may confirm or dispute until confirm_deadline; a fulfillment
+-- left unanswered counts as confirmed once it passes. Marking a disputed
+-- fulfillment again replaces its proof and restarts the window.
+--
+-- A campaign is only settled, and tx-helper only builds its settlement
+-- transaction, when every active participation is fulfilled and accepted
+-- and none is disputed. Campaigns already in fulfillment need marking
+-- before they can settle. Safe to re-run.
+
+ALTER TABLE media_uploads DROP CONSTRAINT IF EXISTS media_uploads_purpose_check;
+ALTER TABLE media_uploads ADD CONSTRAINT media_uploads_purpose_check
+    CHECK (purpose IN ('campaign_image', 'merchant_logo', 'fulfillment_proof'));
+
+CREATE TABLE IF NOT EXISTS fulfillments (
+    participation_id UUID PRIMARY KEY REFERENCES participations(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('fulfilled', 'confirmed', 'disputed')),
+    -- 0x-prefixed SHA-256 of the receipt or photo
+    proof_hash TEXT NOT NULL,
+    proof_url TEXT,
+    proof_thumbnail_url TEXT,
+    note TEXT,
+    fulfilled_by UUID,
+    fulfilled_at TIMESTAMPTZ NOT NULL,
+    confirm_deadline TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    dispute_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_fulfillments_campaign ON fulfillments (campaign_id, status);
+CREATE INDEX IF NOT EXISTS idx_fulfillments_user ON fulfillments (user_id, fulfilled_at DESC);
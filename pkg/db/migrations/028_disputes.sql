@@ -0,0 +1,44 @@
+-- Disputes and refunds. A participant opens a dispute on the fulfillment of
+-- their order (disputing a fulfillment opens one) or, within
+-- DISPUTE_SETTLEMENT_WINDOW of settlement, on their settlement. Admins
+-- resolve open disputes: rejecting closes them, refunding moves them to
+-- refunding until the refund is done, either through the payment provider
+-- (the payment's refunded webhook completes it) or on chain (an admin
+-- records the mined refund transaction). A merchant marking a disputed
+-- fulfillment again closes its open dispute as redelivered.
+--
+-- sla_due_at is when the current stage is due: resolving an open dispute,
+-- or completing the refund of a refunding one. batch-server marks the
+-- overdue ones in sla_breached_at. At most one dispute per participation is
+-- open or refunding. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY,
+    participation_id UUID NOT NULL REFERENCES participations(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('fulfillment', 'settlement')),
+    status VARCHAR(16) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'refunding', 'refunded', 'rejected', 'redelivered')),
+    reason TEXT NOT NULL,
+    resolution_note TEXT,
+    refund_amount NUMERIC(36, 18) CHECK (refund_amount > 0),
+    refund_method VARCHAR(16) CHECK (refund_method IN ('payment', 'onchain')),
+    payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    refund_tx_hash TEXT,
+    resolved_by UUID,
+    resolved_at TIMESTAMPTZ,
+    refunded_at TIMESTAMPTZ,
+    sla_due_at TIMESTAMPTZ NOT NULL,
+    sla_breached_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_pending_participation
+    ON disputes (participation_id) WHERE status IN ('open', 'refunding');
+CREATE INDEX IF NOT EXISTS idx_disputes_sla
+    ON disputes (sla_due_at) WHERE status IN ('open', 'refunding');
+CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_disputes_user ON disputes (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_disputes_payment ON disputes (payment_id) WHERE status = 'refunding';
@@ -0,0 +1,29 @@
+-- Redemption vouchers. Settling a campaign issues each settled participant
+-- a voucher to redeem their order at the merchant's point of sale. The code
+-- is derived from the voucher id with VOUCHER_SECRET, so the participant can
+-- be shown it again, and only its SHA-256 is stored here; rotating the
+-- secret invalidates the codes already issued.
+--
+-- Merchants validate a code and mark it redeemed; the status update only
+-- succeeds on an issued voucher, so a code is redeemed at most once. A
+-- voucher is voided when its settlement is fully refunded after a dispute.
+-- Campaigns settled before this migration have no vouchers. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS vouchers (
+    id UUID PRIMARY KEY,
+    participation_id UUID NOT NULL UNIQUE REFERENCES participations(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- hex SHA-256 of the normalized code
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    status VARCHAR(16) NOT NULL DEFAULT 'issued'
+        CHECK (status IN ('issued', 'redeemed', 'void')),
+    issued_at TIMESTAMPTZ NOT NULL,
+    redeemed_at TIMESTAMPTZ,
+    redeemed_by UUID,
+    voided_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vouchers_user ON vouchers (user_id, issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_vouchers_campaign ON vouchers (campaign_id, status);
@@ -0,0 +1,39 @@
+-- Campaign templates. Merchants relaunching a recurring offer save its
+-- pricing, rebate parameters, imagery and cancellation terms under a name
+-- and launch new draft campaigns from it with a fresh lock window, or clone
+-- a past campaign directly. Either way the new campaign is validated as if
+-- created by hand, with the merchant's current fee, so a template saved
+-- under older bounds cannot launch outside today's.
+--
+-- The cancellation deadline is kept relative to the end of the window.
+-- category is not a foreign key so deleting a category does not depend on
+-- templates; launching from a template whose category is gone fails until
+-- the template is updated. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS campaign_templates (
+    id UUID PRIMARY KEY,
+    merchant_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    image_url TEXT,
+    image_thumbnail_url TEXT,
+    merchant_wallet VARCHAR(42) NOT NULL,
+    base_price NUMERIC(36, 18) NOT NULL CHECK (base_price > 0),
+    min_qty INTEGER NOT NULL CHECK (min_qty > 0),
+    discount_rate INTEGER NOT NULL CHECK (discount_rate >= 0 AND discount_rate <= 10000),
+    save_floor_bps INTEGER NOT NULL CHECK (save_floor_bps >= 0 AND save_floor_bps <= 10000),
+    r_max_bps INTEGER NOT NULL CHECK (r_max_bps >= 0 AND r_max_bps <= 10000),
+    -- seconds before the end of the window free cancellation closes
+    cancel_lead_seconds BIGINT CHECK (cancel_lead_seconds >= 0),
+    late_cancel_penalty_bps INTEGER CHECK (late_cancel_penalty_bps >= 0 AND late_cancel_penalty_bps <= 10000),
+    category TEXT,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    source_campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (merchant_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaign_templates_merchant ON campaign_templates (merchant_id, updated_at DESC);
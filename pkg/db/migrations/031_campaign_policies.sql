@@ -0,0 +1,17 @@
+-- Campaign policies. Admins bound the terms of new campaigns per merchant
+-- tier, the KYC tier of the merchant's user: the highest max rebate
+-- (r_max_bps), the shortest lock window and the merchant fee range. A
+-- campaign is checked when it is created, cloned or launched from a
+-- template, and again when its window changes; a NULL bound is not
+-- enforced. Tiers without a policy are only held to the built-in checks,
+-- so no rows are seeded. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS campaign_policies (
+    merchant_tier INTEGER PRIMARY KEY CHECK (merchant_tier >= 0 AND merchant_tier <= 3),
+    max_r_max_bps INTEGER CHECK (max_r_max_bps >= 0 AND max_r_max_bps <= 10000),
+    min_lock_hours INTEGER CHECK (min_lock_hours >= 0),
+    min_fee_bps INTEGER CHECK (min_fee_bps >= 0 AND min_fee_bps <= 10000),
+    max_fee_bps INTEGER CHECK (max_fee_bps >= 0 AND max_fee_bps <= 10000),
+    updated_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT check_fee_range CHECK (min_fee_bps IS NULL OR max_fee_bps IS NULL OR min_fee_bps <= max_fee_bps)
+);
@@ -0,0 +1,27 @@
+-- Transactional outbox of core-server domain events (pkg/outbox). Services
+-- append an event in the transaction that makes the change, so an event is
+-- stored exactly when its change commits. batch-server's relay publishes
+-- unpublished events to the r2s:events Redis stream, in order per
+-- aggregate, marks them published and prunes them after
+-- OUTBOX_RETENTION. Safe to re-run.
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    actor_id TEXT,
+    actor_type TEXT NOT NULL,
+    request_id TEXT,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished
+    ON outbox_events (seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published
+    ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate
+    ON outbox_events (aggregate_type, aggregate_id, seq);
@@ -0,0 +1,70 @@
+-- Move campaign stats and participant change notifications onto the
+-- unified schema (017_unified_schema.sql). 015_campaign_stats.sql and
+-- 016_participant_change_notify.sql were written against query-server's
+-- old schema, with BIGINT campaign ids and a participants table.
+-- Safe to re-run.
+
+-- Daily stats keyed by the old BIGINT campaign ids match no campaign any
+-- more; batch-server refills the table on its next refresh. Amounts are in
+-- base units, like participations.deposit_amount.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'campaign_stats_daily' AND column_name = 'campaign_id' AND data_type <> 'uuid'
+    ) THEN
+        DROP TABLE campaign_stats_daily;
+    END IF;
+END;
+$$;
+
+CREATE TABLE IF NOT EXISTS campaign_stats_daily (
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    -- participant_count counts everyone who joined, cancelled or not
+    participant_count BIGINT NOT NULL,
+    funded_count BIGINT NOT NULL,
+    cancelled_count BIGINT NOT NULL,
+    -- total_deposit sums the deposits of funded participants
+    total_deposit NUMERIC(36,18) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (campaign_id, day)
+);
+
+-- Publish participation changes on r2s_changes with the campaign they
+-- belong to, so caches holding a campaign's deposit total (query-server)
+-- are dropped when deposits move. Participations are written by
+-- core-server and by event-receiver as it indexes on-chain joins and
+-- cancellations. Replaces the plain r2s_notify_change trigger of
+-- 002_change_notify.sql.
+-- Payload: {"table": "participations", "op": "...", "id": "...", "campaign_id": "..."}
+
+CREATE OR REPLACE FUNCTION r2s_notify_participation_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    changed participations;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := OLD;
+    ELSE
+        changed := NEW;
+    END IF;
+
+    PERFORM pg_notify('r2s_changes', json_build_object(
+        'table', TG_TABLE_NAME,
+        'op', TG_OP,
+        'id', changed.id::TEXT,
+        'campaign_id', changed.campaign_id::TEXT
+    )::TEXT);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notify_participations_change ON participations;
+CREATE TRIGGER notify_participations_change
+    AFTER INSERT OR UPDATE OR DELETE ON participations
+    FOR EACH ROW EXECUTE FUNCTION r2s_notify_participation_change();
+
+-- The trigger of 016 on the old participants table, where it exists
+DROP FUNCTION IF EXISTS r2s_notify_participant_change() CASCADE;
@@ -0,0 +1,146 @@
+// Package diag serves runtime diagnostics: net/http/pprof profiles, full
+// goroutine dumps, GC and memory statistics. The endpoints expose internals
+// and can stall a busy process, so they are only served on a separate
+// internal listener (bind it to loopback or a private network), never on the
+// public router.
+package diag
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"time"
+)
+
+// Handler returns the diagnostics endpoints:
+//
+//	GET  /debug/pprof/...     standard pprof profiles (go tool pprof)
+//	GET  /debug/goroutines    full dump of every goroutine's stack
+//	GET  /debug/runtime       memory, GC and scheduler statistics as JSON
+//	POST /debug/gc            runs a GC and returns memory to the OS
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/goroutines", goroutines)
+	mux.HandleFunc("/debug/runtime", runtimeStats)
+	mux.HandleFunc("/debug/gc", forceGC)
+	return mux
+}
+
+// Start serves Handler on addr in the background. An empty addr disables
+// the listener. It fails only if addr cannot be bound.
+func Start(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	// Profiles and traces may legitimately run for a while, so only the
+	// header read is bounded
+	srv := &http.Server{Handler: Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil {
+			slog.Error("Diagnostics server stopped", "error", err)
+		}
+	}()
+	slog.Info("Diagnostics server starting", "addr", ln.Addr().String())
+	return nil
+}
+
+func goroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = rpprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+func runtimeStats(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var gc debug.GCStats
+	gc.PauseQuantiles = make([]time.Duration, 5)
+	debug.ReadGCStats(&gc)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data": map[string]interface{}{
+			"goroutines": runtime.NumGoroutine(),
+			"gomaxprocs": runtime.GOMAXPROCS(0),
+			"numCpu":     runtime.NumCPU(),
+			"memory": map[string]interface{}{
+				"heapAlloc":    mem.HeapAlloc,
+				"heapInuse":    mem.HeapInuse,
+				"heapIdle":     mem.HeapIdle,
+				"heapReleased": mem.HeapReleased,
+				"heapObjects":  mem.HeapObjects,
+				"stackInuse":   mem.StackInuse,
+				"sys":          mem.Sys,
+				"totalAlloc":   mem.TotalAlloc,
+				"mallocs":      mem.Mallocs,
+				"frees":        mem.Frees,
+			},
+			"gc": map[string]interface{}{
+				"numGc":          gc.NumGC,
+				"lastGc":         gc.LastGC,
+				"nextGcBytes":    mem.NextGC,
+				"pauseTotal":     gc.PauseTotal.String(),
+				"pauseQuantiles": durations(gc.PauseQuantiles),
+				"gcCpuFraction":  mem.GCCPUFraction,
+				// A negative input only reads the limit (GOMEMLIMIT)
+				"memoryLimit": debug.SetMemoryLimit(-1),
+			},
+		},
+	})
+}
+
+func forceGC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"success": false, "error": "Method not allowed"})
+		return
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	debug.FreeOSMemory()
+	took := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	slog.Warn("Forced GC via diagnostics endpoint", "duration", took.String())
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"data": map[string]interface{}{
+			"duration":        took.String(),
+			"heapAllocBefore": before.HeapAlloc,
+			"heapAllocAfter":  after.HeapAlloc,
+			"heapReleased":    after.HeapReleased,
+		},
+	})
+}
+
+func durations(ds []time.Duration) []string {
+	out := make([]string, len(ds))
+	for i, d := range ds {
+		out[i] = d.String()
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,335 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Reason identifies one specific failure, such as R2S-1001 for a sign-in
+// with an unknown nonce. Clients branch on it rather than on the message,
+// which is localized. Reasons are grouped by range:
+//
+//	1xxx  authentication, sessions, MFA, accounts and KYC (auth-server)
+//	2xxx  campaigns and participations (core-server)
+//	3xxx  payments
+//	4xxx  merchants
+//	5xxx  administration
+//	6xxx  notifications
+//	9xxx  the gateway
+//
+// A reason is never reused for another failure once released.
+type Reason string
+
+const (
+	ReasonInvalidNonce         Reason = "R2S-1001"
+	ReasonNonceExpired         Reason = "R2S-1002"
+	ReasonInvalidMessage       Reason = "R2S-1003"
+	ReasonAddressMismatch      Reason = "R2S-1004"
+	ReasonInvalidSignature     Reason = "R2S-1005"
+	ReasonInvalidWallet        Reason = "R2S-1006"
+	ReasonChallengeRequired    Reason = "R2S-1007"
+	ReasonChallengeFailed      Reason = "R2S-1008"
+	ReasonInvalidLineToken     Reason = "R2S-1009"
+	ReasonAccountSuspended     Reason = "R2S-1010"
+	ReasonInvalidClient        Reason = "R2S-1011"
+	ReasonTokenRequired        Reason = "R2S-1101"
+	ReasonInvalidToken         Reason = "R2S-1102"
+	ReasonTokenRevoked         Reason = "R2S-1103"
+	ReasonInvalidRefreshToken  Reason = "R2S-1104"
+	ReasonInvalidSession       Reason = "R2S-1105"
+	ReasonSessionExpired       Reason = "R2S-1106"
+	ReasonSessionNotFound      Reason = "R2S-1107"
+	ReasonReauthenticate       Reason = "R2S-1108"
+	ReasonMFAAlreadyEnabled    Reason = "R2S-1201"
+	ReasonMFANotSetUp          Reason = "R2S-1202"
+	ReasonInvalidMFACode       Reason = "R2S-1203"
+	ReasonMFARequired          Reason = "R2S-1204"
+	ReasonUserNotFound         Reason = "R2S-1301"
+	ReasonInvalidEmail         Reason = "R2S-1302"
+	ReasonEmailChanged         Reason = "R2S-1303"
+	ReasonEmailTaken           Reason = "R2S-1304"
+	ReasonWalletTaken          Reason = "R2S-1305"
+	ReasonRecoveryUnavailable  Reason = "R2S-1306"
+	ReasonLineMismatch         Reason = "R2S-1307"
+	ReasonKYCPending           Reason = "R2S-1401"
+	ReasonKYCTierNotHigher     Reason = "R2S-1402"
+	ReasonKYCTierOutOfRange    Reason = "R2S-1403"
+	ReasonKYCNotFound          Reason = "R2S-1404"
+	ReasonKYCDocumentCount     Reason = "R2S-1405"
+	ReasonKYCDocumentType      Reason = "R2S-1406"
+	ReasonKYCDocumentTooLarge  Reason = "R2S-1407"
+	ReasonKYCDocumentFormat    Reason = "R2S-1408"
+	ReasonKYCDocumentUnread    Reason = "R2S-1409"
+	ReasonInvalidKYCWebhook    Reason = "R2S-1410"
+	ReasonInvalidKYCSignature  Reason = "R2S-1411"
+	ReasonCampaignNotFound     Reason = "R2S-2001"
+	ReasonNotCampaignOwner     Reason = "R2S-2002"
+	ReasonInvalidMinQuantity   Reason = "R2S-2003"
+	ReasonCampaignNotOpen      Reason = "R2S-2004"
+	ReasonCampaignNotSettled   Reason = "R2S-2005"
+	ReasonSettlementTooEarly   Reason = "R2S-2006"
+	ReasonCampaignNotPaused    Reason = "R2S-2007"
+	ReasonCampaignNotPausable  Reason = "R2S-2008"
+	ReasonMetadataDisabled     Reason = "R2S-2009"
+	ReasonCampaignTransition   Reason = "R2S-2010"
+	ReasonVersionRequired      Reason = "R2S-2011"
+	ReasonStaleVersion         Reason = "R2S-2012"
+	ReasonCancelTermsLocked    Reason = "R2S-2013"
+	ReasonCampaignInReview     Reason = "R2S-2014"
+	ReasonCampaignNotInReview  Reason = "R2S-2015"
+	ReasonCampaignNotApproved  Reason = "R2S-2016"
+	ReasonChainAddressTaken    Reason = "R2S-2017"
+	ReasonTemplateNotFound     Reason = "R2S-2018"
+	ReasonTemplateNameTaken    Reason = "R2S-2019"
+	ReasonPolicyRMaxBps        Reason = "R2S-2020"
+	ReasonPolicyLockDuration   Reason = "R2S-2021"
+	ReasonPolicyFeeRange       Reason = "R2S-2022"
+	ReasonPolicyNotFound       Reason = "R2S-2023"
+	ReasonParticipationMissing Reason = "R2S-2101"
+	ReasonAlreadyParticipating Reason = "R2S-2102"
+	ReasonInvalidDeposit       Reason = "R2S-2103"
+	ReasonNotCancellable       Reason = "R2S-2104"
+	ReasonJoinInProgress       Reason = "R2S-2105"
+	ReasonCancelWindowClosed   Reason = "R2S-2106"
+	ReasonInvalidCancelAmount  Reason = "R2S-2107"
+	ReasonMediaDisabled        Reason = "R2S-2201"
+	ReasonMediaType            Reason = "R2S-2202"
+	ReasonMediaTooLarge        Reason = "R2S-2203"
+	ReasonMediaNotFound        Reason = "R2S-2204"
+	ReasonMediaNotUploaded     Reason = "R2S-2205"
+	ReasonMediaUploadExpired   Reason = "R2S-2206"
+	ReasonMediaNotReady        Reason = "R2S-2207"
+	ReasonCategoryNotFound     Reason = "R2S-2301"
+	ReasonCategoryExists       Reason = "R2S-2302"
+	ReasonCategoryInUse        Reason = "R2S-2303"
+	ReasonInvalidTags          Reason = "R2S-2304"
+	ReasonWatchlistFull        Reason = "R2S-2401"
+	ReasonNotInFulfillment     Reason = "R2S-2501"
+	ReasonInvalidProofHash     Reason = "R2S-2502"
+	ReasonFulfillmentNotFound  Reason = "R2S-2503"
+	ReasonFulfillmentAnswered  Reason = "R2S-2504"
+	ReasonConfirmWindowClosed  Reason = "R2S-2505"
+	ReasonFulfillmentPending   Reason = "R2S-2506"
+	ReasonDisputeNotFound      Reason = "R2S-2601"
+	ReasonDisputeExists        Reason = "R2S-2602"
+	ReasonNotDisputable        Reason = "R2S-2603"
+	ReasonDisputeWindowClosed  Reason = "R2S-2604"
+	ReasonDisputeClosed        Reason = "R2S-2605"
+	ReasonRefundNotPending     Reason = "R2S-2606"
+	ReasonInvalidRefund        Reason = "R2S-2607"
+	ReasonNoPaymentToRefund    Reason = "R2S-2608"
+	ReasonVouchersDisabled     Reason = "R2S-2701"
+	ReasonVoucherNotFound      Reason = "R2S-2702"
+	ReasonVoucherRedeemed      Reason = "R2S-2703"
+	ReasonVoucherVoid          Reason = "R2S-2704"
+	ReasonPaymentNotFound      Reason = "R2S-3001"
+	ReasonInvalidAmount        Reason = "R2S-3002"
+	ReasonStripeDisabled       Reason = "R2S-3003"
+	ReasonInvalidWebhook       Reason = "R2S-3004"
+	ReasonWebhookSignature     Reason = "R2S-3005"
+	ReasonPaymentStatus        Reason = "R2S-3006"
+	ReasonMerchantNotFound     Reason = "R2S-4001"
+	ReasonMerchantRegistered   Reason = "R2S-4002"
+	ReasonMerchantNotApproved  Reason = "R2S-4003"
+	ReasonFeeNotAgreed         Reason = "R2S-4004"
+	ReasonFeeOnlyOnApproval    Reason = "R2S-4005"
+	ReasonNotOwnMerchant       Reason = "R2S-4006"
+	ReasonCannotSuspendAdmin   Reason = "R2S-5001"
+	ReasonCannotChangeOwnRole  Reason = "R2S-5002"
+	ReasonUserNotSuspended     Reason = "R2S-5003"
+	ReasonBulkSize             Reason = "R2S-5004"
+	ReasonDeviceNotFound       Reason = "R2S-6001"
+	ReasonInvalidPlatform      Reason = "R2S-6002"
+	ReasonInvalidDeviceToken   Reason = "R2S-6003"
+	ReasonReferralCodeNotFound Reason = "R2S-7001"
+	ReasonSelfReferral         Reason = "R2S-7002"
+	ReasonAlreadyReferred      Reason = "R2S-7003"
+	ReasonReferralClosed       Reason = "R2S-7004"
+	ReasonRoleRequired         Reason = "R2S-9001"
+	ReasonServiceUnavailable   Reason = "R2S-9002"
+	ReasonIdempotencyKeyLength Reason = "R2S-9003"
+	ReasonIdempotencyKeyInUse  Reason = "R2S-9004"
+	ReasonIdempotencyKeyReused Reason = "R2S-9005"
+	ReasonMaintenance          Reason = "R2S-9006"
+	ReasonFeatureFrozen        Reason = "R2S-9007"
+	ReasonQuotaExceeded        Reason = "R2S-9008"
+	ReasonQuotaNotFound        Reason = "R2S-9009"
+)
+
+// CatalogEntry is the code and client message of a reason. Message may hold
+// fmt verbs, filled from the arguments of Catalog.
+type CatalogEntry struct {
+	Reason  Reason
+	Code    Code
+	Message string
+}
+
+var catalog = map[Reason]CatalogEntry{}
+
+func init() {
+	for _, e := range []CatalogEntry{
+		{ReasonInvalidNonce, CodeUnauthorized, "invalid or expired nonce"},
+		{ReasonNonceExpired, CodeUnauthorized, "nonce expired"},
+		{ReasonInvalidMessage, CodeInvalidArgument, "invalid message format"},
+		{ReasonAddressMismatch, CodeUnauthorized, "address mismatch"},
+		{ReasonInvalidSignature, CodeUnauthorized, "invalid signature"},
+		{ReasonInvalidWallet, CodeInvalidArgument, "invalid wallet address"},
+		{ReasonChallengeRequired, CodeForbidden, "solve the challenge from GET /auth/nonce/challenge first"},
+		{ReasonChallengeFailed, CodeForbidden, "challenge failed"},
+		{ReasonInvalidLineToken, CodeUnauthorized, "invalid LINE ID token"},
+		{ReasonAccountSuspended, CodeForbidden, "account suspended"},
+		{ReasonInvalidClient, CodeUnauthorized, "invalid client credentials"},
+		{ReasonTokenRequired, CodeUnauthorized, "token required"},
+		{ReasonInvalidToken, CodeUnauthorized, "invalid token"},
+		{ReasonTokenRevoked, CodeUnauthorized, "token has been revoked"},
+		{ReasonInvalidRefreshToken, CodeUnauthorized, "invalid refresh token"},
+		{ReasonInvalidSession, CodeUnauthorized, "invalid session"},
+		{ReasonSessionExpired, CodeUnauthorized, "session expired"},
+		{ReasonSessionNotFound, CodeNotFound, "session not found"},
+		{ReasonReauthenticate, CodeUnauthorized, "session was used from a new device or location; sign in again"},
+		{ReasonMFAAlreadyEnabled, CodeConflict, "MFA is already enabled"},
+		{ReasonMFANotSetUp, CodeConflict, "MFA has not been set up"},
+		{ReasonInvalidMFACode, CodeUnauthorized, "invalid MFA code"},
+		{ReasonMFARequired, CodeForbidden, "MFA verification required"},
+		{ReasonUserNotFound, CodeNotFound, "user not found"},
+		{ReasonInvalidEmail, CodeInvalidArgument, "invalid email address"},
+		{ReasonEmailChanged, CodeConflict, "the email was changed or verified since the link was sent"},
+		{ReasonEmailTaken, CodeConflict, "email is verified by another account"},
+		{ReasonWalletTaken, CodeConflict, "wallet belongs to another account"},
+		{ReasonRecoveryUnavailable, CodeUnavailable, "account recovery is not configured"},
+		{ReasonLineMismatch, CodeUnauthorized, "LINE account does not match"},
+		{ReasonKYCPending, CodeConflict, "a KYC application is already under review"},
+		{ReasonKYCTierNotHigher, CodeInvalidArgument, "requested tier must be above the current tier"},
+		{ReasonKYCTierOutOfRange, CodeInvalidArgument, "tier must be between 1 and %d"},
+		{ReasonKYCNotFound, CodeNotFound, "KYC application not found"},
+		{ReasonKYCDocumentCount, CodeInvalidArgument, "between 1 and %d documents are required"},
+		{ReasonKYCDocumentType, CodeInvalidArgument, "unsupported document type"},
+		{ReasonKYCDocumentTooLarge, CodeInvalidArgument, "documents must be at most %d MB"},
+		{ReasonKYCDocumentFormat, CodeInvalidArgument, "documents must be JPEG, PNG or PDF"},
+		{ReasonKYCDocumentUnread, CodeInvalidArgument, "unreadable document"},
+		{ReasonInvalidKYCWebhook, CodeInvalidArgument, "invalid KYC webhook payload"},
+		{ReasonInvalidKYCSignature, CodeUnauthorized, "invalid webhook signature"},
+		{ReasonCampaignNotFound, CodeNotFound, "campaign not found"},
+		{ReasonNotCampaignOwner, CodeForbidden, "campaign belongs to another merchant"},
+		{ReasonInvalidMinQuantity, CodeInvalidArgument, "minimum quantity must be positive"},
+		{ReasonCampaignNotOpen, CodeConflict, "campaign is not accepting participations"},
+		{ReasonCampaignNotSettled, CodeConflict, "campaign cannot be settled in its current state"},
+		{ReasonSettlementTooEarly, CodeConflict, "campaign has not ended yet"},
+		{ReasonCampaignNotPaused, CodeConflict, "campaign is not paused"},
+		{ReasonCampaignNotPausable, CodeConflict, "campaign cannot be paused in its current state"},
+		{ReasonMetadataDisabled, CodeConflict, "metadata publishing is not configured"},
+		{ReasonCampaignTransition, CodeConflict, "campaign status cannot change from %s to %s"},
+		{ReasonVersionRequired, CodePreconditionRequired, "send the version you read in If-Match"},
+		{ReasonStaleVersion, CodePreconditionFailed, "it was changed by someone else; reload it and try again"},
+		{ReasonCancelTermsLocked, CodeConflict, "cancellation terms can only change while the campaign is a draft"},
+		{ReasonCampaignInReview, CodeConflict, "campaign is in review; move it back to draft to edit it"},
+		{ReasonCampaignNotInReview, CodeConflict, "campaign is not awaiting review"},
+		{ReasonCampaignNotApproved, CodeConflict, "only an approved campaign can be deployed"},
+		{ReasonChainAddressTaken, CodeConflict, "another campaign is deployed at this address"},
+		{ReasonTemplateNotFound, CodeNotFound, "campaign template not found"},
+		{ReasonTemplateNameTaken, CodeConflict, "a campaign template with this name already exists"},
+		{ReasonPolicyRMaxBps, CodeInvalidArgument, "rMaxBps must be at most %d for this merchant tier"},
+		{ReasonPolicyLockDuration, CodeInvalidArgument, "campaigns must run for at least %d hours for this merchant tier"},
+		{ReasonPolicyFeeRange, CodeConflict, "the merchant fee of %d bps is outside the %d-%d bps allowed for this merchant tier"},
+		{ReasonPolicyNotFound, CodeNotFound, "campaign policy not found"},
+		{ReasonParticipationMissing, CodeNotFound, "participation not found"},
+		{ReasonAlreadyParticipating, CodeConflict, "user already participates in this campaign"},
+		{ReasonInvalidDeposit, CodeInvalidArgument, "deposit must be a positive multiple of the base price"},
+		{ReasonNotCancellable, CodeConflict, "participation cannot be cancelled"},
+		{ReasonJoinInProgress, CodeConflict, "this participation is already being created; retry shortly"},
+		{ReasonCancelWindowClosed, CodeConflict, "the cancellation window of this campaign has closed"},
+		{ReasonInvalidCancelAmount, CodeInvalidArgument, "cancel amount must be a positive multiple of the base price, at most the deposit"},
+		{ReasonMediaDisabled, CodeConflict, "media uploads are not configured"},
+		{ReasonMediaType, CodeInvalidArgument, "images must be JPEG or PNG"},
+		{ReasonMediaTooLarge, CodeInvalidArgument, "images must be at most %d MB"},
+		{ReasonMediaNotFound, CodeNotFound, "upload not found"},
+		{ReasonMediaNotUploaded, CodeConflict, "the file has not been uploaded yet"},
+		{ReasonMediaUploadExpired, CodeConflict, "the upload expired; start a new one"},
+		{ReasonMediaNotReady, CodeInvalidArgument, "image must be a completed upload of a %s"},
+		{ReasonCategoryNotFound, CodeNotFound, "category not found"},
+		{ReasonCategoryExists, CodeConflict, "a category with this slug already exists"},
+		{ReasonCategoryInUse, CodeConflict, "the category still has campaigns"},
+		{ReasonInvalidTags, CodeInvalidArgument, "campaigns take at most %d tags of up to %d characters"},
+		{ReasonWatchlistFull, CodeConflict, "the watchlist is full"},
+		{ReasonNotInFulfillment, CodeConflict, "campaign is not in fulfillment"},
+		{ReasonInvalidProofHash, CodeInvalidArgument, "proofHash must be a 0x-prefixed SHA-256 hash"},
+		{ReasonFulfillmentNotFound, CodeNotFound, "fulfillment not found"},
+		{ReasonFulfillmentAnswered, CodeConflict, "the fulfillment was already confirmed or disputed"},
+		{ReasonConfirmWindowClosed, CodeConflict, "the confirmation window has closed"},
+		{ReasonFulfillmentPending, CodeConflict, "every participation must be fulfilled and accepted, and none disputed, before settlement"},
+		{ReasonDisputeNotFound, CodeNotFound, "dispute not found"},
+		{ReasonDisputeExists, CodeConflict, "the participation already has an open dispute"},
+		{ReasonNotDisputable, CodeConflict, "only settled participations can be disputed after settlement"},
+		{ReasonDisputeWindowClosed, CodeConflict, "the dispute window has closed"},
+		{ReasonDisputeClosed, CodeConflict, "the dispute was already resolved"},
+		{ReasonRefundNotPending, CodeConflict, "the dispute is not awaiting an on-chain refund"},
+		{ReasonInvalidRefund, CodeInvalidArgument, "refund must be positive and at most the deposit"},
+		{ReasonNoPaymentToRefund, CodeConflict, "the participation has no completed payment to refund"},
+		{ReasonVouchersDisabled, CodeConflict, "vouchers are not configured"},
+		{ReasonVoucherNotFound, CodeNotFound, "voucher not found"},
+		{ReasonVoucherRedeemed, CodeConflict, "the voucher was already redeemed"},
+		{ReasonVoucherVoid, CodeConflict, "the voucher is no longer valid"},
+		{ReasonPaymentNotFound, CodeNotFound, "payment not found"},
+		{ReasonInvalidAmount, CodeInvalidArgument, "amount must be positive"},
+		{ReasonStripeDisabled, CodeForbidden, "stripe payments are not enabled"},
+		{ReasonInvalidWebhook, CodeInvalidArgument, "invalid webhook payload"},
+		{ReasonWebhookSignature, CodeUnauthorized, "invalid webhook signature"},
+		{ReasonPaymentStatus, CodeInvalidArgument, "unsupported payment status %q"},
+		{ReasonMerchantNotFound, CodeNotFound, "merchant not found"},
+		{ReasonMerchantRegistered, CodeConflict, "merchant is already registered"},
+		{ReasonMerchantNotApproved, CodeForbidden, "merchant registration is not approved"},
+		{ReasonFeeNotAgreed, CodeInvalidArgument, "acceptedFeeBps must match the merchant fee of %d bps"},
+		{ReasonFeeOnlyOnApproval, CodeInvalidArgument, "feeBps can only be set when approving"},
+		{ReasonNotOwnMerchant, CodeForbidden, "merchants can only view their own dashboard"},
+		{ReasonCannotSuspendAdmin, CodeForbidden, "admins cannot be suspended"},
+		{ReasonCannotChangeOwnRole, CodeForbidden, "admins cannot change their own role"},
+		{ReasonUserNotSuspended, CodeConflict, "user is not suspended"},
+		{ReasonBulkSize, CodeInvalidArgument, "ids must contain between 1 and %d entries"},
+		{ReasonDeviceNotFound, CodeNotFound, "device not found"},
+		{ReasonInvalidPlatform, CodeInvalidArgument, "platform must be web, ios or android"},
+		{ReasonInvalidDeviceToken, CodeInvalidArgument, "invalid device token"},
+		{ReasonReferralCodeNotFound, CodeNotFound, "referral code not found"},
+		{ReasonSelfReferral, CodeForbidden, "you cannot use your own referral code"},
+		{ReasonAlreadyReferred, CodeConflict, "a referral code was already applied"},
+		{ReasonReferralClosed, CodeConflict, "referral codes can only be applied before your first participation"},
+		{ReasonRoleRequired, CodeForbidden, "%s role required"},
+		{ReasonServiceUnavailable, CodeUnavailable, "%s service is temporarily unavailable"},
+		{ReasonIdempotencyKeyLength, CodeInvalidArgument, "Idempotency-Key must be at most %d characters"},
+		{ReasonIdempotencyKeyInUse, CodeConflict, "a request with this Idempotency-Key is being processed"},
+		{ReasonIdempotencyKeyReused, CodeInvalidArgument, "Idempotency-Key was already used for a different request"},
+		{ReasonMaintenance, CodeUnavailable, "the service is under maintenance"},
+		{ReasonFeatureFrozen, CodeUnavailable, "this feature is temporarily disabled"},
+		{ReasonQuotaExceeded, CodeRateLimited, "%s quota exceeded"},
+		{ReasonQuotaNotFound, CodeNotFound, "quota not found"},
+	} {
+		if _, dup := catalog[e.Reason]; dup {
+			panic("errors: duplicate reason " + string(e.Reason))
+		}
+		catalog[e.Reason] = e
+	}
+}
+
+// Catalog returns the catalogued error for reason, its message formatted
+// with args. It panics on a reason missing from the catalog, which is a
+// programming error; sentinels declared with it fail at startup.
+func Catalog(reason Reason, args ...interface{}) *Error {
+	e, ok := catalog[reason]
+	if !ok {
+		panic("errors: reason " + string(reason) + " is not catalogued")
+	}
+	msg := e.Message
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return &Error{Code: e.Code, Reason: reason, Message: msg}
+}
+
+// Entries lists the catalog in reason order, for documentation
+func Entries() []CatalogEntry {
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, e := range catalog {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Reason < entries[j].Reason })
+	return entries
+}
@@ -0,0 +1,230 @@
+// Package errors provides coded application errors that map onto HTTP status
+// codes and gRPC codes, so every service reports failures the same way.
+//
+// The package is usually imported as apperrors to keep the standard library
+// errors package available.
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"google.golang.org/grpc/status"
+)
+
+// Code classifies an error independently of the transport
+type Code string
+
+const (
+	CodeInvalidArgument Code = "INVALID_ARGUMENT"
+	CodeValidation      Code = "VALIDATION_FAILED"
+	CodePayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	CodeUnauthorized    Code = "UNAUTHORIZED"
+	CodeForbidden       Code = "FORBIDDEN"
+	CodeNotFound        Code = "NOT_FOUND"
+	CodeConflict        Code = "CONFLICT"
+	// CodePreconditionFailed means the resource changed since the caller
+	// read it (a stale If-Match); CodePreconditionRequired that the caller
+	// must say which version it read
+	CodePreconditionFailed   Code = "PRECONDITION_FAILED"
+	CodePreconditionRequired Code = "PRECONDITION_REQUIRED"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeTimeout              Code = "TIMEOUT"
+	CodeChainUnavailable     Code = "CHAIN_UNAVAILABLE"
+	CodeUnavailable          Code = "UNAVAILABLE"
+	CodeUnimplemented        Code = "UNIMPLEMENTED"
+	CodeInternal             Code = "INTERNAL"
+)
+
+// internalMessage is returned to clients instead of the text of internal errors
+const internalMessage = "Internal server error"
+
+// Error is an error with a code and a message that is safe to show clients.
+// The wrapped cause is kept for logs but never exposed.
+type Error struct {
+	Code Code
+	// Reason is the catalogued failure, if any; see Catalog
+	Reason  Reason
+	Message string
+	Err     error
+	// Fields lists the invalid fields of a CodeValidation error
+	Fields []FieldError
+}
+
+// FieldError is one invalid field of a request
+type FieldError struct {
+	// Field is a path such as "body.items[2].id" or "query.limit"
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns an error with the given code and client message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf is New with a formatted message
+func Newf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap attaches a code and client message to err. A nil err returns nil.
+func Wrap(err error, code Code, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches another *Error with the same reason or, if the target has
+// none, the same code and (if the target has one) message. This lets
+// sentinel errors declared with New or Catalog be compared with errors.Is
+// after they have been wrapped or have crossed a service boundary.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Reason != "" {
+		return t.Reason == e.Reason
+	}
+	return t.Code == e.Code && (t.Message == "" || t.Message == e.Message)
+}
+
+// InvalidArgument returns a CodeInvalidArgument error
+func InvalidArgument(message string) *Error {
+	return New(CodeInvalidArgument, message)
+}
+
+// Unauthorized returns a CodeUnauthorized error
+func Unauthorized(message string) *Error {
+	return New(CodeUnauthorized, message)
+}
+
+// Forbidden returns a CodeForbidden error
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+// NotFound returns a CodeNotFound error
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+// Validation returns a CodeValidation error listing the invalid fields
+func Validation(fields ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: "Request validation failed", Fields: fields}
+}
+
+// PayloadTooLarge returns a CodePayloadTooLarge error for a body over limit
+// bytes
+func PayloadTooLarge(limit int64) *Error {
+	return Newf(CodePayloadTooLarge, "Request body must be at most %d bytes", limit)
+}
+
+// Conflict returns a CodeConflict error
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+// ChainUnavailable wraps a failed blockchain RPC call
+func ChainUnavailable(err error) error {
+	return Wrap(err, CodeChainUnavailable, "Blockchain node unavailable")
+}
+
+// Unavailable wraps a failed call to a downstream service
+func Unavailable(err error, message string) error {
+	return Wrap(err, CodeUnavailable, message)
+}
+
+// Internal wraps an unexpected failure; its text is never shown to clients
+func Internal(err error) error {
+	return Wrap(err, CodeInternal, internalMessage)
+}
+
+// CodeOf returns the code of the first *Error in err's chain. Context
+// deadlines map to CodeTimeout, gRPC status errors to the code the other
+// service sent or else the matching code, and anything else to
+// CodeInternal. A nil error has no code.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.Code
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return CodeTimeout
+	}
+	if s, ok := status.FromError(err); ok {
+		if info := errorInfo(s); info != nil && info.Metadata[metadataCode] != "" {
+			return Code(info.Metadata[metadataCode])
+		}
+		return fromGRPCCode(s.Code())
+	}
+	return CodeInternal
+}
+
+// ReasonOf returns the catalogued reason of err, including one sent by
+// another service over gRPC, or "" when it has none
+func ReasonOf(err error) Reason {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.Reason
+	}
+	if s, ok := status.FromError(err); ok {
+		if info := errorInfo(s); info != nil {
+			return Reason(info.Reason)
+		}
+	}
+	return ""
+}
+
+// FieldsOf returns the invalid fields of the first *Error in err's chain,
+// or of a gRPC status error
+func FieldsOf(err error) []FieldError {
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.Fields
+	}
+	if s, ok := status.FromError(err); ok {
+		return fieldViolations(s)
+	}
+	return nil
+}
+
+// MessageOf returns the client-safe message for err
+func MessageOf(err error) string {
+	var e *Error
+	if stderrors.As(err, &e) && e.Code != CodeInternal {
+		return e.Message
+	}
+
+	switch code := CodeOf(err); code {
+	case CodeInternal:
+		return internalMessage
+	case CodeTimeout:
+		return "Request timed out"
+	default:
+		// gRPC status from another service; its message was already
+		// produced by this package on the other side
+		if s, ok := status.FromError(err); ok && s.Message() != "" {
+			return s.Message()
+		}
+		return internalMessage
+	}
+}
@@ -0,0 +1,36 @@
+// Package ginerrors writes pkg/errors errors as gin responses. It lives in its
+// own package so services that do not use gin (query-server) do not depend
+// on it.
+package ginerrors
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+)
+
+// RespondOption adds to the response Respond writes for err, such as a header
+type RespondOption func(c *gin.Context, err error)
+
+// Respond writes err to c in the apperrors.Response format; the cause of
+// server-side failures is logged and attached to the context for error
+// reporting rather than returned. Options run before the body is written.
+func Respond(c *gin.Context, err error, opts ...RespondOption) {
+	status, body := apperrors.Response(err)
+	if status >= http.StatusInternalServerError {
+		ginlog.From(c).Error("request failed", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+		_ = c.Error(err)
+	}
+	for _, opt := range opts {
+		opt(c, err)
+	}
+	c.JSON(status, body)
+}
+
+// BadRequest writes an InvalidArgument error with msg to c
+func BadRequest(c *gin.Context, msg string) {
+	Respond(c, apperrors.InvalidArgument(msg))
+}
@@ -0,0 +1,140 @@
+package errors
+
+import (
+	stderrors "errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const (
+	// errorDomain is the ErrorInfo domain of errors sent by this package
+	errorDomain = "r2s"
+	// metadataCode carries the Code in ErrorInfo, as several codes share a
+	// gRPC code
+	metadataCode = "code"
+)
+
+var grpcCodes = map[Code]codes.Code{
+	CodeInvalidArgument:      codes.InvalidArgument,
+	CodeValidation:           codes.InvalidArgument,
+	CodePayloadTooLarge:      codes.InvalidArgument,
+	CodeUnauthorized:         codes.Unauthenticated,
+	CodeForbidden:            codes.PermissionDenied,
+	CodeNotFound:             codes.NotFound,
+	CodeConflict:             codes.FailedPrecondition,
+	CodePreconditionFailed:   codes.Aborted,
+	CodePreconditionRequired: codes.FailedPrecondition,
+	CodeRateLimited:          codes.ResourceExhausted,
+	CodeTimeout:              codes.DeadlineExceeded,
+	CodeChainUnavailable:     codes.Unavailable,
+	CodeUnavailable:          codes.Unavailable,
+	CodeUnimplemented:        codes.Unimplemented,
+	CodeInternal:             codes.Internal,
+}
+
+// GRPCStatus lets grpc-go send *Error with its mapped code. Only the client
+// message, code, reason and invalid fields cross the wire, the latter as
+// ErrorInfo and BadRequest details, so the caller's CodeOf, ReasonOf and
+// FieldsOf see what this side returned.
+func (e *Error) GRPCStatus() *status.Status {
+	code, ok := grpcCodes[e.Code]
+	if !ok {
+		code = codes.Internal
+	}
+	msg := e.Message
+	if e.Code == CodeInternal {
+		msg = internalMessage
+	}
+	s := status.New(code, msg)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   string(e.Reason),
+		Domain:   errorDomain,
+		Metadata: map[string]string{metadataCode: string(e.Code)},
+	}}
+	if len(e.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(e.Fields))
+		for i, f := range e.Fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message}
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if withDetails, err := s.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return s
+}
+
+// GRPCCode returns the gRPC code for err
+func GRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if c, ok := grpcCodes[CodeOf(err)]; ok {
+		return c
+	}
+	return codes.Internal
+}
+
+// ToGRPC converts err into a gRPC status error for returning from a handler
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e.GRPCStatus().Err()
+	}
+	return status.Error(GRPCCode(err), MessageOf(err))
+}
+
+// errorInfo returns the ErrorInfo this package attached to s
+func errorInfo(s *status.Status) *errdetails.ErrorInfo {
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == errorDomain {
+			return info
+		}
+	}
+	return nil
+}
+
+// fieldViolations returns the invalid fields attached to s
+func fieldViolations(s *status.Status) []FieldError {
+	var fields []FieldError
+	for _, d := range s.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, FieldError{Field: v.Field, Message: v.Description})
+			}
+		}
+	}
+	return fields
+}
+
+func fromGRPCCode(c codes.Code) Code {
+	switch c {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return CodeInvalidArgument
+	case codes.Unauthenticated:
+		return CodeUnauthorized
+	case codes.PermissionDenied:
+		return CodeForbidden
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		return CodeConflict
+	case codes.ResourceExhausted:
+		return CodeRateLimited
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+	case codes.Unavailable:
+		return CodeUnavailable
+	case codes.Unimplemented:
+		return CodeUnimplemented
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var httpStatus = map[Code]int{
+	CodeInvalidArgument:      http.StatusBadRequest,
+	CodeValidation:           http.StatusUnprocessableEntity,
+	CodePayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	CodeUnauthorized:         http.StatusUnauthorized,
+	CodeForbidden:            http.StatusForbidden,
+	CodeNotFound:             http.StatusNotFound,
+	CodeConflict:             http.StatusConflict,
+	CodePreconditionFailed:   http.StatusPreconditionFailed,
+	CodePreconditionRequired: http.StatusPreconditionRequired,
+	CodeRateLimited:          http.StatusTooManyRequests,
+	CodeTimeout:              http.StatusGatewayTimeout,
+	CodeChainUnavailable:     http.StatusServiceUnavailable,
+	CodeUnavailable:          http.StatusServiceUnavailable,
+	CodeUnimplemented:        http.StatusNotImplemented,
+	CodeInternal:             http.StatusInternalServerError,
+}
+
+// HTTPStatus returns the HTTP status code for err
+func HTTPStatus(err error) int {
+	if s, ok := httpStatus[CodeOf(err)]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Response returns the status and JSON body for err in the format every
+// handler uses, so it can be passed straight to gin: c.JSON(Response(err)).
+// Catalogued errors carry their reason under "reason", and validation
+// errors list their fields under "details".
+func Response(err error) (int, map[string]interface{}) {
+	body := map[string]interface{}{
+		"success": false,
+		"error":   MessageOf(err),
+		"code":    CodeOf(err),
+	}
+	if reason := ReasonOf(err); reason != "" {
+		body["reason"] = reason
+	}
+	if fields := FieldsOf(err); len(fields) > 0 {
+		body["details"] = fields
+	}
+	return HTTPStatus(err), body
+}
+
+// FromResponse rebuilds the error of a body in the Response format, as
+// answered by another service, so its code and reason survive being passed
+// on. It returns nil for any other body.
+func FromResponse(body []byte) *Error {
+	var payload struct {
+		Error   string       `json:"error"`
+		Code    Code         `json:"code"`
+		Reason  Reason       `json:"reason"`
+		Details []FieldError `json:"details"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Code == "" {
+		return nil
+	}
+	return &Error{Code: payload.Code, Reason: payload.Reason, Message: payload.Error, Fields: payload.Details}
+}
@@ -0,0 +1,125 @@
+// Package errreport recovers panics and reports them, and other unexpected
+// errors, to Sentry (or any Sentry-compatible service such as GlitchTip)
+// with the request id, trace id and stack trace attached. Without a DSN
+// nothing is sent, but panics are still recovered and logged with their
+// stack.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// FlushTimeout bounds how long Flush waits for queued events on shutdown
+const FlushTimeout = 2 * time.Second
+
+// Config is loadable with pkg/config
+type Config struct {
+	DSN         string  `env:"SENTRY_DSN" secret:"true"`
+	Environment string  `env:"APP_ENV" default:"development"`
+	Release     string  `env:"APP_RELEASE"`
+	SampleRate  float64 `env:"SENTRY_SAMPLE_RATE" default:"1"`
+}
+
+// Validate rejects sample rates outside [0, 1]
+func (c *Config) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("SENTRY_SAMPLE_RATE must be between 0 and 1, got %v", c.SampleRate)
+	}
+	return nil
+}
+
+// Init configures the Sentry client for service. Call Flush before the
+// process exits so queued events are not lost.
+func Init(service string, cfg Config) error {
+	if cfg.DSN == "" {
+		return nil
+	}
+	err := sentry.Init(sentry.ClientOptions{
+		Dsn:              cfg.DSN,
+		Environment:      cfg.Environment,
+		Release:          cfg.Release,
+		SampleRate:       cfg.SampleRate,
+		ServerName:       service,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize Sentry: %w", err)
+	}
+	sentry.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag(logger.KeyService, service)
+	})
+	return nil
+}
+
+// Flush waits up to FlushTimeout for queued events to be sent
+func Flush() {
+	sentry.Flush(FlushTimeout)
+}
+
+// Hub returns a Sentry hub for one request or job, tagged with the request
+// and trace ids found in ctx
+func Hub(ctx context.Context) *sentry.Hub {
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		if id := logger.RequestID(ctx); id != "" {
+			scope.SetTag(logger.KeyRequestID, id)
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			scope.SetTag(logger.KeyTraceID, sc.TraceID().String())
+		}
+	})
+	return hub
+}
+
+// Report sends an unexpected error. Expected failures (not found, invalid
+// input, ...) should not be reported.
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	Hub(ctx).CaptureException(err)
+}
+
+// ReportPanic logs a recovered panic with its stack and reports it. where
+// names the handler, RPC or job that panicked.
+func ReportPanic(ctx context.Context, hub *sentry.Hub, where string, recovered interface{}) {
+	logger.FromContext(ctx).Error("panic recovered",
+		"where", where,
+		"panic", fmt.Sprint(recovered),
+		"stack", string(debug.Stack()),
+	)
+	if hub == nil {
+		hub = Hub(ctx)
+	}
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag("where", where)
+	})
+	hub.RecoverWithContext(ctx, recovered)
+}
+
+// Recover reports a panic in the calling goroutine and lets it continue.
+// Use it as the first deferred call of background work:
+//
+//	defer errreport.Recover(ctx, "settlement")
+func Recover(ctx context.Context, where string) {
+	if r := recover(); r != nil {
+		ReportPanic(ctx, nil, where, r)
+	}
+}
+
+// Go runs fn in a new goroutine whose panics are reported instead of
+// crashing the process
+func Go(ctx context.Context, where string, fn func(ctx context.Context)) {
+	go func() {
+		defer Recover(ctx, where)
+		fn(ctx)
+	}()
+}
@@ -0,0 +1,52 @@
+// Package ginreport adapts pkg/errreport to gin. It lives in its own package
+// so services that do not use gin (query-server) do not depend on it.
+package ginreport
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+)
+
+// Middleware replaces gin.Recovery. A panicking handler gets the usual 500
+// error body, and the panic is logged with its stack and reported together
+// with the request. Errors attached with c.Error on a 500 response are
+// reported too. Register it after ginlog.Middleware and gintrace.Middleware
+// so reports carry the request and trace ids.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		hub := errreport.Hub(ctx)
+		hub.ConfigureScope(func(scope *sentry.Scope) {
+			scope.SetRequest(c.Request)
+		})
+
+		defer func() {
+			if r := recover(); r != nil {
+				hub.ConfigureScope(func(scope *sentry.Scope) {
+					scope.SetTag("route", c.FullPath())
+				})
+				errreport.ReportPanic(ctx, hub, c.Request.Method+" "+c.FullPath(), r)
+				if !c.Writer.Written() {
+					c.AbortWithStatusJSON(apperrors.Response(apperrors.Internal(fmt.Errorf("panic: %v", r))))
+				} else {
+					c.Abort()
+				}
+			}
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() == http.StatusInternalServerError && len(c.Errors) > 0 {
+			hub.ConfigureScope(func(scope *sentry.Scope) {
+				scope.SetTag("route", c.FullPath())
+			})
+			hub.CaptureException(c.Errors.Last().Err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package errreport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// UnaryServerInterceptor turns a panicking RPC into codes.Internal and
+// reports it, along with any other Internal or Unknown error the handler
+// returns. Chain it after the logging and tracing interceptors so the report
+// carries their request and trace ids.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				ReportPanic(ctx, nil, info.FullMethod, r)
+				resp, err = nil, apperrors.ToGRPC(apperrors.Internal(fmt.Errorf("panic: %v", r)))
+			}
+		}()
+
+		resp, err = handler(ctx, req)
+		switch status.Code(err) {
+		case codes.Internal, codes.Unknown:
+			Report(ctx, err)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := ss.Context()
+		defer func() {
+			if r := recover(); r != nil {
+				ReportPanic(ctx, nil, info.FullMethod, r)
+				err = apperrors.ToGRPC(apperrors.Internal(fmt.Errorf("panic: %v", r)))
+			}
+		}()
+
+		err = handler(srv, ss)
+		switch status.Code(err) {
+		case codes.Internal, codes.Unknown:
+			Report(ctx, err)
+		}
+		return err
+	}
+}
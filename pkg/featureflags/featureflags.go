@@ -0,0 +1,296 @@
+// Package featureflags turns features on per environment, per user or for a
+// percentage of users without a redeploy. Defaults come from FEATURE_*
+// environment variables; flags written to Redis override them and are picked
+// up by every instance within the refresh interval.
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// Known flags
+const (
+	GaslessTransactions = "gasless_tx"
+	StripePayments      = "stripe_payments"
+	Waitlist            = "waitlist"
+
+	// Maintenance takes the user API offline; the admin API stays up
+	Maintenance = "maintenance"
+	// Freeze flags disable route groups during an incident
+	FreezeParticipations = "freeze_participations"
+	FreezePayments       = "freeze_payments"
+	FreezeCampaigns      = "freeze_campaigns"
+)
+
+// Known lists the flags reported by All
+var Known = []string{
+	GaslessTransactions, StripePayments, Waitlist,
+	Maintenance, FreezeParticipations, FreezePayments, FreezeCampaigns,
+}
+
+// RedisKey is the hash holding flag overrides, one JSON Flag per field
+const RedisKey = "r2s:featureflags"
+
+// DefaultRefresh is how long an instance trusts its copy of the Redis flags
+const DefaultRefresh = 30 * time.Second
+
+// Flag is the state of one feature
+type Flag struct {
+	// Enabled turns the feature on for everyone
+	Enabled bool `json:"enabled"`
+	// Rollout turns it on for this percentage (0-100) of users
+	Rollout int `json:"rollout"`
+	// Users always get the feature
+	Users []string `json:"users,omitempty"`
+	// Message is shown to users while the flag takes something offline
+	Message string `json:"message,omitempty"`
+}
+
+// Validate checks the rollout bounds
+func (f Flag) Validate() error {
+	if f.Rollout < 0 || f.Rollout > 100 {
+		return apperrors.InvalidArgument("rollout must be between 0 and 100")
+	}
+	return nil
+}
+
+// EnabledFor reports whether the flag name is on for userID. Anonymous
+// callers (empty userID) only see features enabled for everyone.
+func (f Flag) EnabledFor(name, userID string) bool {
+	if f.Enabled || f.Rollout >= 100 {
+		return true
+	}
+	if userID == "" {
+		return false
+	}
+	for _, u := range f.Users {
+		if u == userID {
+			return true
+		}
+	}
+	return f.Rollout > 0 && bucket(name, userID) < f.Rollout
+}
+
+// bucket places userID in 0-99, independently per flag so the same users
+// are not always first in every rollout
+func bucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// Flags evaluates flags from the environment and Redis. It is safe for
+// concurrent use.
+type Flags struct {
+	client  redis.UniversalClient
+	lookup  func(string) (string, bool)
+	refresh time.Duration
+	clock   clock.Clock
+
+	mu       sync.RWMutex
+	remote   map[string]Flag
+	loadedAt time.Time
+}
+
+// Option customises New
+type Option func(*Flags)
+
+// WithRefresh sets how often Redis is re-read
+func WithRefresh(d time.Duration) Option {
+	return func(f *Flags) { f.refresh = d }
+}
+
+// WithLookup replaces os.LookupEnv for the FEATURE_* defaults
+func WithLookup(lookup func(string) (string, bool)) Option {
+	return func(f *Flags) { f.lookup = lookup }
+}
+
+// WithClock sets the clock used for the refresh interval
+func WithClock(c clock.Clock) Option {
+	return func(f *Flags) { f.clock = clock.OrSystem(c) }
+}
+
+// New returns flags backed by client. A nil client uses the environment only.
+func New(client redis.UniversalClient, opts ...Option) *Flags {
+	f := &Flags{
+		client:  client,
+		lookup:  os.LookupEnv,
+		refresh: DefaultRefresh,
+		clock:   clock.System,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Enabled reports whether name is on for userID
+func (f *Flags) Enabled(ctx context.Context, name, userID string) bool {
+	return f.Get(ctx, name).EnabledFor(name, userID)
+}
+
+// All evaluates every Known flag for userID
+func (f *Flags) All(ctx context.Context, userID string) map[string]bool {
+	out := make(map[string]bool, len(Known))
+	for _, name := range Known {
+		out[name] = f.Enabled(ctx, name, userID)
+	}
+	return out
+}
+
+// List returns every Known flag and every override, as Get sees them
+func (f *Flags) List(ctx context.Context) map[string]Flag {
+	out := make(map[string]Flag, len(Known))
+	for _, name := range Known {
+		out[name] = f.Get(ctx, name)
+	}
+	for name, flag := range f.overrides(ctx) {
+		out[name] = flag
+	}
+	return out
+}
+
+// Get returns the Redis override for name, or its environment default
+func (f *Flags) Get(ctx context.Context, name string) Flag {
+	if flag, ok := f.remoteFlag(ctx, name); ok {
+		return flag
+	}
+	return f.envFlag(name)
+}
+
+// Set stores an override for name in Redis. Other instances see it after
+// their next refresh.
+func (f *Flags) Set(ctx context.Context, name string, flag Flag) error {
+	if f.client == nil {
+		return apperrors.Unavailable(errors.New("no redis client"), "feature flags are read-only")
+	}
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("failed to encode flag %s: %w", name, err)
+	}
+	if err := f.client.HSet(ctx, RedisKey, name, raw).Err(); err != nil {
+		return fmt.Errorf("failed to store flag %s: %w", name, err)
+	}
+	f.invalidate()
+	return nil
+}
+
+// Delete removes the Redis override for name so the environment default
+// applies again
+func (f *Flags) Delete(ctx context.Context, name string) error {
+	if f.client == nil {
+		return apperrors.Unavailable(errors.New("no redis client"), "feature flags are read-only")
+	}
+	if err := f.client.HDel(ctx, RedisKey, name).Err(); err != nil {
+		return fmt.Errorf("failed to delete flag %s: %w", name, err)
+	}
+	f.invalidate()
+	return nil
+}
+
+func (f *Flags) invalidate() {
+	f.mu.Lock()
+	f.loadedAt = time.Time{}
+	f.mu.Unlock()
+}
+
+func (f *Flags) remoteFlag(ctx context.Context, name string) (Flag, bool) {
+	flag, ok := f.overrides(ctx)[name]
+	return flag, ok
+}
+
+// overrides returns the Redis flags, re-read once the copy is older than
+// the refresh interval. The map is replaced, never modified, by load.
+func (f *Flags) overrides(ctx context.Context) map[string]Flag {
+	if f.client == nil {
+		return nil
+	}
+
+	f.mu.RLock()
+	fresh := !f.loadedAt.IsZero() && f.clock.Since(f.loadedAt) < f.refresh
+	remote := f.remote
+	f.mu.RUnlock()
+	if fresh {
+		return remote
+	}
+
+	if err := f.load(ctx); err != nil {
+		// Keep serving the last copy; Redis being down must not flip flags.
+		// It is retried after the refresh interval, not on every call.
+		logger.FromContext(ctx).Warn("failed to refresh feature flags", "error", err)
+		f.mu.Lock()
+		f.loadedAt = f.clock.Now()
+		f.mu.Unlock()
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.remote
+}
+
+func (f *Flags) load(ctx context.Context) error {
+	raw, err := f.client.HGetAll(ctx, RedisKey).Result()
+	if err != nil {
+		return err
+	}
+
+	remote := make(map[string]Flag, len(raw))
+	for name, v := range raw {
+		var flag Flag
+		if err := json.Unmarshal([]byte(v), &flag); err != nil {
+			logger.FromContext(ctx).Warn("ignoring malformed feature flag", "flag", name, "error", err)
+			continue
+		}
+		remote[name] = flag
+	}
+
+	f.mu.Lock()
+	f.remote = remote
+	f.loadedAt = f.clock.Now()
+	f.mu.Unlock()
+	return nil
+}
+
+// envFlag reads FEATURE_<NAME> ("true", "false" or a rollout such as "25%")
+// and FEATURE_<NAME>_USERS (comma separated user ids)
+func (f *Flags) envFlag(name string) Flag {
+	key := "FEATURE_" + strings.ToUpper(name)
+
+	var flag Flag
+	if v, ok := f.lookup(key); ok {
+		v = strings.TrimSpace(v)
+		if pct, isPct := strings.CutSuffix(v, "%"); isPct {
+			if n, err := strconv.Atoi(pct); err == nil && n >= 0 && n <= 100 {
+				flag.Rollout = n
+			}
+		} else if b, err := strconv.ParseBool(v); err == nil {
+			flag.Enabled = b
+		}
+	}
+	if v, ok := f.lookup(key + "_USERS"); ok {
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				flag.Users = append(flag.Users, u)
+			}
+		}
+	}
+	return flag
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GRPCServer serves the standard grpc.health.v1 Check from a Checker, so
+// gRPC-only services (query-server) answer grpc_health_probe and gRPC
+// clients the same way their /ready does
+type GRPCServer struct {
+	healthpb.UnimplementedHealthServer
+	checker *Checker
+}
+
+// NewGRPCServer returns a health service backed by checker
+func NewGRPCServer(checker *Checker) *GRPCServer {
+	return &GRPCServer{checker: checker}
+}
+
+// Check reports NOT_SERVING while a critical check fails. The service name
+// in the request is ignored: readiness is per process.
+func (s *GRPCServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if s.checker.Run(ctx).Status == StatusFail {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return &healthpb.HealthCheckResponse{Status: status}, nil
+}
+
+// GRPC expects SERVING from the grpc.health.v1 service behind conn
+func GRPC(conn grpc.ClientConnInterface) CheckFunc {
+	client := healthpb.NewHealthClient(conn)
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("upstream is %s", resp.GetStatus())
+		}
+		return nil
+	}
+}
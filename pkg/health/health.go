@@ -0,0 +1,213 @@
+// Package health implements liveness and readiness probes. Liveness only
+// says the process is up and serving; readiness runs the registered
+// dependency checks (database, Redis, chain RPC, upstream services) and
+// fails when a critical one does. Results are cached briefly so frequent
+// probes and load balancer checks do not hammer the dependencies.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+// Defaults for NewChecker
+const (
+	DefaultTTL     = 2 * time.Second
+	DefaultTimeout = 3 * time.Second
+)
+
+// Check statuses
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+// CheckFunc reports whether a dependency is usable
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of one check
+type Result struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// Report is the outcome of a readiness run
+type Report struct {
+	Status    string            `json:"status"`
+	Service   string            `json:"service"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checkedAt"`
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	critical bool
+}
+
+// Checker runs the readiness checks of one service
+type Checker struct {
+	service string
+	ttl     time.Duration
+	timeout time.Duration
+	clock   clock.Clock
+
+	mu     sync.Mutex
+	checks []check
+	last   *Report
+}
+
+// Option customises NewChecker
+type Option func(*Checker)
+
+// WithTTL sets how long a report is reused; zero runs the checks on every
+// probe
+func WithTTL(d time.Duration) Option {
+	return func(c *Checker) { c.ttl = d }
+}
+
+// WithTimeout bounds each check
+func WithTimeout(d time.Duration) Option {
+	return func(c *Checker) { c.timeout = d }
+}
+
+// WithClock sets the clock used for caching and timestamps
+func WithClock(clk clock.Clock) Option {
+	return func(c *Checker) { c.clock = clock.OrSystem(clk) }
+}
+
+// NewChecker returns a checker with no checks
+func NewChecker(service string, opts ...Option) *Checker {
+	c := &Checker{
+		service: service,
+		ttl:     DefaultTTL,
+		timeout: DefaultTimeout,
+		clock:   clock.System,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Add registers a critical check: readiness fails while it fails
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.add(name, fn, true)
+}
+
+// AddOptional registers a check that is reported but only degrades
+// readiness, e.g. an upstream the service can work without
+func (c *Checker) AddOptional(name string, fn CheckFunc) {
+	c.add(name, fn, false)
+}
+
+func (c *Checker) add(name string, fn CheckFunc, critical bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn, critical: critical})
+	c.last = nil
+}
+
+// Run returns the cached report if it is fresh, otherwise runs every check
+// concurrently
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	if c.last != nil && now.Sub(c.last.CheckedAt) < c.ttl {
+		return *c.last
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = c.runOne(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:    StatusOK,
+		Service:   c.service,
+		Checks:    make(map[string]Result, len(c.checks)),
+		CheckedAt: now,
+	}
+	for i, chk := range c.checks {
+		r := results[i]
+		report.Checks[chk.name] = r
+		if r.Status == StatusOK {
+			continue
+		}
+		if chk.critical {
+			report.Status = StatusFail
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	c.last = &report
+	return report
+}
+
+func (c *Checker) runOne(ctx context.Context, chk check) (res Result) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	res = Result{Status: StatusOK, Critical: chk.critical}
+	defer func() {
+		if r := recover(); r != nil {
+			res.Status, res.Error = StatusFail, fmt.Sprintf("check panicked: %v", r)
+		}
+		res.LatencyMs = time.Since(start).Milliseconds()
+	}()
+
+	if err := chk.fn(ctx); err != nil {
+		res.Status, res.Error = StatusFail, err.Error()
+	}
+	return res
+}
+
+// LiveHandler answers 200 as long as the process can serve requests. It
+// never touches dependencies, so an outage does not get the process killed.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"status":    StatusOK,
+			"service":   c.service,
+			"timestamp": c.clock.Now().Format(time.RFC3339),
+		})
+	})
+}
+
+// ReadyHandler answers 200 when every critical check passes (status ok or
+// degraded) and 503 otherwise, with the per-check results in both cases
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+		status := http.StatusOK
+		if report.Status == StatusFail {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,107 @@
+// Package i18n localizes client-facing messages. The catalog is keyed by the
+// English message (for messages clients commonly see) and by error code (as
+// a fallback), and the language is negotiated from Accept-Language.
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// Lang is a supported language tag
+type Lang string
+
+const (
+	English  Lang = "en"
+	Korean   Lang = "ko"
+	Japanese Lang = "ja"
+)
+
+// Default is used when the client accepts none of Supported
+const Default = English
+
+// Supported lists the languages the catalog covers
+var Supported = []Lang{English, Korean, Japanese}
+
+// Negotiate picks the best supported language from an Accept-Language
+// header, e.g. "ja-JP,ja;q=0.9,en;q=0.8" -> Japanese
+func Negotiate(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+		pos  int
+	}
+
+	var candidates []candidate
+	for i, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		if lang, ok := match(tag); ok {
+			candidates = append(candidates, candidate{lang, q, i})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// match maps a language tag such as "ko-KR" onto a supported language
+func match(tag string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	for _, l := range Supported {
+		if string(l) == primary {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Localize returns message in lang. The message itself is looked up first
+// (case-insensitively), then the generic text for code. English, and
+// messages with no translation at all, are returned unchanged.
+func Localize(lang Lang, code apperrors.Code, message string) string {
+	if lang == English || lang == "" {
+		return message
+	}
+	if t, ok := messages[strings.ToLower(message)][lang]; ok {
+		return t
+	}
+	if t, ok := codes[code][lang]; ok {
+		return t
+	}
+	return message
+}
+
+type langKey struct{}
+
+// WithLang stores the negotiated language in ctx
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// FromContext returns the language stored by WithLang, or Default
+func FromContext(ctx context.Context) Lang {
+	if lang, ok := ctx.Value(langKey{}).(Lang); ok {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,382 @@
+package i18n
+
+import apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+
+// codes is the generic text for each error code, used when the specific
+// message has no translation
+var codes = map[apperrors.Code]map[Lang]string{
+	apperrors.CodeInvalidArgument: {
+		Korean:   "잘못된 요청입니다",
+		Japanese: "リクエストが正しくありません",
+	},
+	apperrors.CodeValidation: {
+		Korean:   "요청 값이 올바르지 않습니다",
+		Japanese: "リクエストの値が正しくありません",
+	},
+	apperrors.CodePayloadTooLarge: {
+		Korean:   "요청 본문이 너무 큽니다",
+		Japanese: "リクエストボディが大きすぎます",
+	},
+	apperrors.CodeUnauthorized: {
+		Korean:   "인증이 필요합니다",
+		Japanese: "認証が必要です",
+	},
+	apperrors.CodeForbidden: {
+		Korean:   "권한이 없습니다",
+		Japanese: "権限がありません",
+	},
+	apperrors.CodeNotFound: {
+		Korean:   "찾을 수 없습니다",
+		Japanese: "見つかりません",
+	},
+	apperrors.CodeConflict: {
+		Korean:   "현재 상태에서는 요청을 처리할 수 없습니다",
+		Japanese: "現在の状態ではリクエストを処理できません",
+	},
+	apperrors.CodePreconditionFailed: {
+		Korean:   "다른 곳에서 먼저 변경되었습니다. 새로 불러온 뒤 다시 시도해 주세요",
+		Japanese: "他の場所で先に変更されました。再読み込みしてからもう一度お試しください",
+	},
+	apperrors.CodePreconditionRequired: {
+		Korean:   "변경하려면 조회한 버전이 필요합니다",
+		Japanese: "変更するには取得したバージョンが必要です",
+	},
+	apperrors.CodeRateLimited: {
+		Korean:   "요청이 너무 많습니다. 잠시 후 다시 시도해 주세요",
+		Japanese: "リクエストが多すぎます。しばらくしてから再度お試しください",
+	},
+	apperrors.CodeTimeout: {
+		Korean:   "요청 시간이 초과되었습니다",
+		Japanese: "リクエストがタイムアウトしました",
+	},
+	apperrors.CodeChainUnavailable: {
+		Korean:   "블록체인 네트워크를 일시적으로 사용할 수 없습니다",
+		Japanese: "ブロックチェーンネットワークが一時的に利用できません",
+	},
+	apperrors.CodeUnavailable: {
+		Korean:   "서비스를 일시적으로 사용할 수 없습니다",
+		Japanese: "サービスが一時的に利用できません",
+	},
+	apperrors.CodeUnimplemented: {
+		Korean:   "지원하지 않는 기능입니다",
+		Japanese: "サポートされていない機能です",
+	},
+	apperrors.CodeInternal: {
+		Korean:   "서버 내부 오류가 발생했습니다",
+		Japanese: "サーバー内部エラーが発生しました",
+	},
+}
+
+// messages translates specific messages. Keys are the lower-cased English
+// text returned by the services.
+var messages = map[string]map[Lang]string{
+	// Authentication
+	"authorization header required": {
+		Korean:   "로그인이 필요합니다",
+		Japanese: "ログインが必要です",
+	},
+	"token required": {
+		Korean:   "로그인이 필요합니다",
+		Japanese: "ログインが必要です",
+	},
+	"invalid token": {
+		Korean:   "유효하지 않은 토큰입니다",
+		Japanese: "無効なトークンです",
+	},
+	"token validation failed": {
+		Korean:   "토큰 검증에 실패했습니다",
+		Japanese: "トークンの検証に失敗しました",
+	},
+	"token has been revoked": {
+		Korean:   "만료된 로그인입니다. 다시 로그인해 주세요",
+		Japanese: "ログインが無効になりました。再度ログインしてください",
+	},
+	"invalid session": {
+		Korean:   "유효하지 않은 세션입니다",
+		Japanese: "無効なセッションです",
+	},
+	"session expired": {
+		Korean:   "세션이 만료되었습니다. 다시 로그인해 주세요",
+		Japanese: "セッションの有効期限が切れました。再度ログインしてください",
+	},
+	"invalid refresh token": {
+		Korean:   "유효하지 않은 리프레시 토큰입니다",
+		Japanese: "無効なリフレッシュトークンです",
+	},
+	"nonce expired": {
+		Korean:   "서명 요청이 만료되었습니다. 다시 시도해 주세요",
+		Japanese: "署名リクエストの有効期限が切れました。もう一度お試しください",
+	},
+	"invalid or expired nonce": {
+		Korean:   "서명 요청이 유효하지 않거나 만료되었습니다",
+		Japanese: "署名リクエストが無効か、有効期限が切れています",
+	},
+	"invalid signature": {
+		Korean:   "서명이 올바르지 않습니다",
+		Japanese: "署名が正しくありません",
+	},
+	"address mismatch": {
+		Korean:   "서명한 지갑 주소가 일치하지 않습니다",
+		Japanese: "署名したウォレットアドレスが一致しません",
+	},
+	"invalid wallet address": {
+		Korean:   "지갑 주소가 올바르지 않습니다",
+		Japanese: "ウォレットアドレスが正しくありません",
+	},
+	"user not found": {
+		Korean:   "사용자를 찾을 수 없습니다",
+		Japanese: "ユーザーが見つかりません",
+	},
+
+	// Campaigns and participations
+	"campaign not found": {
+		Korean:   "캠페인을 찾을 수 없습니다",
+		Japanese: "キャンペーンが見つかりません",
+	},
+	"merchant not found": {
+		Korean:   "판매자를 찾을 수 없습니다",
+		Japanese: "販売者が見つかりません",
+	},
+	"merchants can only view their own dashboard": {
+		Korean:   "본인 판매자 대시보드만 볼 수 있습니다",
+		Japanese: "自分の販売者ダッシュボードのみ閲覧できます",
+	},
+	"participation not found": {
+		Korean:   "참여 내역을 찾을 수 없습니다",
+		Japanese: "参加履歴が見つかりません",
+	},
+	"campaign is not accepting participations": {
+		Korean:   "현재 참여할 수 없는 캠페인입니다",
+		Japanese: "このキャンペーンには現在参加できません",
+	},
+	"user already participates in this campaign": {
+		Korean:   "이미 참여 중인 캠페인입니다",
+		Japanese: "すでに参加しているキャンペーンです",
+	},
+	"deposit must be a positive multiple of the base price": {
+		Korean:   "예치 금액은 기본 가격의 배수여야 합니다",
+		Japanese: "デポジット額は基本価格の倍数である必要があります",
+	},
+	"participation cannot be cancelled": {
+		Korean:   "참여를 취소할 수 없습니다",
+		Japanese: "参加をキャンセルできません",
+	},
+	"this participation is already being created; retry shortly": {
+		Korean:   "참여 요청을 처리하고 있습니다. 잠시 후 다시 시도해 주세요",
+		Japanese: "参加リクエストを処理中です。しばらくしてから再度お試しください",
+	},
+	"the cancellation window of this campaign has closed": {
+		Korean:   "이 캠페인의 취소 가능 기간이 끝났습니다",
+		Japanese: "このキャンペーンのキャンセル期間は終了しました",
+	},
+	"cancel amount must be a positive multiple of the base price, at most the deposit": {
+		Korean:   "취소 금액은 기본 가격의 배수이며 예치금 이하여야 합니다",
+		Japanese: "キャンセル金額は基本価格の倍数で、預け入れ額以下である必要があります",
+	},
+	"cancellation terms can only change while the campaign is a draft": {
+		Korean:   "취소 조건은 캠페인이 초안 상태일 때만 변경할 수 있습니다",
+		Japanese: "キャンセル条件はキャンペーンが下書きの間のみ変更できます",
+	},
+	"campaign is in review; move it back to draft to edit it": {
+		Korean:   "검토 중인 캠페인입니다. 수정하려면 초안으로 되돌려 주세요",
+		Japanese: "キャンペーンは審査中です。編集するには下書きに戻してください",
+	},
+	"campaign is not awaiting review": {
+		Korean:   "검토 대기 중인 캠페인이 아닙니다",
+		Japanese: "審査待ちのキャンペーンではありません",
+	},
+	"only an approved campaign can be deployed": {
+		Korean:   "승인된 캠페인만 배포할 수 있습니다",
+		Japanese: "承認されたキャンペーンのみデプロイできます",
+	},
+	"another campaign is deployed at this address": {
+		Korean:   "이 주소에 이미 다른 캠페인이 배포되어 있습니다",
+		Japanese: "このアドレスには別のキャンペーンがデプロイされています",
+	},
+	"campaign template not found": {
+		Korean:   "캠페인 템플릿을 찾을 수 없습니다",
+		Japanese: "キャンペーンテンプレートが見つかりません",
+	},
+	"a campaign template with this name already exists": {
+		Korean:   "같은 이름의 캠페인 템플릿이 이미 있습니다",
+		Japanese: "同じ名前のキャンペーンテンプレートが既に存在します",
+	},
+	"campaign policy not found": {
+		Korean:   "캠페인 정책을 찾을 수 없습니다",
+		Japanese: "キャンペーンポリシーが見つかりません",
+	},
+	"media uploads are not configured": {
+		Korean:   "미디어 업로드가 설정되어 있지 않습니다",
+		Japanese: "メディアのアップロードが設定されていません",
+	},
+	"images must be jpeg or png": {
+		Korean:   "이미지는 JPEG 또는 PNG여야 합니다",
+		Japanese: "画像はJPEGまたはPNGである必要があります",
+	},
+	"upload not found": {
+		Korean:   "업로드를 찾을 수 없습니다",
+		Japanese: "アップロードが見つかりません",
+	},
+	"the file has not been uploaded yet": {
+		Korean:   "파일이 아직 업로드되지 않았습니다",
+		Japanese: "ファイルはまだアップロードされていません",
+	},
+	"the upload expired; start a new one": {
+		Korean:   "업로드가 만료되었습니다. 새로 시작해 주세요",
+		Japanese: "アップロードの有効期限が切れました。新しく開始してください",
+	},
+	"category not found": {
+		Korean:   "카테고리를 찾을 수 없습니다",
+		Japanese: "カテゴリが見つかりません",
+	},
+	"a category with this slug already exists": {
+		Korean:   "같은 슬러그의 카테고리가 이미 있습니다",
+		Japanese: "同じスラッグのカテゴリが既に存在します",
+	},
+	"the category still has campaigns": {
+		Korean:   "이 카테고리에 아직 캠페인이 있습니다",
+		Japanese: "このカテゴリにはまだキャンペーンがあります",
+	},
+	"the watchlist is full": {
+		Korean:   "관심 목록이 가득 찼습니다",
+		Japanese: "ウォッチリストがいっぱいです",
+	},
+	"campaign is not in fulfillment": {
+		Korean:   "캠페인이 이행 단계가 아닙니다",
+		Japanese: "キャンペーンは履行段階ではありません",
+	},
+	"proofHash must be a 0x-prefixed SHA-256 hash": {
+		Korean:   "proofHash는 0x로 시작하는 SHA-256 해시여야 합니다",
+		Japanese: "proofHashは0xで始まるSHA-256ハッシュである必要があります",
+	},
+	"fulfillment not found": {
+		Korean:   "이행 내역을 찾을 수 없습니다",
+		Japanese: "履行記録が見つかりません",
+	},
+	"the fulfillment was already confirmed or disputed": {
+		Korean:   "이미 확인되었거나 이의가 제기된 이행입니다",
+		Japanese: "この履行は既に確認済みまたは異議申し立て済みです",
+	},
+	"the confirmation window has closed": {
+		Korean:   "확인 기간이 종료되었습니다",
+		Japanese: "確認期間は終了しました",
+	},
+	"every participation must be fulfilled and accepted, and none disputed, before settlement": {
+		Korean:   "정산 전에 모든 참여가 이행 및 확인되어야 하며 이의가 없어야 합니다",
+		Japanese: "精算の前にすべての参加が履行・確認され、異議がないことが必要です",
+	},
+	"dispute not found": {
+		Korean:   "분쟁을 찾을 수 없습니다",
+		Japanese: "紛争が見つかりません",
+	},
+	"the participation already has an open dispute": {
+		Korean:   "이 참여에는 이미 진행 중인 분쟁이 있습니다",
+		Japanese: "この参加には既に対応中の紛争があります",
+	},
+	"only settled participations can be disputed after settlement": {
+		Korean:   "정산 후에는 정산된 참여에 대해서만 분쟁을 제기할 수 있습니다",
+		Japanese: "精算後は精算済みの参加のみ異議を申し立てられます",
+	},
+	"the dispute window has closed": {
+		Korean:   "분쟁 제기 기간이 종료되었습니다",
+		Japanese: "異議申し立て期間は終了しました",
+	},
+	"the dispute was already resolved": {
+		Korean:   "이미 처리된 분쟁입니다",
+		Japanese: "この紛争は既に解決済みです",
+	},
+	"the dispute is not awaiting an on-chain refund": {
+		Korean:   "온체인 환불을 기다리는 분쟁이 아닙니다",
+		Japanese: "この紛争はオンチェーン返金待ちではありません",
+	},
+	"refund must be positive and at most the deposit": {
+		Korean:   "환불 금액은 0보다 크고 예치금 이하여야 합니다",
+		Japanese: "返金額は0より大きく、預入額以下である必要があります",
+	},
+	"the participation has no completed payment to refund": {
+		Korean:   "환불할 완료된 결제가 없습니다",
+		Japanese: "返金できる完了済みの決済がありません",
+	},
+	"vouchers are not configured": {
+		Korean:   "바우처가 설정되지 않았습니다",
+		Japanese: "引換券が設定されていません",
+	},
+	"voucher not found": {
+		Korean:   "바우처를 찾을 수 없습니다",
+		Japanese: "引換券が見つかりません",
+	},
+	"the voucher was already redeemed": {
+		Korean:   "이미 사용된 바우처입니다",
+		Japanese: "この引換券は既に使用されています",
+	},
+	"the voucher is no longer valid": {
+		Korean:   "더 이상 유효하지 않은 바우처입니다",
+		Japanese: "この引換券は無効になっています",
+	},
+	"campaign cannot be settled in its current state": {
+		Korean:   "현재 상태에서는 캠페인을 정산할 수 없습니다",
+		Japanese: "現在の状態ではキャンペーンを精算できません",
+	},
+	"campaign has not ended yet": {
+		Korean:   "캠페인이 아직 종료되지 않았습니다",
+		Japanese: "キャンペーンはまだ終了していません",
+	},
+
+	// Payments
+	"payment not found": {
+		Korean:   "결제 내역을 찾을 수 없습니다",
+		Japanese: "決済が見つかりません",
+	},
+	"amount must be positive": {
+		Korean:   "금액은 0보다 커야 합니다",
+		Japanese: "金額は0より大きくなければなりません",
+	},
+	"stripe payments are not enabled": {
+		Korean:   "카드 결제를 사용할 수 없습니다",
+		Japanese: "カード決済はご利用いただけません",
+	},
+
+	// Referrals
+	"referral code not found": {
+		Korean:   "추천 코드를 찾을 수 없습니다",
+		Japanese: "紹介コードが見つかりません",
+	},
+	"you cannot use your own referral code": {
+		Korean:   "본인의 추천 코드는 사용할 수 없습니다",
+		Japanese: "ご自身の紹介コードは使用できません",
+	},
+	"a referral code was already applied": {
+		Korean:   "이미 추천 코드가 적용되었습니다",
+		Japanese: "紹介コードは既に適用されています",
+	},
+	"referral codes can only be applied before your first participation": {
+		Korean:   "추천 코드는 첫 참여 전에만 적용할 수 있습니다",
+		Japanese: "紹介コードは初回参加前のみ適用できます",
+	},
+
+	// Generic
+	"invalid request": {
+		Korean:   "잘못된 요청입니다",
+		Japanese: "リクエストが正しくありません",
+	},
+	"invalid cursor": {
+		Korean:   "페이지 정보가 올바르지 않습니다",
+		Japanese: "ページ情報が正しくありません",
+	},
+	"request timed out": {
+		Korean:   "요청 시간이 초과되었습니다",
+		Japanese: "リクエストがタイムアウトしました",
+	},
+	"internal server error": {
+		Korean:   "서버 내부 오류가 발생했습니다",
+		Japanese: "サーバー内部エラーが発生しました",
+	},
+	"the service is under maintenance": {
+		Korean:   "서비스 점검 중입니다. 잠시 후 다시 이용해 주세요",
+		Japanese: "メンテナンス中です。しばらくしてから再度ご利用ください",
+	},
+	"this feature is temporarily disabled": {
+		Korean:   "이 기능은 일시적으로 사용할 수 없습니다",
+		Japanese: "この機能は一時的にご利用いただけません",
+	},
+}
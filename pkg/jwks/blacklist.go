@@ -0,0 +1,20 @@
+package jwks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// BlacklistKey is the Redis key auth-server sets when an access token is
+// revoked by logout. It expires with the token, so services validating
+// tokens locally check it to refuse logged-out tokens.
+func BlacklistKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return BlacklistHashKey(hex.EncodeToString(sum[:]))
+}
+
+// BlacklistHashKey is BlacklistKey for a token known only by its hex
+// SHA-256 hash, as sessions store it
+func BlacklistHashKey(tokenHash string) string {
+	return "blacklist:" + tokenHash
+}
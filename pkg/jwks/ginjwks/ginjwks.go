@@ -0,0 +1,39 @@
+// Package ginjwks adapts pkg/jwks to gin
+package ginjwks
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/rbac"
+)
+
+// Middleware verifies the bearer token of requests that carry one and
+// attributes them to the token's user and role, replacing the actor headers
+// set by the gateway. Requests without a token are internal calls and keep
+// their headers. It must run before ginaudit and ginrbac.
+func Middleware(v *jwks.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		claims, err := v.Verify(c.Request.Context(), token)
+		if err != nil {
+			c.AbortWithStatusJSON(apperrors.Response(apperrors.Wrap(err, apperrors.CodeUnauthorized, "Invalid token")))
+			return
+		}
+		userID, _ := claims["user_id"].(string)
+		role, _ := claims["role"].(string)
+		c.Request.Header.Set(audit.ActorIDHeader, userID)
+		c.Request.Header.Set(audit.ActorTypeHeader, audit.ActorUser)
+		c.Request.Header.Set(rbac.Header, role)
+		c.Next()
+	}
+}
@@ -0,0 +1,207 @@
+// Package jwks publishes and consumes the public keys access tokens are
+// signed with, as a JSON Web Key Set (RFC 7517).
+//
+// auth-server signs access tokens with an RSA (RS256) or P-256 (ES256)
+// private key and serves the public half at /auth/.well-known/jwks.json.
+// Other services verify tokens locally with a Verifier over a Remote key
+// set instead of calling auth-server for every request.
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Issuer and Audience of every access token
+const (
+	Issuer   = "r2s-auth"
+	Audience = "r2s-api"
+)
+
+var (
+	// ErrUnknownKey means no published key has the token's key id
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrKeysUnavailable means the key set could not be fetched, so the
+	// token could not be checked either way
+	ErrKeysUnavailable = errors.New("signing keys are unavailable")
+)
+
+// Key is a public JSON Web Key
+type Key struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	public crypto.PublicKey
+}
+
+// Set is a JSON Web Key Set
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// Keys finds the public key a token was signed with
+type Keys interface {
+	Lookup(ctx context.Context, kid string) (*Key, error)
+}
+
+// NewKey encodes an RSA or P-256 public key. An empty kid is replaced by
+// the key's RFC 7638 thumbprint.
+func NewKey(pub crypto.PublicKey, kid string) (Key, error) {
+	var k Key
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		k = Key{
+			Kty: "RSA",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   encode(pub.N.Bytes()),
+			E:   encode(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return Key{}, errors.New("only P-256 EC keys are supported")
+		}
+		k = Key{
+			Kty: "EC",
+			Alg: jwt.SigningMethodES256.Alg(),
+			Crv: "P-256",
+			X:   encode(pub.X.FillBytes(make([]byte, 32))),
+			Y:   encode(pub.Y.FillBytes(make([]byte, 32))),
+		}
+	default:
+		return Key{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	k.Use = "sig"
+	k.Kid = kid
+	if k.Kid == "" {
+		k.Kid = k.thumbprint()
+	}
+	k.public = pub
+	return k, nil
+}
+
+// PublicKey decodes the key
+func (k *Key) PublicKey() (crypto.PublicKey, error) {
+	if k.public != nil {
+		return k.public, nil
+	}
+	return k.decode()
+}
+
+func (k *Key) decode() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// thumbprint is the RFC 7638 SHA-256 thumbprint; the members are listed in
+// the lexicographic order the RFC requires
+func (k *Key) thumbprint() string {
+	var members interface{}
+	if k.Kty == "RSA" {
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	} else {
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	}
+	raw, _ := json.Marshal(members)
+	sum := sha256.Sum256(raw)
+	return encode(sum[:])
+}
+
+// Lookup implements Keys
+func (s Set) Lookup(_ context.Context, kid string) (*Key, error) {
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i], nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// Keyfunc returns the public key for a token from keys, refusing tokens
+// whose algorithm differs from the key's
+func Keyfunc(ctx context.Context, keys Keys) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.Lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Alg {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.PublicKey()
+	}
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encoding: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,112 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+const (
+	// remoteTTL is how long fetched keys are used before refetching
+	remoteTTL = 5 * time.Minute
+	// remoteMinInterval limits refetches for unknown key ids, so tokens
+	// with made-up kids cannot hammer auth-server
+	remoteMinInterval = 30 * time.Second
+	remoteTimeout     = 5 * time.Second
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// URL is auth-server's key set
+	URL string `env:"AUTH_JWKS_URL" default:"http://localhost:3002/auth/.well-known/jwks.json"`
+}
+
+// Remote is the key set published at a URL. Keys are fetched on first use,
+// refetched after remoteTTL or when a token names an unknown key, and kept
+// when a refetch fails so a short auth-server outage does not reject
+// tokens signed with known keys.
+type Remote struct {
+	url    string
+	client *http.Client
+	clock  clock.Clock
+
+	mu          sync.Mutex
+	keys        Set
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+func NewRemote(url string, clk clock.Clock) *Remote {
+	return &Remote{
+		url:    url,
+		client: &http.Client{Timeout: remoteTimeout},
+		clock:  clock.OrSystem(clk),
+	}
+}
+
+// Lookup implements Keys
+func (r *Remote) Lookup(ctx context.Context, kid string) (*Key, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, _ := r.keys.Lookup(ctx, kid)
+	if key != nil && r.clock.Since(r.fetchedAt) < remoteTTL {
+		return key, nil
+	}
+	if r.attemptedAt.IsZero() || r.clock.Since(r.attemptedAt) >= remoteMinInterval {
+		r.attemptedAt = r.clock.Now()
+		if err := r.fetch(ctx); err != nil {
+			if key != nil {
+				slog.Warn("Failed to refresh signing keys, using cached keys", "url", r.url, "error", err)
+				return key, nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+		}
+		key, _ = r.keys.Lookup(ctx, kid)
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// fetch replaces the cached keys; keys that fail to decode are skipped
+func (r *Remote) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch signing keys: %s returned %d", r.url, res.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+	keys := make([]Key, 0, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.decode()
+		if err != nil {
+			slog.Warn("Skipping invalid signing key", "kid", k.Kid, "error", err)
+			continue
+		}
+		k.public = pub
+		keys = append(keys, k)
+	}
+
+	r.keys = Set{Keys: keys}
+	r.fetchedAt = r.clock.Now()
+	return nil
+}
@@ -0,0 +1,89 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing
+const minRSABits = 2048
+
+// SigningKey is the private key access tokens are signed with
+type SigningKey struct {
+	// ID is published as the JWK kid and set in each token's header
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+}
+
+// ParseSigningKey reads a PEM-encoded RSA (at least 2048 bits) or P-256
+// private key, in PKCS#8, PKCS#1 or SEC 1 form
+func ParseSigningKey(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in signing key")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+
+	sk := &SigningKey{}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA signing key must be at least %d bits", minRSABits)
+		}
+		sk.Method, sk.Private = jwt.SigningMethodRS256, key
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return nil, errors.New("EC signing key must use P-256")
+		}
+		sk.Method, sk.Private = jwt.SigningMethodES256, key
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+
+	pub, err := sk.JWK()
+	if err != nil {
+		return nil, err
+	}
+	sk.ID = pub.Kid
+	return sk, nil
+}
+
+// LoadSigningKey parses pemData, or the file at path when pemData is empty
+func LoadSigningKey(pemData, path string) (*SigningKey, error) {
+	data := []byte(pemData)
+	if len(data) == 0 {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read signing key: %w", err)
+		}
+	}
+	return ParseSigningKey(data)
+}
+
+// JWK is the public half of the key, to publish
+func (k *SigningKey) JWK() (Key, error) {
+	return NewKey(k.Private.Public(), k.ID)
+}
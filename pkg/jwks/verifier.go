@@ -0,0 +1,51 @@
+package jwks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+// Verifier checks access tokens against a key set. It checks the
+// signature, issuer, audience and validity period only; whether the session
+// was revoked is known to auth-server alone.
+type Verifier struct {
+	keys   Keys
+	clock  clock.Clock
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys Keys, clk clock.Clock) *Verifier {
+	return &Verifier{
+		keys:  keys,
+		clock: clock.OrSystem(clk),
+		parser: jwt.NewParser(
+			jwt.WithoutClaimsValidation(),
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		),
+	}
+}
+
+// Verify returns the claims of a valid access token. Time-based claims are
+// checked against the verifier's clock rather than jwt.TimeFunc.
+func (v *Verifier) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, Keyfunc(ctx, v.keys)); err != nil {
+		return nil, err
+	}
+
+	now := v.clock.Now().Unix()
+	if !claims.VerifyExpiresAt(now, true) {
+		return nil, errors.New("token is expired")
+	}
+	if !claims.VerifyNotBefore(now, false) || !claims.VerifyIssuedAt(now, false) {
+		return nil, errors.New("token used before issued")
+	}
+	if !claims.VerifyIssuer(Issuer, true) || !claims.VerifyAudience(Audience, true) {
+		return nil, errors.New("token was not issued for this API")
+	}
+	return claims, nil
+}
@@ -0,0 +1,124 @@
+// Package line sends push messages to LINE users through the Messaging
+// API of the mini-app's channel. Users are addressed by the LINE user id
+// they connected at sign-in.
+package line
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// ErrRejected means LINE refused the message for good (unknown user, or a
+// malformed request); retrying will not help
+var ErrRejected = errors.New("line: message rejected")
+
+const (
+	defaultBaseURL = "https://api.line.me"
+	requestTimeout = 10 * time.Second
+)
+
+// Config is loadable with pkg/config. Without an access token messages are
+// only logged.
+type Config struct {
+	ChannelAccessToken string `env:"LINE_CHANNEL_ACCESS_TOKEN" secret:"true"`
+	// BaseURL is the Messaging API endpoint, overridable for testing
+	BaseURL string `env:"LINE_API_BASE_URL" default:"https://api.line.me"`
+}
+
+// Sender delivers a text message to one LINE user. retryKey identifies the
+// message: LINE delivers a message once however often it is sent with the
+// same key within a day.
+type Sender interface {
+	Push(ctx context.Context, retryKey, to, text string) error
+}
+
+// New returns a Messaging API sender, or a sender that only logs when cfg
+// has no access token
+func New(cfg Config) Sender {
+	if cfg.ChannelAccessToken == "" {
+		return logSender{}
+	}
+	base := cfg.BaseURL
+	if base == "" {
+		base = defaultBaseURL
+	}
+	return &Client{
+		token:    cfg.ChannelAccessToken,
+		endpoint: strings.TrimRight(base, "/") + "/v2/bot/message/push",
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Client calls the push message endpoint
+type Client struct {
+	token    string
+	endpoint string
+	client   *http.Client
+}
+
+type textMessage struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type pushRequest struct {
+	To       string        `json:"to"`
+	Messages []textMessage `json:"messages"`
+}
+
+// Push sends text to the LINE user to. It returns ErrRejected when LINE
+// will never accept the message.
+func (c *Client) Push(ctx context.Context, retryKey, to, text string) error {
+	body, err := json.Marshal(pushRequest{To: to, Messages: []textMessage{{Type: "text", Text: text}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode LINE message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create LINE request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("X-Line-Retry-Key", retryKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send LINE message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	// A request with this retry key was already accepted
+	case resp.StatusCode == http.StatusConflict:
+		return nil
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var le struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(raw, &le)
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %d %s", ErrRejected, resp.StatusCode, le.Message)
+	}
+	return fmt.Errorf("LINE returned %d: %s", resp.StatusCode, le.Message)
+}
+
+type logSender struct{}
+
+func (logSender) Push(ctx context.Context, retryKey, to, text string) error {
+	logger.FromContext(ctx).Debug("LINE messaging disabled, dropping message", "retry_key", retryKey)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package ginlog adapts pkg/logger to gin. It lives in its own package so
+// services that do not use gin (query-server) do not depend on it.
+package ginlog
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// Middleware assigns each request an id (honouring an incoming X-Request-ID),
+// echoes it in the response, stores a request-scoped logger in the request
+// context and writes one access log line when the request completes. It
+// replaces gin.Logger.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logger.RequestIDHeader)
+		if id == "" {
+			id = logger.NewRequestID()
+		}
+		c.Header(logger.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		lvl := slog.LevelInfo
+		switch {
+		case status >= 500:
+			lvl = slog.LevelError
+		case status >= 400:
+			lvl = slog.LevelWarn
+		}
+
+		ctx := c.Request.Context()
+		logger.FromContext(ctx).Log(ctx, lvl, "request",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// With adds fields to the request-scoped logger for the rest of the request,
+// e.g. ginlog.With(c, logger.KeyCampaignID, id)
+func With(c *gin.Context, args ...any) {
+	c.Request = c.Request.WithContext(logger.With(c.Request.Context(), args...))
+}
+
+// From returns the request-scoped logger
+func From(c *gin.Context) *slog.Logger {
+	return logger.FromContext(c.Request.Context())
+}
+
+// RegisterLevelEndpoint mounts logger.LevelHandler at GET/PUT path
+func RegisterLevelEndpoint(r gin.IRoutes, path string) {
+	h := gin.WrapH(logger.LevelHandler())
+	r.GET(path, h)
+	r.PUT(path, h)
+}
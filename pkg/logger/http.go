@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// LevelHandler serves the admin log-level endpoint. GET returns the current
+// level; PUT or POST with {"level":"debug"} changes it for the whole process.
+// Mount it on an internal or authenticated route only.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var body struct {
+				Level string `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": false, "error": "Invalid request"})
+				return
+			}
+			if err := SetLevel(body.Level); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{"success": false, "error": err.Error()})
+				return
+			}
+			FromContext(r.Context()).Warn("log level changed", "level", strings.ToLower(Level().String()))
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{"success": false, "error": "Method not allowed"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"level":   strings.ToLower(Level().String()),
+		})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
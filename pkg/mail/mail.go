@@ -0,0 +1,101 @@
+// Package mail sends transactional email (verification links, recovery
+// codes) over SMTP.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// Config is loadable with pkg/config. Without a host mail is only logged.
+type Config struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     int    `env:"SMTP_PORT" default:"587"`
+	User     string `env:"SMTP_USER"`
+	Password string `env:"SMTP_PASS" secret:"true"`
+	From     string `env:"SMTP_FROM" default:"noreply@r2s.com"`
+}
+
+// Message is one plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a message
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns an SMTP sender, or a sender that only logs when cfg has no
+// host
+func New(cfg Config) Sender {
+	if cfg.Host == "" {
+		return logSender{}
+	}
+	return &SMTP{cfg: cfg}
+}
+
+// SMTP sends through a relay, upgrading to TLS when the server offers
+// STARTTLS
+type SMTP struct {
+	cfg Config
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return fmt.Errorf("mail: header values must not contain line breaks")
+	}
+
+	var auth smtp.Auth
+	if s.cfg.User != "" {
+		auth = smtp.PlainAuth("", s.cfg.User, s.cfg.Password, s.cfg.Host)
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+
+	// net/smtp takes no context; run it aside so a stuck relay does not
+	// outlive the caller
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, s.cfg.From, []string{msg.To}, s.encode(msg))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send mail: %w", ctx.Err())
+	}
+}
+
+func (s *SMTP) encode(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+type logSender struct{}
+
+func (logSender) Send(ctx context.Context, msg Message) error {
+	logger.FromContext(ctx).Info("mail disabled, dropping message", "to", msg.To, "subject", msg.Subject)
+	logger.FromContext(ctx).Debug("dropped message body", "body", msg.Body)
+	return nil
+}
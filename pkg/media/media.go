@@ -0,0 +1,54 @@
+// Package media checks uploaded images and renders their thumbnails.
+// Uploads go straight from clients to object storage through pre-signed
+// URLs and are served from a public base URL.
+package media
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Config is loadable with pkg/config. Without a public base URL uploads are
+// off.
+type Config struct {
+	// PublicBaseURL serves the object store bucket over HTTPS
+	PublicBaseURL string `env:"MEDIA_PUBLIC_BASE_URL"`
+	// UploadTTL is how long an upload URL accepts the file
+	UploadTTL time.Duration `env:"MEDIA_UPLOAD_TTL" default:"15m"`
+	// MaxBytes bounds an uploaded file
+	MaxBytes int64 `env:"MEDIA_MAX_BYTES" default:"5242880"`
+	// ThumbnailSize bounds the longer side of thumbnails, in pixels
+	ThumbnailSize int `env:"MEDIA_THUMBNAIL_SIZE" default:"320"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.PublicBaseURL == "" {
+		return nil
+	}
+	if !strings.HasPrefix(c.PublicBaseURL, "https://") && !strings.HasPrefix(c.PublicBaseURL, "http://") {
+		return errors.New("MEDIA_PUBLIC_BASE_URL must be an http(s) URL")
+	}
+	if c.UploadTTL <= 0 || c.MaxBytes <= 0 || c.ThumbnailSize <= 0 {
+		return errors.New("MEDIA_UPLOAD_TTL, MEDIA_MAX_BYTES and MEDIA_THUMBNAIL_SIZE must be positive")
+	}
+	return nil
+}
+
+// Enabled reports whether uploads are configured
+func (c Config) Enabled() bool {
+	return c.PublicBaseURL != ""
+}
+
+// URL is the public address of the object at key
+func (c Config) URL(key string) string {
+	return strings.TrimRight(c.PublicBaseURL, "/") + "/" + key
+}
+
+// ContentTypes maps the accepted image types to the extension they are
+// stored with
+var ContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
@@ -0,0 +1,101 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// MaxPixels bounds the images decoded, so a small file cannot expand into
+// gigabytes of pixels
+const MaxPixels = 40_000_000
+
+// ErrUnsupportedImage is returned for files that are not a JPEG or PNG
+// image of at most MaxPixels
+var ErrUnsupportedImage = errors.New("media: unsupported image")
+
+// jpegQuality is the quality of JPEG thumbnails
+const jpegQuality = 85
+
+// Thumbnail decodes a JPEG or PNG image and renders it scaled to fit size x
+// size pixels, in the format it came in so PNG transparency is kept.
+// Smaller images are re-encoded at their size. It returns the thumbnail and
+// its content type.
+func Thumbnail(r io.Reader, size int) ([]byte, string, error) {
+	var buf bytes.Buffer
+	cfg, format, err := image.DecodeConfig(io.TeeReader(r, &buf))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return nil, "", ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&buf, r))
+	if err != nil {
+		return nil, "", ErrUnsupportedImage
+	}
+	thumb := scale(src, size)
+
+	var out bytes.Buffer
+	if format == "png" {
+		if err := png.Encode(&out, thumb); err != nil {
+			return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		return out.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&out, thumb, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return out.Bytes(), "image/jpeg", nil
+}
+
+// scale shrinks src to fit size x size by averaging the source pixels that
+// fall into each target pixel
+func scale(src image.Image, size int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	in := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+	if tw == w && th == h {
+		return in
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, tw, th))
+	for ty := 0; ty < th; ty++ {
+		y0, y1 := ty*h/th, max((ty+1)*h/th, ty*h/th+1)
+		for tx := 0; tx < tw; tx++ {
+			x0, x1 := tx*w/tw, max((tx+1)*w/tw, tx*w/tw+1)
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				row := in.Pix[y*in.Stride+x0*4 : y*in.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			o := ty*out.Stride + tx*4
+			for c := 0; c < 4; c++ {
+				out.Pix[o+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return out
+}
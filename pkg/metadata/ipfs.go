@@ -0,0 +1,84 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+)
+
+const ipfsTimeout = 30 * time.Second
+
+// IPFS adds and pins documents through the Kubo RPC API. Every version gets
+// its own CID; earlier versions stay pinned so URIs already written
+// on-chain keep resolving.
+type IPFS struct {
+	client        *http.Client
+	endpoint      string
+	authorization string
+}
+
+func NewIPFS(apiURL, authorization string) *IPFS {
+	return &IPFS{
+		client:        &http.Client{Timeout: ipfsTimeout},
+		endpoint:      strings.TrimRight(apiURL, "/") + "/api/v0/add?pin=true&cid-version=1",
+		authorization: authorization,
+	}
+}
+
+// Publish returns ipfs://<cid>
+func (p *IPFS) Publish(ctx context.Context, name string, doc []byte) (string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, path.Base(name)))
+	header.Set("Content-Type", "application/json")
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(doc); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create IPFS request: %w", err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	if p.authorization != "" {
+		req.Header.Set("Authorization", p.authorization)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add metadata to IPFS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", fmt.Errorf("failed to read IPFS response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("IPFS returned %d: %s", resp.StatusCode, raw)
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.Unmarshal(raw, &added); err != nil || added.Hash == "" {
+		return "", fmt.Errorf("unexpected IPFS response: %s", raw)
+	}
+	return "ipfs://" + added.Hash, nil
+}
@@ -0,0 +1,102 @@
+// Package metadata publishes the JSON documents that on-chain metadata URIs
+// point at: to IPFS through a Kubo node (or a hosted pinning service with the
+// same RPC API), or to object storage served under a public base URL.
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Reserve-to-save-backend/pkg/objectstore"
+)
+
+// Drivers
+const (
+	DriverIPFS  = "ipfs"
+	DriverStore = "store"
+)
+
+// ErrDisabled is returned by Publish when no driver is configured
+var ErrDisabled = errors.New("metadata: publishing is disabled")
+
+// Config is loadable with pkg/config. Without a driver nothing is published.
+type Config struct {
+	Driver string `env:"METADATA_DRIVER"`
+	// IPFSAPIURL is the Kubo RPC API the documents are added and pinned on
+	IPFSAPIURL string `env:"METADATA_IPFS_API_URL" default:"http://localhost:5001"`
+	// IPFSAuthorization is sent as the Authorization header, for hosted
+	// pinning services ("Basic ...", "Bearer ...")
+	IPFSAuthorization string `env:"METADATA_IPFS_AUTHORIZATION" secret:"true"`
+	// PublicBaseURL serves the object store bucket over HTTPS (store driver)
+	PublicBaseURL string `env:"METADATA_PUBLIC_BASE_URL"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	switch c.Driver {
+	case "":
+	case DriverIPFS:
+		if c.IPFSAPIURL == "" {
+			return errors.New("METADATA_IPFS_API_URL is required for the ipfs driver")
+		}
+	case DriverStore:
+		if c.PublicBaseURL == "" {
+			return errors.New("METADATA_PUBLIC_BASE_URL is required for the store driver")
+		}
+	default:
+		return fmt.Errorf("unknown METADATA_DRIVER %q", c.Driver)
+	}
+	return nil
+}
+
+// Publisher makes a document resolvable
+type Publisher interface {
+	// Publish stores doc under name ("campaigns/<id>/metadata.json") and
+	// returns the URI to reference it by
+	Publish(ctx context.Context, name string, doc []byte) (string, error)
+}
+
+// New returns the publisher selected by cfg.Driver. store is only used by
+// the store driver and may be nil otherwise.
+func New(cfg Config, store objectstore.Store) (Publisher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Driver {
+	case DriverIPFS:
+		return NewIPFS(cfg.IPFSAPIURL, cfg.IPFSAuthorization), nil
+	case DriverStore:
+		if store == nil {
+			return nil, errors.New("metadata: the store driver needs an object store")
+		}
+		return NewStore(store, cfg.PublicBaseURL), nil
+	}
+	return disabled{}, nil
+}
+
+type disabled struct{}
+
+func (disabled) Publish(context.Context, string, []byte) (string, error) {
+	return "", ErrDisabled
+}
+
+// Store publishes to object storage. The key stays the same across
+// versions, so the URI written on-chain always serves the latest document.
+type Store struct {
+	store   objectstore.Store
+	baseURL string
+}
+
+func NewStore(store objectstore.Store, publicBaseURL string) *Store {
+	return &Store{store: store, baseURL: strings.TrimRight(publicBaseURL, "/")}
+}
+
+func (s *Store) Publish(ctx context.Context, name string, doc []byte) (string, error) {
+	if err := s.store.Put(ctx, name, bytes.NewReader(doc), int64(len(doc)), "application/json"); err != nil {
+		return "", fmt.Errorf("failed to upload metadata: %w", err)
+	}
+	return s.baseURL + "/" + name, nil
+}
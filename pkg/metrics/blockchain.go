@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var blockchainRPCDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "blockchain",
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of JSON-RPC calls to the blockchain node, by method and outcome.",
+		Buckets:   DurationBuckets,
+	},
+	[]string{"method", "status"},
+)
+
+func init() {
+	MustRegister(blockchainRPCDuration)
+}
+
+// ObserveBlockchainRPC records one JSON-RPC call to the node. ok is false
+// when the call failed at the transport or HTTP level.
+func ObserveBlockchainRPC(method string, ok bool, d time.Duration) {
+	status := "ok"
+	if !ok {
+		status = "error"
+	}
+	blockchainRPCDuration.WithLabelValues(method, status).Observe(d.Seconds())
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/money"
+)
+
+// Domain counters. Record them after the transaction that made the change
+// commits, never inside a retried closure, so retries are not counted twice.
+var (
+	// ParticipationsCreated counts participations accepted by core-server
+	ParticipationsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "participations_created_total",
+		Help:      "Participations created.",
+	})
+
+	// ParticipationsCancelled counts participations cancelled by users
+	ParticipationsCancelled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "participations_cancelled_total",
+		Help:      "Participations cancelled.",
+	})
+
+	// SettlementsCompleted counts campaigns settled
+	SettlementsCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "settlements_completed_total",
+		Help:      "Campaign settlements completed.",
+	})
+
+	rebatesPaid = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "rebates_paid_total",
+			Help:      "Rebates paid out at settlement, in whole units of the currency.",
+		},
+		[]string{"currency"},
+	)
+
+	// PushNotifications counts push deliveries by topic and result (sent,
+	// unregistered, failed)
+	PushNotifications = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "push_notifications_total",
+			Help:      "Push notifications by topic and delivery result.",
+		},
+		[]string{"topic", "result"},
+	)
+)
+
+func init() {
+	MustRegister(ParticipationsCreated, ParticipationsCancelled, SettlementsCompleted, rebatesPaid, PushNotifications)
+}
+
+// AddRebates adds a settled rebate total to rebates_paid_total. The counter
+// is a float and only meant for dashboards; the database holds exact figures.
+func AddRebates(a money.Amount) {
+	if a.Sign() <= 0 {
+		return
+	}
+	v, err := strconv.ParseFloat(a.Decimal(), 64)
+	if err != nil {
+		return
+	}
+	rebatesPaid.WithLabelValues(a.Currency().Code).Add(v)
+}
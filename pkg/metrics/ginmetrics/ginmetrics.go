@@ -0,0 +1,69 @@
+// Package ginmetrics adapts pkg/metrics to gin. It lives in its own package so
+// services that do not use gin (query-server) do not depend on it.
+package ginmetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var (
+	requests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests completed, by method, route and status.",
+		},
+		[]string{"method", "route", "status"},
+	)
+	duration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests, by method and route.",
+			Buckets:   metrics.DurationBuckets,
+		},
+		[]string{"method", "route"},
+	)
+	inFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+)
+
+func init() {
+	metrics.MustRegister(requests, duration, inFlight)
+}
+
+// Middleware counts and times every request. Routes are labelled with their
+// pattern (/campaigns/:id), not the raw path, so ids do not explode the label
+// set; requests that match no route share the "unmatched" label.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inFlight.Inc()
+		start := time.Now()
+		c.Next()
+		inFlight.Dec()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		requests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		duration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Register mounts the metrics endpoint at metrics.Path
+func Register(r gin.IRoutes) {
+	r.GET(metrics.Path, gin.WrapH(metrics.Handler()))
+}
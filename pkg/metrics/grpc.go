@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcServerHandled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "grpc",
+			Name:      "server_handled_total",
+			Help:      "RPCs completed by the server, by method and status code.",
+		},
+		[]string{"method", "code"},
+	)
+	grpcServerDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "grpc",
+			Name:      "server_handling_seconds",
+			Help:      "Latency of RPCs handled by the server.",
+			Buckets:   DurationBuckets,
+		},
+		[]string{"method"},
+	)
+	grpcClientHandled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "grpc",
+			Name:      "client_handled_total",
+			Help:      "RPCs completed by clients, by method and status code.",
+		},
+		[]string{"method", "code"},
+	)
+	grpcClientDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "grpc",
+			Name:      "client_handling_seconds",
+			Help:      "Latency of RPCs as seen by clients.",
+			Buckets:   DurationBuckets,
+		},
+		[]string{"method"},
+	)
+)
+
+func init() {
+	MustRegister(grpcServerHandled, grpcServerDuration, grpcClientHandled, grpcClientDuration)
+}
+
+// UnaryServerInterceptor counts and times every RPC by method and code
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		grpcServerHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcServerDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts and times every streaming RPC, from its
+// start to its end
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		grpcServerHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcServerDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// UnaryClientInterceptor counts and times every outgoing RPC by method and
+// code
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		grpcClientHandled.WithLabelValues(method, status.Code(err).String()).Inc()
+		grpcClientDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+// Package metrics holds the Prometheus collectors shared by every service so
+// one dashboard covers the whole platform. Everything is registered on the
+// default registry under the r2s namespace:
+//
+//   - r2s_http_* from ginmetrics.Middleware
+//   - r2s_grpc_* from the interceptors in this package
+//   - r2s_db_* and r2s_redis_* from pkg/database
+//   - r2s_cache_* from pkg/cache
+//   - r2s_blockchain_* from pkg/tracing/ethtrace
+//   - the domain counters in domain.go
+//
+// plus the Go runtime and process collectors registered by client_golang.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric defined by the platform
+const Namespace = "r2s"
+
+// Path is where every service serves its metrics
+const Path = "/metrics"
+
+// DurationBuckets are the latency buckets (seconds) used for requests and RPCs
+var DurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Handler serves the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// MustRegister registers c on the default registry, tolerating duplicates so
+// packages can register lazily from constructors
+func MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := prometheus.Register(c); err != nil {
+			var are prometheus.AlreadyRegisteredError
+			if !errors.As(err, &are) {
+				panic(err)
+			}
+		}
+	}
+}
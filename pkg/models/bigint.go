@@ -0,0 +1,115 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BigInt wraps big.Int for NUMERIC columns and JSON. Amounts are always
+// serialised as decimal strings ("5000000") so clients never lose precision
+// to float64; a nil value is written as null.
+type BigInt struct {
+	*big.Int
+}
+
+// NewBigInt wraps v; a nil v stays nil
+func NewBigInt(v *big.Int) BigInt {
+	return BigInt{Int: v}
+}
+
+// ParseBigInt parses a base-10 integer string
+func ParseBigInt(s string) (BigInt, error) {
+	n, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return BigInt{}, fmt.Errorf("invalid integer %q", s)
+	}
+	return BigInt{Int: n}, nil
+}
+
+// Big returns the value, treating nil as zero, for use in arithmetic
+func (b BigInt) Big() *big.Int {
+	if b.Int == nil {
+		return new(big.Int)
+	}
+	return b.Int
+}
+
+// String returns the decimal representation, "0" for nil
+func (b BigInt) String() string {
+	return b.Big().String()
+}
+
+func (b *BigInt) Scan(value interface{}) error {
+	if value == nil {
+		b.Int = nil
+		return nil
+	}
+
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case int64:
+		b.Int = big.NewInt(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into BigInt", value)
+	}
+
+	// NUMERIC(36,18) columns come back as "1000000.000000000000000000";
+	// amounts are stored in base units so the fraction is always zero
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid BigInt value %q", s)
+	}
+	b.Int = n
+	return nil
+}
+
+func (b BigInt) Value() (driver.Value, error) {
+	if b.Int == nil {
+		return nil, nil
+	}
+	return b.Int.String(), nil
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	if b.Int == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(b.Int.String())
+}
+
+// UnmarshalJSON accepts a decimal string or, for older clients, a bare
+// JSON number
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		b.Int = nil
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+
+	v, err := ParseBigInt(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
@@ -1,99 +1,147 @@
 package models
 
 import (
-	"database/sql/driver"
-	"math/big"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type CampaignStatus string
 
 const (
-	StatusDraft       CampaignStatus = "draft"
-	StatusRecruiting  CampaignStatus = "recruiting"
-	StatusReached     CampaignStatus = "reached"
-	StatusFulfillment CampaignStatus = "fulfillment"
-	StatusSettled     CampaignStatus = "settled"
-	StatusFailed      CampaignStatus = "failed"
-	StatusCancelled   CampaignStatus = "cancelled"
+	StatusDraft         CampaignStatus = "draft"
+	StatusPendingReview CampaignStatus = "pending_review"
+	StatusApproved      CampaignStatus = "approved"
+	StatusRejected      CampaignStatus = "rejected"
+	StatusRecruiting    CampaignStatus = "recruiting"
+	StatusReached       CampaignStatus = "reached"
+	StatusFulfillment   CampaignStatus = "fulfillment"
+	StatusSettled       CampaignStatus = "settled"
+	StatusFailed        CampaignStatus = "failed"
+	StatusCancelled     CampaignStatus = "cancelled"
+	StatusPaused        CampaignStatus = "paused"
 )
 
+// JoinableStatuses are the statuses in which a campaign accepts participations
+var JoinableStatuses = []CampaignStatus{StatusRecruiting, StatusReached}
+
+// ActiveStatuses are the statuses of campaigns still running, i.e. holding
+// deposits that are neither settled nor returned
+var ActiveStatuses = []CampaignStatus{StatusRecruiting, StatusReached, StatusFulfillment, StatusPaused}
+
+// Participation statuses
+const (
+	ParticipationActive        = "active"
+	ParticipationPendingCancel = "pending_cancel"
+	ParticipationCancelled     = "cancelled"
+	ParticipationSettled       = "settled"
+	ParticipationRefunded      = "refunded"
+)
+
+// Campaign is a group-buy campaign. ChainAddress is empty until the
+// campaign is deployed, which needs an approved review.
 type Campaign struct {
-	ID             uuid.UUID       `json:"id" db:"id"`
-	ChainAddress   string          `json:"chain_address" db:"chain_address"`
-	Title          string          `json:"title" db:"title"`
-	Description    *string         `json:"description,omitempty" db:"description"`
-	ImageURL       *string         `json:"image_url,omitempty" db:"image_url"`
-	MerchantID     *uuid.UUID      `json:"merchant_id,omitempty" db:"merchant_id"`
-	MerchantWallet string          `json:"merchant_wallet" db:"merchant_wallet"`
-	BasePrice      *big.Int        `json:"base_price" db:"base_price"`
-	MinQty         int             `json:"min_qty" db:"min_qty"`
-	CurrentQty     int             `json:"current_qty" db:"current_qty"`
-	TargetAmount   *big.Int        `json:"target_amount" db:"target_amount"`
-	CurrentAmount  *big.Int        `json:"current_amount" db:"current_amount"`
-	DiscountRate   int             `json:"discount_rate" db:"discount_rate"`
-	SaveFloorBps   int             `json:"save_floor_bps" db:"save_floor_bps"`
-	RMaxBps        int             `json:"r_max_bps" db:"r_max_bps"`
-	MerchantFeeBps int             `json:"merchant_fee_bps" db:"merchant_fee_bps"`
-	OpsFeeBps      int             `json:"ops_fee_bps" db:"ops_fee_bps"`
-	StartTime      time.Time       `json:"start_time" db:"start_time"`
-	EndTime        time.Time       `json:"end_time" db:"end_time"`
-	SettlementDate *time.Time      `json:"settlement_date,omitempty" db:"settlement_date"`
-	Status         CampaignStatus  `json:"status" db:"status"`
-	TxHash         *string         `json:"tx_hash,omitempty" db:"tx_hash"`
-	BlockNumber    *int64          `json:"block_number,omitempty" db:"block_number"`
-	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
-	Metadata       map[string]interface{} `json:"metadata" db:"metadata"`
+	ID             uuid.UUID      `json:"id" db:"id"`
+	ChainAddress   string         `json:"chain_address" db:"chain_address"`
+	Title          string         `json:"title" db:"title"`
+	Description    *string        `json:"description,omitempty" db:"description"`
+	ImageURL       *string        `json:"image_url,omitempty" db:"image_url"`
+	MerchantID     *uuid.UUID     `json:"merchant_id,omitempty" db:"merchant_id"`
+	MerchantWallet string         `json:"merchant_wallet" db:"merchant_wallet"`
+	BasePrice      BigInt         `json:"base_price" db:"base_price"`
+	MinQty         int            `json:"min_qty" db:"min_qty"`
+	CurrentQty     int            `json:"current_qty" db:"current_qty"`
+	TargetAmount   BigInt         `json:"target_amount" db:"target_amount"`
+	CurrentAmount  BigInt         `json:"current_amount" db:"current_amount"`
+	DiscountRate   int            `json:"discount_rate" db:"discount_rate"`
+	SaveFloorBps   int            `json:"save_floor_bps" db:"save_floor_bps"`
+	RMaxBps        int            `json:"r_max_bps" db:"r_max_bps"`
+	MerchantFeeBps int            `json:"merchant_fee_bps" db:"merchant_fee_bps"`
+	OpsFeeBps      int            `json:"ops_fee_bps" db:"ops_fee_bps"`
+	StartTime      time.Time      `json:"start_time" db:"start_time"`
+	EndTime        time.Time      `json:"end_time" db:"end_time"`
+	SettlementDate *time.Time     `json:"settlement_date,omitempty" db:"settlement_date"`
+	Status         CampaignStatus `json:"status" db:"status"`
+	TxHash         *string        `json:"tx_hash,omitempty" db:"tx_hash"`
+	BlockNumber    *int64         `json:"block_number,omitempty" db:"block_number"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	Metadata       JSONB          `json:"metadata" db:"metadata"`
+	// MetadataURI is where the published metadata document resolves
+	MetadataURI         *string    `json:"metadata_uri,omitempty" db:"metadata_uri"`
+	MetadataHash        *string    `json:"metadata_hash,omitempty" db:"metadata_hash"`
+	MetadataPublishedAt *time.Time `json:"metadata_published_at,omitempty" db:"metadata_published_at"`
+	// CancelDeadline ends free cancellation; nil means EndTime. Later
+	// cancels cost LateCancelPenaltyBps, or are refused when it is nil.
+	CancelDeadline       *time.Time `json:"cancel_deadline,omitempty" db:"cancel_deadline"`
+	LateCancelPenaltyBps *int       `json:"late_cancel_penalty_bps,omitempty" db:"late_cancel_penalty_bps"`
+	// ImageURL and ImageThumbnailURL are set from an uploaded MediaUpload
+	ImageThumbnailURL *string `json:"image_thumbnail_url,omitempty" db:"image_thumbnail_url"`
+	// Category is the slug of a CampaignCategory; Tags are lowercase and
+	// unique
+	Category *string        `json:"category,omitempty" db:"category"`
+	Tags     pq.StringArray `json:"tags" db:"tags"`
+	// Version is bumped by every change (019_row_versions.sql); updates
+	// must send the version they read
+	Version int64 `json:"version" db:"version"`
 }
 
-type Participation struct {
-	ID                uuid.UUID  `json:"id" db:"id"`
-	CampaignID        uuid.UUID  `json:"campaign_id" db:"campaign_id"`
-	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
-	WalletAddress     string     `json:"wallet_address" db:"wallet_address"`
-	DepositAmount     *big.Int   `json:"deposit_amount" db:"deposit_amount"`
-	JoinedAt          time.Time  `json:"joined_at" db:"joined_at"`
-	CancelPending     *big.Int   `json:"cancel_pending" db:"cancel_pending"`
-	ExpectedRebate    *big.Int   `json:"expected_rebate" db:"expected_rebate"`
-	ActualRebate      *big.Int   `json:"actual_rebate,omitempty" db:"actual_rebate"`
-	Status            string     `json:"status" db:"status"`
-	TxHash            *string    `json:"tx_hash,omitempty" db:"tx_hash"`
-	CancelTxHash      *string    `json:"cancel_tx_hash,omitempty" db:"cancel_tx_hash"`
-	SettlementTxHash  *string    `json:"settlement_tx_hash,omitempty" db:"settlement_tx_hash"`
-	RefundTxHash      *string    `json:"refund_tx_hash,omitempty" db:"refund_tx_hash"`
-	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
-	Metadata          map[string]interface{} `json:"metadata" db:"metadata"`
+// FreeCancelUntil returns when free cancellation of the campaign ends
+func (c *Campaign) FreeCancelUntil() time.Time {
+	if c.CancelDeadline != nil {
+		return *c.CancelDeadline
+	}
+	return c.EndTime
 }
 
-// BigInt is a wrapper for big.Int to handle database operations
-type BigInt struct {
-	*big.Int
+// CampaignTransition is one status change of a campaign, with who made it
+// and why. ActorID is empty for changes made by a service.
+type CampaignTransition struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	CampaignID uuid.UUID      `json:"campaign_id" db:"campaign_id"`
+	FromStatus CampaignStatus `json:"from_status" db:"from_status"`
+	ToStatus   CampaignStatus `json:"to_status" db:"to_status"`
+	ActorID    *string        `json:"actor_id,omitempty" db:"actor_id"`
+	ActorType  string         `json:"actor_type" db:"actor_type"`
+	Reason     *string        `json:"reason,omitempty" db:"reason"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
 }
 
-func (b *BigInt) Scan(value interface{}) error {
-	if value == nil {
-		b.Int = nil
-		return nil
-	}
-	
-	switch v := value.(type) {
-	case []byte:
-		b.Int = new(big.Int)
-		b.Int.SetString(string(v), 10)
-	case string:
-		b.Int = new(big.Int)
-		b.Int.SetString(v, 10)
-	}
-	return nil
+// Review decisions
+const (
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// CampaignReview is an ops decision on a campaign submitted for review
+type CampaignReview struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CampaignID uuid.UUID `json:"campaign_id" db:"campaign_id"`
+	ReviewerID string    `json:"reviewer_id" db:"reviewer_id"`
+	Decision   string    `json:"decision" db:"decision"`
+	Comment    *string   `json:"comment,omitempty" db:"comment"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
 
-func (b BigInt) Value() (driver.Value, error) {
-	if b.Int == nil {
-		return nil, nil
-	}
-	return b.String(), nil
-}
\ No newline at end of file
+type Participation struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	CampaignID       uuid.UUID `json:"campaign_id" db:"campaign_id"`
+	UserID           uuid.UUID `json:"user_id" db:"user_id"`
+	WalletAddress    string    `json:"wallet_address" db:"wallet_address"`
+	DepositAmount    BigInt    `json:"deposit_amount" db:"deposit_amount"`
+	JoinedAt         time.Time `json:"joined_at" db:"joined_at"`
+	CancelPending    BigInt    `json:"cancel_pending" db:"cancel_pending"`
+	CancelPenalty    BigInt    `json:"cancel_penalty" db:"cancel_penalty"`
+	ExpectedRebate   BigInt    `json:"expected_rebate" db:"expected_rebate"`
+	ActualRebate     BigInt    `json:"actual_rebate" db:"actual_rebate"`
+	Status           string    `json:"status" db:"status"`
+	TxHash           *string   `json:"tx_hash,omitempty" db:"tx_hash"`
+	CancelTxHash     *string   `json:"cancel_tx_hash,omitempty" db:"cancel_tx_hash"`
+	SettlementTxHash *string   `json:"settlement_tx_hash,omitempty" db:"settlement_tx_hash"`
+	RefundTxHash     *string   `json:"refund_tx_hash,omitempty" db:"refund_tx_hash"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	Metadata         JSONB     `json:"metadata" db:"metadata"`
+	Version          int64     `json:"version" db:"version"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// CampaignCategory is one entry of the category taxonomy campaigns are
+// browsed by. Slug is its permanent id; categories are listed by Position.
+type CampaignCategory struct {
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
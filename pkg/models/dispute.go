@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dispute statuses. Open disputes await an admin; refunding ones await
+// their refund. The others are closed.
+const (
+	DisputeOpen        = "open"
+	DisputeRefunding   = "refunding"
+	DisputeRefunded    = "refunded"
+	DisputeRejected    = "rejected"
+	DisputeRedelivered = "redelivered"
+)
+
+// What a dispute is about
+const (
+	DisputeOnFulfillment = "fulfillment"
+	DisputeOnSettlement  = "settlement"
+)
+
+// How a dispute is refunded: through the payment provider, or by an
+// on-chain refund transaction built by tx-helper
+const (
+	RefundViaPayment = "payment"
+	RefundViaChain   = "onchain"
+)
+
+// Dispute is a participant's complaint about the fulfillment of their order
+// or their settlement, and how it was resolved. SLADueAt is when the current
+// stage is due; SLABreachedAt is set by batch-server once it passed.
+// WalletAddress and ChainAddress are the participant's wallet and the
+// campaign contract, which on-chain refunds are sent between.
+type Dispute struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	ParticipationID uuid.UUID  `json:"participation_id" db:"participation_id"`
+	CampaignID      uuid.UUID  `json:"campaign_id" db:"campaign_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Kind            string     `json:"kind" db:"kind"`
+	Status          string     `json:"status" db:"status"`
+	Reason          string     `json:"reason" db:"reason"`
+	ResolutionNote  *string    `json:"resolution_note,omitempty" db:"resolution_note"`
+	RefundAmount    BigInt     `json:"refund_amount,omitempty" db:"refund_amount"`
+	RefundMethod    *string    `json:"refund_method,omitempty" db:"refund_method"`
+	PaymentID       *uuid.UUID `json:"payment_id,omitempty" db:"payment_id"`
+	RefundTxHash    *string    `json:"refund_tx_hash,omitempty" db:"refund_tx_hash"`
+	ResolvedBy      *uuid.UUID `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+	RefundedAt      *time.Time `json:"refunded_at,omitempty" db:"refunded_at"`
+	SLADueAt        time.Time  `json:"sla_due_at" db:"sla_due_at"`
+	SLABreachedAt   *time.Time `json:"sla_breached_at,omitempty" db:"sla_breached_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	WalletAddress   string     `json:"wallet_address" db:"wallet_address"`
+	ChainAddress    string     `json:"chain_address" db:"chain_address"`
+}
+
+// Pending reports whether the dispute still awaits a resolution or refund
+func (d *Dispute) Pending() bool {
+	return d.Status == DisputeOpen || d.Status == DisputeRefunding
+}
@@ -0,0 +1,5 @@
+// Package models holds the rows of the database schema shared by every
+// service: pkg/db/init-postgres.sql plus pkg/db/migrations. Ids are UUIDs,
+// addresses are stored in lower case, amounts are NUMERIC base units
+// (BigInt) and statuses are the string constants declared here.
+package models
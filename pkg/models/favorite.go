@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignFavorite is a campaign on a user's watchlist
+type CampaignFavorite struct {
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	CampaignID uuid.UUID `json:"campaign_id" db:"campaign_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fulfillment statuses
+const (
+	FulfillmentFulfilled = "fulfilled"
+	FulfillmentConfirmed = "confirmed"
+	FulfillmentDisputed  = "disputed"
+)
+
+// Fulfillment is a merchant's statement that a participation's order was
+// delivered, with proof, and the participant's answer. ProofHash is the
+// 0x-prefixed SHA-256 of the receipt or photo; ProofURL is set when the
+// photo itself was uploaded.
+type Fulfillment struct {
+	ParticipationID   uuid.UUID  `json:"participation_id" db:"participation_id"`
+	CampaignID        uuid.UUID  `json:"campaign_id" db:"campaign_id"`
+	UserID            uuid.UUID  `json:"user_id" db:"user_id"`
+	Status            string     `json:"status" db:"status"`
+	ProofHash         string     `json:"proof_hash" db:"proof_hash"`
+	ProofURL          *string    `json:"proof_url,omitempty" db:"proof_url"`
+	ProofThumbnailURL *string    `json:"proof_thumbnail_url,omitempty" db:"proof_thumbnail_url"`
+	Note              *string    `json:"note,omitempty" db:"note"`
+	FulfilledBy       *uuid.UUID `json:"fulfilled_by,omitempty" db:"fulfilled_by"`
+	FulfilledAt       time.Time  `json:"fulfilled_at" db:"fulfilled_at"`
+	ConfirmDeadline   time.Time  `json:"confirm_deadline" db:"confirm_deadline"`
+	RespondedAt       *time.Time `json:"responded_at,omitempty" db:"responded_at"`
+	DisputeReason     *string    `json:"dispute_reason,omitempty" db:"dispute_reason"`
+}
+
+// Accepted reports whether the participant confirmed the fulfillment, or
+// let the confirmation window pass without disputing it
+func (f *Fulfillment) Accepted(now time.Time) bool {
+	return f.Status == FulfillmentConfirmed || (f.Status == FulfillmentFulfilled && !now.Before(f.ConfirmDeadline))
+}
+
+// FulfillmentSummary counts a campaign's active participations by
+// fulfillment state. Settleable is true once every one is accepted and
+// none is disputed.
+type FulfillmentSummary struct {
+	CampaignID     uuid.UUID      `json:"campaign_id"`
+	CampaignStatus CampaignStatus `json:"campaign_status"`
+	ChainAddress   string         `json:"chain_address"`
+	Participations int64          `json:"participations" db:"participations"`
+	Unfulfilled    int64          `json:"unfulfilled" db:"unfulfilled"`
+	// AwaitingResponse were marked fulfilled and may still be confirmed or
+	// disputed
+	AwaitingResponse int64 `json:"awaiting_response" db:"awaiting_response"`
+	Accepted         int64 `json:"accepted" db:"accepted"`
+	Disputed         int64 `json:"disputed" db:"disputed"`
+	// ConfirmDeadline is when the last open confirmation window closes
+	ConfirmDeadline *time.Time `json:"confirm_deadline,omitempty" db:"confirm_deadline"`
+	Settleable      bool       `json:"settleable"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONB maps a JSONB object column. NULL scans to an empty object and an
+// empty value is written as '{}', so callers never see a nil map from the
+// database and the column never holds SQL NULL.
+type JSONB map[string]interface{}
+
+func (j *JSONB) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*j = JSONB{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONB", value)
+	}
+
+	m := JSONB{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return fmt.Errorf("invalid JSONB value: %w", err)
+	}
+	*j = m
+	return nil
+}
+
+func (j JSONB) Value() (driver.Value, error) {
+	if j == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// MarshalJSON writes an empty object rather than null for a nil map
+func (j JSONB) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]interface{}(j))
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MaxKYCTier is the highest verification tier
+const MaxKYCTier = 3
+
+// KYC application statuses
+const (
+	KYCPending  = "pending"
+	KYCApproved = "approved"
+	KYCRejected = "rejected"
+)
+
+// KYC document types
+const (
+	KYCDocPassport        = "passport"
+	KYCDocIDCard          = "id_card"
+	KYCDocDrivingLicense  = "driving_license"
+	KYCDocResidencePermit = "residence_permit"
+)
+
+// KYCApplication asks for a user's KYC tier to be raised
+type KYCApplication struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	RequestedTier int       `json:"requested_tier" db:"requested_tier"`
+	DocumentType  string    `json:"document_type" db:"document_type"`
+	// DocumentKeys are object storage keys; documents are never served back
+	DocumentKeys pq.StringArray `json:"-" db:"document_keys"`
+	Status       string         `json:"status" db:"status"`
+	RejectReason *string        `json:"reject_reason,omitempty" db:"reject_reason"`
+	CreatedAt    time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+	ReviewedAt   *time.Time     `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MediaPurpose is what an uploaded image is for
+type MediaPurpose string
+
+const (
+	MediaCampaignImage    MediaPurpose = "campaign_image"
+	MediaMerchantLogo     MediaPurpose = "merchant_logo"
+	MediaFulfillmentProof MediaPurpose = "fulfillment_proof"
+)
+
+type MediaStatus string
+
+const (
+	// MediaPending uploads have an upload URL but no checked file yet
+	MediaPending MediaStatus = "pending"
+	// MediaReady uploads were checked and have a URL and thumbnail
+	MediaReady MediaStatus = "ready"
+)
+
+// MediaUpload is an image a client uploads to object storage through a
+// pre-signed URL. Completing the upload checks the file, moves it under
+// media/ with a thumbnail and sets URL and ThumbnailURL.
+type MediaUpload struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	OwnerID      uuid.UUID    `json:"owner_id" db:"owner_id"`
+	Purpose      MediaPurpose `json:"purpose" db:"purpose"`
+	Status       MediaStatus  `json:"status" db:"status"`
+	ContentType  string       `json:"content_type" db:"content_type"`
+	Size         int64        `json:"size" db:"size"`
+	UploadKey    string       `json:"-" db:"upload_key"`
+	URL          *string      `json:"url,omitempty" db:"url"`
+	ThumbnailURL *string      `json:"thumbnail_url,omitempty" db:"thumbnail_url"`
+	ExpiresAt    time.Time    `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	CompletedAt  *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+}
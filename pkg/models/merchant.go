@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMerchantFeeBps is the fee merchants agree to when they register
+const DefaultMerchantFeeBps = 250
+
+type MerchantStatus string
+
+const (
+	MerchantPending   MerchantStatus = "pending"
+	MerchantApproved  MerchantStatus = "approved"
+	MerchantRejected  MerchantStatus = "rejected"
+	MerchantSuspended MerchantStatus = "suspended"
+)
+
+// Merchant is a user's business registration. ID is the user's ID.
+type Merchant struct {
+	ID                 uuid.UUID      `json:"id" db:"id"`
+	BusinessName       string         `json:"business_name" db:"business_name"`
+	RegistrationNumber *string        `json:"registration_number,omitempty" db:"registration_number"`
+	ContactEmail       string         `json:"contact_email" db:"contact_email"`
+	ContactPhone       *string        `json:"contact_phone,omitempty" db:"contact_phone"`
+	Website            *string        `json:"website,omitempty" db:"website"`
+	Country            string         `json:"country" db:"country"`
+	PayoutWallet       string         `json:"payout_wallet" db:"payout_wallet"`
+	FeeBps             int            `json:"fee_bps" db:"fee_bps"`
+	FeeAgreedAt        time.Time      `json:"fee_agreed_at" db:"fee_agreed_at"`
+	Status             MerchantStatus `json:"status" db:"status"`
+	StatusReason       *string        `json:"status_reason,omitempty" db:"status_reason"`
+	ReviewedBy         *uuid.UUID     `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt         *time.Time     `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	// LogoURL and LogoThumbnailURL are set from an uploaded MediaUpload
+	LogoURL          *string `json:"logo_url,omitempty" db:"logo_url"`
+	LogoThumbnailURL *string `json:"logo_thumbnail_url,omitempty" db:"logo_thumbnail_url"`
+}
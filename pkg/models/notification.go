@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device platforms
+const (
+	PlatformWeb     = "web"
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// Notification topics; each is a column of notification_preferences
+const (
+	TopicCampaignMilestones = "campaign_milestones"
+	TopicRebatePayouts      = "rebate_payouts"
+	TopicWatchlist          = "watchlist"
+	TopicCancellations      = "cancellations"
+)
+
+// Notification channels besides push; each is a column of
+// notification_preferences
+const (
+	ChannelLine  = "line"
+	ChannelEmail = "email"
+)
+
+// Notification delivery statuses
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// Device is a push token registered by a web or app client
+type Device struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	Token      string    `json:"token" db:"token"`
+	Platform   string    `json:"platform" db:"platform"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// NotificationPreferences are a user's topic and channel opt-ins; users
+// without a row receive everything
+type NotificationPreferences struct {
+	UserID             uuid.UUID `json:"user_id" db:"user_id"`
+	CampaignMilestones bool      `json:"campaign_milestones" db:"campaign_milestones"`
+	RebatePayouts      bool      `json:"rebate_payouts" db:"rebate_payouts"`
+	Watchlist          bool      `json:"watchlist" db:"watchlist"`
+	Cancellations      bool      `json:"cancellations" db:"cancellations"`
+	Line               bool      `json:"line" db:"line"`
+	Email              bool      `json:"email" db:"email"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NotificationDelivery is a LINE message or email queued for a user, and
+// the log of sending it
+type NotificationDelivery struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	EventKey      string     `json:"event_key" db:"event_key"`
+	Kind          string     `json:"kind" db:"kind"`
+	Channel       string     `json:"channel" db:"channel"`
+	Recipient     string     `json:"-" db:"recipient"`
+	Subject       string     `json:"subject" db:"subject"`
+	Body          string     `json:"body" db:"body"`
+	Status        string     `json:"status" db:"status"`
+	Attempts      int        `json:"attempts" db:"attempts"`
+	LastError     *string    `json:"-" db:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
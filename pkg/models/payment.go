@@ -1,7 +1,6 @@
 package models
 
 import (
-	"math/big"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,7 +32,7 @@ type Payment struct {
 	CampaignID       *uuid.UUID             `json:"campaign_id,omitempty" db:"campaign_id"`
 	UserID           *uuid.UUID             `json:"user_id,omitempty" db:"user_id"`
 	ParticipationID  *uuid.UUID             `json:"participation_id,omitempty" db:"participation_id"`
-	Amount           *big.Int               `json:"amount" db:"amount"`
+	Amount           BigInt                 `json:"amount" db:"amount"`
 	Currency         Currency               `json:"currency" db:"currency"`
 	Mode             PaymentMode            `json:"mode" db:"mode"`
 	Status           PaymentStatus          `json:"status" db:"status"`
@@ -43,7 +42,7 @@ type Payment struct {
 	CompletedAt      *time.Time             `json:"completed_at,omitempty" db:"completed_at"`
 	FailedAt         *time.Time             `json:"failed_at,omitempty" db:"failed_at"`
 	RefundedAt       *time.Time             `json:"refunded_at,omitempty" db:"refunded_at"`
-	Metadata         map[string]interface{} `json:"metadata" db:"metadata"`
+	Metadata         JSONB                  `json:"metadata" db:"metadata"`
 }
 
 type WebhookLog struct {
@@ -57,4 +56,4 @@ type WebhookLog struct {
 	ErrorMessage *string                `json:"error_message,omitempty" db:"error_message"`
 	ReceivedAt   time.Time              `json:"received_at" db:"received_at"`
 	ProcessedAt  *time.Time             `json:"processed_at,omitempty" db:"processed_at"`
-}
\ No newline at end of file
+}
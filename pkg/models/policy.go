@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CampaignPolicy bounds the terms of new campaigns of merchants at a tier,
+// the KYC tier of the merchant's user. Nil bounds are not enforced.
+type CampaignPolicy struct {
+	MerchantTier int `json:"merchant_tier" db:"merchant_tier"`
+	// MaxRMaxBps caps the max rebate rate
+	MaxRMaxBps *int `json:"max_r_max_bps,omitempty" db:"max_r_max_bps"`
+	// MinLockHours is the shortest window from start to end time
+	MinLockHours *int `json:"min_lock_hours,omitempty" db:"min_lock_hours"`
+	// MinFeeBps and MaxFeeBps bound the merchant's agreed fee
+	MinFeeBps *int      `json:"min_fee_bps,omitempty" db:"min_fee_bps"`
+	MaxFeeBps *int      `json:"max_fee_bps,omitempty" db:"max_fee_bps"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Referral reward roles and statuses
+const (
+	ReferralRoleReferrer = "referrer"
+	ReferralRoleReferee  = "referee"
+
+	ReferralRewardPending = "pending"
+	ReferralRewardPaid    = "paid"
+)
+
+// Referral records that a user joined with another user's referral code.
+// It qualifies on the referee's first participation.
+type Referral struct {
+	RefereeID       uuid.UUID  `json:"referee_id" db:"referee_id"`
+	ReferrerID      uuid.UUID  `json:"referrer_id" db:"referrer_id"`
+	Code            string     `json:"code" db:"code"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	QualifiedAt     *time.Time `json:"qualified_at,omitempty" db:"qualified_at"`
+	ParticipationID *uuid.UUID `json:"participation_id,omitempty" db:"participation_id"`
+}
+
+// ReferralReward is an entry of the referral rewards ledger: a bonus of
+// BonusBps of the referee's first deposit, in the deposit's base units
+type ReferralReward struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	RefereeID       uuid.UUID  `json:"referee_id" db:"referee_id"`
+	ParticipationID *uuid.UUID `json:"participation_id,omitempty" db:"participation_id"`
+	Role            string     `json:"role" db:"role"`
+	Amount          BigInt     `json:"amount" db:"amount"`
+	BonusBps        int        `json:"bonus_bps" db:"bonus_bps"`
+	Status          string     `json:"status" db:"status"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	PaidAt          *time.Time `json:"paid_at,omitempty" db:"paid_at"`
+}
+
+// ReferralStats summarises a user's referrals and rewards
+type ReferralStats struct {
+	Code      string `json:"code"`
+	Referred  int64  `json:"referred" db:"referred"`
+	Qualified int64  `json:"qualified" db:"qualified"`
+	// Pending and Paid are reward totals in base units
+	Pending BigInt `json:"pending" db:"pending"`
+	Paid    BigInt `json:"paid" db:"paid"`
+	// ReferredBy is the code the user joined with, if any
+	ReferredBy *string `json:"referred_by,omitempty" db:"referred_by"`
+}
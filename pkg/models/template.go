@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CampaignTemplate holds the terms of a recurring offer under a name unique
+// to its merchant. Campaigns launched from it get their own lock window;
+// CancelLeadSeconds, when set, closes free cancellation that long before the
+// window ends. SourceCampaignID is the campaign it was saved from, if any.
+type CampaignTemplate struct {
+	ID                   uuid.UUID      `json:"id" db:"id"`
+	MerchantID           uuid.UUID      `json:"merchant_id" db:"merchant_id"`
+	Name                 string         `json:"name" db:"name"`
+	Title                string         `json:"title" db:"title"`
+	Description          *string        `json:"description,omitempty" db:"description"`
+	ImageURL             *string        `json:"image_url,omitempty" db:"image_url"`
+	ImageThumbnailURL    *string        `json:"image_thumbnail_url,omitempty" db:"image_thumbnail_url"`
+	MerchantWallet       string         `json:"merchant_wallet" db:"merchant_wallet"`
+	BasePrice            BigInt         `json:"base_price" db:"base_price"`
+	MinQty               int            `json:"min_qty" db:"min_qty"`
+	DiscountRate         int            `json:"discount_rate" db:"discount_rate"`
+	SaveFloorBps         int            `json:"save_floor_bps" db:"save_floor_bps"`
+	RMaxBps              int            `json:"r_max_bps" db:"r_max_bps"`
+	CancelLeadSeconds    *int64         `json:"cancel_lead_seconds,omitempty" db:"cancel_lead_seconds"`
+	LateCancelPenaltyBps *int           `json:"late_cancel_penalty_bps,omitempty" db:"late_cancel_penalty_bps"`
+	Category             *string        `json:"category,omitempty" db:"category"`
+	Tags                 pq.StringArray `json:"tags" db:"tags"`
+	Metadata             JSONB          `json:"metadata" db:"metadata"`
+	SourceCampaignID     *uuid.UUID     `json:"source_campaign_id,omitempty" db:"source_campaign_id"`
+	CreatedAt            time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at" db:"updated_at"`
+}
@@ -4,22 +4,38 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/lib/pq"
+)
+
+// User roles
+const (
+	RoleUser     = "user"
+	RoleMerchant = "merchant"
+	RoleOps      = "ops"
+	RoleAdmin    = "admin"
+)
+
+// User statuses
+const (
+	UserActive    = "active"
+	UserSuspended = "suspended"
 )
 
 type User struct {
-	ID              uuid.UUID      `json:"id" db:"id"`
-	WalletAddress   string         `json:"wallet_address" db:"wallet_address"`
-	LineUserID      *string        `json:"line_user_id,omitempty" db:"line_user_id"`
-	LineDisplayName *string        `json:"line_display_name,omitempty" db:"line_display_name"`
-	LinePictureURL  *string        `json:"line_picture_url,omitempty" db:"line_picture_url"`
-	Email           *string        `json:"email,omitempty" db:"email"`
-	KYCTier         int            `json:"kyc_tier" db:"kyc_tier"`
-	Status          string         `json:"status" db:"status"`
-	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
-	LastLoginAt     *time.Time     `json:"last_login_at,omitempty" db:"last_login_at"`
-	Metadata        pq.StringArray `json:"metadata" db:"metadata"`
+	ID              uuid.UUID  `json:"id" db:"id"`
+	WalletAddress   string     `json:"wallet_address" db:"wallet_address"`
+	LineUserID      *string    `json:"line_user_id,omitempty" db:"line_user_id"`
+	LineDisplayName *string    `json:"line_display_name,omitempty" db:"line_display_name"`
+	LinePictureURL  *string    `json:"line_picture_url,omitempty" db:"line_picture_url"`
+	Email           *string    `json:"email,omitempty" db:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	KYCTier         int        `json:"kyc_tier" db:"kyc_tier"`
+	Status          string     `json:"status" db:"status"`
+	Role            string     `json:"role" db:"role"`
+	MFAEnabled      bool       `json:"mfa_enabled" db:"mfa_enabled"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	Metadata        JSONB      `json:"metadata" db:"metadata"`
 }
 
 type Session struct {
@@ -30,8 +46,10 @@ type Session struct {
 	IPAddress         *string    `json:"ip_address,omitempty" db:"ip_address"`
 	UserAgent         *string    `json:"user_agent,omitempty" db:"user_agent"`
 	DeviceFingerprint *string    `json:"device_fingerprint,omitempty" db:"device_fingerprint"`
+	Country           *string    `json:"country,omitempty" db:"country"`
 	ExpiresAt         time.Time  `json:"expires_at" db:"expires_at"`
 	RefreshExpiresAt  *time.Time `json:"refresh_expires_at,omitempty" db:"refresh_expires_at"`
 	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
 	LastUsedAt        time.Time  `json:"last_used_at" db:"last_used_at"`
-}
\ No newline at end of file
+	MFAVerifiedAt     *time.Time `json:"mfa_verified_at,omitempty" db:"mfa_verified_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Voucher statuses
+const (
+	VoucherIssued   = "issued"
+	VoucherRedeemed = "redeemed"
+	VoucherVoid     = "void"
+)
+
+// Voucher redeems a settled participation's order at the merchant's point
+// of sale. Code and QRPayload are only filled in for the participant; the
+// database keeps the code's hash. CampaignTitle and MerchantID come from the
+// campaign.
+type Voucher struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	ParticipationID uuid.UUID  `json:"participation_id" db:"participation_id"`
+	CampaignID      uuid.UUID  `json:"campaign_id" db:"campaign_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	Status          string     `json:"status" db:"status"`
+	Code            string     `json:"code,omitempty" db:"-"`
+	QRPayload       string     `json:"qr_payload,omitempty" db:"-"`
+	IssuedAt        time.Time  `json:"issued_at" db:"issued_at"`
+	RedeemedAt      *time.Time `json:"redeemed_at,omitempty" db:"redeemed_at"`
+	RedeemedBy      *uuid.UUID `json:"redeemed_by,omitempty" db:"redeemed_by"`
+	VoidedAt        *time.Time `json:"voided_at,omitempty" db:"voided_at"`
+	CampaignTitle   string     `json:"campaign_title" db:"campaign_title"`
+	MerchantID      *uuid.UUID `json:"merchant_id,omitempty" db:"merchant_id"`
+	DepositAmount   BigInt     `json:"deposit_amount" db:"deposit_amount"`
+}
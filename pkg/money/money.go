@@ -0,0 +1,302 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrDivisionByZero   = errors.New("division by zero")
+)
+
+// Currency describes how many decimals one unit is split into on-chain or at
+// the payment provider
+type Currency struct {
+	Code     string
+	Decimals int
+}
+
+var (
+	USDT = Currency{Code: "USDT", Decimals: 6}
+	KAIA = Currency{Code: "KAIA", Decimals: 18}
+	KRW  = Currency{Code: "KRW", Decimals: 0}
+	USD  = Currency{Code: "USD", Decimals: 2}
+)
+
+var currencies = map[string]Currency{
+	USDT.Code: USDT,
+	KAIA.Code: KAIA,
+	KRW.Code:  KRW,
+	USD.Code:  USD,
+}
+
+// LookupCurrency returns the currency registered under code
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+func (c Currency) String() string {
+	return c.Code
+}
+
+// scale returns 10^Decimals
+func (c Currency) scale() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.Decimals)), nil)
+}
+
+// Amount is an immutable quantity of a currency held in base units
+// (e.g. 5000000 for 5 USDT). Every operation returns a new Amount.
+type Amount struct {
+	units    *big.Int
+	currency Currency
+}
+
+// New returns an amount of units base units; nil is treated as zero
+func New(units *big.Int, c Currency) Amount {
+	a := Amount{units: new(big.Int), currency: c}
+	if units != nil {
+		a.units.Set(units)
+	}
+	return a
+}
+
+// FromInt64 returns an amount of units base units
+func FromInt64(units int64, c Currency) Amount {
+	return Amount{units: big.NewInt(units), currency: c}
+}
+
+// Zero returns a zero amount of c
+func Zero(c Currency) Amount {
+	return Amount{units: new(big.Int), currency: c}
+}
+
+// ParseUnits parses an integer string of base units ("5000000")
+func ParseUnits(s string, c Currency) (Amount, error) {
+	n, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return Amount{units: n, currency: c}, nil
+}
+
+// Parse parses a human readable decimal ("5", "5.00", "5.25 USDT") into base
+// units. A trailing currency code must match c, and more fractional digits
+// than c supports are rejected rather than rounded.
+func Parse(s string, c Currency) (Amount, error) {
+	str := strings.TrimSpace(s)
+	if fields := strings.Fields(str); len(fields) == 2 {
+		if !strings.EqualFold(fields[1], c.Code) {
+			return Amount{}, fmt.Errorf("%w: %s != %s", ErrCurrencyMismatch, fields[1], c.Code)
+		}
+		str = fields[0]
+	}
+
+	neg := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+
+	whole, frac, _ := strings.Cut(str, ".")
+	if whole == "" && frac == "" {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if len(frac) > c.Decimals {
+		return Amount{}, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidAmount, s, c.Decimals)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	digits := whole + frac + strings.Repeat("0", c.Decimals-len(frac))
+	if strings.ContainsAny(digits, "+-") {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return Amount{units: n, currency: c}, nil
+}
+
+// Units returns a copy of the amount in base units
+func (a Amount) Units() *big.Int {
+	if a.units == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.units)
+}
+
+// Currency returns the currency of the amount
+func (a Amount) Currency() Currency {
+	return a.currency
+}
+
+func (a Amount) int() *big.Int {
+	if a.units == nil {
+		return new(big.Int)
+	}
+	return a.units
+}
+
+// Sign returns -1, 0 or +1
+func (a Amount) Sign() int {
+	return a.int().Sign()
+}
+
+// IsZero reports whether the amount is zero
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (a Amount) IsPositive() bool {
+	return a.Sign() > 0
+}
+
+func (a Amount) same(b Amount) error {
+	if a.currency != b.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency, b.currency)
+	}
+	return nil
+}
+
+// Add returns a+b
+func (a Amount) Add(b Amount) (Amount, error) {
+	if err := a.same(b); err != nil {
+		return Amount{}, err
+	}
+	return Amount{units: new(big.Int).Add(a.int(), b.int()), currency: a.currency}, nil
+}
+
+// Sub returns a-b
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if err := a.same(b); err != nil {
+		return Amount{}, err
+	}
+	return Amount{units: new(big.Int).Sub(a.int(), b.int()), currency: a.currency}, nil
+}
+
+// Cmp compares a and b and returns -1, 0 or +1
+func (a Amount) Cmp(b Amount) (int, error) {
+	if err := a.same(b); err != nil {
+		return 0, err
+	}
+	return a.int().Cmp(b.int()), nil
+}
+
+// Mul returns a multiplied by n
+func (a Amount) Mul(n int64) Amount {
+	return Amount{units: new(big.Int).Mul(a.int(), big.NewInt(n)), currency: a.currency}
+}
+
+// MulBps returns a * bps / 10000, truncated toward zero so rebates and fees
+// never exceed what the contract pays out
+func (a Amount) MulBps(bps int) Amount {
+	out := new(big.Int).Mul(a.int(), big.NewInt(int64(bps)))
+	return Amount{units: out.Quo(out, big.NewInt(10000)), currency: a.currency}
+}
+
+// QuoRem returns how many whole times b fits into a and the remainder
+func (a Amount) QuoRem(b Amount) (*big.Int, Amount, error) {
+	if err := a.same(b); err != nil {
+		return nil, Amount{}, err
+	}
+	if b.IsZero() {
+		return nil, Amount{}, ErrDivisionByZero
+	}
+	q, r := new(big.Int).QuoRem(a.int(), b.int(), new(big.Int))
+	return q, Amount{units: r, currency: a.currency}, nil
+}
+
+// Decimal formats the amount with all of the currency's decimals
+// ("5.000000")
+func (a Amount) Decimal() string {
+	return a.format(a.currency.Decimals)
+}
+
+// Format formats the amount for display with at least minDecimals fraction
+// digits, keeping any further non-zero digits ("5.00", "5.000001")
+func (a Amount) Format(minDecimals int) string {
+	return a.format(minDecimals)
+}
+
+// String formats the amount for display ("5.00 USDT")
+func (a Amount) String() string {
+	return a.format(2) + " " + a.currency.Code
+}
+
+func (a Amount) format(minDecimals int) string {
+	d := a.currency.Decimals
+	abs := new(big.Int).Abs(a.int())
+
+	var whole, frac string
+	if d == 0 {
+		whole = abs.String()
+	} else {
+		q, r := new(big.Int).QuoRem(abs, a.currency.scale(), new(big.Int))
+		whole = q.String()
+		frac = fmt.Sprintf("%0*s", d, r.String())
+		frac = strings.TrimRight(frac, "0")
+	}
+	if minDecimals > d {
+		minDecimals = d
+	}
+	if len(frac) < minDecimals {
+		frac += strings.Repeat("0", minDecimals-len(frac))
+	}
+
+	out := whole
+	if frac != "" {
+		out += "." + frac
+	}
+	if a.Sign() < 0 {
+		out = "-" + out
+	}
+	return out
+}
+
+type amountJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+	Decimals int    `json:"decimals"`
+	Display  string `json:"display,omitempty"`
+}
+
+// MarshalJSON encodes base units as a string next to the currency and a
+// display string so clients never have to do the decimal shift themselves
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountJSON{
+		Amount:   a.int().String(),
+		Currency: a.currency.Code,
+		Decimals: a.currency.Decimals,
+		Display:  a.String(),
+	})
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var v amountJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	c, err := LookupCurrency(v.Currency)
+	if err != nil {
+		return err
+	}
+	parsed, err := ParseUnits(v.Amount, c)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
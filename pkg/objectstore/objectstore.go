@@ -0,0 +1,85 @@
+// Package objectstore writes files to object storage: S3 or any
+// S3-compatible service (GCS through its XML API with HMAC keys, MinIO), or
+// a local directory in development.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Drivers
+const (
+	DriverS3    = "s3"
+	DriverLocal = "local"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	Driver string `env:"OBJECT_STORE_DRIVER" default:"local"`
+	Bucket string `env:"OBJECT_STORE_BUCKET"`
+	// Endpoint is the S3 API host: storage.googleapis.com for GCS, host:port
+	// for MinIO
+	Endpoint  string `env:"OBJECT_STORE_ENDPOINT" default:"s3.amazonaws.com"`
+	Region    string `env:"OBJECT_STORE_REGION"`
+	AccessKey string `env:"OBJECT_STORE_ACCESS_KEY"`
+	SecretKey string `env:"OBJECT_STORE_SECRET_KEY" secret:"true"`
+	// Insecure talks plain HTTP, for a local MinIO
+	Insecure bool `env:"OBJECT_STORE_INSECURE"`
+	// Dir is the root of the local driver
+	Dir string `env:"OBJECT_STORE_DIR" default:"./data/objects"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	switch c.Driver {
+	case DriverS3:
+		if c.Bucket == "" {
+			return errors.New("OBJECT_STORE_BUCKET is required for the s3 driver")
+		}
+	case DriverLocal:
+	default:
+		return fmt.Errorf("unknown OBJECT_STORE_DRIVER %q", c.Driver)
+	}
+	return nil
+}
+
+// ErrNotFound is returned by Stat and Open for a missing object
+var ErrNotFound = errors.New("objectstore: object not found")
+
+// Store writes objects by key ("exports/campaigns/v1/dt=2024-01-02/...")
+type Store interface {
+	// Put writes r, of size bytes (-1 if unknown), to key, replacing any
+	// existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	// Stat returns the size of the object at key
+	Stat(ctx context.Context, key string) (int64, error)
+	// Open reads the object at key; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object at key, if there is one
+	Delete(ctx context.Context, key string) error
+	// URI addresses key for other tools: s3://, gs:// or file://
+	URI(key string) string
+}
+
+// Presigner is implemented by stores clients can upload to directly
+type Presigner interface {
+	// PresignPut returns a URL that accepts one PUT of the object at key
+	// until expires has passed
+	PresignPut(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// New returns the store selected by cfg.Driver
+func New(cfg Config) (Store, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Driver == DriverS3 {
+		return NewS3(cfg)
+	}
+	return NewLocal(cfg.Dir)
+}
@@ -0,0 +1,117 @@
+// Package onetime issues expiring single-use tokens (email verification
+// links, QR codes) stored in Redis. Only a hash of each token is stored, and
+// Consume deletes it atomically so a token can be redeemed exactly once.
+package onetime
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// KeyPrefix is prepended to every token key
+const KeyPrefix = "r2s:onetime"
+
+const (
+	// DefaultTokenBytes is the entropy of issued tokens (256 bits)
+	DefaultTokenBytes = 32
+	// MinTokenBytes is the least entropy WithSize accepts
+	MinTokenBytes = 16
+)
+
+// ErrInvalidToken is returned for unknown, expired or already used tokens
+var ErrInvalidToken = apperrors.Unauthorized("invalid or expired token")
+
+// Tokens issues and redeems tokens for one purpose, e.g. "email_verify"
+type Tokens struct {
+	client  redis.UniversalClient
+	purpose string
+	ttl     time.Duration
+	size    int
+	clock   clock.Clock
+}
+
+// Option customises New
+type Option func(*Tokens)
+
+// WithSize sets the token entropy in bytes; values below MinTokenBytes are
+// raised to it
+func WithSize(n int) Option {
+	return func(t *Tokens) { t.size = max(n, MinTokenBytes) }
+}
+
+// WithClock sets the clock used to report expiry times
+func WithClock(c clock.Clock) Option {
+	return func(t *Tokens) { t.clock = clock.OrSystem(c) }
+}
+
+// New returns tokens for purpose that expire after ttl
+func New(client redis.UniversalClient, purpose string, ttl time.Duration, opts ...Option) *Tokens {
+	t := &Tokens{
+		client:  client,
+		purpose: purpose,
+		ttl:     ttl,
+		size:    DefaultTokenBytes,
+		clock:   clock.System,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Issue creates a token bound to subject (a user id, email address, ...)
+func (t *Tokens) Issue(ctx context.Context, subject string) (string, time.Time, error) {
+	b := make([]byte, t.size)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate %s token: %w", t.purpose, err)
+	}
+	// URL-safe so the token fits in links and QR codes as is
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	expiresAt := t.clock.Now().Add(t.ttl)
+	if err := t.client.Set(ctx, t.key(token), subject, t.ttl).Err(); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store %s token: %w", t.purpose, err)
+	}
+	return token, expiresAt, nil
+}
+
+// Consume redeems token and returns its subject. Every later call with the
+// same token returns ErrInvalidToken.
+func (t *Tokens) Consume(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrInvalidToken
+	}
+
+	subject, err := t.client.GetDel(ctx, t.key(token)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrInvalidToken
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to redeem %s token: %w", t.purpose, err)
+	}
+	return subject, nil
+}
+
+// Revoke invalidates token without redeeming it
+func (t *Tokens) Revoke(ctx context.Context, token string) error {
+	if err := t.client.Del(ctx, t.key(token)).Err(); err != nil {
+		return fmt.Errorf("failed to revoke %s token: %w", t.purpose, err)
+	}
+	return nil
+}
+
+func (t *Tokens) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%s:%s:%s", KeyPrefix, t.purpose, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,145 @@
+// Package ginopenapi serves an openapi.Document from gin and validates
+// requests against it
+package ginopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/openapi"
+)
+
+// maxBody bounds the body the validator reads into memory
+const maxBody = 1 << 20
+
+// Handler serves doc as JSON. The document is encoded once; add every
+// route before calling it.
+func Handler(doc *openapi.Document) gin.HandlerFunc {
+	body, err := json.Marshal(doc)
+	return func(c *gin.Context) {
+		if err != nil {
+			c.AbortWithStatusJSON(apperrors.Response(apperrors.Internal(err)))
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// Validator rejects requests whose path parameters, query parameters or
+// JSON body do not match their documented operation with 422, listing the
+// invalid fields, and bodies over 1 MiB with 413. Routes that are not
+// documented pass through.
+func Validator(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		op := doc.Operation(c.Request.Method, c.FullPath())
+		if op == nil {
+			c.Next()
+			return
+		}
+		if err := validate(c, op); err != nil {
+			c.AbortWithStatusJSON(apperrors.Response(err))
+			return
+		}
+		c.Next()
+	}
+}
+
+func validate(c *gin.Context, op *openapi.Operation) error {
+	var fields []apperrors.FieldError
+	add := func(err error) {
+		var v *openapi.ValidationError
+		if errors.As(err, &v) {
+			fields = append(fields, apperrors.FieldError{Field: v.Field, Message: v.Message})
+		}
+	}
+
+	query := c.Request.URL.Query()
+	for _, p := range op.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw = c.Param(p.Name)
+			raw, present = strings.TrimPrefix(raw, "/"), raw != ""
+		case "query":
+			raw, present = query.Get(p.Name), query.Has(p.Name)
+		}
+		if !present {
+			if p.Required {
+				add(&openapi.ValidationError{Field: p.In + "." + p.Name, Message: "is required"})
+			}
+			continue
+		}
+		add(p.Schema.ValidateParam(p.In+"."+p.Name, raw))
+	}
+
+	if err := validateBody(c, op, add); err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return apperrors.Validation(fields...)
+	}
+	return nil
+}
+
+// validateBody reports the mismatches of the JSON body to add. It fails
+// only when the body cannot be read.
+func validateBody(c *gin.Context, op *openapi.Operation, add func(error)) error {
+	if op.RequestBody == nil || c.Request.Body == nil {
+		return nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBody+1))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return apperrors.PayloadTooLarge(tooLarge.Limit)
+	}
+	if err != nil {
+		return apperrors.InvalidArgument("Failed to read request body")
+	}
+	if len(raw) > maxBody {
+		return apperrors.PayloadTooLarge(maxBody)
+	}
+	// Put the body back for the handler
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if op.RequestBody.Required {
+			add(&openapi.ValidationError{Field: "body", Message: "is required"})
+		}
+		return nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		add(&openapi.ValidationError{Field: "body", Message: "must be valid JSON"})
+		return nil
+	}
+	for _, err := range media.Schema.ValidateAll("body", body) {
+		add(err)
+	}
+	return nil
+}
+
+// Undocumented lists the routes in routes that are missing from doc and
+// whose path starts with prefix, as "METHOD path", so drift between the
+// router and the spec shows up at startup
+func Undocumented(doc *openapi.Document, routes gin.RoutesInfo, prefix string) []string {
+	var missing []string
+	for _, r := range routes {
+		if strings.HasPrefix(r.Path, prefix) && !doc.Documented(r.Method, r.Path) {
+			missing = append(missing, r.Method+" "+r.Path)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,274 @@
+// Package openapi builds an OpenAPI 3.0 document from route annotations and
+// Go request types, so the published spec is generated from the same
+// definitions the server uses instead of a hand-maintained file, and
+// validates requests against it (see ginopenapi).
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.0.3"
+
+// bearerScheme is the security scheme name used by Route.Auth
+const bearerScheme = "bearerAuth"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	// operations indexes operations by method and router path (":id"
+	// syntax) for request validation
+	operations map[string]*Operation
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	OperationID string                `json:"operationId,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Route annotates one route. Query is a struct whose `form` tags name the
+// query parameters (as gin binds them); Body and Response are the JSON
+// request body and the "data" of a successful response. Each may be nil.
+type Route struct {
+	Summary     string
+	Description string
+	Tags        []string
+	// Auth marks the route as requiring a bearer token
+	Auth     bool
+	Query    interface{}
+	Body     interface{}
+	Response interface{}
+	// Paged adds the "pagination" block of list responses
+	Paged bool
+	// Flat puts the Response fields beside "success" instead of under
+	// "data", as auth-server answers
+	Flat bool
+	// Status is the success status; 0 means 200
+	Status int
+}
+
+// New returns an empty document. Every operation answers errors in the
+// shared {"success": false, "error", "code"} shape, with "reason" naming
+// catalogued failures and "details" listing the invalid fields of
+// VALIDATION_FAILED errors.
+func New(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{
+				"Error": {
+					Type:     TypeObject,
+					Required: []string{"success", "error", "code"},
+					Properties: map[string]*Schema{
+						"success": {Type: TypeBoolean},
+						"error":   {Type: TypeString},
+						"code":    {Type: TypeString},
+						"reason":  reasonSchema(),
+						"details": {
+							Type: TypeArray,
+							Items: &Schema{
+								Type:     TypeObject,
+								Required: []string{"field", "message"},
+								Properties: map[string]*Schema{
+									"field":   {Type: TypeString},
+									"message": {Type: TypeString},
+								},
+							},
+						},
+					},
+				},
+			},
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		operations: map[string]*Operation{},
+	}
+}
+
+// Add documents method on path, given in router syntax ("/campaigns/:id",
+// "/admin/*path")
+func (d *Document) Add(method, path string, r Route) {
+	method = strings.ToUpper(method)
+	specPath, params := convertPath(path)
+
+	op := &Operation{
+		Summary:     r.Summary,
+		Description: r.Description,
+		Tags:        r.Tags,
+		OperationID: operationID(method, path),
+		Responses: map[string]Response{
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(&Schema{Ref: "#/components/schemas/Error"}),
+			},
+		},
+	}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name: name, In: "path", Required: true, Schema: &Schema{Type: TypeString},
+		})
+	}
+	if r.Query != nil {
+		op.Parameters = append(op.Parameters, queryParameters(reflect.TypeOf(r.Query))...)
+	}
+	if r.Body != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(SchemaOf(r.Body))}
+	}
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = successResponse(r.Response, r.Paged, r.Flat)
+	if r.Auth {
+		op.Security = []map[string][]string{{bearerScheme: {}}}
+	}
+
+	item, ok := d.Paths[specPath]
+	if !ok {
+		item = PathItem{}
+		d.Paths[specPath] = item
+	}
+	item[strings.ToLower(method)] = op
+	d.operations[method+" "+path] = op
+}
+
+// Operation returns the operation for method and router path, or nil
+func (d *Document) Operation(method, path string) *Operation {
+	return d.operations[strings.ToUpper(method)+" "+path]
+}
+
+// Documented reports whether method and router path were added
+func (d *Document) Documented(method, path string) bool {
+	return d.Operation(method, path) != nil
+}
+
+// convertPath turns ":id" and "*path" segments into "{id}" and "{path}"
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, s := range segments {
+		if len(s) > 1 && (s[0] == ':' || s[0] == '*') {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// operationID derives a stable id such as "post_api_auth_verify"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, s := range strings.Split(path, "/") {
+		s = strings.TrimLeft(s, ":*")
+		if s == "" {
+			continue
+		}
+		b.WriteByte('_')
+		b.WriteString(strings.NewReplacer("-", "_", ".", "_").Replace(s))
+	}
+	return b.String()
+}
+
+// successResponse wraps data in the {"success": true, "data": ...} envelope,
+// or merges its fields into the envelope when flat
+func successResponse(data interface{}, paged, flat bool) Response {
+	envelope := &Schema{
+		Type:       TypeObject,
+		Properties: map[string]*Schema{"success": {Type: TypeBoolean}},
+	}
+	switch {
+	case data != nil && flat:
+		fields := SchemaOf(data)
+		envelope.Title = fields.Title
+		for name, prop := range fields.Properties {
+			envelope.Properties[name] = prop
+		}
+		envelope.Required = fields.Required
+	case data != nil:
+		envelope.Properties["data"] = SchemaOf(data)
+	}
+	if paged {
+		page := SchemaOf(pagination.Result{})
+		page.Title = "Pagination"
+		envelope.Properties["pagination"] = page
+	}
+	return Response{Description: "OK", Content: jsonContent(envelope)}
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// reasonSchema documents the error catalog of pkg/errors
+func reasonSchema() *Schema {
+	schema := &Schema{Type: TypeString, Description: "Catalogued failure; the message may change or be localized, the reason does not.\n"}
+	for _, e := range apperrors.Entries() {
+		schema.Enum = append(schema.Enum, string(e.Reason))
+		schema.Description += fmt.Sprintf("\n- %s (%s): %s", e.Reason, e.Code, e.Message)
+	}
+	return schema
+}
@@ -0,0 +1,256 @@
+package openapi
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema types
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeArray   = "array"
+	TypeObject  = "object"
+)
+
+// Schema is the subset of the OpenAPI schema object the generator emits
+// and the validator checks
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	typesMu sync.RWMutex
+	types   = map[reflect.Type]Schema{
+		reflect.TypeOf(time.Time{}): {Type: TypeString, Format: "date-time"},
+	}
+)
+
+// RegisterType sets the schema used for v's type, for types with custom JSON
+// encodings (big integers as strings, free-form JSON columns)
+func RegisterType(v interface{}, s Schema) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	types[reflect.TypeOf(v)] = s
+}
+
+func registered(t reflect.Type) (Schema, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	s, ok := types[t]
+	return s, ok
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// SchemaOf derives a schema from v's type the way encoding/json encodes it.
+// Struct fields use their json names; gin `binding` rules map onto the
+// schema: required, oneof (enum), min and max, uuid, email and url.
+func SchemaOf(v interface{}) *Schema {
+	return schemaOf(reflect.TypeOf(v))
+}
+
+func schemaOf(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+	if s, ok := registered(t); ok {
+		return &s
+	}
+	if t.Kind() == reflect.Ptr {
+		s := schemaOf(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	}
+	// uuid.UUID and similar encode as text
+	if t.Implements(textMarshaler) || reflect.PointerTo(t).Implements(textMarshaler) {
+		s := &Schema{Type: TypeString}
+		if t.PkgPath() == "github.com/google/uuid" && t.Name() == "UUID" {
+			s.Format = "uuid"
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeString, Format: "byte"}
+		}
+		return &Schema{Type: TypeArray, Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	// interface{} and anything else: any value
+	return &Schema{}
+}
+
+func structSchema(t reflect.Type) *Schema {
+	// The Go type name becomes the title, which SDK generators use to name
+	// the model
+	s := &Schema{Type: TypeObject, Title: title(t.Name()), Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		// Embedded structs without a json name are flattened, as in
+		// encoding/json
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := structSchema(f.Type)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := schemaOf(f.Type)
+		if applyBinding(prop, f.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		if doc := f.Tag.Get("doc"); doc != "" {
+			prop.Description = doc
+		}
+		s.Properties[name] = prop
+	}
+	return s
+}
+
+// title exports a type name: "verifyRequest" becomes "VerifyRequest"
+func title(name string) string {
+	if name == "" {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// queryParameters lists the `form`-tagged fields of a query struct,
+// including those of embedded structs
+func queryParameters(t reflect.Type) []Parameter {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			params = append(params, queryParameters(f.Type)...)
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := schemaOf(f.Type)
+		schema.Nullable = false
+		required := applyBinding(schema, f.Tag.Get("binding"))
+		params = append(params, Parameter{
+			Name:        name,
+			In:          "query",
+			Description: f.Tag.Get("doc"),
+			Required:    required,
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// applyBinding maps gin binding rules onto s and reports whether the field
+// is required. Unknown rules are ignored.
+func applyBinding(s *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			s.Enum = strings.Fields(arg)
+		case "min", "gte":
+			setBound(s, arg, true)
+		case "max", "lte":
+			setBound(s, arg, false)
+		case "uuid":
+			s.Format = "uuid"
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		}
+	}
+	return required
+}
+
+// setBound applies a min/max rule: a value bound for numbers, a length for
+// strings and an item count for arrays
+func setBound(s *Schema, arg string, lower bool) {
+	n, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return
+	}
+	switch s.Type {
+	case TypeInteger, TypeNumber:
+		if lower {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	case TypeString:
+		l := int(n)
+		if lower {
+			s.MinLength = &l
+		} else {
+			s.MaxLength = &l
+		}
+	case TypeArray:
+		l := int(n)
+		if lower {
+			s.MinItems = &l
+		} else {
+			s.MaxItems = &l
+		}
+	}
+}
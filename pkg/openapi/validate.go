@@ -0,0 +1,229 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError describes the first value that does not match its schema
+type ValidationError struct {
+	// Field is a path such as "body.items[2].id" or "query.limit"
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func invalid(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate checks v, as decoded by encoding/json into interface{}, against
+// s and returns the first mismatch. Properties the schema does not mention
+// are allowed, like gin's binding.
+func (s *Schema) Validate(field string, v interface{}) error {
+	if errs := s.ValidateAll(field, v); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll is Validate returning every mismatch, with object properties
+// in name order
+func (s *Schema) ValidateAll(field string, v interface{}) []*ValidationError {
+	var errs []*ValidationError
+	s.validate(field, v, func(err error) {
+		if err != nil {
+			errs = append(errs, err.(*ValidationError))
+		}
+	})
+	return errs
+}
+
+func (s *Schema) validate(field string, v interface{}, report func(error)) {
+	if s == nil || s.Ref != "" {
+		return
+	}
+	if v == nil {
+		if !s.Nullable && s.Type != "" {
+			report(invalid(field, "must not be null"))
+		}
+		return
+	}
+
+	switch s.Type {
+	case TypeString:
+		str, ok := v.(string)
+		if !ok {
+			report(invalid(field, "must be a string"))
+			return
+		}
+		report(s.validateString(field, str))
+	case TypeInteger, TypeNumber:
+		n, ok := v.(float64)
+		if !ok {
+			report(invalid(field, "must be a number"))
+			return
+		}
+		if s.Type == TypeInteger && n != math.Trunc(n) {
+			report(invalid(field, "must be an integer"))
+			return
+		}
+		report(s.validateNumber(field, n))
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			report(invalid(field, "must be a boolean"))
+		}
+	case TypeArray:
+		items, ok := v.([]interface{})
+		if !ok {
+			report(invalid(field, "must be an array"))
+			return
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			report(invalid(field, "must have at least %d items", *s.MinItems))
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			report(invalid(field, "must have at most %d items", *s.MaxItems))
+		}
+		for i, item := range items {
+			s.Items.validate(fmt.Sprintf("%s[%d]", field, i), item, report)
+		}
+	case TypeObject:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			report(invalid(field, "must be an object"))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				report(invalid(field+"."+name, "is required"))
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			prop.validate(field+"."+name, obj[name], report)
+		}
+	}
+}
+
+// ValidateParam checks a path or query parameter, which arrives as text
+func (s *Schema) ValidateParam(field, raw string) error {
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case TypeInteger:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return invalid(field, "must be an integer")
+		}
+		return s.validateNumber(field, float64(n))
+	case TypeNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return invalid(field, "must be a number")
+		}
+		return s.validateNumber(field, n)
+	case TypeBoolean:
+		if _, err := strconv.ParseBool(raw); err != nil {
+			return invalid(field, "must be a boolean")
+		}
+		return nil
+	case TypeString:
+		return s.validateString(field, raw)
+	}
+	return nil
+}
+
+func (s *Schema) validateString(field, v string) error {
+	if len(s.Enum) > 0 && !contains(s.Enum, v) {
+		return invalid(field, "must be one of %s", strings.Join(s.Enum, ", "))
+	}
+	if s.MinLength != nil && len(v) < *s.MinLength {
+		return invalid(field, "must be at least %d characters", *s.MinLength)
+	}
+	if s.MaxLength != nil && len(v) > *s.MaxLength {
+		return invalid(field, "must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err == nil && !re.MatchString(v) {
+			return invalid(field, "has an invalid format")
+		}
+	}
+
+	var err error
+	switch s.Format {
+	case "uuid":
+		_, err = uuid.Parse(v)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "email":
+		_, err = mail.ParseAddress(v)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(v); err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("not absolute")
+		}
+	}
+	if err != nil {
+		return invalid(field, "must be a valid %s", s.Format)
+	}
+	return nil
+}
+
+func (s *Schema) validateNumber(field string, n float64) error {
+	if s.Minimum != nil && n < *s.Minimum {
+		return invalid(field, "must be at least %v", *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return invalid(field, "must be at most %v", *s.Maximum)
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// patterns caches compiled schema patterns; schemas are built at startup,
+// so it only grows to the number of distinct patterns in the document
+var patterns sync.Map
+
+func compilePattern(p string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(p); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(p, re)
+	return re, nil
+}
@@ -0,0 +1,194 @@
+// Package outbox is the transactional outbox of core-server's domain events
+// (pkg/db/migrations/032_outbox.sql). Services append an event with the
+// transaction that makes the change, so a crash between the commit and a
+// side effect can no longer lose it: the event is stored exactly when the
+// change is. batch-server's relay publishes stored events to the Stream
+// Redis stream and marks them published.
+//
+// Delivery is at least once: a relay that dies after publishing but before
+// its commit publishes the batch again. Consumers drop repeats by event id.
+// Order is only kept per aggregate: its events are written under its row
+// lock, so they get increasing seqs and commit in that order. seq is
+// assigned at insert, not at commit, so across aggregates an event that
+// commits late can be published after events with higher seqs. Relays
+// running side by side skip each other's claimed events, so per-aggregate
+// order holds only while one relay runs at a time.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// Aggregate types
+const (
+	AggregateCampaign      = "campaign"
+	AggregateParticipation = "participation"
+	AggregatePayment       = "payment"
+)
+
+// Event types
+const (
+	CampaignCreated       = "campaign.created"
+	CampaignUpdated       = "campaign.updated"
+	CampaignStatusChanged = "campaign.status_changed"
+	CampaignSettled       = "campaign.settled"
+
+	ParticipationCreated         = "participation.created"
+	ParticipationUpdated         = "participation.updated"
+	ParticipationCancelRequested = "participation.cancel_requested"
+	ParticipationCancelled       = "participation.cancelled"
+	ParticipationRefunded        = "participation.refunded"
+
+	PaymentCreated         = "payment.created"
+	PaymentStatusChanged   = "payment.status_changed"
+	PaymentRefundRequested = "payment.refund_requested"
+)
+
+// Event is a domain event to append. Payload is marshalled to JSON.
+type Event struct {
+	Type          string
+	AggregateType string
+	AggregateID   string
+	Payload       interface{}
+}
+
+// Record is a stored event
+type Record struct {
+	Seq           int64      `db:"seq"`
+	ID            uuid.UUID  `db:"id"`
+	Type          string     `db:"type"`
+	AggregateType string     `db:"aggregate_type"`
+	AggregateID   string     `db:"aggregate_id"`
+	Payload       string     `db:"payload"`
+	ActorID       *string    `db:"actor_id"`
+	ActorType     string     `db:"actor_type"`
+	RequestID     *string    `db:"request_id"`
+	OccurredAt    time.Time  `db:"occurred_at"`
+	PublishedAt   *time.Time `db:"published_at"`
+}
+
+// Store writes and relays the outbox
+type Store struct {
+	db    *database.DB
+	clock clock.Clock
+}
+
+// NewStore returns a store on db
+func NewStore(db *database.DB, clk clock.Clock) *Store {
+	return &Store{db: db, clock: clock.OrSystem(clk)}
+}
+
+// Append stores e, attributed to the actor in ctx, inside tx, the
+// transaction that makes the change it describes
+func (s *Store) Append(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", e.Type, err)
+	}
+
+	actor := audit.ActorFrom(ctx)
+	query := `
+		INSERT INTO outbox_events (
+			id, type, aggregate_type, aggregate_id, payload,
+			actor_id, actor_type, request_id, occurred_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err = tx.ExecContext(ctx, query,
+		uuid.New(),
+		e.Type,
+		e.AggregateType,
+		e.AggregateID,
+		string(payload),
+		nullString(actor.ID),
+		actor.Type,
+		nullString(logger.RequestID(ctx)),
+		s.clock.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Relay claims up to limit unpublished events, lowest seq first, and marks
+// them published once publish returns nil, in one transaction: a failed
+// publish leaves them to the next call. Events another relay is claiming
+// are skipped. Returns how many were published.
+func (s *Store) Relay(ctx context.Context, limit int, publish func(context.Context, []Record) error) (int, error) {
+	var records []Record
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		query := `
+			WITH due AS (
+				SELECT seq FROM outbox_events
+				WHERE published_at IS NULL
+				ORDER BY seq
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			UPDATE outbox_events o
+			SET published_at = $1
+			FROM due
+			WHERE o.seq = due.seq
+			RETURNING o.*`
+
+		records = []Record{}
+		if err := tx.SelectContext(ctx, &records, query, s.clock.Now(), limit); err != nil {
+			return fmt.Errorf("failed to claim outbox events: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		// RETURNING has no order
+		sort.Slice(records, func(i, j int) bool { return records[i].Seq < records[j].Seq })
+		return publish(ctx, records)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// Prune deletes the events published before cutoff and returns how many
+func (s *Store) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox events: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Backlog returns the number of unpublished events and when the oldest of
+// them occurred, zero when there are none
+func (s *Store) Backlog(ctx context.Context) (int64, time.Time, error) {
+	var row struct {
+		Count  int64      `db:"count"`
+		Oldest *time.Time `db:"oldest"`
+	}
+	query := `SELECT COUNT(*) AS count, MIN(occurred_at) AS oldest FROM outbox_events WHERE published_at IS NULL`
+	if err := s.db.GetContext(ctx, &row, query); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to count outbox backlog: %w", err)
+	}
+	if row.Oldest == nil {
+		return row.Count, time.Time{}, nil
+	}
+	return row.Count, *row.Oldest, nil
+}
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Stream is the Redis stream events are published on. Each entry holds the
+// fields of Values; consumers read it with consumer groups and drop repeats
+// by the id field.
+const Stream = "r2s:events"
+
+// Values returns the stream entry fields of r
+func (r Record) Values() map[string]interface{} {
+	values := map[string]interface{}{
+		"id":             r.ID.String(),
+		"seq":            r.Seq,
+		"type":           r.Type,
+		"aggregate_type": r.AggregateType,
+		"aggregate_id":   r.AggregateID,
+		"payload":        r.Payload,
+		"actor_type":     r.ActorType,
+		"occurred_at":    r.OccurredAt.UTC().Format(time.RFC3339Nano),
+	}
+	if r.ActorID != nil {
+		values["actor_id"] = *r.ActorID
+	}
+	if r.RequestID != nil {
+		values["request_id"] = *r.RequestID
+	}
+	return values
+}
+
+// Publisher appends events to Stream, trimming it to about MaxLen entries
+type Publisher struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+// NewPublisher returns a publisher on client; maxLen 0 never trims
+func NewPublisher(client redis.UniversalClient, maxLen int64) *Publisher {
+	return &Publisher{client: client, maxLen: maxLen}
+}
+
+// Publish appends records to Stream in order in one pipeline; use it as the
+// publish function of Store.Relay
+func (p *Publisher) Publish(ctx context.Context, records []Record) error {
+	pipe := p.client.Pipeline()
+	for _, r := range records {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: Stream,
+			MaxLen: p.maxLen,
+			Approx: p.maxLen > 0,
+			Values: r.Values(),
+		})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", Stream, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package pagination defines the limit/offset window and cursor shared by
+// every list endpoint (REST in core-server and the gateway, gRPC in
+// query-server), along with the metadata returned alongside a page.
+package pagination
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+const (
+	// DefaultLimit applies when the caller sends no limit (or 0)
+	DefaultLimit = 20
+	// MaxLimit is the largest page any endpoint returns
+	MaxLimit = 100
+)
+
+// cursorPrefix versions the cursor encoding so it can change later without
+// misreading cursors already handed to clients
+const cursorPrefix = "o1:"
+
+// Page is a validated window into a list
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Result is the pagination metadata returned with a page
+type Result struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int64  `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// New validates limit and offset. A zero limit means DefaultLimit. A
+// non-empty cursor takes precedence over offset.
+func New(limit, offset int, cursor string) (Page, error) {
+	if cursor != "" {
+		o, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		offset = o
+	}
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 0 || limit > MaxLimit {
+		return Page{}, apperrors.Newf(apperrors.CodeInvalidArgument, "limit must be between 1 and %d", MaxLimit)
+	}
+	if offset < 0 {
+		return Page{}, apperrors.InvalidArgument("offset must not be negative")
+	}
+	return Page{Limit: limit, Offset: offset}, nil
+}
+
+// FromProto validates the limit, offset and cursor fields of a gRPC list
+// request
+func FromProto(limit, offset int32, cursor string) (Page, error) {
+	return New(int(limit), int(offset), cursor)
+}
+
+// Parse reads the limit, offset and cursor query parameters through get,
+// e.g. pagination.Parse(c.Query) with gin
+func Parse(get func(string) string) (Page, error) {
+	limit, err := atoi(get("limit"), "limit")
+	if err != nil {
+		return Page{}, err
+	}
+	offset, err := atoi(get("offset"), "offset")
+	if err != nil {
+		return Page{}, err
+	}
+	return New(limit, offset, get("cursor"))
+}
+
+// Result builds the response metadata for a page out of total items
+func (p Page) Result(total int64) Result {
+	return Result{
+		Limit:      p.Limit,
+		Offset:     p.Offset,
+		Total:      total,
+		NextCursor: p.NextCursor(total),
+	}
+}
+
+// NextCursor returns the cursor for the following page, or "" on the last
+// page
+func (p Page) NextCursor(total int64) string {
+	next := p.Offset + p.Limit
+	if int64(next) >= total {
+		return ""
+	}
+	return EncodeCursor(next)
+}
+
+// EncodeCursor returns an opaque cursor for offset. Clients must treat it
+// as a token and pass it back unchanged.
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor returns the offset held by a cursor from EncodeCursor
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return 0, apperrors.InvalidArgument("invalid cursor")
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(raw), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, apperrors.InvalidArgument("invalid cursor")
+	}
+	return offset, nil
+}
+
+func atoi(s, field string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, apperrors.InvalidArgument(field + " must be an integer")
+	}
+	return n, nil
+}
@@ -0,0 +1,82 @@
+// Package policy checks the terms of new campaigns against the platform's
+// campaign policies (see 031_campaign_policies.sql). Admins keep one policy
+// per merchant tier, the KYC tier of the merchant's user; each bounds the
+// max rebate rate, the length of the lock window and the merchant fee.
+//
+// Violations are catalogued errors naming the bound, so clients can tell a
+// policy rejection from malformed input.
+package policy
+
+import (
+	"fmt"
+	"time"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/validate"
+)
+
+// Terms are the campaign terms policies bound
+type Terms struct {
+	RMaxBps        int
+	MerchantFeeBps int
+	StartTime      time.Time
+	EndTime        time.Time
+}
+
+// Check returns the error of the first bound of p that t violates. A nil
+// policy allows any terms.
+func Check(p *models.CampaignPolicy, t Terms) error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxRMaxBps != nil && t.RMaxBps > *p.MaxRMaxBps {
+		return apperrors.Catalog(apperrors.ReasonPolicyRMaxBps, *p.MaxRMaxBps)
+	}
+	if p.MinLockHours != nil && t.EndTime.Sub(t.StartTime) < time.Duration(*p.MinLockHours)*time.Hour {
+		return apperrors.Catalog(apperrors.ReasonPolicyLockDuration, *p.MinLockHours)
+	}
+	minFee, maxFee := feeRange(p)
+	if t.MerchantFeeBps < minFee || t.MerchantFeeBps > maxFee {
+		return apperrors.Catalog(apperrors.ReasonPolicyFeeRange, t.MerchantFeeBps, minFee, maxFee)
+	}
+	return nil
+}
+
+// Validate checks a policy an admin sets: a known tier, bps within 0 and
+// 10000 and a fee range that is not empty
+func Validate(p *models.CampaignPolicy) error {
+	if p.MerchantTier < 0 || p.MerchantTier > models.MaxKYCTier {
+		return apperrors.InvalidArgument(fmt.Sprintf("tier must be between 0 and %d", models.MaxKYCTier))
+	}
+	for _, b := range []struct {
+		field string
+		bps   *int
+	}{{"maxRMaxBps", p.MaxRMaxBps}, {"minFeeBps", p.MinFeeBps}, {"maxFeeBps", p.MaxFeeBps}} {
+		if b.bps == nil {
+			continue
+		}
+		if err := validate.Bps(b.field, *b.bps); err != nil {
+			return err
+		}
+	}
+	if p.MinLockHours != nil && *p.MinLockHours < 0 {
+		return apperrors.InvalidArgument("minLockHours must not be negative")
+	}
+	if minFee, maxFee := feeRange(p); minFee > maxFee {
+		return apperrors.InvalidArgument("minFeeBps must not exceed maxFeeBps")
+	}
+	return nil
+}
+
+// feeRange returns p's fee bounds, open ends being 0 and 10000
+func feeRange(p *models.CampaignPolicy) (int, int) {
+	minFee, maxFee := 0, 10000
+	if p.MinFeeBps != nil {
+		minFee = *p.MinFeeBps
+	}
+	if p.MaxFeeBps != nil {
+		maxFee = *p.MaxFeeBps
+	}
+	return minFee, maxFee
+}
@@ -22,12 +22,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 캠페인 목록 정렬 기준
+type CampaignSort int32
+
+const (
+	CampaignSort_CAMPAIGN_SORT_NEWEST      CampaignSort = 0 // 최신 등록순
+	CampaignSort_CAMPAIGN_SORT_ENDING_SOON CampaignSort = 1 // 종료가 가까운 순 (이미 끝난 캠페인은 마지막)
+	CampaignSort_CAMPAIGN_SORT_MOST_FUNDED CampaignSort = 2 // 예치 금액이 많은 순
+	CampaignSort_CAMPAIGN_SORT_CATEGORY    CampaignSort = 3 // 카테고리 표시 순서, 같은 카테고리 안에서는 최신순 (카테고리 없는 캠페인은 마지막)
+)
+
+// Enum value maps for CampaignSort.
+var (
+	CampaignSort_name = map[int32]string{
+		0: "CAMPAIGN_SORT_NEWEST",
+		1: "CAMPAIGN_SORT_ENDING_SOON",
+		2: "CAMPAIGN_SORT_MOST_FUNDED",
+		3: "CAMPAIGN_SORT_CATEGORY",
+	}
+	CampaignSort_value = map[string]int32{
+		"CAMPAIGN_SORT_NEWEST":      0,
+		"CAMPAIGN_SORT_ENDING_SOON": 1,
+		"CAMPAIGN_SORT_MOST_FUNDED": 2,
+		"CAMPAIGN_SORT_CATEGORY":    3,
+	}
+)
+
+func (x CampaignSort) Enum() *CampaignSort {
+	p := new(CampaignSort)
+	*p = x
+	return p
+}
+
+func (x CampaignSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CampaignSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_query_campaigns_proto_enumTypes[0].Descriptor()
+}
+
+func (CampaignSort) Type() protoreflect.EnumType {
+	return &file_proto_query_campaigns_proto_enumTypes[0]
+}
+
+func (x CampaignSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CampaignSort.Descriptor instead.
+func (CampaignSort) EnumDescriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{0}
+}
+
 // 캠페인 목록 조회 요청
 type GetCampaignsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`   // 페이지 크기 (기본값: 10)
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // 오프셋 (기본값: 0)
-	State         int32                  `protobuf:"varint,3,opt,name=state,proto3" json:"state,omitempty"`   // 캠페인 상태 필터 (옵션, 0=전체)
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`                                     // 페이지 크기 (기본값: 20, 최대: 100)
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`                                   // 오프셋 (기본값: 0)
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                                    // 캠페인 상태 필터 (옵션, 빈 값=전체)
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                                    // 이전 응답의 next_cursor (지정 시 offset 무시)
+	MerchantId    string                 `protobuf:"bytes,5,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`          // 머천트 필터 (옵션, 빈 값=전체)
+	Statuses      []string               `protobuf:"bytes,6,rep,name=statuses,proto3" json:"statuses,omitempty"`                                // 상태 목록 필터 (옵션, status와 함께 지정하면 합쳐서 적용)
+	LockFrom      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=lock_from,json=lockFrom,proto3" json:"lock_from,omitempty"`                // 기간(start_time~end_time)이 이 시각 이후에 끝나는 캠페인 (옵션)
+	LockTo        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=lock_to,json=lockTo,proto3" json:"lock_to,omitempty"`                      // 기간이 이 시각 이전에 시작하는 캠페인 (옵션)
+	MinBasePrice  string                 `protobuf:"bytes,9,opt,name=min_base_price,json=minBasePrice,proto3" json:"min_base_price,omitempty"`  // 최소 기본 가격 (옵션, 소수 문자열)
+	MaxBasePrice  string                 `protobuf:"bytes,10,opt,name=max_base_price,json=maxBasePrice,proto3" json:"max_base_price,omitempty"` // 최대 기본 가격 (옵션, 소수 문자열)
+	Q             string                 `protobuf:"bytes,11,opt,name=q,proto3" json:"q,omitempty"`                                             // 제목/메타데이터 텍스트 검색 (옵션, 대소문자 무관)
+	Sort          CampaignSort           `protobuf:"varint,12,opt,name=sort,proto3,enum=query.CampaignSort" json:"sort,omitempty"`              // 정렬 기준 (기본값: 최신순)
+	SkipCount     bool                   `protobuf:"varint,13,opt,name=skip_count,json=skipCount,proto3" json:"skip_count,omitempty"`           // 총 개수 조회 생략 (total_count는 -1, next_cursor는 다음 행 유무로 결정)
+	Ids           []string               `protobuf:"bytes,14,rep,name=ids,proto3" json:"ids,omitempty"`                                         // 캠페인 id 목록 필터 (옵션, 배치 조회용으로 limit 이하)
+	Category      string                 `protobuf:"bytes,15,opt,name=category,proto3" json:"category,omitempty"`                               // 카테고리 슬러그 필터 (옵션, 빈 값=전체)
+	Tags          []string               `protobuf:"bytes,16,rep,name=tags,proto3" json:"tags,omitempty"`                                       // 태그 필터 (옵션, 모든 태그를 가진 캠페인)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -76,18 +142,110 @@ func (x *GetCampaignsRequest) GetOffset() int32 {
 	return 0
 }
 
-func (x *GetCampaignsRequest) GetState() int32 {
+func (x *GetCampaignsRequest) GetStatus() string {
 	if x != nil {
-		return x.State
+		return x.Status
 	}
-	return 0
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *GetCampaignsRequest) GetLockFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LockFrom
+	}
+	return nil
+}
+
+func (x *GetCampaignsRequest) GetLockTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LockTo
+	}
+	return nil
+}
+
+func (x *GetCampaignsRequest) GetMinBasePrice() string {
+	if x != nil {
+		return x.MinBasePrice
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetMaxBasePrice() string {
+	if x != nil {
+		return x.MaxBasePrice
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetQ() string {
+	if x != nil {
+		return x.Q
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetSort() CampaignSort {
+	if x != nil {
+		return x.Sort
+	}
+	return CampaignSort_CAMPAIGN_SORT_NEWEST
+}
+
+func (x *GetCampaignsRequest) GetSkipCount() bool {
+	if x != nil {
+		return x.SkipCount
+	}
+	return false
+}
+
+func (x *GetCampaignsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *GetCampaignsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *GetCampaignsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
 }
 
 // 캠페인 목록 조회 응답
 type GetCampaignsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Campaigns     []*Campaign            `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
-	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // 전체 개수 (skip_count 요청이면 -1)
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`  // 다음 페이지 커서 (마지막 페이지면 빈 값)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,28 +294,39 @@ func (x *GetCampaignsResponse) GetTotalCount() int64 {
 	return 0
 }
 
-// 특정 캠페인 조회 요청
-type GetCampaignRequest struct {
+func (x *GetCampaignsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 캠페인 검색 요청
+type SearchCampaignsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    int64                  `protobuf:"varint,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Q             string                 `protobuf:"bytes,1,opt,name=q,proto3" json:"q,omitempty"` // 검색어 (단어마다 접두사 일치, 모든 단어 포함)
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Statuses      []string               `protobuf:"bytes,5,rep,name=statuses,proto3" json:"statuses,omitempty"` // 상태 목록 필터 (옵션)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCampaignRequest) Reset() {
-	*x = GetCampaignRequest{}
+func (x *SearchCampaignsRequest) Reset() {
+	*x = SearchCampaignsRequest{}
 	mi := &file_proto_query_campaigns_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignRequest) String() string {
+func (x *SearchCampaignsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignRequest) ProtoMessage() {}
+func (*SearchCampaignsRequest) ProtoMessage() {}
 
-func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
+func (x *SearchCampaignsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_query_campaigns_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -169,41 +338,70 @@ func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
-func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*SearchCampaignsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetCampaignRequest) GetCampaignId() int64 {
+func (x *SearchCampaignsRequest) GetQ() string {
 	if x != nil {
-		return x.CampaignId
+		return x.Q
+	}
+	return ""
+}
+
+func (x *SearchCampaignsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
 	}
 	return 0
 }
 
-// 특정 캠페인 조회 응답
-type GetCampaignResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
-	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+func (x *SearchCampaignsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchCampaignsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchCampaignsRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// 캠페인 검색 응답 (관련도 순)
+type SearchCampaignsResponse struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Results       []*CampaignSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	TotalCount    int64                   `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor    string                  `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCampaignResponse) Reset() {
-	*x = GetCampaignResponse{}
+func (x *SearchCampaignsResponse) Reset() {
+	*x = SearchCampaignsResponse{}
 	mi := &file_proto_query_campaigns_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignResponse) String() string {
+func (x *SearchCampaignsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignResponse) ProtoMessage() {}
+func (*SearchCampaignsResponse) ProtoMessage() {}
 
-func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
+func (x *SearchCampaignsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_query_campaigns_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -215,61 +413,57 @@ func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
-func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*SearchCampaignsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetCampaignResponse) GetCampaign() *Campaign {
+func (x *SearchCampaignsResponse) GetResults() []*CampaignSearchResult {
 	if x != nil {
-		return x.Campaign
+		return x.Results
 	}
 	return nil
 }
 
-func (x *GetCampaignResponse) GetFound() bool {
+func (x *SearchCampaignsResponse) GetTotalCount() int64 {
 	if x != nil {
-		return x.Found
+		return x.TotalCount
 	}
-	return false
+	return 0
 }
 
-// 캠페인 데이터 구조
-type Campaign struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Address        string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // hex string으로 변환된 주소
-	MerchantId     int64                  `protobuf:"varint,3,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
-	MerchantName   string                 `protobuf:"bytes,4,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"` // JOIN으로 가져온 merchant 이름
-	BasePrice      string                 `protobuf:"bytes,5,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`          // NUMERIC을 string으로 (정밀도 보장)
-	MinQty         int64                  `protobuf:"varint,6,opt,name=min_qty,json=minQty,proto3" json:"min_qty,omitempty"`
-	LockStart      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=lock_start,json=lockStart,proto3" json:"lock_start,omitempty"`
-	LockEnd        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=lock_end,json=lockEnd,proto3" json:"lock_end,omitempty"`
-	RmaxBps        int32                  `protobuf:"varint,9,opt,name=rmax_bps,json=rmaxBps,proto3" json:"rmax_bps,omitempty"`
-	SavefloorBps   int32                  `protobuf:"varint,10,opt,name=savefloor_bps,json=savefloorBps,proto3" json:"savefloor_bps,omitempty"`
-	MerchantFeeBps int32                  `protobuf:"varint,11,opt,name=merchant_fee_bps,json=merchantFeeBps,proto3" json:"merchant_fee_bps,omitempty"`
-	OpsFeeBps      int32                  `protobuf:"varint,12,opt,name=ops_fee_bps,json=opsFeeBps,proto3" json:"ops_fee_bps,omitempty"`
-	State          int32                  `protobuf:"varint,13,opt,name=state,proto3" json:"state,omitempty"`
-	MetadataUri    string                 `protobuf:"bytes,14,opt,name=metadata_uri,json=metadataUri,proto3" json:"metadata_uri,omitempty"`
-	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *SearchCampaignsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
 }
 
-func (x *Campaign) Reset() {
-	*x = Campaign{}
+// 캠페인 검색 결과
+type CampaignSearchResult struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Campaign           *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Rank               float32                `protobuf:"fixed32,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	TitleSnippet       string                 `protobuf:"bytes,3,opt,name=title_snippet,json=titleSnippet,proto3" json:"title_snippet,omitempty"`                   // 일치한 단어를 <mark>로 감싼 제목 (HTML 이스케이프됨)
+	DescriptionSnippet string                 `protobuf:"bytes,4,opt,name=description_snippet,json=descriptionSnippet,proto3" json:"description_snippet,omitempty"` // 일치한 단어 주변의 설명 일부 (HTML 이스케이프됨)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CampaignSearchResult) Reset() {
+	*x = CampaignSearchResult{}
 	mi := &file_proto_query_campaigns_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Campaign) String() string {
+func (x *CampaignSearchResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Campaign) ProtoMessage() {}
+func (*CampaignSearchResult) ProtoMessage() {}
 
-func (x *Campaign) ProtoReflect() protoreflect.Message {
+func (x *CampaignSearchResult) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_query_campaigns_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -281,159 +475,1700 @@ func (x *Campaign) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Campaign.ProtoReflect.Descriptor instead.
-func (*Campaign) Descriptor() ([]byte, []int) {
+// Deprecated: Use CampaignSearchResult.ProtoReflect.Descriptor instead.
+func (*CampaignSearchResult) Descriptor() ([]byte, []int) {
 	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Campaign) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *Campaign) GetAddress() string {
+func (x *CampaignSearchResult) GetCampaign() *Campaign {
 	if x != nil {
-		return x.Address
+		return x.Campaign
 	}
-	return ""
+	return nil
 }
 
-func (x *Campaign) GetMerchantId() int64 {
+func (x *CampaignSearchResult) GetRank() float32 {
 	if x != nil {
-		return x.MerchantId
+		return x.Rank
 	}
 	return 0
 }
 
-func (x *Campaign) GetMerchantName() string {
+func (x *CampaignSearchResult) GetTitleSnippet() string {
 	if x != nil {
-		return x.MerchantName
+		return x.TitleSnippet
 	}
 	return ""
 }
 
-func (x *Campaign) GetBasePrice() string {
+func (x *CampaignSearchResult) GetDescriptionSnippet() string {
 	if x != nil {
-		return x.BasePrice
+		return x.DescriptionSnippet
 	}
 	return ""
 }
 
-func (x *Campaign) GetMinQty() int64 {
-	if x != nil {
-		return x.MinQty
-	}
-	return 0
+// 캠페인 스트리밍 요청
+type StreamCampaignsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`                       // 상태 목록 필터 (옵션)
+	MerchantId    string                 `protobuf:"bytes,2,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"` // 머천트 필터 (옵션, 빈 값=전체)
+	AfterId       string                 `protobuf:"bytes,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`          // 이 id 다음부터 전송 (끊긴 스트림 이어받기, 빈 값=처음부터)
+	BatchSize     int32                  `protobuf:"varint,4,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`   // 배치 크기 (기본값: 500, 최대: 1000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamCampaignsRequest) Reset() {
+	*x = StreamCampaignsRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Campaign) GetLockStart() *timestamppb.Timestamp {
+func (*StreamCampaignsRequest) ProtoMessage() {}
+
+func (x *StreamCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[5]
 	if x != nil {
-		return x.LockStart
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Campaign) GetLockEnd() *timestamppb.Timestamp {
+// Deprecated: Use StreamCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*StreamCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamCampaignsRequest) GetStatuses() []string {
 	if x != nil {
-		return x.LockEnd
+		return x.Statuses
 	}
 	return nil
 }
 
-func (x *Campaign) GetRmaxBps() int32 {
+func (x *StreamCampaignsRequest) GetMerchantId() string {
 	if x != nil {
-		return x.RmaxBps
+		return x.MerchantId
 	}
-	return 0
+	return ""
 }
 
-func (x *Campaign) GetSavefloorBps() int32 {
+func (x *StreamCampaignsRequest) GetAfterId() string {
 	if x != nil {
-		return x.SavefloorBps
+		return x.AfterId
 	}
-	return 0
+	return ""
 }
 
-func (x *Campaign) GetMerchantFeeBps() int32 {
+func (x *StreamCampaignsRequest) GetBatchSize() int32 {
 	if x != nil {
-		return x.MerchantFeeBps
+		return x.BatchSize
 	}
 	return 0
 }
 
-func (x *Campaign) GetOpsFeeBps() int32 {
+// 캠페인 스트리밍 배치
+type CampaignBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaigns     []*Campaign            `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
+	LastId        string                 `protobuf:"bytes,2,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"` // 배치의 마지막 id (이어받을 때 after_id로 전달)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CampaignBatch) Reset() {
+	*x = CampaignBatch{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CampaignBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CampaignBatch) ProtoMessage() {}
+
+func (x *CampaignBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[6]
 	if x != nil {
-		return x.OpsFeeBps
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Campaign) GetState() int32 {
+// Deprecated: Use CampaignBatch.ProtoReflect.Descriptor instead.
+func (*CampaignBatch) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CampaignBatch) GetCampaigns() []*Campaign {
 	if x != nil {
-		return x.State
+		return x.Campaigns
 	}
-	return 0
+	return nil
 }
 
-func (x *Campaign) GetMetadataUri() string {
+func (x *CampaignBatch) GetLastId() string {
 	if x != nil {
-		return x.MetadataUri
+		return x.LastId
 	}
 	return ""
 }
 
-func (x *Campaign) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
+// 캠페인 통계 조회 요청
+type GetCampaignStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"` // 모집 추이 기간 (일, 기본값: 30, 최대: 180)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var File_proto_query_campaigns_proto protoreflect.FileDescriptor
+func (x *GetCampaignStatsRequest) Reset() {
+	*x = GetCampaignStatsRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-const file_proto_query_campaigns_proto_rawDesc = "" +
-	"\n" +
-	"\x1bproto/query/campaigns.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\"Y\n" +
-	"\x13GetCampaignsRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05state\x18\x03 \x01(\x05R\x05state\"f\n" +
-	"\x14GetCampaignsResponse\x12-\n" +
-	"\tcampaigns\x18\x01 \x03(\v2\x0f.query.CampaignR\tcampaigns\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x03R\n" +
-	"totalCount\"5\n" +
-	"\x12GetCampaignRequest\x12\x1f\n" +
-	"\vcampaign_id\x18\x01 \x01(\x03R\n" +
-	"campaignId\"X\n" +
+func (x *GetCampaignStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignStatsRequest) ProtoMessage() {}
+
+func (x *GetCampaignStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetCampaignStatsRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *GetCampaignStatsRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+// 캠페인 통계 조회 응답
+type GetCampaignStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *CampaignStats         `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"` // 캠페인 존재 여부 (집계 전이면 통계는 0)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignStatsResponse) Reset() {
+	*x = GetCampaignStatsResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignStatsResponse) ProtoMessage() {}
+
+func (x *GetCampaignStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetCampaignStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetCampaignStatsResponse) GetStats() *CampaignStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetCampaignStatsResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 캠페인 참여 통계 (마지막 집계 시점 기준)
+type CampaignStats struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId         string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	ParticipantCount   int64                  `protobuf:"varint,2,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"` // 참여 인원 (취소 포함)
+	FundedCount        int64                  `protobuf:"varint,3,opt,name=funded_count,json=fundedCount,proto3" json:"funded_count,omitempty"`                // 예치 유지 인원 (취소/환불 제외)
+	CancelledCount     int64                  `protobuf:"varint,4,opt,name=cancelled_count,json=cancelledCount,proto3" json:"cancelled_count,omitempty"`
+	TotalDeposit       string                 `protobuf:"bytes,5,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`                     // 예치 유지 금액 합계 (USDT 소수 문자열)
+	AverageDeposit     string                 `protobuf:"bytes,6,opt,name=average_deposit,json=averageDeposit,proto3" json:"average_deposit,omitempty"`               // 예치 유지 인원당 평균 예치금
+	CancellationRate   float64                `protobuf:"fixed64,7,opt,name=cancellation_rate,json=cancellationRate,proto3" json:"cancellation_rate,omitempty"`       // 취소 인원 / 참여 인원 (0~1)
+	ProjectedRebateMin string                 `protobuf:"bytes,8,opt,name=projected_rebate_min,json=projectedRebateMin,proto3" json:"projected_rebate_min,omitempty"` // 평균 예치금 기준 참여자당 예상 리베이트 (save_floor_bps)
+	ProjectedRebateMax string                 `protobuf:"bytes,9,opt,name=projected_rebate_max,json=projectedRebateMax,proto3" json:"projected_rebate_max,omitempty"` // 평균 예치금 기준 참여자당 예상 리베이트 (r_max_bps)
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                             // 마지막 집계 시각 (집계 전이면 비어 있음)
+	Funding            []*FundingPoint        `protobuf:"bytes,11,rep,name=funding,proto3" json:"funding,omitempty"`                                                  // 일별 모집 추이 (오래된 날부터)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CampaignStats) Reset() {
+	*x = CampaignStats{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CampaignStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CampaignStats) ProtoMessage() {}
+
+func (x *CampaignStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CampaignStats.ProtoReflect.Descriptor instead.
+func (*CampaignStats) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CampaignStats) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *CampaignStats) GetParticipantCount() int64 {
+	if x != nil {
+		return x.ParticipantCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetFundedCount() int64 {
+	if x != nil {
+		return x.FundedCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetCancelledCount() int64 {
+	if x != nil {
+		return x.CancelledCount
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetTotalDeposit() string {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return ""
+}
+
+func (x *CampaignStats) GetAverageDeposit() string {
+	if x != nil {
+		return x.AverageDeposit
+	}
+	return ""
+}
+
+func (x *CampaignStats) GetCancellationRate() float64 {
+	if x != nil {
+		return x.CancellationRate
+	}
+	return 0
+}
+
+func (x *CampaignStats) GetProjectedRebateMin() string {
+	if x != nil {
+		return x.ProjectedRebateMin
+	}
+	return ""
+}
+
+func (x *CampaignStats) GetProjectedRebateMax() string {
+	if x != nil {
+		return x.ProjectedRebateMax
+	}
+	return ""
+}
+
+func (x *CampaignStats) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *CampaignStats) GetFunding() []*FundingPoint {
+	if x != nil {
+		return x.Funding
+	}
+	return nil
+}
+
+// 하루의 모집 현황 (그날 마지막 집계 기준)
+type FundingPoint struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Day              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"` // UTC 날짜의 0시
+	TotalDeposit     string                 `protobuf:"bytes,2,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`
+	ParticipantCount int64                  `protobuf:"varint,3,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"`
+	FundedCount      int64                  `protobuf:"varint,4,opt,name=funded_count,json=fundedCount,proto3" json:"funded_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *FundingPoint) Reset() {
+	*x = FundingPoint{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FundingPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FundingPoint) ProtoMessage() {}
+
+func (x *FundingPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FundingPoint.ProtoReflect.Descriptor instead.
+func (*FundingPoint) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FundingPoint) GetDay() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Day
+	}
+	return nil
+}
+
+func (x *FundingPoint) GetTotalDeposit() string {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return ""
+}
+
+func (x *FundingPoint) GetParticipantCount() int64 {
+	if x != nil {
+		return x.ParticipantCount
+	}
+	return 0
+}
+
+func (x *FundingPoint) GetFundedCount() int64 {
+	if x != nil {
+		return x.FundedCount
+	}
+	return 0
+}
+
+// 인기 캠페인 조회 요청
+type GetTrendingCampaignsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`                                // 최대 개수 (기본값: 10, 최대: 50)
+	WindowHours   int32                  `protobuf:"varint,2,opt,name=window_hours,json=windowHours,proto3" json:"window_hours,omitempty"` // 참여 속도를 보는 기간 (시간, 기본값: 24, 최대: 168)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrendingCampaignsRequest) Reset() {
+	*x = GetTrendingCampaignsRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrendingCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrendingCampaignsRequest) ProtoMessage() {}
+
+func (x *GetTrendingCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrendingCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*GetTrendingCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTrendingCampaignsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTrendingCampaignsRequest) GetWindowHours() int32 {
+	if x != nil {
+		return x.WindowHours
+	}
+	return 0
+}
+
+// 인기 캠페인 조회 응답 (점수 순)
+type GetTrendingCampaignsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaigns     []*TrendingCampaign    `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrendingCampaignsResponse) Reset() {
+	*x = GetTrendingCampaignsResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrendingCampaignsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrendingCampaignsResponse) ProtoMessage() {}
+
+func (x *GetTrendingCampaignsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrendingCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*GetTrendingCampaignsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetTrendingCampaignsResponse) GetCampaigns() []*TrendingCampaign {
+	if x != nil {
+		return x.Campaigns
+	}
+	return nil
+}
+
+// 인기 캠페인
+type TrendingCampaign struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	RecentJoins   int64                  `protobuf:"varint,2,opt,name=recent_joins,json=recentJoins,proto3" json:"recent_joins,omitempty"` // 기간 안의 참여 수 (취소/환불 제외)
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`                               // 최근 참여일수록 가중치를 크게 준 참여 수 (기간 시작 시점 0 ~ 현재 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrendingCampaign) Reset() {
+	*x = TrendingCampaign{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrendingCampaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrendingCampaign) ProtoMessage() {}
+
+func (x *TrendingCampaign) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrendingCampaign.ProtoReflect.Descriptor instead.
+func (*TrendingCampaign) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TrendingCampaign) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *TrendingCampaign) GetRecentJoins() int64 {
+	if x != nil {
+		return x.RecentJoins
+	}
+	return 0
+}
+
+func (x *TrendingCampaign) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// 추천 캠페인 조회 요청
+type GetRecommendedCampaignsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 최대 개수 (기본값: 10, 최대: 50)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecommendedCampaignsRequest) Reset() {
+	*x = GetRecommendedCampaignsRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecommendedCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecommendedCampaignsRequest) ProtoMessage() {}
+
+func (x *GetRecommendedCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecommendedCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*GetRecommendedCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetRecommendedCampaignsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRecommendedCampaignsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// 추천 캠페인 조회 응답 (추천 순, 이미 참여한 캠페인 제외)
+type GetRecommendedCampaignsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaigns     []*RecommendedCampaign `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecommendedCampaignsResponse) Reset() {
+	*x = GetRecommendedCampaignsResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecommendedCampaignsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecommendedCampaignsResponse) ProtoMessage() {}
+
+func (x *GetRecommendedCampaignsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecommendedCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*GetRecommendedCampaignsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetRecommendedCampaignsResponse) GetCampaigns() []*RecommendedCampaign {
+	if x != nil {
+		return x.Campaigns
+	}
+	return nil
+}
+
+// 추천 캠페인
+type RecommendedCampaign struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // 추천 이유: merchant(참여했던 머천트), category(참여했던 카테고리), trending(최근 인기), new(최신)
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"` // 참여 이력과의 관련도 (머천트 참여 1건당 2, 카테고리 참여 1건당 1)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendedCampaign) Reset() {
+	*x = RecommendedCampaign{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendedCampaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendedCampaign) ProtoMessage() {}
+
+func (x *RecommendedCampaign) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendedCampaign.ProtoReflect.Descriptor instead.
+func (*RecommendedCampaign) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RecommendedCampaign) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *RecommendedCampaign) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RecommendedCampaign) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// 특정 캠페인 조회 요청
+type GetCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignRequest) Reset() {
+	*x = GetCampaignRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignRequest) ProtoMessage() {}
+
+func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetCampaignRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+// 특정 캠페인 조회 응답
+type GetCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignResponse) Reset() {
+	*x = GetCampaignResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignResponse) ProtoMessage() {}
+
+func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
+func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *GetCampaignResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 캠페인 데이터 구조 (campaigns, pkg/models.Campaign). 금액은 USDT 소수 문자열입니다.
+type Campaign struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChainAddress         string                 `protobuf:"bytes,2,opt,name=chain_address,json=chainAddress,proto3" json:"chain_address,omitempty"` // EIP-55 체크섬 주소 (배포 전이면 빈 값)
+	MerchantId           string                 `protobuf:"bytes,3,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	MerchantName         string                 `protobuf:"bytes,4,opt,name=merchant_name,json=merchantName,proto3" json:"merchant_name,omitempty"` // JOIN으로 가져온 merchants.business_name (등록 전이면 빈 값)
+	BasePrice            string                 `protobuf:"bytes,5,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`
+	MinQty               int64                  `protobuf:"varint,6,opt,name=min_qty,json=minQty,proto3" json:"min_qty,omitempty"`
+	StartTime            *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	RMaxBps              int32                  `protobuf:"varint,9,opt,name=r_max_bps,json=rMaxBps,proto3" json:"r_max_bps,omitempty"`
+	SaveFloorBps         int32                  `protobuf:"varint,10,opt,name=save_floor_bps,json=saveFloorBps,proto3" json:"save_floor_bps,omitempty"`
+	MerchantFeeBps       int32                  `protobuf:"varint,11,opt,name=merchant_fee_bps,json=merchantFeeBps,proto3" json:"merchant_fee_bps,omitempty"`
+	OpsFeeBps            int32                  `protobuf:"varint,12,opt,name=ops_fee_bps,json=opsFeeBps,proto3" json:"ops_fee_bps,omitempty"`
+	Status               string                 `protobuf:"bytes,13,opt,name=status,proto3" json:"status,omitempty"` // models.CampaignStatus
+	MetadataUri          string                 `protobuf:"bytes,14,opt,name=metadata_uri,json=metadataUri,proto3" json:"metadata_uri,omitempty"`
+	CreatedAt            *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Title                string                 `protobuf:"bytes,16,opt,name=title,proto3" json:"title,omitempty"`
+	CurrentAmount        string                 `protobuf:"bytes,17,opt,name=current_amount,json=currentAmount,proto3" json:"current_amount,omitempty"` // 참여 예치 금액 합계
+	Description          string                 `protobuf:"bytes,18,opt,name=description,proto3" json:"description,omitempty"`
+	ImageUrl             string                 `protobuf:"bytes,19,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	MerchantWallet       string                 `protobuf:"bytes,20,opt,name=merchant_wallet,json=merchantWallet,proto3" json:"merchant_wallet,omitempty"` // EIP-55 체크섬 주소
+	CurrentQty           int64                  `protobuf:"varint,21,opt,name=current_qty,json=currentQty,proto3" json:"current_qty,omitempty"`
+	TargetAmount         string                 `protobuf:"bytes,22,opt,name=target_amount,json=targetAmount,proto3" json:"target_amount,omitempty"`                              // base_price * min_qty
+	DiscountRate         int32                  `protobuf:"varint,23,opt,name=discount_rate,json=discountRate,proto3" json:"discount_rate,omitempty"`                             // 달성 할인율 (bps)
+	SettlementDate       *timestamppb.Timestamp `protobuf:"bytes,24,opt,name=settlement_date,json=settlementDate,proto3" json:"settlement_date,omitempty"`                        // 정산 시각 (정산 전이면 비어 있음)
+	Version              int64                  `protobuf:"varint,25,opt,name=version,proto3" json:"version,omitempty"`                                                           // 변경마다 증가, 수정 시 If-Match로 전달
+	FreeCancelUntil      *timestamppb.Timestamp `protobuf:"bytes,26,opt,name=free_cancel_until,json=freeCancelUntil,proto3" json:"free_cancel_until,omitempty"`                   // 무료 취소 마감 (cancel_deadline, 없으면 end_time)
+	LateCancelAllowed    bool                   `protobuf:"varint,27,opt,name=late_cancel_allowed,json=lateCancelAllowed,proto3" json:"late_cancel_allowed,omitempty"`            // 마감 후에도 페널티를 내고 취소할 수 있는지
+	LateCancelPenaltyBps int32                  `protobuf:"varint,28,opt,name=late_cancel_penalty_bps,json=lateCancelPenaltyBps,proto3" json:"late_cancel_penalty_bps,omitempty"` // 마감 후 취소 페널티 (bps)
+	ImageThumbnailUrl    string                 `protobuf:"bytes,29,opt,name=image_thumbnail_url,json=imageThumbnailUrl,proto3" json:"image_thumbnail_url,omitempty"`             // image_url의 썸네일 (업로드된 이미지만)
+	Category             string                 `protobuf:"bytes,30,opt,name=category,proto3" json:"category,omitempty"`                                                          // 카테고리 슬러그 (없으면 빈 값)
+	CategoryName         string                 `protobuf:"bytes,31,opt,name=category_name,json=categoryName,proto3" json:"category_name,omitempty"`                              // JOIN으로 가져온 campaign_categories.name
+	Tags                 []string               `protobuf:"bytes,32,rep,name=tags,proto3" json:"tags,omitempty"`                                                                  // 소문자 태그
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Campaign) Reset() {
+	*x = Campaign{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Campaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Campaign) ProtoMessage() {}
+
+func (x *Campaign) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Campaign.ProtoReflect.Descriptor instead.
+func (*Campaign) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Campaign) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Campaign) GetChainAddress() string {
+	if x != nil {
+		return x.ChainAddress
+	}
+	return ""
+}
+
+func (x *Campaign) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *Campaign) GetMerchantName() string {
+	if x != nil {
+		return x.MerchantName
+	}
+	return ""
+}
+
+func (x *Campaign) GetBasePrice() string {
+	if x != nil {
+		return x.BasePrice
+	}
+	return ""
+}
+
+func (x *Campaign) GetMinQty() int64 {
+	if x != nil {
+		return x.MinQty
+	}
+	return 0
+}
+
+func (x *Campaign) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *Campaign) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Campaign) GetRMaxBps() int32 {
+	if x != nil {
+		return x.RMaxBps
+	}
+	return 0
+}
+
+func (x *Campaign) GetSaveFloorBps() int32 {
+	if x != nil {
+		return x.SaveFloorBps
+	}
+	return 0
+}
+
+func (x *Campaign) GetMerchantFeeBps() int32 {
+	if x != nil {
+		return x.MerchantFeeBps
+	}
+	return 0
+}
+
+func (x *Campaign) GetOpsFeeBps() int32 {
+	if x != nil {
+		return x.OpsFeeBps
+	}
+	return 0
+}
+
+func (x *Campaign) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Campaign) GetMetadataUri() string {
+	if x != nil {
+		return x.MetadataUri
+	}
+	return ""
+}
+
+func (x *Campaign) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Campaign) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Campaign) GetCurrentAmount() string {
+	if x != nil {
+		return x.CurrentAmount
+	}
+	return ""
+}
+
+func (x *Campaign) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Campaign) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Campaign) GetMerchantWallet() string {
+	if x != nil {
+		return x.MerchantWallet
+	}
+	return ""
+}
+
+func (x *Campaign) GetCurrentQty() int64 {
+	if x != nil {
+		return x.CurrentQty
+	}
+	return 0
+}
+
+func (x *Campaign) GetTargetAmount() string {
+	if x != nil {
+		return x.TargetAmount
+	}
+	return ""
+}
+
+func (x *Campaign) GetDiscountRate() int32 {
+	if x != nil {
+		return x.DiscountRate
+	}
+	return 0
+}
+
+func (x *Campaign) GetSettlementDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettlementDate
+	}
+	return nil
+}
+
+func (x *Campaign) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Campaign) GetFreeCancelUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FreeCancelUntil
+	}
+	return nil
+}
+
+func (x *Campaign) GetLateCancelAllowed() bool {
+	if x != nil {
+		return x.LateCancelAllowed
+	}
+	return false
+}
+
+func (x *Campaign) GetLateCancelPenaltyBps() int32 {
+	if x != nil {
+		return x.LateCancelPenaltyBps
+	}
+	return 0
+}
+
+func (x *Campaign) GetImageThumbnailUrl() string {
+	if x != nil {
+		return x.ImageThumbnailUrl
+	}
+	return ""
+}
+
+func (x *Campaign) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Campaign) GetCategoryName() string {
+	if x != nil {
+		return x.CategoryName
+	}
+	return ""
+}
+
+func (x *Campaign) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// 카테고리 목록 조회 요청
+type GetCategoriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesRequest) Reset() {
+	*x = GetCategoriesRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesRequest) ProtoMessage() {}
+
+func (x *GetCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{20}
+}
+
+// 카테고리 목록 조회 응답 (position 순)
+type GetCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoriesResponse) Reset() {
+	*x = GetCategoriesResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoriesResponse) ProtoMessage() {}
+
+func (x *GetCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+// 캠페인 카테고리 (campaign_categories, pkg/models.CampaignCategory)
+type Category struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Slug                string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Position            int32                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	ActiveCampaignCount int64                  `protobuf:"varint,4,opt,name=active_campaign_count,json=activeCampaignCount,proto3" json:"active_campaign_count,omitempty"` // 진행 중인 캠페인 수 (models.ActiveStatuses)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Category) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Category) GetActiveCampaignCount() int64 {
+	if x != nil {
+		return x.ActiveCampaignCount
+	}
+	return 0
+}
+
+// 관심 캠페인 목록 조회 요청
+type GetUserFavoritesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`      // 페이지 크기 (기본값: 20, 최대: 100)
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`    // 오프셋 (기본값: 0)
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`     // 이전 응답의 next_cursor (지정 시 offset 무시)
+	Statuses      []string               `protobuf:"bytes,5,rep,name=statuses,proto3" json:"statuses,omitempty"` // 캠페인 상태 목록 필터 (옵션)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserFavoritesRequest) Reset() {
+	*x = GetUserFavoritesRequest{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserFavoritesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserFavoritesRequest) ProtoMessage() {}
+
+func (x *GetUserFavoritesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserFavoritesRequest.ProtoReflect.Descriptor instead.
+func (*GetUserFavoritesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetUserFavoritesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserFavoritesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetUserFavoritesRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetUserFavoritesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetUserFavoritesRequest) GetStatuses() []string {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// 관심 캠페인 목록 조회 응답
+type GetUserFavoritesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Favorites     []*FavoriteCampaign    `protobuf:"bytes,1,rep,name=favorites,proto3" json:"favorites,omitempty"`
+	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 다음 페이지 커서 (마지막 페이지면 빈 값)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserFavoritesResponse) Reset() {
+	*x = GetUserFavoritesResponse{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserFavoritesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserFavoritesResponse) ProtoMessage() {}
+
+func (x *GetUserFavoritesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserFavoritesResponse.ProtoReflect.Descriptor instead.
+func (*GetUserFavoritesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetUserFavoritesResponse) GetFavorites() []*FavoriteCampaign {
+	if x != nil {
+		return x.Favorites
+	}
+	return nil
+}
+
+func (x *GetUserFavoritesResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetUserFavoritesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 관심 캠페인 (campaign_favorites)
+type FavoriteCampaign struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	FavoritedAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=favorited_at,json=favoritedAt,proto3" json:"favorited_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FavoriteCampaign) Reset() {
+	*x = FavoriteCampaign{}
+	mi := &file_proto_query_campaigns_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FavoriteCampaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FavoriteCampaign) ProtoMessage() {}
+
+func (x *FavoriteCampaign) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_campaigns_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FavoriteCampaign.ProtoReflect.Descriptor instead.
+func (*FavoriteCampaign) Descriptor() ([]byte, []int) {
+	return file_proto_query_campaigns_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FavoriteCampaign) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *FavoriteCampaign) GetFavoritedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FavoritedAt
+	}
+	return nil
+}
+
+var File_proto_query_campaigns_proto protoreflect.FileDescriptor
+
+const file_proto_query_campaigns_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/query/campaigns.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\"\x82\x04\n" +
+	"\x13GetCampaignsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x1f\n" +
+	"\vmerchant_id\x18\x05 \x01(\tR\n" +
+	"merchantId\x12\x1a\n" +
+	"\bstatuses\x18\x06 \x03(\tR\bstatuses\x127\n" +
+	"\tlock_from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\blockFrom\x123\n" +
+	"\alock_to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06lockTo\x12$\n" +
+	"\x0emin_base_price\x18\t \x01(\tR\fminBasePrice\x12$\n" +
+	"\x0emax_base_price\x18\n" +
+	" \x01(\tR\fmaxBasePrice\x12\f\n" +
+	"\x01q\x18\v \x01(\tR\x01q\x12'\n" +
+	"\x04sort\x18\f \x01(\x0e2\x13.query.CampaignSortR\x04sort\x12\x1d\n" +
+	"\n" +
+	"skip_count\x18\r \x01(\bR\tskipCount\x12\x10\n" +
+	"\x03ids\x18\x0e \x03(\tR\x03ids\x12\x1a\n" +
+	"\bcategory\x18\x0f \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\x10 \x03(\tR\x04tags\"\x87\x01\n" +
+	"\x14GetCampaignsResponse\x12-\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x0f.query.CampaignR\tcampaigns\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\x88\x01\n" +
+	"\x16SearchCampaignsRequest\x12\f\n" +
+	"\x01q\x18\x01 \x01(\tR\x01q\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x1a\n" +
+	"\bstatuses\x18\x05 \x03(\tR\bstatuses\"\x92\x01\n" +
+	"\x17SearchCampaignsResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.query.CampaignSearchResultR\aresults\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\xad\x01\n" +
+	"\x14CampaignSearchResult\x12+\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x0f.query.CampaignR\bcampaign\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x02R\x04rank\x12#\n" +
+	"\rtitle_snippet\x18\x03 \x01(\tR\ftitleSnippet\x12/\n" +
+	"\x13description_snippet\x18\x04 \x01(\tR\x12descriptionSnippet\"\x8f\x01\n" +
+	"\x16StreamCampaignsRequest\x12\x1a\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\x12\x1f\n" +
+	"\vmerchant_id\x18\x02 \x01(\tR\n" +
+	"merchantId\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\tR\aafterId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x04 \x01(\x05R\tbatchSize\"W\n" +
+	"\rCampaignBatch\x12-\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x0f.query.CampaignR\tcampaigns\x12\x17\n" +
+	"\alast_id\x18\x02 \x01(\tR\x06lastId\"N\n" +
+	"\x17GetCampaignStatsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12\x12\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\"\\\n" +
+	"\x18GetCampaignStatsResponse\x12*\n" +
+	"\x05stats\x18\x01 \x01(\v2\x14.query.CampaignStatsR\x05stats\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xf2\x03\n" +
+	"\rCampaignStats\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12+\n" +
+	"\x11participant_count\x18\x02 \x01(\x03R\x10participantCount\x12!\n" +
+	"\ffunded_count\x18\x03 \x01(\x03R\vfundedCount\x12'\n" +
+	"\x0fcancelled_count\x18\x04 \x01(\x03R\x0ecancelledCount\x12#\n" +
+	"\rtotal_deposit\x18\x05 \x01(\tR\ftotalDeposit\x12'\n" +
+	"\x0faverage_deposit\x18\x06 \x01(\tR\x0eaverageDeposit\x12+\n" +
+	"\x11cancellation_rate\x18\a \x01(\x01R\x10cancellationRate\x120\n" +
+	"\x14projected_rebate_min\x18\b \x01(\tR\x12projectedRebateMin\x120\n" +
+	"\x14projected_rebate_max\x18\t \x01(\tR\x12projectedRebateMax\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12-\n" +
+	"\afunding\x18\v \x03(\v2\x13.query.FundingPointR\afunding\"\xb1\x01\n" +
+	"\fFundingPoint\x12,\n" +
+	"\x03day\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x03day\x12#\n" +
+	"\rtotal_deposit\x18\x02 \x01(\tR\ftotalDeposit\x12+\n" +
+	"\x11participant_count\x18\x03 \x01(\x03R\x10participantCount\x12!\n" +
+	"\ffunded_count\x18\x04 \x01(\x03R\vfundedCount\"V\n" +
+	"\x1bGetTrendingCampaignsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12!\n" +
+	"\fwindow_hours\x18\x02 \x01(\x05R\vwindowHours\"U\n" +
+	"\x1cGetTrendingCampaignsResponse\x125\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x17.query.TrendingCampaignR\tcampaigns\"x\n" +
+	"\x10TrendingCampaign\x12+\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x0f.query.CampaignR\bcampaign\x12!\n" +
+	"\frecent_joins\x18\x02 \x01(\x03R\vrecentJoins\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\"O\n" +
+	"\x1eGetRecommendedCampaignsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"[\n" +
+	"\x1fGetRecommendedCampaignsResponse\x128\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x1a.query.RecommendedCampaignR\tcampaigns\"p\n" +
+	"\x13RecommendedCampaign\x12+\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x0f.query.CampaignR\bcampaign\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\"5\n" +
+	"\x12GetCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\"X\n" +
 	"\x13GetCampaignResponse\x12+\n" +
 	"\bcampaign\x18\x01 \x01(\v2\x0f.query.CampaignR\bcampaign\x12\x14\n" +
-	"\x05found\x18\x02 \x01(\bR\x05found\"\xa2\x04\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xd4\t\n" +
 	"\bCampaign\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
-	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1f\n" +
-	"\vmerchant_id\x18\x03 \x01(\x03R\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rchain_address\x18\x02 \x01(\tR\fchainAddress\x12\x1f\n" +
+	"\vmerchant_id\x18\x03 \x01(\tR\n" +
 	"merchantId\x12#\n" +
 	"\rmerchant_name\x18\x04 \x01(\tR\fmerchantName\x12\x1d\n" +
 	"\n" +
 	"base_price\x18\x05 \x01(\tR\tbasePrice\x12\x17\n" +
 	"\amin_qty\x18\x06 \x01(\x03R\x06minQty\x129\n" +
 	"\n" +
-	"lock_start\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tlockStart\x125\n" +
-	"\block_end\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\alockEnd\x12\x19\n" +
-	"\brmax_bps\x18\t \x01(\x05R\armaxBps\x12#\n" +
-	"\rsavefloor_bps\x18\n" +
-	" \x01(\x05R\fsavefloorBps\x12(\n" +
+	"start_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1a\n" +
+	"\tr_max_bps\x18\t \x01(\x05R\arMaxBps\x12$\n" +
+	"\x0esave_floor_bps\x18\n" +
+	" \x01(\x05R\fsaveFloorBps\x12(\n" +
 	"\x10merchant_fee_bps\x18\v \x01(\x05R\x0emerchantFeeBps\x12\x1e\n" +
-	"\vops_fee_bps\x18\f \x01(\x05R\topsFeeBps\x12\x14\n" +
-	"\x05state\x18\r \x01(\x05R\x05state\x12!\n" +
+	"\vops_fee_bps\x18\f \x01(\x05R\topsFeeBps\x12\x16\n" +
+	"\x06status\x18\r \x01(\tR\x06status\x12!\n" +
 	"\fmetadata_uri\x18\x0e \x01(\tR\vmetadataUri\x129\n" +
 	"\n" +
-	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt2\x9d\x01\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x14\n" +
+	"\x05title\x18\x10 \x01(\tR\x05title\x12%\n" +
+	"\x0ecurrent_amount\x18\x11 \x01(\tR\rcurrentAmount\x12 \n" +
+	"\vdescription\x18\x12 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x13 \x01(\tR\bimageUrl\x12'\n" +
+	"\x0fmerchant_wallet\x18\x14 \x01(\tR\x0emerchantWallet\x12\x1f\n" +
+	"\vcurrent_qty\x18\x15 \x01(\x03R\n" +
+	"currentQty\x12#\n" +
+	"\rtarget_amount\x18\x16 \x01(\tR\ftargetAmount\x12#\n" +
+	"\rdiscount_rate\x18\x17 \x01(\x05R\fdiscountRate\x12C\n" +
+	"\x0fsettlement_date\x18\x18 \x01(\v2\x1a.google.protobuf.TimestampR\x0esettlementDate\x12\x18\n" +
+	"\aversion\x18\x19 \x01(\x03R\aversion\x12F\n" +
+	"\x11free_cancel_until\x18\x1a \x01(\v2\x1a.google.protobuf.TimestampR\x0ffreeCancelUntil\x12.\n" +
+	"\x13late_cancel_allowed\x18\x1b \x01(\bR\x11lateCancelAllowed\x125\n" +
+	"\x17late_cancel_penalty_bps\x18\x1c \x01(\x05R\x14lateCancelPenaltyBps\x12.\n" +
+	"\x13image_thumbnail_url\x18\x1d \x01(\tR\x11imageThumbnailUrl\x12\x1a\n" +
+	"\bcategory\x18\x1e \x01(\tR\bcategory\x12#\n" +
+	"\rcategory_name\x18\x1f \x01(\tR\fcategoryName\x12\x12\n" +
+	"\x04tags\x18  \x03(\tR\x04tags\"\x16\n" +
+	"\x14GetCategoriesRequest\"H\n" +
+	"\x15GetCategoriesResponse\x12/\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x0f.query.CategoryR\n" +
+	"categories\"\x82\x01\n" +
+	"\bCategory\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\x05R\bposition\x122\n" +
+	"\x15active_campaign_count\x18\x04 \x01(\x03R\x13activeCampaignCount\"\x94\x01\n" +
+	"\x17GetUserFavoritesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x1a\n" +
+	"\bstatuses\x18\x05 \x03(\tR\bstatuses\"\x93\x01\n" +
+	"\x18GetUserFavoritesResponse\x125\n" +
+	"\tfavorites\x18\x01 \x03(\v2\x17.query.FavoriteCampaignR\tfavorites\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"~\n" +
+	"\x10FavoriteCampaign\x12+\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x0f.query.CampaignR\bcampaign\x12=\n" +
+	"\ffavorited_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vfavoritedAt*\x82\x01\n" +
+	"\fCampaignSort\x12\x18\n" +
+	"\x14CAMPAIGN_SORT_NEWEST\x10\x00\x12\x1d\n" +
+	"\x19CAMPAIGN_SORT_ENDING_SOON\x10\x01\x12\x1d\n" +
+	"\x19CAMPAIGN_SORT_MOST_FUNDED\x10\x02\x12\x1a\n" +
+	"\x16CAMPAIGN_SORT_CATEGORY\x10\x032\xfa\x05\n" +
 	"\fQueryService\x12G\n" +
 	"\fGetCampaigns\x12\x1a.query.GetCampaignsRequest\x1a\x1b.query.GetCampaignsResponse\x12D\n" +
-	"\vGetCampaign\x12\x19.query.GetCampaignRequest\x1a\x1a.query.GetCampaignResponseB\tZ\a./queryb\x06proto3"
+	"\vGetCampaign\x12\x19.query.GetCampaignRequest\x1a\x1a.query.GetCampaignResponse\x12P\n" +
+	"\x0fSearchCampaigns\x12\x1d.query.SearchCampaignsRequest\x1a\x1e.query.SearchCampaignsResponse\x12H\n" +
+	"\x0fStreamCampaigns\x12\x1d.query.StreamCampaignsRequest\x1a\x14.query.CampaignBatch0\x01\x12S\n" +
+	"\x10GetCampaignStats\x12\x1e.query.GetCampaignStatsRequest\x1a\x1f.query.GetCampaignStatsResponse\x12_\n" +
+	"\x14GetTrendingCampaigns\x12\".query.GetTrendingCampaignsRequest\x1a#.query.GetTrendingCampaignsResponse\x12h\n" +
+	"\x17GetRecommendedCampaigns\x12%.query.GetRecommendedCampaignsRequest\x1a&.query.GetRecommendedCampaignsResponse\x12J\n" +
+	"\rGetCategories\x12\x1b.query.GetCategoriesRequest\x1a\x1c.query.GetCategoriesResponse\x12S\n" +
+	"\x10GetUserFavorites\x12\x1e.query.GetUserFavoritesRequest\x1a\x1f.query.GetUserFavoritesResponseB\tZ\a./queryb\x06proto3"
 
 var (
 	file_proto_query_campaigns_proto_rawDescOnce sync.Once
@@ -447,30 +2182,87 @@ func file_proto_query_campaigns_proto_rawDescGZIP() []byte {
 	return file_proto_query_campaigns_proto_rawDescData
 }
 
-var file_proto_query_campaigns_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_query_campaigns_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_query_campaigns_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_proto_query_campaigns_proto_goTypes = []any{
-	(*GetCampaignsRequest)(nil),   // 0: query.GetCampaignsRequest
-	(*GetCampaignsResponse)(nil),  // 1: query.GetCampaignsResponse
-	(*GetCampaignRequest)(nil),    // 2: query.GetCampaignRequest
-	(*GetCampaignResponse)(nil),   // 3: query.GetCampaignResponse
-	(*Campaign)(nil),              // 4: query.Campaign
-	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(CampaignSort)(0),                       // 0: query.CampaignSort
+	(*GetCampaignsRequest)(nil),             // 1: query.GetCampaignsRequest
+	(*GetCampaignsResponse)(nil),            // 2: query.GetCampaignsResponse
+	(*SearchCampaignsRequest)(nil),          // 3: query.SearchCampaignsRequest
+	(*SearchCampaignsResponse)(nil),         // 4: query.SearchCampaignsResponse
+	(*CampaignSearchResult)(nil),            // 5: query.CampaignSearchResult
+	(*StreamCampaignsRequest)(nil),          // 6: query.StreamCampaignsRequest
+	(*CampaignBatch)(nil),                   // 7: query.CampaignBatch
+	(*GetCampaignStatsRequest)(nil),         // 8: query.GetCampaignStatsRequest
+	(*GetCampaignStatsResponse)(nil),        // 9: query.GetCampaignStatsResponse
+	(*CampaignStats)(nil),                   // 10: query.CampaignStats
+	(*FundingPoint)(nil),                    // 11: query.FundingPoint
+	(*GetTrendingCampaignsRequest)(nil),     // 12: query.GetTrendingCampaignsRequest
+	(*GetTrendingCampaignsResponse)(nil),    // 13: query.GetTrendingCampaignsResponse
+	(*TrendingCampaign)(nil),                // 14: query.TrendingCampaign
+	(*GetRecommendedCampaignsRequest)(nil),  // 15: query.GetRecommendedCampaignsRequest
+	(*GetRecommendedCampaignsResponse)(nil), // 16: query.GetRecommendedCampaignsResponse
+	(*RecommendedCampaign)(nil),             // 17: query.RecommendedCampaign
+	(*GetCampaignRequest)(nil),              // 18: query.GetCampaignRequest
+	(*GetCampaignResponse)(nil),             // 19: query.GetCampaignResponse
+	(*Campaign)(nil),                        // 20: query.Campaign
+	(*GetCategoriesRequest)(nil),            // 21: query.GetCategoriesRequest
+	(*GetCategoriesResponse)(nil),           // 22: query.GetCategoriesResponse
+	(*Category)(nil),                        // 23: query.Category
+	(*GetUserFavoritesRequest)(nil),         // 24: query.GetUserFavoritesRequest
+	(*GetUserFavoritesResponse)(nil),        // 25: query.GetUserFavoritesResponse
+	(*FavoriteCampaign)(nil),                // 26: query.FavoriteCampaign
+	(*timestamppb.Timestamp)(nil),           // 27: google.protobuf.Timestamp
 }
 var file_proto_query_campaigns_proto_depIdxs = []int32{
-	4, // 0: query.GetCampaignsResponse.campaigns:type_name -> query.Campaign
-	4, // 1: query.GetCampaignResponse.campaign:type_name -> query.Campaign
-	5, // 2: query.Campaign.lock_start:type_name -> google.protobuf.Timestamp
-	5, // 3: query.Campaign.lock_end:type_name -> google.protobuf.Timestamp
-	5, // 4: query.Campaign.created_at:type_name -> google.protobuf.Timestamp
-	0, // 5: query.QueryService.GetCampaigns:input_type -> query.GetCampaignsRequest
-	2, // 6: query.QueryService.GetCampaign:input_type -> query.GetCampaignRequest
-	1, // 7: query.QueryService.GetCampaigns:output_type -> query.GetCampaignsResponse
-	3, // 8: query.QueryService.GetCampaign:output_type -> query.GetCampaignResponse
-	7, // [7:9] is the sub-list for method output_type
-	5, // [5:7] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	27, // 0: query.GetCampaignsRequest.lock_from:type_name -> google.protobuf.Timestamp
+	27, // 1: query.GetCampaignsRequest.lock_to:type_name -> google.protobuf.Timestamp
+	0,  // 2: query.GetCampaignsRequest.sort:type_name -> query.CampaignSort
+	20, // 3: query.GetCampaignsResponse.campaigns:type_name -> query.Campaign
+	5,  // 4: query.SearchCampaignsResponse.results:type_name -> query.CampaignSearchResult
+	20, // 5: query.CampaignSearchResult.campaign:type_name -> query.Campaign
+	20, // 6: query.CampaignBatch.campaigns:type_name -> query.Campaign
+	10, // 7: query.GetCampaignStatsResponse.stats:type_name -> query.CampaignStats
+	27, // 8: query.CampaignStats.updated_at:type_name -> google.protobuf.Timestamp
+	11, // 9: query.CampaignStats.funding:type_name -> query.FundingPoint
+	27, // 10: query.FundingPoint.day:type_name -> google.protobuf.Timestamp
+	14, // 11: query.GetTrendingCampaignsResponse.campaigns:type_name -> query.TrendingCampaign
+	20, // 12: query.TrendingCampaign.campaign:type_name -> query.Campaign
+	17, // 13: query.GetRecommendedCampaignsResponse.campaigns:type_name -> query.RecommendedCampaign
+	20, // 14: query.RecommendedCampaign.campaign:type_name -> query.Campaign
+	20, // 15: query.GetCampaignResponse.campaign:type_name -> query.Campaign
+	27, // 16: query.Campaign.start_time:type_name -> google.protobuf.Timestamp
+	27, // 17: query.Campaign.end_time:type_name -> google.protobuf.Timestamp
+	27, // 18: query.Campaign.created_at:type_name -> google.protobuf.Timestamp
+	27, // 19: query.Campaign.settlement_date:type_name -> google.protobuf.Timestamp
+	27, // 20: query.Campaign.free_cancel_until:type_name -> google.protobuf.Timestamp
+	23, // 21: query.GetCategoriesResponse.categories:type_name -> query.Category
+	26, // 22: query.GetUserFavoritesResponse.favorites:type_name -> query.FavoriteCampaign
+	20, // 23: query.FavoriteCampaign.campaign:type_name -> query.Campaign
+	27, // 24: query.FavoriteCampaign.favorited_at:type_name -> google.protobuf.Timestamp
+	1,  // 25: query.QueryService.GetCampaigns:input_type -> query.GetCampaignsRequest
+	18, // 26: query.QueryService.GetCampaign:input_type -> query.GetCampaignRequest
+	3,  // 27: query.QueryService.SearchCampaigns:input_type -> query.SearchCampaignsRequest
+	6,  // 28: query.QueryService.StreamCampaigns:input_type -> query.StreamCampaignsRequest
+	8,  // 29: query.QueryService.GetCampaignStats:input_type -> query.GetCampaignStatsRequest
+	12, // 30: query.QueryService.GetTrendingCampaigns:input_type -> query.GetTrendingCampaignsRequest
+	15, // 31: query.QueryService.GetRecommendedCampaigns:input_type -> query.GetRecommendedCampaignsRequest
+	21, // 32: query.QueryService.GetCategories:input_type -> query.GetCategoriesRequest
+	24, // 33: query.QueryService.GetUserFavorites:input_type -> query.GetUserFavoritesRequest
+	2,  // 34: query.QueryService.GetCampaigns:output_type -> query.GetCampaignsResponse
+	19, // 35: query.QueryService.GetCampaign:output_type -> query.GetCampaignResponse
+	4,  // 36: query.QueryService.SearchCampaigns:output_type -> query.SearchCampaignsResponse
+	7,  // 37: query.QueryService.StreamCampaigns:output_type -> query.CampaignBatch
+	9,  // 38: query.QueryService.GetCampaignStats:output_type -> query.GetCampaignStatsResponse
+	13, // 39: query.QueryService.GetTrendingCampaigns:output_type -> query.GetTrendingCampaignsResponse
+	16, // 40: query.QueryService.GetRecommendedCampaigns:output_type -> query.GetRecommendedCampaignsResponse
+	22, // 41: query.QueryService.GetCategories:output_type -> query.GetCategoriesResponse
+	25, // 42: query.QueryService.GetUserFavorites:output_type -> query.GetUserFavoritesResponse
+	34, // [34:43] is the sub-list for method output_type
+	25, // [25:34] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_query_campaigns_proto_init() }
@@ -483,13 +2275,14 @@ func file_proto_query_campaigns_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_query_campaigns_proto_rawDesc), len(file_proto_query_campaigns_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   5,
+			NumEnums:      1,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_query_campaigns_proto_goTypes,
 		DependencyIndexes: file_proto_query_campaigns_proto_depIdxs,
+		EnumInfos:         file_proto_query_campaigns_proto_enumTypes,
 		MessageInfos:      file_proto_query_campaigns_proto_msgTypes,
 	}.Build()
 	File_proto_query_campaigns_proto = out.File
@@ -19,8 +19,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	QueryService_GetCampaigns_FullMethodName = "/query.QueryService/GetCampaigns"
-	QueryService_GetCampaign_FullMethodName  = "/query.QueryService/GetCampaign"
+	QueryService_GetCampaigns_FullMethodName            = "/query.QueryService/GetCampaigns"
+	QueryService_GetCampaign_FullMethodName             = "/query.QueryService/GetCampaign"
+	QueryService_SearchCampaigns_FullMethodName         = "/query.QueryService/SearchCampaigns"
+	QueryService_StreamCampaigns_FullMethodName         = "/query.QueryService/StreamCampaigns"
+	QueryService_GetCampaignStats_FullMethodName        = "/query.QueryService/GetCampaignStats"
+	QueryService_GetTrendingCampaigns_FullMethodName    = "/query.QueryService/GetTrendingCampaigns"
+	QueryService_GetRecommendedCampaigns_FullMethodName = "/query.QueryService/GetRecommendedCampaigns"
+	QueryService_GetCategories_FullMethodName           = "/query.QueryService/GetCategories"
+	QueryService_GetUserFavorites_FullMethodName        = "/query.QueryService/GetUserFavorites"
 )
 
 // QueryServiceClient is the client API for QueryService service.
@@ -33,6 +40,20 @@ type QueryServiceClient interface {
 	GetCampaigns(ctx context.Context, in *GetCampaignsRequest, opts ...grpc.CallOption) (*GetCampaignsResponse, error)
 	// 특정 캠페인 조회
 	GetCampaign(ctx context.Context, in *GetCampaignRequest, opts ...grpc.CallOption) (*GetCampaignResponse, error)
+	// 캠페인 전문 검색 (제목, 설명, 머천트 이름)
+	SearchCampaigns(ctx context.Context, in *SearchCampaignsRequest, opts ...grpc.CallOption) (*SearchCampaignsResponse, error)
+	// 캠페인 전체를 id 순 배치로 스트리밍 (배치 작업, 분석용 내보내기)
+	StreamCampaigns(ctx context.Context, in *StreamCampaignsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CampaignBatch], error)
+	// 캠페인 참여 통계 및 일별 모집 추이 조회 (batch-server 집계 기준)
+	GetCampaignStats(ctx context.Context, in *GetCampaignStatsRequest, opts ...grpc.CallOption) (*GetCampaignStatsResponse, error)
+	// 최근 참여 속도 기준 인기 캠페인 (모집 중인 캠페인만)
+	GetTrendingCampaigns(ctx context.Context, in *GetTrendingCampaignsRequest, opts ...grpc.CallOption) (*GetTrendingCampaignsResponse, error)
+	// 사용자 맞춤 추천 캠페인 (참여 이력의 머천트/카테고리 기준, 부족하면 인기 캠페인으로 채움)
+	GetRecommendedCampaigns(ctx context.Context, in *GetRecommendedCampaignsRequest, opts ...grpc.CallOption) (*GetRecommendedCampaignsResponse, error)
+	// 캠페인 카테고리 목록 (표시 순서, 앱의 카테고리 탭용)
+	GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error)
+	// 사용자의 관심 캠페인 목록 (관심 등록 최신순)
+	GetUserFavorites(ctx context.Context, in *GetUserFavoritesRequest, opts ...grpc.CallOption) (*GetUserFavoritesResponse, error)
 }
 
 type queryServiceClient struct {
@@ -63,6 +84,85 @@ func (c *queryServiceClient) GetCampaign(ctx context.Context, in *GetCampaignReq
 	return out, nil
 }
 
+func (c *queryServiceClient) SearchCampaigns(ctx context.Context, in *SearchCampaignsRequest, opts ...grpc.CallOption) (*SearchCampaignsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchCampaignsResponse)
+	err := c.cc.Invoke(ctx, QueryService_SearchCampaigns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) StreamCampaigns(ctx context.Context, in *StreamCampaignsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CampaignBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], QueryService_StreamCampaigns_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamCampaignsRequest, CampaignBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_StreamCampaignsClient = grpc.ServerStreamingClient[CampaignBatch]
+
+func (c *queryServiceClient) GetCampaignStats(ctx context.Context, in *GetCampaignStatsRequest, opts ...grpc.CallOption) (*GetCampaignStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCampaignStatsResponse)
+	err := c.cc.Invoke(ctx, QueryService_GetCampaignStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetTrendingCampaigns(ctx context.Context, in *GetTrendingCampaignsRequest, opts ...grpc.CallOption) (*GetTrendingCampaignsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTrendingCampaignsResponse)
+	err := c.cc.Invoke(ctx, QueryService_GetTrendingCampaigns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetRecommendedCampaigns(ctx context.Context, in *GetRecommendedCampaignsRequest, opts ...grpc.CallOption) (*GetRecommendedCampaignsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecommendedCampaignsResponse)
+	err := c.cc.Invoke(ctx, QueryService_GetRecommendedCampaigns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetCategories(ctx context.Context, in *GetCategoriesRequest, opts ...grpc.CallOption) (*GetCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCategoriesResponse)
+	err := c.cc.Invoke(ctx, QueryService_GetCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetUserFavorites(ctx context.Context, in *GetUserFavoritesRequest, opts ...grpc.CallOption) (*GetUserFavoritesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserFavoritesResponse)
+	err := c.cc.Invoke(ctx, QueryService_GetUserFavorites_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 // All implementations must embed UnimplementedQueryServiceServer
 // for forward compatibility.
@@ -73,6 +173,20 @@ type QueryServiceServer interface {
 	GetCampaigns(context.Context, *GetCampaignsRequest) (*GetCampaignsResponse, error)
 	// 특정 캠페인 조회
 	GetCampaign(context.Context, *GetCampaignRequest) (*GetCampaignResponse, error)
+	// 캠페인 전문 검색 (제목, 설명, 머천트 이름)
+	SearchCampaigns(context.Context, *SearchCampaignsRequest) (*SearchCampaignsResponse, error)
+	// 캠페인 전체를 id 순 배치로 스트리밍 (배치 작업, 분석용 내보내기)
+	StreamCampaigns(*StreamCampaignsRequest, grpc.ServerStreamingServer[CampaignBatch]) error
+	// 캠페인 참여 통계 및 일별 모집 추이 조회 (batch-server 집계 기준)
+	GetCampaignStats(context.Context, *GetCampaignStatsRequest) (*GetCampaignStatsResponse, error)
+	// 최근 참여 속도 기준 인기 캠페인 (모집 중인 캠페인만)
+	GetTrendingCampaigns(context.Context, *GetTrendingCampaignsRequest) (*GetTrendingCampaignsResponse, error)
+	// 사용자 맞춤 추천 캠페인 (참여 이력의 머천트/카테고리 기준, 부족하면 인기 캠페인으로 채움)
+	GetRecommendedCampaigns(context.Context, *GetRecommendedCampaignsRequest) (*GetRecommendedCampaignsResponse, error)
+	// 캠페인 카테고리 목록 (표시 순서, 앱의 카테고리 탭용)
+	GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error)
+	// 사용자의 관심 캠페인 목록 (관심 등록 최신순)
+	GetUserFavorites(context.Context, *GetUserFavoritesRequest) (*GetUserFavoritesResponse, error)
 	mustEmbedUnimplementedQueryServiceServer()
 }
 
@@ -89,6 +203,27 @@ func (UnimplementedQueryServiceServer) GetCampaigns(context.Context, *GetCampaig
 func (UnimplementedQueryServiceServer) GetCampaign(context.Context, *GetCampaignRequest) (*GetCampaignResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetCampaign not implemented")
 }
+func (UnimplementedQueryServiceServer) SearchCampaigns(context.Context, *SearchCampaignsRequest) (*SearchCampaignsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCampaigns not implemented")
+}
+func (UnimplementedQueryServiceServer) StreamCampaigns(*StreamCampaignsRequest, grpc.ServerStreamingServer[CampaignBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamCampaigns not implemented")
+}
+func (UnimplementedQueryServiceServer) GetCampaignStats(context.Context, *GetCampaignStatsRequest) (*GetCampaignStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCampaignStats not implemented")
+}
+func (UnimplementedQueryServiceServer) GetTrendingCampaigns(context.Context, *GetTrendingCampaignsRequest) (*GetTrendingCampaignsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrendingCampaigns not implemented")
+}
+func (UnimplementedQueryServiceServer) GetRecommendedCampaigns(context.Context, *GetRecommendedCampaignsRequest) (*GetRecommendedCampaignsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendedCampaigns not implemented")
+}
+func (UnimplementedQueryServiceServer) GetCategories(context.Context, *GetCategoriesRequest) (*GetCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategories not implemented")
+}
+func (UnimplementedQueryServiceServer) GetUserFavorites(context.Context, *GetUserFavoritesRequest) (*GetUserFavoritesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserFavorites not implemented")
+}
 func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
 func (UnimplementedQueryServiceServer) testEmbeddedByValue()                      {}
 
@@ -146,6 +281,125 @@ func _QueryService_GetCampaign_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_SearchCampaigns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCampaignsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).SearchCampaigns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_SearchCampaigns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).SearchCampaigns(ctx, req.(*SearchCampaignsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_StreamCampaigns_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamCampaignsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).StreamCampaigns(m, &grpc.GenericServerStream[StreamCampaignsRequest, CampaignBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_StreamCampaignsServer = grpc.ServerStreamingServer[CampaignBatch]
+
+func _QueryService_GetCampaignStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCampaignStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetCampaignStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_GetCampaignStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetCampaignStats(ctx, req.(*GetCampaignStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetTrendingCampaigns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrendingCampaignsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetTrendingCampaigns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_GetTrendingCampaigns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetTrendingCampaigns(ctx, req.(*GetTrendingCampaignsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetRecommendedCampaigns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecommendedCampaignsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetRecommendedCampaigns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_GetRecommendedCampaigns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetRecommendedCampaigns(ctx, req.(*GetRecommendedCampaignsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_GetCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetCategories(ctx, req.(*GetCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetUserFavorites_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserFavoritesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetUserFavorites(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_GetUserFavorites_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetUserFavorites(ctx, req.(*GetUserFavoritesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -161,7 +415,37 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCampaign",
 			Handler:    _QueryService_GetCampaign_Handler,
 		},
+		{
+			MethodName: "SearchCampaigns",
+			Handler:    _QueryService_SearchCampaigns_Handler,
+		},
+		{
+			MethodName: "GetCampaignStats",
+			Handler:    _QueryService_GetCampaignStats_Handler,
+		},
+		{
+			MethodName: "GetTrendingCampaigns",
+			Handler:    _QueryService_GetTrendingCampaigns_Handler,
+		},
+		{
+			MethodName: "GetRecommendedCampaigns",
+			Handler:    _QueryService_GetRecommendedCampaigns_Handler,
+		},
+		{
+			MethodName: "GetCategories",
+			Handler:    _QueryService_GetCategories_Handler,
+		},
+		{
+			MethodName: "GetUserFavorites",
+			Handler:    _QueryService_GetUserFavorites_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamCampaigns",
+			Handler:       _QueryService_StreamCampaigns_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/query/campaigns.proto",
 }
@@ -0,0 +1,673 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.0
+// source: proto/query/merchants.proto
+
+package query
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 머천트 목록 조회 요청
+type GetMerchantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`   // 페이지 크기 (기본값: 20, 최대: 100)
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // 오프셋 (기본값: 0)
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`  // 이전 응답의 next_cursor (지정 시 offset 무시)
+	Ids           []string               `protobuf:"bytes,4,rep,name=ids,proto3" json:"ids,omitempty"`        // 머천트 id 목록 필터 (옵션, 배치 조회용으로 limit 이하)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantsRequest) Reset() {
+	*x = GetMerchantsRequest{}
+	mi := &file_proto_query_merchants_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantsRequest) ProtoMessage() {}
+
+func (x *GetMerchantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantsRequest.ProtoReflect.Descriptor instead.
+func (*GetMerchantsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetMerchantsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMerchantsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetMerchantsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetMerchantsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// 머천트 목록 조회 응답
+type GetMerchantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Merchants     []*Merchant            `protobuf:"bytes,1,rep,name=merchants,proto3" json:"merchants,omitempty"`
+	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 다음 페이지 커서 (마지막 페이지면 빈 값)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantsResponse) Reset() {
+	*x = GetMerchantsResponse{}
+	mi := &file_proto_query_merchants_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantsResponse) ProtoMessage() {}
+
+func (x *GetMerchantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantsResponse.ProtoReflect.Descriptor instead.
+func (*GetMerchantsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetMerchantsResponse) GetMerchants() []*Merchant {
+	if x != nil {
+		return x.Merchants
+	}
+	return nil
+}
+
+func (x *GetMerchantsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetMerchantsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 특정 머천트 조회 요청
+type GetMerchantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MerchantId    string                 `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantRequest) Reset() {
+	*x = GetMerchantRequest{}
+	mi := &file_proto_query_merchants_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantRequest) ProtoMessage() {}
+
+func (x *GetMerchantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantRequest.ProtoReflect.Descriptor instead.
+func (*GetMerchantRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMerchantRequest) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+// 특정 머천트 조회 응답
+type GetMerchantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Merchant      *Merchant              `protobuf:"bytes,1,opt,name=merchant,proto3" json:"merchant,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Settlements   []*Settlement          `protobuf:"bytes,3,rep,name=settlements,proto3" json:"settlements,omitempty"` // 최근 정산 내역 (최신순, 최대 20건)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantResponse) Reset() {
+	*x = GetMerchantResponse{}
+	mi := &file_proto_query_merchants_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantResponse) ProtoMessage() {}
+
+func (x *GetMerchantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantResponse.ProtoReflect.Descriptor instead.
+func (*GetMerchantResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetMerchantResponse) GetMerchant() *Merchant {
+	if x != nil {
+		return x.Merchant
+	}
+	return nil
+}
+
+func (x *GetMerchantResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetMerchantResponse) GetSettlements() []*Settlement {
+	if x != nil {
+		return x.Settlements
+	}
+	return nil
+}
+
+// 머천트 캠페인 목록 조회 요청
+type GetMerchantCampaignsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MerchantId    string                 `protobuf:"bytes,1,opt,name=merchant_id,json=merchantId,proto3" json:"merchant_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // 캠페인 상태 필터 (옵션, 빈 값=전체)
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMerchantCampaignsRequest) Reset() {
+	*x = GetMerchantCampaignsRequest{}
+	mi := &file_proto_query_merchants_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMerchantCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMerchantCampaignsRequest) ProtoMessage() {}
+
+func (x *GetMerchantCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMerchantCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*GetMerchantCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetMerchantCampaignsRequest) GetMerchantId() string {
+	if x != nil {
+		return x.MerchantId
+	}
+	return ""
+}
+
+func (x *GetMerchantCampaignsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMerchantCampaignsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetMerchantCampaignsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetMerchantCampaignsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// 머천트 데이터 구조 (merchants, pkg/models.Merchant)
+type Merchant struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Id                   string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                         // 머천트로 등록한 사용자 id
+	PayoutWallet         string                 `protobuf:"bytes,2,opt,name=payout_wallet,json=payoutWallet,proto3" json:"payout_wallet,omitempty"` // EIP-55 체크섬 주소
+	BusinessName         string                 `protobuf:"bytes,3,opt,name=business_name,json=businessName,proto3" json:"business_name,omitempty"`
+	CreatedAt            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CampaignCount        int64                  `protobuf:"varint,5,opt,name=campaign_count,json=campaignCount,proto3" json:"campaign_count,omitempty"`                        // 등록된 캠페인 수
+	ActiveCampaignCount  int64                  `protobuf:"varint,6,opt,name=active_campaign_count,json=activeCampaignCount,proto3" json:"active_campaign_count,omitempty"`    // 진행 중인 캠페인 수 (models.ActiveStatuses)
+	TotalVolume          string                 `protobuf:"bytes,7,opt,name=total_volume,json=totalVolume,proto3" json:"total_volume,omitempty"`                               // 전체 캠페인 예치 금액 합계 (USDT 소수 문자열)
+	SettledCampaignCount int64                  `protobuf:"varint,8,opt,name=settled_campaign_count,json=settledCampaignCount,proto3" json:"settled_campaign_count,omitempty"` // 정산된 캠페인 수
+	Status               string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                                                            // models.MerchantStatus
+	LogoUrl              string                 `protobuf:"bytes,10,opt,name=logo_url,json=logoUrl,proto3" json:"logo_url,omitempty"`                                          // 업로드된 로고 (없으면 빈 값)
+	LogoThumbnailUrl     string                 `protobuf:"bytes,11,opt,name=logo_thumbnail_url,json=logoThumbnailUrl,proto3" json:"logo_thumbnail_url,omitempty"`             // 로고 썸네일
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Merchant) Reset() {
+	*x = Merchant{}
+	mi := &file_proto_query_merchants_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Merchant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Merchant) ProtoMessage() {}
+
+func (x *Merchant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Merchant.ProtoReflect.Descriptor instead.
+func (*Merchant) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Merchant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Merchant) GetPayoutWallet() string {
+	if x != nil {
+		return x.PayoutWallet
+	}
+	return ""
+}
+
+func (x *Merchant) GetBusinessName() string {
+	if x != nil {
+		return x.BusinessName
+	}
+	return ""
+}
+
+func (x *Merchant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Merchant) GetCampaignCount() int64 {
+	if x != nil {
+		return x.CampaignCount
+	}
+	return 0
+}
+
+func (x *Merchant) GetActiveCampaignCount() int64 {
+	if x != nil {
+		return x.ActiveCampaignCount
+	}
+	return 0
+}
+
+func (x *Merchant) GetTotalVolume() string {
+	if x != nil {
+		return x.TotalVolume
+	}
+	return ""
+}
+
+func (x *Merchant) GetSettledCampaignCount() int64 {
+	if x != nil {
+		return x.SettledCampaignCount
+	}
+	return 0
+}
+
+func (x *Merchant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Merchant) GetLogoUrl() string {
+	if x != nil {
+		return x.LogoUrl
+	}
+	return ""
+}
+
+func (x *Merchant) GetLogoThumbnailUrl() string {
+	if x != nil {
+		return x.LogoThumbnailUrl
+	}
+	return ""
+}
+
+// 캠페인 정산 데이터 구조 (status가 settled인 캠페인과 정산된 참여 집계)
+type Settlement struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId       string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CampaignAddress  string                 `protobuf:"bytes,2,opt,name=campaign_address,json=campaignAddress,proto3" json:"campaign_address,omitempty"`     // JOIN으로 가져온 캠페인 주소 (EIP-55)
+	SettledAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=settled_at,json=settledAt,proto3" json:"settled_at,omitempty"`                       // campaigns.settlement_date
+	TotalDeposit     string                 `protobuf:"bytes,4,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`              // 정산된 참여의 예치 금액 합계 (USDT 소수 문자열)
+	TotalRebate      string                 `protobuf:"bytes,5,opt,name=total_rebate,json=totalRebate,proto3" json:"total_rebate,omitempty"`                 // 지급된 리베이트 합계
+	ParticipantCount int64                  `protobuf:"varint,6,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"` // 정산된 참여 수
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Settlement) Reset() {
+	*x = Settlement{}
+	mi := &file_proto_query_merchants_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Settlement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Settlement) ProtoMessage() {}
+
+func (x *Settlement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_merchants_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Settlement.ProtoReflect.Descriptor instead.
+func (*Settlement) Descriptor() ([]byte, []int) {
+	return file_proto_query_merchants_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Settlement) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *Settlement) GetCampaignAddress() string {
+	if x != nil {
+		return x.CampaignAddress
+	}
+	return ""
+}
+
+func (x *Settlement) GetSettledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettledAt
+	}
+	return nil
+}
+
+func (x *Settlement) GetTotalDeposit() string {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return ""
+}
+
+func (x *Settlement) GetTotalRebate() string {
+	if x != nil {
+		return x.TotalRebate
+	}
+	return ""
+}
+
+func (x *Settlement) GetParticipantCount() int64 {
+	if x != nil {
+		return x.ParticipantCount
+	}
+	return 0
+}
+
+var File_proto_query_merchants_proto protoreflect.FileDescriptor
+
+const file_proto_query_merchants_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/query/merchants.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bproto/query/campaigns.proto\"m\n" +
+	"\x13GetMerchantsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x10\n" +
+	"\x03ids\x18\x04 \x03(\tR\x03ids\"\x87\x01\n" +
+	"\x14GetMerchantsResponse\x12-\n" +
+	"\tmerchants\x18\x01 \x03(\v2\x0f.query.MerchantR\tmerchants\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"5\n" +
+	"\x12GetMerchantRequest\x12\x1f\n" +
+	"\vmerchant_id\x18\x01 \x01(\tR\n" +
+	"merchantId\"\x8d\x01\n" +
+	"\x13GetMerchantResponse\x12+\n" +
+	"\bmerchant\x18\x01 \x01(\v2\x0f.query.MerchantR\bmerchant\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x123\n" +
+	"\vsettlements\x18\x03 \x03(\v2\x11.query.SettlementR\vsettlements\"\x9c\x01\n" +
+	"\x1bGetMerchantCampaignsRequest\x12\x1f\n" +
+	"\vmerchant_id\x18\x01 \x01(\tR\n" +
+	"merchantId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\xb4\x03\n" +
+	"\bMerchant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rpayout_wallet\x18\x02 \x01(\tR\fpayoutWallet\x12#\n" +
+	"\rbusiness_name\x18\x03 \x01(\tR\fbusinessName\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12%\n" +
+	"\x0ecampaign_count\x18\x05 \x01(\x03R\rcampaignCount\x122\n" +
+	"\x15active_campaign_count\x18\x06 \x01(\x03R\x13activeCampaignCount\x12!\n" +
+	"\ftotal_volume\x18\a \x01(\tR\vtotalVolume\x124\n" +
+	"\x16settled_campaign_count\x18\b \x01(\x03R\x14settledCampaignCount\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x19\n" +
+	"\blogo_url\x18\n" +
+	" \x01(\tR\alogoUrl\x12,\n" +
+	"\x12logo_thumbnail_url\x18\v \x01(\tR\x10logoThumbnailUrl\"\x88\x02\n" +
+	"\n" +
+	"Settlement\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12)\n" +
+	"\x10campaign_address\x18\x02 \x01(\tR\x0fcampaignAddress\x129\n" +
+	"\n" +
+	"settled_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tsettledAt\x12#\n" +
+	"\rtotal_deposit\x18\x04 \x01(\tR\ftotalDeposit\x12!\n" +
+	"\ftotal_rebate\x18\x05 \x01(\tR\vtotalRebate\x12+\n" +
+	"\x11participant_count\x18\x06 \x01(\x03R\x10participantCount2\xf9\x01\n" +
+	"\x0fMerchantService\x12G\n" +
+	"\fGetMerchants\x12\x1a.query.GetMerchantsRequest\x1a\x1b.query.GetMerchantsResponse\x12D\n" +
+	"\vGetMerchant\x12\x19.query.GetMerchantRequest\x1a\x1a.query.GetMerchantResponse\x12W\n" +
+	"\x14GetMerchantCampaigns\x12\".query.GetMerchantCampaignsRequest\x1a\x1b.query.GetCampaignsResponseB\tZ\a./queryb\x06proto3"
+
+var (
+	file_proto_query_merchants_proto_rawDescOnce sync.Once
+	file_proto_query_merchants_proto_rawDescData []byte
+)
+
+func file_proto_query_merchants_proto_rawDescGZIP() []byte {
+	file_proto_query_merchants_proto_rawDescOnce.Do(func() {
+		file_proto_query_merchants_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_query_merchants_proto_rawDesc), len(file_proto_query_merchants_proto_rawDesc)))
+	})
+	return file_proto_query_merchants_proto_rawDescData
+}
+
+var file_proto_query_merchants_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_query_merchants_proto_goTypes = []any{
+	(*GetMerchantsRequest)(nil),         // 0: query.GetMerchantsRequest
+	(*GetMerchantsResponse)(nil),        // 1: query.GetMerchantsResponse
+	(*GetMerchantRequest)(nil),          // 2: query.GetMerchantRequest
+	(*GetMerchantResponse)(nil),         // 3: query.GetMerchantResponse
+	(*GetMerchantCampaignsRequest)(nil), // 4: query.GetMerchantCampaignsRequest
+	(*Merchant)(nil),                    // 5: query.Merchant
+	(*Settlement)(nil),                  // 6: query.Settlement
+	(*timestamppb.Timestamp)(nil),       // 7: google.protobuf.Timestamp
+	(*GetCampaignsResponse)(nil),        // 8: query.GetCampaignsResponse
+}
+var file_proto_query_merchants_proto_depIdxs = []int32{
+	5, // 0: query.GetMerchantsResponse.merchants:type_name -> query.Merchant
+	5, // 1: query.GetMerchantResponse.merchant:type_name -> query.Merchant
+	6, // 2: query.GetMerchantResponse.settlements:type_name -> query.Settlement
+	7, // 3: query.Merchant.created_at:type_name -> google.protobuf.Timestamp
+	7, // 4: query.Settlement.settled_at:type_name -> google.protobuf.Timestamp
+	0, // 5: query.MerchantService.GetMerchants:input_type -> query.GetMerchantsRequest
+	2, // 6: query.MerchantService.GetMerchant:input_type -> query.GetMerchantRequest
+	4, // 7: query.MerchantService.GetMerchantCampaigns:input_type -> query.GetMerchantCampaignsRequest
+	1, // 8: query.MerchantService.GetMerchants:output_type -> query.GetMerchantsResponse
+	3, // 9: query.MerchantService.GetMerchant:output_type -> query.GetMerchantResponse
+	8, // 10: query.MerchantService.GetMerchantCampaigns:output_type -> query.GetCampaignsResponse
+	8, // [8:11] is the sub-list for method output_type
+	5, // [5:8] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_query_merchants_proto_init() }
+func file_proto_query_merchants_proto_init() {
+	if File_proto_query_merchants_proto != nil {
+		return
+	}
+	file_proto_query_campaigns_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_query_merchants_proto_rawDesc), len(file_proto_query_merchants_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_query_merchants_proto_goTypes,
+		DependencyIndexes: file_proto_query_merchants_proto_depIdxs,
+		MessageInfos:      file_proto_query_merchants_proto_msgTypes,
+	}.Build()
+	File_proto_query_merchants_proto = out.File
+	file_proto_query_merchants_proto_goTypes = nil
+	file_proto_query_merchants_proto_depIdxs = nil
+}
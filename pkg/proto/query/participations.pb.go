@@ -0,0 +1,746 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.0
+// source: proto/query/participations.proto
+
+package query
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 사용자 참여 목록 조회 요청
+type GetUserParticipationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`   // 페이지 크기 (기본값: 20, 최대: 100)
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"` // 오프셋 (기본값: 0)
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`  // 참여 상태 필터 (옵션, 빈 값=전체)
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`  // 이전 응답의 next_cursor (지정 시 offset 무시)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserParticipationsRequest) Reset() {
+	*x = GetUserParticipationsRequest{}
+	mi := &file_proto_query_participations_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserParticipationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserParticipationsRequest) ProtoMessage() {}
+
+func (x *GetUserParticipationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserParticipationsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserParticipationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUserParticipationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserParticipationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetUserParticipationsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetUserParticipationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetUserParticipationsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// 캠페인 참여자 목록 조회 요청
+type GetCampaignParticipationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignParticipationsRequest) Reset() {
+	*x = GetCampaignParticipationsRequest{}
+	mi := &file_proto_query_participations_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignParticipationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignParticipationsRequest) ProtoMessage() {}
+
+func (x *GetCampaignParticipationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignParticipationsRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignParticipationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetCampaignParticipationsRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *GetCampaignParticipationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetCampaignParticipationsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetCampaignParticipationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetCampaignParticipationsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// 참여 목록 조회 응답
+type GetParticipationsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Participations []*Participation       `protobuf:"bytes,1,rep,name=participations,proto3" json:"participations,omitempty"`
+	TotalCount     int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	NextCursor     string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // 다음 페이지 커서 (마지막 페이지면 빈 값)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetParticipationsResponse) Reset() {
+	*x = GetParticipationsResponse{}
+	mi := &file_proto_query_participations_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetParticipationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetParticipationsResponse) ProtoMessage() {}
+
+func (x *GetParticipationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetParticipationsResponse.ProtoReflect.Descriptor instead.
+func (*GetParticipationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetParticipationsResponse) GetParticipations() []*Participation {
+	if x != nil {
+		return x.Participations
+	}
+	return nil
+}
+
+func (x *GetParticipationsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetParticipationsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// 참여 스트리밍 요청
+type StreamParticipationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"` // 캠페인 필터 (옵션, 빈 값=전체)
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`             // 사용자 필터 (옵션, 빈 값=전체)
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                           // 참여 상태 필터 (옵션, 빈 값=전체)
+	AfterId       string                 `protobuf:"bytes,4,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`          // 이 id 다음부터 전송 (끊긴 스트림 이어받기, 빈 값=처음부터)
+	BatchSize     int32                  `protobuf:"varint,5,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`   // 배치 크기 (기본값: 500, 최대: 1000)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamParticipationsRequest) Reset() {
+	*x = StreamParticipationsRequest{}
+	mi := &file_proto_query_participations_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamParticipationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamParticipationsRequest) ProtoMessage() {}
+
+func (x *StreamParticipationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamParticipationsRequest.ProtoReflect.Descriptor instead.
+func (*StreamParticipationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamParticipationsRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *StreamParticipationsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StreamParticipationsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StreamParticipationsRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *StreamParticipationsRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+// 참여 스트리밍 배치
+type ParticipationBatch struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Participations []*Participation       `protobuf:"bytes,1,rep,name=participations,proto3" json:"participations,omitempty"`
+	LastId         string                 `protobuf:"bytes,2,opt,name=last_id,json=lastId,proto3" json:"last_id,omitempty"` // 배치의 마지막 id (이어받을 때 after_id로 전달)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ParticipationBatch) Reset() {
+	*x = ParticipationBatch{}
+	mi := &file_proto_query_participations_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParticipationBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParticipationBatch) ProtoMessage() {}
+
+func (x *ParticipationBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParticipationBatch.ProtoReflect.Descriptor instead.
+func (*ParticipationBatch) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ParticipationBatch) GetParticipations() []*Participation {
+	if x != nil {
+		return x.Participations
+	}
+	return nil
+}
+
+func (x *ParticipationBatch) GetLastId() string {
+	if x != nil {
+		return x.LastId
+	}
+	return ""
+}
+
+// 특정 참여 조회 요청
+type GetParticipationRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ParticipationId string                 `protobuf:"bytes,1,opt,name=participation_id,json=participationId,proto3" json:"participation_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetParticipationRequest) Reset() {
+	*x = GetParticipationRequest{}
+	mi := &file_proto_query_participations_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetParticipationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetParticipationRequest) ProtoMessage() {}
+
+func (x *GetParticipationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetParticipationRequest.ProtoReflect.Descriptor instead.
+func (*GetParticipationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetParticipationRequest) GetParticipationId() string {
+	if x != nil {
+		return x.ParticipationId
+	}
+	return ""
+}
+
+// 특정 참여 조회 응답
+type GetParticipationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Participation *Participation         `protobuf:"bytes,1,opt,name=participation,proto3" json:"participation,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetParticipationResponse) Reset() {
+	*x = GetParticipationResponse{}
+	mi := &file_proto_query_participations_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetParticipationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetParticipationResponse) ProtoMessage() {}
+
+func (x *GetParticipationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetParticipationResponse.ProtoReflect.Descriptor instead.
+func (*GetParticipationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetParticipationResponse) GetParticipation() *Participation {
+	if x != nil {
+		return x.Participation
+	}
+	return nil
+}
+
+func (x *GetParticipationResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 참여 데이터 구조 (participations, pkg/models.Participation). 금액은 USDT 소수 문자열입니다.
+type Participation struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CampaignId        string                 `protobuf:"bytes,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CampaignAddress   string                 `protobuf:"bytes,3,opt,name=campaign_address,json=campaignAddress,proto3" json:"campaign_address,omitempty"` // JOIN으로 가져온 캠페인 주소 (EIP-55)
+	UserId            string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WalletAddress     string                 `protobuf:"bytes,5,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"` // 참여한 지갑 주소 (EIP-55)
+	DepositAmount     string                 `protobuf:"bytes,6,opt,name=deposit_amount,json=depositAmount,proto3" json:"deposit_amount,omitempty"`
+	JoinedAt          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	Status            string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                                                   // models.Participation* 상태
+	ExpectedRebateMin string                 `protobuf:"bytes,9,opt,name=expected_rebate_min,json=expectedRebateMin,proto3" json:"expected_rebate_min,omitempty"`  // 캠페인 save_floor_bps 기준 최소 예상 리베이트
+	ExpectedRebateMax string                 `protobuf:"bytes,10,opt,name=expected_rebate_max,json=expectedRebateMax,proto3" json:"expected_rebate_max,omitempty"` // 캠페인 r_max_bps 기준 최대 예상 리베이트
+	ActualRebate      string                 `protobuf:"bytes,11,opt,name=actual_rebate,json=actualRebate,proto3" json:"actual_rebate,omitempty"`                  // 정산으로 지급된 리베이트 (정산 전이면 빈 값)
+	CancelPending     string                 `protobuf:"bytes,12,opt,name=cancel_pending,json=cancelPending,proto3" json:"cancel_pending,omitempty"`               // 취소 대기 중인 금액
+	Version           int64                  `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`                                               // 변경마다 증가, 취소 시 If-Match로 전달
+	CancelPenalty     string                 `protobuf:"bytes,14,opt,name=cancel_penalty,json=cancelPenalty,proto3" json:"cancel_penalty,omitempty"`               // 지금까지 취소로 차감된 페널티 합계
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Participation) Reset() {
+	*x = Participation{}
+	mi := &file_proto_query_participations_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Participation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Participation) ProtoMessage() {}
+
+func (x *Participation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_participations_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Participation.ProtoReflect.Descriptor instead.
+func (*Participation) Descriptor() ([]byte, []int) {
+	return file_proto_query_participations_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Participation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Participation) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *Participation) GetCampaignAddress() string {
+	if x != nil {
+		return x.CampaignAddress
+	}
+	return ""
+}
+
+func (x *Participation) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Participation) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *Participation) GetDepositAmount() string {
+	if x != nil {
+		return x.DepositAmount
+	}
+	return ""
+}
+
+func (x *Participation) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
+func (x *Participation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Participation) GetExpectedRebateMin() string {
+	if x != nil {
+		return x.ExpectedRebateMin
+	}
+	return ""
+}
+
+func (x *Participation) GetExpectedRebateMax() string {
+	if x != nil {
+		return x.ExpectedRebateMax
+	}
+	return ""
+}
+
+func (x *Participation) GetActualRebate() string {
+	if x != nil {
+		return x.ActualRebate
+	}
+	return ""
+}
+
+func (x *Participation) GetCancelPending() string {
+	if x != nil {
+		return x.CancelPending
+	}
+	return ""
+}
+
+func (x *Participation) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Participation) GetCancelPenalty() string {
+	if x != nil {
+		return x.CancelPenalty
+	}
+	return ""
+}
+
+var File_proto_query_participations_proto protoreflect.FileDescriptor
+
+const file_proto_query_participations_proto_rawDesc = "" +
+	"\n" +
+	" proto/query/participations.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x01\n" +
+	"\x1cGetUserParticipationsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\xa1\x01\n" +
+	" GetCampaignParticipationsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\x9b\x01\n" +
+	"\x19GetParticipationsResponse\x12<\n" +
+	"\x0eparticipations\x18\x01 \x03(\v2\x14.query.ParticipationR\x0eparticipations\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x03R\n" +
+	"totalCount\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"\xa9\x01\n" +
+	"\x1bStreamParticipationsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x19\n" +
+	"\bafter_id\x18\x04 \x01(\tR\aafterId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x05 \x01(\x05R\tbatchSize\"k\n" +
+	"\x12ParticipationBatch\x12<\n" +
+	"\x0eparticipations\x18\x01 \x03(\v2\x14.query.ParticipationR\x0eparticipations\x12\x17\n" +
+	"\alast_id\x18\x02 \x01(\tR\x06lastId\"D\n" +
+	"\x17GetParticipationRequest\x12)\n" +
+	"\x10participation_id\x18\x01 \x01(\tR\x0fparticipationId\"l\n" +
+	"\x18GetParticipationResponse\x12:\n" +
+	"\rparticipation\x18\x01 \x01(\v2\x14.query.ParticipationR\rparticipation\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\x90\x04\n" +
+	"\rParticipation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\tR\n" +
+	"campaignId\x12)\n" +
+	"\x10campaign_address\x18\x03 \x01(\tR\x0fcampaignAddress\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12%\n" +
+	"\x0ewallet_address\x18\x05 \x01(\tR\rwalletAddress\x12%\n" +
+	"\x0edeposit_amount\x18\x06 \x01(\tR\rdepositAmount\x127\n" +
+	"\tjoined_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12.\n" +
+	"\x13expected_rebate_min\x18\t \x01(\tR\x11expectedRebateMin\x12.\n" +
+	"\x13expected_rebate_max\x18\n" +
+	" \x01(\tR\x11expectedRebateMax\x12#\n" +
+	"\ractual_rebate\x18\v \x01(\tR\factualRebate\x12%\n" +
+	"\x0ecancel_pending\x18\f \x01(\tR\rcancelPending\x12\x18\n" +
+	"\aversion\x18\r \x01(\x03R\aversion\x12%\n" +
+	"\x0ecancel_penalty\x18\x0e \x01(\tR\rcancelPenalty2\x8c\x03\n" +
+	"\x14ParticipationService\x12^\n" +
+	"\x15GetUserParticipations\x12#.query.GetUserParticipationsRequest\x1a .query.GetParticipationsResponse\x12f\n" +
+	"\x19GetCampaignParticipations\x12'.query.GetCampaignParticipationsRequest\x1a .query.GetParticipationsResponse\x12S\n" +
+	"\x10GetParticipation\x12\x1e.query.GetParticipationRequest\x1a\x1f.query.GetParticipationResponse\x12W\n" +
+	"\x14StreamParticipations\x12\".query.StreamParticipationsRequest\x1a\x19.query.ParticipationBatch0\x01B\tZ\a./queryb\x06proto3"
+
+var (
+	file_proto_query_participations_proto_rawDescOnce sync.Once
+	file_proto_query_participations_proto_rawDescData []byte
+)
+
+func file_proto_query_participations_proto_rawDescGZIP() []byte {
+	file_proto_query_participations_proto_rawDescOnce.Do(func() {
+		file_proto_query_participations_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_query_participations_proto_rawDesc), len(file_proto_query_participations_proto_rawDesc)))
+	})
+	return file_proto_query_participations_proto_rawDescData
+}
+
+var file_proto_query_participations_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_query_participations_proto_goTypes = []any{
+	(*GetUserParticipationsRequest)(nil),     // 0: query.GetUserParticipationsRequest
+	(*GetCampaignParticipationsRequest)(nil), // 1: query.GetCampaignParticipationsRequest
+	(*GetParticipationsResponse)(nil),        // 2: query.GetParticipationsResponse
+	(*StreamParticipationsRequest)(nil),      // 3: query.StreamParticipationsRequest
+	(*ParticipationBatch)(nil),               // 4: query.ParticipationBatch
+	(*GetParticipationRequest)(nil),          // 5: query.GetParticipationRequest
+	(*GetParticipationResponse)(nil),         // 6: query.GetParticipationResponse
+	(*Participation)(nil),                    // 7: query.Participation
+	(*timestamppb.Timestamp)(nil),            // 8: google.protobuf.Timestamp
+}
+var file_proto_query_participations_proto_depIdxs = []int32{
+	7, // 0: query.GetParticipationsResponse.participations:type_name -> query.Participation
+	7, // 1: query.ParticipationBatch.participations:type_name -> query.Participation
+	7, // 2: query.GetParticipationResponse.participation:type_name -> query.Participation
+	8, // 3: query.Participation.joined_at:type_name -> google.protobuf.Timestamp
+	0, // 4: query.ParticipationService.GetUserParticipations:input_type -> query.GetUserParticipationsRequest
+	1, // 5: query.ParticipationService.GetCampaignParticipations:input_type -> query.GetCampaignParticipationsRequest
+	5, // 6: query.ParticipationService.GetParticipation:input_type -> query.GetParticipationRequest
+	3, // 7: query.ParticipationService.StreamParticipations:input_type -> query.StreamParticipationsRequest
+	2, // 8: query.ParticipationService.GetUserParticipations:output_type -> query.GetParticipationsResponse
+	2, // 9: query.ParticipationService.GetCampaignParticipations:output_type -> query.GetParticipationsResponse
+	6, // 10: query.ParticipationService.GetParticipation:output_type -> query.GetParticipationResponse
+	4, // 11: query.ParticipationService.StreamParticipations:output_type -> query.ParticipationBatch
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_query_participations_proto_init() }
+func file_proto_query_participations_proto_init() {
+	if File_proto_query_participations_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_query_participations_proto_rawDesc), len(file_proto_query_participations_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_query_participations_proto_goTypes,
+		DependencyIndexes: file_proto_query_participations_proto_depIdxs,
+		MessageInfos:      file_proto_query_participations_proto_msgTypes,
+	}.Build()
+	File_proto_query_participations_proto = out.File
+	file_proto_query_participations_proto_goTypes = nil
+	file_proto_query_participations_proto_depIdxs = nil
+}
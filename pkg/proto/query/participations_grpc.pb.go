@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.0
+// source: proto/query/participations.proto
+
+package query
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ParticipationService_GetUserParticipations_FullMethodName     = "/query.ParticipationService/GetUserParticipations"
+	ParticipationService_GetCampaignParticipations_FullMethodName = "/query.ParticipationService/GetCampaignParticipations"
+	ParticipationService_GetParticipation_FullMethodName          = "/query.ParticipationService/GetParticipation"
+	ParticipationService_StreamParticipations_FullMethodName      = "/query.ParticipationService/StreamParticipations"
+)
+
+// ParticipationServiceClient is the client API for ParticipationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Participation 서비스 정의
+type ParticipationServiceClient interface {
+	// 사용자의 참여 목록 조회
+	GetUserParticipations(ctx context.Context, in *GetUserParticipationsRequest, opts ...grpc.CallOption) (*GetParticipationsResponse, error)
+	// 캠페인의 참여자 목록 조회
+	GetCampaignParticipations(ctx context.Context, in *GetCampaignParticipationsRequest, opts ...grpc.CallOption) (*GetParticipationsResponse, error)
+	// 특정 참여 조회
+	GetParticipation(ctx context.Context, in *GetParticipationRequest, opts ...grpc.CallOption) (*GetParticipationResponse, error)
+	// 참여 전체를 id 순 배치로 스트리밍 (배치 작업, 분석용 내보내기)
+	StreamParticipations(ctx context.Context, in *StreamParticipationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ParticipationBatch], error)
+}
+
+type participationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewParticipationServiceClient(cc grpc.ClientConnInterface) ParticipationServiceClient {
+	return &participationServiceClient{cc}
+}
+
+func (c *participationServiceClient) GetUserParticipations(ctx context.Context, in *GetUserParticipationsRequest, opts ...grpc.CallOption) (*GetParticipationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetParticipationsResponse)
+	err := c.cc.Invoke(ctx, ParticipationService_GetUserParticipations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *participationServiceClient) GetCampaignParticipations(ctx context.Context, in *GetCampaignParticipationsRequest, opts ...grpc.CallOption) (*GetParticipationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetParticipationsResponse)
+	err := c.cc.Invoke(ctx, ParticipationService_GetCampaignParticipations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *participationServiceClient) GetParticipation(ctx context.Context, in *GetParticipationRequest, opts ...grpc.CallOption) (*GetParticipationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetParticipationResponse)
+	err := c.cc.Invoke(ctx, ParticipationService_GetParticipation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *participationServiceClient) StreamParticipations(ctx context.Context, in *StreamParticipationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ParticipationBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ParticipationService_ServiceDesc.Streams[0], ParticipationService_StreamParticipations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamParticipationsRequest, ParticipationBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParticipationService_StreamParticipationsClient = grpc.ServerStreamingClient[ParticipationBatch]
+
+// ParticipationServiceServer is the server API for ParticipationService service.
+// All implementations must embed UnimplementedParticipationServiceServer
+// for forward compatibility.
+//
+// Participation 서비스 정의
+type ParticipationServiceServer interface {
+	// 사용자의 참여 목록 조회
+	GetUserParticipations(context.Context, *GetUserParticipationsRequest) (*GetParticipationsResponse, error)
+	// 캠페인의 참여자 목록 조회
+	GetCampaignParticipations(context.Context, *GetCampaignParticipationsRequest) (*GetParticipationsResponse, error)
+	// 특정 참여 조회
+	GetParticipation(context.Context, *GetParticipationRequest) (*GetParticipationResponse, error)
+	// 참여 전체를 id 순 배치로 스트리밍 (배치 작업, 분석용 내보내기)
+	StreamParticipations(*StreamParticipationsRequest, grpc.ServerStreamingServer[ParticipationBatch]) error
+	mustEmbedUnimplementedParticipationServiceServer()
+}
+
+// UnimplementedParticipationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedParticipationServiceServer struct{}
+
+func (UnimplementedParticipationServiceServer) GetUserParticipations(context.Context, *GetUserParticipationsRequest) (*GetParticipationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserParticipations not implemented")
+}
+func (UnimplementedParticipationServiceServer) GetCampaignParticipations(context.Context, *GetCampaignParticipationsRequest) (*GetParticipationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCampaignParticipations not implemented")
+}
+func (UnimplementedParticipationServiceServer) GetParticipation(context.Context, *GetParticipationRequest) (*GetParticipationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetParticipation not implemented")
+}
+func (UnimplementedParticipationServiceServer) StreamParticipations(*StreamParticipationsRequest, grpc.ServerStreamingServer[ParticipationBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamParticipations not implemented")
+}
+func (UnimplementedParticipationServiceServer) mustEmbedUnimplementedParticipationServiceServer() {}
+func (UnimplementedParticipationServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeParticipationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ParticipationServiceServer will
+// result in compilation errors.
+type UnsafeParticipationServiceServer interface {
+	mustEmbedUnimplementedParticipationServiceServer()
+}
+
+func RegisterParticipationServiceServer(s grpc.ServiceRegistrar, srv ParticipationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedParticipationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ParticipationService_ServiceDesc, srv)
+}
+
+func _ParticipationService_GetUserParticipations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserParticipationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParticipationServiceServer).GetUserParticipations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParticipationService_GetUserParticipations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParticipationServiceServer).GetUserParticipations(ctx, req.(*GetUserParticipationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParticipationService_GetCampaignParticipations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCampaignParticipationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParticipationServiceServer).GetCampaignParticipations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParticipationService_GetCampaignParticipations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParticipationServiceServer).GetCampaignParticipations(ctx, req.(*GetCampaignParticipationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParticipationService_GetParticipation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetParticipationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ParticipationServiceServer).GetParticipation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ParticipationService_GetParticipation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ParticipationServiceServer).GetParticipation(ctx, req.(*GetParticipationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ParticipationService_StreamParticipations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamParticipationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ParticipationServiceServer).StreamParticipations(m, &grpc.GenericServerStream[StreamParticipationsRequest, ParticipationBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ParticipationService_StreamParticipationsServer = grpc.ServerStreamingServer[ParticipationBatch]
+
+// ParticipationService_ServiceDesc is the grpc.ServiceDesc for ParticipationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ParticipationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "query.ParticipationService",
+	HandlerType: (*ParticipationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserParticipations",
+			Handler:    _ParticipationService_GetUserParticipations_Handler,
+		},
+		{
+			MethodName: "GetCampaignParticipations",
+			Handler:    _ParticipationService_GetCampaignParticipations_Handler,
+		},
+		{
+			MethodName: "GetParticipation",
+			Handler:    _ParticipationService_GetParticipation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamParticipations",
+			Handler:       _ParticipationService_StreamParticipations_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/query/participations.proto",
+}
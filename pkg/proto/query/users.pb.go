@@ -0,0 +1,932 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.0
+// source: proto/query/users.proto
+
+package query
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// 사용자 조회 요청
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_proto_query_users_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 지갑 주소로 사용자 조회 요청
+type GetUserByWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletAddress string                 `protobuf:"bytes,1,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"` // 0x 접두사 유무 무관, 대소문자 무관
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserByWalletRequest) Reset() {
+	*x = GetUserByWalletRequest{}
+	mi := &file_proto_query_users_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserByWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByWalletRequest) ProtoMessage() {}
+
+func (x *GetUserByWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByWalletRequest.ProtoReflect.Descriptor instead.
+func (*GetUserByWalletRequest) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUserByWalletRequest) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+// 사용자 조회 응답
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_proto_query_users_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *GetUserResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 사용자 프로필 조회 응답
+type GetUserProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *UserProfile           `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserProfileResponse) Reset() {
+	*x = GetUserProfileResponse{}
+	mi := &file_proto_query_users_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserProfileResponse) ProtoMessage() {}
+
+func (x *GetUserProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetUserProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUserProfileResponse) GetProfile() *UserProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *GetUserProfileResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 사용자 프로필 데이터 구조
+type UserProfile struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	User                *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	ActiveCampaignCount int64                  `protobuf:"varint,2,opt,name=active_campaign_count,json=activeCampaignCount,proto3" json:"active_campaign_count,omitempty"` // 진행 중인 캠페인 참여 수
+	TotalRebate         string                 `protobuf:"bytes,3,opt,name=total_rebate,json=totalRebate,proto3" json:"total_rebate,omitempty"`                            // 지급된 리베이트 합계 (USDT 소수 문자열)
+	Rebates             []*Rebate              `protobuf:"bytes,4,rep,name=rebates,proto3" json:"rebates,omitempty"`                                                       // 최근 리베이트 내역 (최신순, 최대 20건)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	mi := &file_proto_query_users_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
+func (*UserProfile) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UserProfile) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserProfile) GetActiveCampaignCount() int64 {
+	if x != nil {
+		return x.ActiveCampaignCount
+	}
+	return 0
+}
+
+func (x *UserProfile) GetTotalRebate() string {
+	if x != nil {
+		return x.TotalRebate
+	}
+	return ""
+}
+
+func (x *UserProfile) GetRebates() []*Rebate {
+	if x != nil {
+		return x.Rebates
+	}
+	return nil
+}
+
+// 사용자 자산 요약 조회 응답
+type GetUserPortfolioResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Portfolio     *UserPortfolio         `protobuf:"bytes,1,opt,name=portfolio,proto3" json:"portfolio,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserPortfolioResponse) Reset() {
+	*x = GetUserPortfolioResponse{}
+	mi := &file_proto_query_users_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserPortfolioResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserPortfolioResponse) ProtoMessage() {}
+
+func (x *GetUserPortfolioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserPortfolioResponse.ProtoReflect.Descriptor instead.
+func (*GetUserPortfolioResponse) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUserPortfolioResponse) GetPortfolio() *UserPortfolio {
+	if x != nil {
+		return x.Portfolio
+	}
+	return nil
+}
+
+func (x *GetUserPortfolioResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// 사용자 자산 요약 ("내 절약" 탭). 금액은 모두 USDT 소수 문자열이며,
+// 잠긴 참여는 정산 전인 활성/취소 대기 참여입니다.
+type UserPortfolio struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	UserId                string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TotalLocked           string                 `protobuf:"bytes,2,opt,name=total_locked,json=totalLocked,proto3" json:"total_locked,omitempty"`                                   // 잠긴 예치 금액 합계
+	LockedCount           int64                  `protobuf:"varint,3,opt,name=locked_count,json=lockedCount,proto3" json:"locked_count,omitempty"`                                  // 잠긴 참여 수
+	AccruedRebateMin      string                 `protobuf:"bytes,4,opt,name=accrued_rebate_min,json=accruedRebateMin,proto3" json:"accrued_rebate_min,omitempty"`                  // 잠금 기간이 지난 비율만큼 적립된 예상 리베이트 (save_floor_bps 기준)
+	AccruedRebateMax      string                 `protobuf:"bytes,5,opt,name=accrued_rebate_max,json=accruedRebateMax,proto3" json:"accrued_rebate_max,omitempty"`                  // 잠금 기간이 지난 비율만큼 적립된 예상 리베이트 (r_max_bps 기준)
+	ExpectedRebateMin     string                 `protobuf:"bytes,6,opt,name=expected_rebate_min,json=expectedRebateMin,proto3" json:"expected_rebate_min,omitempty"`               // 잠금 기간이 끝났을 때의 예상 리베이트 (save_floor_bps 기준)
+	ExpectedRebateMax     string                 `protobuf:"bytes,7,opt,name=expected_rebate_max,json=expectedRebateMax,proto3" json:"expected_rebate_max,omitempty"`               // 잠금 기간이 끝났을 때의 예상 리베이트 (r_max_bps 기준)
+	Upcoming              []*SettlementForecast  `protobuf:"bytes,8,rep,name=upcoming,proto3" json:"upcoming,omitempty"`                                                            // 캠페인 종료일별 정산 예정 (가까운 날부터, 종료 후 정산 대기 포함)
+	RealizedTotal         string                 `protobuf:"bytes,9,opt,name=realized_total,json=realizedTotal,proto3" json:"realized_total,omitempty"`                             // 지급된 리베이트 합계
+	RealizedCampaignCount int64                  `protobuf:"varint,10,opt,name=realized_campaign_count,json=realizedCampaignCount,proto3" json:"realized_campaign_count,omitempty"` // 리베이트를 받은 캠페인 수
+	Realized              []*MonthlySavings      `protobuf:"bytes,11,rep,name=realized,proto3" json:"realized,omitempty"`                                                           // 월별 지급 리베이트 (오래된 달부터)
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *UserPortfolio) Reset() {
+	*x = UserPortfolio{}
+	mi := &file_proto_query_users_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserPortfolio) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserPortfolio) ProtoMessage() {}
+
+func (x *UserPortfolio) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserPortfolio.ProtoReflect.Descriptor instead.
+func (*UserPortfolio) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UserPortfolio) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetTotalLocked() string {
+	if x != nil {
+		return x.TotalLocked
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetLockedCount() int64 {
+	if x != nil {
+		return x.LockedCount
+	}
+	return 0
+}
+
+func (x *UserPortfolio) GetAccruedRebateMin() string {
+	if x != nil {
+		return x.AccruedRebateMin
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetAccruedRebateMax() string {
+	if x != nil {
+		return x.AccruedRebateMax
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetExpectedRebateMin() string {
+	if x != nil {
+		return x.ExpectedRebateMin
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetExpectedRebateMax() string {
+	if x != nil {
+		return x.ExpectedRebateMax
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetUpcoming() []*SettlementForecast {
+	if x != nil {
+		return x.Upcoming
+	}
+	return nil
+}
+
+func (x *UserPortfolio) GetRealizedTotal() string {
+	if x != nil {
+		return x.RealizedTotal
+	}
+	return ""
+}
+
+func (x *UserPortfolio) GetRealizedCampaignCount() int64 {
+	if x != nil {
+		return x.RealizedCampaignCount
+	}
+	return 0
+}
+
+func (x *UserPortfolio) GetRealized() []*MonthlySavings {
+	if x != nil {
+		return x.Realized
+	}
+	return nil
+}
+
+// 하루에 캠페인이 끝나는 참여의 정산 예정
+type SettlementForecast struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Day               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=day,proto3" json:"day,omitempty"` // 캠페인 종료일 (UTC 날짜의 0시)
+	CampaignCount     int64                  `protobuf:"varint,2,opt,name=campaign_count,json=campaignCount,proto3" json:"campaign_count,omitempty"`
+	Deposit           string                 `protobuf:"bytes,3,opt,name=deposit,proto3" json:"deposit,omitempty"`
+	ExpectedRebateMin string                 `protobuf:"bytes,4,opt,name=expected_rebate_min,json=expectedRebateMin,proto3" json:"expected_rebate_min,omitempty"`
+	ExpectedRebateMax string                 `protobuf:"bytes,5,opt,name=expected_rebate_max,json=expectedRebateMax,proto3" json:"expected_rebate_max,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SettlementForecast) Reset() {
+	*x = SettlementForecast{}
+	mi := &file_proto_query_users_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SettlementForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettlementForecast) ProtoMessage() {}
+
+func (x *SettlementForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettlementForecast.ProtoReflect.Descriptor instead.
+func (*SettlementForecast) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SettlementForecast) GetDay() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Day
+	}
+	return nil
+}
+
+func (x *SettlementForecast) GetCampaignCount() int64 {
+	if x != nil {
+		return x.CampaignCount
+	}
+	return 0
+}
+
+func (x *SettlementForecast) GetDeposit() string {
+	if x != nil {
+		return x.Deposit
+	}
+	return ""
+}
+
+func (x *SettlementForecast) GetExpectedRebateMin() string {
+	if x != nil {
+		return x.ExpectedRebateMin
+	}
+	return ""
+}
+
+func (x *SettlementForecast) GetExpectedRebateMax() string {
+	if x != nil {
+		return x.ExpectedRebateMax
+	}
+	return ""
+}
+
+// 한 달 동안 정산으로 지급된 리베이트
+type MonthlySavings struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"` // 정산 월 (UTC 1일 0시)
+	CampaignCount int64                  `protobuf:"varint,2,opt,name=campaign_count,json=campaignCount,proto3" json:"campaign_count,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MonthlySavings) Reset() {
+	*x = MonthlySavings{}
+	mi := &file_proto_query_users_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MonthlySavings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonthlySavings) ProtoMessage() {}
+
+func (x *MonthlySavings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonthlySavings.ProtoReflect.Descriptor instead.
+func (*MonthlySavings) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *MonthlySavings) GetMonth() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *MonthlySavings) GetCampaignCount() int64 {
+	if x != nil {
+		return x.CampaignCount
+	}
+	return 0
+}
+
+func (x *MonthlySavings) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+// 정산으로 지급된 리베이트 데이터 구조 (정산된 참여의 actual_rebate)
+type Rebate struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ParticipationId  string                 `protobuf:"bytes,1,opt,name=participation_id,json=participationId,proto3" json:"participation_id,omitempty"`
+	CampaignId       string                 `protobuf:"bytes,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CampaignAddress  string                 `protobuf:"bytes,3,opt,name=campaign_address,json=campaignAddress,proto3" json:"campaign_address,omitempty"`      // JOIN으로 가져온 캠페인 주소 (EIP-55)
+	Amount           string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`                                               // USDT 소수 문자열
+	SettlementTxHash string                 `protobuf:"bytes,5,opt,name=settlement_tx_hash,json=settlementTxHash,proto3" json:"settlement_tx_hash,omitempty"` // 리베이트 지급 트랜잭션 (없으면 빈 값)
+	SettledAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=settled_at,json=settledAt,proto3" json:"settled_at,omitempty"`                        // campaigns.settlement_date
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Rebate) Reset() {
+	*x = Rebate{}
+	mi := &file_proto_query_users_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rebate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rebate) ProtoMessage() {}
+
+func (x *Rebate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rebate.ProtoReflect.Descriptor instead.
+func (*Rebate) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Rebate) GetParticipationId() string {
+	if x != nil {
+		return x.ParticipationId
+	}
+	return ""
+}
+
+func (x *Rebate) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *Rebate) GetCampaignAddress() string {
+	if x != nil {
+		return x.CampaignAddress
+	}
+	return ""
+}
+
+func (x *Rebate) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Rebate) GetSettlementTxHash() string {
+	if x != nil {
+		return x.SettlementTxHash
+	}
+	return ""
+}
+
+func (x *Rebate) GetSettledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SettledAt
+	}
+	return nil
+}
+
+// 사용자 데이터 구조 (users, pkg/models.User)
+type User struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WalletAddress      string                 `protobuf:"bytes,2,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"` // EIP-55 체크섬 주소
+	LineUserId         string                 `protobuf:"bytes,3,opt,name=line_user_id,json=lineUserId,proto3" json:"line_user_id,omitempty"`
+	Status             string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // models.User* 상태
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ParticipationCount int64                  `protobuf:"varint,6,opt,name=participation_count,json=participationCount,proto3" json:"participation_count,omitempty"` // 참여한 캠페인 수
+	TotalDeposit       string                 `protobuf:"bytes,7,opt,name=total_deposit,json=totalDeposit,proto3" json:"total_deposit,omitempty"`                    // 전체 예치 금액 (USDT 소수 문자열)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_proto_query_users_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_query_users_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_proto_query_users_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetWalletAddress() string {
+	if x != nil {
+		return x.WalletAddress
+	}
+	return ""
+}
+
+func (x *User) GetLineUserId() string {
+	if x != nil {
+		return x.LineUserId
+	}
+	return ""
+}
+
+func (x *User) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetParticipationCount() int64 {
+	if x != nil {
+		return x.ParticipationCount
+	}
+	return 0
+}
+
+func (x *User) GetTotalDeposit() string {
+	if x != nil {
+		return x.TotalDeposit
+	}
+	return ""
+}
+
+var File_proto_query_users_proto protoreflect.FileDescriptor
+
+const file_proto_query_users_proto_rawDesc = "" +
+	"\n" +
+	"\x17proto/query/users.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\")\n" +
+	"\x0eGetUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
+	"\x16GetUserByWalletRequest\x12%\n" +
+	"\x0ewallet_address\x18\x01 \x01(\tR\rwalletAddress\"H\n" +
+	"\x0fGetUserResponse\x12\x1f\n" +
+	"\x04user\x18\x01 \x01(\v2\v.query.UserR\x04user\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\\\n" +
+	"\x16GetUserProfileResponse\x12,\n" +
+	"\aprofile\x18\x01 \x01(\v2\x12.query.UserProfileR\aprofile\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xae\x01\n" +
+	"\vUserProfile\x12\x1f\n" +
+	"\x04user\x18\x01 \x01(\v2\v.query.UserR\x04user\x122\n" +
+	"\x15active_campaign_count\x18\x02 \x01(\x03R\x13activeCampaignCount\x12!\n" +
+	"\ftotal_rebate\x18\x03 \x01(\tR\vtotalRebate\x12'\n" +
+	"\arebates\x18\x04 \x03(\v2\r.query.RebateR\arebates\"d\n" +
+	"\x18GetUserPortfolioResponse\x122\n" +
+	"\tportfolio\x18\x01 \x01(\v2\x14.query.UserPortfolioR\tportfolio\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xf3\x03\n" +
+	"\rUserPortfolio\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\ftotal_locked\x18\x02 \x01(\tR\vtotalLocked\x12!\n" +
+	"\flocked_count\x18\x03 \x01(\x03R\vlockedCount\x12,\n" +
+	"\x12accrued_rebate_min\x18\x04 \x01(\tR\x10accruedRebateMin\x12,\n" +
+	"\x12accrued_rebate_max\x18\x05 \x01(\tR\x10accruedRebateMax\x12.\n" +
+	"\x13expected_rebate_min\x18\x06 \x01(\tR\x11expectedRebateMin\x12.\n" +
+	"\x13expected_rebate_max\x18\a \x01(\tR\x11expectedRebateMax\x125\n" +
+	"\bupcoming\x18\b \x03(\v2\x19.query.SettlementForecastR\bupcoming\x12%\n" +
+	"\x0erealized_total\x18\t \x01(\tR\rrealizedTotal\x126\n" +
+	"\x17realized_campaign_count\x18\n" +
+	" \x01(\x03R\x15realizedCampaignCount\x121\n" +
+	"\brealized\x18\v \x03(\v2\x15.query.MonthlySavingsR\brealized\"\xe3\x01\n" +
+	"\x12SettlementForecast\x12,\n" +
+	"\x03day\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x03day\x12%\n" +
+	"\x0ecampaign_count\x18\x02 \x01(\x03R\rcampaignCount\x12\x18\n" +
+	"\adeposit\x18\x03 \x01(\tR\adeposit\x12.\n" +
+	"\x13expected_rebate_min\x18\x04 \x01(\tR\x11expectedRebateMin\x12.\n" +
+	"\x13expected_rebate_max\x18\x05 \x01(\tR\x11expectedRebateMax\"\x81\x01\n" +
+	"\x0eMonthlySavings\x120\n" +
+	"\x05month\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05month\x12%\n" +
+	"\x0ecampaign_count\x18\x02 \x01(\x03R\rcampaignCount\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\"\x80\x02\n" +
+	"\x06Rebate\x12)\n" +
+	"\x10participation_id\x18\x01 \x01(\tR\x0fparticipationId\x12\x1f\n" +
+	"\vcampaign_id\x18\x02 \x01(\tR\n" +
+	"campaignId\x12)\n" +
+	"\x10campaign_address\x18\x03 \x01(\tR\x0fcampaignAddress\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12,\n" +
+	"\x12settlement_tx_hash\x18\x05 \x01(\tR\x10settlementTxHash\x129\n" +
+	"\n" +
+	"settled_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tsettledAt\"\x88\x02\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0ewallet_address\x18\x02 \x01(\tR\rwalletAddress\x12 \n" +
+	"\fline_user_id\x18\x03 \x01(\tR\n" +
+	"lineUserId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12/\n" +
+	"\x13participation_count\x18\x06 \x01(\x03R\x12participationCount\x12#\n" +
+	"\rtotal_deposit\x18\a \x01(\tR\ftotalDeposit2\xa5\x02\n" +
+	"\vUserService\x128\n" +
+	"\aGetUser\x12\x15.query.GetUserRequest\x1a\x16.query.GetUserResponse\x12H\n" +
+	"\x0fGetUserByWallet\x12\x1d.query.GetUserByWalletRequest\x1a\x16.query.GetUserResponse\x12F\n" +
+	"\x0eGetUserProfile\x12\x15.query.GetUserRequest\x1a\x1d.query.GetUserProfileResponse\x12J\n" +
+	"\x10GetUserPortfolio\x12\x15.query.GetUserRequest\x1a\x1f.query.GetUserPortfolioResponseB\tZ\a./queryb\x06proto3"
+
+var (
+	file_proto_query_users_proto_rawDescOnce sync.Once
+	file_proto_query_users_proto_rawDescData []byte
+)
+
+func file_proto_query_users_proto_rawDescGZIP() []byte {
+	file_proto_query_users_proto_rawDescOnce.Do(func() {
+		file_proto_query_users_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_query_users_proto_rawDesc), len(file_proto_query_users_proto_rawDesc)))
+	})
+	return file_proto_query_users_proto_rawDescData
+}
+
+var file_proto_query_users_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_query_users_proto_goTypes = []any{
+	(*GetUserRequest)(nil),           // 0: query.GetUserRequest
+	(*GetUserByWalletRequest)(nil),   // 1: query.GetUserByWalletRequest
+	(*GetUserResponse)(nil),          // 2: query.GetUserResponse
+	(*GetUserProfileResponse)(nil),   // 3: query.GetUserProfileResponse
+	(*UserProfile)(nil),              // 4: query.UserProfile
+	(*GetUserPortfolioResponse)(nil), // 5: query.GetUserPortfolioResponse
+	(*UserPortfolio)(nil),            // 6: query.UserPortfolio
+	(*SettlementForecast)(nil),       // 7: query.SettlementForecast
+	(*MonthlySavings)(nil),           // 8: query.MonthlySavings
+	(*Rebate)(nil),                   // 9: query.Rebate
+	(*User)(nil),                     // 10: query.User
+	(*timestamppb.Timestamp)(nil),    // 11: google.protobuf.Timestamp
+}
+var file_proto_query_users_proto_depIdxs = []int32{
+	10, // 0: query.GetUserResponse.user:type_name -> query.User
+	4,  // 1: query.GetUserProfileResponse.profile:type_name -> query.UserProfile
+	10, // 2: query.UserProfile.user:type_name -> query.User
+	9,  // 3: query.UserProfile.rebates:type_name -> query.Rebate
+	6,  // 4: query.GetUserPortfolioResponse.portfolio:type_name -> query.UserPortfolio
+	7,  // 5: query.UserPortfolio.upcoming:type_name -> query.SettlementForecast
+	8,  // 6: query.UserPortfolio.realized:type_name -> query.MonthlySavings
+	11, // 7: query.SettlementForecast.day:type_name -> google.protobuf.Timestamp
+	11, // 8: query.MonthlySavings.month:type_name -> google.protobuf.Timestamp
+	11, // 9: query.Rebate.settled_at:type_name -> google.protobuf.Timestamp
+	11, // 10: query.User.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 11: query.UserService.GetUser:input_type -> query.GetUserRequest
+	1,  // 12: query.UserService.GetUserByWallet:input_type -> query.GetUserByWalletRequest
+	0,  // 13: query.UserService.GetUserProfile:input_type -> query.GetUserRequest
+	0,  // 14: query.UserService.GetUserPortfolio:input_type -> query.GetUserRequest
+	2,  // 15: query.UserService.GetUser:output_type -> query.GetUserResponse
+	2,  // 16: query.UserService.GetUserByWallet:output_type -> query.GetUserResponse
+	3,  // 17: query.UserService.GetUserProfile:output_type -> query.GetUserProfileResponse
+	5,  // 18: query.UserService.GetUserPortfolio:output_type -> query.GetUserPortfolioResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_proto_query_users_proto_init() }
+func file_proto_query_users_proto_init() {
+	if File_proto_query_users_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_query_users_proto_rawDesc), len(file_proto_query_users_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_query_users_proto_goTypes,
+		DependencyIndexes: file_proto_query_users_proto_depIdxs,
+		MessageInfos:      file_proto_query_users_proto_msgTypes,
+	}.Build()
+	File_proto_query_users_proto = out.File
+	file_proto_query_users_proto_goTypes = nil
+	file_proto_query_users_proto_depIdxs = nil
+}
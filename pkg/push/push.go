@@ -0,0 +1,53 @@
+// Package push delivers notifications to web and app clients through
+// Firebase Cloud Messaging. LINE users are reached over LINE instead; this
+// is for clients that registered a device token.
+package push
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// ErrUnregistered means the device token is no longer valid (app
+// uninstalled, token rotated); callers should forget it
+var ErrUnregistered = errors.New("push: device token is not registered")
+
+// Config is loadable with pkg/config. Without a project id pushes are only
+// logged.
+type Config struct {
+	FCMProjectID string `env:"FCM_PROJECT_ID"`
+	// FCMCredentialsFile is a service account key; empty uses the
+	// application default credentials
+	FCMCredentialsFile string `env:"FCM_CREDENTIALS_FILE"`
+}
+
+// Message is one notification. Data is delivered to the client app as-is so
+// it can deep link; FCM requires string values.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers a message to one device token
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// New returns an FCM sender, or a sender that only logs when cfg has no
+// project id
+func New(ctx context.Context, cfg Config) (Sender, error) {
+	if cfg.FCMProjectID == "" {
+		return logSender{}, nil
+	}
+	return NewFCM(ctx, cfg.FCMProjectID, cfg.FCMCredentialsFile)
+}
+
+type logSender struct{}
+
+func (logSender) Send(ctx context.Context, token string, msg Message) error {
+	logger.FromContext(ctx).Debug("push disabled, dropping notification", "title", msg.Title)
+	return nil
+}
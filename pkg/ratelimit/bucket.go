@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// take refills a bucket for the time since it was last used and takes a
+// token if there is one. It returns 1 and 0 when allowed, or 0 and the
+// milliseconds until a token is available. Redis' clock is used so every
+// instance agrees.
+var take = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(capacity / rate))
+return {allowed, wait}
+`)
+
+// TokenBucket allows bursts of up to capacity events per key and refills
+// at capacity per period, so a steady client gets an even rate instead of
+// a burst at the start of every window
+type TokenBucket struct {
+	client   redis.UniversalClient
+	name     string
+	capacity int64
+	// rate is tokens per millisecond
+	rate float64
+}
+
+// NewTokenBucket allows capacity events per period for each key; name
+// keeps the buckets apart from other limiters
+func NewTokenBucket(client redis.UniversalClient, name string, capacity int, period time.Duration) *TokenBucket {
+	return &TokenBucket{
+		client:   client,
+		name:     name,
+		capacity: int64(capacity),
+		rate:     float64(capacity) / float64(period.Milliseconds()),
+	}
+}
+
+// Allow takes a token for key and returns a CodeRateLimited error when the
+// bucket is empty
+func (b *TokenBucket) Allow(ctx context.Context, key string) error {
+	res, err := take.Run(ctx, b.client, []string{KeyPrefix + ":" + b.name + ":" + key}, b.capacity, b.rate).Int64Slice()
+	if err != nil {
+		return fmt.Errorf("failed to take token for %s: %w", key, err)
+	}
+	if res[0] == 0 {
+		return limited(time.Duration(res[1]) * time.Millisecond)
+	}
+	return nil
+}
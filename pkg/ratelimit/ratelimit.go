@@ -0,0 +1,158 @@
+// Package ratelimit counts events in Redis so limits hold across every
+// instance of a service. Limiter caps events per fixed window; Quota does
+// too, with limits overridable per key; TokenBucket allows bursts and
+// refills steadily; Lockout blocks a key for growing periods after repeated
+// failures.
+//
+// Exceeded limits are returned as CodeRateLimited errors whose cause is an
+// *Error carrying how long the caller should wait.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// KeyPrefix is prepended to every counter key
+const KeyPrefix = "r2s:ratelimit"
+
+// Error is the cause of a CodeRateLimited error
+type Error struct {
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %s", e.RetryAfter)
+}
+
+// RetryAfter reports how long to wait when err is a rate limit error
+func RetryAfter(err error) (time.Duration, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter, true
+	}
+	return 0, false
+}
+
+func limited(retryAfter time.Duration) error {
+	return apperrors.Wrap(&Error{RetryAfter: retryAfter}, apperrors.CodeRateLimited, "Too many requests")
+}
+
+// incr counts an event and returns the count and the window's remaining
+// time; the window starts with the first event
+var incr = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {n, redis.call("PTTL", KEYS[1])}
+`)
+
+func count(ctx context.Context, client redis.UniversalClient, key string, window time.Duration) (int64, time.Duration, error) {
+	res, err := incr.Run(ctx, client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count %s: %w", key, err)
+	}
+	return res[0], time.Duration(res[1]) * time.Millisecond, nil
+}
+
+// Limiter allows a number of events per key in each window
+type Limiter struct {
+	client redis.UniversalClient
+	name   string
+	limit  int64
+	window time.Duration
+}
+
+// NewLimiter allows limit events per window for each key; name keeps the
+// counters apart from other limiters
+func NewLimiter(client redis.UniversalClient, name string, limit int, window time.Duration) *Limiter {
+	return &Limiter{client: client, name: name, limit: int64(limit), window: window}
+}
+
+// Allow counts an event for key and returns a CodeRateLimited error once
+// the window's limit is exceeded
+func (l *Limiter) Allow(ctx context.Context, key string) error {
+	n, ttl, err := count(ctx, l.client, KeyPrefix+":"+l.name+":"+key, l.window)
+	if err != nil {
+		return err
+	}
+	if n > l.limit {
+		return limited(ttl)
+	}
+	return nil
+}
+
+// Lockout locks a key out once it fails Threshold times within Window. The
+// first lockout lasts Base and each further failure doubles it, up to Max.
+type Lockout struct {
+	client    redis.UniversalClient
+	name      string
+	threshold int64
+	window    time.Duration
+	base      time.Duration
+	max       time.Duration
+}
+
+// NewLockout returns a lockout; name keeps its keys apart from others
+func NewLockout(client redis.UniversalClient, name string, threshold int, window, base, max time.Duration) *Lockout {
+	return &Lockout{
+		client:    client,
+		name:      name,
+		threshold: int64(threshold),
+		window:    window,
+		base:      base,
+		max:       max,
+	}
+}
+
+func (l *Lockout) failKey(key string) string { return KeyPrefix + ":" + l.name + ":fail:" + key }
+func (l *Lockout) lockKey(key string) string { return KeyPrefix + ":" + l.name + ":lock:" + key }
+
+// Check returns a CodeRateLimited error while key is locked out
+func (l *Lockout) Check(ctx context.Context, key string) error {
+	ttl, err := l.client.PTTL(ctx, l.lockKey(key)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check lockout: %w", err)
+	}
+	if ttl > 0 {
+		return limited(ttl)
+	}
+	return nil
+}
+
+// Fail records a failure for key and locks it out once the threshold is
+// reached. It returns the lockout duration, zero if key is not locked.
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	n, _, err := count(ctx, l.client, l.failKey(key), l.window)
+	if err != nil {
+		return 0, err
+	}
+	if n < l.threshold {
+		return 0, nil
+	}
+
+	d := l.base
+	for i := n - l.threshold; i > 0 && d < l.max; i-- {
+		d *= 2
+	}
+	d = min(d, l.max)
+	if err := l.client.Set(ctx, l.lockKey(key), n, d).Err(); err != nil {
+		return 0, fmt.Errorf("failed to lock out: %w", err)
+	}
+	return d, nil
+}
+
+// Reset forgets key's failures, e.g. after a successful attempt
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	if err := l.client.Del(ctx, l.failKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to reset lockout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Package ginrbac adapts pkg/rbac to gin
+package ginrbac
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Reserve-to-save-backend/pkg/audit"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/rbac"
+)
+
+// Middleware stores the role the gateway forwarded (rbac.Header) in the
+// request context. A caller the gateway authenticated without a role is a
+// plain user; requests without an actor are internal service calls.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := rbac.Service
+		if c.GetHeader(audit.ActorIDHeader) != "" {
+			role = c.GetHeader(rbac.Header)
+			if !rbac.Valid(role) {
+				role = models.RoleUser
+			}
+		}
+		c.Request = c.Request.WithContext(rbac.WithRole(c.Request.Context(), role))
+		c.Next()
+	}
+}
+
+// Require lets through callers holding any of roles (admins and internal
+// services always pass). It must run after Middleware.
+func Require(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rbac.Allows(rbac.RoleFrom(c.Request.Context()), roles...) {
+			c.AbortWithStatusJSON(apperrors.Response(apperrors.Forbidden(strings.Join(roles, " or ") + " role required")))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,59 @@
+// Package rbac carries the caller's role from the gateway to upstream
+// services and checks it against the roles a route requires.
+//
+// The gateway takes the role from the access token and forwards it in
+// Header next to the audit actor headers, dropping any client-supplied
+// value. Requests without an actor are internal service calls and are
+// given the Service role, which passes every check.
+package rbac
+
+import (
+	"context"
+
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+// Header passes the authenticated caller's role to upstream services
+const Header = "X-Actor-Role"
+
+// Service is the role of internal calls made without an actor
+const Service = "service"
+
+// Valid reports whether role is a role users can hold
+func Valid(role string) bool {
+	switch role {
+	case models.RoleUser, models.RoleMerchant, models.RoleOps, models.RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Allows reports whether role satisfies a requirement of any of roles.
+// Admins and internal services are allowed everything.
+func Allows(role string, roles ...string) bool {
+	if role == models.RoleAdmin || role == Service {
+		return true
+	}
+	for _, r := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+type roleKey struct{}
+
+// WithRole records the caller's role in ctx
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey{}, role)
+}
+
+// RoleFrom returns the role stored by WithRole. Without one the call is an
+// internal service call.
+func RoleFrom(ctx context.Context) string {
+	if role, _ := ctx.Value(roleKey{}).(string); role != "" {
+		return role
+	}
+	return Service
+}
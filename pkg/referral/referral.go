@@ -0,0 +1,310 @@
+// Package referral runs the referral program. Every user has a code; a new
+// user applies one at sign-up (auth-server) or later, up to their first
+// participation (core-server). That first participation qualifies the
+// referral and writes two entries to the rewards ledger, a bonus for the
+// referrer and one for the referee, each a configured share of the deposit.
+//
+// Codes cannot be applied to oneself, between users who referred each other,
+// or between accounts that signed in from the same device and network,
+// which is how self-referrals with a second wallet usually look.
+package referral
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/money"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+)
+
+var (
+	ErrCodeNotFound    = apperrors.Catalog(apperrors.ReasonReferralCodeNotFound)
+	ErrSelfReferral    = apperrors.Catalog(apperrors.ReasonSelfReferral)
+	ErrAlreadyReferred = apperrors.Catalog(apperrors.ReasonAlreadyReferred)
+	ErrReferralClosed  = apperrors.Catalog(apperrors.ReasonReferralClosed)
+)
+
+// maxBps is 100%
+const maxBps = 10000
+
+// Config is loadable with pkg/config
+type Config struct {
+	// ReferrerBonusBps of the referee's first deposit is credited to the
+	// referrer
+	ReferrerBonusBps int `env:"REFERRAL_REFERRER_BONUS_BPS" default:"100"`
+	// RefereeBonusBps of the referee's first deposit is credited to the
+	// referee
+	RefereeBonusBps int `env:"REFERRAL_REFEREE_BONUS_BPS" default:"50"`
+}
+
+// Validate implements config.Validator
+func (c Config) Validate() error {
+	if c.ReferrerBonusBps < 0 || c.ReferrerBonusBps > maxBps {
+		return errors.New("REFERRAL_REFERRER_BONUS_BPS must be between 0 and 10000")
+	}
+	if c.RefereeBonusBps < 0 || c.RefereeBonusBps > maxBps {
+		return errors.New("REFERRAL_REFEREE_BONUS_BPS must be between 0 and 10000")
+	}
+	return nil
+}
+
+const (
+	// codeAlphabet leaves out 0/O and 1/I so codes can be read out
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 8
+	// codeAttempts bounds retries on a code collision
+	codeAttempts = 5
+)
+
+// Store reads and writes referrals and their rewards
+type Store struct {
+	db    *database.DB
+	cfg   Config
+	clock clock.Clock
+}
+
+// NewStore returns a store on db
+func NewStore(db *database.DB, cfg Config, clk clock.Clock) *Store {
+	return &Store{db: db, cfg: cfg, clock: clock.OrSystem(clk)}
+}
+
+// Code returns the user's referral code, creating it on first use
+func (s *Store) Code(ctx context.Context, userID uuid.UUID) (string, error) {
+	for i := 0; i < codeAttempts; i++ {
+		var code string
+		err := s.db.GetContext(ctx, &code, `SELECT code FROM referral_codes WHERE user_id = $1`, userID)
+		if err == nil {
+			return code, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("failed to load referral code: %w", err)
+		}
+
+		code, err = newCode()
+		if err != nil {
+			return "", err
+		}
+		// A conflict on user_id means a concurrent call created the code,
+		// on code a collision; either way the next round reads or retries
+		_, err = s.db.ExecContext(ctx, `
+			INSERT INTO referral_codes (user_id, code, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`, userID, code, s.clock.Now())
+		if err != nil {
+			return "", fmt.Errorf("failed to create referral code: %w", err)
+		}
+	}
+	return "", errors.New("failed to create referral code: too many collisions")
+}
+
+func newCode() (string, error) {
+	b := make([]byte, codeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate referral code: %w", err)
+	}
+	for i := range b {
+		// 256 is a multiple of len(codeAlphabet), so this is unbiased
+		b[i] = codeAlphabet[int(b[i])%len(codeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeCode accepts codes typed in lower case or with spaces around
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Apply refers userID by the owner of code. It fails when the code is
+// unknown or its owner is suspended, when the code is the user's own or the
+// two accounts look like the same person, when the user was already
+// referred, and once the user has joined a campaign.
+func (s *Store) Apply(ctx context.Context, userID uuid.UUID, code string) (*models.Referral, error) {
+	code = normalizeCode(code)
+	var referral *models.Referral
+	err := s.db.TransactionContext(ctx, nil, func(tx *sqlx.Tx) error {
+		// Serializes the user's applies so only one referral is written
+		if err := database.AdvisoryXactLock(ctx, tx, database.NewAdvisoryKey(database.LockUser, userID.String())); err != nil {
+			return err
+		}
+
+		var referrerID uuid.UUID
+		err := tx.GetContext(ctx, &referrerID, `
+			SELECT rc.user_id FROM referral_codes rc
+			JOIN users u ON u.id = rc.user_id
+			WHERE rc.code = $1 AND u.status = $2`, code, models.UserActive)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCodeNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load referral code: %w", err)
+		}
+		if referrerID == userID {
+			return ErrSelfReferral
+		}
+
+		var state struct {
+			Referred     bool `db:"referred"`
+			Participated bool `db:"participated"`
+			Circular     bool `db:"circular"`
+			SharedDevice bool `db:"shared_device"`
+		}
+		err = tx.GetContext(ctx, &state, `
+			SELECT
+				EXISTS (SELECT 1 FROM referrals WHERE referee_id = $1) AS referred,
+				EXISTS (SELECT 1 FROM participations WHERE user_id = $1) AS participated,
+				EXISTS (SELECT 1 FROM referrals WHERE referee_id = $2 AND referrer_id = $1) AS circular,
+				EXISTS (
+					SELECT 1 FROM sessions a
+					JOIN sessions b ON b.device_fingerprint = a.device_fingerprint AND b.ip_address = a.ip_address
+					WHERE a.user_id = $1 AND b.user_id = $2
+				) AS shared_device`, userID, referrerID)
+		if err != nil {
+			return fmt.Errorf("failed to check referral: %w", err)
+		}
+		switch {
+		case state.Referred:
+			return ErrAlreadyReferred
+		case state.Participated:
+			return ErrReferralClosed
+		case state.Circular, state.SharedDevice:
+			return ErrSelfReferral
+		}
+
+		referral = &models.Referral{
+			RefereeID:  userID,
+			ReferrerID: referrerID,
+			Code:       code,
+			CreatedAt:  s.clock.Now(),
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO referrals (referee_id, referrer_id, code, created_at)
+			VALUES ($1, $2, $3, $4)`,
+			referral.RefereeID, referral.ReferrerID, referral.Code, referral.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create referral: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
+
+// Qualify qualifies the referral of p's user, if any is still open, and
+// credits the referrer and the referee their bonus of p's deposit. Call it
+// inside the transaction that creates p; it returns the rewards written.
+func (s *Store) Qualify(ctx context.Context, tx *sqlx.Tx, p *models.Participation) ([]*models.ReferralReward, error) {
+	var referral models.Referral
+	err := database.GetForUpdate(ctx, tx, database.ForUpdate, &referral, `
+		SELECT referee_id, referrer_id, code, created_at, qualified_at, participation_id
+		FROM referrals WHERE referee_id = $1 AND qualified_at IS NULL`, p.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral: %w", err)
+	}
+
+	now := s.clock.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE referrals SET qualified_at = $2, participation_id = $3
+		WHERE referee_id = $1`, referral.RefereeID, now, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to qualify referral: %w", err)
+	}
+
+	deposit := money.New(p.DepositAmount.Big(), money.USDT)
+	credits := []struct {
+		userID uuid.UUID
+		role   string
+		bps    int
+	}{
+		{referral.ReferrerID, models.ReferralRoleReferrer, s.cfg.ReferrerBonusBps},
+		{referral.RefereeID, models.ReferralRoleReferee, s.cfg.RefereeBonusBps},
+	}
+
+	rewards := []*models.ReferralReward{}
+	for _, c := range credits {
+		amount := deposit.MulBps(c.bps)
+		if !amount.IsPositive() {
+			continue
+		}
+		participationID := p.ID
+		reward := &models.ReferralReward{
+			ID:              uuid.New(),
+			UserID:          c.userID,
+			RefereeID:       referral.RefereeID,
+			ParticipationID: &participationID,
+			Role:            c.role,
+			Amount:          models.NewBigInt(amount.Units()),
+			BonusBps:        c.bps,
+			Status:          models.ReferralRewardPending,
+			CreatedAt:       now,
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO referral_rewards (id, user_id, referee_id, participation_id, role, amount, bonus_bps, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			reward.ID, reward.UserID, reward.RefereeID, reward.ParticipationID, reward.Role,
+			reward.Amount, reward.BonusBps, reward.Status, reward.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to credit referral reward: %w", err)
+		}
+		rewards = append(rewards, reward)
+	}
+	return rewards, nil
+}
+
+// Stats returns the user's code, how many users they referred and how many
+// of those qualified, their reward totals and the code they joined with
+func (s *Store) Stats(ctx context.Context, userID uuid.UUID) (*models.ReferralStats, error) {
+	code, err := s.Code(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := models.ReferralStats{Code: code}
+	err = s.db.GetContext(ctx, &stats, `
+		SELECT
+			(SELECT COUNT(*) FROM referrals WHERE referrer_id = $1) AS referred,
+			(SELECT COUNT(*) FROM referrals WHERE referrer_id = $1 AND qualified_at IS NOT NULL) AS qualified,
+			(SELECT COALESCE(SUM(amount), 0) FROM referral_rewards WHERE user_id = $1 AND status = $2) AS pending,
+			(SELECT COALESCE(SUM(amount), 0) FROM referral_rewards WHERE user_id = $1 AND status = $3) AS paid,
+			(SELECT code FROM referrals WHERE referee_id = $1) AS referred_by`,
+		userID, models.ReferralRewardPending, models.ReferralRewardPaid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referral stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// Rewards returns one page of the user's ledger entries, newest first, and
+// the user's total count
+func (s *Store) Rewards(ctx context.Context, userID uuid.UUID, page pagination.Page) ([]*models.ReferralReward, int64, error) {
+	var total int64
+	if err := s.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM referral_rewards WHERE user_id = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count referral rewards: %w", err)
+	}
+
+	rewards := []*models.ReferralReward{}
+	err := s.db.SelectContext(ctx, &rewards, `
+		SELECT id, user_id, referee_id, participation_id, role, amount, bonus_bps, status, created_at, paid_at
+		FROM referral_rewards WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, page.Limit, page.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list referral rewards: %w", err)
+	}
+	return rewards, total, nil
+}
@@ -0,0 +1,111 @@
+// Package server runs a service's HTTP and gRPC listeners until SIGINT or
+// SIGTERM and then drains them, so a rolling deploy lets in-flight requests
+// (payments included) finish instead of dropping them. Connections the
+// caller opened (database, Redis, RPC) are closed by its deferred Close
+// calls once Serve returns.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// readHeaderTimeout bounds slow clients sending request headers
+const readHeaderTimeout = 10 * time.Second
+
+// Config is loadable with pkg/config
+type Config struct {
+	// ShutdownTimeout bounds draining in-flight requests after SIGTERM; keep
+	// it below the orchestrator's grace period (30s on Kubernetes)
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"25s"`
+	// DrainDelay keeps accepting requests for a while after SIGTERM, until
+	// load balancers have stopped routing new ones to the instance
+	DrainDelay time.Duration `env:"SHUTDOWN_DRAIN_DELAY" default:"0s"`
+}
+
+// Validate rejects a timeout that leaves no time to drain
+func (c *Config) Validate() error {
+	if c.ShutdownTimeout <= 0 {
+		return errors.New("SHUTDOWN_TIMEOUT must be positive")
+	}
+	if c.DrainDelay < 0 {
+		return errors.New("SHUTDOWN_DRAIN_DELAY must not be negative")
+	}
+	return nil
+}
+
+// Context returns a context cancelled on SIGINT or SIGTERM
+func Context() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+}
+
+// ServeHTTP serves handler on addr until ctx is done, then stops accepting
+// connections and waits up to cfg.ShutdownTimeout for in-flight requests
+func ServeHTTP(ctx context.Context, addr string, handler http.Handler, cfg Config) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		return fmt.Errorf("failed to serve %s: %w", addr, err)
+	case <-ctx.Done():
+	}
+
+	drain(cfg, addr)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("failed to drain %s: %w", addr, err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeGRPC serves srv on lis until ctx is done, then stops it gracefully,
+// cancelling the RPCs still running after cfg.ShutdownTimeout
+func ServeGRPC(ctx context.Context, srv *grpc.Server, lis net.Listener, cfg Config) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(lis) }()
+	select {
+	case err := <-errc:
+		return fmt.Errorf("failed to serve %s: %w", lis.Addr(), err)
+	case <-ctx.Done():
+	}
+
+	drain(cfg, lis.Addr().String())
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-time.After(cfg.ShutdownTimeout):
+		srv.Stop()
+		return fmt.Errorf("failed to drain %s: RPCs still running after %s", lis.Addr(), cfg.ShutdownTimeout)
+	}
+}
+
+// drain waits out cfg.DrainDelay before the listener stops accepting
+func drain(cfg Config, addr string) {
+	slog.Info("Shutting down, draining requests", "addr", addr, "delay", cfg.DrainDelay, "timeout", cfg.ShutdownTimeout)
+	time.Sleep(cfg.DrainDelay)
+}
@@ -0,0 +1,90 @@
+package statemachine
+
+import (
+	"context"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+// CampaignTable is the campaign lifecycle:
+//
+//	draft -> pending_review -> approved -> recruiting -> reached -> fulfillment -> settled
+//
+// Drafts are reviewed before they go on chain: a rejected or approved
+// campaign goes back to draft to be edited and resubmitted, and only an
+// approved campaign is deployed and opened for participation. A reached
+// campaign falls back to recruiting when cancellations take it under
+// min_qty, and may settle without a separate fulfillment step. Recruiting
+// campaigns that end short of min_qty fail. An admin may pause a recruiting
+// or reached campaign, which stops new participations until it resumes to
+// whichever of the two its totals put it in.
+var CampaignTable = Table[models.CampaignStatus]{
+	models.StatusDraft:         {models.StatusPendingReview, models.StatusCancelled},
+	models.StatusPendingReview: {models.StatusApproved, models.StatusRejected, models.StatusDraft, models.StatusCancelled},
+	models.StatusApproved:      {models.StatusRecruiting, models.StatusDraft, models.StatusCancelled},
+	models.StatusRejected:      {models.StatusDraft, models.StatusCancelled},
+	models.StatusRecruiting:    {models.StatusReached, models.StatusFailed, models.StatusCancelled, models.StatusPaused},
+	models.StatusReached:       {models.StatusRecruiting, models.StatusFulfillment, models.StatusSettled, models.StatusCancelled, models.StatusPaused},
+	models.StatusFulfillment:   {models.StatusSettled, models.StatusFailed},
+	models.StatusPaused:        {models.StatusRecruiting, models.StatusReached, models.StatusCancelled},
+}
+
+// ParticipationTable is the participation lifecycle. Active participations
+// are settled with their campaign, refunded when it fails, or cancelled by
+// the user (optionally via pending_cancel while the on-chain cancel confirms).
+var ParticipationTable = Table[string]{
+	models.ParticipationActive:        {models.ParticipationPendingCancel, models.ParticipationCancelled, models.ParticipationSettled, models.ParticipationRefunded},
+	models.ParticipationPendingCancel: {models.ParticipationActive, models.ParticipationCancelled},
+}
+
+// PaymentTable is the payment lifecycle driven by provider webhooks
+var PaymentTable = Table[models.PaymentStatus]{
+	models.PaymentPending:    {models.PaymentProcessing, models.PaymentCompleted, models.PaymentFailed},
+	models.PaymentProcessing: {models.PaymentCompleted, models.PaymentFailed},
+	models.PaymentCompleted:  {models.PaymentRefunded},
+}
+
+// MerchantTable is the merchant registration lifecycle. Admins review
+// pending registrations and may suspend approved merchants; a rejected
+// merchant may resubmit, which puts the registration back under review.
+var MerchantTable = Table[models.MerchantStatus]{
+	models.MerchantPending:   {models.MerchantApproved, models.MerchantRejected},
+	models.MerchantApproved:  {models.MerchantSuspended},
+	models.MerchantSuspended: {models.MerchantApproved},
+	models.MerchantRejected:  {models.MerchantPending},
+}
+
+// NewCampaign returns a machine for CampaignTable
+func NewCampaign() *Machine[models.CampaignStatus] {
+	return New("campaign", CampaignTable)
+}
+
+// NewParticipation returns a machine for ParticipationTable
+func NewParticipation() *Machine[string] {
+	return New("participation", ParticipationTable)
+}
+
+// NewPayment returns a machine for PaymentTable
+func NewPayment() *Machine[models.PaymentStatus] {
+	return New("payment", PaymentTable)
+}
+
+// NewMerchant returns a machine for MerchantTable
+func NewMerchant() *Machine[models.MerchantStatus] {
+	return New("merchant", MerchantTable)
+}
+
+// LogHistory is a hook that writes every transition to the request logger.
+// Transitions are usually checked inside a database transaction, so a logged
+// change can still be rolled back with it.
+func LogHistory[S comparable]() Hook[S] {
+	return func(ctx context.Context, c Change[S]) {
+		logger.FromContext(ctx).Info("status transition",
+			"machine", c.Machine,
+			"id", c.ID,
+			"from", c.From,
+			"to", c.To,
+		)
+	}
+}
@@ -0,0 +1,143 @@
+// Package statemachine enforces status lifecycles declaratively. A Machine
+// is built from a transition table; guards veto individual transitions and
+// hooks observe every transition that was allowed, e.g. to record history.
+package statemachine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// Table lists, for each state, the states it may move to. States that only
+// appear as targets are terminal.
+type Table[S comparable] map[S][]S
+
+// Change describes one transition of the entity identified by ID
+type Change[S comparable] struct {
+	Machine string
+	ID      string
+	From    S
+	To      S
+}
+
+// Guard can veto an allowed transition by returning an error
+type Guard[S comparable] func(ctx context.Context, c Change[S]) error
+
+// Hook is called after a transition passed the table and every guard
+type Hook[S comparable] func(ctx context.Context, c Change[S])
+
+type edge[S comparable] struct {
+	from, to S
+}
+
+// Machine validates transitions against a table. It is safe for concurrent
+// use; register guards and hooks at start-up.
+type Machine[S comparable] struct {
+	name   string
+	next   map[S]map[S]struct{}
+	mu     sync.RWMutex
+	guards map[edge[S]][]Guard[S]
+	global []Guard[S]
+	hooks  []Hook[S]
+}
+
+// New returns a machine named name (used in errors and Change) for table
+func New[S comparable](name string, table Table[S]) *Machine[S] {
+	next := make(map[S]map[S]struct{}, len(table))
+	for from, tos := range table {
+		set := make(map[S]struct{}, len(tos))
+		for _, to := range tos {
+			set[to] = struct{}{}
+		}
+		next[from] = set
+	}
+	return &Machine[S]{
+		name:   name,
+		next:   next,
+		guards: make(map[edge[S]][]Guard[S]),
+	}
+}
+
+// Name returns the machine name
+func (m *Machine[S]) Name() string {
+	return m.name
+}
+
+// Guard registers g for the from -> to transition
+func (m *Machine[S]) Guard(from, to S, g Guard[S]) *Machine[S] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := edge[S]{from, to}
+	m.guards[k] = append(m.guards[k], g)
+	return m
+}
+
+// GuardAll registers g for every transition
+func (m *Machine[S]) GuardAll(g Guard[S]) *Machine[S] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.global = append(m.global, g)
+	return m
+}
+
+// OnTransition registers h to run after every allowed transition
+func (m *Machine[S]) OnTransition(h Hook[S]) *Machine[S] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, h)
+	return m
+}
+
+// Can reports whether the table allows from -> to. Guards are not run.
+func (m *Machine[S]) Can(from, to S) bool {
+	_, ok := m.next[from][to]
+	return ok
+}
+
+// Terminal reports whether no transition leaves s
+func (m *Machine[S]) Terminal(s S) bool {
+	return len(m.next[s]) == 0
+}
+
+// Targets returns the states reachable from s in one step, sorted by their
+// string form
+func (m *Machine[S]) Targets(s S) []S {
+	out := make([]S, 0, len(m.next[s]))
+	for to := range m.next[s] {
+		out = append(out, to)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return fmt.Sprint(out[i]) < fmt.Sprint(out[j])
+	})
+	return out
+}
+
+// Transition checks that the entity id may move from -> to, runs the guards
+// and then the hooks. A transition missing from the table returns a
+// Conflict error; a guard error is returned as is.
+func (m *Machine[S]) Transition(ctx context.Context, id string, from, to S) error {
+	if !m.Can(from, to) {
+		return apperrors.Newf(apperrors.CodeConflict, "%s cannot move from %v to %v", m.name, from, to)
+	}
+
+	c := Change[S]{Machine: m.name, ID: id, From: from, To: to}
+
+	m.mu.RLock()
+	guards := append(append([]Guard[S](nil), m.global...), m.guards[edge[S]{from, to}]...)
+	hooks := append([]Hook[S](nil), m.hooks...)
+	m.mu.RUnlock()
+
+	for _, g := range guards {
+		if err := g(ctx, c); err != nil {
+			return err
+		}
+	}
+	for _, h := range hooks {
+		h(ctx, c)
+	}
+	return nil
+}
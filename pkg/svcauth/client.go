@@ -0,0 +1,92 @@
+package svcauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+const (
+	// renewBefore is how long before expiry a cached token is replaced
+	renewBefore  = time.Minute
+	tokenTimeout = 5 * time.Second
+)
+
+// Client fetches and caches this service's internal token
+type Client struct {
+	id     string
+	secret string
+	url    string
+	http   *http.Client
+	clock  clock.Clock
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewClient returns a client for cfg's credentials, or nil when none are
+// configured (calls then go out without a token)
+func NewClient(cfg Config, clk clock.Clock) *Client {
+	if cfg.ClientID == "" {
+		return nil
+	}
+	return &Client{
+		id:     cfg.ClientID,
+		secret: cfg.ClientSecret,
+		url:    cfg.TokenURL,
+		http:   &http.Client{Timeout: tokenTimeout},
+		clock:  clock.OrSystem(clk),
+	}
+}
+
+// Token returns a valid internal token, fetching a new one when the cached
+// token is about to expire
+func (c *Client) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.clock.Until(c.expiresAt) > renewBefore {
+		return c.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.id, c.secret)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch internal token: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch internal token: auth-server returned %d", res.StatusCode)
+	}
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Token == "" {
+		return "", fmt.Errorf("failed to decode internal token: %v", err)
+	}
+	c.token, c.expiresAt = body.Token, body.ExpiresAt
+	return c.token, nil
+}
+
+// SetHeader authenticates req as this service
+func (c *Client) SetHeader(ctx context.Context, req *http.Request) error {
+	token, err := c.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(Header, token)
+	return nil
+}
@@ -0,0 +1,46 @@
+// Package ginsvcauth adapts pkg/svcauth to gin
+package ginsvcauth
+
+import (
+	"github.com/gin-gonic/gin"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger/ginlog"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+)
+
+// exempt are probe and scrape paths, which carry no token
+var exempt = map[string]bool{
+	"/health":  true,
+	"/live":    true,
+	"/ready":   true,
+	"/metrics": true,
+}
+
+// Middleware authenticates the calling service from svcauth.Header and
+// puts it in the request context. Unless required, requests without a
+// token are served unauthenticated. It must run before ginjwks, which
+// trusts the actor headers of requests without a bearer token.
+func Middleware(v *svcauth.Verifier, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if exempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(svcauth.Header)
+		if token == "" && !required {
+			c.Next()
+			return
+		}
+
+		id, err := v.Verify(c.Request.Context(), token)
+		if err != nil {
+			ginlog.From(c).Warn("Rejected internal call", "path", c.Request.URL.Path, "error", err)
+			c.AbortWithStatusJSON(apperrors.Response(apperrors.Wrap(err, apperrors.CodeUnauthorized, "Invalid internal token")))
+			return
+		}
+		c.Request = c.Request.WithContext(svcauth.WithIdentity(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package svcauth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthService is exempt so probes need no token
+const healthService = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor authenticates the calling service from the
+// x-internal-token metadata. Unless required, calls without a token are
+// served unauthenticated.
+func UnaryServerInterceptor(v *Verifier, required bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, v, required, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming RPCs like
+// UnaryServerInterceptor
+func StreamServerInterceptor(v *Verifier, required bool) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), v, required, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate returns ctx with the caller's identity, or ctx unchanged for
+// health checks and, unless required, calls without a token
+func authenticate(ctx context.Context, v *Verifier, required bool, method string) (context.Context, error) {
+	if strings.HasPrefix(method, healthService) {
+		return ctx, nil
+	}
+
+	token := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get(strings.ToLower(Header)); len(vals) > 0 {
+			token = vals[0]
+		}
+	}
+	if token == "" && !required {
+		return ctx, nil
+	}
+
+	id, err := v.Verify(ctx, token)
+	if err != nil {
+		slog.WarnContext(ctx, "Rejected internal call", "method", method, "error", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid internal token")
+	}
+	return WithIdentity(ctx, id), nil
+}
+
+// serverStream replaces the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// PerRPCCredentials sends c's token with every RPC. The token is not a
+// transport secret on its own, so plaintext connections are allowed.
+func (c *Client) PerRPCCredentials() credentials.PerRPCCredentials {
+	return rpcCredentials{c}
+}
+
+type rpcCredentials struct {
+	client *Client
+}
+
+func (r rpcCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := r.client.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{strings.ToLower(Header): token}, nil
+}
+
+func (rpcCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,117 @@
+// Package svcauth authenticates calls between services.
+//
+// auth-server issues each service a short-lived internal token for its
+// client credentials (POST /auth/internal/token), signed with the access
+// token keys but for Audience rather than the public API. Callers send it
+// in Header (or the x-internal-token gRPC metadata); core-server,
+// query-server and tx-helper check it with a Verifier and only serve
+// services whose token scope names them.
+package svcauth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+)
+
+const (
+	// Header carries the internal token on HTTP calls
+	Header = "X-Internal-Token"
+	// Audience of internal tokens; access tokens are never accepted here,
+	// nor internal tokens as access tokens
+	Audience = "r2s-internal"
+)
+
+// ErrMissingToken is returned for calls without an internal token
+var ErrMissingToken = errors.New("internal token required")
+
+// Config is loadable with pkg/config. Callers set the client credentials
+// registered in auth-server's INTERNAL_CLIENTS; callees set Required.
+type Config struct {
+	ClientID     string `env:"INTERNAL_CLIENT_ID"`
+	ClientSecret string `env:"INTERNAL_CLIENT_SECRET" secret:"true"`
+	TokenURL     string `env:"INTERNAL_TOKEN_URL" default:"http://localhost:3002/auth/internal/token"`
+	// Required rejects calls without a valid internal token. Off, calls
+	// without one are served, for development only; tokens that are sent
+	// are checked either way.
+	Required bool `env:"INTERNAL_AUTH_REQUIRED" default:"false"`
+}
+
+// Claims of an internal token; the subject is the calling service
+type Claims struct {
+	// Scope lists the services the caller may call
+	Scope []string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+// Identity is an authenticated calling service
+type Identity struct {
+	Service string
+	Scopes  []string
+}
+
+type identityKey struct{}
+
+// WithIdentity returns ctx carrying the calling service
+func WithIdentity(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the calling service, or nil for unauthenticated calls
+func FromContext(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+// Verifier checks internal tokens sent to one service
+type Verifier struct {
+	keys    jwks.Keys
+	service string
+	clock   clock.Clock
+	parser  *jwt.Parser
+}
+
+// NewVerifier checks tokens against keys for calls to service
+func NewVerifier(keys jwks.Keys, service string, clk clock.Clock) *Verifier {
+	return &Verifier{
+		keys:    keys,
+		service: service,
+		clock:   clock.OrSystem(clk),
+		parser: jwt.NewParser(
+			jwt.WithoutClaimsValidation(),
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+		),
+	}
+}
+
+// Verify returns the caller of a valid token whose scope includes the
+// verifier's service
+func (v *Verifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	claims := &Claims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, jwks.Keyfunc(ctx, v.keys)); err != nil {
+		return nil, err
+	}
+
+	now := v.clock.Now()
+	if !claims.VerifyExpiresAt(now, true) {
+		return nil, errors.New("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now, false) {
+		return nil, errors.New("token used before issued")
+	}
+	if !claims.VerifyIssuer(jwks.Issuer, true) || !claims.VerifyAudience(Audience, true) || claims.Subject == "" {
+		return nil, errors.New("not an internal token")
+	}
+	if !slices.Contains(claims.Scope, v.service) {
+		return nil, errors.New("caller may not call " + v.service)
+	}
+	return &Identity{Service: claims.Subject, Scopes: claims.Scope}, nil
+}
@@ -0,0 +1,118 @@
+// Package ethtrace traces JSON-RPC calls made through go-ethereum clients and
+// records their latency in r2s_blockchain_rpc_duration_seconds.
+// It lives in its own package so services that never talk to a node do not
+// depend on go-ethereum.
+package ethtrace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/pkg/tracing"
+)
+
+// Dial connects an ethclient whose HTTP JSON-RPC calls are traced.
+// Only calls made with a context that carries a span are recorded, so pass
+// the request context rather than context.Background(). WebSocket and IPC
+// endpoints are dialled without tracing.
+func Dial(ctx context.Context, rawURL string) (*ethclient.Client, error) {
+	client, err := rpc.DialOptions(ctx, rawURL, rpc.WithHTTPClient(&http.Client{
+		Transport: NewTransport(http.DefaultTransport),
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", rawURL, err)
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// NewTransport wraps base so each JSON-RPC request is timed and becomes a
+// client span named after its method (eth_call, eth_estimateGas, ...)
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := "jsonrpc"
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			method = rpcMethod(body)
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.send(req, method)
+	metrics.ObserveBlockchainRPC(method, err == nil && resp.StatusCode < http.StatusBadRequest, time.Since(start))
+	return resp, err
+}
+
+// send sends req in a client span when its context carries a trace
+func (t *transport) send(req *http.Request, method string) (*http.Response, error) {
+	ctx := req.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("server.address", req.URL.Host),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// rpcMethod reads the method of a single JSON-RPC request; batches are
+// named "batch"
+func rpcMethod(body io.ReadCloser) string {
+	defer body.Close()
+
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return "jsonrpc"
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return "batch"
+	}
+	var msg struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(raw, &msg) != nil || msg.Method == "" {
+		return "jsonrpc"
+	}
+	return msg.Method
+}
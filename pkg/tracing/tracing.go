@@ -0,0 +1,131 @@
+// Package tracing sets up OpenTelemetry for a service and instruments the
+// clients it uses. Init installs the global tracer provider and W3C
+// propagators; spans are exported over OTLP when an endpoint is configured
+// and dropped otherwise, so instrumentation can stay on everywhere.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Reserve-to-save-backend/pkg/logger"
+)
+
+// TracerName identifies spans created by pkg/tracing
+const TracerName = "github.com/Reserve-to-save-backend/pkg/tracing"
+
+// OTLP transports
+const (
+	ProtocolHTTP = "http/protobuf"
+	ProtocolGRPC = "grpc"
+)
+
+// Config is loadable with pkg/config. The exporter also honours the standard
+// OTEL_EXPORTER_OTLP_* variables (headers, TLS, timeouts).
+type Config struct {
+	// Endpoint is the collector URL, e.g. http://otel-collector:4318; empty
+	// disables export
+	Endpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Protocol string `env:"OTEL_EXPORTER_OTLP_PROTOCOL" default:"http/protobuf"`
+	// SampleRatio is the fraction of new traces recorded; requests that
+	// arrive with a sampled parent are always recorded
+	SampleRatio float64 `env:"OTEL_TRACES_SAMPLER_ARG" default:"1"`
+	Environment string  `env:"APP_ENV" default:"development"`
+}
+
+// Validate rejects unknown protocols and ratios outside [0, 1]
+func (c *Config) Validate() error {
+	switch c.Protocol {
+	case "", ProtocolHTTP, ProtocolGRPC:
+	default:
+		return fmt.Errorf("unknown OTLP protocol %q (want %s or %s)", c.Protocol, ProtocolHTTP, ProtocolGRPC)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("OTEL_TRACES_SAMPLER_ARG must be between 0 and 1, got %v", c.SampleRatio)
+	}
+	return nil
+}
+
+// Init installs the tracer provider for service and returns a function that
+// flushes pending spans; call it before the process exits
+func Init(ctx context.Context, service string, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(
+			semconv.ServiceName(service),
+			semconv.DeploymentEnvironment(cfg.Environment),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// newExporter treats Endpoint as the collector base URL, as the
+// OTEL_EXPORTER_OTLP_ENDPOINT convention does; the scheme decides TLS
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", cfg.Endpoint)
+	}
+
+	if cfg.Protocol == ProtocolGRPC {
+		return otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(u.String()))
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/v1/traces"
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+}
+
+// Tracer returns the tracer used by this package's instrumentation
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Annotate adds the trace and span id of the span in ctx to the
+// request-scoped logger, so every line logged for the request can be joined
+// with its trace
+func Annotate(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return logger.With(ctx,
+		logger.KeyTraceID, sc.TraceID().String(),
+		logger.KeySpanID, sc.SpanID().String(),
+	)
+}
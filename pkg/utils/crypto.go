@@ -1,6 +1,7 @@
 package utils
 
 import (
+	cryptorand "crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -56,11 +57,38 @@ func VerifySignature(message, signature string, expectedAddress string) (bool, e
 	return strings.EqualFold(recoveredAddress.Hex(), expectedAddress), nil
 }
 
-// GenerateNonce generates a random nonce
-func GenerateNonce() string {
-	bytes := make([]byte, 16)
-	crypto.RandRead(bytes)
-	return hex.EncodeToString(bytes)
+const (
+	// DefaultNonceBytes is the entropy of GenerateNonce (128 bits)
+	DefaultNonceBytes = 16
+	// MinNonceBytes is the least entropy RandomBytes accepts
+	MinNonceBytes = 8
+)
+
+// RandomBytes returns n bytes from crypto/rand
+func RandomBytes(n int) ([]byte, error) {
+	if n < MinNonceBytes {
+		return nil, fmt.Errorf("random size %d is below the minimum of %d bytes", n, MinNonceBytes)
+	}
+	b := make([]byte, n)
+	if _, err := cryptorand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return b, nil
+}
+
+// GenerateNonce generates a random hex nonce with DefaultNonceBytes of entropy
+func GenerateNonce() (string, error) {
+	return GenerateNonceSize(DefaultNonceBytes)
+}
+
+// GenerateNonceSize generates a random hex nonce from size bytes of entropy.
+// Hex keeps it valid as an EIP-4361 nonce (alphanumeric, 8+ chars).
+func GenerateNonceSize(size int) (string, error) {
+	b, err := RandomBytes(size)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 // IsValidAddress checks if a string is a valid Ethereum address
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// eip1271MagicValue is both the selector of isValidSignature(bytes32,bytes)
+// and what it returns for a valid signature
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+// VerifyContractSignature checks a personal_sign signature against a
+// contract wallet (Safe, account abstraction wallets) by calling its EIP-1271
+// isValidSignature. Addresses without code and calls the node rejects, such
+// as reverts, are reported as invalid; only failures to reach the node are
+// returned as errors.
+func VerifyContractSignature(ctx context.Context, caller bind.ContractCaller, message, signature, address string) (bool, error) {
+	sigBytes, err := hexutil.Decode("0x" + strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return false, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	wallet := common.HexToAddress(address)
+
+	code, err := caller.CodeAt(ctx, wallet, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get wallet code: %w", err)
+	}
+	if len(code) == 0 {
+		return false, nil
+	}
+
+	hash := common.BytesToHash(accounts.TextHash([]byte(message)))
+	out, err := caller.CallContract(ctx, ethereum.CallMsg{
+		To:   &wallet,
+		Data: isValidSignatureCall(hash, sigBytes),
+	}, nil)
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to call isValidSignature: %w", err)
+	}
+	return len(out) >= 4 && bytes.Equal(out[:4], eip1271MagicValue), nil
+}
+
+// isValidSignatureCall ABI-encodes isValidSignature(hash, sig)
+func isValidSignatureCall(hash common.Hash, sig []byte) []byte {
+	data := make([]byte, 0, 4+3*32+len(sig)+31)
+	data = append(data, eip1271MagicValue...)
+	data = append(data, hash.Bytes()...)
+	// offset of the dynamic bytes argument, then its length and padded data
+	data = append(data, common.LeftPadBytes(big.NewInt(64).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(sig))).Bytes(), 32)...)
+	data = append(data, common.RightPadBytes(sig, (len(sig)+31)/32*32)...)
+	return data
+}
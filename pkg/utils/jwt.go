@@ -1,9 +1,16 @@
 package utils
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 )
@@ -14,35 +21,137 @@ type JWTClaims struct {
 	LineUserID  string    `json:"line_user_id,omitempty"`
 	KYCTier     int       `json:"kyc_tier"`
 	SessionID   uuid.UUID `json:"session_id"`
+	Role        string    `json:"role,omitempty"`
+	MFA         bool      `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// JWTConfig configures a JWTManager. Keys and secrets are listed newest
+// first: the first signs new tokens and the rest only verify them, so a key
+// can be rotated out without ending every session at once and dropped once
+// the tokens it signed have expired.
+type JWTConfig struct {
+	SigningKeys     []*jwks.SigningKey
+	RefreshSecrets  []string
+	AccessDuration  time.Duration
+	RefreshDuration time.Duration
+}
+
+// JWTManager issues and verifies tokens. Access tokens are signed with an
+// asymmetric key so other services can verify them against the published
+// key set (see pkg/jwks); refresh tokens are only ever read by auth-server
+// and stay HMAC-signed. Both carry the key's kid header.
 type JWTManager struct {
-	secretKey       string
-	refreshKey      string
+	signingKey      *jwks.SigningKey
+	keys            jwks.Set
+	refreshKeys     []refreshKey
 	accessDuration  time.Duration
 	refreshDuration time.Duration
+	clock           clock.Clock
+}
+
+// refreshKey is an HMAC secret with the kid refresh tokens name it by
+type refreshKey struct {
+	id     string
+	secret []byte
 }
 
-func NewJWTManager(secretKey, refreshKey string, accessDuration, refreshDuration time.Duration) *JWTManager {
-	return &JWTManager{
-		secretKey:       secretKey,
-		refreshKey:      refreshKey,
-		accessDuration:  accessDuration,
-		refreshDuration: refreshDuration,
+// NewJWTManager creates a manager; a nil clock uses the wall clock
+func NewJWTManager(cfg JWTConfig, clk clock.Clock) (*JWTManager, error) {
+	if len(cfg.SigningKeys) == 0 || len(cfg.RefreshSecrets) == 0 {
+		return nil, errors.New("a signing key and a refresh secret are required")
+	}
+	if cfg.AccessDuration <= 0 || cfg.RefreshDuration <= 0 {
+		return nil, errors.New("token lifetimes must be positive")
+	}
+
+	m := &JWTManager{
+		signingKey:      cfg.SigningKeys[0],
+		keys:            jwks.Set{Keys: make([]jwks.Key, 0, len(cfg.SigningKeys))},
+		accessDuration:  cfg.AccessDuration,
+		refreshDuration: cfg.RefreshDuration,
+		clock:           clock.OrSystem(clk),
+	}
+	for _, key := range cfg.SigningKeys {
+		pub, err := key.JWK()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := m.keys.Lookup(context.Background(), pub.Kid); err == nil {
+			return nil, fmt.Errorf("signing key %s is listed twice", pub.Kid)
+		}
+		m.keys.Keys = append(m.keys.Keys, pub)
+	}
+	for _, secret := range cfg.RefreshSecrets {
+		if secret == "" {
+			return nil, errors.New("refresh secrets must not be empty")
+		}
+		m.refreshKeys = append(m.refreshKeys, refreshKey{id: refreshKeyID(secret), secret: []byte(secret)})
 	}
+	return m, nil
+}
+
+// refreshKeyID names a refresh secret by a short hash, which identifies it
+// without revealing it
+func refreshKeyID(secret string) string {
+	sum := sha256.Sum256([]byte("r2s-refresh-kid:" + secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// JWKS is the key set access tokens are verified against; it includes the
+// retired keys still accepted
+func (m *JWTManager) JWKS() jwks.Set {
+	return m.keys
+}
+
+// SigningKeyID is the kid new access tokens are signed with
+func (m *JWTManager) SigningKeyID() string {
+	return m.signingKey.ID
+}
+
+// AccessDuration is how long an access token is valid
+func (m *JWTManager) AccessDuration() time.Duration {
+	return m.accessDuration
+}
+
+// RefreshDuration is how long a refresh token is valid
+func (m *JWTManager) RefreshDuration() time.Duration {
+	return m.refreshDuration
 }
 
 func (m *JWTManager) GenerateAccessToken(claims *JWTClaims) (string, error) {
 	claims.RegisteredClaims = jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessDuration)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Issuer:    "r2s-auth",
-		Audience:  []string{"r2s-api"},
+		ExpiresAt: jwt.NewNumericDate(m.clock.Now().Add(m.accessDuration)),
+		IssuedAt:  jwt.NewNumericDate(m.clock.Now()),
+		Issuer:    jwks.Issuer,
+		Audience:  []string{jwks.Audience},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.secretKey))
+	token := jwt.NewWithClaims(m.signingKey.Method, claims)
+	token.Header["kid"] = m.signingKey.ID
+	return token.SignedString(m.signingKey.Private)
+}
+
+// GenerateInternalToken issues service a token for calling the services in
+// scope (see pkg/svcauth), signed like access tokens
+func (m *JWTManager) GenerateInternalToken(service string, scope []string, ttl time.Duration) (string, time.Time, error) {
+	now := m.clock.Now()
+	expiresAt := now.Add(ttl)
+	claims := &svcauth.Claims{
+		Scope: scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   service,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    jwks.Issuer,
+			Audience:  []string{svcauth.Audience},
+		},
+	}
+
+	token := jwt.NewWithClaims(m.signingKey.Method, claims)
+	token.Header["kid"] = m.signingKey.ID
+	signed, err := token.SignedString(m.signingKey.Private)
+	return signed, expiresAt, err
 }
 
 func (m *JWTManager) GenerateRefreshToken(userID uuid.UUID, address string) (string, error) {
@@ -50,44 +159,53 @@ func (m *JWTManager) GenerateRefreshToken(userID uuid.UUID, address string) (str
 		UserID:  userID,
 		Address: address,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "r2s-auth",
-			Audience:  []string{"r2s-api"},
+			ExpiresAt: jwt.NewNumericDate(m.clock.Now().Add(m.refreshDuration)),
+			IssuedAt:  jwt.NewNumericDate(m.clock.Now()),
+			Issuer:    jwks.Issuer,
+			Audience:  []string{jwks.Audience},
 		},
 	}
 
+	key := m.refreshKeys[0]
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.refreshKey))
+	token.Header["kid"] = key.id
+	return token.SignedString(key.secret)
 }
 
 func (m *JWTManager) VerifyAccessToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(m.secretKey), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
-	}
-
-	return claims, nil
+	return m.verify(tokenString, jwks.Keyfunc(context.Background(), m.keys))
 }
 
+// VerifyRefreshToken accepts tokens signed with any configured refresh
+// secret. Tokens issued before refresh tokens carried a kid are tried
+// against each secret.
 func (m *JWTManager) VerifyRefreshToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+	var lastErr error
+	for _, key := range m.refreshKeys {
+		key := key
+		claims, err := m.verify(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+			kid, hasKid := token.Header["kid"].(string)
+			if hasKid && kid != key.id {
+				return nil, jwks.ErrUnknownKey
+			}
+			return key.secret, nil
+		})
+		if err == nil {
+			return claims, nil
 		}
-		return []byte(m.refreshKey), nil
-	})
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// verify checks the signature with keyfunc, then the time-based claims
+// against the manager's clock rather than jwt.TimeFunc
+func (m *JWTManager) verify(tokenString string, keyfunc jwt.Keyfunc) (*JWTClaims, error) {
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+	token, err := parser.ParseWithClaims(tokenString, &JWTClaims{}, keyfunc)
 
 	if err != nil {
 		return nil, err
@@ -98,5 +216,13 @@ func (m *JWTManager) VerifyRefreshToken(tokenString string) (*JWTClaims, error)
 		return nil, errors.New("invalid token")
 	}
 
+	now := m.clock.Now()
+	if !claims.VerifyExpiresAt(now, true) {
+		return nil, errors.New("token is expired")
+	}
+	if !claims.VerifyNotBefore(now, false) || !claims.VerifyIssuedAt(now, false) {
+		return nil, errors.New("token used before issued")
+	}
+
 	return claims, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,167 @@
+// Package validate checks domain inputs at the service boundary. Every check
+// returns nil or an INVALID_ARGUMENT error from pkg/errors whose message
+// names the offending field, so handlers can return it as-is.
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/address"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+)
+
+const (
+	// MaxBps is 100% in basis points
+	MaxBps = 10000
+
+	// MaxMetadataBytes bounds the encoded size of a metadata object
+	MaxMetadataBytes = 16 << 10
+
+	// DefaultLimit and MaxLimit bound list endpoints
+	DefaultLimit = pagination.DefaultLimit
+	MaxLimit     = pagination.MaxLimit
+)
+
+func invalid(field, format string, args ...interface{}) error {
+	return apperrors.InvalidArgument(field + " " + fmt.Sprintf(format, args...))
+}
+
+// First returns the first non-nil error, so several checks read as one
+// statement: if err := validate.First(a, b, c); err != nil { ... }
+func First(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Required checks that a string field is not blank
+func Required(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return invalid(field, "is required")
+	}
+	return nil
+}
+
+// slugPattern is lowercase words of letters and digits joined by hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slug checks for a URL-safe identifier such as "convenience-store"
+func Slug(field, value string) error {
+	if len(value) > 64 || !slugPattern.MatchString(value) {
+		return invalid(field, "must be lowercase letters and digits joined by hyphens")
+	}
+	return nil
+}
+
+// Address checks for a 0x-prefixed 20-byte hex wallet or contract address.
+// Mixed-case input must carry a valid EIP-55 checksum.
+func Address(field, value string) error {
+	if !strings.HasPrefix(value, "0x") && !strings.HasPrefix(value, "0X") {
+		return invalid(field, "must be a 0x-prefixed address")
+	}
+	if _, err := address.Parse(value); err != nil {
+		if errors.Is(err, address.ErrChecksum) {
+			return invalid(field, "has an invalid EIP-55 checksum")
+		}
+		return invalid(field, "must be a valid address")
+	}
+	return nil
+}
+
+// Bps checks a basis-point value is within 0..10000
+func Bps(field string, value int) error {
+	if value < 0 || value > MaxBps {
+		return invalid(field, "must be between 0 and %d", MaxBps)
+	}
+	return nil
+}
+
+// BpsRange checks both bounds and that floor does not exceed ceiling
+func BpsRange(floorField string, floor int, ceilField string, ceil int) error {
+	if err := First(Bps(floorField, floor), Bps(ceilField, ceil)); err != nil {
+		return err
+	}
+	if floor > ceil {
+		return invalid(floorField, "must not exceed %s", ceilField)
+	}
+	return nil
+}
+
+// Metadata checks a client-supplied metadata object stays within
+// MaxMetadataBytes once encoded
+func Metadata(field string, value map[string]interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return invalid(field, "must be a JSON object")
+	}
+	if len(raw) > MaxMetadataBytes {
+		return invalid(field, "must not exceed %d bytes", MaxMetadataBytes)
+	}
+	return nil
+}
+
+// PositiveAmount checks an amount is present and greater than zero
+func PositiveAmount(field string, value *big.Int) error {
+	if value == nil {
+		return invalid(field, "is required")
+	}
+	if value.Sign() <= 0 {
+		return invalid(field, "must be positive")
+	}
+	return nil
+}
+
+// ParseAmount parses a base-10 integer amount in base units and checks it is
+// positive. Fractions, signs, hex and surrounding garbage are rejected.
+func ParseAmount(field, value string) (*big.Int, error) {
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return nil, invalid(field, "is required")
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return nil, invalid(field, "must be an integer amount in base units")
+		}
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, invalid(field, "must be an integer amount in base units")
+	}
+	if err := PositiveAmount(field, n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// TimeWindow checks both times are set and start is strictly before end
+func TimeWindow(startField string, start time.Time, endField string, end time.Time) error {
+	if start.IsZero() {
+		return invalid(startField, "is required")
+	}
+	if end.IsZero() {
+		return invalid(endField, "is required")
+	}
+	if !start.Before(end) {
+		return invalid(startField, "must be before %s", endField)
+	}
+	return nil
+}
+
+// Pagination checks list bounds; a zero limit means DefaultLimit
+func Pagination(limit, offset int) (int, int, error) {
+	page, err := pagination.New(limit, offset, "")
+	if err != nil {
+		return 0, 0, err
+	}
+	return page.Limit, page.Offset, nil
+}
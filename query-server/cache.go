@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Reserve-to-save-backend/pkg/cache"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// 변경 알림 수신이 끊겼을 때 다시 연결하기 전 대기 시간
+const cacheListenRetryDelay = 5 * time.Second
+
+// CacheConfig는 캠페인 조회 캐시 설정입니다
+type CacheConfig struct {
+	// GetCampaign과 GetCampaigns 앞쪽 페이지를 Redis에 캐시할지 여부
+	// 캠페인/참여 변경 NOTIFY(event-receiver가 반영한 온체인 이벤트 포함)로 무효화되며,
+	// TTL은 무효화를 놓쳤을 때 결과가 오래될 수 있는 최대 시간
+	Enabled bool          `env:"QUERY_CACHE_ENABLED" default:"true"`
+	ItemTTL time.Duration `env:"QUERY_CACHE_ITEM_TTL" default:"1m"`
+	ListTTL time.Duration `env:"QUERY_CACHE_LIST_TTL" default:"15s"`
+	// Pages는 캐시하는 목록 앞쪽 페이지 수입니다 (뒤쪽 페이지는 항상 DB에서 조회)
+	Pages int `env:"QUERY_CACHE_PAGES" default:"3"`
+}
+
+// campaignCache는 캠페인 단건과 목록 앞쪽 페이지 조회 결과를 Redis에 캐시합니다.
+// 캐시 미스는 프로세스 안에서 키마다 한 번만 조회하고(singleflight), 복제본 지연으로
+// 무효화 직전 데이터가 다시 캐시되지 않도록 primary에서 조회합니다.
+// redis가 nil이면 캐시 없이 db(복제본 라우터)에서 조회합니다.
+type campaignCache struct {
+	db    *database.Router
+	redis *database.RedisClient
+	items *cache.Cache[*campaignRow]
+	lists *cache.Cache[campaignPage]
+	pages int
+}
+
+// campaignPage는 캐시되는 목록 한 페이지입니다
+type campaignPage struct {
+	Rows       []campaignRow `json:"rows"`
+	TotalCount int64         `json:"total_count"`
+}
+
+func newCampaignCache(db *database.Router, redis *database.RedisClient, cfg CacheConfig) *campaignCache {
+	c := &campaignCache{db: db, redis: redis, pages: cfg.Pages}
+	if redis != nil {
+		c.items = cache.New[*campaignRow](redis.UniversalClient, cache.Config{Namespace: "query:campaign", TTL: cfg.ItemTTL})
+		c.lists = cache.New[campaignPage](redis.UniversalClient, cache.Config{Namespace: "query:campaigns", TTL: cfg.ListTTL})
+	}
+	return c
+}
+
+// campaign은 캠페인 단건을 캐시에서 찾고, 없으면 load로 조회해 캐시합니다 (없는 캠페인도 캐시)
+func (c *campaignCache) campaign(ctx context.Context, id string, load func(context.Context, sqlx.QueryerContext) (*campaignRow, error)) (*campaignRow, error) {
+	if c.redis == nil {
+		return load(ctx, c.db)
+	}
+	return c.items.GetOrLoad(ctx, id, func(ctx context.Context) (*campaignRow, error) {
+		return load(ctx, c.db.Primary())
+	})
+}
+
+// list는 앞쪽 페이지 목록을 캐시에서 찾고, 없으면 load로 조회해 캐시합니다.
+// 키는 페이지 위치와 필터로 정해지며, 목록 세대가 바뀌면 이전 키는 TTL 후 사라집니다.
+func (c *campaignCache) list(ctx context.Context, req *query.GetCampaignsRequest, limit, offset int, load func(context.Context, sqlx.QueryerContext) (campaignPage, error)) (campaignPage, error) {
+	if c.redis == nil || offset >= c.pages*limit {
+		return load(ctx, c.db)
+	}
+	gen, err := c.redis.Get(ctx, c.generationKey()).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		slog.Warn("Query cache unavailable, reading the database", "error", err)
+		return load(ctx, c.db)
+	}
+	key := fmt.Sprintf("%d:%s", gen, listKey(req, limit, offset))
+	return c.lists.GetOrLoad(ctx, key, func(ctx context.Context) (campaignPage, error) {
+		return load(ctx, c.db.Primary())
+	})
+}
+
+// listKey는 커서를 풀어 쓴 요청의 해시입니다 (같은 페이지를 가리키는 요청은 같은 키)
+func listKey(req *query.GetCampaignsRequest, limit, offset int) string {
+	normalized := proto.Clone(req).(*query.GetCampaignsRequest)
+	normalized.Limit, normalized.Offset, normalized.Cursor = int32(limit), int32(offset), ""
+	raw, _ := proto.MarshalOptions{Deterministic: true}.Marshal(normalized)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:16])
+}
+
+// generationKey는 목록 캐시 세대 번호의 Redis 키입니다 (올리면 모든 목록 캐시가 무효화됨)
+func (c *campaignCache) generationKey() string {
+	return c.lists.Key("generation")
+}
+
+// invalidate는 캠페인 단건 캐시를 지우고 목록 캐시 세대를 올립니다 (campaignID가 비면 목록만)
+func (c *campaignCache) invalidate(ctx context.Context, campaignID string) {
+	if campaignID != "" {
+		if err := c.items.Invalidate(ctx, campaignID); err != nil {
+			slog.Warn("Failed to invalidate cached campaign", "campaign_id", campaignID, "error", err)
+		}
+	}
+	if err := c.redis.Incr(ctx, c.generationKey()).Err(); err != nil {
+		slog.Warn("Failed to invalidate cached campaign lists", "error", err)
+	}
+}
+
+// watch는 campaigns/participations 변경 NOTIFY를 받아 캐시를 무효화합니다 (ctx가 끝날 때까지)
+func (c *campaignCache) watch(ctx context.Context, dbCfg database.Config) {
+	if c.redis == nil {
+		return
+	}
+	for {
+		err := c.listen(ctx, dbCfg)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("Lost campaign change notifications, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheListenRetryDelay):
+		}
+	}
+}
+
+func (c *campaignCache) listen(ctx context.Context, dbCfg database.Config) error {
+	// LISTEN은 primary에서만 받을 수 있음
+	listener, err := database.NewListener(dbCfg, database.ChangeChannel)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	// 끊긴 동안의 변경은 알 수 없으므로 목록은 바로 무효화하고, 단건은 ItemTTL 안에 갱신
+	listener.OnReconnect = func() { c.invalidate(ctx, "") }
+
+	return listener.RunChanges(ctx, func(ev database.ChangeEvent) {
+		switch ev.Table {
+		case "campaigns":
+			c.invalidate(ctx, ev.ID)
+		case "participations":
+			// 예치 합계가 바뀜
+			c.invalidate(ctx, ev.CampaignID)
+		}
+	})
+}
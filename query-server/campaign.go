@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/Reserve-to-save-backend/pkg/address"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/money"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyLimit은 프로필에 포함하는 정산/리베이트 내역의 최대 건수입니다
+const historyLimit = 20
+
+// campaignRow는 campaigns + merchants 조회 결과 한 행입니다 (금액은 USDT 최소 단위)
+type campaignRow struct {
+	ID             string         `db:"id"`
+	ChainAddress   sql.NullString `db:"chain_address"`
+	MerchantID     sql.NullString `db:"merchant_id"`
+	MerchantName   sql.NullString `db:"merchant_name"`
+	MerchantWallet string         `db:"merchant_wallet"`
+	BasePrice      models.BigInt  `db:"base_price"`
+	MinQty         int64          `db:"min_qty"`
+	CurrentQty     sql.NullInt64  `db:"current_qty"`
+	TargetAmount   models.BigInt  `db:"target_amount"`
+	CurrentAmount  models.BigInt  `db:"current_amount"`
+	DiscountRate   int32          `db:"discount_rate"`
+	StartTime      sql.NullTime   `db:"start_time"`
+	EndTime        sql.NullTime   `db:"end_time"`
+	SettlementDate sql.NullTime   `db:"settlement_date"`
+	RMaxBps        int32          `db:"r_max_bps"`
+	SaveFloorBps   int32          `db:"save_floor_bps"`
+	MerchantFeeBps sql.NullInt32  `db:"merchant_fee_bps"`
+	OpsFeeBps      sql.NullInt32  `db:"ops_fee_bps"`
+	Status         sql.NullString `db:"status"`
+	MetadataURI    sql.NullString `db:"metadata_uri"`
+	CreatedAt      sql.NullTime   `db:"created_at"`
+	Title          string         `db:"title"`
+	Description    sql.NullString `db:"description"`
+	ImageURL       sql.NullString `db:"image_url"`
+	Version        int64          `db:"version"`
+
+	CancelDeadline       sql.NullTime  `db:"cancel_deadline"`
+	LateCancelPenaltyBps sql.NullInt32 `db:"late_cancel_penalty_bps"`
+
+	ImageThumbnailURL sql.NullString `db:"image_thumbnail_url"`
+
+	Category     sql.NullString `db:"category"`
+	CategoryName sql.NullString `db:"category_name"`
+	Tags         pq.StringArray `db:"tags"`
+}
+
+// toProto는 주소를 EIP-55 형식으로, 금액을 USDT 소수 문자열로, timestamp를 protobuf 타입으로 변환합니다
+func (r campaignRow) toProto() *query.Campaign {
+	return &query.Campaign{
+		Id:             r.ID,
+		ChainAddress:   address.Display(r.ChainAddress.String),
+		MerchantId:     r.MerchantID.String,
+		MerchantName:   r.MerchantName.String,
+		MerchantWallet: address.Display(r.MerchantWallet),
+		BasePrice:      usdt(r.BasePrice),
+		MinQty:         r.MinQty,
+		CurrentQty:     r.CurrentQty.Int64,
+		TargetAmount:   usdt(r.TargetAmount),
+		CurrentAmount:  usdt(r.CurrentAmount),
+		DiscountRate:   r.DiscountRate,
+		StartTime:      toTimestamp(r.StartTime),
+		EndTime:        toTimestamp(r.EndTime),
+		SettlementDate: toTimestamp(r.SettlementDate),
+		RMaxBps:        r.RMaxBps,
+		SaveFloorBps:   r.SaveFloorBps,
+		MerchantFeeBps: r.MerchantFeeBps.Int32,
+		OpsFeeBps:      r.OpsFeeBps.Int32,
+		Status:         r.Status.String,
+		MetadataUri:    r.MetadataURI.String,
+		CreatedAt:      toTimestamp(r.CreatedAt),
+		Title:          r.Title,
+		Description:    r.Description.String,
+		ImageUrl:       r.ImageURL.String,
+		Version:        r.Version,
+
+		FreeCancelUntil:      toTimestamp(r.freeCancelUntil()),
+		LateCancelAllowed:    r.LateCancelPenaltyBps.Valid,
+		LateCancelPenaltyBps: r.LateCancelPenaltyBps.Int32,
+		ImageThumbnailUrl:    r.ImageThumbnailURL.String,
+		Category:             r.Category.String,
+		CategoryName:         r.CategoryName.String,
+		Tags:                 r.Tags,
+	}
+}
+
+// freeCancelUntil은 무료 취소 마감 시각입니다 (cancel_deadline이 없으면 end_time, models.Campaign.FreeCancelUntil)
+func (r campaignRow) freeCancelUntil() sql.NullTime {
+	if r.CancelDeadline.Valid {
+		return r.CancelDeadline
+	}
+	return r.EndTime
+}
+
+// usdt는 최소 단위 금액을 USDT 소수 문자열로 변환합니다 (NULL은 0)
+func usdt(units models.BigInt) string {
+	return money.New(units.Big(), money.USDT).Decimal()
+}
+
+// parsePrice는 가격 필터를 검증해 base_price 비교용 최소 단위 문자열로 변환합니다 (빈 값은 필터 없음)
+func parsePrice(price, field string) (string, error) {
+	if price == "" {
+		return "", nil
+	}
+	amount, err := money.Parse(price, money.USDT)
+	if err != nil {
+		return "", apperrors.InvalidArgument(field + " must be a decimal amount")
+	}
+	return amount.Units().String(), nil
+}
+
+// checkID는 UUID가 아닌 id를 InvalidArgument로 거부합니다 (optional이면 빈 값 허용)
+func checkID(id, field string, optional bool) error {
+	if id == "" && optional {
+		return nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return apperrors.InvalidArgument("invalid " + field)
+	}
+	return nil
+}
+
+// checkIDs는 id 목록의 UUID 형식을 검증합니다
+func checkIDs(ids []string, field string) error {
+	for _, id := range ids {
+		if err := checkID(id, field, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusStrings는 상태 상수 목록을 WhereAny 파라미터로 변환합니다
+func statusStrings(statuses []models.CampaignStatus) []string {
+	out := make([]string, len(statuses))
+	for i, s := range statuses {
+		out[i] = string(s)
+	}
+	return out
+}
+
+// statusList는 상태 상수 목록을 SQL IN 목록("'recruiting', 'reached'")으로 만듭니다 (사용자 입력은 넣지 않음)
+func statusList(statuses []models.CampaignStatus) string {
+	quoted := make([]string, len(statuses))
+	for i, s := range statuses {
+		quoted[i] = "'" + string(s) + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func toTimestamp(t sql.NullTime) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// campaignColumns는 campaignRow의 컬럼입니다 (머천트 이름은 등록된 머천트만, 카테고리 이름은 카테고리가 있는 캠페인만)
+var campaignColumns = []string{
+	"c.id", "c.chain_address", "c.merchant_id", "m.business_name AS merchant_name", "c.merchant_wallet",
+	"c.base_price", "c.min_qty", "c.current_qty", "c.target_amount", "c.current_amount", "c.discount_rate",
+	"c.start_time", "c.end_time", "c.settlement_date",
+	"c.r_max_bps", "c.save_floor_bps", "c.merchant_fee_bps", "c.ops_fee_bps",
+	"c.status", "c.metadata_uri", "c.created_at", "c.title", "c.description", "c.image_url", "c.version",
+	"c.cancel_deadline", "c.late_cancel_penalty_bps", "c.image_thumbnail_url",
+	"c.category", "cat.name AS category_name", "c.tags",
+}
+
+// campaignSelect는 캠페인 조회 공통 SELECT를 생성합니다
+func campaignSelect(columns ...string) *database.SelectBuilder {
+	return database.NewSelect(append(slices.Clone(campaignColumns), columns...)...).
+		From("campaigns c").
+		Join("LEFT JOIN merchants m ON c.merchant_id = m.id").
+		Join("LEFT JOIN campaign_categories cat ON c.category = cat.slug")
+}
+
+// campaignOrders는 정렬 기준별 ORDER BY입니다 (페이지가 겹치지 않도록 id로 동순위를 구분)
+var campaignOrders = map[query.CampaignSort][]string{
+	query.CampaignSort_CAMPAIGN_SORT_NEWEST:      {"c.created_at DESC", "c.id DESC"},
+	query.CampaignSort_CAMPAIGN_SORT_ENDING_SOON: {"c.end_time < now()", "c.end_time ASC", "c.id ASC"},
+	query.CampaignSort_CAMPAIGN_SORT_MOST_FUNDED: {"c.current_amount DESC NULLS LAST", "c.id DESC"},
+	query.CampaignSort_CAMPAIGN_SORT_CATEGORY:    {"cat.position ASC NULLS LAST", "c.category", "c.created_at DESC", "c.id DESC"},
+}
+
+// GetCampaigns는 필터와 정렬 기준에 따라 캠페인 목록을 조회합니다
+func (s *QueryServer) GetCampaigns(ctx context.Context, req *query.GetCampaignsRequest) (*query.GetCampaignsResponse, error) {
+	logger.FromContext(ctx).Debug("GetCampaigns", "limit", req.Limit, "offset", req.Offset, "status", req.Status,
+		"statuses", req.Statuses, "ids", req.Ids, "merchant_id", req.MerchantId, "category", req.Category, "tags", req.Tags, "q", req.Q, "sort", req.Sort)
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	order, ok := campaignOrders[req.Sort]
+	if !ok {
+		return nil, apperrors.InvalidArgument("unknown sort")
+	}
+	minPrice, err := parsePrice(req.MinBasePrice, "min_base_price")
+	if err != nil {
+		return nil, err
+	}
+	maxPrice, err := parsePrice(req.MaxBasePrice, "max_base_price")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkID(req.MerchantId, "merchant_id", true); err != nil {
+		return nil, err
+	}
+	if err := checkIDs(req.Ids, "ids"); err != nil {
+		return nil, err
+	}
+	statuses := req.Statuses
+	if req.Status != "" {
+		statuses = append([]string{req.Status}, req.Statuses...)
+	}
+
+	// 총 개수를 세지 않으면 한 행 더 조회해 다음 페이지가 있는지 판단
+	limit := page.Limit
+	if req.SkipCount {
+		limit++
+	}
+
+	// SQL 쿼리 구성 (모든 필터는 옵션, 값은 파라미터로 전달)
+	b := campaignSelect().
+		WhereAny("c.status", statuses).
+		WhereAnyID("c.id", req.Ids).
+		WhereIf(req.MerchantId != "", "c.merchant_id = ?", req.MerchantId).
+		WhereIf(req.Category != "", "c.category = ?", req.Category).
+		WhereContainsAll("c.tags", normalizeTags(req.Tags)).
+		WhereIf(req.LockFrom != nil, "c.end_time >= ?", req.LockFrom.AsTime()).
+		WhereIf(req.LockTo != nil, "c.start_time <= ?", req.LockTo.AsTime()).
+		WhereIf(minPrice != "", "c.base_price >= ?", minPrice).
+		WhereIf(maxPrice != "", "c.base_price <= ?", maxPrice).
+		WhereIf(req.Q != "", "(c.title ILIKE ? OR c.metadata::TEXT ILIKE ?)", database.LikePattern(req.Q), database.LikePattern(req.Q)).
+		OrderBy(order...).
+		Limit(int64(limit)).
+		Offset(int64(page.Offset))
+	countQuery, countArgs := b.CountSQL()
+	listQuery, listArgs := b.ToSQL()
+
+	// 앞쪽 페이지는 캐시에서 조회
+	result, err := s.cache.list(ctx, req, page.Limit, page.Offset, func(ctx context.Context, db sqlx.QueryerContext) (campaignPage, error) {
+		// 총 개수와 목록을 동시에 조회 (하나가 실패하면 다른 쿼리도 취소)
+		result := campaignPage{TotalCount: -1}
+		g, ctx := errgroup.WithContext(ctx)
+		if !req.SkipCount {
+			g.Go(func() error {
+				if err := sqlx.GetContext(ctx, db, &result.TotalCount, countQuery, countArgs...); err != nil {
+					logger.FromContext(ctx).Error("failed to count campaigns", "error", err)
+					return queryError(err, "failed to count campaigns")
+				}
+				return nil
+			})
+		}
+		g.Go(func() error {
+			rows, err := database.Select[campaignRow](ctx, db, listQuery, listArgs...)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to query campaigns", "error", err)
+				return queryError(err, "failed to query campaigns")
+			}
+			result.Rows = rows
+			return nil
+		})
+		if err := g.Wait(); err != nil {
+			return campaignPage{}, err
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &query.GetCampaignsResponse{TotalCount: result.TotalCount}
+	rows := result.Rows
+	if req.SkipCount {
+		if len(rows) > page.Limit {
+			rows = rows[:page.Limit]
+			response.NextCursor = pagination.EncodeCursor(page.Offset + page.Limit)
+		}
+	} else {
+		response.NextCursor = page.NextCursor(result.TotalCount)
+	}
+	response.Campaigns = database.Map(rows, campaignRow.toProto)
+
+	logger.FromContext(ctx).Debug("returning campaigns", "count", len(response.Campaigns), "total_count", result.TotalCount)
+	return response, nil
+}
+
+// GetCampaign은 특정 캠페인을 조회합니다
+func (s *QueryServer) GetCampaign(ctx context.Context, req *query.GetCampaignRequest) (*query.GetCampaignResponse, error) {
+	logger.FromContext(ctx).Debug("GetCampaign", "campaign_id", req.CampaignId)
+
+	if err := checkID(req.CampaignId, "campaign_id", false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := campaignSelect().Where("c.id = ?", req.CampaignId).ToSQL()
+	row, err := s.cache.campaign(ctx, req.CampaignId, func(ctx context.Context, db sqlx.QueryerContext) (*campaignRow, error) {
+		return database.Get[campaignRow](ctx, db, sqlQuery, args...)
+	})
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query campaign", "error", err)
+		return nil, queryError(err, "failed to query campaign")
+	}
+	if row == nil {
+		logger.FromContext(ctx).Debug("campaign not found", "campaign_id", req.CampaignId)
+		return &query.GetCampaignResponse{Found: false}, nil
+	}
+
+	response := &query.GetCampaignResponse{
+		Campaign: row.toProto(),
+		Found:    true,
+	}
+
+	logger.FromContext(ctx).Debug("found campaign", "chain_address", response.Campaign.ChainAddress)
+	return response, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// categoryRow는 campaign_categories 조회 결과와 캠페인 집계 한 행입니다
+type categoryRow struct {
+	Slug                string `db:"slug"`
+	Name                string `db:"name"`
+	Position            int32  `db:"position"`
+	ActiveCampaignCount int64  `db:"active_campaign_count"`
+}
+
+func (r categoryRow) toProto() *query.Category {
+	return &query.Category{
+		Slug:                r.Slug,
+		Name:                r.Name,
+		Position:            r.Position,
+		ActiveCampaignCount: r.ActiveCampaignCount,
+	}
+}
+
+// GetCategories는 카테고리를 표시 순서대로 진행 중인 캠페인 수와 함께 조회합니다
+func (s *QueryServer) GetCategories(ctx context.Context, req *query.GetCategoriesRequest) (*query.GetCategoriesResponse, error) {
+	logger.FromContext(ctx).Debug("GetCategories")
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := database.NewSelect(
+		"cat.slug", "cat.name", "cat.position",
+		fmt.Sprintf("(SELECT COUNT(*) FROM campaigns c WHERE c.category = cat.slug AND c.status IN (%s)) AS active_campaign_count", statusList(models.ActiveStatuses)),
+	).
+		From("campaign_categories cat").
+		OrderBy("cat.position", "cat.slug").
+		ToSQL()
+	rows, err := database.Select[categoryRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query categories", "error", err)
+		return nil, queryError(err, "failed to query categories")
+	}
+
+	logger.FromContext(ctx).Debug("returning categories", "count", len(rows))
+	return &query.GetCategoriesResponse{Categories: database.Map(rows, categoryRow.toProto)}, nil
+}
+
+// normalizeTags는 태그 필터를 저장된 형식(소문자, 앞뒤 공백 제거)으로 맞추고 빈 값을 뺍니다
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/jwks"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/server"
+	"github.com/Reserve-to-save-backend/pkg/svcauth"
+	"github.com/Reserve-to-save-backend/pkg/tracing"
+)
+
+// Config는 query-server 설정입니다 (환경변수 > CONFIG_FILE > 기본값)
+type Config struct {
+	Port        string `env:"QUERY_SERVER_PORT" default:"50051"`
+	MetricsPort string `env:"QUERY_METRICS_PORT" default:"9464"`
+	// DebugAddr는 pkg/diag 리스너 주소입니다 (비어 있으면 비활성화, 외부에 노출하지 말 것)
+	DebugAddr string `env:"QUERY_DEBUG_ADDR"`
+
+	Database database.Config
+	// Replicas는 조회를 분산할 읽기 전용 복제본입니다 (비어 있으면 primary에서 조회)
+	Replicas database.ReplicaConfig
+	Cache    CacheConfig
+	Log      logger.Config
+	Tracing  tracing.Config
+	Errors   errreport.Config
+	Server   server.Config
+
+	// JWKS는 호출 서비스의 내부 토큰 검증에 사용합니다
+	JWKS jwks.Config
+	// Internal이 필수이면 query-server 호출이 허용된 서비스만 받습니다
+	Internal svcauth.Config
+}
+
+// defaultConfig는 공통 기본값과 다른 query-server 전용 기본값을 채운 Config를 반환합니다
+func defaultConfig() Config {
+	return Config{
+		Database: database.Config{
+			// 조회 전용 서버이므로 공통 기본값(30s)보다 짧게 제한
+			StatementTimeout: 15 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// favoriteRow는 관심 캠페인 한 행입니다
+type favoriteRow struct {
+	campaignRow
+	FavoritedAt sql.NullTime `db:"favorited_at"`
+}
+
+func (r favoriteRow) toProto() *query.FavoriteCampaign {
+	return &query.FavoriteCampaign{
+		Campaign:    r.campaignRow.toProto(),
+		FavoritedAt: toTimestamp(r.FavoritedAt),
+	}
+}
+
+// GetUserFavorites는 사용자의 관심 캠페인을 관심 등록 최신순으로 조회합니다
+func (s *QueryServer) GetUserFavorites(ctx context.Context, req *query.GetUserFavoritesRequest) (*query.GetUserFavoritesResponse, error) {
+	logger.FromContext(ctx).Debug("GetUserFavorites", "user_id", req.UserId, "limit", req.Limit, "offset", req.Offset, "statuses", req.Statuses)
+
+	if err := checkID(req.UserId, "user_id", false); err != nil {
+		return nil, err
+	}
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	b := campaignSelect("f.created_at AS favorited_at").
+		Join("JOIN campaign_favorites f ON f.campaign_id = c.id").
+		Where("f.user_id = ?", req.UserId).
+		WhereAny("c.status", req.Statuses).
+		OrderBy("f.created_at DESC", "c.id DESC").
+		Limit(int64(page.Limit)).
+		Offset(int64(page.Offset))
+
+	// 총 개수 조회
+	var totalCount int64
+	countQuery, countArgs := b.CountSQL()
+	if err := s.db.GetContext(ctx, &totalCount, countQuery, countArgs...); err != nil {
+		logger.FromContext(ctx).Error("failed to count favorites", "error", err)
+		return nil, queryError(err, "failed to count favorites")
+	}
+
+	// 관심 캠페인 조회
+	listQuery, listArgs := b.ToSQL()
+	rows, err := database.Select[favoriteRow](ctx, s.db, listQuery, listArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query favorites", "error", err)
+		return nil, queryError(err, "failed to query favorites")
+	}
+
+	logger.FromContext(ctx).Debug("returning favorites", "count", len(rows), "total_count", totalCount)
+	return &query.GetUserFavoritesResponse{
+		Favorites:  database.Map(rows, favoriteRow.toProto),
+		TotalCount: totalCount,
+		NextCursor: page.NextCursor(totalCount),
+	}, nil
+}
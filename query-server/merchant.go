@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Reserve-to-save-backend/pkg/address"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// MerchantServer는 gRPC MerchantService를 구현합니다
+type MerchantServer struct {
+	query.UnimplementedMerchantServiceServer
+	db *database.Router
+}
+
+// NewMerchantServer는 새로운 MerchantServer 인스턴스를 생성합니다
+func NewMerchantServer(db *database.Router) *MerchantServer {
+	return &MerchantServer{db: db}
+}
+
+// merchantRow는 merchants 조회 결과와 캠페인 집계 한 행입니다
+type merchantRow struct {
+	ID                   string        `db:"id"`
+	PayoutWallet         string        `db:"payout_wallet"`
+	BusinessName         string        `db:"business_name"`
+	Status               string        `db:"status"`
+	CreatedAt            sql.NullTime  `db:"created_at"`
+	CampaignCount        int64         `db:"campaign_count"`
+	ActiveCampaignCount  int64         `db:"active_campaign_count"`
+	TotalVolume          models.BigInt `db:"total_volume"`
+	SettledCampaignCount int64         `db:"settled_campaign_count"`
+
+	LogoURL          sql.NullString `db:"logo_url"`
+	LogoThumbnailURL sql.NullString `db:"logo_thumbnail_url"`
+}
+
+// toProto는 주소를 EIP-55 형식으로, 금액을 USDT 소수 문자열로 변환합니다
+func (r merchantRow) toProto() *query.Merchant {
+	return &query.Merchant{
+		Id:                   r.ID,
+		PayoutWallet:         address.Display(r.PayoutWallet),
+		BusinessName:         r.BusinessName,
+		Status:               r.Status,
+		CreatedAt:            toTimestamp(r.CreatedAt),
+		CampaignCount:        r.CampaignCount,
+		ActiveCampaignCount:  r.ActiveCampaignCount,
+		TotalVolume:          usdt(r.TotalVolume),
+		SettledCampaignCount: r.SettledCampaignCount,
+		LogoUrl:              r.LogoURL.String,
+		LogoThumbnailUrl:     r.LogoThumbnailURL.String,
+	}
+}
+
+// settlementRow는 정산된 캠페인과 정산된 참여 집계 한 행입니다
+type settlementRow struct {
+	CampaignID       string        `db:"campaign_id"`
+	CampaignAddress  string        `db:"campaign_address"`
+	SettledAt        sql.NullTime  `db:"settled_at"`
+	TotalDeposit     models.BigInt `db:"total_deposit"`
+	TotalRebate      models.BigInt `db:"total_rebate"`
+	ParticipantCount int64         `db:"participant_count"`
+}
+
+func (r settlementRow) toProto() *query.Settlement {
+	return &query.Settlement{
+		CampaignId:       r.CampaignID,
+		CampaignAddress:  address.Display(r.CampaignAddress),
+		SettledAt:        toTimestamp(r.SettledAt),
+		TotalDeposit:     usdt(r.TotalDeposit),
+		TotalRebate:      usdt(r.TotalRebate),
+		ParticipantCount: r.ParticipantCount,
+	}
+}
+
+// merchantSelect는 머천트 조회 공통 SELECT를 생성합니다 (캠페인 집계는 서브쿼리로 계산)
+func merchantSelect() *database.SelectBuilder {
+	return database.NewSelect(
+		"m.id", "m.payout_wallet", "m.business_name", "m.status", "m.created_at",
+		"m.logo_url", "m.logo_thumbnail_url",
+		"(SELECT COUNT(*) FROM campaigns c WHERE c.merchant_id = m.id) AS campaign_count",
+		fmt.Sprintf("(SELECT COUNT(*) FROM campaigns c WHERE c.merchant_id = m.id AND c.status IN (%s)) AS active_campaign_count", statusList(models.ActiveStatuses)),
+		"(SELECT COALESCE(SUM(c.current_amount), 0) FROM campaigns c WHERE c.merchant_id = m.id) AS total_volume",
+		fmt.Sprintf("(SELECT COUNT(*) FROM campaigns c WHERE c.merchant_id = m.id AND c.status = '%s') AS settled_campaign_count", models.StatusSettled),
+	).
+		From("merchants m")
+}
+
+// GetMerchants는 머천트 목록을 조회합니다
+func (s *MerchantServer) GetMerchants(ctx context.Context, req *query.GetMerchantsRequest) (*query.GetMerchantsResponse, error) {
+	logger.FromContext(ctx).Debug("GetMerchants", "limit", req.Limit, "offset", req.Offset, "ids", req.Ids)
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkIDs(req.Ids, "ids"); err != nil {
+		return nil, err
+	}
+	b := merchantSelect().
+		WhereAnyID("m.id", req.Ids).
+		OrderBy("m.created_at DESC").
+		Limit(int64(page.Limit)).
+		Offset(int64(page.Offset))
+
+	// 총 개수 조회
+	var totalCount int64
+	countQuery, countArgs := b.CountSQL()
+	if err := s.db.GetContext(ctx, &totalCount, countQuery, countArgs...); err != nil {
+		logger.FromContext(ctx).Error("failed to count merchants", "error", err)
+		return nil, queryError(err, "failed to count merchants")
+	}
+
+	// 머천트 목록 조회
+	listQuery, listArgs := b.ToSQL()
+	rows, err := database.Select[merchantRow](ctx, s.db, listQuery, listArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query merchants", "error", err)
+		return nil, queryError(err, "failed to query merchants")
+	}
+
+	response := &query.GetMerchantsResponse{
+		Merchants:  database.Map(rows, merchantRow.toProto),
+		TotalCount: totalCount,
+		NextCursor: page.NextCursor(totalCount),
+	}
+
+	logger.FromContext(ctx).Debug("returning merchants", "count", len(response.Merchants), "total_count", totalCount)
+	return response, nil
+}
+
+// GetMerchant는 특정 머천트를 조회합니다
+func (s *MerchantServer) GetMerchant(ctx context.Context, req *query.GetMerchantRequest) (*query.GetMerchantResponse, error) {
+	logger.FromContext(ctx).Debug("GetMerchant", "merchant_id", req.MerchantId)
+
+	if err := checkID(req.MerchantId, "merchant_id", false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := merchantSelect().Where("m.id = ?", req.MerchantId).ToSQL()
+	row, err := database.Get[merchantRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query merchant", "error", err)
+		return nil, queryError(err, "failed to query merchant")
+	}
+	if row == nil {
+		logger.FromContext(ctx).Debug("merchant not found", "merchant_id", req.MerchantId)
+		return &query.GetMerchantResponse{Found: false}, nil
+	}
+
+	// 최근 정산 내역 조회
+	settlementsQuery, settlementsArgs := database.NewSelect(
+		"c.id AS campaign_id", "c.chain_address AS campaign_address", "c.settlement_date AS settled_at",
+		"COALESCE(SUM(p.deposit_amount), 0) AS total_deposit",
+		"COALESCE(SUM(p.actual_rebate), 0) AS total_rebate",
+		"COUNT(p.id) AS participant_count",
+	).
+		From("campaigns c").
+		Join("LEFT JOIN participations p ON p.campaign_id = c.id AND p.status = ?", models.ParticipationSettled).
+		Where("c.merchant_id = ?", req.MerchantId).
+		Where("c.status = ?", models.StatusSettled).
+		GroupBy("c.id").
+		OrderBy("c.settlement_date DESC", "c.id DESC").
+		Limit(historyLimit).
+		ToSQL()
+	settlements, err := database.Select[settlementRow](ctx, s.db, settlementsQuery, settlementsArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query merchant settlements", "error", err)
+		return nil, queryError(err, "failed to query settlements")
+	}
+
+	return &query.GetMerchantResponse{
+		Merchant:    row.toProto(),
+		Found:       true,
+		Settlements: database.Map(settlements, settlementRow.toProto),
+	}, nil
+}
+
+// GetMerchantCampaigns는 머천트가 등록한 캠페인 목록을 조회합니다
+func (s *MerchantServer) GetMerchantCampaigns(ctx context.Context, req *query.GetMerchantCampaignsRequest) (*query.GetCampaignsResponse, error) {
+	logger.FromContext(ctx).Debug("GetMerchantCampaigns", "merchant_id", req.MerchantId, "limit", req.Limit, "offset", req.Offset, "status", req.Status)
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	if err := checkID(req.MerchantId, "merchant_id", false); err != nil {
+		return nil, err
+	}
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	b := campaignSelect().
+		Where("c.merchant_id = ?", req.MerchantId).
+		WhereIf(req.Status != "", "c.status = ?", req.Status).
+		OrderBy("c.created_at DESC").
+		Limit(int64(page.Limit)).
+		Offset(int64(page.Offset))
+
+	var totalCount int64
+	countQuery, countArgs := b.CountSQL()
+	if err := s.db.GetContext(ctx, &totalCount, countQuery, countArgs...); err != nil {
+		logger.FromContext(ctx).Error("failed to count merchant campaigns", "error", err)
+		return nil, queryError(err, "failed to count campaigns")
+	}
+
+	listQuery, listArgs := b.ToSQL()
+	rows, err := database.Select[campaignRow](ctx, s.db, listQuery, listArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query merchant campaigns", "error", err)
+		return nil, queryError(err, "failed to query campaigns")
+	}
+
+	return &query.GetCampaignsResponse{
+		Campaigns:  database.Map(rows, campaignRow.toProto),
+		TotalCount: totalCount,
+		NextCursor: page.NextCursor(totalCount),
+	}, nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Reserve-to-save-backend/pkg/address"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/money"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// ParticipationServer는 gRPC ParticipationService를 구현합니다
+type ParticipationServer struct {
+	query.UnimplementedParticipationServiceServer
+	db *database.Router
+}
+
+// NewParticipationServer는 새로운 ParticipationServer 인스턴스를 생성합니다
+func NewParticipationServer(db *database.Router) *ParticipationServer {
+	return &ParticipationServer{db: db}
+}
+
+// participationRow는 participations + campaigns 조회 결과 한 행입니다 (금액은 USDT 최소 단위)
+type participationRow struct {
+	ID              string        `db:"id"`
+	CampaignID      string        `db:"campaign_id"`
+	CampaignAddress string        `db:"campaign_address"`
+	UserID          string        `db:"user_id"`
+	WalletAddress   string        `db:"wallet_address"`
+	DepositAmount   models.BigInt `db:"deposit_amount"`
+	CancelPending   models.BigInt `db:"cancel_pending"`
+	CancelPenalty   models.BigInt `db:"cancel_penalty"`
+	JoinedAt        sql.NullTime  `db:"joined_at"`
+	Status          string        `db:"status"`
+	RMaxBps         int32         `db:"r_max_bps"`
+	SaveFloorBps    int32         `db:"save_floor_bps"`
+	ActualRebate    models.BigInt `db:"actual_rebate"`
+	Version         int64         `db:"version"`
+}
+
+// toProto는 주소를 EIP-55 형식으로, 금액을 USDT 소수 문자열로, timestamp를 protobuf 타입으로 변환합니다
+func (r participationRow) toProto() *query.Participation {
+	p := &query.Participation{
+		Id:                r.ID,
+		CampaignId:        r.CampaignID,
+		CampaignAddress:   address.Display(r.CampaignAddress),
+		UserId:            r.UserID,
+		WalletAddress:     address.Display(r.WalletAddress),
+		DepositAmount:     usdt(r.DepositAmount),
+		CancelPending:     usdt(r.CancelPending),
+		CancelPenalty:     usdt(r.CancelPenalty),
+		JoinedAt:          toTimestamp(r.JoinedAt),
+		Status:            r.Status,
+		ExpectedRebateMin: expectedRebate(r.DepositAmount, r.SaveFloorBps),
+		ExpectedRebateMax: expectedRebate(r.DepositAmount, r.RMaxBps),
+		Version:           r.Version,
+	}
+	// 정산 전이면 actual_rebate는 NULL
+	if r.ActualRebate.Int != nil {
+		p.ActualRebate = usdt(r.ActualRebate)
+	}
+	return p
+}
+
+// expectedRebate는 예치금의 bps 비율을 컨트랙트와 같이 소수점 이하 버림으로 계산합니다
+func expectedRebate(deposit models.BigInt, bps int32) string {
+	return money.New(deposit.Big(), money.USDT).MulBps(int(bps)).Decimal()
+}
+
+// participationSelect는 참여 조회 공통 SELECT를 생성합니다
+func participationSelect() *database.SelectBuilder {
+	return database.NewSelect(
+		"p.id", "p.campaign_id", "c.chain_address AS campaign_address",
+		"p.user_id", "p.wallet_address",
+		"p.deposit_amount", "p.cancel_pending", "p.cancel_penalty", "p.joined_at", "p.status",
+		"c.r_max_bps", "c.save_floor_bps", "p.actual_rebate", "p.version",
+	).
+		From("participations p").
+		Join("JOIN campaigns c ON p.campaign_id = c.id")
+}
+
+// GetUserParticipations는 사용자의 참여 목록을 조회합니다
+func (s *ParticipationServer) GetUserParticipations(ctx context.Context, req *query.GetUserParticipationsRequest) (*query.GetParticipationsResponse, error) {
+	logger.FromContext(ctx).Debug("GetUserParticipations", "user_id", req.UserId, "limit", req.Limit, "offset", req.Offset, "status", req.Status)
+
+	if err := checkID(req.UserId, "user_id", false); err != nil {
+		return nil, err
+	}
+	b := participationSelect().
+		Where("p.user_id = ?", req.UserId).
+		WhereIf(req.Status != "", "p.status = ?", req.Status)
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	return s.listParticipations(ctx, b, page)
+}
+
+// GetCampaignParticipations는 캠페인의 참여자 목록을 조회합니다
+func (s *ParticipationServer) GetCampaignParticipations(ctx context.Context, req *query.GetCampaignParticipationsRequest) (*query.GetParticipationsResponse, error) {
+	logger.FromContext(ctx).Debug("GetCampaignParticipations", "campaign_id", req.CampaignId, "limit", req.Limit, "offset", req.Offset, "status", req.Status)
+
+	if err := checkID(req.CampaignId, "campaign_id", false); err != nil {
+		return nil, err
+	}
+	b := participationSelect().
+		Where("p.campaign_id = ?", req.CampaignId).
+		WhereIf(req.Status != "", "p.status = ?", req.Status)
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	return s.listParticipations(ctx, b, page)
+}
+
+// listParticipations는 필터가 적용된 SELECT로 페이지와 총 개수를 조회합니다
+func (s *ParticipationServer) listParticipations(ctx context.Context, b *database.SelectBuilder, page pagination.Page) (*query.GetParticipationsResponse, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	b = b.OrderBy("p.joined_at DESC", "p.id DESC").Limit(int64(page.Limit)).Offset(int64(page.Offset))
+
+	// 총 개수 조회
+	var totalCount int64
+	countQuery, countArgs := b.CountSQL()
+	if err := s.db.GetContext(ctx, &totalCount, countQuery, countArgs...); err != nil {
+		logger.FromContext(ctx).Error("failed to count participations", "error", err)
+		return nil, queryError(err, "failed to count participations")
+	}
+
+	// 참여 목록 조회
+	listQuery, listArgs := b.ToSQL()
+	rows, err := database.Select[participationRow](ctx, s.db, listQuery, listArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query participations", "error", err)
+		return nil, queryError(err, "failed to query participations")
+	}
+
+	response := &query.GetParticipationsResponse{
+		Participations: database.Map(rows, participationRow.toProto),
+		TotalCount:     totalCount,
+		NextCursor:     page.NextCursor(totalCount),
+	}
+
+	logger.FromContext(ctx).Debug("returning participations", "count", len(response.Participations), "total_count", totalCount)
+	return response, nil
+}
+
+// GetParticipation은 특정 참여를 조회합니다
+func (s *ParticipationServer) GetParticipation(ctx context.Context, req *query.GetParticipationRequest) (*query.GetParticipationResponse, error) {
+	logger.FromContext(ctx).Debug("GetParticipation", "participation_id", req.ParticipationId)
+
+	if err := checkID(req.ParticipationId, "participation_id", false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := participationSelect().Where("p.id = ?", req.ParticipationId).ToSQL()
+	row, err := database.Get[participationRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query participation", "error", err)
+		return nil, queryError(err, "failed to query participation")
+	}
+	if row == nil {
+		logger.FromContext(ctx).Debug("participation not found", "participation_id", req.ParticipationId)
+		return &query.GetParticipationResponse{Found: false}, nil
+	}
+
+	return &query.GetParticipationResponse{
+		Participation: row.toProto(),
+		Found:         true,
+	}, nil
+}
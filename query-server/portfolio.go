@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"time"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/money"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// lockedRow는 정산 전 참여 한 행입니다
+type lockedRow struct {
+	CampaignID    string        `db:"campaign_id"`
+	DepositAmount models.BigInt `db:"deposit_amount"`
+	RMaxBps       int32         `db:"r_max_bps"`
+	SaveFloorBps  int32         `db:"save_floor_bps"`
+	StartTime     time.Time     `db:"start_time"`
+	EndTime       time.Time     `db:"end_time"`
+}
+
+// monthlyRow는 월별 지급 리베이트 한 행입니다
+type monthlyRow struct {
+	Month         sql.NullTime  `db:"month"`
+	CampaignCount int64         `db:"campaign_count"`
+	Amount        models.BigInt `db:"amount"`
+}
+
+func (r monthlyRow) toProto() *query.MonthlySavings {
+	return &query.MonthlySavings{
+		Month:         toTimestamp(r.Month),
+		CampaignCount: r.CampaignCount,
+		Amount:        usdt(r.Amount),
+	}
+}
+
+// rebateRange는 save_floor_bps와 r_max_bps 기준 리베이트 합계입니다
+type rebateRange struct {
+	min, max money.Amount
+}
+
+func zeroRange() rebateRange {
+	return rebateRange{min: money.Zero(money.USDT), max: money.Zero(money.USDT)}
+}
+
+func (r rebateRange) add(o rebateRange) rebateRange {
+	return rebateRange{min: addAmount(r.min, o.min), max: addAmount(r.max, o.max)}
+}
+
+// addAmount는 같은 통화(USDT) 금액을 더합니다
+func addAmount(a, b money.Amount) money.Amount {
+	sum, _ := a.Add(b)
+	return sum
+}
+
+// accrued는 rebate 중 now까지 지난 잠금 기간 비율만큼을 소수점 이하 버림으로 계산합니다
+func accrued(rebate money.Amount, start, end, now time.Time) money.Amount {
+	switch {
+	case !now.After(start):
+		return money.Zero(money.USDT)
+	case !now.Before(end):
+		return rebate
+	}
+	units := new(big.Int).Mul(rebate.Units(), big.NewInt(int64(now.Sub(start))))
+	return money.New(units.Quo(units, big.NewInt(int64(end.Sub(start)))), money.USDT)
+}
+
+// GetUserPortfolio는 "내 절약" 탭에 필요한 자산 요약을 한 번에 조회합니다.
+// 예상 리베이트는 참여마다 컨트랙트와 같이 버림으로 계산한 뒤 합산합니다.
+func (s *UserServer) GetUserPortfolio(ctx context.Context, req *query.GetUserRequest) (*query.GetUserPortfolioResponse, error) {
+	logger.FromContext(ctx).Debug("GetUserPortfolio", "user_id", req.UserId)
+
+	if err := checkID(req.UserId, "user_id", false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	var found bool
+	if err := s.db.GetContext(ctx, &found, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, req.UserId); err != nil {
+		logger.FromContext(ctx).Error("failed to query user", "error", err)
+		return nil, queryError(err, "failed to query user")
+	}
+	if !found {
+		logger.FromContext(ctx).Debug("user not found", "user_id", req.UserId)
+		return &query.GetUserPortfolioResponse{Found: false}, nil
+	}
+
+	// 정산 전 참여 조회 (캠페인 종료가 가까운 순, 정산되면 참여 상태가 settled로 바뀜)
+	lockedQuery, lockedArgs := database.NewSelect(
+		"p.campaign_id", "p.deposit_amount", "c.r_max_bps", "c.save_floor_bps", "c.start_time", "c.end_time",
+	).
+		From("participations p").
+		Join("JOIN campaigns c ON p.campaign_id = c.id").
+		Where("p.user_id = ?", req.UserId).
+		WhereAny("p.status", []string{models.ParticipationActive, models.ParticipationPendingCancel}).
+		OrderBy("c.end_time", "p.campaign_id").
+		ToSQL()
+	locked, err := database.Select[lockedRow](ctx, s.db, lockedQuery, lockedArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query locked participations", "error", err)
+		return nil, queryError(err, "failed to query locked participations")
+	}
+
+	// 월별 지급 리베이트 조회 (캠페인마다 정산은 한 번이므로 월별 캠페인 수의 합이 전체 캠페인 수)
+	realizedQuery, realizedArgs := database.NewSelect(
+		"date_trunc('month', c.settlement_date AT TIME ZONE 'UTC') AS month",
+		"COUNT(DISTINCT p.campaign_id) AS campaign_count",
+		"SUM(p.actual_rebate) AS amount",
+	).
+		From("participations p").
+		Join("JOIN campaigns c ON p.campaign_id = c.id").
+		Where("p.user_id = ?", req.UserId).
+		Where("p.status = ?", models.ParticipationSettled).
+		Where("p.actual_rebate IS NOT NULL").
+		GroupBy("1").
+		OrderBy("1").
+		ToSQL()
+	realized, err := database.Select[monthlyRow](ctx, s.db, realizedQuery, realizedArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query realized rebates", "error", err)
+		return nil, queryError(err, "failed to query realized rebates")
+	}
+
+	portfolio := &query.UserPortfolio{UserId: req.UserId, LockedCount: int64(len(locked))}
+	now := time.Now()
+	totalLocked := money.Zero(money.USDT)
+	expected, accruedSoFar := zeroRange(), zeroRange()
+	var forecast *query.SettlementForecast
+	var forecastDay time.Time
+	var forecastDeposit money.Amount
+	var forecastRebate rebateRange
+	flush := func() {
+		if forecast != nil {
+			forecast.Deposit = forecastDeposit.Decimal()
+			forecast.ExpectedRebateMin = forecastRebate.min.Decimal()
+			forecast.ExpectedRebateMax = forecastRebate.max.Decimal()
+			portfolio.Upcoming = append(portfolio.Upcoming, forecast)
+		}
+	}
+	for _, row := range locked {
+		deposit := money.New(row.DepositAmount.Big(), money.USDT)
+		rebate := rebateRange{min: deposit.MulBps(int(row.SaveFloorBps)), max: deposit.MulBps(int(row.RMaxBps))}
+		totalLocked = addAmount(totalLocked, deposit)
+		expected = expected.add(rebate)
+		accruedSoFar = accruedSoFar.add(rebateRange{
+			min: accrued(rebate.min, row.StartTime, row.EndTime, now),
+			max: accrued(rebate.max, row.StartTime, row.EndTime, now),
+		})
+
+		// 캠페인 종료일(UTC)별로 묶음
+		day := row.EndTime.UTC().Truncate(24 * time.Hour)
+		if forecast == nil || !day.Equal(forecastDay) {
+			flush()
+			forecast = &query.SettlementForecast{Day: timestamppb.New(day)}
+			forecastDay, forecastDeposit, forecastRebate = day, money.Zero(money.USDT), zeroRange()
+		}
+		forecast.CampaignCount++
+		forecastDeposit = addAmount(forecastDeposit, deposit)
+		forecastRebate = forecastRebate.add(rebate)
+	}
+	flush()
+
+	realizedTotal := money.Zero(money.USDT)
+	for _, row := range realized {
+		realizedTotal = addAmount(realizedTotal, money.New(row.Amount.Big(), money.USDT))
+		portfolio.RealizedCampaignCount += row.CampaignCount
+	}
+
+	portfolio.TotalLocked = totalLocked.Decimal()
+	portfolio.AccruedRebateMin = accruedSoFar.min.Decimal()
+	portfolio.AccruedRebateMax = accruedSoFar.max.Decimal()
+	portfolio.ExpectedRebateMin = expected.min.Decimal()
+	portfolio.ExpectedRebateMax = expected.max.Decimal()
+	portfolio.RealizedTotal = realizedTotal.Decimal()
+	portfolio.Realized = database.Map(realized, monthlyRow.toProto)
+
+	logger.FromContext(ctx).Debug("returning portfolio", "locked_count", portfolio.LockedCount, "realized_total", portfolio.RealizedTotal)
+	return &query.GetUserPortfolioResponse{Portfolio: portfolio, Found: true}, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/pagination"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// maxSearchTerms는 검색어에서 사용하는 최대 단어 수입니다
+const maxSearchTerms = 8
+
+// ts_headline이 일치한 단어 앞뒤에 넣는 표시입니다. 본문에 쓰이지 않는
+// 사용자 정의 영역 문자이므로, HTML 이스케이프 후 <mark> 태그로 바꿉니다.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// 검색 SELECT의 추가 컬럼입니다. tsq는 검색어의 tsquery이고, 머천트 이름은
+// 가장 낮은 가중치로 순위에 반영됩니다.
+var searchColumns = []string{
+	"ts_rank(c.search_vector || setweight(to_tsvector('simple', coalesce(m.business_name, '')), 'C'), tsq) AS rank",
+	"ts_headline('simple', coalesce(c.title, ''), tsq, 'StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true') AS title_snippet",
+	"ts_headline('simple', coalesce(c.description, ''), tsq, 'StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxWords=20, MinWords=8, MaxFragments=2, FragmentDelimiter=\" ... \"') AS description_snippet",
+}
+
+// searchRow는 검색 결과 한 행입니다
+type searchRow struct {
+	campaignRow
+	Rank               float32 `db:"rank"`
+	TitleSnippet       string  `db:"title_snippet"`
+	DescriptionSnippet string  `db:"description_snippet"`
+}
+
+func (r searchRow) toProto() *query.CampaignSearchResult {
+	return &query.CampaignSearchResult{
+		Campaign:           r.campaignRow.toProto(),
+		Rank:               r.Rank,
+		TitleSnippet:       highlight(r.TitleSnippet),
+		DescriptionSnippet: highlight(r.DescriptionSnippet),
+	}
+}
+
+// SearchCampaigns는 제목, 설명, 머천트 이름으로 캠페인을 전문 검색합니다 (관련도 순)
+func (s *QueryServer) SearchCampaigns(ctx context.Context, req *query.SearchCampaignsRequest) (*query.SearchCampaignsResponse, error) {
+	logger.FromContext(ctx).Debug("SearchCampaigns", "q", req.Q, "limit", req.Limit, "offset", req.Offset, "statuses", req.Statuses)
+
+	tsquery := prefixQuery(req.Q)
+	if tsquery == "" {
+		return nil, apperrors.InvalidArgument("q must contain a word")
+	}
+	page, err := pagination.FromProto(req.Limit, req.Offset, req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	// 검색어는 파라미터로 전달해 tsq로 한 번만 변환합니다
+	b := campaignSelect(searchColumns...).
+		Join("CROSS JOIN to_tsquery('simple', ?) AS tsq", tsquery).
+		Where("(c.search_vector @@ tsq OR to_tsvector('simple', coalesce(m.business_name, '')) @@ tsq)").
+		WhereAny("c.status", req.Statuses).
+		OrderBy("rank DESC", "c.id DESC").
+		Limit(int64(page.Limit)).
+		Offset(int64(page.Offset))
+
+	// 총 개수 조회
+	var totalCount int64
+	countQuery, countArgs := b.CountSQL()
+	if err := s.db.GetContext(ctx, &totalCount, countQuery, countArgs...); err != nil {
+		logger.FromContext(ctx).Error("failed to count search results", "error", err)
+		return nil, queryError(err, "failed to search campaigns")
+	}
+
+	// 검색 결과 조회
+	listQuery, listArgs := b.ToSQL()
+	rows, err := database.Select[searchRow](ctx, s.db, listQuery, listArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to search campaigns", "error", err)
+		return nil, queryError(err, "failed to search campaigns")
+	}
+
+	logger.FromContext(ctx).Debug("returning search results", "count", len(rows), "total_count", totalCount)
+	return &query.SearchCampaignsResponse{
+		Results:    database.Map(rows, searchRow.toProto),
+		TotalCount: totalCount,
+		NextCursor: page.NextCursor(totalCount),
+	}, nil
+}
+
+// prefixQuery는 검색어의 단어마다 접두사 일치를 적용한 tsquery를 만듭니다
+// ("coff bea" → "coff:* & bea:*"). 문자와 숫자만 남기므로 tsquery 문법이
+// 사용자 입력으로 바뀌지 않으며, 단어가 없으면 빈 문자열을 반환합니다.
+func prefixQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	for i, w := range words {
+		words[i] = strings.ToLower(w) + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// highlight는 ts_headline 결과를 HTML 이스케이프하고 일치 표시를 <mark>로 바꿉니다
+func highlight(snippet string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// 모집 추이 기간 (일)
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 180
+)
+
+// campaignStatsRow는 캠페인과 campaign_stats_daily 최신 집계를 LEFT JOIN한 결과입니다 (집계 전이면 0)
+type campaignStatsRow struct {
+	CampaignID       string        `db:"campaign_id"`
+	RMaxBps          int32         `db:"r_max_bps"`
+	SaveFloorBps     int32         `db:"save_floor_bps"`
+	ParticipantCount int64         `db:"participant_count"`
+	FundedCount      int64         `db:"funded_count"`
+	CancelledCount   int64         `db:"cancelled_count"`
+	TotalDeposit     models.BigInt `db:"total_deposit"`
+	AverageDeposit   models.BigInt `db:"average_deposit"`
+	UpdatedAt        sql.NullTime  `db:"updated_at"`
+}
+
+func (r campaignStatsRow) toProto() *query.CampaignStats {
+	stats := &query.CampaignStats{
+		CampaignId:         r.CampaignID,
+		ParticipantCount:   r.ParticipantCount,
+		FundedCount:        r.FundedCount,
+		CancelledCount:     r.CancelledCount,
+		TotalDeposit:       usdt(r.TotalDeposit),
+		AverageDeposit:     usdt(r.AverageDeposit),
+		ProjectedRebateMin: expectedRebate(r.AverageDeposit, r.SaveFloorBps),
+		ProjectedRebateMax: expectedRebate(r.AverageDeposit, r.RMaxBps),
+		UpdatedAt:          toTimestamp(r.UpdatedAt),
+	}
+	if r.ParticipantCount > 0 {
+		stats.CancellationRate = float64(r.CancelledCount) / float64(r.ParticipantCount)
+	}
+	return stats
+}
+
+// fundingRow는 campaign_stats_daily 한 행입니다
+type fundingRow struct {
+	Day              sql.NullTime  `db:"day"`
+	TotalDeposit     models.BigInt `db:"total_deposit"`
+	ParticipantCount int64         `db:"participant_count"`
+	FundedCount      int64         `db:"funded_count"`
+}
+
+func (r fundingRow) toProto() *query.FundingPoint {
+	return &query.FundingPoint{
+		Day:              toTimestamp(r.Day),
+		TotalDeposit:     usdt(r.TotalDeposit),
+		ParticipantCount: r.ParticipantCount,
+		FundedCount:      r.FundedCount,
+	}
+}
+
+// campaignStatsQuery는 캠페인의 가장 최근 집계를 조회합니다 (평균 예치금은 최소 단위로 버림)
+const campaignStatsQuery = `SELECT c.id AS campaign_id, c.r_max_bps, c.save_floor_bps,
+	COALESCE(st.participant_count, 0) AS participant_count,
+	COALESCE(st.funded_count, 0) AS funded_count,
+	COALESCE(st.cancelled_count, 0) AS cancelled_count,
+	COALESCE(st.total_deposit, 0) AS total_deposit,
+	COALESCE(TRUNC(st.total_deposit / NULLIF(st.funded_count, 0)), 0) AS average_deposit,
+	st.updated_at
+FROM campaigns c
+LEFT JOIN LATERAL (
+	SELECT * FROM campaign_stats_daily s WHERE s.campaign_id = c.id ORDER BY s.day DESC LIMIT 1
+) st ON true
+WHERE c.id = $1`
+
+// GetCampaignStats는 batch-server가 집계한 캠페인 참여 통계와 일별 모집 추이를 조회합니다
+func (s *QueryServer) GetCampaignStats(ctx context.Context, req *query.GetCampaignStatsRequest) (*query.GetCampaignStatsResponse, error) {
+	logger.FromContext(ctx).Debug("GetCampaignStats", "campaign_id", req.CampaignId, "days", req.Days)
+
+	if err := checkID(req.CampaignId, "campaign_id", false); err != nil {
+		return nil, err
+	}
+	days := req.Days
+	if days <= 0 {
+		days = defaultStatsDays
+	}
+	days = min(days, maxStatsDays)
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	row, err := database.Get[campaignStatsRow](ctx, s.db, campaignStatsQuery, req.CampaignId)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query campaign stats", "error", err)
+		return nil, queryError(err, "failed to query campaign stats")
+	}
+	if row == nil {
+		logger.FromContext(ctx).Debug("campaign not found", "campaign_id", req.CampaignId)
+		return &query.GetCampaignStatsResponse{Found: false}, nil
+	}
+
+	// 최근 days일(UTC)의 추이를 오래된 날부터 조회
+	fundingQuery, fundingArgs := database.NewSelect(
+		"s.day", "s.total_deposit", "s.participant_count", "s.funded_count",
+	).
+		From("campaign_stats_daily s").
+		Where("s.campaign_id = ?", req.CampaignId).
+		Where("s.day > (now() AT TIME ZONE 'UTC')::DATE - ?::INTEGER", days).
+		OrderBy("s.day").
+		ToSQL()
+	funding, err := database.Select[fundingRow](ctx, s.db, fundingQuery, fundingArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query campaign funding", "error", err)
+		return nil, queryError(err, "failed to query campaign funding")
+	}
+
+	stats := row.toProto()
+	stats.Funding = database.Map(funding, fundingRow.toProto)
+	return &query.GetCampaignStatsResponse{Stats: stats, Found: true}, nil
+}
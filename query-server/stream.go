@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// 스트리밍 배치 크기 (요청의 batch_size가 없거나 범위를 벗어나면 조정)
+const (
+	defaultStreamBatch = 500
+	maxStreamBatch     = 1000
+)
+
+// streamBatchSize는 요청한 배치 크기를 허용 범위로 맞춥니다
+func streamBatchSize(size int32) int {
+	switch {
+	case size <= 0:
+		return defaultStreamBatch
+	case size > maxStreamBatch:
+		return maxStreamBatch
+	}
+	return int(size)
+}
+
+// streamRows는 afterID 다음 행부터 id 순으로 size개씩 조회해 send로 보냅니다.
+// 배치마다 쿼리를 새로 실행하므로(keyset 페이지네이션) 수만 건을 내보내도
+// 메모리에는 한 배치만 올라가고, 느린 클라이언트를 기다리는 동안 DB 연결이나
+// 트랜잭션을 잡고 있지 않습니다. 각 배치 쿼리는 단일 RPC와 같은 시간 제한을 받습니다.
+func streamRows[T any](ctx context.Context, db *database.Router, afterID string, size int,
+	batch func(afterID string) *database.SelectBuilder, id func(T) string, send func(rows []T, lastID string) error) error {
+	for {
+		queryCtx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+		sqlQuery, args := batch(afterID).Limit(int64(size)).ToSQL()
+		rows, err := database.Select[T](queryCtx, db, sqlQuery, args...)
+		cancel()
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to query stream batch", "after_id", afterID, "error", err)
+			return queryError(err, "failed to query stream batch")
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		afterID = id(rows[len(rows)-1])
+		if err := send(rows, afterID); err != nil {
+			return err
+		}
+		if len(rows) < size {
+			return nil
+		}
+	}
+}
+
+// StreamCampaigns는 필터에 맞는 캠페인 전체를 id 순 배치로 스트리밍합니다
+func (s *QueryServer) StreamCampaigns(req *query.StreamCampaignsRequest, stream query.QueryService_StreamCampaignsServer) error {
+	ctx := stream.Context()
+	logger.FromContext(ctx).Debug("StreamCampaigns", "statuses", req.Statuses, "merchant_id", req.MerchantId, "after_id", req.AfterId)
+
+	if err := checkID(req.MerchantId, "merchant_id", true); err != nil {
+		return err
+	}
+	if err := checkID(req.AfterId, "after_id", true); err != nil {
+		return err
+	}
+	batch := func(afterID string) *database.SelectBuilder {
+		return campaignSelect().
+			WhereIf(afterID != "", "c.id > ?", afterID).
+			WhereAny("c.status", req.Statuses).
+			WhereIf(req.MerchantId != "", "c.merchant_id = ?", req.MerchantId).
+			OrderBy("c.id ASC")
+	}
+	return streamRows(ctx, s.db, req.AfterId, streamBatchSize(req.BatchSize), batch,
+		func(r campaignRow) string { return r.ID },
+		func(rows []campaignRow, lastID string) error {
+			return stream.Send(&query.CampaignBatch{
+				Campaigns: database.Map(rows, campaignRow.toProto),
+				LastId:    lastID,
+			})
+		})
+}
+
+// StreamParticipations는 필터에 맞는 참여 전체를 id 순 배치로 스트리밍합니다
+func (s *ParticipationServer) StreamParticipations(req *query.StreamParticipationsRequest, stream query.ParticipationService_StreamParticipationsServer) error {
+	ctx := stream.Context()
+	logger.FromContext(ctx).Debug("StreamParticipations", "campaign_id", req.CampaignId, "user_id", req.UserId, "status", req.Status, "after_id", req.AfterId)
+
+	for field, id := range map[string]string{"campaign_id": req.CampaignId, "user_id": req.UserId, "after_id": req.AfterId} {
+		if err := checkID(id, field, true); err != nil {
+			return err
+		}
+	}
+	batch := func(afterID string) *database.SelectBuilder {
+		return participationSelect().
+			WhereIf(afterID != "", "p.id > ?", afterID).
+			WhereIf(req.CampaignId != "", "p.campaign_id = ?", req.CampaignId).
+			WhereIf(req.UserId != "", "p.user_id = ?", req.UserId).
+			WhereIf(req.Status != "", "p.status = ?", req.Status).
+			OrderBy("p.id ASC")
+	}
+	return streamRows(ctx, s.db, req.AfterId, streamBatchSize(req.BatchSize), batch,
+		func(r participationRow) string { return r.ID },
+		func(rows []participationRow, lastID string) error {
+			return stream.Send(&query.ParticipationBatch{
+				Participations: database.Map(rows, participationRow.toProto),
+				LastId:         lastID,
+			})
+		})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// 인기/추천 캠페인 개수와 참여 속도 기간 (시간)
+const (
+	defaultFeedLimit     = 10
+	maxFeedLimit         = 50
+	defaultTrendingHours = 24
+	maxTrendingHours     = 168
+)
+
+// trendingJoin은 기간 안의 캠페인별 참여 수와 점수를 계산하는 서브쿼리입니다.
+// 참여마다 기간 시작 시점 0에서 현재 1까지 선형으로 가중치를 주며, 취소/환불은 제외합니다.
+// 파라미터는 기간(초) 두 번과 제외할 상태 두 개입니다.
+const trendingJoin = `(
+	SELECT p.campaign_id, COUNT(*) AS recent_joins,
+		SUM(1 - EXTRACT(EPOCH FROM now() - p.joined_at) / ?::FLOAT8)::FLOAT8 AS score
+	FROM participations p
+	WHERE p.joined_at > now() - make_interval(secs => ?) AND p.status NOT IN (?, ?)
+	GROUP BY p.campaign_id
+) t ON t.campaign_id = c.id`
+
+// trendingArgs는 trendingJoin의 파라미터입니다
+func trendingArgs(hours int32) []interface{} {
+	seconds := hours * 3600
+	return []interface{}{seconds, seconds, models.ParticipationCancelled, models.ParticipationRefunded}
+}
+
+// feedLimit은 요청 개수를 기본값과 최대값으로 보정합니다
+func feedLimit(limit int32) int64 {
+	if limit <= 0 {
+		return defaultFeedLimit
+	}
+	return int64(min(limit, maxFeedLimit))
+}
+
+// trendingRow는 인기 캠페인 한 행입니다
+type trendingRow struct {
+	campaignRow
+	RecentJoins int64   `db:"recent_joins"`
+	Score       float64 `db:"score"`
+}
+
+func (r trendingRow) toProto() *query.TrendingCampaign {
+	return &query.TrendingCampaign{
+		Campaign:    r.campaignRow.toProto(),
+		RecentJoins: r.RecentJoins,
+		Score:       r.Score,
+	}
+}
+
+// GetTrendingCampaigns는 최근 참여 속도가 빠른 모집 중인 캠페인을 조회합니다 (미니앱 홈 피드)
+func (s *QueryServer) GetTrendingCampaigns(ctx context.Context, req *query.GetTrendingCampaignsRequest) (*query.GetTrendingCampaignsResponse, error) {
+	logger.FromContext(ctx).Debug("GetTrendingCampaigns", "limit", req.Limit, "window_hours", req.WindowHours)
+
+	hours := req.WindowHours
+	if hours <= 0 {
+		hours = defaultTrendingHours
+	}
+	hours = min(hours, maxTrendingHours)
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := campaignSelect("t.recent_joins", "t.score").
+		Join("JOIN "+trendingJoin, trendingArgs(hours)...).
+		WhereAny("c.status", statusStrings(models.JoinableStatuses)).
+		Where("c.end_time > now()").
+		OrderBy("t.score DESC", "c.id DESC").
+		Limit(feedLimit(req.Limit)).
+		ToSQL()
+	rows, err := database.Select[trendingRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query trending campaigns", "error", err)
+		return nil, queryError(err, "failed to query trending campaigns")
+	}
+
+	logger.FromContext(ctx).Debug("returning trending campaigns", "count", len(rows))
+	return &query.GetTrendingCampaignsResponse{Campaigns: database.Map(rows, trendingRow.toProto)}, nil
+}
+
+// 추천 SELECT의 추가 컬럼입니다. 참여했던 머천트는 참여 1건당 2점, 같은 카테고리
+// (campaigns.category)는 1건당 1점이며, 이유는 가장 강한 근거를 씁니다.
+var recommendColumns = []string{
+	"(2 * COALESCE(ma.joins, 0) + COALESCE(ca.joins, 0))::FLOAT8 AS score",
+	`CASE WHEN ma.joins IS NOT NULL THEN 'merchant'
+		WHEN ca.joins IS NOT NULL THEN 'category'
+		WHEN t.score IS NOT NULL THEN 'trending'
+		ELSE 'new' END AS reason`,
+}
+
+// 사용자가 참여했던 머천트/카테고리별 참여 수 (파라미터는 사용자 id)
+const (
+	merchantAffinityJoin = `LEFT JOIN (
+	SELECT pc.merchant_id, COUNT(*) AS joins
+	FROM participations up JOIN campaigns pc ON pc.id = up.campaign_id
+	WHERE up.user_id = ?
+	GROUP BY pc.merchant_id
+) ma ON ma.merchant_id = c.merchant_id`
+	categoryAffinityJoin = `LEFT JOIN (
+	SELECT pc.category, COUNT(*) AS joins
+	FROM participations up JOIN campaigns pc ON pc.id = up.campaign_id
+	WHERE up.user_id = ? AND pc.category IS NOT NULL
+	GROUP BY pc.category
+) ca ON ca.category = c.category`
+)
+
+// recommendedRow는 추천 캠페인 한 행입니다
+type recommendedRow struct {
+	campaignRow
+	Score  float64 `db:"score"`
+	Reason string  `db:"reason"`
+}
+
+func (r recommendedRow) toProto() *query.RecommendedCampaign {
+	return &query.RecommendedCampaign{
+		Campaign: r.campaignRow.toProto(),
+		Reason:   r.Reason,
+		Score:    r.Score,
+	}
+}
+
+// GetRecommendedCampaigns는 참여 이력의 머천트와 카테고리로 모집 중인 캠페인을 추천합니다.
+// 관련된 캠페인이 부족하면 인기 캠페인, 최신 캠페인 순으로 채우며, 이미 참여한 캠페인은 제외합니다.
+func (s *QueryServer) GetRecommendedCampaigns(ctx context.Context, req *query.GetRecommendedCampaignsRequest) (*query.GetRecommendedCampaignsResponse, error) {
+	logger.FromContext(ctx).Debug("GetRecommendedCampaigns", "user_id", req.UserId, "limit", req.Limit)
+
+	if err := checkID(req.UserId, "user_id", false); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := campaignSelect(recommendColumns...).
+		Join(merchantAffinityJoin, req.UserId).
+		Join(categoryAffinityJoin, req.UserId).
+		Join("LEFT JOIN "+trendingJoin, trendingArgs(defaultTrendingHours)...).
+		WhereAny("c.status", statusStrings(models.JoinableStatuses)).
+		Where("c.end_time > now()").
+		Where("NOT EXISTS (SELECT 1 FROM participations own WHERE own.campaign_id = c.id AND own.user_id = ?)", req.UserId).
+		OrderBy("score DESC", "COALESCE(t.score, 0) DESC", "c.created_at DESC", "c.id DESC").
+		Limit(feedLimit(req.Limit)).
+		ToSQL()
+	rows, err := database.Select[recommendedRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query recommended campaigns", "error", err)
+		return nil, queryError(err, "failed to query recommended campaigns")
+	}
+
+	logger.FromContext(ctx).Debug("returning recommended campaigns", "count", len(rows))
+	return &query.GetRecommendedCampaignsResponse{Campaigns: database.Map(rows, recommendedRow.toProto)}, nil
+}
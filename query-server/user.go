@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Reserve-to-save-backend/pkg/address"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/models"
+	"github.com/Reserve-to-save-backend/pkg/proto/query"
+)
+
+// UserServer는 gRPC UserService를 구현합니다
+type UserServer struct {
+	query.UnimplementedUserServiceServer
+	db *database.Router
+}
+
+// NewUserServer는 새로운 UserServer 인스턴스를 생성합니다
+func NewUserServer(db *database.Router) *UserServer {
+	return &UserServer{db: db}
+}
+
+// userRow는 users 조회 결과와 참여 집계 한 행입니다
+type userRow struct {
+	ID                 string         `db:"id"`
+	WalletAddress      string         `db:"wallet_address"`
+	LineUserID         sql.NullString `db:"line_user_id"`
+	Status             sql.NullString `db:"status"`
+	CreatedAt          sql.NullTime   `db:"created_at"`
+	ParticipationCount int64          `db:"participation_count"`
+	TotalDeposit       models.BigInt  `db:"total_deposit"`
+}
+
+// toProto는 주소를 EIP-55 형식으로, 금액을 USDT 소수 문자열로 변환합니다
+func (r userRow) toProto() *query.User {
+	return &query.User{
+		Id:                 r.ID,
+		WalletAddress:      address.Display(r.WalletAddress),
+		LineUserId:         r.LineUserID.String,
+		Status:             r.Status.String,
+		CreatedAt:          toTimestamp(r.CreatedAt),
+		ParticipationCount: r.ParticipationCount,
+		TotalDeposit:       usdt(r.TotalDeposit),
+	}
+}
+
+// rebateRow는 정산된 participations + campaigns 조회 결과 한 행입니다
+type rebateRow struct {
+	ParticipationID  string         `db:"participation_id"`
+	CampaignID       string         `db:"campaign_id"`
+	CampaignAddress  string         `db:"campaign_address"`
+	Amount           models.BigInt  `db:"amount"`
+	SettlementTxHash sql.NullString `db:"settlement_tx_hash"`
+	SettledAt        sql.NullTime   `db:"settled_at"`
+}
+
+func (r rebateRow) toProto() *query.Rebate {
+	return &query.Rebate{
+		ParticipationId:  r.ParticipationID,
+		CampaignId:       r.CampaignID,
+		CampaignAddress:  address.Display(r.CampaignAddress),
+		Amount:           usdt(r.Amount),
+		SettlementTxHash: r.SettlementTxHash.String,
+		SettledAt:        toTimestamp(r.SettledAt),
+	}
+}
+
+// profileStats는 사용자 프로필의 참여/리베이트 집계입니다
+type profileStats struct {
+	ActiveCampaignCount int64         `db:"active_campaign_count"`
+	TotalRebate         models.BigInt `db:"total_rebate"`
+}
+
+// userSelect는 사용자 조회 공통 SELECT를 생성합니다 (참여 집계는 서브쿼리로 계산)
+func userSelect() *database.SelectBuilder {
+	return database.NewSelect(
+		"u.id", "u.wallet_address", "u.line_user_id", "u.status", "u.created_at",
+		"(SELECT COUNT(*) FROM participations p WHERE p.user_id = u.id) AS participation_count",
+		"(SELECT COALESCE(SUM(p.deposit_amount), 0) FROM participations p WHERE p.user_id = u.id) AS total_deposit",
+	).
+		From("users u")
+}
+
+// GetUser는 특정 사용자를 조회합니다
+func (s *UserServer) GetUser(ctx context.Context, req *query.GetUserRequest) (*query.GetUserResponse, error) {
+	logger.FromContext(ctx).Debug("GetUser", "user_id", req.UserId)
+
+	if err := checkID(req.UserId, "user_id", false); err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, userSelect().Where("u.id = ?", req.UserId))
+}
+
+// GetUserByWallet은 지갑 주소로 사용자를 조회합니다
+func (s *UserServer) GetUserByWallet(ctx context.Context, req *query.GetUserByWalletRequest) (*query.GetUserResponse, error) {
+	logger.FromContext(ctx).Debug("GetUserByWallet", "wallet_address", req.WalletAddress)
+
+	wallet, err := canonicalAddress(req.WalletAddress)
+	if err != nil {
+		return nil, apperrors.Catalog(apperrors.ReasonInvalidWallet)
+	}
+	return s.getUser(ctx, userSelect().Where("u.wallet_address = ?", wallet))
+}
+
+func (s *UserServer) getUser(ctx context.Context, b *database.SelectBuilder) (*query.GetUserResponse, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	sqlQuery, args := b.ToSQL()
+	row, err := database.Get[userRow](ctx, s.db, sqlQuery, args...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query user", "error", err)
+		return nil, queryError(err, "failed to query user")
+	}
+	if row == nil {
+		logger.FromContext(ctx).Debug("user not found")
+		return &query.GetUserResponse{Found: false}, nil
+	}
+
+	return &query.GetUserResponse{
+		User:  row.toProto(),
+		Found: true,
+	}, nil
+}
+
+// GetUserProfile은 사용자와 진행 중인 캠페인 수, 리베이트 합계 및 최근 내역을 조회합니다
+func (s *UserServer) GetUserProfile(ctx context.Context, req *query.GetUserRequest) (*query.GetUserProfileResponse, error) {
+	logger.FromContext(ctx).Debug("GetUserProfile", "user_id", req.UserId)
+
+	resp, err := s.GetUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return &query.GetUserProfileResponse{Found: false}, nil
+	}
+
+	ctx, cancel := database.WithQueryTimeout(ctx, rpcQueryTimeout)
+	defer cancel()
+
+	// 참여/리베이트 집계 조회
+	var stats profileStats
+	statsQuery := fmt.Sprintf(`SELECT
+		(SELECT COUNT(*) FROM participations p JOIN campaigns c ON p.campaign_id = c.id
+			WHERE p.user_id = $1 AND c.status IN (%s)) AS active_campaign_count,
+		(SELECT COALESCE(SUM(p.actual_rebate), 0) FROM participations p
+			WHERE p.user_id = $1 AND p.status = $2) AS total_rebate`, statusList(models.ActiveStatuses))
+	if err := s.db.GetContext(ctx, &stats, statsQuery, req.UserId, models.ParticipationSettled); err != nil {
+		logger.FromContext(ctx).Error("failed to query user stats", "error", err)
+		return nil, queryError(err, "failed to query user stats")
+	}
+
+	// 최근 리베이트 내역 조회
+	rebatesQuery, rebatesArgs := rebateSelect().
+		Where("p.user_id = ?", req.UserId).
+		OrderBy("c.settlement_date DESC", "p.id DESC").
+		Limit(historyLimit).
+		ToSQL()
+	rebates, err := database.Select[rebateRow](ctx, s.db, rebatesQuery, rebatesArgs...)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query user rebates", "error", err)
+		return nil, queryError(err, "failed to query rebates")
+	}
+
+	return &query.GetUserProfileResponse{
+		Profile: &query.UserProfile{
+			User:                resp.User,
+			ActiveCampaignCount: stats.ActiveCampaignCount,
+			TotalRebate:         usdt(stats.TotalRebate),
+			Rebates:             database.Map(rebates, rebateRow.toProto),
+		},
+		Found: true,
+	}, nil
+}
+
+// rebateSelect는 정산된 참여의 리베이트를 조회하는 SELECT를 생성합니다
+func rebateSelect() *database.SelectBuilder {
+	return database.NewSelect(
+		"p.id AS participation_id", "p.campaign_id", "c.chain_address AS campaign_address",
+		"p.actual_rebate AS amount", "p.settlement_tx_hash", "c.settlement_date AS settled_at",
+	).
+		From("participations p").
+		Join("JOIN campaigns c ON p.campaign_id = c.id").
+		Where("p.status = ?", models.ParticipationSettled).
+		Where("p.actual_rebate IS NOT NULL")
+}
+
+// canonicalAddress는 "0x" 접두사가 있거나 없는 주소를 저장 형식(소문자)으로 변환합니다
+func canonicalAddress(wallet string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(wallet))
+	if !strings.HasPrefix(s, "0x") {
+		s = "0x" + s
+	}
+	return address.Canonical(s)
+}
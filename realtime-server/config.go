@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/realtime-server/realtime"
+)
+
+// Config is the realtime-server configuration, loaded by config.MustLoad
+type Config struct {
+	Port string `env:"REALTIME_SERVER_PORT" default:"3009"`
+	// DebugAddr serves pkg/diag (empty disables); keep it off the public network
+	DebugAddr string `env:"REALTIME_DEBUG_ADDR"`
+
+	Database database.Config
+	Log      logger.Config
+	Errors   errreport.Config
+	Realtime realtime.Config
+}
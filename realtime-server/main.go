@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+	"github.com/Reserve-to-save-backend/pkg/config"
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/diag"
+	"github.com/Reserve-to-save-backend/pkg/errreport"
+	"github.com/Reserve-to-save-backend/pkg/health"
+	"github.com/Reserve-to-save-backend/pkg/logger"
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+	"github.com/Reserve-to-save-backend/realtime-server/realtime"
+)
+
+// shutdownTimeout bounds closing the connections on SIGTERM
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	envErr := godotenv.Load()
+
+	var cfg Config
+	config.MustLoad(&cfg)
+
+	logger.Init("realtime-server", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
+	}
+
+	if err := errreport.Init("realtime-server", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
+	}
+	defer errreport.Flush()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.NewDB(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	// Redis carries the events between instances and the presence
+	redis, err := database.NewRedisClient(database.RedisConfigFromEnv())
+	if err != nil {
+		logger.Fatal("Failed to connect to Redis", "error", err)
+	}
+	defer redis.Close()
+
+	clk := clock.New()
+	presence := realtime.NewPresence(redis.UniversalClient, cfg.Realtime.PresenceTTL, clk)
+	hub := realtime.NewHub(ctx, redis.UniversalClient, presence, clk)
+	go hub.Run(ctx)
+	go realtime.NewBridge(db, cfg.Database, redis.UniversalClient, hub).Run(ctx)
+
+	server := realtime.NewServer(hub, presence, realtime.NewAuthenticator(cfg.Realtime.AuthURL), cfg.Realtime)
+
+	checker := health.NewChecker("realtime-server")
+	checker.Add("postgres", health.Database(db))
+	checker.Add("redis", health.Redis(redis.UniversalClient))
+
+	mux := http.NewServeMux()
+	server.Register(mux)
+	mux.Handle(metrics.Path, metrics.Handler())
+	mux.Handle("/live", checker.LiveHandler())
+	mux.Handle("/ready", checker.ReadyHandler())
+
+	if err := diag.Start(cfg.DebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
+	}
+
+	srv := &http.Server{Addr: ":" + cfg.Port, Handler: mux}
+	go func() {
+		slog.Info("Realtime server starting", "port", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to start server", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	// WebSocket connections are hijacked, so srv.Shutdown does not wait for
+	// them; the hub closes them first
+	hub.Shutdown(shutdownCtx)
+	srv.Shutdown(shutdownCtx)
+	slog.Info("Realtime server stopped")
+}
@@ -0,0 +1,84 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+const authTimeout = 10 * time.Second
+
+var ErrInvalidToken = apperrors.Unauthorized("Invalid token")
+
+// Claims are the access token claims the realtime server needs
+type Claims struct {
+	UserID    uuid.UUID `json:"user_id"`
+	ExpiresAt int64     `json:"exp"`
+}
+
+// Authenticator checks access tokens with auth-server, the same check the
+// gateway makes, so revoked sessions are refused here too
+type Authenticator struct {
+	client   *http.Client
+	endpoint string
+}
+
+func NewAuthenticator(authURL string) *Authenticator {
+	return &Authenticator{
+		client:   &http.Client{Timeout: authTimeout},
+		endpoint: strings.TrimRight(authURL, "/") + "/auth/validate",
+	}
+}
+
+// Authenticate returns the claims of token, or ErrInvalidToken
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Claims, error) {
+	if token == "" {
+		return nil, apperrors.Catalog(apperrors.ReasonTokenRequired)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		// auth-server being down is not the client's fault
+		return nil, apperrors.Unavailable(err, "auth-server is unavailable")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, ErrInvalidToken
+	case resp.StatusCode != http.StatusOK:
+		return nil, apperrors.Unavailable(fmt.Errorf("auth-server returned %d", resp.StatusCode), "auth-server is unavailable")
+	}
+
+	var result struct {
+		Success bool   `json:"success"`
+		Claims  Claims `json:"claims"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.Success || result.Claims.UserID == uuid.Nil {
+		return nil, ErrInvalidToken
+	}
+	return &result.Claims, nil
+}
+
+// bearerToken reads the token from the Authorization header, or from the
+// access_token query parameter since browsers cannot set headers on a
+// WebSocket handshake
+func bearerToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		return strings.TrimPrefix(h, "Bearer ")
+	}
+	return r.URL.Query().Get("access_token")
+}
@@ -0,0 +1,252 @@
+package realtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/database"
+	"github.com/Reserve-to-save-backend/pkg/models"
+)
+
+const (
+	bridgeLockKey = keyPrefix + "bridge"
+	// bridgeLockTTL is how long a dead leader blocks the others; the lock is
+	// renewed at a third of it
+	bridgeLockTTL = 15 * time.Second
+	// sentTTL keeps the record of sent participation events long enough to
+	// outlast repeated updates of the same row
+	sentTTL = 7 * 24 * time.Hour
+	// bridgeQueryTimeout bounds loading one changed row
+	bridgeQueryTimeout = 5 * time.Second
+	// maxProgressCache bounds the remembered campaign progress
+	maxProgressCache = 10000
+)
+
+// renewScript extends the lock only while this instance still owns it
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// Bridge turns database change notifications into events. Every instance
+// runs one, but only the holder of a Redis lock listens, so each change is
+// loaded and published once; another instance takes over within
+// bridgeLockTTL when the leader goes away. Changes made during a takeover
+// are not replayed.
+type Bridge struct {
+	db    *database.DB
+	dbCfg database.Config
+	redis redis.UniversalClient
+	hub   *Hub
+	id    string
+
+	// progress holds a digest of the last progress published per campaign,
+	// so updates that do not change it (metadata, publishing) stay quiet
+	progress map[uuid.UUID][sha256.Size]byte
+	// resync clears progress after the listener reconnected, since changes
+	// were missed meanwhile
+	resync atomic.Bool
+}
+
+func NewBridge(db *database.DB, dbCfg database.Config, client redis.UniversalClient, hub *Hub) *Bridge {
+	return &Bridge{
+		db:       db,
+		dbCfg:    dbCfg,
+		redis:    client,
+		hub:      hub,
+		id:       uuid.NewString(),
+		progress: make(map[uuid.UUID][sha256.Size]byte),
+	}
+}
+
+// Run competes for the bridge lock and bridges changes while holding it,
+// until ctx is done
+func (b *Bridge) Run(ctx context.Context) {
+	for {
+		ok, err := b.redis.SetNX(ctx, bridgeLockKey, b.id, bridgeLockTTL).Result()
+		if err != nil && ctx.Err() == nil {
+			slog.Warn("Failed to acquire bridge lock", "error", err)
+		}
+		if ok {
+			slog.Info("Bridging database changes")
+			if err := b.lead(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Bridge stopped", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bridgeLockTTL / 3):
+		}
+	}
+}
+
+// lead listens for changes until ctx is done or the lock is lost
+func (b *Bridge) lead(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer releaseScript.Run(context.WithoutCancel(ctx), b.redis, []string{bridgeLockKey}, b.id)
+
+	listener, err := database.NewListener(b.dbCfg, database.ChangeChannel)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	listener.OnReconnect = func() { b.resync.Store(true) }
+
+	go func() {
+		ticker := time.NewTicker(bridgeLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				renewed, err := renewScript.Run(ctx, b.redis, []string{bridgeLockKey}, b.id, bridgeLockTTL.Milliseconds()).Int()
+				if err != nil || renewed == 0 {
+					slog.Warn("Lost bridge lock", "error", err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = listener.RunChanges(ctx, func(ev database.ChangeEvent) {
+		// the leader relays every change for services without a database
+		// connection, such as the gateway's response cache
+		if err := database.RelayChange(ctx, b.redis, ev); err != nil {
+			slog.Warn("Failed to relay change", "table", ev.Table, "id", ev.ID, "error", err)
+		}
+		if err := b.handle(ctx, ev); err != nil {
+			slog.Error("Failed to bridge change", "table", ev.Table, "id", ev.ID, "error", err)
+		}
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+func (b *Bridge) handle(ctx context.Context, ev database.ChangeEvent) error {
+	if ev.Op == "DELETE" {
+		return nil
+	}
+	id, err := uuid.Parse(ev.ID)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bridgeQueryTimeout)
+	defer cancel()
+	switch ev.Table {
+	case "participations":
+		return b.participationChanged(ctx, id)
+	case "campaigns":
+		return b.campaignChanged(ctx, id)
+	}
+	return nil
+}
+
+type participationChange struct {
+	ID            uuid.UUID     `db:"id" json:"participation_id"`
+	CampaignID    uuid.UUID     `db:"campaign_id" json:"-"`
+	UserID        uuid.UUID     `db:"user_id" json:"-"`
+	Status        string        `db:"status" json:"status"`
+	DepositAmount models.BigInt `db:"deposit_amount" json:"deposit_amount"`
+	ActualRebate  models.BigInt `db:"actual_rebate" json:"actual_rebate"`
+}
+
+// participationEvents maps the participation status a user hears about to
+// its event
+var participationEvents = map[string]string{
+	models.ParticipationActive:    EventParticipationConfirmed,
+	models.ParticipationCancelled: EventParticipationCancelled,
+	models.ParticipationRefunded:  EventParticipationRefunded,
+	models.ParticipationSettled:   EventRebateReceived,
+}
+
+// participationChanged tells the participant once per status reached. The
+// notification carries no old row, so a Redis marker per participation and
+// status keeps later updates of the row from repeating the event.
+func (b *Bridge) participationChanged(ctx context.Context, id uuid.UUID) error {
+	var p participationChange
+	err := b.db.GetContext(ctx, &p, `
+		SELECT id, campaign_id, user_id, status, deposit_amount, actual_rebate
+		FROM participations WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load participation: %w", err)
+	}
+
+	eventType, ok := participationEvents[p.Status]
+	if !ok {
+		return nil
+	}
+	if eventType == EventRebateReceived && p.ActualRebate.Big().Sign() <= 0 {
+		return nil
+	}
+
+	first, err := b.redis.SetNX(ctx, fmt.Sprintf("%ssent:%s:%s", keyPrefix, p.ID, p.Status), 1, sentTTL).Result()
+	if err != nil || !first {
+		return err
+	}
+	return b.hub.PublishToUser(ctx, p.UserID, Event{Type: eventType, CampaignID: &p.CampaignID, Data: p})
+}
+
+type campaignProgress struct {
+	Status        models.CampaignStatus `db:"status" json:"status"`
+	CurrentQty    int                   `db:"current_qty" json:"current_qty"`
+	MinQty        int                   `db:"min_qty" json:"min_qty"`
+	CurrentAmount models.BigInt         `db:"current_amount" json:"current_amount"`
+	TargetAmount  models.BigInt         `db:"target_amount" json:"target_amount"`
+}
+
+// campaignChanged publishes the campaign's progress when it moved
+func (b *Bridge) campaignChanged(ctx context.Context, id uuid.UUID) error {
+	var p campaignProgress
+	err := b.db.GetContext(ctx, &p, `
+		SELECT status, current_qty, min_qty, current_amount, target_amount
+		FROM campaigns WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load campaign: %w", err)
+	}
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(raw)
+	if b.resync.Swap(false) || len(b.progress) >= maxProgressCache {
+		b.progress = make(map[uuid.UUID][sha256.Size]byte)
+	}
+	if last, ok := b.progress[id]; ok && last == digest {
+		return nil
+	}
+	b.progress[id] = digest
+
+	return b.hub.PublishToCampaign(ctx, id, Event{Type: EventCampaignProgress, Data: json.RawMessage(raw)})
+}
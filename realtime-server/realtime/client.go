@@ -0,0 +1,216 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize bounds client messages, which are small commands
+	maxMessageSize = 4 << 10
+	// sendBuffer is how many events may queue for one client before it is
+	// considered too slow and disconnected
+	sendBuffer = 64
+)
+
+// Client actions
+const (
+	actionSubscribe   = "subscribe"
+	actionUnsubscribe = "unsubscribe"
+)
+
+// clientMessage is a command sent by the client:
+// {"action": "subscribe", "campaign_id": "..."}
+type clientMessage struct {
+	Action     string    `json:"action"`
+	CampaignID uuid.UUID `json:"campaign_id"`
+}
+
+// Client is one WebSocket connection of an authenticated user
+type Client struct {
+	id     string
+	userID uuid.UUID
+	// expires is when the access token expires; the connection is closed
+	// then and the client reconnects with a fresh token
+	expires time.Time
+	conn    *websocket.Conn
+	hub     *Hub
+	cfg     Config
+
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu        sync.Mutex
+	campaigns map[uuid.UUID]struct{}
+}
+
+func newClient(conn *websocket.Conn, hub *Hub, cfg Config, userID uuid.UUID, expires time.Time) *Client {
+	return &Client{
+		id:        uuid.NewString(),
+		userID:    userID,
+		expires:   expires,
+		conn:      conn,
+		hub:       hub,
+		cfg:       cfg,
+		send:      make(chan []byte, sendBuffer),
+		done:      make(chan struct{}),
+		campaigns: make(map[uuid.UUID]struct{}),
+	}
+}
+
+// enqueue queues payload without blocking the hub; a client that cannot
+// keep up is disconnected instead of buffered without bound
+func (c *Client) enqueue(payload []byte) {
+	select {
+	case c.send <- payload:
+	case <-c.done:
+	default:
+		droppedClients.Inc()
+		c.close()
+	}
+}
+
+// close makes writePump close the connection
+func (c *Client) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+func (c *Client) campaignIDs() []uuid.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]uuid.UUID, 0, len(c.campaigns))
+	for id := range c.campaigns {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (c *Client) reply(ev Event) {
+	ev.At = c.hub.clk.Now().UTC()
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	c.enqueue(payload)
+}
+
+// readPump handles client commands until the connection fails, then
+// unregisters the client
+func (c *Client) readPump(ctx context.Context) {
+	defer func() {
+		c.close()
+		c.hub.unregister(ctx, c)
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(raw, &msg); err != nil || msg.CampaignID == uuid.Nil {
+			c.reply(Event{Type: EventError, Data: "expected {\"action\", \"campaign_id\"}"})
+			continue
+		}
+
+		switch msg.Action {
+		case actionSubscribe:
+			c.subscribe(ctx, msg.CampaignID)
+		case actionUnsubscribe:
+			c.mu.Lock()
+			_, ok := c.campaigns[msg.CampaignID]
+			delete(c.campaigns, msg.CampaignID)
+			c.mu.Unlock()
+			if ok {
+				c.hub.leave(ctx, c, msg.CampaignID)
+			}
+			c.reply(Event{Type: EventUnsubscribed, CampaignID: &msg.CampaignID})
+		default:
+			c.reply(Event{Type: EventError, Data: "unknown action " + msg.Action})
+		}
+	}
+}
+
+func (c *Client) subscribe(ctx context.Context, campaignID uuid.UUID) {
+	c.mu.Lock()
+	_, ok := c.campaigns[campaignID]
+	full := len(c.campaigns) >= c.cfg.MaxCampaigns
+	c.mu.Unlock()
+	if ok {
+		c.reply(Event{Type: EventSubscribed, CampaignID: &campaignID})
+		return
+	}
+	if full {
+		c.reply(Event{Type: EventError, CampaignID: &campaignID, Data: "too many campaign subscriptions"})
+		return
+	}
+
+	if err := c.hub.join(ctx, c, campaignID); err != nil {
+		c.reply(Event{Type: EventError, CampaignID: &campaignID, Data: "subscription failed, retry later"})
+		return
+	}
+	c.mu.Lock()
+	c.campaigns[campaignID] = struct{}{}
+	c.mu.Unlock()
+	c.reply(Event{Type: EventSubscribed, CampaignID: &campaignID})
+}
+
+// writePump writes queued events and pings, refreshes presence and closes
+// the connection when the client is closed or its token expires
+func (c *Client) writePump(ctx context.Context) {
+	ping := time.NewTicker(pingPeriod)
+	heartbeat := time.NewTicker(c.cfg.PresenceTTL / 3)
+	expiry := time.NewTimer(time.Until(c.expires))
+	defer func() {
+		ping.Stop()
+		heartbeat.Stop()
+		expiry.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case payload := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				c.close()
+				return
+			}
+		case <-ping.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close()
+				return
+			}
+		case <-heartbeat.C:
+			c.hub.touch(ctx, c)
+		case <-expiry.C:
+			c.closeWith(websocket.ClosePolicyViolation, "token expired")
+			return
+		case <-c.done:
+			c.closeWith(websocket.CloseGoingAway, "")
+			return
+		}
+	}
+}
+
+func (c *Client) closeWith(code int, reason string) {
+	c.close()
+	msg := websocket.FormatCloseMessage(code, reason)
+	c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+}
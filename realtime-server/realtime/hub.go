@@ -0,0 +1,261 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+// redisTimeout bounds the Redis calls made for one client action
+const redisTimeout = 5 * time.Second
+
+// Hub connects local clients to the Redis channels they listen on
+type Hub struct {
+	redis    redis.UniversalClient
+	pubsub   *redis.PubSub
+	presence *Presence
+	clk      clock.Clock
+
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+	// active counts registered clients until they are unregistered
+	active sync.WaitGroup
+	// subs maps each subscribed channel to its local clients
+	subs map[string]map[*Client]struct{}
+}
+
+func NewHub(ctx context.Context, client redis.UniversalClient, presence *Presence, clk clock.Clock) *Hub {
+	return &Hub{
+		redis:    client,
+		pubsub:   client.Subscribe(ctx),
+		presence: presence,
+		clk:      clock.OrSystem(clk),
+		clients:  make(map[*Client]struct{}),
+		subs:     make(map[string]map[*Client]struct{}),
+	}
+}
+
+// Run delivers messages from Redis to the local clients until ctx is done.
+// go-redis resubscribes by itself after a lost connection; events sent in
+// between are lost.
+func (h *Hub) Run(ctx context.Context) {
+	ch := h.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			payload := []byte(msg.Payload)
+			h.mu.RLock()
+			for c := range h.subs[msg.Channel] {
+				c.enqueue(payload)
+			}
+			h.mu.RUnlock()
+		}
+	}
+}
+
+// Shutdown disconnects every local client, waits until their presence is
+// cleared or ctx is done, and closes the Redis subscription
+func (h *Hub) Shutdown(ctx context.Context) {
+	h.mu.RLock()
+	for c := range h.clients {
+		c.close()
+	}
+	h.mu.RUnlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	h.pubsub.Close()
+}
+
+// Publish sends ev to the clients listening on channel on every instance
+func (h *Hub) Publish(ctx context.Context, channel string, ev Event) error {
+	if ev.At.IsZero() {
+		ev.At = h.clk.Now().UTC()
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if err := h.redis.Publish(ctx, channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	publishedEvents.WithLabelValues(ev.Type).Inc()
+	return nil
+}
+
+// PublishToUser sends ev to every connection of the user
+func (h *Hub) PublishToUser(ctx context.Context, userID uuid.UUID, ev Event) error {
+	return h.Publish(ctx, userChannel(userID), ev)
+}
+
+// PublishToCampaign sends ev to every connection subscribed to the campaign
+func (h *Hub) PublishToCampaign(ctx context.Context, campaignID uuid.UUID, ev Event) error {
+	ev.CampaignID = &campaignID
+	return h.Publish(ctx, campaignChannel(campaignID), ev)
+}
+
+// register subscribes a new client to its user channel and marks the user
+// present
+func (h *Hub) register(ctx context.Context, c *Client) error {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	h.mu.Lock()
+	err := h.subscribe(ctx, c, userChannel(c.userID))
+	if err == nil {
+		h.clients[c] = struct{}{}
+		h.active.Add(1)
+	}
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	connections.Inc()
+
+	if err := h.presence.Touch(ctx, c.id, userPresenceKey(c.userID)); err != nil {
+		slog.Warn("Failed to record presence", "error", err)
+	}
+	return nil
+}
+
+// unregister drops every subscription of a disconnected client
+func (h *Hub) unregister(ctx context.Context, c *Client) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), redisTimeout)
+	defer cancel()
+
+	campaigns := c.campaignIDs()
+	h.mu.Lock()
+	_, ok := h.clients[c]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.clients, c)
+	defer h.active.Done()
+	connections.Dec()
+	h.unsubscribe(ctx, c, userChannel(c.userID))
+	for _, id := range campaigns {
+		h.unsubscribe(ctx, c, campaignChannel(id))
+	}
+	h.mu.Unlock()
+
+	keys := []string{userPresenceKey(c.userID)}
+	for _, id := range campaigns {
+		keys = append(keys, campaignPresenceKey(id))
+	}
+	if err := h.presence.Leave(ctx, c.id, keys...); err != nil {
+		slog.Warn("Failed to clear presence", "error", err)
+	}
+	for _, id := range campaigns {
+		h.publishViewers(ctx, id)
+	}
+}
+
+// join subscribes c to a campaign's progress and announces the new viewer
+// count
+func (h *Hub) join(ctx context.Context, c *Client, campaignID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	h.mu.Lock()
+	err := h.subscribe(ctx, c, campaignChannel(campaignID))
+	h.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := h.presence.Touch(ctx, c.id, campaignPresenceKey(campaignID)); err != nil {
+		slog.Warn("Failed to record presence", "error", err)
+	}
+	h.publishViewers(ctx, campaignID)
+	return nil
+}
+
+// leave undoes join
+func (h *Hub) leave(ctx context.Context, c *Client, campaignID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	h.mu.Lock()
+	h.unsubscribe(ctx, c, campaignChannel(campaignID))
+	h.mu.Unlock()
+	if err := h.presence.Leave(ctx, c.id, campaignPresenceKey(campaignID)); err != nil {
+		slog.Warn("Failed to clear presence", "error", err)
+	}
+	h.publishViewers(ctx, campaignID)
+}
+
+// touch refreshes the presence of a connected client
+func (h *Hub) touch(ctx context.Context, c *Client) {
+	ctx, cancel := context.WithTimeout(ctx, redisTimeout)
+	defer cancel()
+
+	keys := []string{userPresenceKey(c.userID)}
+	for _, id := range c.campaignIDs() {
+		keys = append(keys, campaignPresenceKey(id))
+	}
+	if err := h.presence.Touch(ctx, c.id, keys...); err != nil {
+		slog.Warn("Failed to refresh presence", "error", err)
+	}
+}
+
+func (h *Hub) publishViewers(ctx context.Context, campaignID uuid.UUID) {
+	viewers, err := h.presence.CampaignViewers(ctx, campaignID)
+	if err == nil {
+		err = h.PublishToCampaign(ctx, campaignID, Event{Type: EventPresence, Data: map[string]int64{"viewers": viewers}})
+	}
+	if err != nil {
+		slog.Warn("Failed to publish campaign viewers", "campaign_id", campaignID, "error", err)
+	}
+}
+
+// subscribe adds c to channel, subscribing this instance on first use.
+// Callers hold h.mu.
+func (h *Hub) subscribe(ctx context.Context, c *Client, channel string) error {
+	clients, ok := h.subs[channel]
+	if !ok {
+		if err := h.pubsub.Subscribe(ctx, channel); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+		}
+		clients = make(map[*Client]struct{})
+		h.subs[channel] = clients
+	}
+	clients[c] = struct{}{}
+	return nil
+}
+
+// unsubscribe removes c from channel, unsubscribing this instance once no
+// client is left. Callers hold h.mu.
+func (h *Hub) unsubscribe(ctx context.Context, c *Client, channel string) {
+	clients, ok := h.subs[channel]
+	if !ok {
+		return
+	}
+	delete(clients, c)
+	if len(clients) > 0 {
+		return
+	}
+	delete(h.subs, channel)
+	if err := h.pubsub.Unsubscribe(ctx, channel); err != nil {
+		slog.Warn("Failed to unsubscribe", "channel", channel, "error", err)
+	}
+}
@@ -0,0 +1,41 @@
+package realtime
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Reserve-to-save-backend/pkg/metrics"
+)
+
+var (
+	connections = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "realtime",
+			Name:      "connections",
+			Help:      "Open WebSocket connections on this instance.",
+		},
+	)
+
+	publishedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "realtime",
+			Name:      "events_total",
+			Help:      "Events published to Redis by type.",
+		},
+		[]string{"type"},
+	)
+
+	droppedClients = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "realtime",
+			Name:      "slow_clients_dropped_total",
+			Help:      "Connections closed because they did not keep up with their events.",
+		},
+	)
+)
+
+func init() {
+	metrics.MustRegister(connections, publishedEvents, droppedClients)
+}
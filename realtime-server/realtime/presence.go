@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/Reserve-to-save-backend/pkg/clock"
+)
+
+// Presence tracks which users are connected and how many connections watch
+// each campaign, across instances. Each key is a sorted set of connection
+// ids scored by when they expire; connections refresh their entries with
+// Touch, so an instance that dies without cleaning up drops out after the
+// TTL.
+type Presence struct {
+	redis redis.UniversalClient
+	ttl   time.Duration
+	clk   clock.Clock
+}
+
+func NewPresence(client redis.UniversalClient, ttl time.Duration, clk clock.Clock) *Presence {
+	return &Presence{redis: client, ttl: ttl, clk: clock.OrSystem(clk)}
+}
+
+func userPresenceKey(id uuid.UUID) string {
+	return keyPrefix + "presence:user:" + id.String()
+}
+
+func campaignPresenceKey(id uuid.UUID) string {
+	return keyPrefix + "presence:campaign:" + id.String()
+}
+
+// Touch marks conn present under each key until the TTL passes
+func (p *Presence) Touch(ctx context.Context, conn string, keys ...string) error {
+	now := p.clk.Now()
+	expires := float64(now.Add(p.ttl).UnixMilli())
+	_, err := p.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZAdd(ctx, key, &redis.Z{Score: expires, Member: conn})
+			pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+			pipe.PExpire(ctx, key, 2*p.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Leave removes conn from each key
+func (p *Presence) Leave(ctx context.Context, conn string, keys ...string) error {
+	_, err := p.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZRem(ctx, key, conn)
+		}
+		return nil
+	})
+	return err
+}
+
+func (p *Presence) count(ctx context.Context, key string) (int64, error) {
+	return p.redis.ZCount(ctx, key, strconv.FormatInt(p.clk.Now().UnixMilli(), 10), "+inf").Result()
+}
+
+// UserOnline reports whether the user has an open connection on any
+// instance
+func (p *Presence) UserOnline(ctx context.Context, id uuid.UUID) (bool, error) {
+	n, err := p.count(ctx, userPresenceKey(id))
+	return n > 0, err
+}
+
+// CampaignViewers is the number of connections subscribed to the campaign
+func (p *Presence) CampaignViewers(ctx context.Context, id uuid.UUID) (int64, error) {
+	return p.count(ctx, campaignPresenceKey(id))
+}
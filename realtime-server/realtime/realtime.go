@@ -0,0 +1,67 @@
+// Package realtime pushes user notifications and campaign progress to
+// WebSocket clients.
+//
+// Events travel between instances over Redis pub/sub, on one channel per
+// user and per campaign; an instance subscribes to a channel only while one
+// of its clients needs it, so any number of instances can run behind a load
+// balancer. The Bridge, run by one instance at a time, turns the database
+// change notifications (database.ChangeChannel) into events and relays them
+// on database.ChangeRelayChannel.
+package realtime
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const keyPrefix = "r2s:realtime:"
+
+// Event types
+const (
+	EventParticipationConfirmed = "participation.confirmed"
+	EventParticipationCancelled = "participation.cancelled"
+	EventParticipationRefunded  = "participation.refunded"
+	EventRebateReceived         = "rebate.received"
+	EventCampaignProgress       = "campaign.progress"
+	// EventSessionAnomaly is published by auth-server when a session was
+	// ended because its refresh token came from a new device or country
+	EventSessionAnomaly = "security.session_anomaly"
+	// EventPresence carries a campaign's viewer count
+	EventPresence = "presence"
+
+	// Replies to client messages
+	EventSubscribed   = "subscribed"
+	EventUnsubscribed = "unsubscribed"
+	EventError        = "error"
+)
+
+// Config is loadable with pkg/config
+type Config struct {
+	// AuthURL is the auth-server base URL tokens are validated against
+	AuthURL string `env:"REALTIME_AUTH_URL" default:"http://localhost:3002"`
+	// AllowedOrigins lists the browser origins allowed to connect; empty
+	// allows only same-origin pages
+	AllowedOrigins []string `env:"REALTIME_ALLOWED_ORIGINS"`
+	// MaxCampaigns caps the campaign channels one connection subscribes to
+	MaxCampaigns int `env:"REALTIME_MAX_CAMPAIGNS" default:"20"`
+	// PresenceTTL is how long a connection counts as present without a
+	// heartbeat; heartbeats are sent at a third of it
+	PresenceTTL time.Duration `env:"REALTIME_PRESENCE_TTL" default:"90s"`
+}
+
+// Event is one message sent to clients
+type Event struct {
+	Type       string      `json:"type"`
+	CampaignID *uuid.UUID  `json:"campaign_id,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	At         time.Time   `json:"at"`
+}
+
+func userChannel(id uuid.UUID) string {
+	return keyPrefix + "user:" + id.String()
+}
+
+func campaignChannel(id uuid.UUID) string {
+	return keyPrefix + "campaign:" + id.String()
+}
@@ -0,0 +1,128 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+
+	apperrors "github.com/Reserve-to-save-backend/pkg/errors"
+)
+
+// Server serves the WebSocket endpoint and the presence queries
+type Server struct {
+	hub      *Hub
+	presence *Presence
+	auth     *Authenticator
+	cfg      Config
+	upgrader websocket.Upgrader
+}
+
+func NewServer(hub *Hub, presence *Presence, auth *Authenticator, cfg Config) *Server {
+	s := &Server{hub: hub, presence: presence, auth: auth, cfg: cfg}
+	s.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     s.checkOrigin,
+	}
+	return s
+}
+
+// Register adds the routes to mux. The presence routes are for other
+// services and must not be exposed publicly.
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /ws", s.ServeWS)
+	mux.HandleFunc("GET /presence/users/{id}", s.UserPresence)
+	mux.HandleFunc("GET /presence/campaigns/{id}", s.CampaignPresence)
+}
+
+// checkOrigin allows same-origin pages, non-browser clients (no Origin) and
+// the configured origins
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if slices.Contains(s.cfg.AllowedOrigins, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// ServeWS handles GET /ws. The token is checked before the upgrade, so an
+// unauthenticated client gets a plain 401.
+func (s *Server) ServeWS(w http.ResponseWriter, r *http.Request) {
+	claims, err := s.auth.Authenticate(r.Context(), bearerToken(r))
+	if err != nil {
+		respondError(w, err)
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already answered the client
+		return
+	}
+
+	client := newClient(conn, s.hub, s.cfg, claims.UserID, time.Unix(claims.ExpiresAt, 0))
+	ctx := r.Context()
+	if err := s.hub.register(ctx, client); err != nil {
+		slog.Error("Failed to register connection", "error", err)
+		client.closeWith(websocket.CloseTryAgainLater, "try again later")
+		conn.Close()
+		return
+	}
+	go client.writePump(ctx)
+	client.readPump(ctx)
+}
+
+// UserPresence handles GET /presence/users/{id}
+func (s *Server) UserPresence(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError(w, apperrors.InvalidArgument("Invalid user ID"))
+		return
+	}
+	online, err := s.presence.UserOnline(r.Context(), id)
+	if err != nil {
+		respondError(w, apperrors.Unavailable(err, "presence is unavailable"))
+		return
+	}
+	respond(w, map[string]interface{}{"user_id": id, "online": online})
+}
+
+// CampaignPresence handles GET /presence/campaigns/{id}
+func (s *Server) CampaignPresence(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		respondError(w, apperrors.InvalidArgument("Invalid campaign ID"))
+		return
+	}
+	viewers, err := s.presence.CampaignViewers(r.Context(), id)
+	if err != nil {
+		respondError(w, apperrors.Unavailable(err, "presence is unavailable"))
+		return
+	}
+	respond(w, map[string]interface{}{"campaign_id": id, "viewers": viewers})
+}
+
+func respond(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": data})
+}
+
+func respondError(w http.ResponseWriter, err error) {
+	status, body := apperrors.Response(err)
+	if status >= http.StatusInternalServerError {
+		slog.Error("request failed", "error", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
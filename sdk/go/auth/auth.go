@@ -0,0 +1,312 @@
+// Package auth signs in to the R2S API with a wallet and keeps the access
+// token fresh. Use Session.Transport as the generated client's HTTP
+// transport:
+//
+//	session := auth.NewSession("https://api.example.com", nil)
+//	if _, err := session.SignIn(ctx, address, sign); err != nil { ... }
+//	cfg := client.NewConfiguration()
+//	cfg.Servers = client.ServerConfigurations{{URL: "https://api.example.com"}}
+//	cfg.HTTPClient = &http.Client{Transport: session.Transport(nil)}
+//	api := client.NewAPIClient(cfg)
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshLeeway refreshes the access token this long before it expires
+const refreshLeeway = 30 * time.Second
+
+// ErrNotSignedIn is returned when a token is needed before SignIn or
+// SetTokens
+var ErrNotSignedIn = errors.New("not signed in")
+
+// SignFunc signs message with the wallet's key (EIP-191 personal_sign) and
+// returns the 0x-prefixed signature
+type SignFunc func(ctx context.Context, message string) (string, error)
+
+// APIError is an error response from the API
+type APIError struct {
+	Status int
+	Code   string
+	// Reason is the catalogued failure, such as R2S-1001, if any
+	Reason  string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("r2s: %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// User is the signed-in user returned by SignIn
+type User struct {
+	ID            string `json:"id"`
+	Address       string `json:"address"`
+	KYCTier       int    `json:"kycTier"`
+	LineConnected bool   `json:"lineConnected"`
+}
+
+// Session holds the tokens of one signed-in user. It is safe for
+// concurrent use.
+type Session struct {
+	baseURL string
+	http    *http.Client
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+// NewSession returns a session for the API at baseURL. httpClient is used
+// for the auth calls; nil means http.DefaultClient.
+func NewSession(baseURL string, httpClient *http.Client) *Session {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Session{baseURL: strings.TrimRight(baseURL, "/"), http: httpClient}
+}
+
+// SignIn requests a nonce for address, signs the returned message with sign
+// and exchanges the signature for tokens
+func (s *Session) SignIn(ctx context.Context, address string, sign SignFunc) (*User, error) {
+	var nonce struct {
+		Message   string `json:"message"`
+		RequestID string `json:"requestId"`
+	}
+	query := url.Values{"address": {address}}
+	if err := s.call(ctx, http.MethodGet, "/api/auth/nonce?"+query.Encode(), nil, &nonce); err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	signature, err := sign(ctx, nonce.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	var res struct {
+		AccessToken  string `json:"accessToken"`
+		RefreshToken string `json:"refreshToken"`
+		User         User   `json:"user"`
+	}
+	req := map[string]string{
+		"address":   address,
+		"signature": signature,
+		"message":   nonce.Message,
+		"requestId": nonce.RequestID,
+	}
+	if err := s.call(ctx, http.MethodPost, "/api/auth/verify", req, &res); err != nil {
+		return nil, fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	s.SetTokens(res.AccessToken, res.RefreshToken)
+	return &res.User, nil
+}
+
+// SetTokens restores a session from stored tokens. refreshToken may be
+// empty, in which case the session ends when the access token expires.
+func (s *Session) SetTokens(accessToken, refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = accessToken
+	s.refreshToken = refreshToken
+	s.expiresAt = expiry(accessToken)
+}
+
+// Tokens returns the current tokens, to be stored and passed back to
+// SetTokens later
+func (s *Session) Tokens() (accessToken, refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessToken, s.refreshToken
+}
+
+// Token returns a valid access token, refreshing it when it is about to
+// expire
+func (s *Session) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.accessToken == "" {
+		return "", ErrNotSignedIn
+	}
+	if s.expiresAt.IsZero() || time.Until(s.expiresAt) > refreshLeeway || s.refreshToken == "" {
+		return s.accessToken, nil
+	}
+	if err := s.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return s.accessToken, nil
+}
+
+// Refresh exchanges the refresh token for a new access token
+func (s *Session) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshLocked(ctx)
+}
+
+func (s *Session) refreshLocked(ctx context.Context) error {
+	if s.refreshToken == "" {
+		return ErrNotSignedIn
+	}
+	var res struct {
+		AccessToken string `json:"accessToken"`
+	}
+	req := map[string]string{"refreshToken": s.refreshToken}
+	if err := s.call(ctx, http.MethodPost, "/api/auth/refresh", req, &res); err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	s.accessToken = res.AccessToken
+	s.expiresAt = expiry(res.AccessToken)
+	return nil
+}
+
+// SignOut ends the session on the server and forgets the tokens
+func (s *Session) SignOut(ctx context.Context) error {
+	token, err := s.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/api/auth/logout", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if err := s.do(req, nil); err != nil {
+		return fmt.Errorf("failed to sign out: %w", err)
+	}
+	s.SetTokens("", "")
+	return nil
+}
+
+// Transport returns a RoundTripper that adds the session's bearer token to
+// each request and, on 401, refreshes the token and retries once. base nil
+// means http.DefaultTransport.
+func (s *Session) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{session: s, base: base}
+}
+
+type transport struct {
+	session *Session
+	base    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.session.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.base.RoundTrip(withToken(req, token))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// The body can only be sent again if the request can replay it
+	if req.Body != nil && req.GetBody == nil {
+		return res, nil
+	}
+	if err := t.session.Refresh(req.Context()); err != nil {
+		return res, nil
+	}
+	retry := withToken(req, t.session.accessTokenNow())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	res.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+func (s *Session) accessTokenNow() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessToken
+}
+
+// withToken clones req, as RoundTrippers must not modify their request
+func withToken(req *http.Request, token string) *http.Request {
+	out := req.Clone(req.Context())
+	out.Header.Set("Authorization", "Bearer "+token)
+	return out
+}
+
+// call sends body as JSON and decodes the response into out
+func (s *Session) call(ctx context.Context, method, path string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return s.do(req, out)
+}
+
+func (s *Session) do(req *http.Request, out interface{}) error {
+	res, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		apiErr := &APIError{Status: res.StatusCode}
+		var body struct {
+			Error  string `json:"error"`
+			Code   string `json:"code"`
+			Reason string `json:"reason"`
+		}
+		if json.NewDecoder(res.Body).Decode(&body) == nil {
+			apiErr.Code, apiErr.Reason, apiErr.Message = body.Code, body.Reason, body.Error
+		}
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// expiry reads the exp claim of a JWT without verifying it; the server
+// does that. It returns the zero time if there is none.
+func expiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,3 @@
+module github.com/Reserve-to-save-backend/sdk/go
+
+go 1.23.0
@@ -0,0 +1,180 @@
+import { Configuration, ConfigurationParameters } from './generated';
+
+// Refresh the access token this long before it expires
+const REFRESH_LEEWAY_MS = 30_000;
+
+/** Signs a message with the wallet (EIP-191 personal_sign), returning the 0x signature */
+export type SignMessage = (message: string) => Promise<string>;
+
+export interface SessionTokens {
+  accessToken: string;
+  refreshToken?: string;
+}
+
+/** The signed-in user as returned by /api/auth/verify and /api/auth/line */
+export interface SessionUser {
+  id: string;
+  [key: string]: unknown;
+}
+
+/** An error response from the auth endpoints */
+export class AuthError extends Error {
+  constructor(
+    readonly status: number,
+    readonly code: string,
+    message: string,
+    /** Catalogued failure such as R2S-1001, if any */
+    readonly reason: string = '',
+  ) {
+    super(message);
+    this.name = 'AuthError';
+  }
+}
+
+/**
+ * Session signs in to the R2S API and keeps the access token fresh. Pass
+ * session.configuration() to the generated API classes:
+ *
+ *   const session = new Session('https://api.example.com');
+ *   await session.signInWithLine(liff.getIDToken(), liff.getAccessToken());
+ *   const campaigns = new CampaignsApi(session.configuration());
+ */
+export class Session {
+  private tokens?: SessionTokens;
+  private expiresAt = 0;
+  private refreshing?: Promise<void>;
+
+  constructor(
+    private readonly basePath: string,
+    private readonly fetchApi: typeof fetch = (input, init) => fetch(input, init),
+    /** Called whenever the tokens change, e.g. to persist them */
+    private readonly onTokens?: (tokens: SessionTokens | undefined) => void,
+  ) {
+    this.basePath = basePath.replace(/\/+$/, '');
+  }
+
+  /** Signs in with a wallet: requests a nonce, signs it and verifies the signature */
+  async signIn(address: string, sign: SignMessage, chainId?: string): Promise<SessionUser> {
+    const query = new URLSearchParams({ address });
+    if (chainId) {
+      query.set('chainId', chainId);
+    }
+    const nonce = await this.call<{ message: string; requestId: string }>('GET', `/api/auth/nonce?${query}`);
+    const signature = await sign(nonce.message);
+
+    const res = await this.call<{ accessToken: string; refreshToken: string; user: SessionUser }>(
+      'POST',
+      '/api/auth/verify',
+      { address, signature, message: nonce.message, requestId: nonce.requestId },
+    );
+    this.setTokens({ accessToken: res.accessToken, refreshToken: res.refreshToken });
+    return res.user;
+  }
+
+  /** Signs in with the LIFF ID and access tokens. LINE sessions have no refresh token. */
+  async signInWithLine(idToken: string, accessToken: string): Promise<SessionUser> {
+    const res = await this.call<{ token: string; user: SessionUser }>('POST', '/api/auth/line', {
+      idToken,
+      accessToken,
+    });
+    this.setTokens({ accessToken: res.token });
+    return res.user;
+  }
+
+  /** Restores stored tokens, or clears them when given undefined */
+  setTokens(tokens: SessionTokens | undefined): void {
+    this.tokens = tokens;
+    this.expiresAt = tokens ? expiry(tokens.accessToken) : 0;
+    this.onTokens?.(tokens);
+  }
+
+  get signedIn(): boolean {
+    return this.tokens !== undefined;
+  }
+
+  /** Returns a valid access token, refreshing it when it is about to expire */
+  accessToken = async (): Promise<string> => {
+    if (!this.tokens) {
+      throw new AuthError(401, 'UNAUTHORIZED', 'Not signed in');
+    }
+    if (this.tokens.refreshToken && this.expiresAt && this.expiresAt - Date.now() < REFRESH_LEEWAY_MS) {
+      await this.refresh();
+    }
+    return this.tokens!.accessToken;
+  };
+
+  /** Exchanges the refresh token for a new access token; concurrent calls share one request */
+  refresh(): Promise<void> {
+    if (!this.refreshing) {
+      this.refreshing = this.doRefresh().finally(() => {
+        this.refreshing = undefined;
+      });
+    }
+    return this.refreshing;
+  }
+
+  private async doRefresh(): Promise<void> {
+    const refreshToken = this.tokens?.refreshToken;
+    if (!refreshToken) {
+      throw new AuthError(401, 'UNAUTHORIZED', 'No refresh token');
+    }
+    const res = await this.call<{ accessToken: string }>('POST', '/api/auth/refresh', { refreshToken });
+    this.setTokens({ accessToken: res.accessToken, refreshToken });
+  }
+
+  /** Ends the session on the server and forgets the tokens */
+  async signOut(): Promise<void> {
+    if (!this.tokens) {
+      return;
+    }
+    const token = await this.accessToken();
+    await this.call('POST', '/api/auth/logout', undefined, token);
+    this.setTokens(undefined);
+  }
+
+  /** A configuration for the generated API classes that authenticates with this session */
+  configuration(params: ConfigurationParameters = {}): Configuration {
+    return new Configuration({
+      basePath: this.basePath,
+      fetchApi: this.fetchApi,
+      ...params,
+      accessToken: this.accessToken,
+    });
+  }
+
+  private async call<T>(method: string, path: string, body?: unknown, token?: string): Promise<T> {
+    const headers: Record<string, string> = {};
+    if (body !== undefined) {
+      headers['Content-Type'] = 'application/json';
+    }
+    if (token) {
+      headers['Authorization'] = `Bearer ${token}`;
+    }
+    const res = await this.fetchApi(this.basePath + path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const data = await res.json().catch(() => ({}));
+    if (!res.ok) {
+      throw new AuthError(res.status, data.code ?? '', data.error ?? res.statusText, data.reason ?? '');
+    }
+    return data as T;
+  }
+}
+
+// expiry reads the exp claim of a JWT without verifying it (the server does
+// that), in milliseconds; 0 if there is none
+function expiry(token: string): number {
+  const payload = token.split('.')[1];
+  if (!payload) {
+    return 0;
+  }
+  try {
+    const json = atob(payload.replace(/-/g, '+').replace(/_/g, '/'));
+    const exp = JSON.parse(json).exp;
+    return typeof exp === 'number' ? exp * 1000 : 0;
+  } catch {
+    return 0;
+  }
+}
@@ -0,0 +1,3 @@
+// Generated client (make sdk) plus the hand-written auth helpers
+export * from './generated';
+export * from './auth';
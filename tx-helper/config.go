@@ -0,0 +1,45 @@
+package main
+
+import (
+	"r2s/pkg/errreport"
+	"r2s/pkg/jwks"
+	"r2s/pkg/logger"
+	"r2s/pkg/server"
+	"r2s/pkg/svcauth"
+	"r2s/pkg/tracing"
+	"r2s/pkg/validate"
+)
+
+// Config is the tx-helper configuration, loaded by config.MustLoad
+type Config struct {
+	Port                   string `env:"TX_HELPER_PORT" default:"3006"`
+	RPCURL                 string `env:"BLOCKCHAIN_RPC_URL" required:"true"`
+	CampaignFactoryAddress string `env:"CAMPAIGN_FACTORY_ADDRESS" required:"true"`
+	USDTAddress            string `env:"USDT_ADDRESS" required:"true"`
+
+	// CoreURL is core-server, asked whether a campaign may be settled and for
+	// the disputes refunded on chain
+	CoreURL string `env:"TX_HELPER_CORE_URL" default:"http://localhost:3003"`
+
+	// DebugAddr serves pkg/diag (empty disables); keep it off the public network
+	DebugAddr string `env:"TX_HELPER_DEBUG_ADDR"`
+
+	Log     logger.Config
+	Tracing tracing.Config
+	Errors  errreport.Config
+	Server  server.Config
+
+	// JWKS verifies the internal tokens of calling services
+	JWKS jwks.Config
+	// Internal requires callers to be services allowed to call tx-helper,
+	// and holds tx-helper's own credentials for calling core-server
+	Internal svcauth.Config
+}
+
+// Validate checks the contract addresses are well-formed
+func (c *Config) Validate() error {
+	return validate.First(
+		validate.Address("CAMPAIGN_FACTORY_ADDRESS", c.CampaignFactoryAddress),
+		validate.Address("USDT_ADDRESS", c.USDTAddress),
+	)
+}
@@ -5,6 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"r2s/pkg/errors/ginerrors"
+	"r2s/pkg/validate"
 	"r2s/tx-helper/services"
 )
 
@@ -27,26 +30,32 @@ func (h *TransactionHandler) BuildJoinCampaignTx(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
 		return
 	}
 
-	amount := new(big.Int)
-	amount.SetString(req.Amount, 10)
+	if err := validate.First(
+		validate.Address("userAddress", req.UserAddress),
+		validate.Address("campaignAddress", req.CampaignAddress),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	amount, err := validate.ParseAmount("amount", req.Amount)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
 
 	txMessage, err := h.txService.BuildJoinCampaignTx(
+		c.Request.Context(),
 		req.UserAddress,
 		req.CampaignAddress,
 		amount,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -67,10 +76,15 @@ func (h *TransactionHandler) BuildCancelParticipationTx(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.First(
+		validate.Address("userAddress", req.UserAddress),
+		validate.Address("campaignAddress", req.CampaignAddress),
+	); err != nil {
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -93,26 +107,32 @@ func (h *TransactionHandler) BuildRequestCancelTx(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
 		return
 	}
 
-	amount := new(big.Int)
-	amount.SetString(req.Amount, 10)
+	if err := validate.First(
+		validate.Address("userAddress", req.UserAddress),
+		validate.Address("campaignAddress", req.CampaignAddress),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	amount, err := validate.ParseAmount("amount", req.Amount)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
 
 	txMessage, err := h.txService.BuildRequestCancelTx(
+		c.Request.Context(),
 		req.UserAddress,
 		req.CampaignAddress,
 		amount,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -134,26 +154,32 @@ func (h *TransactionHandler) BuildApproveUSDTTx(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request",
-		})
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.First(
+		validate.Address("userAddress", req.UserAddress),
+		validate.Address("spenderAddress", req.SpenderAddress),
+	); err != nil {
+		ginerrors.Respond(c, err)
 		return
 	}
 
-	amount := new(big.Int)
-	amount.SetString(req.Amount, 10)
+	amount, err := validate.ParseAmount("amount", req.Amount)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
 
 	txMessage, err := h.txService.BuildApproveUSDTTx(
+		c.Request.Context(),
 		req.UserAddress,
 		req.SpenderAddress,
 		amount,
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -166,6 +192,77 @@ func (h *TransactionHandler) BuildApproveUSDTTx(c *gin.Context) {
 	})
 }
 
+// BuildDeployCampaignTx handles POST /tx/deploy-campaign. The merchant
+// sends the transaction once ops approved the campaign, then records the
+// deployment with core-server's POST /campaigns/:id/deployment.
+func (h *TransactionHandler) BuildDeployCampaignTx(c *gin.Context) {
+	var req struct {
+		MerchantAddress  string `json:"merchantAddress" binding:"required"`
+		Title            string `json:"title" binding:"required"`
+		Description      string `json:"description"`
+		ImageURL         string `json:"imageUrl"`
+		TargetAmount     string `json:"targetAmount" binding:"required"`
+		MinDeposit       string `json:"minDeposit" binding:"required"`
+		MaxDeposit       string `json:"maxDeposit" binding:"required"`
+		DiscountRate     int    `json:"discountRate"`
+		Duration         int64  `json:"duration" binding:"required,gt=0"`
+		SettlementPeriod int64  `json:"settlementPeriod" binding:"required,gt=0"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.First(
+		validate.Address("merchantAddress", req.MerchantAddress),
+		validate.Bps("discountRate", req.DiscountRate),
+	); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	params := services.DeployCampaignParams{
+		Title:            req.Title,
+		Description:      req.Description,
+		ImageURL:         req.ImageURL,
+		DiscountRate:     big.NewInt(int64(req.DiscountRate)),
+		Duration:         big.NewInt(req.Duration),
+		SettlementPeriod: big.NewInt(req.SettlementPeriod),
+	}
+	var err error
+	if params.TargetAmount, err = validate.ParseAmount("targetAmount", req.TargetAmount); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	if params.MinDeposit, err = validate.ParseAmount("minDeposit", req.MinDeposit); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	if params.MaxDeposit, err = validate.ParseAmount("maxDeposit", req.MaxDeposit); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	txMessage, err := h.txService.BuildDeployCampaignTx(
+		c.Request.Context(),
+		req.MerchantAddress,
+		params,
+	)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"transaction": txMessage,
+			"message":     "Sign and send this transaction to deploy the campaign",
+		},
+	})
+}
+
 // BuildConfirmFulfillmentTx handles POST /tx/confirm-fulfillment
 func (h *TransactionHandler) BuildConfirmFulfillmentTx(c *gin.Context) {
 	// Simplified for demo
@@ -177,25 +274,97 @@ func (h *TransactionHandler) BuildConfirmFulfillmentTx(c *gin.Context) {
 	})
 }
 
-// BuildSettleCampaignTx handles POST /tx/settle-campaign
+// BuildSettleCampaignTx handles POST /tx/settle-campaign. Ops send the
+// transaction once every participation of the campaign is fulfilled and
+// accepted, then settle it with core-server's POST /campaigns/:id/settle.
 func (h *TransactionHandler) BuildSettleCampaignTx(c *gin.Context) {
-	// Simplified for demo
+	var req struct {
+		OperatorAddress string `json:"operatorAddress" binding:"required"`
+		CampaignID      string `json:"campaignId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.Address("operatorAddress", req.OperatorAddress); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	campaignID, err := uuid.Parse(req.CampaignID)
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid campaign ID")
+		return
+	}
+
+	txMessage, err := h.txService.BuildSettleCampaignTx(
+		c.Request.Context(),
+		req.OperatorAddress,
+		campaignID,
+	)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"transaction": txMessage,
+			"message":     "Sign and send this transaction to settle the campaign",
+		},
+	})
+}
+
+// BuildRefundTx handles POST /tx/refund. Ops send the transaction for a
+// dispute resolved with an on-chain refund, then record it with
+// core-server's POST /admin/disputes/:id/refund.
+func (h *TransactionHandler) BuildRefundTx(c *gin.Context) {
+	var req struct {
+		OperatorAddress string `json:"operatorAddress" binding:"required"`
+		DisputeID       string `json:"disputeId" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		ginerrors.BadRequest(c, "Invalid request")
+		return
+	}
+
+	if err := validate.Address("operatorAddress", req.OperatorAddress); err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+	disputeID, err := uuid.Parse(req.DisputeID)
+	if err != nil {
+		ginerrors.BadRequest(c, "Invalid dispute ID")
+		return
+	}
+
+	txMessage, err := h.txService.BuildRefundTx(
+		c.Request.Context(),
+		req.OperatorAddress,
+		disputeID,
+	)
+	if err != nil {
+		ginerrors.Respond(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"message": "Admin only function - not implemented for demo",
+			"transaction": txMessage,
+			"message":     "Sign and send this transaction to refund the dispute",
 		},
 	})
 }
 
 // EstimateGas handles GET /tx/estimate-gas
 func (h *TransactionHandler) EstimateGas(c *gin.Context) {
-	gasPrice, err := h.txService.EstimateGasPrice()
+	gasPrice, err := h.txService.EstimateGasPrice(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -212,19 +381,17 @@ func (h *TransactionHandler) EstimateGas(c *gin.Context) {
 func (h *TransactionHandler) GetCampaignInfo(c *gin.Context) {
 	campaignAddress := c.Query("address")
 	if campaignAddress == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Campaign address is required",
-		})
+		ginerrors.BadRequest(c, "Campaign address is required")
+		return
+	}
+	if err := validate.Address("address", campaignAddress); err != nil {
+		ginerrors.Respond(c, err)
 		return
 	}
 
-	info, err := h.txService.GetCampaignInfo(campaignAddress)
+	info, err := h.txService.GetCampaignInfo(c.Request.Context(), campaignAddress)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		ginerrors.Respond(c, err)
 		return
 	}
 
@@ -1,34 +1,81 @@
 package main
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"r2s/pkg/config"
+	"r2s/pkg/diag"
+	"r2s/pkg/errreport"
+	"r2s/pkg/errreport/ginreport"
+	"r2s/pkg/health"
+	"r2s/pkg/jwks"
+	"r2s/pkg/logger"
+	"r2s/pkg/logger/ginlog"
+	"r2s/pkg/metrics/ginmetrics"
+	"r2s/pkg/models"
+	"r2s/pkg/rbac/ginrbac"
+	"r2s/pkg/server"
+	"r2s/pkg/svcauth"
+	"r2s/pkg/svcauth/ginsvcauth"
+	"r2s/pkg/tracing"
+	"r2s/pkg/tracing/gintrace"
 	"r2s/tx-helper/handlers"
 	"r2s/tx-helper/services"
 )
 
 func main() {
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
+	envErr := godotenv.Load()
+
+	// Load and validate configuration (env, CONFIG_FILE, defaults)
+	var cfg Config
+	config.MustLoad(&cfg)
+
+	// Structured logging (LOG_LEVEL, LOG_FORMAT)
+	logger.Init("tx-helper", cfg.Log)
+	if envErr != nil {
+		slog.Info("No .env file found")
+	}
+
+	// Error reporting (Sentry when SENTRY_DSN is set)
+	if err := errreport.Init("tx-helper", cfg.Errors); err != nil {
+		logger.Fatal("Failed to initialize error reporting", "error", err)
+	}
+	defer errreport.Flush()
+
+	// Tracing (spans are exported when OTEL_EXPORTER_OTLP_ENDPOINT is set)
+	shutdownTracing, err := tracing.Init(context.Background(), "tx-helper", cfg.Tracing)
+	if err != nil {
+		logger.Fatal("Failed to initialize tracing", "error", err)
 	}
+	defer shutdownTracing(context.Background())
 
 	// Initialize services
+	coreClient := services.NewCoreClient(cfg.CoreURL, svcauth.NewClient(cfg.Internal, nil))
 	txService := services.NewTransactionService(
-		os.Getenv("BLOCKCHAIN_RPC_URL"),
-		os.Getenv("CAMPAIGN_FACTORY_ADDRESS"),
-		os.Getenv("USDT_ADDRESS"),
+		cfg.RPCURL,
+		cfg.CampaignFactoryAddress,
+		cfg.USDTAddress,
+		coreClient,
 	)
+	defer txService.Close()
 
 	// Initialize handlers
 	txHandler := handlers.NewTransactionHandler(txService)
 
+	// Calling services are verified against auth-server's published keys
+	callerVerifier := svcauth.NewVerifier(jwks.NewRemote(cfg.JWKS.URL, nil), "tx-helper", nil)
+	if !cfg.Internal.Required {
+		slog.Warn("INTERNAL_AUTH_REQUIRED is off, serving callers without an internal token")
+	}
+
 	// Setup router
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gintrace.Middleware(), ginmetrics.Middleware(), ginlog.Middleware(), ginreport.Middleware(), ginsvcauth.Middleware(callerVerifier, cfg.Internal.Required), ginrbac.Middleware())
 
 	// Health check
 	router.GET("/health", func(c *gin.Context) {
@@ -38,6 +85,18 @@ func main() {
 		})
 	})
 
+	// Liveness (process up) and readiness (RPC node answering)
+	checker := health.NewChecker("tx-helper")
+	checker.Add("rpc", txService.Ping)
+	router.GET("/live", gin.WrapH(checker.LiveHandler()))
+	router.GET("/ready", gin.WrapH(checker.ReadyHandler()))
+
+	// Runtime log level (GET/PUT {"level":"debug"})
+	ginlog.RegisterLevelEndpoint(router, "/admin/log-level")
+
+	// Prometheus metrics (HTTP, Go runtime)
+	ginmetrics.Register(router)
+
 	// Transaction routes
 	txGroup := router.Group("/tx")
 	{
@@ -47,8 +106,10 @@ func main() {
 		txGroup.POST("/request-cancel", txHandler.BuildRequestCancelTx)
 		
 		// Merchant transactions
-		txGroup.POST("/confirm-fulfillment", txHandler.BuildConfirmFulfillmentTx)
-		txGroup.POST("/settle-campaign", txHandler.BuildSettleCampaignTx)
+		txGroup.POST("/deploy-campaign", ginrbac.Require(models.RoleMerchant), txHandler.BuildDeployCampaignTx)
+		txGroup.POST("/confirm-fulfillment", ginrbac.Require(models.RoleMerchant), txHandler.BuildConfirmFulfillmentTx)
+		txGroup.POST("/settle-campaign", ginrbac.Require(models.RoleOps), txHandler.BuildSettleCampaignTx)
+		txGroup.POST("/refund", ginrbac.Require(models.RoleOps), txHandler.BuildRefundTx)
 		
 		// Utility
 		txGroup.POST("/approve-usdt", txHandler.BuildApproveUSDTTx)
@@ -56,14 +117,18 @@ func main() {
 		txGroup.GET("/campaign-info", txHandler.GetCampaignInfo)
 	}
 
-	// Start server
-	port := os.Getenv("TX_HELPER_PORT")
-	if port == "" {
-		port = "3006"
+	// Diagnostics (pprof, goroutine dumps, GC stats) on an internal listener
+	if err := diag.Start(cfg.DebugAddr); err != nil {
+		logger.Fatal("Failed to start diagnostics server", "error", err)
 	}
 
-	log.Printf("TX Helper starting on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	// Start server; SIGTERM drains in-flight requests before the RPC
+	// connection is closed
+	ctx, stop := server.Context()
+	defer stop()
+	slog.Info("TX Helper starting", "port", cfg.Port)
+	if err := server.ServeHTTP(ctx, ":"+cfg.Port, router, cfg.Server); err != nil {
+		logger.Fatal("Server failed", "error", err)
 	}
+	slog.Info("TX Helper stopped")
 }
\ No newline at end of file
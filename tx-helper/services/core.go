@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/svcauth"
+)
+
+// coreTimeout bounds one call to core-server
+const coreTimeout = 10 * time.Second
+
+// CoreClient reads campaign state tx-helper checks before building
+// transactions from core-server, authenticated with tx-helper's internal
+// token
+type CoreClient struct {
+	baseURL  string
+	http     *http.Client
+	internal *svcauth.Client
+}
+
+// NewCoreClient takes a nil internal client when no credentials are
+// configured; calls then go out without a token
+func NewCoreClient(baseURL string, internal *svcauth.Client) *CoreClient {
+	return &CoreClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		http:     &http.Client{Timeout: coreTimeout},
+		internal: internal,
+	}
+}
+
+// FulfillmentSummary returns the fulfillment state of a campaign
+func (c *CoreClient) FulfillmentSummary(ctx context.Context, campaignID uuid.UUID) (*models.FulfillmentSummary, error) {
+	var summary models.FulfillmentSummary
+	if err := c.get(ctx, "/campaigns/"+campaignID.String()+"/fulfillment", &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Dispute returns a dispute with the participant's wallet and the campaign
+// contract its refund is sent from
+func (c *CoreClient) Dispute(ctx context.Context, id uuid.UUID) (*models.Dispute, error) {
+	var dispute models.Dispute
+	if err := c.get(ctx, "/admin/disputes/"+id.String(), &dispute); err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// get decodes the data of core-server's answer to GET path into dest
+func (c *CoreClient) get(ctx context.Context, path string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create core-server request: %w", err)
+	}
+	if c.internal != nil {
+		if err := c.internal.SetHeader(ctx, req); err != nil {
+			return apperrors.Unavailable(err, "Failed to authenticate to core service")
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apperrors.Unavailable(err, "Core service unavailable")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return apperrors.Unavailable(err, "Core service unavailable")
+	}
+	if resp.StatusCode != http.StatusOK {
+		// Pass on client errors such as an unknown campaign as answered
+		if appErr := apperrors.FromResponse(body); appErr != nil && resp.StatusCode < http.StatusInternalServerError {
+			return appErr
+		}
+		return apperrors.Unavailable(fmt.Errorf("core-server returned %d", resp.StatusCode), "Core service unavailable")
+	}
+
+	payload := struct {
+		Data interface{} `json:"data"`
+	}{Data: dest}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("failed to decode core-server response to %s: %w", path, err)
+	}
+	return nil
+}
@@ -15,8 +15,12 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
 	
 	"r2s/pkg/contracts"
+	apperrors "r2s/pkg/errors"
+	"r2s/pkg/models"
+	"r2s/pkg/tracing/ethtrace"
 )
 
 type TransactionService struct {
@@ -24,6 +28,7 @@ type TransactionService struct {
 	factoryAddress common.Address
 	usdtAddress    common.Address
 	chainID        *big.Int
+	core           *CoreClient
 }
 
 type TransactionMessage struct {
@@ -37,8 +42,10 @@ type TransactionMessage struct {
 	ChainID  string          `json:"chainId"`
 }
 
-func NewTransactionService(rpcURL, factoryAddress, usdtAddress string) *TransactionService {
-	client, err := ethclient.Dial(rpcURL)
+func NewTransactionService(rpcURL, factoryAddress, usdtAddress string, core *CoreClient) *TransactionService {
+	// Traced client: JSON-RPC calls made with a request context show up in
+	// the request's trace
+	client, err := ethtrace.Dial(context.Background(), rpcURL)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to connect to blockchain: %v", err))
 	}
@@ -53,11 +60,13 @@ func NewTransactionService(rpcURL, factoryAddress, usdtAddress string) *Transact
 		factoryAddress: common.HexToAddress(factoryAddress),
 		usdtAddress:    common.HexToAddress(usdtAddress),
 		chainID:        chainID,
+		core:           core,
 	}
 }
 
 // BuildJoinCampaignTx creates a transaction message for joining a campaign
 func (s *TransactionService) BuildJoinCampaignTx(
+	ctx context.Context,
 	userAddress string,
 	campaignAddress string,
 	amount *big.Int,
@@ -90,21 +99,21 @@ func (s *TransactionService) BuildJoinCampaignTx(
 	}
 
 	// Estimate gas
-	gasLimit, err := s.estimateGas(userAddress, campaignAddress, data)
+	gasLimit, err := s.estimateGas(ctx, userAddress, campaignAddress, data)
 	if err != nil {
 		gasLimit = uint64(300000) // Default gas limit
 	}
 
 	// Get gas price
-	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
 	}
 
 	// Get nonce
-	nonce, err := s.client.PendingNonceAt(context.Background(), common.HexToAddress(userAddress))
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(userAddress))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
 	}
 
 	return &TransactionMessage{
@@ -121,6 +130,7 @@ func (s *TransactionService) BuildJoinCampaignTx(
 
 // BuildApproveUSDTTx creates a transaction message for approving USDT
 func (s *TransactionService) BuildApproveUSDTTx(
+	ctx context.Context,
 	userAddress string,
 	spenderAddress string,
 	amount *big.Int,
@@ -143,21 +153,21 @@ func (s *TransactionService) BuildApproveUSDTTx(
 	}
 
 	// Estimate gas
-	gasLimit, err := s.estimateGas(userAddress, s.usdtAddress.Hex(), data)
+	gasLimit, err := s.estimateGas(ctx, userAddress, s.usdtAddress.Hex(), data)
 	if err != nil {
 		gasLimit = uint64(100000) // Default gas limit for approve
 	}
 
 	// Get gas price
-	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
 	}
 
 	// Get nonce
-	nonce, err := s.client.PendingNonceAt(context.Background(), common.HexToAddress(userAddress))
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(userAddress))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
 	}
 
 	return &TransactionMessage{
@@ -174,6 +184,7 @@ func (s *TransactionService) BuildApproveUSDTTx(
 
 // BuildRequestCancelTx creates a transaction message for requesting cancellation
 func (s *TransactionService) BuildRequestCancelTx(
+	ctx context.Context,
 	userAddress string,
 	campaignAddress string,
 	amount *big.Int,
@@ -191,21 +202,21 @@ func (s *TransactionService) BuildRequestCancelTx(
 	}
 
 	// Estimate gas
-	gasLimit, err := s.estimateGas(userAddress, campaignAddress, data)
+	gasLimit, err := s.estimateGas(ctx, userAddress, campaignAddress, data)
 	if err != nil {
 		gasLimit = uint64(200000)
 	}
 
 	// Get gas price
-	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
 	}
 
 	// Get nonce
-	nonce, err := s.client.PendingNonceAt(context.Background(), common.HexToAddress(userAddress))
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(userAddress))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
 	}
 
 	return &TransactionMessage{
@@ -220,38 +231,245 @@ func (s *TransactionService) BuildRequestCancelTx(
 	}, nil
 }
 
+// DeployCampaignParams are the createCampaign arguments of an approved
+// campaign. Amounts are in USDT base units, durations in seconds.
+type DeployCampaignParams struct {
+	Title            string
+	Description      string
+	ImageURL         string
+	TargetAmount     *big.Int
+	MinDeposit       *big.Int
+	MaxDeposit       *big.Int
+	DiscountRate     *big.Int
+	Duration         *big.Int
+	SettlementPeriod *big.Int
+}
+
+// BuildDeployCampaignTx creates a transaction message for deploying an
+// approved campaign through the campaign factory. The campaign opens once
+// core-server records the deployment, which it only does for approved
+// campaigns.
+func (s *TransactionService) BuildDeployCampaignTx(
+	ctx context.Context,
+	merchantAddress string,
+	params DeployCampaignParams,
+) (*TransactionMessage, error) {
+	// Get ABI
+	campaignABI, err := abi.JSON(strings.NewReader(contracts.R2scampaignABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	// Pack the createCampaign function call
+	data, err := campaignABI.Pack(
+		"createCampaign",
+		params.Title,
+		params.Description,
+		params.ImageURL,
+		s.usdtAddress,
+		params.TargetAmount,
+		params.MinDeposit,
+		params.MaxDeposit,
+		params.DiscountRate,
+		params.Duration,
+		params.SettlementPeriod,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack createCampaign call: %w", err)
+	}
+
+	// Estimate gas
+	gasLimit, err := s.estimateGas(ctx, merchantAddress, s.factoryAddress.Hex(), data)
+	if err != nil {
+		gasLimit = uint64(3000000) // Default gas limit for a deployment
+	}
+
+	// Get gas price
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
+	}
+
+	// Get nonce
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(merchantAddress))
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
+	}
+
+	return &TransactionMessage{
+		To:       s.factoryAddress.Hex(),
+		From:     merchantAddress,
+		Data:     fmt.Sprintf("0x%x", data),
+		Value:    "0",
+		GasLimit: gasLimit,
+		GasPrice: gasPrice.String(),
+		Nonce:    nonce,
+		ChainID:  s.chainID.String(),
+	}, nil
+}
+
+// BuildSettleCampaignTx creates a transaction message for settling a
+// campaign. It is only built once core-server reports every participation
+// fulfilled and accepted with none disputed; core-server checks the same
+// again when the settlement is recorded.
+func (s *TransactionService) BuildSettleCampaignTx(
+	ctx context.Context,
+	operatorAddress string,
+	campaignID uuid.UUID,
+) (*TransactionMessage, error) {
+	summary, err := s.core.FulfillmentSummary(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if summary.ChainAddress == "" {
+		return nil, apperrors.Catalog(apperrors.ReasonCampaignNotSettled)
+	}
+	if !summary.Settleable {
+		if summary.Unfulfilled > 0 || summary.AwaitingResponse > 0 || summary.Disputed > 0 {
+			return nil, apperrors.Catalog(apperrors.ReasonFulfillmentPending)
+		}
+		return nil, apperrors.Catalog(apperrors.ReasonCampaignNotSettled)
+	}
+	campaignAddress := summary.ChainAddress
+
+	// Get ABI
+	campaignABI, err := abi.JSON(strings.NewReader(contracts.R2scampaignABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	// Pack the settle function call
+	data, err := campaignABI.Pack("settle")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack settle call: %w", err)
+	}
+
+	// Estimate gas
+	gasLimit, err := s.estimateGas(ctx, operatorAddress, campaignAddress, data)
+	if err != nil {
+		gasLimit = uint64(500000) // Default gas limit for a settlement
+	}
+
+	// Get gas price
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
+	}
+
+	// Get nonce
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(operatorAddress))
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
+	}
+
+	return &TransactionMessage{
+		To:       campaignAddress,
+		From:     operatorAddress,
+		Data:     fmt.Sprintf("0x%x", data),
+		Value:    "0",
+		GasLimit: gasLimit,
+		GasPrice: gasPrice.String(),
+		Nonce:    nonce,
+		ChainID:  s.chainID.String(),
+	}, nil
+}
+
+// BuildRefundTx creates a transaction message refunding a dispute from the
+// campaign contract to the participant's wallet. It is only built for
+// disputes an admin resolved with an on-chain refund; the mined transaction
+// is then recorded with core-server's POST /admin/disputes/:id/refund.
+func (s *TransactionService) BuildRefundTx(
+	ctx context.Context,
+	operatorAddress string,
+	disputeID uuid.UUID,
+) (*TransactionMessage, error) {
+	dispute, err := s.core.Dispute(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != models.DisputeRefunding || dispute.RefundMethod == nil ||
+		*dispute.RefundMethod != models.RefundViaChain || dispute.RefundAmount.Int == nil {
+		return nil, apperrors.Catalog(apperrors.ReasonRefundNotPending)
+	}
+	if dispute.ChainAddress == "" {
+		return nil, apperrors.Catalog(apperrors.ReasonRefundNotPending)
+	}
+	campaignAddress := dispute.ChainAddress
+
+	// Get ABI
+	campaignABI, err := abi.JSON(strings.NewReader(contracts.R2scampaignABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	// Pack the refund function call
+	data, err := campaignABI.Pack("refund", common.HexToAddress(dispute.WalletAddress), dispute.RefundAmount.Int)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack refund call: %w", err)
+	}
+
+	// Estimate gas
+	gasLimit, err := s.estimateGas(ctx, operatorAddress, campaignAddress, data)
+	if err != nil {
+		gasLimit = uint64(200000)
+	}
+
+	// Get gas price
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
+	}
+
+	// Get nonce
+	nonce, err := s.client.PendingNonceAt(ctx, common.HexToAddress(operatorAddress))
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get nonce: %w", err))
+	}
+
+	return &TransactionMessage{
+		To:       campaignAddress,
+		From:     operatorAddress,
+		Data:     fmt.Sprintf("0x%x", data),
+		Value:    "0",
+		GasLimit: gasLimit,
+		GasPrice: gasPrice.String(),
+		Nonce:    nonce,
+		ChainID:  s.chainID.String(),
+	}, nil
+}
+
 // GetCampaignInfo retrieves campaign information from blockchain
-func (s *TransactionService) GetCampaignInfo(campaignAddress string) (map[string]interface{}, error) {
+func (s *TransactionService) GetCampaignInfo(ctx context.Context, campaignAddress string) (map[string]interface{}, error) {
 	campaign, err := contracts.NewR2scampaign(common.HexToAddress(campaignAddress), s.client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to instantiate campaign contract: %w", err)
 	}
 
 	// Call view functions
-	opts := &bind.CallOpts{Context: context.Background()}
+	opts := &bind.CallOpts{Context: ctx}
 	
 	// Get campaign parameters
 	params, err := campaign.Params(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get campaign params: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get campaign params: %w", err))
 	}
 
 	// Get current state
 	state, err := campaign.GetState(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get campaign state: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get campaign state: %w", err))
 	}
 
 	// Get current amount
 	currentAmount, err := campaign.CurrentAmount(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current amount: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get current amount: %w", err))
 	}
 
 	// Get participant count
 	participantCount, err := campaign.GetParticipantCount(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get participant count: %w", err)
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get participant count: %w", err))
 	}
 
 	return map[string]interface{}{
@@ -273,12 +491,29 @@ func (s *TransactionService) GetCampaignInfo(campaignAddress string) (map[string
 }
 
 // EstimateGasPrice returns current gas price
-func (s *TransactionService) EstimateGasPrice() (*big.Int, error) {
-	return s.client.SuggestGasPrice(context.Background())
+func (s *TransactionService) EstimateGasPrice(ctx context.Context) (*big.Int, error) {
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, apperrors.ChainUnavailable(fmt.Errorf("failed to get gas price: %w", err))
+	}
+	return gasPrice, nil
+}
+
+// Close closes the RPC connection
+func (s *TransactionService) Close() {
+	s.client.Close()
+}
+
+// Ping checks the RPC node answers by fetching the latest block number
+func (s *TransactionService) Ping(ctx context.Context) error {
+	if _, err := s.client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("failed to get block number: %w", err)
+	}
+	return nil
 }
 
 // estimateGas estimates gas for a transaction
-func (s *TransactionService) estimateGas(from, to string, data []byte) (uint64, error) {
+func (s *TransactionService) estimateGas(ctx context.Context, from, to string, data []byte) (uint64, error) {
 	msg := ethereum.CallMsg{
 		From: common.HexToAddress(from),
 		To:   &common.Address{},
@@ -288,7 +523,7 @@ func (s *TransactionService) estimateGas(from, to string, data []byte) (uint64,
 	toAddr := common.HexToAddress(to)
 	msg.To = &toAddr
 	
-	gasLimit, err := s.client.EstimateGas(context.Background(), msg)
+	gasLimit, err := s.client.EstimateGas(ctx, msg)
 	if err != nil {
 		return 0, err
 	}